@@ -3,8 +3,12 @@ package main
 
 import (
 	"context"
+	"errors"
 	"flag"
 	"os"
+	"os/signal"
+	"strings"
+	"syscall"
 	"time"
 
 	"github.com/google/uuid"
@@ -12,7 +16,9 @@ import (
 	"github.com/yourusername/clever-better/internal/backtest"
 	"github.com/yourusername/clever-better/internal/config"
 	"github.com/yourusername/clever-better/internal/database"
+	applogger "github.com/yourusername/clever-better/internal/logger"
 	"github.com/yourusername/clever-better/internal/models"
+	"github.com/yourusername/clever-better/internal/repository"
 	"github.com/yourusername/clever-better/internal/strategy"
 )
 
@@ -29,23 +35,40 @@ func main() {
 		strategyName = flag.String("strategy", "simple_value", "Strategy name to test")
 		startDate = flag.String("start-date", "", "Override start date (YYYY-MM-DD)")
 		endDate = flag.String("end-date", "", "Override end date (YYYY-MM-DD)")
-		mode = flag.String("mode", "all", "Backtest mode: historical, monte-carlo, walk-forward, all")
+		mode = flag.String("mode", "all", "Backtest mode: historical, monte-carlo, walk-forward, all, or rescore to recompute stored composite scores")
+		windowMode = flag.String("walk-forward-window", "rolling", "Walk-forward training window: rolling or anchored")
 		output = flag.String("output", "./output/backtest_results.json", "Output path for results")
 		mlExport = flag.Bool("ml-export", false, "Enable ML export")
+		dryRun = flag.Bool("dry-run", false, "With --mode rescore, report score changes without saving them")
+		tags tagList
 	)
+	flag.Var(&tags, "tag", "Tag to stamp on the persisted result; repeat for several")
 	flag.Parse()
 
 	logger := newLogger()
-	ctx := context.Background()
+	// Ctrl-C stops the run between races and reports the partial results
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
 	cfg := loadConfigWithSecrets(*configPath, logger)
+	if *mode == "rescore" {
+		runRescore(ctx, cfg, *dryRun, logger)
+		return
+	}
 	btConfig := buildBacktestConfig(cfg, *output, *mlExport, *startDate, *endDate, logger)
+	btConfig.Tags = tags
 	strat := resolveStrategy(*strategyName)
+	// Gate backtests on the same odds bands as live trading
+	if value, ok := strat.(*strategy.SimpleValueStrategy); ok {
+		for _, band := range cfg.Trading.OddsBands {
+			value.OddsBands = append(value.OddsBands, strategy.OddsBand(band))
+		}
+	}
 	engine := buildEngine(ctx, cfg, btConfig, strat, logger)
 	defer engine.Close(ctx)
 
 	logger.WithFields(logrus.Fields{"mode": *mode, "strategy": strat.Name()}).Info("Starting backtest")
-	runMode(ctx, engine, btConfig, strat, *mode)
+	runMode(ctx, engine, btConfig, strat, *mode, backtest.WindowMode(*windowMode))
 }
 
 func resolveStrategy(name string) strategy.Strategy {
@@ -82,6 +105,9 @@ func loadConfigWithSecrets(path string, logger *logrus.Logger) *config.Config {
 	if err := config.Validate(cfg); err != nil {
 		logger.Fatalf("Invalid configuration: %v", err)
 	}
+	if err := applogger.Configure(logger, cfg.App); err != nil {
+		logger.Fatalf("Invalid log configuration: %v", err)
+	}
 	return cfg
 }
 
@@ -90,6 +116,17 @@ func buildBacktestConfig(cfg *config.Config, output string, mlExport bool, start
 	if err != nil {
 		logger.Fatalf("Invalid backtest config: %v", err)
 	}
+	btConfig.MarketFilter = strategy.NewMarketFilter(
+		strategy.MarketRules(cfg.Trading.MarketFilter.Allow),
+		strategy.MarketRules(cfg.Trading.MarketFilter.Deny),
+	)
+	btConfig.IncludeReserveRunners = cfg.Trading.IncludeReserveRunners
+	btConfig.MaxLadderDepth = cfg.Trading.MaxLadderDepth
+	btConfig.OddsSourcePreference = cfg.Trading.OddsSourcePreference
+	btConfig.StakeRounding = strategy.StakeRounding{
+		MinStake:  cfg.Trading.StakeRounding.MinStake,
+		Increment: cfg.Trading.StakeRounding.Increment,
+	}
 	if output != "" {
 		btConfig.OutputPath = output
 	}
@@ -125,16 +162,16 @@ func buildEngine(ctx context.Context, cfg *config.Config, btConfig backtest.Back
 	return engine
 }
 
-func runMode(ctx context.Context, engine *backtest.Engine, cfg backtest.BacktestConfig, strat strategy.Strategy, mode string) {
+func runMode(ctx context.Context, engine *backtest.Engine, cfg backtest.BacktestConfig, strat strategy.Strategy, mode string, windowMode backtest.WindowMode) {
 	switch mode {
 	case "historical":
 		runHistoricalBacktest(ctx, engine)
 	case "monte-carlo":
 		runMonteCarloBacktest(ctx, engine, cfg)
 	case "walk-forward":
-		runWalkForwardBacktest(ctx, engine, strat)
+		runWalkForwardBacktest(ctx, engine, strat, windowMode)
 	case "all":
-		runAllMethods(ctx, engine, cfg, strat)
+		runAllMethods(ctx, engine, cfg, strat, windowMode)
 	default:
 		engineLogger(engine).Fatalf("Unsupported mode: %s", mode)
 	}
@@ -142,13 +179,17 @@ func runMode(ctx context.Context, engine *backtest.Engine, cfg backtest.Backtest
 
 func runHistoricalBacktest(ctx context.Context, engine *backtest.Engine) {
 	state, metrics, err := engine.Run(ctx, engineConfigStart(engine), engineConfigEnd(engine))
-	if err != nil {
+	if errors.Is(err, backtest.ErrCancelled) {
+		engineLogger(engine).WithError(err).Warn("Historical backtest cancelled, reporting partial results")
+	} else if err != nil {
 		engineLogger(engine).Fatalf("Historical backtest failed: %v", err)
 	}
 	aggregated := backtest.AggregateResults(metrics, backtest.MonteCarloResult{}, backtest.WalkForwardResult{}, backtest.AggregationWeights{})
+	if err == nil {
+		aggregated.Benchmark = runBenchmark(ctx, engine, state, metrics)
+	}
 	report := backtest.GenerateConsoleReport(aggregated)
 	engineLogger(engine).Info(report)
-	_ = state
 }
 
 func runMonteCarloBacktest(ctx context.Context, engine *backtest.Engine, cfg backtest.BacktestConfig) {
@@ -171,21 +212,77 @@ func runMonteCarloBacktest(ctx context.Context, engine *backtest.Engine, cfg bac
 	engineLogger(engine).WithField("mean_return", result.MeanReturn).Info("Monte Carlo completed")
 }
 
-func runWalkForwardBacktest(ctx context.Context, engine *backtest.Engine, strat strategy.Strategy) {
-	result, err := backtest.RunWalkForward(ctx, engine, strat, backtest.WalkForwardConfig{
+// runRescore recomputes the composite scores of stored aggregated results
+// under the configured weights, saving them unless dryRun is set
+func runRescore(ctx context.Context, cfg *config.Config, dryRun bool, logger *logrus.Logger) {
+	db, err := database.NewDB(ctx, &cfg.Database)
+	if err != nil {
+		logger.Fatalf("Failed to initialize database: %v", err)
+	}
+	defer db.Close(ctx)
+
+	weights := backtest.WeightsFromConfig(cfg.Backtest.CompositeWeights)
+	report, err := backtest.RescoreCompositeScores(ctx, repository.NewPostgresBacktestResultRepository(db), weights, dryRun)
+	if err != nil {
+		logger.Fatalf("Rescore failed: %v", err)
+	}
+
+	for _, change := range report.Changes {
+		logger.WithFields(logrus.Fields{
+			"result_id":          change.ResultID,
+			"strategy_id":        change.StrategyID,
+			"old_score":          change.OldScore,
+			"new_score":          change.NewScore,
+			"old_recommendation": change.OldRecommendation,
+			"new_recommendation": change.NewRecommendation,
+			"dry_run":            dryRun,
+		}).Info("Composite score rescored")
+	}
+	logger.WithFields(logrus.Fields{
+		"scanned":   report.Scanned,
+		"changed":   len(report.Changes),
+		"unchanged": report.Unchanged,
+		"skipped":   report.Skipped,
+		"weights":   weights,
+		"dry_run":   dryRun,
+	}).Info("Rescore completed")
+}
+
+// runBenchmark compares the run with favourite backing over the same races
+// when the benchmark is enabled, returning nil otherwise
+func runBenchmark(ctx context.Context, engine *backtest.Engine, state *backtest.BacktestState, metrics backtest.Metrics) *backtest.BenchmarkComparison {
+	if !engine.Config().Benchmark {
+		return nil
+	}
+	comparison, err := backtest.RunBenchmarkComparison(ctx, engine, state, metrics, engineConfigStart(engine), engineConfigEnd(engine))
+	if err != nil {
+		engineLogger(engine).WithError(err).Warn("Benchmark comparison failed")
+		return nil
+	}
+	return &comparison
+}
+
+// walkForwardConfig returns the default walk-forward windows
+func walkForwardConfig(windowMode backtest.WindowMode) backtest.WalkForwardConfig {
+	return backtest.WalkForwardConfig{
 		TrainingWindowDays:   90,
 		ValidationWindowDays: 30,
 		TestWindowDays:       30,
 		StepSizeDays:         30,
 		MinTradesPerWindow:   10,
-	})
+		WindowMode:           windowMode,
+	}
+}
+
+func runWalkForwardBacktest(ctx context.Context, engine *backtest.Engine, strat strategy.Strategy, windowMode backtest.WindowMode) {
+	result, err := backtest.RunWalkForward(ctx, engine, strat, walkForwardConfig(windowMode))
 	if err != nil {
 		engineLogger(engine).Fatalf("Walk-forward failed: %v", err)
 	}
 	engineLogger(engine).WithField("consistency", result.ConsistencyScore).Info("Walk-forward completed")
 }
 
-func runAllMethods(ctx context.Context, engine *backtest.Engine, cfg backtest.BacktestConfig, strat strategy.Strategy) {
+func runAllMethods(ctx context.Context, engine *backtest.Engine, cfg backtest.BacktestConfig, strat strategy.Strategy, windowMode backtest.WindowMode) {
 	state, metrics, err := engine.Run(ctx, engineConfigStart(engine), engineConfigEnd(engine))
 	if err != nil {
 		engineLogger(engine).Fatalf("Historical backtest failed: %v", err)
@@ -202,22 +299,13 @@ func runAllMethods(ctx context.Context, engine *backtest.Engine, cfg backtest.Ba
 	if err != nil {
 		engineLogger(engine).Fatalf("Monte Carlo failed: %v", err)
 	}
-	walkForward, err := backtest.RunWalkForward(ctx, engine, strat, backtest.WalkForwardConfig{
-		TrainingWindowDays:   90,
-		ValidationWindowDays: 30,
-		TestWindowDays:       30,
-		StepSizeDays:         30,
-		MinTradesPerWindow:   10,
-	})
+	walkForward, err := backtest.RunWalkForward(ctx, engine, strat, walkForwardConfig(windowMode))
 	if err != nil {
 		engineLogger(engine).Fatalf("Walk-forward failed: %v", err)
 	}
 
-	aggregated := backtest.AggregateResults(metrics, monteCarlo, walkForward, backtest.AggregationWeights{
-		HistoricalReplay: 0.4,
-		MonteCarlo:       0.3,
-		WalkForward:      0.3,
-	})
+	aggregated := backtest.AggregateResults(metrics, monteCarlo, walkForward, cfg.Weights)
+	aggregated.Benchmark = runBenchmark(ctx, engine, state, metrics)
 	report := backtest.GenerateConsoleReport(aggregated)
 	engineLogger(engine).Info(report)
 
@@ -258,6 +346,7 @@ func runAllMethods(ctx context.Context, engine *backtest.Engine, cfg backtest.Ba
 			EndDate:        cfg.EndDate,
 			InitialCapital: cfg.InitialBankroll,
 			FinalCapital:   state.CurrentBankroll,
+			Tags:           cfg.Tags,
 		}
 		if err := backtest.ExportToDatabase(ctx, aggregated, engine.Repositories().BacktestResult, params); err != nil {
 			engineLogger(engine).Fatalf("Failed to persist backtest result: %v", err)
@@ -265,6 +354,18 @@ func runAllMethods(ctx context.Context, engine *backtest.Engine, cfg backtest.Ba
 	}
 }
 
+// tagList collects repeated --tag flags
+type tagList []string
+
+func (t *tagList) String() string {
+	return strings.Join(*t, ",")
+}
+
+func (t *tagList) Set(value string) error {
+	*t = append(*t, value)
+	return nil
+}
+
 func flattenBets(bets []*models.Bet) []models.Bet {
 	result := make([]models.Bet, 0, len(bets))
 	for _, bet := range bets {
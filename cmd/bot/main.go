@@ -89,7 +89,7 @@ func initMetricsServer(appLog *logrus.Logger) {
 }
 
 // initTracing initializes AWS X-Ray tracing if enabled
-func initTracing(appLog *logrus.Logger) {
+func initTracing(cfg config.TracingConfig, appLog *logrus.Logger) {
 	xrayEnabled := os.Getenv("XRAY_ENABLED") == "true"
 	if !xrayEnabled {
 		return
@@ -99,12 +99,17 @@ func initTracing(appLog *logrus.Logger) {
 	if daemonAddr == "" {
 		daemonAddr = "localhost:2000"
 	}
-	tracing.Initialize(tracing.Config{
-		ServiceName: "clever-better-bot",
-		Enabled:     true,
-		SamplingRate: 0.1,
-		DaemonAddr:  daemonAddr,
-	}, appLog)
+	serviceName := "clever-better-bot"
+	if err := tracing.Initialize(tracing.Config{
+		ServiceName:   serviceName,
+		Enabled:       true,
+		SamplingRate:  cfg.SamplingRateFor(serviceName),
+		SlowThreshold: time.Duration(cfg.SlowThresholdMs) * time.Millisecond,
+		DaemonAddr:    daemonAddr,
+	}, appLog); err != nil {
+		appLog.WithError(err).Error("Failed to initialize AWS X-Ray tracing")
+		return
+	}
 	appLog.WithField("daemon_addr", daemonAddr).Info("AWS X-Ray tracing initialized")
 }
 
@@ -122,7 +127,7 @@ func initBetfairServices(cfg *config.Config, betRepo repository.BetRepository, o
 	betfairClient := betfair.NewBetfairClient(&cfg.Betfair, httpClient, orderLogger)
 
 	// Login to Betfair
-	if err := betfairClient.Login(context.Background()); err != nil {
+	if err := betfair.NewAuthService(betfairClient, orderLogger).Login(context.Background()); err != nil {
 		return nil, nil, fmt.Errorf("failed to login to Betfair: %w", err)
 	}
 
@@ -135,7 +140,7 @@ func initBetfairServices(cfg *config.Config, betRepo repository.BetRepository, o
 		betfair.BettingConfig{
 			MaxStake:       cfg.Trading.MaxStakePerBet,
 			MinStake:       0.10,
-			MaxBetsPerDay:  cfg.Trading.MaxConcurrentBets,
+			MaxBetsPerDay:  cfg.Trading.MaxBetsPerDay,
 			CommissionRate: cfg.Backtest.CommissionRate,
 		},
 		orderLogger,
@@ -152,6 +157,63 @@ func initBetfairServices(cfg *config.Config, betRepo repository.BetRepository, o
 	return bettingService, orderManager, nil
 }
 
+// initAccountRouter logs in to each additional Betfair account and builds the
+// router that spreads live bets across them and the primary account. It
+// returns nil when no additional accounts are configured.
+func initAccountRouter(cfg *config.Config, primary *betfair.BettingService, betRepo repository.BetRepository, orderLogger *log.Logger, appLog *logrus.Logger) (*bot.AccountRouter, error) {
+	if primary == nil || len(cfg.Betfair.Accounts) == 0 {
+		return nil, nil
+	}
+
+	httpLogger := log.New(os.Stdout, "betfair-http: ", log.LstdFlags)
+	accounts := []*bot.BettingAccount{{Name: bot.PrimaryAccountName, Service: primary}}
+	for _, accountCfg := range cfg.Betfair.Accounts {
+		betfairCfg := cfg.Betfair
+		betfairCfg.Username = accountCfg.Username
+		betfairCfg.Password = accountCfg.Password
+		if accountCfg.AppKey != "" {
+			betfairCfg.AppKey = accountCfg.AppKey
+		}
+		if accountCfg.CertFile != "" {
+			betfairCfg.CertFile = accountCfg.CertFile
+		}
+		if accountCfg.KeyFile != "" {
+			betfairCfg.KeyFile = accountCfg.KeyFile
+		}
+
+		httpClient := datasource.NewRateLimitedHTTPClient(datasource.DefaultHTTPClientConfig(), httpLogger)
+		client := betfair.NewBetfairClient(&betfairCfg, httpClient, orderLogger)
+		if err := betfair.NewAuthService(client, orderLogger).Login(context.Background()); err != nil {
+			return nil, fmt.Errorf("failed to login to Betfair account %s: %w", accountCfg.Name, err)
+		}
+
+		service := betfair.NewBettingService(
+			client,
+			betRepo,
+			betfair.BettingConfig{
+				MaxStake:       cfg.Trading.MaxStakePerBet,
+				MinStake:       0.10,
+				MaxBetsPerDay:  cfg.Trading.MaxBetsPerDay,
+				CommissionRate: cfg.Backtest.CommissionRate,
+			},
+			orderLogger,
+		)
+		accounts = append(accounts, &bot.BettingAccount{
+			Name:        accountCfg.Name,
+			Service:     service,
+			MaxExposure: accountCfg.MaxExposure,
+		})
+
+		appLog.WithField("account", accountCfg.Name).Info("Additional Betfair account logged in")
+	}
+
+	router, err := bot.NewAccountRouter(cfg.Betfair.AccountRouting, accounts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create account router: %w", err)
+	}
+	return router, nil
+}
+
 // logStartupInfo logs startup information
 func logStartupInfo(appLog *logrus.Logger, cfg *config.Config, orchestrator *bot.Orchestrator) {
 	appLog.WithFields(logrus.Fields{
@@ -190,7 +252,10 @@ func main() {
 	}
 
 	// Set up logging
-	appLog := logger.NewLogger(cfg.App.LogLevel)
+	appLog, err := logger.NewFromConfig(cfg.App)
+	if err != nil {
+		log.Fatalf("Logger configuration error: %v", err)
+	}
 	appLog.WithFields(logrus.Fields{
 		"environment": cfg.App.Environment,
 		"log_level":   cfg.App.LogLevel,
@@ -201,7 +266,7 @@ func main() {
 
 	// Initialize metrics and tracing
 	initMetricsServer(appLog)
-	initTracing(appLog)
+	initTracing(cfg.Tracing, appLog)
 
 	// Initialize database connection
 	db, err := database.NewDB(cfg.GetDatabaseDSN())
@@ -230,6 +295,7 @@ func main() {
 		ServiceName: "bot",
 		Version:     Version,
 		Commit:      GitCommit,
+		BuildDate:   BuildDate,
 		Logger:      appLog,
 		DB:          db,
 	})
@@ -248,12 +314,15 @@ func main() {
 	betRepo := repository.NewPostgresBetRepository(db)
 	strategyRepo := repository.NewPostgresStrategyRepository(db)
 	strategyPerfRepo := repository.NewPostgresStrategyPerformanceRepository(db)
+	modelRepo := repository.NewPostgresModelRepository(db)
+	predictionRepo := repository.NewPostgresPredictionRepository(db)
 
 	// Initialize ML client
 	mlClient := ml.NewMLClient(&cfg.MLService, appLog)
 	cachedMLClient := ml.NewCachedMLClient(mlClient, appLog)
 
 	appLog.WithField("ml_service_url", cfg.MLService.URL).Info("ML client initialized")
+	healthServer.RegisterCheck("ml", ml.NewHTTPClient(&cfg.MLService, appLog).HealthCheck)
 
 	// Initialize Betfair services
 	orderLogger := log.New(os.Stdout, "order-manager: ", log.LstdFlags)
@@ -261,6 +330,19 @@ func main() {
 	if err != nil {
 		appLog.WithError(err).Fatal("Failed to initialize Betfair services")
 	}
+	if bettingService != nil {
+		healthServer.RegisterCheck("betfair", bettingService.HealthCheck)
+	}
+	if orderManager != nil && cfg.Bot.UnmatchedBets.Enabled {
+		unmatched := cfg.Bot.UnmatchedBets
+		orderManager.SetUnmatchedPolicy(betfair.UnmatchedPolicy{
+			GracePeriod:   time.Duration(unmatched.GracePeriodSeconds) * time.Second,
+			RepriceWithin: time.Duration(unmatched.RepriceWithinSeconds) * time.Second,
+			RepriceStep:   unmatched.RepriceStepTicks,
+			MaxReprices:   unmatched.MaxReprices,
+			CancelWithin:  time.Duration(unmatched.CancelWithinSeconds) * time.Second,
+		}, raceRepo)
+	}
 
 	// Create bot orchestrator
 	repos := bot.Repositories{
@@ -270,6 +352,8 @@ func main() {
 		Odds:                oddsRepo,
 		Bet:                 betRepo,
 		StrategyPerformance: strategyPerfRepo,
+		Model:               modelRepo,
+		Prediction:          predictionRepo,
 	}
 
 	orchestrator, err := bot.NewOrchestrator(
@@ -288,6 +372,28 @@ func main() {
 		appLog.WithError(err).Fatal("Failed to create orchestrator")
 	}
 
+	accountRouter, err := initAccountRouter(cfg, bettingService, betRepo, orderLogger, appLog)
+	if err != nil {
+		appLog.WithError(err).Fatal("Failed to initialize Betfair accounts")
+	}
+	if accountRouter != nil {
+		orchestrator.SetAccountRouter(accountRouter)
+	}
+	healthServer.Handle(bot.SimulatePath, orchestrator.SimulateHandler())
+	if cfg.Bot.Admin.Enabled {
+		if admin := cfg.Bot.Admin.Backtest; admin.Enabled {
+			orchestrator.EnableBacktests(&repository.Repositories{
+				Race:           raceRepo,
+				Runner:         runnerRepo,
+				Odds:           oddsRepo,
+				Strategy:       strategyRepo,
+				RaceResult:     repository.NewPostgresRaceResultRepository(db),
+				BacktestResult: repository.NewPostgresBacktestResultRepository(db),
+			}, admin.MaxConcurrent, time.Duration(admin.TimeoutSeconds)*time.Second)
+		}
+		healthServer.Handle(bot.AdminStrategiesPath, orchestrator.AdminHandler(cfg.Bot.Admin.Secret))
+	}
+
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
 
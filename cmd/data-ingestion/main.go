@@ -16,6 +16,7 @@ import (
 	dbpkg "github.com/yourusername/clever-better/internal/database"
 	"github.com/yourusername/clever-better/internal/health"
 	"github.com/yourusername/clever-better/internal/logger"
+	"github.com/yourusername/clever-better/internal/metrics"
 	"github.com/yourusername/clever-better/internal/repository"
 	"github.com/yourusername/clever-better/internal/scheduler"
 	"github.com/yourusername/clever-better/internal/service"
@@ -67,11 +68,22 @@ func createDataSources(cfg *config.Config, httpClient datasource.HTTPClient, app
 		return nil, fmt.Errorf("no data sources configured")
 	}
 
+	failover := cfg.DataIngestion.Failover
+	if failover.Enabled && len(sources) > 1 {
+		reprobe := time.Duration(failover.ReprobeIntervalSeconds) * time.Second
+		source, err := datasource.NewFailoverSource(sources, reprobe, appLog)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create failover source: %w", err)
+		}
+		appLog.Infof("Data source failover enabled with %s as primary", source.Name())
+		return []datasource.DataSource{source}, nil
+	}
+
 	return sources, nil
 }
 
 // scheduleJobs configures and schedules data ingestion jobs
-func scheduleJobs(cfg *config.Config, sched *scheduler.Scheduler, appLog logger.Interface) error {
+func scheduleJobs(cfg *config.Config, sched *scheduler.Scheduler, repos *repository.Repositories, appLog logger.Interface) error {
 	if cfg.App.Scheduler.HistoricalSyncEnabled {
 		if err := sched.ScheduleHistoricalSync(
 			cfg.App.Scheduler.HistoricalSyncCronExpression,
@@ -90,6 +102,14 @@ func scheduleJobs(cfg *config.Config, sched *scheduler.Scheduler, appLog logger.
 		}
 	}
 
+	retention := cfg.DataIngestion.OddsRetention
+	if retention.Enabled {
+		retentionSvc := service.NewOddsRetentionService(repos.Chunk, retention, appLog)
+		if err := sched.ScheduleOddsRetention(retention.Schedule, retentionSvc); err != nil {
+			return fmt.Errorf("failed to schedule odds retention: %w", err)
+		}
+	}
+
 	return nil
 }
 
@@ -138,7 +158,10 @@ func main() {
 	}
 
 	// Set up logging
-	appLog := logger.NewLogger(cfg.App.LogLevel)
+	appLog, err := logger.NewFromConfig(cfg.App)
+	if err != nil {
+		log.Fatalf("Logger configuration error: %v", err)
+	}
 	appLog.Info("Clever Better Data Ingestion Service")
 	appLog.Infof("Version: %s, Commit: %s, Build Date: %s", Version, GitCommit, BuildDate)
 	appLog.Infof("Running in %s mode with log level: %s", cfg.App.Environment, cfg.App.LogLevel)
@@ -162,10 +185,35 @@ func main() {
 		ServiceName: "data-ingestion",
 		Version:     Version,
 		Commit:      GitCommit,
+		BuildDate:   BuildDate,
 		Logger:      appLog,
 		DB:          nil, // Connection interface differs; health server will skip DB check
 	})
 
+	// Initialize repositories
+	repos, err := repository.NewRepositories(db)
+	if err != nil {
+		appLog.Fatalf("Failed to create repositories: %v", err)
+	}
+
+	if cfg.DataIngestion.ResultWebhook.Enabled {
+		settler := service.NewBetSettlementService(repos.Bet, repos.Runner, cfg.Backtest.CommissionRate, appLog)
+		healthServer.Handle(service.ResultWebhookPath, service.NewResultWebhookHandler(
+			cfg.DataIngestion.ResultWebhook.Secret,
+			repos.Race,
+			repos.Runner,
+			repos.RaceResult,
+			settler,
+			appLog,
+		))
+		appLog.Infof("Result webhook enabled at %s", service.ResultWebhookPath)
+	}
+
+	if cfg.Metrics.Enabled {
+		metrics.InitRegistry()
+		healthServer.Handle(cfg.Metrics.Path, metrics.Handler())
+	}
+
 	if err := healthServer.Start(ctx); err != nil {
 		appLog.Errorf("Failed to start health server: %v", err)
 	} else {
@@ -173,12 +221,6 @@ func main() {
 	}
 	defer healthServer.Shutdown()
 
-	// Initialize repositories
-	repos, err := repository.NewRepositories(db)
-	if err != nil {
-		appLog.Fatalf("Failed to create repositories: %v", err)
-	}
-
 	// Initialize HTTP client
 	httpClientCfg := datasource.DefaultHTTPClientConfig()
 	httpClientCfg.RateLimit = float64(cfg.App.RateLimit.RequestsPerSecond)
@@ -205,14 +247,16 @@ func main() {
 		appLog,
 		100, // batch size
 	)
+	ingestionSvc.EnableSyncCursor(repos.SyncCursor, db)
 
 	appLog.Info("Ingestion service initialized")
 
 	// Initialize scheduler
 	sched := scheduler.NewScheduler(ingestionSvc, appLog)
+	sched.SetMaintenance(cfg.Betfair.Maintenance)
 
 	// Schedule jobs based on configuration
-	if err := scheduleJobs(cfg, sched, appLog); err != nil {
+	if err := scheduleJobs(cfg, sched, repos, appLog); err != nil {
 		appLog.Warnf("Job scheduling error: %v", err)
 	}
 
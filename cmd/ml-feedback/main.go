@@ -2,9 +2,14 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"os"
+	"os/signal"
+	"syscall"
+	"text/tabwriter"
+	"time"
 
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
@@ -29,6 +34,10 @@ var (
 var (
 	configFile  string
 	batchSize   int
+	retryEvery  time.Duration
+	exportStart string
+	exportEnd   string
+	exportPath  string
 	logger      *logrus.Logger
 	mlLogger    *applogger.MLLogger
 	cfg         *config.Config
@@ -40,6 +49,17 @@ var (
 func init() {
 	rootCmd.PersistentFlags().StringVarP(&configFile, "config", "c", "./config/config.yaml", "Path to configuration file")
 	submitCmd.Flags().IntVarP(&batchSize, "batch-size", "b", 100, "Number of backtest results to submit per batch")
+	retryCmd.Flags().IntVarP(&batchSize, "batch-size", "b", 100, "Number of dead-lettered submissions to retry per pass")
+	retryCmd.Flags().DurationVar(&retryEvery, "interval", 0, "Keep retrying at this interval; a single pass when zero")
+	exportTrainingCmd.Flags().StringVar(&exportStart, "start", "", "First settlement date to export (YYYY-MM-DD)")
+	exportTrainingCmd.Flags().StringVar(&exportEnd, "end", "", "Last settlement date to export (YYYY-MM-DD), inclusive")
+	exportTrainingCmd.Flags().StringVarP(&exportPath, "output", "o", "", "Write JSON lines to this file instead of stdout")
+	_ = exportTrainingCmd.MarkFlagRequired("start")
+	_ = exportTrainingCmd.MarkFlagRequired("end")
+	attributionCmd.Flags().StringVar(&exportStart, "start", "", "First settlement date to report (YYYY-MM-DD)")
+	attributionCmd.Flags().StringVar(&exportEnd, "end", "", "Last settlement date to report (YYYY-MM-DD), inclusive")
+	_ = attributionCmd.MarkFlagRequired("start")
+	_ = attributionCmd.MarkFlagRequired("end")
 }
 
 var rootCmd = &cobra.Command{
@@ -66,6 +86,34 @@ var submitCmd = &cobra.Command{
 	},
 }
 
+var retryCmd = &cobra.Command{
+	Use:   "retry",
+	Short: "Redeliver dead-lettered feedback",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		return retryDeadLetters(ctx)
+	},
+}
+
+var exportTrainingCmd = &cobra.Command{
+	Use:   "export-training",
+	Short: "Export settled bets as labelled training examples",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+		return exportTrainingExamples(ctx)
+	},
+}
+
+var attributionCmd = &cobra.Command{
+	Use:   "attribution",
+	Short: "Split each strategy's settled P&L into expected edge and luck",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+		return reportAttribution(ctx)
+	},
+}
+
 var retrainCmd = &cobra.Command{
 	Use:   "retrain",
 	Short: "Trigger model retraining",
@@ -85,7 +133,7 @@ var statusCmd = &cobra.Command{
 }
 
 func main() {
-	rootCmd.AddCommand(submitCmd, retrainCmd, statusCmd)
+	rootCmd.AddCommand(submitCmd, retryCmd, exportTrainingCmd, attributionCmd, retrainCmd, statusCmd)
 
 	if err := rootCmd.Execute(); err != nil {
 		log.Fatalf("Error: %v", err)
@@ -112,9 +160,11 @@ func loadConfig() error {
 
 func setupDependencies() error {
 	// Setup logger
-	logger = logrus.New()
-	logger.SetLevel(logrus.DebugLevel)
-	logger.SetFormatter(&logrus.JSONFormatter{})
+	var err error
+	logger, err = applogger.NewFromConfig(cfg.App)
+	if err != nil {
+		return fmt.Errorf("failed to configure logger: %w", err)
+	}
 
 	// Initialize ML logger
 	mlLogger = applogger.NewMLLogger(logger)
@@ -125,17 +175,20 @@ func setupDependencies() error {
 		if daemonAddr == "" {
 			daemonAddr = "localhost:2000"
 		}
-		tracing.Initialize(tracing.Config{
-			ServiceName: "clever-better-ml-feedback",
-			Enabled:     true,
-			SamplingRate: 0.1,
-			DaemonAddr:  daemonAddr,
-		}, logger)
+		serviceName := "clever-better-ml-feedback"
+		if err := tracing.Initialize(tracing.Config{
+			ServiceName:   serviceName,
+			Enabled:       true,
+			SamplingRate:  cfg.Tracing.SamplingRateFor(serviceName),
+			SlowThreshold: time.Duration(cfg.Tracing.SlowThresholdMs) * time.Millisecond,
+			DaemonAddr:    daemonAddr,
+		}, logger); err != nil {
+			return fmt.Errorf("failed to initialize tracing: %w", err)
+		}
 		logger.WithField("daemon_addr", daemonAddr).Info("AWS X-Ray tracing initialized")
 	}
 
 	// Connect to database
-	var err error
 	db, err = database.New(cfg.Database)
 	if err != nil {
 		return fmt.Errorf("failed to connect to database: %w", err)
@@ -155,6 +208,8 @@ func setupDependencies() error {
 
 	httpClient := ml.NewHTTPClient(&cfg.MLService, logger)
 	mlFeedback = service.NewMLFeedbackService(mlClient, httpClient, repos.BacktestResult, logger)
+	mlFeedback.SetLocker(db)
+	mlFeedback.SetDeadLetterStore(repos.FeedbackDeadLetter)
 
 	return nil
 }
@@ -163,6 +218,10 @@ func submitBatchFeedback(ctx context.Context) error {
 	logger.WithField("batch_size", batchSize).Info("Submitting batch feedback")
 
 	count, err := mlFeedback.SubmitBatch(ctx, batchSize)
+	if errors.Is(err, service.ErrFeedbackInProgress) {
+		fmt.Println("Another feedback submission is already running, skipping")
+		return nil
+	}
 	if err != nil {
 		logger.WithError(err).Error("Failed to submit batch feedback")
 		mlLogger.LogMLPredictionError("feedback_submission", err.Error())
@@ -174,6 +233,89 @@ func submitBatchFeedback(ctx context.Context) error {
 	return nil
 }
 
+func retryDeadLetters(ctx context.Context) error {
+	if retryEvery > 0 {
+		mlFeedback.ScheduleDeadLetterRetry(ctx, retryEvery, batchSize)
+		return nil
+	}
+
+	count, err := mlFeedback.RetryDeadLetters(ctx, batchSize)
+	if err != nil {
+		logger.WithError(err).Error("Failed to retry dead-lettered feedback")
+		return err
+	}
+
+	fmt.Printf("Redelivered %d dead-lettered feedback submissions\n", count)
+	return nil
+}
+
+func exportTrainingExamples(ctx context.Context) error {
+	start, err := time.Parse("2006-01-02", exportStart)
+	if err != nil {
+		return fmt.Errorf("invalid start date: %w", err)
+	}
+	end, err := time.Parse("2006-01-02", exportEnd)
+	if err != nil {
+		return fmt.Errorf("invalid end date: %w", err)
+	}
+	end = end.Add(24*time.Hour - time.Nanosecond)
+
+	exporter := service.NewTrainingExampleExporter(repos.Bet, repos.Runner, repos.Prediction, repos.RaceResult, logger)
+	examples, err := exporter.ExportTrainingExamples(ctx, start, end)
+	if err != nil {
+		logger.WithError(err).Error("Failed to export training examples")
+		return err
+	}
+
+	out := os.Stdout
+	if exportPath != "" {
+		file, err := os.Create(exportPath)
+		if err != nil {
+			return fmt.Errorf("failed to create output file: %w", err)
+		}
+		defer file.Close()
+		out = file
+	}
+
+	if err := service.WriteTrainingExamples(out, examples); err != nil {
+		return err
+	}
+
+	logger.WithField("examples", len(examples)).Info("Training examples exported")
+	return nil
+}
+
+func reportAttribution(ctx context.Context) error {
+	start, err := time.Parse("2006-01-02", exportStart)
+	if err != nil {
+		return fmt.Errorf("invalid start date: %w", err)
+	}
+	end, err := time.Parse("2006-01-02", exportEnd)
+	if err != nil {
+		return fmt.Errorf("invalid end date: %w", err)
+	}
+	end = end.Add(24*time.Hour - time.Nanosecond)
+
+	reporter := service.NewPnLAttributionReporter(repos.Bet, repos.Strategy, logger)
+	report, err := reporter.Report(ctx, start, end)
+	if err != nil {
+		logger.WithError(err).Error("Failed to build P&L attribution report")
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "STRATEGY\tBETS\tSTAKE\tEXPECTED\tREALIZED\tRESIDUAL\tUNATTRIBUTED\t")
+	for _, row := range report {
+		name := row.StrategyName
+		if name == "" {
+			name = row.StrategyID.String()
+		}
+		fmt.Fprintf(w, "%s\t%d\t%.2f\t%.2f\t%.2f\t%.2f\t%d\t\n",
+			name, row.Bets, row.Stake, row.ExpectedPnL, row.RealizedPnL, row.ResidualPnL, row.UnattributedBets)
+	}
+	return w.Flush()
+}
+
 func triggerRetraining(ctx context.Context) error {
 	logger.Info("Triggering model retraining")
 
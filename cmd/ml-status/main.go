@@ -13,6 +13,7 @@ import (
 
 	"github.com/yourusername/clever-better/internal/config"
 	"github.com/yourusername/clever-better/internal/database"
+	applogger "github.com/yourusername/clever-better/internal/logger"
 	"github.com/yourusername/clever-better/internal/ml"
 	"github.com/yourusername/clever-better/internal/repository"
 	"github.com/yourusername/clever-better/internal/service"
@@ -80,12 +81,15 @@ func loadConfig() error {
 }
 
 func setupDependencies() error {
-	// Setup logger
-	logger = logrus.New()
+	// Setup logger; status output stays quiet regardless of the configured level
+	var err error
+	logger, err = applogger.NewFromConfig(cfg.App)
+	if err != nil {
+		return fmt.Errorf("failed to configure logger: %w", err)
+	}
 	logger.SetLevel(logrus.WarnLevel)
 
 	// Connect to database
-	var err error
 	db, err = database.New(cfg.Database)
 	if err != nil {
 		return fmt.Errorf("failed to connect to database: %w", err)
@@ -127,6 +131,14 @@ func displayStatus() {
 		fmt.Printf("  Hits: %d\n", hits)
 		fmt.Printf("  Misses: %d\n", misses)
 		fmt.Printf("  Hit Ratio: %.2f%%\n", ratio*100)
+
+		if budget := cfg.MLService.LatencyBudget; budget.Enabled {
+			p95, bypassed := mlClient.GetLatencyStats()
+			fmt.Printf("\nLatency Budget:\n")
+			fmt.Printf("  Budget (p95): %d ms\n", budget.P95Milliseconds)
+			fmt.Printf("  Rolling p95: %d ms\n", p95.Milliseconds())
+			fmt.Printf("  Bypassed: %v\n", bypassed)
+		}
 	}
 
 	// Get database statistics
@@ -7,6 +7,7 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
@@ -30,6 +31,7 @@ var (
 
 var (
 	configFile string
+	dryRun     bool
 	logger     *logrus.Logger
 	mlLogger   *applogger.MLLogger
 	cfg        *config.Config
@@ -39,6 +41,7 @@ var (
 
 func init() {
 	rootCmd.PersistentFlags().StringVarP(&configFile, "config", "c", "./config/config.yaml", "Path to configuration file")
+	rootCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Generate and rank strategies without saving or activating them")
 }
 
 var rootCmd = &cobra.Command{
@@ -85,9 +88,11 @@ func loadConfig() error {
 
 func setupDependencies() error {
 	// Setup logger
-	logger = logrus.New()
-	logger.SetLevel(logrus.DebugLevel)
-	logger.SetFormatter(&logrus.JSONFormatter{})
+	var err error
+	logger, err = applogger.NewFromConfig(cfg.App)
+	if err != nil {
+		return fmt.Errorf("failed to configure logger: %w", err)
+	}
 
 	mlLogger = applogger.NewMLLogger(logger)
 
@@ -96,17 +101,20 @@ func setupDependencies() error {
 		if daemonAddr == "" {
 			daemonAddr = "localhost:2000"
 		}
-		tracing.Initialize(tracing.Config{
-			ServiceName: "clever-better-strategy-discovery",
-			Enabled:     true,
-			SamplingRate: 0.1,
-			DaemonAddr:  daemonAddr,
-		}, logger)
+		serviceName := "clever-better-strategy-discovery"
+		if err := tracing.Initialize(tracing.Config{
+			ServiceName:   serviceName,
+			Enabled:       true,
+			SamplingRate:  cfg.Tracing.SamplingRateFor(serviceName),
+			SlowThreshold: time.Duration(cfg.Tracing.SlowThresholdMs) * time.Millisecond,
+			DaemonAddr:    daemonAddr,
+		}, logger); err != nil {
+			return fmt.Errorf("failed to initialize tracing: %w", err)
+		}
 		logger.WithField("daemon_addr", daemonAddr).Info("AWS X-Ray tracing initialized")
 	}
 
 	// Connect to database
-	var err error
 	db, err = database.New(cfg.Database)
 	if err != nil {
 		return fmt.Errorf("failed to connect to database: %w", err)
@@ -141,9 +149,15 @@ func runDiscoveryPipeline() {
 
 	// Create services
 	strategyGen := service.NewStrategyGeneratorService(mlClient, repos.Strategy, repos.BacktestResult, logger)
+	strategyGen.SetActivationGates(cfg.MLService.Activation)
 	mlFeedback := service.NewMLFeedbackService(mlClient, httpClient, repos.BacktestResult, logger)
+	mlFeedback.SetLocker(db)
+	mlFeedback.SetDeadLetterStore(repos.FeedbackDeadLetter)
 	strategyEval := service.NewStrategyEvaluatorService(mlClient, repos.Strategy, repos.BacktestResult, logger)
 	orchestrator := service.NewMLOrchestratorService(strategyGen, mlFeedback, strategyEval, mlClient, repos.Prediction, logger)
+	decayEvaluator := service.NewPerformanceDecayEvaluator(repos.Strategy, repos.StrategyPerformance, cfg.Bot.PerformanceDecay, logger)
+	decayEvaluator.SetMinSampleBets(cfg.Bot.MinSampleBets)
+	orchestrator.SetDecayEvaluator(decayEvaluator)
 
 	// Configuration for discovery pipeline
 	discoveryConfig := service.DiscoveryConfig{
@@ -154,6 +168,7 @@ func runDiscoveryPipeline() {
 		DeactivateThreshold: 0.50,
 		SubmitFeedback:      true,
 		TriggerRetraining:   true,
+		DryRun:              dryRun,
 	}
 
 	// Run discovery pipeline
@@ -172,6 +187,10 @@ func runDiscoveryPipeline() {
 	// Print report
 	fmt.Println("\n=== Strategy Discovery Pipeline Report ===")
 	fmt.Printf("Run ID: %s\n", report.RunID)
+	if report.DryRun {
+		fmt.Println("Dry run: nothing was saved, activated or deactivated")
+		fmt.Printf("Would Activate: %d\n", len(report.WouldActivate))
+	}
 	fmt.Printf("Generated Strategies: %d\n", report.GeneratedCount)
 	fmt.Printf("Activated Strategies: %d\n", report.ActivatedCount)
 	fmt.Printf("Deactivated Strategies: %d\n", report.DeactivatedCount)
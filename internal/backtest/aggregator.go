@@ -15,6 +15,8 @@ type AggregatedResult struct {
 	Weights                 AggregationWeights `json:"weights"`
 	Recommendation          string            `json:"recommendation"`
 	MLFeatures              map[string]float64 `json:"ml_features"`
+	// Benchmark compares the strategy with favourite backing when enabled
+	Benchmark               *BenchmarkComparison `json:"benchmark,omitempty"`
 }
 
 // AggregationWeights define weighting per method
@@ -81,17 +83,22 @@ func (a AggregatedResult) ExportForML() string {
 }
 
 func extractFeatures(h Metrics, mc MonteCarloResult, wf WalkForwardResult) map[string]float64 {
-	return map[string]float64{
+	features := map[string]float64{
 		"total_return":       h.TotalReturn,
 		"sharpe_ratio":       h.SharpeRatio,
 		"max_drawdown":       h.MaxDrawdown,
 		"profit_factor":      h.ProfitFactor,
 		"win_rate":           h.WinRate,
+		"unmatched_rate":     h.UnmatchedRate,
+		"partial_match_rate": h.PartialMatchRate,
+		"average_fill_ratio": h.AverageFillRatio,
 		"monte_carlo_var95":  mc.VaR95,
 		"monte_carlo_var99":  mc.VaR99,
 		"consistency_score":  wf.ConsistencyScore,
 		"overfit_score":      wf.OverfitScore,
 	}
+	segmentFeatures(h.Segments, features)
+	return features
 }
 
 func normalize(value, min, max float64) float64 {
@@ -0,0 +1,118 @@
+package backtest
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/yourusername/clever-better/internal/strategy"
+)
+
+// defaultBenchmarkStake is the level stake used when none is configured
+const defaultBenchmarkStake = 10.0
+
+// BenchmarkComparison compares a strategy with the favourite-backing
+// benchmark run over the same races
+type BenchmarkComparison struct {
+	Benchmark Metrics `json:"benchmark"`
+	// ExcessReturn is the strategy's total return minus the benchmark's
+	ExcessReturn float64 `json:"excess_return"`
+	// TrackingError is the standard deviation of daily excess returns
+	TrackingError float64 `json:"tracking_error"`
+	// InformationRatio is the mean daily excess return over the tracking error
+	InformationRatio float64 `json:"information_ratio"`
+}
+
+// RunBenchmarkComparison replays the engine's races with the benchmark
+// strategy and compares the result with the strategy's own run
+func RunBenchmarkComparison(ctx context.Context, engine *Engine, state *BacktestState, metrics Metrics, start, end time.Time) (BenchmarkComparison, error) {
+	stake := engine.config.BenchmarkStake
+	if stake <= 0 {
+		stake = defaultBenchmarkStake
+	}
+
+	benchmark := &Engine{
+		config:       engine.config,
+		repositories: engine.repositories,
+		strategy:     strategy.NewFavouriteBenchmarkStrategy(stake),
+		logger:       engine.logger,
+	}
+	benchmarkState, benchmarkMetrics, err := benchmark.Run(ctx, start, end)
+	if err != nil {
+		return BenchmarkComparison{}, fmt.Errorf("failed to run benchmark: %w", err)
+	}
+
+	return CompareWithBenchmark(state, metrics, benchmarkState, benchmarkMetrics, engine.config.InitialBankroll), nil
+}
+
+// CompareWithBenchmark calculates excess return and the information ratio of
+// a strategy run against a benchmark run from the same initial bankroll
+func CompareWithBenchmark(state *BacktestState, metrics Metrics, benchmarkState *BacktestState, benchmarkMetrics Metrics, initialBankroll float64) BenchmarkComparison {
+	comparison := BenchmarkComparison{
+		Benchmark:    benchmarkMetrics,
+		ExcessReturn: metrics.TotalReturn - benchmarkMetrics.TotalReturn,
+	}
+
+	days := tradingDays(state, benchmarkState)
+	strategyReturns := dailyReturns(state, days, initialBankroll)
+	benchmarkReturns := dailyReturns(benchmarkState, days, initialBankroll)
+	if len(days) < 2 {
+		return comparison
+	}
+
+	excess := make([]float64, len(days))
+	mean := 0.0
+	for i := range days {
+		excess[i] = strategyReturns[i] - benchmarkReturns[i]
+		mean += excess[i]
+	}
+	mean /= float64(len(excess))
+
+	variance := 0.0
+	for _, r := range excess {
+		variance += (r - mean) * (r - mean)
+	}
+	comparison.TrackingError = math.Sqrt(variance / float64(len(excess)))
+	if comparison.TrackingError > 0 {
+		comparison.InformationRatio = mean / comparison.TrackingError
+	}
+	return comparison
+}
+
+// tradingDays returns the days on which either run settled a bet, in order
+func tradingDays(states ...*BacktestState) []time.Time {
+	seen := make(map[time.Time]bool)
+	var days []time.Time
+	for _, state := range states {
+		if state == nil {
+			continue
+		}
+		for day := range state.DailyPnL {
+			if !seen[day] {
+				seen[day] = true
+				days = append(days, day)
+			}
+		}
+	}
+	sort.Slice(days, func(i, j int) bool { return days[i].Before(days[j]) })
+	return days
+}
+
+// dailyReturns is each day's P&L over the bankroll at the start of that day
+func dailyReturns(state *BacktestState, days []time.Time, initialBankroll float64) []float64 {
+	returns := make([]float64, len(days))
+	if state == nil {
+		return returns
+	}
+	bankroll := initialBankroll
+	for i, day := range days {
+		pnl := state.DailyPnL[day]
+		if bankroll > 0 {
+			returns[i] = pnl / bankroll
+		}
+		bankroll += pnl
+	}
+	return returns
+}
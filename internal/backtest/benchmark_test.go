@@ -0,0 +1,83 @@
+package backtest
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/clever-better/internal/models"
+	"github.com/yourusername/clever-better/internal/repository"
+)
+
+func TestRunBenchmarkComparisonBacksFavourites(t *testing.T) {
+	start := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	end := start.Add(48 * time.Hour)
+
+	races := []*models.Race{}
+	runners := map[uuid.UUID][]*models.Runner{}
+	odds := map[uuid.UUID][]*models.OddsSnapshot{}
+	results := map[uuid.UUID]*models.RaceResult{}
+	for i := 0; i < 2; i++ {
+		raceID := uuid.New()
+		off := start.Add(time.Duration(i+1) * 24 * time.Hour)
+		first := &models.Runner{ID: uuid.New(), RaceID: raceID, TrapNumber: 1}
+		second := &models.Runner{ID: uuid.New(), RaceID: raceID, TrapNumber: 2}
+		races = append(races, &models.Race{ID: raceID, ScheduledStart: off})
+		runners[raceID] = []*models.Runner{first, second}
+		odds[raceID] = []*models.OddsSnapshot{
+			{RaceID: raceID, RunnerID: first.ID, Time: off.Add(-time.Minute), BackPrice: floatPtr(3.0), LayPrice: floatPtr(3.1), BackSize: floatPtr(100)},
+			{RaceID: raceID, RunnerID: second.ID, Time: off.Add(-time.Minute), BackPrice: floatPtr(2.0), LayPrice: floatPtr(2.02), BackSize: floatPtr(100)},
+		}
+		// The favourite (trap 2) wins the first race and loses the second
+		winner := 2 - i
+		results[raceID] = &models.RaceResult{RaceID: raceID, Time: off, WinnerTrap: &winner}
+	}
+
+	engine := &Engine{
+		config: BacktestConfig{InitialBankroll: 100, BenchmarkStake: 10, Benchmark: true},
+		repositories: &repository.Repositories{
+			Race:       &fakeRaceRepo{races: races, runners: runners, odds: odds},
+			RaceResult: &fakeRaceResultRepo{results: results},
+		},
+		strategy: testStrategy{},
+		logger:   logrus.New(),
+	}
+
+	state, metrics, err := engine.Run(context.Background(), start, end)
+	require.NoError(t, err)
+	comparison, err := RunBenchmarkComparison(context.Background(), engine, state, metrics, start, end)
+	require.NoError(t, err)
+
+	// Backing the 2.0 favourite wins 10 then loses 10; the 3.0 outsider
+	// would have lost 10 then won 20
+	assert.Equal(t, 2, comparison.Benchmark.TotalBets)
+	assert.Equal(t, 1, comparison.Benchmark.WinningBets)
+	assert.InDelta(t, 0.0, comparison.Benchmark.TotalReturn, 1e-9)
+	assert.InDelta(t, metrics.TotalReturn-comparison.Benchmark.TotalReturn, comparison.ExcessReturn, 1e-12)
+}
+
+func TestCompareWithBenchmarkExcessReturn(t *testing.T) {
+	day1 := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	day2 := day1.AddDate(0, 0, 1)
+
+	strategyState := &BacktestState{DailyPnL: map[time.Time]float64{day1: 10, day2: 11}}
+	benchmarkState := &BacktestState{DailyPnL: map[time.Time]float64{day1: -5, day2: 0}}
+
+	comparison := CompareWithBenchmark(strategyState, Metrics{TotalReturn: 0.21}, benchmarkState, Metrics{TotalReturn: -0.05}, 100)
+	assert.InDelta(t, 0.26, comparison.ExcessReturn, 1e-12)
+
+	// Daily returns: strategy 10/100 and 11/110; benchmark -5/100 and 0/95
+	excess := []float64{0.10 + 0.05, 0.10 - 0}
+	mean := (excess[0] + excess[1]) / 2
+	trackingError := math.Sqrt(((excess[0]-mean)*(excess[0]-mean) + (excess[1]-mean)*(excess[1]-mean)) / 2)
+	assert.InDelta(t, trackingError, comparison.TrackingError, 1e-12)
+	assert.InDelta(t, mean/trackingError, comparison.InformationRatio, 1e-9)
+
+	flat := CompareWithBenchmark(strategyState, Metrics{}, strategyState, Metrics{}, 100)
+	assert.Zero(t, flat.InformationRatio, "no tracking error, no ratio")
+}
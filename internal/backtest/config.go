@@ -2,9 +2,12 @@ package backtest
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/yourusername/clever-better/internal/config"
+	"github.com/yourusername/clever-better/internal/models"
+	"github.com/yourusername/clever-better/internal/strategy"
 )
 
 // BacktestConfig extends core config with backtest-specific settings
@@ -13,13 +16,56 @@ type BacktestConfig struct {
 	EndDate              time.Time
 	InitialBankroll      float64
 	CommissionRate       float64
+	// CommissionPromos override CommissionRate for bets settled inside them
+	CommissionPromos     []CommissionPromo
 	SlippageTicks        int
+	// SlippageByMarket overrides SlippageTicks for races whose track or race
+	// type matches a key, ignoring case. A track match wins over a race type.
+	SlippageByMarket     map[string]int
 	MinLiquidity         float64
+	// MaxLadderDepth bounds how many price levels of a recorded ladder count
+	// towards the size a simulated bet can fill. Zero uses the whole ladder.
+	MaxLadderDepth       int
+	// OddsSourcePreference picks between snapshots recorded by several
+	// sources at the same time. Set it from the trading config to match the
+	// bot.
+	OddsSourcePreference models.OddsSourcePreference
+	// StakeRounding rounds each simulated stake as the bot would before
+	// placing it. Set it from the trading config to match the bot.
+	StakeRounding        strategy.StakeRounding
+	// Rejection drops a share of simulated bets as live placement would
+	Rejection            RejectionModel
 	OutputPath           string
 	MLExportEnabled      bool
 	MonteCarloIterations int
 	WalkForwardWindows   int
 	RiskFreeRate         float64
+	// MarketFilter excludes races from the backtest. Set it from the trading
+	// market filter so backtests trade the same markets as the bot.
+	MarketFilter         *strategy.MarketFilter
+	// IncludeReserveRunners lets the strategy bet on reserve runners. Set it
+	// from the trading config to match the bot.
+	IncludeReserveRunners bool
+	// Streaming replays races from a database cursor rather than loading
+	// the whole window, bounding memory on long backtests
+	Streaming            bool
+	// Benchmark runs the favourite-backing benchmark over the same races for
+	// comparison, at BenchmarkStake per race
+	Benchmark            bool
+	BenchmarkStake       float64
+	// Tags are stamped on the persisted result so the run can be found later
+	Tags                 []string
+	// Weights combine the method scores into an aggregated result's
+	// composite score
+	Weights              AggregationWeights
+}
+
+// CommissionPromo charges Rate instead of the normal commission on bets settled
+// from Start up to, but not including, End
+type CommissionPromo struct {
+	Start time.Time
+	End   time.Time
+	Rate  float64
 }
 
 // FromConfig converts app config to backtest config
@@ -42,12 +88,34 @@ func FromConfig(cfg *config.BacktestConfig) (BacktestConfig, error) {
 		InitialBankroll:      cfg.InitialBankroll,
 		CommissionRate:       cfg.CommissionRate,
 		SlippageTicks:        cfg.SlippageTicks,
+		SlippageByMarket:     cfg.SlippageByMarket,
 		MinLiquidity:         cfg.MinLiquidity,
 		OutputPath:           cfg.OutputPath,
 		MLExportEnabled:      cfg.MLExportEnabled,
 		MonteCarloIterations: cfg.MonteCarloIterations,
 		WalkForwardWindows:   cfg.WalkForwardWindows,
 		RiskFreeRate:         cfg.RiskFreeRate,
+		Streaming:            cfg.Streaming,
+		Benchmark:            cfg.Benchmark,
+		BenchmarkStake:       cfg.BenchmarkStake,
+		Weights:              WeightsFromConfig(cfg.CompositeWeights),
+		Rejection:            RejectionModel(cfg.Rejection),
+	}
+	for _, promo := range cfg.CommissionPromos {
+		promoStart, err := time.Parse("2006-01-02", promo.StartDate)
+		if err != nil {
+			return BacktestConfig{}, fmt.Errorf("invalid commission promo start date: %w", err)
+		}
+		promoEnd, err := time.Parse("2006-01-02", promo.EndDate)
+		if err != nil {
+			return BacktestConfig{}, fmt.Errorf("invalid commission promo end date: %w", err)
+		}
+		// The end date is inclusive, so the window runs to the following midnight
+		bt.CommissionPromos = append(bt.CommissionPromos, CommissionPromo{
+			Start: promoStart,
+			End:   promoEnd.AddDate(0, 0, 1),
+			Rate:  promo.Rate,
+		})
 	}
 
 	return bt, bt.Validate()
@@ -64,11 +132,62 @@ func (b BacktestConfig) Validate() error {
 	if b.CommissionRate < 0 || b.CommissionRate > 0.1 {
 		return fmt.Errorf("commission rate must be between 0 and 0.1")
 	}
+	for _, promo := range b.CommissionPromos {
+		if !promo.Start.Before(promo.End) {
+			return fmt.Errorf("commission promo start must be before its end")
+		}
+		if promo.Rate < 0 || promo.Rate > 0.1 {
+			return fmt.Errorf("commission promo rate must be between 0 and 0.1")
+		}
+	}
 	if b.SlippageTicks < 0 {
 		return fmt.Errorf("slippage ticks cannot be negative")
 	}
+	for market, ticks := range b.SlippageByMarket {
+		if ticks < 0 {
+			return fmt.Errorf("slippage ticks for %s cannot be negative", market)
+		}
+	}
+	if err := b.Rejection.Validate(); err != nil {
+		return err
+	}
 	if b.MonteCarloIterations <= 0 {
 		return fmt.Errorf("monte carlo iterations must be positive")
 	}
 	return nil
 }
+
+// CommissionRateAt returns the commission rate for a bet settled at t. The
+// first promo covering t wins; outside every promo the normal rate applies.
+func (b BacktestConfig) CommissionRateAt(t time.Time) float64 {
+	for _, promo := range b.CommissionPromos {
+		if !t.Before(promo.Start) && t.Before(promo.End) {
+			return promo.Rate
+		}
+	}
+	return b.CommissionRate
+}
+
+// SlippageFor returns the slippage ticks to apply to bets on a race
+func (b BacktestConfig) SlippageFor(race *models.Race) int {
+	if race != nil {
+		for _, key := range []string{race.Track, race.RaceType} {
+			if ticks, ok := lookupSlippage(b.SlippageByMarket, key); ok {
+				return ticks
+			}
+		}
+	}
+	return b.SlippageTicks
+}
+
+func lookupSlippage(overrides map[string]int, key string) (int, bool) {
+	if key == "" {
+		return 0, false
+	}
+	for market, ticks := range overrides {
+		if strings.EqualFold(market, key) {
+			return ticks, true
+		}
+	}
+	return 0, false
+}
@@ -2,6 +2,7 @@ package backtest
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math"
 	"time"
@@ -48,6 +49,27 @@ func NewEngine(cfg BacktestConfig, db *database.DB, strat strategy.Strategy, log
 	}, nil
 }
 
+// NewEngineWithRepositories creates an engine over repositories the caller
+// already holds, such as a running service's. Close leaves their database
+// connection open.
+func NewEngineWithRepositories(cfg BacktestConfig, repos *repository.Repositories, strat strategy.Strategy, logger *logrus.Logger) (*Engine, error) {
+	if repos == nil {
+		return nil, fmt.Errorf("repositories are required")
+	}
+	if strat == nil {
+		return nil, fmt.Errorf("strategy is required")
+	}
+	if logger == nil {
+		logger = logrus.New()
+	}
+	return &Engine{
+		config:       cfg,
+		repositories: repos,
+		strategy:     strat,
+		logger:       logger,
+	}, nil
+}
+
 // Config returns the backtest configuration
 func (e *Engine) Config() BacktestConfig {
 	return e.config
@@ -71,10 +93,21 @@ func (e *Engine) Close(ctx context.Context) error {
 	return e.db.Close(ctx)
 }
 
+// ErrCancelled is returned when a run is stopped by context cancellation.
+// The state and metrics returned alongside it cover the races processed so far.
+var ErrCancelled = errors.New("backtest cancelled")
+
 // Run orchestrates backtest execution
 func (e *Engine) Run(ctx context.Context, startDate, endDate time.Time) (*BacktestState, Metrics, error) {
-	e.logger.WithFields(logrus.Fields{"start": startDate, "end": endDate}).Info("Starting backtest run")
-	state, err := e.HistoricalReplay(ctx, startDate, endDate)
+	e.logger.WithFields(logrus.Fields{"start": startDate, "end": endDate, "streaming": e.config.Streaming}).Info("Starting backtest run")
+	replay := e.HistoricalReplay
+	if e.config.Streaming {
+		replay = e.StreamingReplay
+	}
+	state, err := replay(ctx, startDate, endDate)
+	if errors.Is(err, ErrCancelled) {
+		return state, CalculateMetrics(state, e.config), err
+	}
 	if err != nil {
 		return nil, Metrics{}, err
 	}
@@ -82,7 +115,9 @@ func (e *Engine) Run(ctx context.Context, startDate, endDate time.Time) (*Backte
 	return state, metrics, nil
 }
 
-// HistoricalReplay replays historical races and simulates betting
+// HistoricalReplay replays historical races and simulates betting. If ctx is
+// cancelled it stops between races and returns the partial state with
+// ErrCancelled.
 func (e *Engine) HistoricalReplay(ctx context.Context, startDate, endDate time.Time) (*BacktestState, error) {
 	state := NewBacktestState(e.config.InitialBankroll)
 
@@ -91,8 +126,14 @@ func (e *Engine) HistoricalReplay(ctx context.Context, startDate, endDate time.T
 		return nil, fmt.Errorf("failed to load races: %w", err)
 	}
 
-	for _, race := range races {
+	for i, race := range races {
+		if ctx.Err() != nil {
+			return state, e.cancelled(i, len(races))
+		}
 		if err := e.processRace(ctx, race, startDate, state); err != nil {
+			if ctx.Err() != nil {
+				return state, e.cancelled(i, len(races))
+			}
 			return nil, err
 		}
 	}
@@ -100,24 +141,66 @@ func (e *Engine) HistoricalReplay(ctx context.Context, startDate, endDate time.T
 	return state, nil
 }
 
-func (e *Engine) processRace(ctx context.Context, race *models.Race, startDate time.Time, state *BacktestState) error {
-	runners, err := e.repositories.Runner.GetByRaceID(ctx, race.ID)
+// StreamingReplay replays races like HistoricalReplay but reads them one at a
+// time from RaceRepository.StreamByDateRange instead of loading the window up
+// front, so memory does not grow with the window. Runners and odds are loaded
+// per race in both paths.
+func (e *Engine) StreamingReplay(ctx context.Context, startDate, endDate time.Time) (*BacktestState, error) {
+	state := NewBacktestState(e.config.InitialBankroll)
+
+	processed := 0
+	err := e.repositories.Race.StreamByDateRange(ctx, startDate, endDate, func(race *models.Race) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := e.processRace(ctx, race, startDate, state); err != nil {
+			return err
+		}
+		processed++
+		return nil
+	})
 	if err != nil {
-		return fmt.Errorf("failed to load runners: %w", err)
+		if ctx.Err() != nil {
+			e.logger.WithField("races_processed", processed).Warn("Backtest cancelled")
+			return state, fmt.Errorf("%w after %d races", ErrCancelled, processed)
+		}
+		return nil, err
+	}
+
+	return state, nil
+}
+
+// cancelled reports a run stopped after processed of total races
+func (e *Engine) cancelled(processed, total int) error {
+	e.logger.WithFields(logrus.Fields{
+		"races_processed": processed,
+		"races_total":     total,
+	}).Warn("Backtest cancelled")
+	return fmt.Errorf("%w after %d of %d races", ErrCancelled, processed, total)
+}
+
+// processRace evaluates the strategy on a race, loading its runners and its
+// odds from startDate to the off in one query, and settles any bets placed
+func (e *Engine) processRace(ctx context.Context, race *models.Race, startDate time.Time, state *BacktestState) error {
+	if !e.config.MarketFilter.Allows(race) {
+		return nil
 	}
 
-	oddsSnapshots, err := e.repositories.Odds.GetByRaceID(ctx, race.ID, startDate, race.ScheduledStart)
+	bundle, err := e.repositories.Race.GetRaceBundle(ctx, race.ID, startDate)
 	if err != nil {
-		return fmt.Errorf("failed to load odds: %w", err)
+		return fmt.Errorf("failed to load runners and odds: %w", err)
 	}
+	runners := bundle.Runners
+	oddsSnapshots := e.config.OddsSourcePreference.Prefer(bundle.Odds)
 
 	decisionTime := race.ScheduledStart
 	filteredOdds := filterOddsByTime(oddsSnapshots, decisionTime)
 	strategyCtx := strategy.Context{
-		Race:        race,
-		Runners:     runners,
-		OddsHistory: filteredOdds,
-		CurrentTime: decisionTime,
+		Race:            race,
+		Runners:         runners,
+		OddsHistory:     filteredOdds,
+		CurrentTime:     decisionTime,
+		IncludeReserves: e.config.IncludeReserveRunners,
 	}
 
 	signals, err := e.strategy.Evaluate(ctx, strategyCtx)
@@ -130,6 +213,11 @@ func (e *Engine) processRace(ctx context.Context, race *models.Race, startDate t
 		return fmt.Errorf("failed to load race result: %w", err)
 	}
 
+	commissionRate := e.config.CommissionRate
+	if result != nil {
+		commissionRate = e.config.CommissionRateAt(result.Time)
+	}
+
 	runnerByID := make(map[uuid.UUID]*models.Runner)
 	for _, runner := range runners {
 		runnerByID[runner.ID] = runner
@@ -146,14 +234,24 @@ func (e *Engine) processRace(ctx context.Context, race *models.Race, startDate t
 		adjusted := signal
 		adjusted.Stake = stake
 
-		bet := e.SimulateBetExecution(adjusted, filteredOdds)
+		size, sizeKnown := availableSize(adjusted, filteredOdds, e.config.MaxLadderDepth)
+		if sizeKnown {
+			state.RecordLiquidity(size)
+		}
+		if e.rejected(stake, size, sizeKnown, state) {
+			state.RecordRejection(stake)
+			continue
+		}
+		bet := e.SimulateBetExecution(race, adjusted, filteredOdds)
+		state.RecordFill(stake, filledStake(bet))
 		if bet == nil {
 			continue
 		}
 		bet.RaceID = race.ID
+		state.RecordVenue(race.ID, race.Track)
 
 		runner := runnerByID[signal.RunnerID]
-		pnl := e.SettleBet(bet, result, runner, e.config.CommissionRate)
+		pnl := e.SettleBet(bet, result, runner, commissionRate)
 		state.UpdateState(bet, pnl)
 		if bet.SettledAt != nil {
 			state.RecordEquityPoint(bet.SettledAt.UTC(), state.CurrentBankroll)
@@ -174,47 +272,145 @@ func filterOddsByTime(odds []*models.OddsSnapshot, cutoff time.Time) []*models.O
 	return filtered
 }
 
-// SimulateBetExecution simulates execution with slippage and transaction costs
-func (e *Engine) SimulateBetExecution(signal strategy.Signal, oddsHistory []*models.OddsSnapshot) *models.Bet {
-	_ = oddsHistory
-	if signal.Stake <= 0 || signal.Odds <= 1 {
+// SimulateBetExecution simulates execution with slippage and transaction costs.
+// Slippage is chosen for the race's market. The stake is capped at the size
+// available for the runner in the latest odds snapshot; nil is returned when
+// nothing could be matched.
+func (e *Engine) SimulateBetExecution(race *models.Race, signal strategy.Signal, oddsHistory []*models.OddsSnapshot) *models.Bet {
+	if signal.Stake <= 0 {
+		return nil
+	}
+	if signal.BSP {
+		return e.simulateBSPBet(signal)
+	}
+	if signal.Odds <= 1 {
+		return nil
+	}
+	stake, err := e.config.StakeRounding.Round(signal.Stake)
+	if err != nil {
+		return nil
+	}
+	signal.Stake = stake
+
+	matched := availableStake(signal, oddsHistory, e.config.MaxLadderDepth)
+	if matched <= 0 {
 		return nil
 	}
 
-	odds := applySlippage(signal.Odds, signal.Side, e.config.SlippageTicks)
+	odds := applySlippage(signal.Odds, signal.Side, e.config.SlippageFor(race))
 	betID := uuid.New()
 	now := time.Now().UTC()
 
 	bet := &models.Bet{
-		ID:         betID,
+		ID:          betID,
+		RaceID:      uuid.Nil,
+		RunnerID:    signal.RunnerID,
+		StrategyID:  uuid.Nil,
+		MarketType:  models.MarketTypeWin,
+		Side:        signal.Side,
+		Odds:        odds,
+		Stake:       matched,
+		MatchedSize: &matched,
+		Status:      models.BetStatusMatched,
+		PlacedAt:    now,
+		MatchedAt:   &now,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	return bet
+}
+
+// availableStake returns how much of a signal's stake the market could take,
+// using the runner's latest snapshot. Snapshots without a recorded size are
+// treated as unconstrained.
+func availableStake(signal strategy.Signal, oddsHistory []*models.OddsSnapshot, maxDepth int) float64 {
+	size, ok := availableSize(signal, oddsHistory, maxDepth)
+	if !ok {
+		return signal.Stake
+	}
+	return math.Min(signal.Stake, size)
+}
+
+// availableSize returns the size offered on the signal's side across the
+// best maxDepth ladder levels of the runner's latest snapshot, and false when
+// none was recorded
+func availableSize(signal strategy.Signal, oddsHistory []*models.OddsSnapshot, maxDepth int) (float64, bool) {
+	var latest *models.OddsSnapshot
+	for _, snapshot := range oddsHistory {
+		if snapshot.RunnerID != signal.RunnerID {
+			continue
+		}
+		if latest == nil || snapshot.Time.After(latest.Time) {
+			latest = snapshot
+		}
+	}
+	if latest == nil {
+		return 0, false
+	}
+	return latest.AvailableSize(signal.Side, maxDepth)
+}
+
+// filledStake returns the stake matched on a simulated bet, zero when none
+func filledStake(bet *models.Bet) float64 {
+	if bet == nil {
+		return 0
+	}
+	return bet.Stake
+}
+
+// simulateBSPBet records a Betfair Starting Price bet. The price is unknown
+// until the off, so no slippage is applied and the bet is left unmatched until
+// SettleBet resolves it against the race result.
+func (e *Engine) simulateBSPBet(signal strategy.Signal) *models.Bet {
+	now := time.Now().UTC()
+	return &models.Bet{
+		ID:         uuid.New(),
 		RaceID:     uuid.Nil,
 		RunnerID:   signal.RunnerID,
 		StrategyID: uuid.Nil,
 		MarketType: models.MarketTypeWin,
 		Side:       signal.Side,
-		Odds:       odds,
+		Odds:       signal.Odds,
 		Stake:      signal.Stake,
-		Status:     models.BetStatusMatched,
+		IsBSP:      true,
+		Status:     models.BetStatusPending,
 		PlacedAt:   now,
-		MatchedAt:  &now,
 		CreatedAt:  now,
 		UpdatedAt:  now,
 	}
-
-	return bet
 }
 
 // SettleBet settles a bet against race results and returns PnL
 func (e *Engine) SettleBet(bet *models.Bet, result *models.RaceResult, runner *models.Runner, commissionRate float64) float64 {
+	return SettleBet(bet, result, runner, commissionRate)
+}
+
+// SettleBet settles a bet against race results and returns PnL. It is shared
+// with callers outside the engine, such as the bot replay harness, so paper
+// bets settle exactly as they would in a backtest.
+func SettleBet(bet *models.Bet, result *models.RaceResult, runner *models.Runner, commissionRate float64) float64 {
 	if bet == nil || result == nil {
 		return 0
 	}
+	if result.IsVoid() {
+		voidBet(bet, result.Time)
+		return 0
+	}
+	if bet.IsBSP && !matchAtStartingPrice(bet, result, runner) {
+		voidBet(bet, result.Time)
+		return 0
+	}
+	if bet.EachWay {
+		return settleEachWay(bet, result, runner, commissionRate)
+	}
 	win := isRunnerWinner(runner, result)
-	pnl := calculatePnL(bet, win)
-	commission := 0.0
-	if pnl > 0 && commissionRate > 0 {
-		commission = pnl * commissionRate
-		pnl -= commission
+	var pnl, commission float64
+	if ties := result.DeadHeatCount(); win && ties > 1 {
+		pnl, commission = settleDeadHeat(bet, ties, commissionRate)
+	} else {
+		commission = calculateCommission(bet, win, commissionRate)
+		pnl = calculatePnL(bet, win) - commission
 	}
 
 	settledAt := result.Time
@@ -227,17 +423,141 @@ func (e *Engine) SettleBet(bet *models.Bet, result *models.RaceResult, runner *m
 	return pnl
 }
 
+// settleEachWay settles whichever legs of an each-way bet the result decides
+// and returns the P&L of the legs settled now. Each leg is settled once and
+// commissioned on its own result; ProfitLoss and Commission accumulate across
+// legs. The place leg stays open when the result has no position for a
+// losing runner, and the bet is only marked settled once both legs are, so it
+// can be settled again when positions arrive.
+func settleEachWay(bet *models.Bet, result *models.RaceResult, runner *models.Runner, commissionRate float64) float64 {
+	var pnl, commission float64
+	if bet.WinProfitLoss == nil {
+		legPnL, legCommission := settleLeg(bet, bet.SettlementPrice(), isRunnerWinner(runner, result), result.DeadHeatCount(), commissionRate)
+		bet.WinProfitLoss = &legPnL
+		pnl += legPnL
+		commission += legCommission
+	}
+	if bet.PlaceProfitLoss == nil && runner != nil {
+		if placed, known := result.IsPlaced(runner.ID, runner.TrapNumber, bet.PlacesPaid); known {
+			legPnL, legCommission := settleLeg(bet, bet.PlaceOdds(), placed, 1, commissionRate)
+			bet.PlaceProfitLoss = &legPnL
+			pnl += legPnL
+			commission += legCommission
+		}
+	}
+
+	total := pnl
+	if bet.ProfitLoss != nil {
+		total += *bet.ProfitLoss
+	}
+	totalCommission := commission
+	if bet.Commission != nil {
+		totalCommission += *bet.Commission
+	}
+	bet.ProfitLoss = &total
+	bet.Commission = &totalCommission
+	if bet.WinProfitLoss != nil && bet.PlaceProfitLoss != nil {
+		settledAt := result.Time
+		bet.Status = models.BetStatusSettled
+		bet.SettledAt = &settledAt
+	}
+	bet.UpdatedAt = time.Now().UTC()
+	return pnl
+}
+
+// settleLeg settles one leg of an each-way bet at the leg's price, splitting
+// a winning stake when the runner dead-heated with ties-1 others
+func settleLeg(bet *models.Bet, price float64, won bool, ties int, commissionRate float64) (float64, float64) {
+	leg := *bet
+	leg.EachWay = false
+	leg.Odds = price
+	leg.MatchedPrice = nil
+	if won && ties > 1 {
+		return settleDeadHeat(&leg, ties, commissionRate)
+	}
+	commission := calculateCommission(&leg, won, commissionRate)
+	return calculatePnL(&leg, won) - commission, commission
+}
+
+// matchAtStartingPrice fills a BSP bet at the runner's starting price from the
+// race result. It returns false when no SP was recorded for the runner.
+func matchAtStartingPrice(bet *models.Bet, result *models.RaceResult, runner *models.Runner) bool {
+	if runner == nil {
+		return false
+	}
+	sp, ok := result.StartingPrice(runner.ID, runner.TrapNumber)
+	if !ok {
+		return false
+	}
+	matchedAt := result.Time
+	stake := bet.Stake
+	bet.MatchedPrice = &sp
+	bet.MatchedSize = &stake
+	bet.MatchedAt = &matchedAt
+	bet.Status = models.BetStatusMatched
+	return true
+}
+
+// voidBet marks a bet that could not be matched as cancelled with zero PnL
+func voidBet(bet *models.Bet, at time.Time) {
+	zero := 0.0
+	bet.Status = models.BetStatusCancelled
+	bet.CancelledAt = &at
+	bet.ProfitLoss = &zero
+	bet.Commission = &zero
+	bet.UpdatedAt = time.Now().UTC()
+}
+
+// calculateCommission returns the exchange commission on a settled bet.
+// Commission is only charged on net winnings: the profit of a winning back
+// bet, or the backer's stake kept when a laid selection loses. The liability
+// paid out on a losing lay is not commissioned.
+func calculateCommission(bet *models.Bet, win bool, commissionRate float64) float64 {
+	if commissionRate <= 0 {
+		return 0
+	}
+	if bet.Side == models.BetSideLay {
+		if win {
+			return 0
+		}
+		return bet.Stake * commissionRate
+	}
+	if !win {
+		return 0
+	}
+	return (bet.SettlementPrice() - 1.0) * bet.Stake * commissionRate
+}
+
+// settleDeadHeat settles a bet on a runner that dead-heated for first with
+// ties-1 others. The stake is divided by the number tying: that share is
+// settled as a winner at full odds and the rest as a loser. Commission is
+// charged on the net result.
+func settleDeadHeat(bet *models.Bet, ties int, commissionRate float64) (float64, float64) {
+	winning := *bet
+	winning.Stake = bet.Stake / float64(ties)
+	losing := *bet
+	losing.Stake = bet.Stake - winning.Stake
+
+	pnl := calculatePnL(&winning, true) + calculatePnL(&losing, false)
+	commission := 0.0
+	if pnl > 0 && commissionRate > 0 {
+		commission = pnl * commissionRate
+	}
+	return pnl - commission, commission
+}
+
 func calculatePnL(bet *models.Bet, win bool) float64 {
+	price := bet.SettlementPrice()
 	if bet.Side == models.BetSideBack {
 		if win {
-			return (bet.Odds - 1.0) * bet.Stake
+			return (price - 1.0) * bet.Stake
 		}
 		return -bet.Stake
 	}
 
 	// Lay bet
 	if win {
-		return -(bet.Odds-1.0) * bet.Stake
+		return -(price-1.0) * bet.Stake
 	}
 	return bet.Stake
 }
@@ -246,19 +566,7 @@ func isRunnerWinner(runner *models.Runner, result *models.RaceResult) bool {
 	if result == nil || runner == nil {
 		return false
 	}
-	if result.WinnerTrap != nil {
-		return runner.TrapNumber == *result.WinnerTrap
-	}
-	positions, err := result.ParsePositions()
-	if err != nil {
-		return false
-	}
-	for _, entry := range positions.Runners {
-		if entry.RunnerID == runner.ID && entry.Position == 1 {
-			return true
-		}
-	}
-	return false
+	return result.IsWinner(runner.ID, runner.TrapNumber)
 }
 
 func applySlippage(odds float64, side models.BetSide, ticks int) float64 {
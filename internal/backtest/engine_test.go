@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/yourusername/clever-better/internal/models"
@@ -45,11 +46,13 @@ func (t testStrategy) CalculateStake(signal strategy.Signal, bankroll float64) f
 }
 func (t testStrategy) GetParameters() map[string]interface{} { return map[string]interface{}{} }
 
-type fakeRaceRepo struct{ races []*models.Race }
-
-type fakeRunnerRepo struct{ runners map[uuid.UUID][]*models.Runner }
-
-type fakeOddsRepo struct{ odds map[uuid.UUID][]*models.OddsSnapshot }
+// fakeRaceRepo serves races, bundling each with the runners and odds listed
+// for it
+type fakeRaceRepo struct {
+	races   []*models.Race
+	runners map[uuid.UUID][]*models.Runner
+	odds    map[uuid.UUID][]*models.OddsSnapshot
+}
 
 type fakeRaceResultRepo struct{ results map[uuid.UUID]*models.RaceResult }
 
@@ -59,31 +62,43 @@ func (r *fakeRaceRepo) GetUpcoming(ctx context.Context, limit int) ([]*models.Ra
 func (r *fakeRaceRepo) GetByDateRange(ctx context.Context, start, end time.Time) ([]*models.Race, error) {
 	return r.races, nil
 }
+func (r *fakeRaceRepo) StreamByDateRange(ctx context.Context, start, end time.Time, fn func(*models.Race) error) error {
+	for _, race := range r.races {
+		if err := fn(race); err != nil {
+			return err
+		}
+	}
+	return nil
+}
 func (r *fakeRaceRepo) GetByTrackAndDate(ctx context.Context, track string, date time.Time) ([]*models.Race, error) {
 	return nil, nil
 }
-func (r *fakeRaceRepo) Update(ctx context.Context, race *models.Race) error { return nil }
-func (r *fakeRaceRepo) Delete(ctx context.Context, id uuid.UUID) error { return nil }
-
-func (r *fakeRunnerRepo) Create(ctx context.Context, runner *models.Runner) error { return nil }
-func (r *fakeRunnerRepo) GetByID(ctx context.Context, id uuid.UUID) (*models.Runner, error) { return nil, nil }
-func (r *fakeRunnerRepo) GetByRaceID(ctx context.Context, raceID uuid.UUID) ([]*models.Runner, error) {
-	return r.runners[raceID], nil
-}
-func (r *fakeRunnerRepo) Update(ctx context.Context, runner *models.Runner) error { return nil }
-func (r *fakeRunnerRepo) Delete(ctx context.Context, id uuid.UUID) error { return nil }
-
-func (o *fakeOddsRepo) Insert(ctx context.Context, odds *models.OddsSnapshot) error { return nil }
-func (o *fakeOddsRepo) InsertBatch(ctx context.Context, odds []*models.OddsSnapshot) error { return nil }
-func (o *fakeOddsRepo) GetByRaceID(ctx context.Context, raceID uuid.UUID, start, end time.Time) ([]*models.OddsSnapshot, error) {
-	return o.odds[raceID], nil
-}
-func (o *fakeOddsRepo) GetLatest(ctx context.Context, raceID, runnerID uuid.UUID) (*models.OddsSnapshot, error) {
-	return nil, nil
+func (r *fakeRaceRepo) GetRaceBundle(ctx context.Context, raceID uuid.UUID, oddsSince time.Time) (*models.RaceBundle, error) {
+	for _, race := range r.races {
+		if race.ID != raceID {
+			continue
+		}
+		bundle := &models.RaceBundle{Race: race, Runners: r.runners[raceID]}
+		for _, snapshot := range r.odds[raceID] {
+			if !snapshot.Time.Before(oddsSince) && !snapshot.Time.After(race.ScheduledStart) {
+				bundle.Odds = append(bundle.Odds, snapshot)
+			}
+		}
+		return bundle, nil
+	}
+	return nil, models.ErrNotFound
 }
-func (o *fakeOddsRepo) GetTimeSeriesForRunner(ctx context.Context, runnerID uuid.UUID, start, end time.Time) ([]*models.OddsSnapshot, error) {
-	return nil, nil
+func (r *fakeRaceRepo) GetRaceBundles(ctx context.Context, raceIDs []uuid.UUID, oddsSince time.Time) ([]*models.RaceBundle, error) {
+	bundles := make([]*models.RaceBundle, 0, len(raceIDs))
+	for _, raceID := range raceIDs {
+		if bundle, err := r.GetRaceBundle(ctx, raceID, oddsSince); err == nil {
+			bundles = append(bundles, bundle)
+		}
+	}
+	return bundles, nil
 }
+func (r *fakeRaceRepo) Update(ctx context.Context, race *models.Race) error { return nil }
+func (r *fakeRaceRepo) Delete(ctx context.Context, id uuid.UUID) error { return nil }
 
 func (r *fakeRaceResultRepo) Insert(ctx context.Context, result *models.RaceResult) error { return nil }
 func (r *fakeRaceResultRepo) InsertBatch(ctx context.Context, results []*models.RaceResult) error { return nil }
@@ -129,9 +144,7 @@ func TestHistoricalReplay(t *testing.T) {
 	engine := &Engine{
 		config: BacktestConfig{InitialBankroll: 100, CommissionRate: 0.05},
 		repositories: &repository.Repositories{
-			Race:       &fakeRaceRepo{races: []*models.Race{race}},
-			Runner:     &fakeRunnerRepo{runners: map[uuid.UUID][]*models.Runner{raceID: []*models.Runner{runner}}},
-			Odds:       &fakeOddsRepo{odds: map[uuid.UUID][]*models.OddsSnapshot{raceID: []*models.OddsSnapshot{odds}}},
+			Race:       &fakeRaceRepo{races: []*models.Race{race}, runners: map[uuid.UUID][]*models.Runner{raceID: []*models.Runner{runner}}, odds: map[uuid.UUID][]*models.OddsSnapshot{raceID: []*models.OddsSnapshot{odds}}},
 			RaceResult: &fakeRaceResultRepo{results: map[uuid.UUID]*models.RaceResult{raceID: result}},
 		},
 		strategy: testStrategy{},
@@ -144,6 +157,200 @@ func TestHistoricalReplay(t *testing.T) {
 	assert.Equal(t, len(state.Bets), 1, "expected exactly one bet")
 }
 
+// TestHistoricalReplayUsesEntryTickOffset tests that backtest fills use the
+// offset price the strategy posts
+func TestHistoricalReplayUsesEntryTickOffset(t *testing.T) {
+	raceID := uuid.New()
+	runnerID := uuid.New()
+	start := time.Now().Add(-48 * time.Hour)
+	end := time.Now().Add(-24 * time.Hour)
+
+	race := &models.Race{ID: raceID, ScheduledStart: end}
+	runner := &models.Runner{ID: runnerID, RaceID: raceID, TrapNumber: 1, Name: "Runner", FormRating: floatPtr(25)}
+	odds := &models.OddsSnapshot{RaceID: raceID, RunnerID: runnerID, Time: start, BackPrice: floatPtr(2.98), BackSize: floatPtr(100), LayPrice: floatPtr(3.02), LaySize: floatPtr(100)}
+	winner := 1
+	result := &models.RaceResult{RaceID: raceID, Time: end, WinnerTrap: &winner}
+
+	simpleValue := strategy.NewSimpleValueStrategy()
+	simpleValue.EntryTickOffset = 2
+	engine := &Engine{
+		config: BacktestConfig{InitialBankroll: 100, CommissionRate: 0.05},
+		repositories: &repository.Repositories{
+			Race:       &fakeRaceRepo{races: []*models.Race{race}, runners: map[uuid.UUID][]*models.Runner{raceID: {runner}}, odds: map[uuid.UUID][]*models.OddsSnapshot{raceID: {odds}}},
+			RaceResult: &fakeRaceResultRepo{results: map[uuid.UUID]*models.RaceResult{raceID: result}},
+		},
+		strategy: simpleValue,
+	}
+
+	state, err := engine.HistoricalReplay(context.Background(), start, end)
+	require.NoError(t, err)
+	require.Len(t, state.Bets, 1)
+	assert.Equal(t, 2.96, state.Bets[0].Odds, "back posted two ticks below the 3.0 mid price")
+}
+
+// quoteTakingStrategy backs each runner at the last back price it was shown
+type quoteTakingStrategy struct{ testStrategy }
+
+func (q quoteTakingStrategy) Evaluate(ctx context.Context, strategyCtx strategy.Context) ([]strategy.Signal, error) {
+	var signals []strategy.Signal
+	for _, snapshot := range strategyCtx.OddsHistory {
+		signals = append(signals, strategy.Signal{RunnerID: snapshot.RunnerID, Side: models.BetSideBack, Odds: *snapshot.BackPrice, Stake: 10})
+	}
+	return signals, nil
+}
+
+// TestHistoricalReplayPrefersOddsSource tests that when two sources record
+// odds at the same time, the strategy prices off the preferred one
+func TestHistoricalReplayPrefersOddsSource(t *testing.T) {
+	raceID := uuid.New()
+	runnerID := uuid.New()
+	start := time.Now().Add(-48 * time.Hour)
+	end := time.Now().Add(-24 * time.Hour)
+
+	race := &models.Race{ID: raceID, ScheduledStart: end}
+	runner := &models.Runner{ID: runnerID, RaceID: raceID, TrapNumber: 1, Name: "Runner"}
+	odds := []*models.OddsSnapshot{
+		{RaceID: raceID, RunnerID: runnerID, Time: start, Source: "racing_post", BackPrice: floatPtr(3.95), BackSize: floatPtr(100), LayPrice: floatPtr(4.05), LaySize: floatPtr(100)},
+		{RaceID: raceID, RunnerID: runnerID, Time: start, Source: models.OddsSourceBetfair, BackPrice: floatPtr(2.98), BackSize: floatPtr(100), LayPrice: floatPtr(3.02), LaySize: floatPtr(100)},
+	}
+	winner := 1
+	result := &models.RaceResult{RaceID: raceID, Time: end, WinnerTrap: &winner}
+
+	replay := func(preference models.OddsSourcePreference) *BacktestState {
+		t.Helper()
+		engine := &Engine{
+			config: BacktestConfig{InitialBankroll: 100, CommissionRate: 0.05, OddsSourcePreference: preference},
+			repositories: &repository.Repositories{
+				Race:       &fakeRaceRepo{races: []*models.Race{race}, runners: map[uuid.UUID][]*models.Runner{raceID: {runner}}, odds: map[uuid.UUID][]*models.OddsSnapshot{raceID: odds}},
+				RaceResult: &fakeRaceResultRepo{results: map[uuid.UUID]*models.RaceResult{raceID: result}},
+			},
+			strategy: quoteTakingStrategy{},
+		}
+		state, err := engine.HistoricalReplay(context.Background(), start, end)
+		require.NoError(t, err)
+		require.Len(t, state.Bets, 1)
+		return state
+	}
+
+	assert.Equal(t, 2.98, replay(models.OddsSourcePreference{"betfair", "racing_post"}).Bets[0].Odds)
+	assert.Equal(t, 3.95, replay(models.OddsSourcePreference{"racing_post", "betfair"}).Bets[0].Odds, "falls back in preference order")
+}
+
+// cancellingStrategy cancels the run once it has evaluated a race
+type cancellingStrategy struct {
+	testStrategy
+	cancel context.CancelFunc
+}
+
+func (c cancellingStrategy) Evaluate(ctx context.Context, strategyCtx strategy.Context) ([]strategy.Signal, error) {
+	defer c.cancel()
+	return c.testStrategy.Evaluate(ctx, strategyCtx)
+}
+
+// TestRunStopsOnCancellation tests that a cancelled run returns partial state
+func TestRunStopsOnCancellation(t *testing.T) {
+	start := time.Now().Add(-48 * time.Hour)
+	end := time.Now().Add(-24 * time.Hour)
+
+	races := make([]*models.Race, 0, 5)
+	runners := map[uuid.UUID][]*models.Runner{}
+	odds := map[uuid.UUID][]*models.OddsSnapshot{}
+	results := map[uuid.UUID]*models.RaceResult{}
+	winner := 1
+	for i := 0; i < 5; i++ {
+		raceID := uuid.New()
+		runnerID := uuid.New()
+		races = append(races, &models.Race{ID: raceID, ScheduledStart: end})
+		runners[raceID] = []*models.Runner{{ID: runnerID, RaceID: raceID, TrapNumber: 1, Name: "Runner"}}
+		odds[raceID] = []*models.OddsSnapshot{{RaceID: raceID, RunnerID: runnerID, Time: start, BackPrice: floatPtr(3.0), LayPrice: floatPtr(3.2)}}
+		results[raceID] = &models.RaceResult{RaceID: raceID, Time: end, WinnerTrap: &winner}
+	}
+
+	newEngine := func(strat strategy.Strategy) *Engine {
+		return &Engine{
+			config: BacktestConfig{InitialBankroll: 100, CommissionRate: 0.05},
+			repositories: &repository.Repositories{
+				Race:       &fakeRaceRepo{races: races, runners: runners, odds: odds},
+				RaceResult: &fakeRaceResultRepo{results: results},
+			},
+			strategy: strat,
+			logger:   logrus.New(),
+		}
+	}
+
+	fullState, _, err := newEngine(testStrategy{}).Run(context.Background(), start, end)
+	require.NoError(t, err)
+	require.Len(t, fullState.Bets, 5)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	began := time.Now()
+	state, metrics, err := newEngine(cancellingStrategy{cancel: cancel}).Run(ctx, start, end)
+	require.ErrorIs(t, err, ErrCancelled)
+	assert.Less(t, time.Since(began), time.Second)
+	require.NotNil(t, state, "partial state is returned")
+	assert.Len(t, state.Bets, 1, "only the first race is settled")
+	assert.Equal(t, 1, metrics.TotalBets)
+}
+
+// TestStreamingReplayMatchesHistoricalReplay tests both replay paths settle the same bets
+func TestStreamingReplayMatchesHistoricalReplay(t *testing.T) {
+	start := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	end := start.Add(24 * time.Hour)
+
+	races := make([]*models.Race, 0, 6)
+	runners := map[uuid.UUID][]*models.Runner{}
+	odds := map[uuid.UUID][]*models.OddsSnapshot{}
+	results := map[uuid.UUID]*models.RaceResult{}
+	for i := 0; i < 6; i++ {
+		raceID := uuid.New()
+		runnerID := uuid.New()
+		off := start.Add(time.Duration(i+1) * time.Hour)
+		winner := 1 + i%2
+		races = append(races, &models.Race{ID: raceID, ScheduledStart: off})
+		runners[raceID] = []*models.Runner{{ID: runnerID, RaceID: raceID, TrapNumber: 1, Name: "Runner"}}
+		odds[raceID] = []*models.OddsSnapshot{{RaceID: raceID, RunnerID: runnerID, Time: off.Add(-time.Minute), BackPrice: floatPtr(3.0), LayPrice: floatPtr(3.2)}}
+		results[raceID] = &models.RaceResult{RaceID: raceID, Time: off, WinnerTrap: &winner}
+	}
+
+	run := func(streaming bool) (*BacktestState, Metrics) {
+		engine := &Engine{
+			config: BacktestConfig{InitialBankroll: 100, CommissionRate: 0.05, Streaming: streaming},
+			repositories: &repository.Repositories{
+				Race:       &fakeRaceRepo{races: races, runners: runners, odds: odds},
+				RaceResult: &fakeRaceResultRepo{results: results},
+			},
+			strategy: testStrategy{},
+			logger:   logrus.New(),
+		}
+		state, metrics, err := engine.Run(context.Background(), start, end)
+		require.NoError(t, err)
+		return state, metrics
+	}
+
+	inMemory, inMemoryMetrics := run(false)
+	streamed, streamedMetrics := run(true)
+	require.Len(t, streamed.Bets, 6)
+	assert.Equal(t, inMemoryMetrics, streamedMetrics)
+	assert.Equal(t, inMemory.CurrentBankroll, streamed.CurrentBankroll)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	engine := &Engine{
+		config: BacktestConfig{InitialBankroll: 100, CommissionRate: 0.05, Streaming: true},
+		repositories: &repository.Repositories{
+			Race:       &fakeRaceRepo{races: races, runners: runners, odds: odds},
+			RaceResult: &fakeRaceResultRepo{results: results},
+		},
+		strategy: cancellingStrategy{cancel: cancel},
+		logger:   logrus.New(),
+	}
+	state, _, err := engine.Run(ctx, start, end)
+	require.ErrorIs(t, err, ErrCancelled)
+	assert.Len(t, state.Bets, 1, "streaming stops between races when cancelled")
+}
+
 // TestBankrollEdgeCases tests bankroll edge cases
 func TestBankrollEdgeCases(t *testing.T) {
 	tests := []struct {
@@ -206,9 +413,7 @@ func TestBankrollEdgeCases(t *testing.T) {
 			engine := &Engine{
 				config: BacktestConfig{InitialBankroll: tt.initialBankroll, CommissionRate: 0.0},
 				repositories: &repository.Repositories{
-					Race:       &fakeRaceRepo{races: []*models.Race{race}},
-					Runner:     &fakeRunnerRepo{runners: map[uuid.UUID][]*models.Runner{raceID: []*models.Runner{runner}}},
-					Odds:       &fakeOddsRepo{odds: map[uuid.UUID][]*models.OddsSnapshot{raceID: []*models.OddsSnapshot{odds}}},
+					Race:       &fakeRaceRepo{races: []*models.Race{race}, runners: map[uuid.UUID][]*models.Runner{raceID: []*models.Runner{runner}}, odds: map[uuid.UUID][]*models.OddsSnapshot{raceID: []*models.OddsSnapshot{odds}}},
 					RaceResult: &fakeRaceResultRepo{results: map[uuid.UUID]*models.RaceResult{raceID: result}},
 				},
 				strategy: testStrategy{
@@ -288,9 +493,7 @@ func TestSlippageApplication(t *testing.T) {
 					CommissionRate:  0.0,
 				},
 				repositories: &repository.Repositories{
-					Race:       &fakeRaceRepo{races: []*models.Race{race}},
-					Runner:     &fakeRunnerRepo{runners: map[uuid.UUID][]*models.Runner{raceID: []*models.Runner{runner}}},
-					Odds:       &fakeOddsRepo{odds: map[uuid.UUID][]*models.OddsSnapshot{raceID: []*models.OddsSnapshot{}}},
+					Race:       &fakeRaceRepo{races: []*models.Race{race}, runners: map[uuid.UUID][]*models.Runner{raceID: []*models.Runner{runner}}, odds: map[uuid.UUID][]*models.OddsSnapshot{raceID: []*models.OddsSnapshot{}}},
 					RaceResult: &fakeRaceResultRepo{results: map[uuid.UUID]*models.RaceResult{raceID: result}},
 				},
 				strategy: testStrategy{
@@ -314,6 +517,97 @@ func TestSlippageApplication(t *testing.T) {
 	}
 }
 
+// TestSlippageByMarket tests that each bet uses the slippage for its race's market
+func TestSlippageByMarket(t *testing.T) {
+	start := time.Now().Add(-48 * time.Hour)
+	end := time.Now().Add(-24 * time.Hour)
+	winner := 1
+
+	thinRace := &models.Race{ID: uuid.New(), ScheduledStart: end.Add(-2 * time.Hour), Track: "Towcester", RaceType: "A7"}
+	liquidRace := &models.Race{ID: uuid.New(), ScheduledStart: end.Add(-time.Hour), Track: "Romford", RaceType: "A1"}
+
+	runners := make(map[uuid.UUID][]*models.Runner)
+	results := make(map[uuid.UUID]*models.RaceResult)
+	var signals []strategy.Signal
+	for _, race := range []*models.Race{thinRace, liquidRace} {
+		runnerID := uuid.New()
+		runners[race.ID] = []*models.Runner{{ID: runnerID, RaceID: race.ID, TrapNumber: 1, Name: "Runner"}}
+		results[race.ID] = &models.RaceResult{RaceID: race.ID, Time: end, WinnerTrap: &winner}
+		signals = append(signals, strategy.Signal{RunnerID: runnerID, Side: models.BetSideLay, Odds: 4.0, Stake: 10, Confidence: 0.8})
+	}
+
+	engine := &Engine{
+		config: BacktestConfig{
+			InitialBankroll:  1000.0,
+			SlippageTicks:    1,
+			SlippageByMarket: map[string]int{"towcester": 5, "A1": 0},
+		},
+		repositories: &repository.Repositories{
+			Race:       &fakeRaceRepo{races: []*models.Race{thinRace, liquidRace}, runners: runners, odds: map[uuid.UUID][]*models.OddsSnapshot{}},
+			RaceResult: &fakeRaceResultRepo{results: results},
+		},
+		strategy: testStrategy{returnSignals: signals},
+	}
+
+	state, err := engine.HistoricalReplay(context.Background(), start, end)
+	require.NoError(t, err)
+
+	odds := make(map[uuid.UUID]float64)
+	for _, bet := range state.Bets {
+		if bet.RaceID == thinRace.ID && bet.RunnerID == signals[0].RunnerID {
+			odds[thinRace.ID] = bet.Odds
+		}
+		if bet.RaceID == liquidRace.ID && bet.RunnerID == signals[1].RunnerID {
+			odds[liquidRace.ID] = bet.Odds
+		}
+	}
+	require.Len(t, odds, 2)
+	assert.InDelta(t, 3.95, odds[thinRace.ID], 1e-9, "thin market should use its track's higher slippage")
+	assert.InDelta(t, 4.0, odds[liquidRace.ID], 1e-9, "liquid market should use its race type's lower slippage")
+
+	assert.Equal(t, 1, engine.config.SlippageFor(&models.Race{Track: "Hove", RaceType: "A3"}))
+	assert.Equal(t, 1, engine.config.SlippageFor(nil))
+}
+
+// TestMarketFilterExcludesRaces tests that filtered races are not traded
+func TestMarketFilterExcludesRaces(t *testing.T) {
+	start := time.Now().Add(-48 * time.Hour)
+	end := time.Now().Add(-24 * time.Hour)
+	winner := 1
+
+	banned := &models.Race{ID: uuid.New(), ScheduledStart: end.Add(-2 * time.Hour), Track: "Towcester", Grade: "A3"}
+	allowed := &models.Race{ID: uuid.New(), ScheduledStart: end.Add(-time.Hour), Track: "Romford", Grade: "A3"}
+
+	runners := make(map[uuid.UUID][]*models.Runner)
+	results := make(map[uuid.UUID]*models.RaceResult)
+	var signals []strategy.Signal
+	for _, race := range []*models.Race{banned, allowed} {
+		runnerID := uuid.New()
+		runners[race.ID] = []*models.Runner{{ID: runnerID, RaceID: race.ID, TrapNumber: 1, Name: "Runner"}}
+		results[race.ID] = &models.RaceResult{RaceID: race.ID, Time: end, WinnerTrap: &winner}
+		signals = append(signals, strategy.Signal{RunnerID: runnerID, Side: models.BetSideBack, Odds: 3.0, Stake: 10, Confidence: 0.8})
+	}
+
+	engine := &Engine{
+		config: BacktestConfig{
+			InitialBankroll: 1000.0,
+			MarketFilter:    strategy.NewMarketFilter(strategy.MarketRules{}, strategy.MarketRules{Venues: []string{"tow*"}}),
+		},
+		repositories: &repository.Repositories{
+			Race:       &fakeRaceRepo{races: []*models.Race{banned, allowed}, runners: runners, odds: map[uuid.UUID][]*models.OddsSnapshot{}},
+			RaceResult: &fakeRaceResultRepo{results: results},
+		},
+		strategy: testStrategy{returnSignals: signals},
+	}
+
+	state, err := engine.HistoricalReplay(context.Background(), start, end)
+	require.NoError(t, err)
+	require.NotEmpty(t, state.Bets)
+	for _, bet := range state.Bets {
+		assert.Equal(t, allowed.ID, bet.RaceID, "no bets on the blacklisted venue")
+	}
+}
+
 // TestCommissionDeduction tests commission calculation and deduction
 func TestCommissionDeduction(t *testing.T) {
 	tests := []struct {
@@ -368,9 +662,7 @@ func TestCommissionDeduction(t *testing.T) {
 					CommissionRate:  tt.commissionRate,
 				},
 				repositories: &repository.Repositories{
-					Race:       &fakeRaceRepo{races: []*models.Race{race}},
-					Runner:     &fakeRunnerRepo{runners: map[uuid.UUID][]*models.Runner{raceID: []*models.Runner{runner}}},
-					Odds:       &fakeOddsRepo{odds: map[uuid.UUID][]*models.OddsSnapshot{raceID: []*models.OddsSnapshot{odds}}},
+					Race:       &fakeRaceRepo{races: []*models.Race{race}, runners: map[uuid.UUID][]*models.Runner{raceID: []*models.Runner{runner}}, odds: map[uuid.UUID][]*models.OddsSnapshot{raceID: []*models.OddsSnapshot{odds}}},
 					RaceResult: &fakeRaceResultRepo{results: map[uuid.UUID]*models.RaceResult{raceID: result}},
 				},
 				strategy: testStrategy{},
@@ -393,6 +685,196 @@ func TestCommissionDeduction(t *testing.T) {
 	}
 }
 
+// TestCommissionPromoWindow tests that bets settled inside a promo window pay
+// the promo rate and those outside pay the normal rate
+func TestCommissionPromoWindow(t *testing.T) {
+	start := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	promo := CommissionPromo{Start: start.Add(24 * time.Hour), End: start.Add(48 * time.Hour), Rate: 0}
+
+	races := []*models.Race{}
+	runners := map[uuid.UUID][]*models.Runner{}
+	odds := map[uuid.UUID][]*models.OddsSnapshot{}
+	results := map[uuid.UUID]*models.RaceResult{}
+	// One winning race before, one inside and one after the promo window
+	for day := 0; day < 3; day++ {
+		raceID := uuid.New()
+		raceTime := start.Add(time.Duration(day)*24*time.Hour + time.Hour)
+		runner := &models.Runner{ID: uuid.New(), RaceID: raceID, TrapNumber: 1, Name: "Winner"}
+		races = append(races, &models.Race{ID: raceID, ScheduledStart: raceTime})
+		runners[raceID] = []*models.Runner{runner}
+		odds[raceID] = []*models.OddsSnapshot{{RaceID: raceID, RunnerID: runner.ID, Time: raceTime.Add(-time.Minute), BackPrice: floatPtr(3.0)}}
+		results[raceID] = &models.RaceResult{RaceID: raceID, Time: raceTime, WinnerTrap: intPtr(1)}
+	}
+
+	engine := &Engine{
+		config: BacktestConfig{
+			InitialBankroll:  1000.0,
+			CommissionRate:   0.05,
+			CommissionPromos: []CommissionPromo{promo},
+		},
+		repositories: &repository.Repositories{
+			Race:       &fakeRaceRepo{races: races, runners: runners, odds: odds},
+			RaceResult: &fakeRaceResultRepo{results: results},
+		},
+		strategy: testStrategy{},
+	}
+
+	state, err := engine.HistoricalReplay(context.Background(), start, start.Add(72*time.Hour))
+	require.NoError(t, err)
+	require.Len(t, state.Bets, 3)
+
+	// Stake 10 at 3.0 wins 20 gross profit
+	expected := []float64{1.0, 0, 1.0}
+	for i, bet := range state.Bets {
+		require.NotNil(t, bet.Commission)
+		assert.InDelta(t, expected[i], *bet.Commission, 0.0001, "bet %d settled at %s", i, bet.SettledAt)
+		assert.InDelta(t, 20.0-expected[i], *bet.ProfitLoss, 0.0001)
+	}
+
+	assert.Equal(t, 0.05, engine.config.CommissionRateAt(promo.Start.Add(-time.Nanosecond)))
+	assert.Equal(t, 0.0, engine.config.CommissionRateAt(promo.Start))
+	assert.Equal(t, 0.05, engine.config.CommissionRateAt(promo.End))
+}
+
+// TestLayCommissionOnWinningLay tests that a winning lay pays commission on the
+// backer's stake it keeps, not on the liability
+func TestLayCommissionOnWinningLay(t *testing.T) {
+	raceID := uuid.New()
+	runner := &models.Runner{ID: uuid.New(), RaceID: raceID, TrapNumber: 1, Name: "Laid"}
+	result := &models.RaceResult{RaceID: raceID, Time: time.Now(), WinnerTrap: intPtr(2)}
+	bet := &models.Bet{ID: uuid.New(), RaceID: raceID, RunnerID: runner.ID, Side: models.BetSideLay, Odds: 5.0, Stake: 20.0}
+
+	pnl := SettleBet(bet, result, runner, 0.05)
+
+	require.NotNil(t, bet.Commission)
+	assert.InDelta(t, 1.0, *bet.Commission, 0.0001, "commission is stake * rate")
+	assert.InDelta(t, 19.0, pnl, 0.0001)
+	assert.InDelta(t, 19.0, *bet.ProfitLoss, 0.0001)
+	assert.Equal(t, models.BetStatusSettled, bet.Status)
+}
+
+// TestLayCommissionOnLosingLay tests that a losing lay loses its liability and
+// pays no commission
+func TestLayCommissionOnLosingLay(t *testing.T) {
+	raceID := uuid.New()
+	runner := &models.Runner{ID: uuid.New(), RaceID: raceID, TrapNumber: 1, Name: "Laid"}
+	result := &models.RaceResult{RaceID: raceID, Time: time.Now(), WinnerTrap: intPtr(1)}
+	bet := &models.Bet{ID: uuid.New(), RaceID: raceID, RunnerID: runner.ID, Side: models.BetSideLay, Odds: 5.0, Stake: 20.0}
+
+	pnl := SettleBet(bet, result, runner, 0.05)
+
+	require.NotNil(t, bet.Commission)
+	assert.Zero(t, *bet.Commission)
+	assert.InDelta(t, -80.0, pnl, 0.0001, "loss is the liability (odds-1) * stake")
+}
+
+// TestVoidRaceRefundsBets tests that bets on a voided race settle with zero P&L
+// and leave the bankroll unchanged
+func TestVoidRaceRefundsBets(t *testing.T) {
+	raceID := uuid.New()
+	runnerID := uuid.New()
+	start := time.Now().Add(-48 * time.Hour)
+	end := time.Now().Add(-24 * time.Hour)
+
+	race := &models.Race{ID: raceID, ScheduledStart: end}
+	runner := &models.Runner{ID: runnerID, RaceID: raceID, TrapNumber: 1, Name: "Runner"}
+	odds := &models.OddsSnapshot{RaceID: raceID, RunnerID: runnerID, Time: start, BackPrice: floatPtr(3.0)}
+	result := &models.RaceResult{RaceID: raceID, Time: end, WinnerTrap: intPtr(1), Status: models.RaceResultStatusVoid}
+
+	engine := &Engine{
+		config: BacktestConfig{InitialBankroll: 1000.0, CommissionRate: 0.05},
+		repositories: &repository.Repositories{
+			Race:       &fakeRaceRepo{races: []*models.Race{race}, runners: map[uuid.UUID][]*models.Runner{raceID: []*models.Runner{runner}}, odds: map[uuid.UUID][]*models.OddsSnapshot{raceID: []*models.OddsSnapshot{odds}}},
+			RaceResult: &fakeRaceResultRepo{results: map[uuid.UUID]*models.RaceResult{raceID: result}},
+		},
+		strategy: testStrategy{},
+	}
+
+	state, err := engine.HistoricalReplay(context.Background(), start, end)
+	require.NoError(t, err)
+	require.Len(t, state.Bets, 1)
+
+	bet := state.Bets[0]
+	assert.Equal(t, models.BetStatusCancelled, bet.Status)
+	require.NotNil(t, bet.ProfitLoss)
+	assert.Zero(t, *bet.ProfitLoss)
+	assert.Zero(t, *bet.Commission)
+	assert.Equal(t, 1000.0, state.CurrentBankroll)
+}
+
+// TestDeadHeatSettlesHalfStake tests that a two-way dead heat settles half the
+// stake as a winner and half as a loser, for both tied runners
+func TestDeadHeatSettlesHalfStake(t *testing.T) {
+	raceID := uuid.New()
+	first := &models.Runner{ID: uuid.New(), RaceID: raceID, TrapNumber: 1, Name: "First"}
+	second := &models.Runner{ID: uuid.New(), RaceID: raceID, TrapNumber: 4, Name: "Second"}
+	positions := []byte(`{"runners":[` +
+		`{"runner_id":"` + first.ID.String() + `","trap_number":1,"position":1,"sp":"4.0","place_payout":"0"},` +
+		`{"runner_id":"` + second.ID.String() + `","trap_number":4,"position":1,"sp":"5.0","place_payout":"0"},` +
+		`{"runner_id":"` + uuid.New().String() + `","trap_number":2,"position":3,"sp":"3.0","place_payout":"0"}]}`)
+	result := &models.RaceResult{RaceID: raceID, Time: time.Now(), WinnerTrap: intPtr(1), Positions: positions, Status: models.RaceResultStatusCompleted}
+
+	// 5 wins at 4.0 (+15), 5 loses (-5)
+	backFirst := &models.Bet{ID: uuid.New(), RunnerID: first.ID, Side: models.BetSideBack, Odds: 4.0, Stake: 10.0}
+	assert.InDelta(t, 10.0, SettleBet(backFirst, result, first, 0), 0.0001)
+	assert.Equal(t, models.BetStatusSettled, backFirst.Status)
+
+	// The runner not on the winner trap still dead-heated
+	backSecond := &models.Bet{ID: uuid.New(), RunnerID: second.ID, Side: models.BetSideBack, Odds: 5.0, Stake: 10.0}
+	assert.InDelta(t, 15.0-0.75, SettleBet(backSecond, result, second, 0.05), 0.0001)
+	assert.InDelta(t, 0.75, *backSecond.Commission, 0.0001)
+
+	// Laying a dead-heat winner loses half the liability and keeps half the stake
+	layFirst := &models.Bet{ID: uuid.New(), RunnerID: first.ID, Side: models.BetSideLay, Odds: 4.0, Stake: 10.0}
+	assert.InDelta(t, -10.0, SettleBet(layFirst, result, first, 0.05), 0.0001)
+	assert.Zero(t, *layFirst.Commission)
+}
+
+func TestEachWaySettlesLegsIndependently(t *testing.T) {
+	raceID := uuid.New()
+	runner := &models.Runner{ID: uuid.New(), RaceID: raceID, TrapNumber: 3, Name: "Second"}
+	positions := []byte(`{"runners":[` +
+		`{"runner_id":"` + uuid.New().String() + `","trap_number":1,"position":1,"sp":"2.0","place_payout":"0"},` +
+		`{"runner_id":"` + runner.ID.String() + `","trap_number":3,"position":2,"sp":"9.0","place_payout":"0"}]}`)
+	result := &models.RaceResult{RaceID: raceID, Time: time.Now(), WinnerTrap: intPtr(1), Positions: positions, Status: models.RaceResultStatusCompleted}
+
+	// Win leg loses 10; place leg at a quarter the odds (3.0) wins 20 less 5%
+	bet := &models.Bet{ID: uuid.New(), RunnerID: runner.ID, Side: models.BetSideBack, Odds: 9.0, Stake: 10.0,
+		EachWay: true, PlaceFraction: 0.25, PlacesPaid: 3, Status: models.BetStatusMatched}
+	assert.InDelta(t, 9.0, SettleBet(bet, result, runner, 0.05), 0.0001)
+	assert.Equal(t, models.BetStatusSettled, bet.Status)
+	require.NotNil(t, bet.WinProfitLoss)
+	require.NotNil(t, bet.PlaceProfitLoss)
+	assert.InDelta(t, -10.0, *bet.WinProfitLoss, 0.0001)
+	assert.InDelta(t, 19.0, *bet.PlaceProfitLoss, 0.0001)
+	assert.InDelta(t, 9.0, *bet.ProfitLoss, 0.0001)
+	assert.InDelta(t, 1.0, *bet.Commission, 0.0001)
+	assert.Equal(t, 20.0, bet.TotalStake())
+	assert.InDelta(t, 45.0, bet.GetROI(), 0.0001, "ROI is measured on both legs' stake")
+}
+
+func TestEachWayPlaceLegWaitsForPositions(t *testing.T) {
+	raceID := uuid.New()
+	runner := &models.Runner{ID: uuid.New(), RaceID: raceID, TrapNumber: 3, Name: "Second"}
+	bet := &models.Bet{ID: uuid.New(), RunnerID: runner.ID, Side: models.BetSideBack, Odds: 9.0, Stake: 10.0,
+		EachWay: true, PlaceFraction: 0.25, PlacesPaid: 3, Status: models.BetStatusMatched}
+
+	// Only the winner is known, so only the win leg settles
+	winnerOnly := &models.RaceResult{RaceID: raceID, Time: time.Now(), WinnerTrap: intPtr(1), Status: models.RaceResultStatusCompleted}
+	assert.InDelta(t, -10.0, SettleBet(bet, winnerOnly, runner, 0), 0.0001)
+	assert.Equal(t, models.BetStatusMatched, bet.Status)
+	assert.Nil(t, bet.PlaceProfitLoss)
+	assert.Nil(t, bet.SettledAt)
+
+	// Positions arriving later settle the place leg without resettling the win leg
+	positions := []byte(`{"runners":[{"runner_id":"` + runner.ID.String() + `","trap_number":3,"position":3,"sp":"9.0","place_payout":"0"}]}`)
+	full := &models.RaceResult{RaceID: raceID, Time: time.Now(), WinnerTrap: intPtr(1), Positions: positions, Status: models.RaceResultStatusCompleted}
+	assert.InDelta(t, 20.0, SettleBet(bet, full, runner, 0), 0.0001)
+	assert.Equal(t, models.BetStatusSettled, bet.Status)
+	assert.InDelta(t, 10.0, *bet.ProfitLoss, 0.0001)
+	assert.InDelta(t, -10.0, *bet.WinProfitLoss, 0.0001)
+}
+
 // TestConcurrentProcessing tests that engine handles concurrent race processing
 func TestConcurrentProcessing(t *testing.T) {
 	// Create multiple races
@@ -426,9 +908,7 @@ func TestConcurrentProcessing(t *testing.T) {
 	engine := &Engine{
 		config: BacktestConfig{InitialBankroll: 10000.0, CommissionRate: 0.05},
 		repositories: &repository.Repositories{
-			Race:       &fakeRaceRepo{races: races},
-			Runner:     &fakeRunnerRepo{runners: runners},
-			Odds:       &fakeOddsRepo{odds: odds},
+			Race:       &fakeRaceRepo{races: races, runners: runners, odds: odds},
 			RaceResult: &fakeRaceResultRepo{results: results},
 		},
 		strategy: testStrategy{},
@@ -457,9 +937,7 @@ func TestStateRecovery(t *testing.T) {
 	engine := &Engine{
 		config: BacktestConfig{InitialBankroll: initialBankroll, CommissionRate: 0.0},
 		repositories: &repository.Repositories{
-			Race:       &fakeRaceRepo{races: []*models.Race{race}},
-			Runner:     &fakeRunnerRepo{runners: map[uuid.UUID][]*models.Runner{raceID: []*models.Runner{runner}}},
-			Odds:       &fakeOddsRepo{odds: map[uuid.UUID][]*models.OddsSnapshot{raceID: []*models.OddsSnapshot{odds}}},
+			Race:       &fakeRaceRepo{races: []*models.Race{race}, runners: map[uuid.UUID][]*models.Runner{raceID: []*models.Runner{runner}}, odds: map[uuid.UUID][]*models.OddsSnapshot{raceID: []*models.OddsSnapshot{odds}}},
 			RaceResult: &fakeRaceResultRepo{results: map[uuid.UUID]*models.RaceResult{raceID: result}},
 		},
 		strategy: testStrategy{
@@ -489,3 +967,189 @@ func TestStateRecovery(t *testing.T) {
 	// Verify equity points were recorded
 	assert.Greater(t, len(state.EquityCurve), 0)
 }
+
+// TestBSPBetSettlesAtStartingPrice tests that BSP bets ignore the signal odds and settle at the recorded SP
+func TestBSPBetSettlesAtStartingPrice(t *testing.T) {
+	raceID := uuid.New()
+	runnerID := uuid.New()
+	start := time.Now().Add(-48 * time.Hour)
+	end := time.Now().Add(-24 * time.Hour)
+
+	race := &models.Race{ID: raceID, ScheduledStart: end}
+	runner := &models.Runner{ID: runnerID, RaceID: raceID, TrapNumber: 1, Name: "Runner"}
+	odds := &models.OddsSnapshot{RaceID: raceID, RunnerID: runnerID, Time: start, BackPrice: floatPtr(3.0)}
+	winner := 1
+	positions := []byte(`{"runners":[{"runner_id":"` + runnerID.String() + `","trap_number":1,"position":1,"sp":"4.5","place_payout":"0"}]}`)
+	result := &models.RaceResult{RaceID: raceID, Time: end, WinnerTrap: &winner, Positions: positions}
+
+	engine := &Engine{
+		config: BacktestConfig{InitialBankroll: 1000.0, SlippageTicks: 2, CommissionRate: 0.0},
+		repositories: &repository.Repositories{
+			Race:       &fakeRaceRepo{races: []*models.Race{race}, runners: map[uuid.UUID][]*models.Runner{raceID: []*models.Runner{runner}}, odds: map[uuid.UUID][]*models.OddsSnapshot{raceID: []*models.OddsSnapshot{odds}}},
+			RaceResult: &fakeRaceResultRepo{results: map[uuid.UUID]*models.RaceResult{raceID: result}},
+		},
+		strategy: testStrategy{
+			returnSignals: []strategy.Signal{{
+				RunnerID:   runnerID,
+				Side:       models.BetSideBack,
+				Odds:       3.0,
+				Stake:      10.0,
+				Confidence: 0.8,
+				BSP:        true,
+			}},
+		},
+	}
+
+	state, err := engine.HistoricalReplay(context.Background(), start, end)
+	require.NoError(t, err)
+	require.Len(t, state.Bets, 1)
+
+	bet := state.Bets[0]
+	assert.True(t, bet.IsBSP)
+	assert.Equal(t, models.BetStatusSettled, bet.Status)
+	assert.InDelta(t, 3.0, bet.Odds, 0.0001, "indicative odds should not be slipped")
+	require.NotNil(t, bet.MatchedPrice)
+	assert.InDelta(t, 4.5, *bet.MatchedPrice, 0.0001)
+	require.NotNil(t, bet.ProfitLoss)
+	assert.InDelta(t, 35.0, *bet.ProfitLoss, 0.0001, "profit should use SP, not signal odds")
+	assert.InDelta(t, 1035.0, state.CurrentBankroll, 0.0001)
+}
+
+// TestBSPBetVoidWithoutStartingPrice tests that BSP bets are voided when no SP is recorded
+func TestBSPBetVoidWithoutStartingPrice(t *testing.T) {
+	raceID := uuid.New()
+	runnerID := uuid.New()
+	start := time.Now().Add(-48 * time.Hour)
+	end := time.Now().Add(-24 * time.Hour)
+
+	race := &models.Race{ID: raceID, ScheduledStart: end}
+	runner := &models.Runner{ID: runnerID, RaceID: raceID, TrapNumber: 1, Name: "Runner"}
+	winner := 1
+	result := &models.RaceResult{RaceID: raceID, Time: end, WinnerTrap: &winner}
+
+	engine := &Engine{
+		config: BacktestConfig{InitialBankroll: 1000.0, CommissionRate: 0.05},
+		repositories: &repository.Repositories{
+			Race:       &fakeRaceRepo{races: []*models.Race{race}, runners: map[uuid.UUID][]*models.Runner{raceID: []*models.Runner{runner}}, odds: map[uuid.UUID][]*models.OddsSnapshot{}},
+			RaceResult: &fakeRaceResultRepo{results: map[uuid.UUID]*models.RaceResult{raceID: result}},
+		},
+		strategy: testStrategy{
+			returnSignals: []strategy.Signal{{
+				RunnerID: runnerID,
+				Side:     models.BetSideBack,
+				Stake:    10.0,
+				BSP:      true,
+			}},
+		},
+	}
+
+	state, err := engine.HistoricalReplay(context.Background(), start, end)
+	require.NoError(t, err)
+	require.Len(t, state.Bets, 1)
+
+	bet := state.Bets[0]
+	assert.Equal(t, models.BetStatusCancelled, bet.Status)
+	assert.Nil(t, bet.MatchedPrice)
+	assert.Equal(t, 1000.0, state.CurrentBankroll)
+}
+
+// TestFillStatisticsWithConstrainedLiquidity tests stakes are capped by the available size
+func TestFillStatisticsWithConstrainedLiquidity(t *testing.T) {
+	start := time.Now().Add(-48 * time.Hour)
+	end := time.Now().Add(-24 * time.Hour)
+	winner := 1
+
+	// Available back size per race against a requested stake of 10: unknown
+	// (full fill), deep (full fill), thin (partial fill) and empty (unmatched)
+	sizes := []*float64{nil, floatPtr(25), floatPtr(4), floatPtr(0)}
+
+	races := make([]*models.Race, 0, len(sizes))
+	runners := make(map[uuid.UUID][]*models.Runner)
+	odds := make(map[uuid.UUID][]*models.OddsSnapshot)
+	results := make(map[uuid.UUID]*models.RaceResult)
+	for i, size := range sizes {
+		raceID := uuid.New()
+		runnerID := uuid.New()
+		scheduled := end.Add(-time.Duration(len(sizes)-i) * time.Hour)
+		races = append(races, &models.Race{ID: raceID, ScheduledStart: scheduled})
+		runners[raceID] = []*models.Runner{{ID: runnerID, RaceID: raceID, TrapNumber: 1, Name: "Runner"}}
+		odds[raceID] = []*models.OddsSnapshot{
+			// An older, deeper book is superseded by the latest snapshot
+			{RaceID: raceID, RunnerID: runnerID, Time: scheduled.Add(-10 * time.Minute), BackPrice: floatPtr(3.0), BackSize: floatPtr(100)},
+			{RaceID: raceID, RunnerID: runnerID, Time: scheduled.Add(-time.Minute), BackPrice: floatPtr(3.0), BackSize: size},
+		}
+		results[raceID] = &models.RaceResult{RaceID: raceID, Time: scheduled, WinnerTrap: &winner}
+	}
+
+	engine := &Engine{
+		config: BacktestConfig{InitialBankroll: 1000.0},
+		repositories: &repository.Repositories{
+			Race:       &fakeRaceRepo{races: races, runners: runners, odds: odds},
+			RaceResult: &fakeRaceResultRepo{results: results},
+		},
+		strategy: testStrategy{},
+		logger:   logrus.New(),
+	}
+
+	state, err := engine.HistoricalReplay(context.Background(), start, end)
+	require.NoError(t, err)
+	require.Len(t, state.Bets, 3, "the unmatched bet must not be recorded")
+	assert.Equal(t, 10.0, state.Bets[0].Stake)
+	assert.Equal(t, 10.0, state.Bets[1].Stake)
+	assert.Equal(t, 4.0, state.Bets[2].Stake)
+	require.NotNil(t, state.Bets[2].MatchedSize)
+	assert.Equal(t, 4.0, *state.Bets[2].MatchedSize)
+
+	metrics := CalculateMetrics(state, engine.config)
+	assert.InDelta(t, 0.25, metrics.UnmatchedRate, 1e-9)
+	assert.InDelta(t, 0.25, metrics.PartialMatchRate, 1e-9)
+	assert.InDelta(t, (1+1+0.4+0)/4.0, metrics.AverageFillRatio, 1e-9)
+	assert.InDelta(t, (25+4+0)/3.0, metrics.AverageLiquidity, 1e-9, "races without a recorded size are not sampled")
+}
+
+// TestFillOnlyCountsTopLadderLevels tests fills sum size over the best
+// MaxLadderDepth levels and ignore deeper liquidity
+func TestFillOnlyCountsTopLadderLevels(t *testing.T) {
+	runnerID := uuid.New()
+	race := &models.Race{ID: uuid.New(), ScheduledStart: time.Now()}
+	signal := strategy.Signal{RunnerID: runnerID, Side: models.BetSideBack, Odds: 3.0, Stake: 20}
+	oddsHistory := []*models.OddsSnapshot{{
+		RunnerID: runnerID, Time: time.Now().Add(-time.Minute),
+		BackPrice: floatPtr(3.0), BackSize: floatPtr(5),
+		BackLadder: models.PriceLadder{{Price: 3.0, Size: 5}, {Price: 2.98, Size: 5}, {Price: 2.96, Size: 100}},
+	}}
+
+	engine := &Engine{config: BacktestConfig{InitialBankroll: 1000.0, MaxLadderDepth: 2}}
+	bet := engine.SimulateBetExecution(race, signal, oddsHistory)
+	require.NotNil(t, bet)
+	assert.Equal(t, 10.0, bet.Stake, "the 100 at the third level is ignored")
+
+	engine.config.MaxLadderDepth = 0
+	bet = engine.SimulateBetExecution(race, signal, oddsHistory)
+	require.NotNil(t, bet)
+	assert.Equal(t, 20.0, bet.Stake, "zero depth counts the whole ladder")
+
+	// Snapshots recorded without a ladder fall back to the best price's size
+	engine.config.MaxLadderDepth = 2
+	oddsHistory[0].BackLadder = nil
+	bet = engine.SimulateBetExecution(race, signal, oddsHistory)
+	require.NotNil(t, bet)
+	assert.Equal(t, 5.0, bet.Stake)
+}
+
+func TestFillRoundsStakeToIncrement(t *testing.T) {
+	runnerID := uuid.New()
+	race := &models.Race{ID: uuid.New(), ScheduledStart: time.Now()}
+	oddsHistory := []*models.OddsSnapshot{{RunnerID: runnerID, Time: time.Now().Add(-time.Minute), BackPrice: floatPtr(3.0), BackSize: floatPtr(100)}}
+
+	engine := &Engine{config: BacktestConfig{
+		InitialBankroll: 1000.0,
+		StakeRounding:   strategy.StakeRounding{MinStake: 1, Increment: 0.01},
+	}}
+	bet := engine.SimulateBetExecution(race, strategy.Signal{RunnerID: runnerID, Side: models.BetSideBack, Odds: 3.0, Stake: 3.337}, oddsHistory)
+	require.NotNil(t, bet)
+	assert.Equal(t, 3.33, bet.Stake)
+
+	bet = engine.SimulateBetExecution(race, strategy.Signal{RunnerID: runnerID, Side: models.BetSideBack, Odds: 3.0, Stake: 0.5}, oddsHistory)
+	assert.Nil(t, bet, "stakes below the minimum are not placed")
+}
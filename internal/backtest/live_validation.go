@@ -0,0 +1,102 @@
+package backtest
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/clever-better/internal/models"
+)
+
+// DefaultLiveValidationTolerance is the largest P&L or commission difference
+// per bet put down to rounding
+const DefaultLiveValidationTolerance = 0.01
+
+// LiveDivergence is a live bet whose recorded settlement the backtest engine
+// does not reproduce
+type LiveDivergence struct {
+	BetID              uuid.UUID `json:"bet_id"`
+	RaceID             uuid.UUID `json:"race_id"`
+	LiveProfitLoss     float64   `json:"live_profit_loss"`
+	BacktestProfitLoss float64   `json:"backtest_profit_loss"`
+	LiveCommission     float64   `json:"live_commission"`
+	BacktestCommission float64   `json:"backtest_commission"`
+}
+
+// Difference returns the backtest P&L less the live P&L
+func (d LiveDivergence) Difference() float64 {
+	return d.BacktestProfitLoss - d.LiveProfitLoss
+}
+
+// LiveValidation compares the settlement of live bets with the backtest
+// engine's settlement of the same bets
+type LiveValidation struct {
+	Checked            int              `json:"checked"`
+	Skipped            int              `json:"skipped"`
+	LiveProfitLoss     float64          `json:"live_profit_loss"`
+	BacktestProfitLoss float64          `json:"backtest_profit_loss"`
+	Divergences        []LiveDivergence `json:"divergences"`
+}
+
+// Valid reports whether every checked bet settled the same way in the backtest
+func (v *LiveValidation) Valid() bool {
+	return len(v.Divergences) == 0
+}
+
+// ValidateLiveBets settles copies of live settled bets with the backtest
+// engine, at the matched odds, stake and commission the live bets had, and
+// reports each bet whose P&L or commission differs from the recorded value by
+// more than tolerance. A divergence is a settlement bug in one or the other,
+// not a strategy difference. Commission promos in cfg apply as in a backtest.
+// Bets that are not settled are skipped, and winners are identified by runner
+// ID as in ResettleBets.
+func ValidateLiveBets(bets []*models.Bet, results map[uuid.UUID]*models.RaceResult, cfg BacktestConfig, tolerance float64) (*LiveValidation, error) {
+	if tolerance < 0 {
+		return nil, fmt.Errorf("tolerance must not be negative")
+	}
+
+	validation := &LiveValidation{}
+	for _, bet := range bets {
+		if bet == nil {
+			continue
+		}
+		if bet.Status != models.BetStatusSettled || bet.ProfitLoss == nil {
+			validation.Skipped++
+			continue
+		}
+		result, ok := results[bet.RaceID]
+		if !ok || result == nil {
+			return nil, fmt.Errorf("no result for race %s of bet %s", bet.RaceID, bet.ID)
+		}
+
+		replayed := unsettledCopy(bet)
+		SettleBet(replayed, result, &models.Runner{ID: bet.RunnerID, RaceID: bet.RaceID}, cfg.CommissionRateAt(result.Time))
+
+		divergence := LiveDivergence{
+			BetID:          bet.ID,
+			RaceID:         bet.RaceID,
+			LiveProfitLoss: *bet.ProfitLoss,
+		}
+		if bet.Commission != nil {
+			divergence.LiveCommission = *bet.Commission
+		}
+		if replayed.ProfitLoss != nil {
+			divergence.BacktestProfitLoss = *replayed.ProfitLoss
+		}
+		if replayed.Commission != nil {
+			divergence.BacktestCommission = *replayed.Commission
+		}
+
+		validation.Checked++
+		validation.LiveProfitLoss += divergence.LiveProfitLoss
+		validation.BacktestProfitLoss += divergence.BacktestProfitLoss
+		// An each-way place leg left open cannot match a fully settled live bet
+		if replayed.Status == models.BetStatusMatched ||
+			math.Abs(divergence.Difference()) > tolerance ||
+			math.Abs(divergence.BacktestCommission-divergence.LiveCommission) > tolerance {
+			validation.Divergences = append(validation.Divergences, divergence)
+		}
+	}
+
+	return validation, nil
+}
@@ -0,0 +1,61 @@
+package backtest
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/clever-better/internal/models"
+)
+
+// liveSettledBets gives the fixture bets the P&L and commission the exchange
+// reported at 2% commission
+func liveSettledBets(t *testing.T) ([]*models.Bet, map[uuid.UUID]*models.RaceResult) {
+	t.Helper()
+	bets, results := resettleFixture(t)
+
+	outcomes := [][2]float64{{19.6, 0.4}, {-10, 0}}
+	for i, bet := range bets {
+		pnl, commission := outcomes[i][0], outcomes[i][1]
+		bet.ProfitLoss = &pnl
+		bet.Commission = &commission
+	}
+	return bets, results
+}
+
+func TestValidateLiveBetsMatchingSettlement(t *testing.T) {
+	bets, results := liveSettledBets(t)
+
+	validation, err := ValidateLiveBets(bets, results, BacktestConfig{CommissionRate: 0.02}, DefaultLiveValidationTolerance)
+	require.NoError(t, err)
+	assert.True(t, validation.Valid())
+	assert.Equal(t, 2, validation.Checked)
+	assert.InDelta(t, 9.6, validation.LiveProfitLoss, 1e-9)
+	assert.InDelta(t, 9.6, validation.BacktestProfitLoss, 1e-9)
+	assert.InDelta(t, 19.6, *bets[0].ProfitLoss, 1e-9, "the live bets are left untouched")
+}
+
+func TestValidateLiveBetsFlagsDiscrepancy(t *testing.T) {
+	bets, results := liveSettledBets(t)
+
+	// The winner was recorded as if commission was charged at 1%
+	wrong := 19.8
+	bets[0].ProfitLoss = &wrong
+	// An unsettled bet is not compared
+	bets = append(bets, &models.Bet{RaceID: bets[0].RaceID, Status: models.BetStatusMatched, Stake: 10, Odds: 3.0})
+
+	validation, err := ValidateLiveBets(bets, results, BacktestConfig{CommissionRate: 0.02}, DefaultLiveValidationTolerance)
+	require.NoError(t, err)
+	assert.False(t, validation.Valid())
+	assert.Equal(t, 2, validation.Checked)
+	assert.Equal(t, 1, validation.Skipped)
+	require.Len(t, validation.Divergences, 1)
+	assert.Equal(t, bets[0].ID, validation.Divergences[0].BetID)
+	assert.InDelta(t, -0.2, validation.Divergences[0].Difference(), 1e-9)
+
+	// A wide enough tolerance accepts the difference
+	validation, err = ValidateLiveBets(bets, results, BacktestConfig{CommissionRate: 0.02}, 0.5)
+	require.NoError(t, err)
+	assert.True(t, validation.Valid())
+}
@@ -38,6 +38,15 @@ type Metrics struct {
 	StrategyID       uuid.UUID `json:"strategy_id"`
 	ParameterHash    string    `json:"parameter_hash"`
 	ValidationScore  float64   `json:"validation_score"`
+	UnmatchedRate    float64   `json:"unmatched_rate"`
+	// RejectionRate is the share of bet attempts the rejection model dropped
+	RejectionRate    float64   `json:"rejection_rate"`
+	PartialMatchRate float64   `json:"partial_match_rate"`
+	AverageFillRatio float64   `json:"average_fill_ratio"`
+	// AverageLiquidity is the mean size offered to bet attempts, where known
+	AverageLiquidity float64   `json:"average_liquidity"`
+	// Segments breaks bet performance down by market type, venue and odds band
+	Segments SegmentedMetrics `json:"segments"`
 }
 
 // CalculateMetrics calculates metrics from backtest state
@@ -75,6 +84,18 @@ func CalculateMetrics(state *BacktestState, cfg BacktestConfig) Metrics {
 	metrics.WinRate = calculateWinRate(metrics.WinningBets, metrics.TotalBets)
 	metrics.ProfitFactor = calculateProfitFactor(state.Bets)
 	metrics.Expectancy = calculateExpectancy(state.Bets)
+	metrics.Segments = CalculateSegmentedMetrics(state.Bets, state.Venues)
+
+	if fills := state.Fills; fills.Attempts > 0 {
+		attempts := float64(fills.Attempts)
+		metrics.UnmatchedRate = float64(fills.Unmatched) / attempts
+		metrics.RejectionRate = float64(fills.Rejected) / attempts
+		metrics.PartialMatchRate = float64(fills.PartialMatches) / attempts
+		metrics.AverageFillRatio = fills.FillRatioSum / attempts
+	}
+	if fills := state.Fills; fills.LiquiditySamples > 0 {
+		metrics.AverageLiquidity = fills.LiquiditySum / float64(fills.LiquiditySamples)
+	}
 
 	return metrics
 }
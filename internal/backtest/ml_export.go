@@ -53,6 +53,7 @@ type ExportDBParams struct {
 	EndDate        time.Time
 	InitialCapital float64
 	FinalCapital   float64
+	Tags           []string
 }
 
 // ExportToJSON writes export data to JSON file
@@ -94,6 +95,7 @@ func ExportToDatabase(ctx context.Context, result AggregatedResult, repo reposit
 		Recommendation: result.Recommendation,
 		MLFeatures:     mustMarshalJSON(result.MLFeatures),
 		FullResults:    mustMarshalJSON(result),
+		Tags:           models.NormalizeTags(params.Tags),
 		CreatedAt:      time.Now().UTC(),
 	}
 	return repo.SaveResult(ctx, &model)
@@ -55,10 +55,7 @@ func RunMonteCarlo(ctx context.Context, bets []*models.Bet, probabilities map[st
 				prob = 0.5
 			}
 			win := rng.Float64() < prob
-			pnl := calculatePnL(bet, win)
-			if pnl > 0 && cfg.CommissionRate > 0 {
-				pnl -= pnl * cfg.CommissionRate
-			}
+			pnl := calculatePnL(bet, win) - calculateCommission(bet, win, cfg.CommissionRate)
 			bankroll += pnl
 			if bankroll <= 0 {
 				bankroll = 0
@@ -0,0 +1,254 @@
+package backtest
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/clever-better/internal/models"
+	"github.com/yourusername/clever-better/internal/repository"
+	"github.com/yourusername/clever-better/internal/strategy"
+)
+
+// ParameterGrid maps each parameter name to the values to try
+type ParameterGrid map[string][]float64
+
+// StrategyFactory builds a fresh strategy for one parameter combination
+type StrategyFactory func(params map[string]float64) (strategy.Strategy, error)
+
+// OptimizerConfig configures a parameter search
+type OptimizerConfig struct {
+	Grid ParameterGrid
+	// RandomSamples limits the search to that many combinations drawn from
+	// the grid. Zero evaluates the full grid.
+	RandomSamples int
+	Seed          int64
+	Workers       int
+}
+
+// OptimizationResult is the outcome of one parameter combination
+type OptimizationResult struct {
+	Parameters     map[string]float64 `json:"parameters"`
+	Metrics        Metrics            `json:"metrics"`
+	CompositeScore float64            `json:"composite_score"`
+}
+
+// RunOptimization backtests every selected combination over the engine's date
+// range and returns the results ranked by composite score, best first. Race
+// data is loaded once and shared by all runs.
+func RunOptimization(ctx context.Context, engine *Engine, factory StrategyFactory, cfg OptimizerConfig) ([]OptimizationResult, error) {
+	if engine == nil {
+		return nil, fmt.Errorf("engine is required")
+	}
+	if factory == nil {
+		return nil, fmt.Errorf("strategy factory is required")
+	}
+
+	combinations, err := expandGrid(cfg.Grid)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.RandomSamples > 0 && cfg.RandomSamples < len(combinations) {
+		seed := cfg.Seed
+		if seed == 0 {
+			seed = time.Now().UnixNano()
+		}
+		rng := rand.New(rand.NewSource(seed))
+		rng.Shuffle(len(combinations), func(i, j int) {
+			combinations[i], combinations[j] = combinations[j], combinations[i]
+		})
+		combinations = combinations[:cfg.RandomSamples]
+	}
+
+	start := engine.config.StartDate
+	end := engine.config.EndDate
+	repos, err := preloadRaceData(ctx, engine.repositories, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	workers := cfg.Workers
+	if workers <= 0 {
+		workers = 1
+	}
+
+	results := make([]OptimizationResult, len(combinations))
+	errs := make([]error, len(combinations))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i], errs[i] = runCombination(ctx, engine, repos, factory, combinations[i], start, end)
+			}
+		}()
+	}
+	for i := range combinations {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			return nil, fmt.Errorf("failed to backtest parameters %v: %w", combinations[i], err)
+		}
+	}
+
+	// Stable sort keeps grid order between equal scores
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].CompositeScore > results[j].CompositeScore
+	})
+
+	engine.logger.WithField("combinations", len(results)).Info("Parameter optimization completed")
+	return results, nil
+}
+
+// runCombination backtests one parameter set on an engine sharing the preloaded data
+func runCombination(ctx context.Context, base *Engine, repos *repository.Repositories, factory StrategyFactory, params map[string]float64, start, end time.Time) (OptimizationResult, error) {
+	strat, err := factory(params)
+	if err != nil {
+		return OptimizationResult{}, fmt.Errorf("failed to build strategy: %w", err)
+	}
+
+	engine := &Engine{
+		config:       base.config,
+		repositories: repos,
+		strategy:     strat,
+		logger:       base.logger,
+	}
+	_, metrics, err := engine.Run(ctx, start, end)
+	if err != nil {
+		return OptimizationResult{}, err
+	}
+
+	return OptimizationResult{
+		Parameters:     params,
+		Metrics:        metrics,
+		CompositeScore: CalculateCompositeScore(metrics, AggregationWeights{}),
+	}, nil
+}
+
+// expandGrid returns every combination of the grid's values. Parameters are
+// iterated in name order so the output order is deterministic.
+func expandGrid(grid ParameterGrid) ([]map[string]float64, error) {
+	if len(grid) == 0 {
+		return nil, fmt.Errorf("parameter grid is empty")
+	}
+
+	names := make([]string, 0, len(grid))
+	for name, values := range grid {
+		if len(values) == 0 {
+			return nil, fmt.Errorf("parameter %s has no values", name)
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	combinations := []map[string]float64{{}}
+	for _, name := range names {
+		next := make([]map[string]float64, 0, len(combinations)*len(grid[name]))
+		for _, combination := range combinations {
+			for _, value := range grid[name] {
+				params := make(map[string]float64, len(combination)+1)
+				for k, v := range combination {
+					params[k] = v
+				}
+				params[name] = value
+				next = append(next, params)
+			}
+		}
+		combinations = next
+	}
+
+	return combinations, nil
+}
+
+// preloadRaceData reads the races in range with their runners, odds and
+// results once and returns repositories serving them from memory. Runners
+// and odds for every race come back in a single query.
+func preloadRaceData(ctx context.Context, repos *repository.Repositories, start, end time.Time) (*repository.Repositories, error) {
+	races, err := repos.Race.GetByDateRange(ctx, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load races: %w", err)
+	}
+
+	raceIDs := make([]uuid.UUID, len(races))
+	for i, race := range races {
+		raceIDs[i] = race.ID
+	}
+	loaded, err := repos.Race.GetRaceBundles(ctx, raceIDs, start)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load runners and odds: %w", err)
+	}
+	bundles := make(map[uuid.UUID]*models.RaceBundle, len(loaded))
+	for _, bundle := range loaded {
+		bundles[bundle.Race.ID] = bundle
+	}
+
+	results := make(map[uuid.UUID]*models.RaceResult, len(races))
+	for _, race := range races {
+		if results[race.ID], err = repos.RaceResult.GetByRaceID(ctx, race.ID); err != nil {
+			return nil, fmt.Errorf("failed to load race result: %w", err)
+		}
+	}
+
+	preloaded := *repos
+	preloaded.Race = &preloadedRaceRepo{RaceRepository: repos.Race, races: races, bundles: bundles}
+	preloaded.RaceResult = &preloadedResultRepo{RaceResultRepository: repos.RaceResult, results: results}
+	return &preloaded, nil
+}
+
+// preloadedRaceRepo serves the races loaded for an optimization and their
+// bundles
+type preloadedRaceRepo struct {
+	repository.RaceRepository
+	races   []*models.Race
+	bundles map[uuid.UUID]*models.RaceBundle
+}
+
+func (r *preloadedRaceRepo) GetByDateRange(ctx context.Context, start, end time.Time) ([]*models.Race, error) {
+	return r.races, nil
+}
+
+func (r *preloadedRaceRepo) StreamByDateRange(ctx context.Context, start, end time.Time, fn func(*models.Race) error) error {
+	for _, race := range r.races {
+		if err := fn(race); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetRaceBundle serves a preloaded bundle, leaving out odds before oddsSince
+// when it is later than the optimization's start
+func (r *preloadedRaceRepo) GetRaceBundle(ctx context.Context, raceID uuid.UUID, oddsSince time.Time) (*models.RaceBundle, error) {
+	bundle, ok := r.bundles[raceID]
+	if !ok {
+		return nil, models.ErrNotFound
+	}
+	first := sort.Search(len(bundle.Odds), func(i int) bool {
+		return !bundle.Odds[i].Time.Before(oddsSince)
+	})
+	if first == 0 {
+		return bundle, nil
+	}
+	return &models.RaceBundle{Race: bundle.Race, Runners: bundle.Runners, Odds: bundle.Odds[first:]}, nil
+}
+
+// preloadedResultRepo serves race results keyed by race
+type preloadedResultRepo struct {
+	repository.RaceResultRepository
+	results map[uuid.UUID]*models.RaceResult
+}
+
+func (r *preloadedResultRepo) GetByRaceID(ctx context.Context, raceID uuid.UUID) (*models.RaceResult, error) {
+	return r.results[raceID], nil
+}
@@ -0,0 +1,172 @@
+package backtest
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/clever-better/internal/models"
+	"github.com/yourusername/clever-better/internal/repository"
+	"github.com/yourusername/clever-better/internal/strategy"
+)
+
+// paramStrategy backs trap 1 at the visible price when it is at least
+// min_odds, staking the configured amount
+type paramStrategy struct {
+	testStrategy
+	minOdds float64
+	stake   float64
+}
+
+func (p paramStrategy) Evaluate(ctx context.Context, strategyCtx strategy.Context) ([]strategy.Signal, error) {
+	if len(strategyCtx.Runners) == 0 || len(strategyCtx.OddsHistory) == 0 {
+		return nil, nil
+	}
+	return []strategy.Signal{{
+		RunnerID: strategyCtx.Runners[0].ID,
+		Side:     models.BetSideBack,
+		Odds:     *strategyCtx.OddsHistory[0].BackPrice,
+		Stake:    p.stake,
+	}}, nil
+}
+
+func (p paramStrategy) ShouldBet(signal strategy.Signal) bool { return signal.Odds >= p.minOdds }
+
+// countingRaceRepo counts bundle loads to check data is shared across runs
+type countingRaceRepo struct {
+	fakeRaceRepo
+	mu    sync.Mutex
+	loads int
+}
+
+func (c *countingRaceRepo) GetRaceBundle(ctx context.Context, raceID uuid.UUID, oddsSince time.Time) (*models.RaceBundle, error) {
+	c.mu.Lock()
+	c.loads++
+	c.mu.Unlock()
+	return c.fakeRaceRepo.GetRaceBundle(ctx, raceID, oddsSince)
+}
+
+func (c *countingRaceRepo) GetRaceBundles(ctx context.Context, raceIDs []uuid.UUID, oddsSince time.Time) ([]*models.RaceBundle, error) {
+	c.mu.Lock()
+	c.loads++
+	c.mu.Unlock()
+	return c.fakeRaceRepo.GetRaceBundles(ctx, raceIDs, oddsSince)
+}
+
+func newOptimizerTestEngine(t *testing.T) (*Engine, *countingRaceRepo) {
+	t.Helper()
+	start := time.Now().Add(-48 * time.Hour)
+	end := time.Now().Add(-24 * time.Hour)
+
+	races := &countingRaceRepo{fakeRaceRepo: fakeRaceRepo{
+		runners: map[uuid.UUID][]*models.Runner{},
+		odds:    map[uuid.UUID][]*models.OddsSnapshot{},
+	}}
+	results := map[uuid.UUID]*models.RaceResult{}
+
+	// Trap 1 wins two of three races at 3.0 and loses both at 1.5, so
+	// filtering out short prices scores best
+	for _, race := range []struct {
+		price  float64
+		winner int
+	}{{3.0, 1}, {1.5, 2}, {3.0, 2}, {1.5, 2}, {3.0, 1}} {
+		raceID := uuid.New()
+		runnerID := uuid.New()
+		winner := race.winner
+		races.races = append(races.races, &models.Race{ID: raceID, ScheduledStart: end})
+		races.runners[raceID] = []*models.Runner{{ID: runnerID, RaceID: raceID, TrapNumber: 1, Name: "Runner"}}
+		races.odds[raceID] = []*models.OddsSnapshot{{RaceID: raceID, RunnerID: runnerID, Time: start, BackPrice: floatPtr(race.price)}}
+		results[raceID] = &models.RaceResult{RaceID: raceID, Time: end, WinnerTrap: &winner}
+	}
+
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+	return &Engine{
+		config: BacktestConfig{StartDate: start, EndDate: end, InitialBankroll: 100},
+		repositories: &repository.Repositories{
+			Race:       races,
+			RaceResult: &fakeRaceResultRepo{results: results},
+		},
+		strategy: testStrategy{},
+		logger:   logger,
+	}, races
+}
+
+func paramStrategyFactory(calls *int32) StrategyFactory {
+	return func(params map[string]float64) (strategy.Strategy, error) {
+		atomic.AddInt32(calls, 1)
+		return paramStrategy{minOdds: params["min_odds"], stake: params["stake"]}, nil
+	}
+}
+
+func TestRunOptimizationEvaluatesGrid(t *testing.T) {
+	engine, races := newOptimizerTestEngine(t)
+	var calls int32
+
+	results, err := RunOptimization(context.Background(), engine, paramStrategyFactory(&calls), OptimizerConfig{
+		Grid: ParameterGrid{
+			"min_odds": {1.0, 2.0},
+			"stake":    {5, 10},
+		},
+		Workers: 2,
+	})
+	require.NoError(t, err)
+
+	require.Len(t, results, 4)
+	assert.Equal(t, int32(4), calls, "every combination is evaluated")
+	assert.Equal(t, 1, races.loads, "runners and odds are loaded in one query, not per run")
+
+	seen := map[[2]float64]bool{}
+	for _, result := range results {
+		seen[[2]float64{result.Parameters["min_odds"], result.Parameters["stake"]}] = true
+	}
+	assert.Len(t, seen, 4)
+
+	assert.Equal(t, map[string]float64{"min_odds": 2.0, "stake": 10}, results[0].Parameters)
+	for i := 1; i < len(results); i++ {
+		assert.GreaterOrEqual(t, results[i-1].CompositeScore, results[i].CompositeScore)
+	}
+}
+
+func TestRunOptimizationRandomSearchIsSeeded(t *testing.T) {
+	grid := ParameterGrid{
+		"min_odds": {1.0, 2.0},
+		"stake":    {5, 10},
+	}
+
+	run := func() []OptimizationResult {
+		engine, _ := newOptimizerTestEngine(t)
+		var calls int32
+		results, err := RunOptimization(context.Background(), engine, paramStrategyFactory(&calls), OptimizerConfig{
+			Grid:          grid,
+			RandomSamples: 2,
+			Seed:          42,
+			Workers:       2,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, int32(2), calls)
+		return results
+	}
+
+	first := run()
+	second := run()
+	require.Len(t, first, 2)
+	for i := range first {
+		assert.Equal(t, first[i].Parameters, second[i].Parameters)
+		assert.Equal(t, first[i].CompositeScore, second[i].CompositeScore)
+	}
+}
+
+func TestExpandGridRejectsEmptyValues(t *testing.T) {
+	_, err := expandGrid(ParameterGrid{"stake": {}})
+	assert.Error(t, err)
+
+	_, err = expandGrid(nil)
+	assert.Error(t, err)
+}
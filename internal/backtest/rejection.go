@@ -0,0 +1,80 @@
+package backtest
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+)
+
+// Rejection modes
+const (
+	RejectionOff       = "off"
+	RejectionFlat      = "flat"
+	RejectionLiquidity = "liquidity"
+)
+
+// RejectionModel drops a fraction of simulated bets to mimic live orders that
+// fail to place through latency, suspensions or thin markets. Flat mode
+// rejects every bet with probability Rate. Liquidity mode rises from Rate
+// towards MaxRate as the stake takes up more of the size on offer. Draws come
+// from Seed, so a run rejects the same bets every time.
+type RejectionModel struct {
+	Mode    string
+	Rate    float64
+	MaxRate float64
+	Seed    int64
+}
+
+// Enabled reports whether the model rejects any bets
+func (m RejectionModel) Enabled() bool {
+	return (m.Mode == RejectionFlat || m.Mode == RejectionLiquidity) && math.Max(m.Rate, m.MaxRate) > 0
+}
+
+// Validate validates the rejection model parameters
+func (m RejectionModel) Validate() error {
+	switch m.Mode {
+	case "", RejectionOff, RejectionFlat, RejectionLiquidity:
+	default:
+		return fmt.Errorf("unknown rejection mode %q", m.Mode)
+	}
+	if m.Rate < 0 || m.Rate > 1 {
+		return fmt.Errorf("rejection rate must be between 0 and 1")
+	}
+	if m.Mode == RejectionLiquidity && (m.MaxRate < m.Rate || m.MaxRate > 1) {
+		return fmt.Errorf("rejection max rate must be between the rate and 1")
+	}
+	return nil
+}
+
+// Probability returns the chance a bet of stake is rejected when the market
+// offered size. Liquidity mode uses Rate when no size was recorded.
+func (m RejectionModel) Probability(stake, size float64, sizeKnown bool) float64 {
+	switch m.Mode {
+	case RejectionFlat:
+		return m.Rate
+	case RejectionLiquidity:
+		if !sizeKnown {
+			return m.Rate
+		}
+		share := 1.0
+		if size > 0 {
+			share = math.Min(stake/size, 1)
+		}
+		return m.Rate + (m.MaxRate-m.Rate)*share
+	default:
+		return 0
+	}
+}
+
+// rejected draws whether a bet is rejected, seeding the run's generator on
+// first use
+func (e *Engine) rejected(stake, size float64, sizeKnown bool, state *BacktestState) bool {
+	model := e.config.Rejection
+	if !model.Enabled() {
+		return false
+	}
+	if state.rejections == nil {
+		state.rejections = rand.New(rand.NewSource(model.Seed))
+	}
+	return state.rejections.Float64() < model.Probability(stake, size, sizeKnown)
+}
@@ -0,0 +1,75 @@
+package backtest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/clever-better/internal/models"
+	"github.com/yourusername/clever-better/internal/repository"
+)
+
+// rejectionEngine builds an engine over races that each produce one bet
+func rejectionEngine(races int, model RejectionModel) *Engine {
+	scheduled := time.Now().Add(-time.Hour)
+	raceList := make([]*models.Race, 0, races)
+	runners := make(map[uuid.UUID][]*models.Runner, races)
+	odds := make(map[uuid.UUID][]*models.OddsSnapshot, races)
+	for i := 0; i < races; i++ {
+		raceID := uuid.New()
+		runnerID := uuid.New()
+		raceList = append(raceList, &models.Race{ID: raceID, ScheduledStart: scheduled})
+		runners[raceID] = []*models.Runner{{ID: runnerID, RaceID: raceID, TrapNumber: 1, Name: "Runner"}}
+		odds[raceID] = []*models.OddsSnapshot{{RaceID: raceID, RunnerID: runnerID, Time: scheduled.Add(-time.Minute), BackPrice: floatPtr(3.0), BackSize: floatPtr(40)}}
+	}
+
+	return &Engine{
+		config: BacktestConfig{InitialBankroll: 1000.0, Rejection: model},
+		repositories: &repository.Repositories{
+			Race:       &fakeRaceRepo{races: raceList, runners: runners, odds: odds},
+			RaceResult: &fakeRaceResultRepo{results: map[uuid.UUID]*models.RaceResult{}},
+		},
+		strategy: testStrategy{},
+		logger:   logrus.New(),
+	}
+}
+
+func TestRejectionModelDropsConfiguredShareOfBets(t *testing.T) {
+	const races = 5000
+	start := time.Now().Add(-48 * time.Hour)
+	end := time.Now()
+
+	baseline, err := rejectionEngine(races, RejectionModel{}).HistoricalReplay(context.Background(), start, end)
+	require.NoError(t, err)
+	require.Len(t, baseline.Bets, races)
+
+	engine := rejectionEngine(races, RejectionModel{Mode: RejectionFlat, Rate: 0.2, Seed: 7})
+	state, err := engine.HistoricalReplay(context.Background(), start, end)
+	require.NoError(t, err)
+	assert.InDelta(t, races*0.8, len(state.Bets), races*0.02, "about a fifth of the bets are rejected")
+	assert.Equal(t, races-len(state.Bets), state.Fills.Rejected)
+
+	metrics := CalculateMetrics(state, engine.config)
+	assert.InDelta(t, 0.2, metrics.RejectionRate, 0.02)
+
+	again, err := engine.HistoricalReplay(context.Background(), start, end)
+	require.NoError(t, err)
+	assert.Equal(t, len(state.Bets), len(again.Bets), "the same seed rejects the same bets")
+}
+
+func TestRejectionProbabilityByLiquidity(t *testing.T) {
+	model := RejectionModel{Mode: RejectionLiquidity, Rate: 0.02, MaxRate: 0.2}
+
+	assert.InDelta(t, 0.02, model.Probability(10, 40, false), 1e-9, "unknown size uses the base rate")
+	assert.InDelta(t, 0.02+0.18*0.25, model.Probability(10, 40, true), 1e-9)
+	assert.InDelta(t, 0.2, model.Probability(50, 40, true), 1e-9, "a stake taking the whole book uses the max rate")
+	assert.InDelta(t, 0.02, RejectionModel{Mode: RejectionFlat, Rate: 0.02}.Probability(50, 40, true), 1e-9)
+
+	assert.False(t, RejectionModel{Mode: RejectionOff, Rate: 0.5}.Enabled())
+	assert.Error(t, RejectionModel{Mode: "random"}.Validate())
+	assert.Error(t, RejectionModel{Mode: RejectionLiquidity, Rate: 0.2, MaxRate: 0.1}.Validate())
+}
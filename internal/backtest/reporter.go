@@ -19,6 +19,19 @@ func GenerateConsoleReport(result AggregatedResult) string {
 	builder.WriteString(fmt.Sprintf("Max Drawdown: %.2f%%\n", result.HistoricalReplayMetrics.MaxDrawdown*100))
 	builder.WriteString(fmt.Sprintf("Win Rate: %.2f%%\n", result.HistoricalReplayMetrics.WinRate*100))
 	builder.WriteString(fmt.Sprintf("Profit Factor: %.2f\n", result.HistoricalReplayMetrics.ProfitFactor))
+	builder.WriteString(fmt.Sprintf("Unmatched Rate: %.2f%%\n", result.HistoricalReplayMetrics.UnmatchedRate*100))
+	builder.WriteString(fmt.Sprintf("Rejection Rate: %.2f%%\n", result.HistoricalReplayMetrics.RejectionRate*100))
+	builder.WriteString(fmt.Sprintf("Partial Match Rate: %.2f%%\n", result.HistoricalReplayMetrics.PartialMatchRate*100))
+	builder.WriteString(fmt.Sprintf("Average Fill Ratio: %.2f%%\n", result.HistoricalReplayMetrics.AverageFillRatio*100))
+	if result.Benchmark != nil {
+		builder.WriteString(fmt.Sprintf("Benchmark Return: %.2f%%\n", result.Benchmark.Benchmark.TotalReturn*100))
+		builder.WriteString(fmt.Sprintf("Excess Return: %.2f%%\n", result.Benchmark.ExcessReturn*100))
+		builder.WriteString(fmt.Sprintf("Information Ratio: %.2f\n", result.Benchmark.InformationRatio))
+	}
+	segments := result.HistoricalReplayMetrics.Segments
+	writeSegmentReport(&builder, "Market Type", segments.MarketType)
+	writeSegmentReport(&builder, "Venue", segments.Venue)
+	writeSegmentReport(&builder, "Odds Band", segments.OddsBand)
 	return builder.String()
 }
 
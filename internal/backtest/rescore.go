@@ -0,0 +1,137 @@
+package backtest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/yourusername/clever-better/internal/config"
+	"github.com/yourusername/clever-better/internal/models"
+	"github.com/yourusername/clever-better/internal/repository"
+)
+
+// MethodAggregated is the method stored on results exported by ExportToDatabase
+const MethodAggregated = "aggregated"
+
+// scoreTolerance treats scores within the stored precision of composite_score
+// (four decimal places) as unchanged
+const scoreTolerance = 1e-4
+
+// ErrNotRescorable is returned for stored results whose composite score
+// cannot be recomputed, either because they were scored by another method or
+// because their full results are missing
+var ErrNotRescorable = errors.New("backtest result cannot be rescored")
+
+// DefaultAggregationWeights are the method weights used when none are configured
+var DefaultAggregationWeights = AggregationWeights{
+	HistoricalReplay: 0.4,
+	MonteCarlo:       0.3,
+	WalkForward:      0.3,
+}
+
+// WeightsFromConfig converts configured composite weights, falling back to
+// DefaultAggregationWeights when none are set
+func WeightsFromConfig(cfg config.CompositeWeightsConfig) AggregationWeights {
+	weights := AggregationWeights(cfg)
+	if weights == (AggregationWeights{}) {
+		return DefaultAggregationWeights
+	}
+	return weights
+}
+
+// RescoreResult recomputes a stored aggregated result's composite score and
+// recommendation under weights. Historical metrics come from the result's
+// columns; Monte Carlo and walk-forward results from its full results.
+func RescoreResult(result *models.BacktestResult, weights AggregationWeights) (float64, string, error) {
+	if result.Method != MethodAggregated {
+		return 0, "", fmt.Errorf("%w: method %q", ErrNotRescorable, result.Method)
+	}
+	if len(result.FullResults) == 0 {
+		return 0, "", fmt.Errorf("%w: no full results", ErrNotRescorable)
+	}
+	var stored AggregatedResult
+	if err := json.Unmarshal(result.FullResults, &stored); err != nil {
+		return 0, "", fmt.Errorf("%w: %v", ErrNotRescorable, err)
+	}
+
+	historical := stored.HistoricalReplayMetrics
+	historical.TotalReturn = result.TotalReturn
+	historical.SharpeRatio = result.SharpeRatio
+	historical.MaxDrawdown = result.MaxDrawdown
+	historical.TotalBets = result.TotalBets
+	historical.WinRate = result.WinRate
+	historical.ProfitFactor = result.ProfitFactor
+
+	rescored := AggregateResults(historical, stored.MonteCarloResult, stored.WalkForwardResult, weights)
+	return rescored.CompositeScore, rescored.Recommendation, nil
+}
+
+// RescoreChange is a stored result whose score or recommendation changed
+type RescoreChange struct {
+	ResultID          uuid.UUID `json:"result_id"`
+	StrategyID        uuid.UUID `json:"strategy_id"`
+	OldScore          float64   `json:"old_score"`
+	NewScore          float64   `json:"new_score"`
+	OldRecommendation string    `json:"old_recommendation"`
+	NewRecommendation string    `json:"new_recommendation"`
+}
+
+// RescoreReport summarises a composite score backfill
+type RescoreReport struct {
+	Scanned   int             `json:"scanned"`
+	Unchanged int             `json:"unchanged"`
+	Skipped   int             `json:"skipped"`
+	Changes   []RescoreChange `json:"changes"`
+	DryRun    bool            `json:"dry_run"`
+}
+
+// RescoreCompositeScores recomputes the composite score and recommendation
+// of every stored aggregated result under weights and updates those that
+// changed, so results stored under an older formula rank consistently with
+// new ones. A dry run only reports the changes. Results that cannot be
+// rescored are counted as skipped.
+func RescoreCompositeScores(ctx context.Context, repo repository.BacktestResultRepository, weights AggregationWeights, dryRun bool) (RescoreReport, error) {
+	report := RescoreReport{DryRun: dryRun, Changes: []RescoreChange{}}
+
+	results, err := repo.GetByDateRange(ctx, time.Time{}, time.Now().UTC())
+	if err != nil {
+		return report, fmt.Errorf("failed to get backtest results: %w", err)
+	}
+
+	for _, result := range results {
+		report.Scanned++
+		score, recommendation, err := RescoreResult(result, weights)
+		if errors.Is(err, ErrNotRescorable) {
+			report.Skipped++
+			continue
+		}
+		if err != nil {
+			return report, err
+		}
+		if math.Abs(score-result.CompositeScore) < scoreTolerance && recommendation == result.Recommendation {
+			report.Unchanged++
+			continue
+		}
+
+		report.Changes = append(report.Changes, RescoreChange{
+			ResultID:          result.ID,
+			StrategyID:        result.StrategyID,
+			OldScore:          result.CompositeScore,
+			NewScore:          score,
+			OldRecommendation: result.Recommendation,
+			NewRecommendation: recommendation,
+		})
+		if dryRun {
+			continue
+		}
+		if err := repo.UpdateCompositeScore(ctx, result.ID, score, recommendation); err != nil {
+			return report, fmt.Errorf("failed to update backtest result %s: %w", result.ID, err)
+		}
+	}
+	return report, nil
+}
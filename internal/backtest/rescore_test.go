@@ -0,0 +1,102 @@
+package backtest
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yourusername/clever-better/internal/config"
+	"github.com/yourusername/clever-better/internal/models"
+	"github.com/yourusername/clever-better/internal/repository"
+)
+
+// storedResultRepo serves stored results and records score updates
+type storedResultRepo struct {
+	repository.BacktestResultRepository
+	results []*models.BacktestResult
+	updated map[uuid.UUID]float64
+}
+
+func (r *storedResultRepo) GetByDateRange(ctx context.Context, start, end time.Time) ([]*models.BacktestResult, error) {
+	return r.results, nil
+}
+
+func (r *storedResultRepo) UpdateCompositeScore(ctx context.Context, resultID uuid.UUID, score float64, recommendation string) error {
+	if r.updated == nil {
+		r.updated = make(map[uuid.UUID]float64)
+	}
+	r.updated[resultID] = score
+	return nil
+}
+
+func aggregatedResult(t *testing.T, historical Metrics, monteCarlo MonteCarloResult, walkForward WalkForwardResult, weights AggregationWeights) *models.BacktestResult {
+	t.Helper()
+	aggregated := AggregateResults(historical, monteCarlo, walkForward, weights)
+	full, err := json.Marshal(aggregated)
+	require.NoError(t, err)
+	return &models.BacktestResult{
+		ID:             uuid.New(),
+		StrategyID:     uuid.New(),
+		TotalReturn:    historical.TotalReturn,
+		SharpeRatio:    historical.SharpeRatio,
+		MaxDrawdown:    historical.MaxDrawdown,
+		TotalBets:      historical.TotalBets,
+		WinRate:        historical.WinRate,
+		ProfitFactor:   historical.ProfitFactor,
+		Method:         MethodAggregated,
+		CompositeScore: aggregated.CompositeScore,
+		Recommendation: aggregated.Recommendation,
+		FullResults:    full,
+	}
+}
+
+func TestRescoreCompositeScores(t *testing.T) {
+	historical := Metrics{TotalReturn: 0.3, SharpeRatio: 1.5, MaxDrawdown: 0.1, TotalBets: 200, WinRate: 0.55, ProfitFactor: 1.8}
+	monteCarlo := MonteCarloResult{MeanReturn: 0.25}
+	walkForward := WalkForwardResult{ConsistencyScore: 0.7, AggregatedMetrics: Metrics{TotalReturn: 0.2}}
+
+	current := aggregatedResult(t, historical, monteCarlo, walkForward, DefaultAggregationWeights)
+	legacy := aggregatedResult(t, historical, monteCarlo, walkForward, DefaultAggregationWeights)
+	legacy.CompositeScore = 3.7 // scored under an older formula
+	estimate := &models.BacktestResult{ID: uuid.New(), Method: "ml_estimate", CompositeScore: 1.2}
+	noFullResults := &models.BacktestResult{ID: uuid.New(), Method: MethodAggregated, CompositeScore: 0.9}
+
+	repo := &storedResultRepo{results: []*models.BacktestResult{current, legacy, estimate, noFullResults}}
+	expected, _, err := RescoreResult(legacy, DefaultAggregationWeights)
+	require.NoError(t, err)
+	assert.InDelta(t, current.CompositeScore, expected, 1e-9)
+
+	preview, err := RescoreCompositeScores(context.Background(), repo, DefaultAggregationWeights, true)
+	require.NoError(t, err)
+	assert.Equal(t, 4, preview.Scanned)
+	assert.Equal(t, 1, preview.Unchanged)
+	assert.Equal(t, 2, preview.Skipped)
+	require.Len(t, preview.Changes, 1)
+	assert.Equal(t, legacy.ID, preview.Changes[0].ResultID)
+	assert.Equal(t, 3.7, preview.Changes[0].OldScore)
+	assert.InDelta(t, expected, preview.Changes[0].NewScore, 1e-9)
+	assert.Empty(t, repo.updated, "a dry run saves nothing")
+
+	report, err := RescoreCompositeScores(context.Background(), repo, DefaultAggregationWeights, false)
+	require.NoError(t, err)
+	assert.Equal(t, preview.Changes, report.Changes)
+	require.Len(t, repo.updated, 1)
+	assert.InDelta(t, expected, repo.updated[legacy.ID], 1e-9)
+}
+
+func TestRescoreResultUsesNewWeights(t *testing.T) {
+	historical := Metrics{TotalReturn: 0.3, SharpeRatio: 1.5, MaxDrawdown: 0.1, TotalBets: 200, WinRate: 0.55, ProfitFactor: 1.8}
+	walkForward := WalkForwardResult{ConsistencyScore: 0.7, AggregatedMetrics: Metrics{TotalReturn: 0.4}}
+	result := aggregatedResult(t, historical, MonteCarloResult{MeanReturn: -0.2}, walkForward, DefaultAggregationWeights)
+
+	walkForwardOnly := AggregationWeights{WalkForward: 1}
+	score, _, err := RescoreResult(result, walkForwardOnly)
+	require.NoError(t, err)
+	assert.InDelta(t, normalize(0.4, -0.5, 1.0), score, 1e-9)
+	assert.Equal(t, DefaultAggregationWeights, WeightsFromConfig(config.CompositeWeightsConfig{}), "unset weights use the defaults")
+}
@@ -0,0 +1,84 @@
+package backtest
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/clever-better/internal/models"
+)
+
+// ResettleBets recomputes P&L and metrics for already-placed bets under a
+// hypothetical commission rate, without re-running any strategy. Winners are
+// identified by runner ID from each race's recorded positions. Bets that were
+// never matched are skipped and the caller's bets are left untouched.
+//
+// Live bets carry no bankroll, so returns are measured against turnover: the
+// starting bankroll is the total stake of the resettled bets.
+func ResettleBets(bets []*models.Bet, results map[uuid.UUID]*models.RaceResult, commissionRate float64) (Metrics, error) {
+	if commissionRate < 0 || commissionRate > 0.1 {
+		return Metrics{}, fmt.Errorf("commission rate must be between 0 and 0.1")
+	}
+
+	resettled := make([]*models.Bet, 0, len(bets))
+	turnover := 0.0
+	for _, bet := range bets {
+		if bet == nil || bet.Status == models.BetStatusCancelled {
+			continue
+		}
+		result, ok := results[bet.RaceID]
+		if !ok || result == nil {
+			return Metrics{}, fmt.Errorf("no result for race %s of bet %s", bet.RaceID, bet.ID)
+		}
+
+		copied := unsettledCopy(bet)
+		resettled = append(resettled, copied)
+		turnover += copied.TotalStake()
+	}
+	if len(resettled) == 0 {
+		return Metrics{}, fmt.Errorf("no matched bets to resettle")
+	}
+
+	sort.SliceStable(resettled, func(i, j int) bool {
+		return results[resettled[i].RaceID].Time.Before(results[resettled[j].RaceID].Time)
+	})
+
+	first := results[resettled[0].RaceID].Time.UTC()
+	last := results[resettled[len(resettled)-1].RaceID].Time.UTC()
+	cfg := BacktestConfig{
+		StartDate:       first,
+		EndDate:         last,
+		InitialBankroll: turnover,
+		CommissionRate:  commissionRate,
+	}
+
+	state := &BacktestState{
+		CurrentBankroll: turnover,
+		PeakBankroll:    turnover,
+		DailyPnL:        make(map[time.Time]float64),
+	}
+	state.RecordEquityPoint(first, turnover)
+	for _, bet := range resettled {
+		result := results[bet.RaceID]
+		pnl := SettleBet(bet, result, &models.Runner{ID: bet.RunnerID, RaceID: bet.RaceID}, commissionRate)
+		state.UpdateState(bet, pnl)
+		state.RecordEquityPoint(result.Time.UTC(), state.CurrentBankroll)
+	}
+
+	return CalculateMetrics(state, cfg), nil
+}
+
+// unsettledCopy returns a copy of a placed bet with its settlement cleared,
+// ready to be settled again
+func unsettledCopy(bet *models.Bet) *models.Bet {
+	copied := *bet
+	// A BSP bet that already has its matched price settles at that price
+	if copied.IsBSP && copied.MatchedPrice != nil {
+		copied.IsBSP = false
+	}
+	// Each-way legs settle afresh
+	copied.ProfitLoss, copied.Commission = nil, nil
+	copied.WinProfitLoss, copied.PlaceProfitLoss = nil, nil
+	return &copied
+}
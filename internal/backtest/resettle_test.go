@@ -0,0 +1,92 @@
+package backtest
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/clever-better/internal/models"
+)
+
+func resettleFixture(t *testing.T) ([]*models.Bet, map[uuid.UUID]*models.RaceResult) {
+	t.Helper()
+
+	settled := time.Date(2024, 3, 1, 19, 0, 0, 0, time.UTC)
+	results := make(map[uuid.UUID]*models.RaceResult)
+	var bets []*models.Bet
+
+	// A winning back at 3.0 followed by a losing back, each staking 10
+	for i, wins := range []bool{true, false} {
+		raceID := uuid.New()
+		runnerID := uuid.New()
+		winnerID := runnerID
+		if !wins {
+			winnerID = uuid.New()
+		}
+		positions, err := json.Marshal(models.PositionsData{Runners: []models.RunnerPosition{{RunnerID: winnerID, Position: 1}}})
+		require.NoError(t, err)
+		results[raceID] = &models.RaceResult{RaceID: raceID, Time: settled.Add(time.Duration(i) * time.Hour), Positions: positions}
+
+		pnl := 0.0
+		bets = append(bets, &models.Bet{
+			ID:         uuid.New(),
+			RaceID:     raceID,
+			RunnerID:   runnerID,
+			Side:       models.BetSideBack,
+			Odds:       3.0,
+			Stake:      10,
+			Status:     models.BetStatusSettled,
+			ProfitLoss: &pnl,
+		})
+	}
+
+	return bets, results
+}
+
+// TestResettleBetsCommission tests re-evaluating live bets under a higher commission
+func TestResettleBetsCommission(t *testing.T) {
+	bets, results := resettleFixture(t)
+
+	atTwo, err := ResettleBets(bets, results, 0.02)
+	require.NoError(t, err)
+	atFive, err := ResettleBets(bets, results, 0.05)
+	require.NoError(t, err)
+
+	// Net P&L is 20*(1-rate) - 10 against a turnover of 20
+	assert.InDelta(t, 0.48, atTwo.TotalReturn, 1e-9)
+	assert.InDelta(t, 0.45, atFive.TotalReturn, 1e-9)
+	assert.Equal(t, 2, atFive.TotalBets)
+	assert.Equal(t, 1, atFive.WinningBets)
+	assert.InDelta(t, 19.0, atFive.LargestWin, 1e-9)
+
+	// The caller's bets keep their recorded P&L
+	assert.Equal(t, 0.0, *bets[0].ProfitLoss)
+}
+
+// TestResettleBetsSkipsUnmatched tests that cancelled bets are ignored
+func TestResettleBetsSkipsUnmatched(t *testing.T) {
+	bets, results := resettleFixture(t)
+	bets = append(bets, &models.Bet{ID: uuid.New(), RaceID: uuid.New(), Stake: 50, Status: models.BetStatusCancelled})
+
+	metrics, err := ResettleBets(bets, results, 0.02)
+	require.NoError(t, err)
+	assert.Equal(t, 2, metrics.TotalBets)
+}
+
+// TestResettleBetsErrors tests invalid rates and missing results
+func TestResettleBetsErrors(t *testing.T) {
+	bets, results := resettleFixture(t)
+
+	_, err := ResettleBets(bets, results, 0.2)
+	assert.Error(t, err)
+
+	delete(results, bets[1].RaceID)
+	_, err = ResettleBets(bets, results, 0.02)
+	assert.Error(t, err)
+
+	_, err = ResettleBets(nil, results, 0.02)
+	assert.Error(t, err)
+}
@@ -0,0 +1,145 @@
+package backtest
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/clever-better/internal/models"
+)
+
+// Odds bands used to segment bets by settlement price
+const (
+	OddsBandUnder2 = "under_2"
+	OddsBand2To4   = "2_to_4"
+	OddsBandOver4  = "over_4"
+)
+
+// unknownVenue labels bets whose race venue was not recorded
+const unknownVenue = "unknown"
+
+// SegmentMetrics aggregates the bets falling in one segment
+type SegmentMetrics struct {
+	Bets       int     `json:"bets"`
+	Wins       int     `json:"wins"`
+	WinRate    float64 `json:"win_rate"`
+	Staked     float64 `json:"staked"`
+	ProfitLoss float64 `json:"profit_loss"`
+	// Return is profit over stake for the segment
+	Return float64 `json:"return"`
+}
+
+// SegmentedMetrics breaks bet performance down by market type, venue and odds
+// band, so a strategy profitable at one track and losing at another shows it
+type SegmentedMetrics struct {
+	MarketType map[string]SegmentMetrics `json:"market_type"`
+	Venue      map[string]SegmentMetrics `json:"venue"`
+	OddsBand   map[string]SegmentMetrics `json:"odds_band"`
+}
+
+// OddsBand returns the odds band a settlement price falls in
+func OddsBand(price float64) string {
+	switch {
+	case price < 2.0:
+		return OddsBandUnder2
+	case price <= 4.0:
+		return OddsBand2To4
+	default:
+		return OddsBandOver4
+	}
+}
+
+// CalculateSegmentedMetrics groups bets by market type, venue and odds band.
+// venues maps race IDs to their track.
+func CalculateSegmentedMetrics(bets []*models.Bet, venues map[uuid.UUID]string) SegmentedMetrics {
+	segments := SegmentedMetrics{
+		MarketType: make(map[string]SegmentMetrics),
+		Venue:      make(map[string]SegmentMetrics),
+		OddsBand:   make(map[string]SegmentMetrics),
+	}
+
+	for _, bet := range bets {
+		venue := venues[bet.RaceID]
+		if venue == "" {
+			venue = unknownVenue
+		}
+		addToSegment(segments.MarketType, string(bet.MarketType), bet)
+		addToSegment(segments.Venue, venue, bet)
+		addToSegment(segments.OddsBand, OddsBand(bet.SettlementPrice()), bet)
+	}
+
+	for _, group := range []map[string]SegmentMetrics{segments.MarketType, segments.Venue, segments.OddsBand} {
+		for key, segment := range group {
+			segment.WinRate = calculateWinRate(segment.Wins, segment.Bets)
+			if segment.Staked > 0 {
+				segment.Return = segment.ProfitLoss / segment.Staked
+			}
+			group[key] = segment
+		}
+	}
+	return segments
+}
+
+func addToSegment(group map[string]SegmentMetrics, key string, bet *models.Bet) {
+	segment := group[key]
+	segment.Bets++
+	segment.Staked += bet.TotalStake()
+	if bet.ProfitLoss != nil {
+		segment.ProfitLoss += *bet.ProfitLoss
+		if *bet.ProfitLoss > 0 {
+			segment.Wins++
+		}
+	}
+	group[key] = segment
+}
+
+// segmentFeatures flattens the market type and odds band segments into ML
+// features. Venues are unbounded, so only the spread of their returns is kept.
+func segmentFeatures(segments SegmentedMetrics, features map[string]float64) {
+	for key, segment := range segments.MarketType {
+		prefix := "market_type_" + strings.ToLower(key)
+		features[prefix+"_return"] = segment.Return
+		features[prefix+"_win_rate"] = segment.WinRate
+		features[prefix+"_bets"] = float64(segment.Bets)
+	}
+	for key, segment := range segments.OddsBand {
+		prefix := "odds_band_" + key
+		features[prefix+"_return"] = segment.Return
+		features[prefix+"_win_rate"] = segment.WinRate
+		features[prefix+"_bets"] = float64(segment.Bets)
+	}
+	if len(segments.Venue) == 0 {
+		return
+	}
+	first := true
+	for _, segment := range segments.Venue {
+		if first || segment.Return < features["venue_return_min"] {
+			features["venue_return_min"] = segment.Return
+		}
+		if first || segment.Return > features["venue_return_max"] {
+			features["venue_return_max"] = segment.Return
+		}
+		first = false
+	}
+	features["venue_count"] = float64(len(segments.Venue))
+}
+
+// writeSegmentReport appends one line per segment, in key order
+func writeSegmentReport(builder *strings.Builder, title string, group map[string]SegmentMetrics) {
+	if len(group) == 0 {
+		return
+	}
+	keys := make([]string, 0, len(group))
+	for key := range group {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	builder.WriteString(fmt.Sprintf("By %s:\n", title))
+	for _, key := range keys {
+		segment := group[key]
+		builder.WriteString(fmt.Sprintf("  %s: %d bets, Win Rate %.2f%%, Return %.2f%%\n",
+			key, segment.Bets, segment.WinRate*100, segment.Return*100))
+	}
+}
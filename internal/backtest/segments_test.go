@@ -0,0 +1,79 @@
+package backtest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/clever-better/internal/models"
+)
+
+func TestCalculateMetricsSegmentsByVenueAndOddsBand(t *testing.T) {
+	start := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	state := NewBacktestState(1000)
+	romford := uuid.New()
+	hove := uuid.New()
+	state.RecordVenue(romford, "Romford")
+	state.RecordVenue(hove, "Hove")
+
+	addBet := func(raceID uuid.UUID, marketType models.MarketType, odds, stake, pnl float64) {
+		settled := start.Add(time.Duration(len(state.Bets)) * time.Minute)
+		profit := pnl
+		state.UpdateState(&models.Bet{
+			ID:         uuid.New(),
+			RaceID:     raceID,
+			MarketType: marketType,
+			Odds:       odds,
+			Stake:      stake,
+			ProfitLoss: &profit,
+			SettledAt:  &settled,
+		}, pnl)
+		state.RecordEquityPoint(settled, state.CurrentBankroll)
+	}
+	// Romford: short-priced winners
+	addBet(romford, models.MarketTypeWin, 1.8, 10, 8)
+	addBet(romford, models.MarketTypeWin, 1.5, 10, 5)
+	addBet(romford, models.MarketTypePlace, 1.9, 10, -10)
+	// Hove: longer-priced losers, one winner
+	addBet(hove, models.MarketTypeWin, 3.0, 10, -10)
+	addBet(hove, models.MarketTypeWin, 3.5, 20, -20)
+	addBet(hove, models.MarketTypeWin, 2.5, 10, 15)
+
+	metrics := CalculateMetrics(state, BacktestConfig{StartDate: start, EndDate: start.Add(time.Hour)})
+	segments := metrics.Segments
+
+	require.Len(t, segments.Venue, 2)
+	assert.Equal(t, 3, segments.Venue["Romford"].Bets)
+	assert.Equal(t, 2, segments.Venue["Romford"].Wins)
+	assert.InDelta(t, 2.0/3.0, segments.Venue["Romford"].WinRate, 1e-9)
+	assert.InDelta(t, 3.0/30.0, segments.Venue["Romford"].Return, 1e-9)
+	assert.Equal(t, 3, segments.Venue["Hove"].Bets)
+	assert.InDelta(t, -15.0/40.0, segments.Venue["Hove"].Return, 1e-9)
+
+	require.Len(t, segments.OddsBand, 2)
+	assert.Equal(t, segments.Venue["Romford"], segments.OddsBand[OddsBandUnder2])
+	assert.Equal(t, segments.Venue["Hove"], segments.OddsBand[OddsBand2To4])
+
+	require.Len(t, segments.MarketType, 2)
+	assert.Equal(t, 5, segments.MarketType["WIN"].Bets)
+	assert.InDelta(t, -2.0/60.0, segments.MarketType["WIN"].Return, 1e-9)
+	assert.Equal(t, 1, segments.MarketType["PLACE"].Bets)
+	assert.Equal(t, 0.0, segments.MarketType["PLACE"].WinRate)
+
+	result := AggregateResults(metrics, MonteCarloResult{}, WalkForwardResult{}, AggregationWeights{HistoricalReplay: 1})
+	assert.InDelta(t, 3.0/30.0, result.MLFeatures["odds_band_under_2_return"], 1e-9)
+	assert.InDelta(t, -15.0/40.0, result.MLFeatures["venue_return_min"], 1e-9)
+	assert.InDelta(t, 3.0/30.0, result.MLFeatures["venue_return_max"], 1e-9)
+
+	report := GenerateConsoleReport(result)
+	assert.Contains(t, report, "By Venue:\n  Hove: 3 bets, Win Rate 33.33%, Return -37.50%\n  Romford: 3 bets")
+}
+
+func TestOddsBandBoundaries(t *testing.T) {
+	assert.Equal(t, OddsBandUnder2, OddsBand(1.99))
+	assert.Equal(t, OddsBand2To4, OddsBand(2.0))
+	assert.Equal(t, OddsBand2To4, OddsBand(4.0))
+	assert.Equal(t, OddsBandOver4, OddsBand(4.2))
+}
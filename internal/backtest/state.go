@@ -1,8 +1,11 @@
 package backtest
 
 import (
+	"math"
+	"math/rand"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/yourusername/clever-better/internal/models"
 )
 
@@ -13,6 +16,23 @@ type BacktestState struct {
 	Bets            []*models.Bet
 	EquityCurve     EquityCurve
 	DailyPnL        map[time.Time]float64
+	Fills           FillStats
+	// Venues maps race IDs to their track for segmented metrics
+	Venues map[uuid.UUID]string
+	// rejections draws simulated order rejections for the run
+	rejections *rand.Rand
+}
+
+// FillStats tracks requested versus matched stake across bet attempts, and
+// the size the market offered when it was recorded
+type FillStats struct {
+	Attempts         int
+	Unmatched        int
+	Rejected         int
+	PartialMatches   int
+	FillRatioSum     float64
+	LiquiditySum     float64
+	LiquiditySamples int
 }
 
 // NewBacktestState initializes backtest state
@@ -23,6 +43,7 @@ func NewBacktestState(initialBankroll float64) *BacktestState {
 		Bets:            []*models.Bet{},
 		EquityCurve:     EquityCurve{},
 		DailyPnL:        make(map[time.Time]float64),
+		Venues:          make(map[uuid.UUID]string),
 	}
 	state.RecordEquityPoint(time.Now().UTC(), initialBankroll)
 	return state
@@ -42,6 +63,44 @@ func (s *BacktestState) UpdateState(bet *models.Bet, pnl float64) {
 	}
 }
 
+// RecordFill records how much of a requested stake was matched
+func (s *BacktestState) RecordFill(requested, matched float64) {
+	if requested <= 0 {
+		return
+	}
+	s.Fills.Attempts++
+	switch {
+	case matched <= 0:
+		s.Fills.Unmatched++
+	case matched < requested:
+		s.Fills.PartialMatches++
+	}
+	s.Fills.FillRatioSum += math.Min(matched, requested) / requested
+}
+
+// RecordRejection records a bet attempt rejected before it reached the market
+func (s *BacktestState) RecordRejection(requested float64) {
+	if requested <= 0 {
+		return
+	}
+	s.Fills.Attempts++
+	s.Fills.Rejected++
+}
+
+// RecordLiquidity records the size the market offered for a bet attempt
+func (s *BacktestState) RecordLiquidity(size float64) {
+	s.Fills.LiquiditySum += math.Max(size, 0)
+	s.Fills.LiquiditySamples++
+}
+
+// RecordVenue records the track a race was run at
+func (s *BacktestState) RecordVenue(raceID uuid.UUID, venue string) {
+	if s.Venues == nil {
+		s.Venues = make(map[uuid.UUID]string)
+	}
+	s.Venues[raceID] = venue
+}
+
 // GetCurrentDrawdown calculates peak-to-trough drawdown
 func (s *BacktestState) GetCurrentDrawdown() float64 {
 	if s.PeakBankroll == 0 {
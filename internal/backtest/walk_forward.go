@@ -9,6 +9,17 @@ import (
 	"github.com/yourusername/clever-better/internal/strategy"
 )
 
+// WindowMode selects how the training window moves between folds
+type WindowMode string
+
+const (
+	// WindowModeRolling slides a fixed-length training window each fold
+	WindowModeRolling WindowMode = "rolling"
+	// WindowModeAnchored keeps the training start fixed so the training
+	// window grows each fold
+	WindowModeAnchored WindowMode = "anchored"
+)
+
 // WalkForwardConfig configures walk-forward optimization
 type WalkForwardConfig struct {
 	TrainingWindowDays   int
@@ -16,6 +27,8 @@ type WalkForwardConfig struct {
 	TestWindowDays       int
 	StepSizeDays         int
 	MinTradesPerWindow   int
+	// WindowMode defaults to rolling
+	WindowMode           WindowMode
 }
 
 // WalkForwardWindow represents one walk-forward window
@@ -49,6 +62,16 @@ func RunWalkForward(ctx context.Context, engine *Engine, strat strategy.Strategy
 	if cfg.StepSizeDays <= 0 {
 		cfg.StepSizeDays = cfg.TestWindowDays
 	}
+	if cfg.WindowMode == "" {
+		cfg.WindowMode = WindowModeRolling
+	}
+	if cfg.WindowMode != WindowModeRolling && cfg.WindowMode != WindowModeAnchored {
+		return WalkForwardResult{}, fmt.Errorf("unknown window mode: %s", cfg.WindowMode)
+	}
+	// A step shorter than the test window would reuse test days across folds
+	if cfg.StepSizeDays < cfg.TestWindowDays {
+		return WalkForwardResult{}, fmt.Errorf("step size (%d days) must be at least the test window (%d days)", cfg.StepSizeDays, cfg.TestWindowDays)
+	}
 
 	start := engine.config.StartDate
 	end := engine.config.EndDate
@@ -57,7 +80,10 @@ func RunWalkForward(ctx context.Context, engine *Engine, strat strategy.Strategy
 
 	for current := start; current.Before(end); current = current.AddDate(0, 0, cfg.StepSizeDays) {
 		trainStart := current
-		trainEnd := trainStart.AddDate(0, 0, cfg.TrainingWindowDays)
+		if cfg.WindowMode == WindowModeAnchored {
+			trainStart = start
+		}
+		trainEnd := current.AddDate(0, 0, cfg.TrainingWindowDays)
 		valStart := trainEnd
 		valEnd := valStart.AddDate(0, 0, cfg.ValidationWindowDays)
 		testStart := valEnd
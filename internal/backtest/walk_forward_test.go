@@ -6,6 +6,9 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/yourusername/clever-better/internal/models"
 	"github.com/yourusername/clever-better/internal/repository"
 	"github.com/yourusername/clever-better/internal/strategy"
@@ -28,6 +31,65 @@ func TestRunWalkForward(t *testing.T) {
 	}
 }
 
+func TestRunWalkForwardWindowModes(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+
+	tests := []struct {
+		mode         WindowMode
+		trainingDays []int
+	}{
+		{mode: WindowModeRolling, trainingDays: []int{3, 3, 3, 3}},
+		{mode: WindowModeAnchored, trainingDays: []int{3, 5, 7, 9}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.mode), func(t *testing.T) {
+			engine := buildTestEngine()
+			engine.logger = logrus.New()
+			engine.logger.SetLevel(logrus.ErrorLevel)
+			engine.config.StartDate = start
+			engine.config.EndDate = start.AddDate(0, 0, 11)
+
+			result, err := RunWalkForward(context.Background(), engine, testStrategy{}, WalkForwardConfig{
+				TrainingWindowDays:   3,
+				ValidationWindowDays: 1,
+				TestWindowDays:       2,
+				StepSizeDays:         2,
+				WindowMode:           tt.mode,
+			})
+			require.NoError(t, err)
+			require.Len(t, result.Windows, len(tt.trainingDays))
+
+			for i, window := range result.Windows {
+				assert.Equal(t, time.Duration(tt.trainingDays[i])*day, window.TrainEnd.Sub(window.TrainStart), "fold %d training size", i+1)
+				assert.False(t, window.TestStart.Before(window.ValEnd), "test follows validation")
+				assert.False(t, window.ValStart.Before(window.TrainEnd), "validation follows training")
+				if i > 0 {
+					assert.False(t, window.TestStart.Before(result.Windows[i-1].TestEnd), "test windows do not overlap")
+				}
+			}
+		})
+	}
+}
+
+func TestRunWalkForwardRejectsOverlappingTestWindows(t *testing.T) {
+	engine := buildTestEngine()
+	_, err := RunWalkForward(context.Background(), engine, testStrategy{}, WalkForwardConfig{
+		TrainingWindowDays: 3,
+		TestWindowDays:     2,
+		StepSizeDays:       1,
+	})
+	assert.Error(t, err)
+
+	_, err = RunWalkForward(context.Background(), engine, testStrategy{}, WalkForwardConfig{
+		TrainingWindowDays: 3,
+		TestWindowDays:     2,
+		WindowMode:         "expanding",
+	})
+	assert.Error(t, err)
+}
+
 func buildTestEngine() *Engine {
 	raceID := uuid.New()
 	runnerID := uuid.New()
@@ -42,9 +104,7 @@ func buildTestEngine() *Engine {
 	return &Engine{
 		config: BacktestConfig{StartDate: start, EndDate: end, InitialBankroll: 100, CommissionRate: 0.05},
 		repositories: &repository.Repositories{
-			Race:       &fakeRaceRepo{races: []*models.Race{race}},
-			Runner:     &fakeRunnerRepo{runners: map[uuid.UUID][]*models.Runner{raceID: []*models.Runner{runner}}},
-			Odds:       &fakeOddsRepo{odds: map[uuid.UUID][]*models.OddsSnapshot{raceID: []*models.OddsSnapshot{odds}}},
+			Race:       &fakeRaceRepo{races: []*models.Race{race}, runners: map[uuid.UUID][]*models.Runner{raceID: []*models.Runner{runner}}, odds: map[uuid.UUID][]*models.OddsSnapshot{raceID: []*models.OddsSnapshot{odds}}},
 			RaceResult: &fakeRaceResultRepo{results: map[uuid.UUID]*models.RaceResult{raceID: result}},
 		},
 		strategy: strategy.NewSimpleValueStrategy(),
@@ -0,0 +1,534 @@
+// Package betfairtest provides a scriptable fake Betfair exchange for tests.
+package betfairtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Paths served besides the JSON-RPC endpoint, which answers every other path
+const (
+	LoginPath     = "/api/certlogin"
+	KeepAlivePath = "/api/keepAlive"
+)
+
+// Betfair error codes the fake can be scripted to return
+const (
+	ErrorInvalidSession    = "INVALID_SESSION_INFORMATION"
+	ErrorInsufficientFunds = "INSUFFICIENT_FUNDS"
+	ErrorMarketSuspended   = "MARKET_SUSPENDED"
+)
+
+// defaultSessionToken is issued when the fixture does not set one
+const defaultSessionToken = "fake-session"
+
+// PriceSize is a price level with the size available at it
+type PriceSize struct {
+	Price float64 `json:"price"`
+	Size  float64 `json:"size"`
+}
+
+// Runner is a selection in a fixture market
+type Runner struct {
+	SelectionID     uint64
+	Name            string
+	Status          string
+	LastPriceTraded float64
+	Back            []PriceSize
+	Lay             []PriceSize
+}
+
+// Market is a fixture market served by listMarketCatalogue and listMarketBook
+type Market struct {
+	MarketID      string
+	Name          string
+	MarketType    string
+	Venue         string
+	Status        string
+	ScheduledTime time.Time
+	TotalMatched  float64
+	// InPlay and BetDelay, in seconds, are reported by listMarketBook
+	InPlay   bool
+	BetDelay int
+	Runners  []Runner
+}
+
+// Fixture scripts the fake exchange. The same fixture always produces the same
+// responses, so a test run can be replayed exactly.
+type Fixture struct {
+	// Username and Password are checked by login when set
+	Username string
+	Password string
+	// SessionToken is valid from the start; it defaults to "fake-session"
+	SessionToken string
+	Markets      []Market
+	// Seed is the first bet ID issued by placeOrders
+	Seed int64
+	// MatchFraction of each limit order is matched at its price on placement
+	MatchFraction float64
+}
+
+// Order is an order placed on the fake exchange
+type Order struct {
+	BetID         string    `json:"betId"`
+	MarketID      string    `json:"marketId"`
+	SelectionID   uint64    `json:"selectionId"`
+	Side          string    `json:"side"`
+	OrderType     string    `json:"orderType"`
+	Price         float64   `json:"price"`
+	Size          float64   `json:"size"`
+	SizeMatched   float64   `json:"sizeMatched"`
+	SizeRemaining float64   `json:"sizeRemaining"`
+	SizeCancelled float64   `json:"sizeCancelled"`
+	Status        string    `json:"status"`
+	PlacedDate    time.Time `json:"placedDate"`
+}
+
+// Call is a request received by the fake
+type Call struct {
+	Method string
+	Params map[string]interface{}
+	// SessionToken is the X-Authentication header sent with the call
+	SessionToken string
+}
+
+type rpcRequest struct {
+	JSONRPC string                 `json:"jsonrpc"`
+	Method  string                 `json:"method"`
+	Params  map[string]interface{} `json:"params"`
+	ID      int                    `json:"id"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Data    string `json:"data,omitempty"`
+}
+
+type rpcResponse struct {
+	JSONRPC string      `json:"jsonrpc"`
+	Result  interface{} `json:"result,omitempty"`
+	Error   *rpcError   `json:"error,omitempty"`
+	ID      int         `json:"id"`
+}
+
+// FakeServer is a fake Betfair exchange serving login, keepAlive and the
+// JSON-RPC betting methods from a Fixture
+type FakeServer struct {
+	*httptest.Server
+
+	mu        sync.Mutex
+	fixture   Fixture
+	token     string
+	logins    int
+	nextBetID int64
+	orders    []*Order
+	failures  map[string][]string
+	calls     []Call
+}
+
+// NewFakeServer starts a fake exchange serving fixture. Close it when done.
+func NewFakeServer(fixture Fixture) *FakeServer {
+	f := &FakeServer{
+		fixture:   fixture,
+		token:     fixture.SessionToken,
+		nextBetID: fixture.Seed,
+		failures:  make(map[string][]string),
+	}
+	if f.token == "" {
+		f.token = defaultSessionToken
+	}
+	if f.nextBetID <= 0 {
+		f.nextBetID = 1
+	}
+	f.Server = httptest.NewServer(http.HandlerFunc(f.serveHTTP))
+	return f
+}
+
+// SessionToken returns the currently valid session token
+func (f *FakeServer) SessionToken() string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.token
+}
+
+// ExpireSession invalidates the current session token. JSON-RPC calls and
+// keepAlive fail until the next login.
+func (f *FakeServer) ExpireSession() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.token = ""
+}
+
+// FailNext makes the next call to method fail with errorCode. Queued failures
+// are returned in order.
+func (f *FakeServer) FailNext(method, errorCode string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.failures[method] = append(f.failures[method], errorCode)
+}
+
+// Calls returns the calls received for method, in order
+func (f *FakeServer) Calls(method string) []Call {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var calls []Call
+	for _, call := range f.calls {
+		if call.Method == method {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+// Orders returns a copy of every order placed
+func (f *FakeServer) Orders() []Order {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	orders := make([]Order, len(f.orders))
+	for i, order := range f.orders {
+		orders[i] = *order
+	}
+	return orders
+}
+
+func (f *FakeServer) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case LoginPath:
+		f.serveLogin(w, r)
+	case KeepAlivePath:
+		f.serveKeepAlive(w, r)
+	default:
+		f.serveRPC(w, r)
+	}
+}
+
+func (f *FakeServer) serveLogin(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, Call{Method: "login"})
+
+	if code, ok := f.popFailureLocked("login"); ok {
+		writeJSON(w, map[string]string{"loginStatus": code})
+		return
+	}
+	if (f.fixture.Username != "" && r.PostForm.Get("username") != f.fixture.Username) ||
+		(f.fixture.Password != "" && r.PostForm.Get("password") != f.fixture.Password) {
+		writeJSON(w, map[string]string{"loginStatus": "INVALID_USERNAME_OR_PASSWORD"})
+		return
+	}
+
+	// Each login issues a fresh token
+	f.logins++
+	f.token = orDefault(f.fixture.SessionToken, defaultSessionToken) + "-" + strconv.Itoa(f.logins)
+	writeJSON(w, map[string]string{"sessionToken": f.token, "loginStatus": "SUCCESS"})
+}
+
+func (f *FakeServer) serveKeepAlive(w http.ResponseWriter, r *http.Request) {
+	token := r.Header.Get("X-Authentication")
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, Call{Method: "keepAlive", SessionToken: token})
+
+	errorCode, failed := f.popFailureLocked("keepAlive")
+	if !failed && (f.token == "" || token != f.token) {
+		errorCode, failed = "NO_SESSION", true
+	}
+	if failed {
+		writeJSON(w, map[string]string{"token": token, "product": r.Header.Get("X-Application"), "status": "FAIL", "error": errorCode})
+		return
+	}
+	writeJSON(w, map[string]string{"token": token, "product": r.Header.Get("X-Application"), "status": "SUCCESS", "error": ""})
+}
+
+func (f *FakeServer) serveRPC(w http.ResponseWriter, r *http.Request) {
+	var body bytes.Buffer
+	if _, err := body.ReadFrom(r.Body); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	token := r.Header.Get("X-Authentication")
+
+	// Batched requests arrive as an array
+	if trimmed := bytes.TrimSpace(body.Bytes()); len(trimmed) > 0 && trimmed[0] == '[' {
+		var reqs []rpcRequest
+		if err := json.Unmarshal(trimmed, &reqs); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		resps := make([]rpcResponse, len(reqs))
+		for i, req := range reqs {
+			resps[i] = f.handle(req, token)
+		}
+		writeJSON(w, resps)
+		return
+	}
+
+	var req rpcRequest
+	if err := json.Unmarshal(body.Bytes(), &req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	writeJSON(w, f.handle(req, token))
+}
+
+// handle answers one JSON-RPC request
+func (f *FakeServer) handle(req rpcRequest, token string) rpcResponse {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, Call{Method: req.Method, Params: req.Params, SessionToken: token})
+
+	resp := rpcResponse{JSONRPC: "2.0", ID: req.ID}
+	if f.token == "" || token != f.token {
+		resp.Error = apiError(ErrorInvalidSession)
+		return resp
+	}
+	if code, ok := f.popFailureLocked(req.Method); ok {
+		resp.Error = apiError(code)
+		return resp
+	}
+
+	switch req.Method {
+	case "listMarketCatalogue":
+		resp.Result = f.catalogues(req.Params)
+	case "listMarketBook":
+		resp.Result = f.books(req.Params)
+	case "placeOrders":
+		resp.Result = f.placeOrders(req.Params)
+	case "cancelOrders":
+		resp.Result = f.cancelOrders(req.Params)
+	case "listCurrentOrders":
+		resp.Result = f.currentOrders(req.Params)
+	default:
+		resp.Error = &rpcError{Code: -32601, Message: "DSC-0021", Data: "NO_SUCH_OPERATION"}
+	}
+	return resp
+}
+
+func (f *FakeServer) popFailureLocked(method string) (string, bool) {
+	queued := f.failures[method]
+	if len(queued) == 0 {
+		return "", false
+	}
+	f.failures[method] = queued[1:]
+	return queued[0], true
+}
+
+func apiError(code string) *rpcError {
+	return &rpcError{Code: -32099, Message: code, Data: code}
+}
+
+func (f *FakeServer) catalogues(params map[string]interface{}) []map[string]interface{} {
+	filter, _ := params["filter"].(map[string]interface{})
+	wanted := stringSet(filter["marketIds"])
+
+	catalogues := []map[string]interface{}{}
+	for _, market := range f.fixture.Markets {
+		if wanted != nil && !wanted[market.MarketID] {
+			continue
+		}
+		runners := make([]map[string]interface{}, len(market.Runners))
+		for i, runner := range market.Runners {
+			runners[i] = map[string]interface{}{"selectionId": runner.SelectionID, "runnerName": runner.Name}
+		}
+		catalogues = append(catalogues, map[string]interface{}{
+			"marketId":     market.MarketID,
+			"marketName":   market.Name,
+			"totalMatched": market.TotalMatched,
+			"description": map[string]interface{}{
+				"marketType":    market.MarketType,
+				"scheduledTime": market.ScheduledTime,
+			},
+			"event":   map[string]interface{}{"venue": market.Venue},
+			"runners": runners,
+		})
+	}
+	return catalogues
+}
+
+func (f *FakeServer) books(params map[string]interface{}) []map[string]interface{} {
+	wanted := stringSet(params["marketIds"])
+
+	books := []map[string]interface{}{}
+	for _, market := range f.fixture.Markets {
+		if !wanted[market.MarketID] {
+			continue
+		}
+		runners := make([]map[string]interface{}, len(market.Runners))
+		for i, runner := range market.Runners {
+			runners[i] = map[string]interface{}{
+				"selectionId":     runner.SelectionID,
+				"status":          orDefault(runner.Status, "ACTIVE"),
+				"lastPriceTraded": runner.LastPriceTraded,
+				"ex": map[string]interface{}{
+					"availableToBack": orEmpty(runner.Back),
+					"availableToLay":  orEmpty(runner.Lay),
+				},
+			}
+		}
+		books = append(books, map[string]interface{}{
+			"marketId":     market.MarketID,
+			"status":       orDefault(market.Status, "OPEN"),
+			"inplay":       market.InPlay,
+			"betDelay":     market.BetDelay,
+			"totalMatched": market.TotalMatched,
+			"runners":      runners,
+		})
+	}
+	return books
+}
+
+func (f *FakeServer) placeOrders(params map[string]interface{}) map[string]interface{} {
+	marketID, _ := params["marketId"].(string)
+	instructions, _ := params["instructions"].([]interface{})
+
+	now := time.Now().UTC()
+	reports := make([]map[string]interface{}, 0, len(instructions))
+	for _, raw := range instructions {
+		instruction, _ := raw.(map[string]interface{})
+		order := &Order{
+			BetID:      strconv.FormatInt(f.nextBetID, 10),
+			MarketID:   marketID,
+			Side:       stringValue(instruction["side"]),
+			OrderType:  stringValue(instruction["orderType"]),
+			Status:     "EXECUTABLE",
+			PlacedDate: now,
+		}
+		f.nextBetID++
+		if selectionID, ok := instruction["selectionId"].(float64); ok {
+			order.SelectionID = uint64(selectionID)
+		}
+		if limit, ok := instruction["limitOrder"].(map[string]interface{}); ok {
+			order.Price = floatValue(limit["price"])
+			order.Size = floatValue(limit["size"])
+			order.SizeMatched = order.Size * f.fixture.MatchFraction
+		} else if onClose, ok := instruction["marketOnCloseOrder"].(map[string]interface{}); ok {
+			order.Size = floatValue(onClose["liability"])
+		}
+		order.SizeRemaining = order.Size - order.SizeMatched
+		if order.SizeRemaining <= 0 {
+			order.Status = "EXECUTION_COMPLETE"
+		}
+		f.orders = append(f.orders, order)
+
+		report := map[string]interface{}{
+			"status":      "SUCCESS",
+			"orderStatus": order.Status,
+			"betId":       order.BetID,
+			"placedDate":  now,
+			"sizeMatched": order.SizeMatched,
+		}
+		if order.SizeMatched > 0 {
+			report["averagePriceMatched"] = order.Price
+		}
+		reports = append(reports, report)
+	}
+
+	return map[string]interface{}{
+		"marketId":           marketID,
+		"status":             "SUCCESS",
+		"instructionReports": reports,
+	}
+}
+
+func (f *FakeServer) cancelOrders(params map[string]interface{}) map[string]interface{} {
+	marketID, _ := params["marketId"].(string)
+	betIDs := stringSet(params["betIds"])
+
+	reports := []map[string]interface{}{}
+	for _, order := range f.orders {
+		if marketID != "" && order.MarketID != marketID {
+			continue
+		}
+		if betIDs != nil && !betIDs[order.BetID] {
+			continue
+		}
+		if order.SizeRemaining <= 0 {
+			continue
+		}
+		cancelled := order.SizeRemaining
+		order.SizeCancelled += cancelled
+		order.SizeRemaining = 0
+		order.Status = "EXECUTION_COMPLETE"
+		reports = append(reports, map[string]interface{}{
+			"status":        "SUCCESS",
+			"betId":         order.BetID,
+			"sizeCancelled": cancelled,
+		})
+	}
+
+	return map[string]interface{}{
+		"marketId":           marketID,
+		"status":             "SUCCESS",
+		"instructionReports": reports,
+	}
+}
+
+func (f *FakeServer) currentOrders(params map[string]interface{}) map[string]interface{} {
+	wanted := stringSet(params["marketIds"])
+
+	orders := []Order{}
+	for _, order := range f.orders {
+		if wanted != nil && !wanted[order.MarketID] {
+			continue
+		}
+		orders = append(orders, *order)
+	}
+	return map[string]interface{}{"currentOrders": orders, "moreAvailable": false}
+}
+
+func writeJSON(w http.ResponseWriter, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(value)
+}
+
+// stringSet collects a JSON string array. It returns nil when the value is
+// absent or empty, meaning no filter.
+func stringSet(value interface{}) map[string]bool {
+	items, _ := value.([]interface{})
+	if len(items) == 0 {
+		return nil
+	}
+	set := make(map[string]bool, len(items))
+	for _, item := range items {
+		if s, ok := item.(string); ok {
+			set[s] = true
+		}
+	}
+	return set
+}
+
+func stringValue(value interface{}) string {
+	s, _ := value.(string)
+	return s
+}
+
+func floatValue(value interface{}) float64 {
+	v, _ := value.(float64)
+	return v
+}
+
+func orDefault(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
+
+func orEmpty(levels []PriceSize) []PriceSize {
+	if levels == nil {
+		return []PriceSize{}
+	}
+	return levels
+}
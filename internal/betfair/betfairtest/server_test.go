@@ -0,0 +1,232 @@
+package betfairtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testFixture() Fixture {
+	return Fixture{
+		Username: "user",
+		Password: "secret",
+		Seed:     5000,
+		Markets: []Market{
+			{
+				MarketID: "1.100",
+				Name:     "A1 480m",
+				Venue:    "Romford",
+				Runners: []Runner{
+					{SelectionID: 11, Name: "1. Fast", Back: []PriceSize{{Price: 3.0, Size: 50}}, Lay: []PriceSize{{Price: 3.1, Size: 40}}},
+					{SelectionID: 12, Name: "2. Slow"},
+				},
+			},
+			{MarketID: "1.200", Name: "A2 480m", Venue: "Hove"},
+		},
+	}
+}
+
+// call posts a JSON-RPC request and returns its result, failing on an error
+func call(t *testing.T, server *FakeServer, token, method string, params map[string]interface{}) json.RawMessage {
+	t.Helper()
+	resp := rawCall(t, server, token, method, params)
+	require.Nil(t, resp.Error, "unexpected error: %+v", resp.Error)
+	return resp.Result
+}
+
+type testResponse struct {
+	Result json.RawMessage `json:"result"`
+	Error  *rpcError       `json:"error"`
+	ID     int             `json:"id"`
+}
+
+func rawCall(t *testing.T, server *FakeServer, token, method string, params map[string]interface{}) testResponse {
+	t.Helper()
+	body, err := json.Marshal(rpcRequest{JSONRPC: "2.0", Method: method, Params: params, ID: 1})
+	require.NoError(t, err)
+	req, err := http.NewRequest(http.MethodPost, server.URL, bytes.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set("X-Authentication", token)
+	httpResp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer httpResp.Body.Close()
+
+	var resp testResponse
+	require.NoError(t, json.NewDecoder(httpResp.Body).Decode(&resp))
+	return resp
+}
+
+func TestFakeServerServesMarkets(t *testing.T) {
+	server := NewFakeServer(testFixture())
+	defer server.Close()
+	token := server.SessionToken()
+
+	var catalogues []struct {
+		MarketID string `json:"marketId"`
+		Runners  []struct {
+			SelectionID uint64 `json:"selectionId"`
+		} `json:"runners"`
+	}
+	require.NoError(t, json.Unmarshal(call(t, server, token, "listMarketCatalogue", map[string]interface{}{}), &catalogues))
+	require.Len(t, catalogues, 2)
+	assert.Len(t, catalogues[0].Runners, 2)
+
+	filtered := call(t, server, token, "listMarketCatalogue", map[string]interface{}{"filter": map[string]interface{}{"marketIds": []string{"1.200"}}})
+	require.NoError(t, json.Unmarshal(filtered, &catalogues))
+	require.Len(t, catalogues, 1)
+	assert.Equal(t, "1.200", catalogues[0].MarketID)
+
+	var books []struct {
+		MarketID string `json:"marketId"`
+		Status   string `json:"status"`
+		Runners  []struct {
+			SelectionID uint64 `json:"selectionId"`
+			Ex          struct {
+				AvailableToBack []PriceSize `json:"availableToBack"`
+			} `json:"ex"`
+		} `json:"runners"`
+	}
+	require.NoError(t, json.Unmarshal(call(t, server, token, "listMarketBook", map[string]interface{}{"marketIds": []string{"1.100"}}), &books))
+	require.Len(t, books, 1)
+	assert.Equal(t, "OPEN", books[0].Status)
+	assert.Equal(t, []PriceSize{{Price: 3.0, Size: 50}}, books[0].Runners[0].Ex.AvailableToBack)
+	assert.Len(t, server.Calls("listMarketBook"), 1)
+}
+
+func TestFakeServerPlacesListsAndCancelsOrders(t *testing.T) {
+	fixture := testFixture()
+	fixture.MatchFraction = 0.25
+	server := NewFakeServer(fixture)
+	defer server.Close()
+	token := server.SessionToken()
+
+	place := map[string]interface{}{
+		"marketId": "1.100",
+		"instructions": []map[string]interface{}{
+			{"orderType": "LIMIT", "selectionId": 11, "side": "BACK", "limitOrder": map[string]interface{}{"size": 20.0, "price": 3.0}},
+			{"orderType": "LIMIT", "selectionId": 12, "side": "LAY", "limitOrder": map[string]interface{}{"size": 8.0, "price": 5.0}},
+		},
+	}
+	var placed struct {
+		Status             string `json:"status"`
+		InstructionReports []struct {
+			BetID       string  `json:"betId"`
+			SizeMatched float64 `json:"sizeMatched"`
+		} `json:"instructionReports"`
+	}
+	require.NoError(t, json.Unmarshal(call(t, server, token, "placeOrders", place), &placed))
+	assert.Equal(t, "SUCCESS", placed.Status)
+	require.Len(t, placed.InstructionReports, 2)
+	assert.Equal(t, "5000", placed.InstructionReports[0].BetID, "bet IDs start at the seed")
+	assert.Equal(t, "5001", placed.InstructionReports[1].BetID)
+	assert.Equal(t, 5.0, placed.InstructionReports[0].SizeMatched)
+
+	var current struct {
+		CurrentOrders []Order `json:"currentOrders"`
+	}
+	require.NoError(t, json.Unmarshal(call(t, server, token, "listCurrentOrders", map[string]interface{}{"marketIds": []string{"1.100"}}), &current))
+	require.Len(t, current.CurrentOrders, 2)
+	assert.Equal(t, 15.0, current.CurrentOrders[0].SizeRemaining)
+
+	call(t, server, token, "cancelOrders", map[string]interface{}{"marketId": "1.100", "betIds": []string{"5000"}})
+	orders := server.Orders()
+	assert.Equal(t, 15.0, orders[0].SizeCancelled)
+	assert.Zero(t, orders[0].SizeRemaining)
+	assert.Equal(t, "EXECUTION_COMPLETE", orders[0].Status)
+	assert.Equal(t, 6.0, orders[1].SizeRemaining, "other bets are untouched")
+
+	// The same fixture replays identically
+	replay := NewFakeServer(fixture)
+	defer replay.Close()
+	require.NoError(t, json.Unmarshal(call(t, replay, replay.SessionToken(), "placeOrders", place), &placed))
+	assert.Equal(t, "5000", placed.InstructionReports[0].BetID)
+}
+
+func TestFakeServerScriptedErrors(t *testing.T) {
+	server := NewFakeServer(testFixture())
+	defer server.Close()
+	token := server.SessionToken()
+
+	server.FailNext("placeOrders", ErrorInsufficientFunds)
+	resp := rawCall(t, server, token, "placeOrders", map[string]interface{}{"marketId": "1.100"})
+	require.NotNil(t, resp.Error)
+	assert.Equal(t, ErrorInsufficientFunds, resp.Error.Data)
+	assert.Nil(t, rawCall(t, server, token, "placeOrders", map[string]interface{}{"marketId": "1.100"}).Error, "failures are one-shot")
+
+	resp = rawCall(t, server, token, "noSuchMethod", nil)
+	require.NotNil(t, resp.Error)
+	assert.Equal(t, "NO_SUCH_OPERATION", resp.Error.Data)
+}
+
+func TestFakeServerSessionExpiryAndLogin(t *testing.T) {
+	server := NewFakeServer(testFixture())
+	defer server.Close()
+	token := server.SessionToken()
+
+	keepAlive := func(token string) map[string]string {
+		req, err := http.NewRequest(http.MethodPost, server.URL+KeepAlivePath, nil)
+		require.NoError(t, err)
+		req.Header.Set("X-Authentication", token)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		var body map[string]string
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+		return body
+	}
+	login := func(password string) map[string]string {
+		resp, err := http.PostForm(server.URL+LoginPath, url.Values{"username": {"user"}, "password": {password}})
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		var body map[string]string
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+		return body
+	}
+
+	assert.Equal(t, "SUCCESS", keepAlive(token)["status"])
+
+	server.ExpireSession()
+	resp := rawCall(t, server, token, "listMarketCatalogue", nil)
+	require.NotNil(t, resp.Error)
+	assert.Equal(t, ErrorInvalidSession, resp.Error.Data)
+	assert.Equal(t, "NO_SESSION", keepAlive(token)["error"])
+
+	assert.Equal(t, "INVALID_USERNAME_OR_PASSWORD", login("wrong")["loginStatus"])
+	loggedIn := login("secret")
+	require.Equal(t, "SUCCESS", loggedIn["loginStatus"])
+	newToken := loggedIn["sessionToken"]
+	assert.NotEqual(t, token, newToken)
+	assert.Equal(t, newToken, server.SessionToken())
+
+	assert.Nil(t, rawCall(t, server, newToken, "listMarketCatalogue", nil).Error)
+	assert.NotNil(t, rawCall(t, server, token, "listMarketCatalogue", nil).Error, "the old token stays invalid")
+	assert.Len(t, server.Calls("login"), 2)
+}
+
+func TestFakeServerAnswersBatchesByID(t *testing.T) {
+	server := NewFakeServer(testFixture())
+	defer server.Close()
+
+	body, err := json.Marshal([]rpcRequest{
+		{JSONRPC: "2.0", Method: "listMarketCatalogue", Params: map[string]interface{}{}, ID: 7},
+		{JSONRPC: "2.0", Method: "listMarketBook", Params: map[string]interface{}{"marketIds": []string{"1.100"}}, ID: 9},
+	})
+	require.NoError(t, err)
+	req, err := http.NewRequest(http.MethodPost, server.URL, bytes.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set("X-Authentication", server.SessionToken())
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	var resps []testResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&resps))
+	require.Len(t, resps, 2)
+	assert.Equal(t, 7, resps[0].ID)
+	assert.Equal(t, 9, resps[1].ID)
+}
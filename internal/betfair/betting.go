@@ -3,8 +3,10 @@ package betfair
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"math"
 	"time"
 
 	"github.com/yourusername/clever-better/internal/models"
@@ -36,6 +38,7 @@ type PlaceInstruction struct {
 	Side           string     `json:"side"`
 	LimitOrder     *LimitOrder `json:"limitOrder,omitempty"`
 	LimitOnClose   *LimitOnClose `json:"limitOnCloseOrder,omitempty"`
+	MarketOnClose  *MarketOnClose `json:"marketOnCloseOrder,omitempty"`
 }
 
 // LimitOrder represents a limit order
@@ -54,6 +57,11 @@ type LimitOnClose struct {
 	Price     float64 `json:"price"`
 }
 
+// MarketOnClose represents a market on close (BSP) order
+type MarketOnClose struct {
+	Liability float64 `json:"liability"`
+}
+
 // PlaceOrdersRequest represents bet placement request
 type PlaceOrdersRequest struct {
 	MarketID         string              `json:"marketId"`
@@ -68,12 +76,16 @@ type PlaceOrdersResponse struct {
 	MarketID      string            `json:"marketId"`
 	Status        string            `json:"status"`
 	InstructionReports []InstructionReport `json:"instructionReports"`
+	ErrorCode     string            `json:"errorCode,omitempty"`
 	PlaceOrdersErrors []string `json:"placeOrdersErrors,omitempty"`
 }
 
-// InstructionReport represents result of a single bet placement
+// InstructionReport represents result of a single bet placement.
+// Instruction echoes the instruction it reports on.
 type InstructionReport struct {
 	Status          string          `json:"status"`
+	ErrorCode       string          `json:"errorCode,omitempty"`
+	Instruction     *PlaceInstruction `json:"instruction,omitempty"`
 	OrderStatus     string          `json:"orderStatus"`
 	BetID           string          `json:"betId"`
 	PlacedDate      *time.Time      `json:"placedDate"`
@@ -132,9 +144,28 @@ func (b *BettingService) PlaceBet(
 	stake float64,
 	side string,
 ) (string, error) {
+	report, err := b.PlaceBetWithReport(ctx, marketID, selectionID, price, stake, side)
+	if err != nil {
+		return "", err
+	}
+	return report.BetID, nil
+}
+
+// PlaceBetWithReport places a single bet and returns Betfair's instruction
+// report. The report's average price and size matched show what was filled
+// on placement, which may be at a better price than requested or only part
+// of the stake.
+func (b *BettingService) PlaceBetWithReport(
+	ctx context.Context,
+	marketID string,
+	selectionID uint64,
+	price float64,
+	stake float64,
+	side string,
+) (*InstructionReport, error) {
 	// Validate parameters
 	if err := b.validateBet(price, stake, side); err != nil {
-		return "", err
+		return nil, err
 	}
 
 	instruction := PlaceInstruction{
@@ -147,43 +178,58 @@ func (b *BettingService) PlaceBet(
 		},
 	}
 
-	request := PlaceOrdersRequest{
-		MarketID:     marketID,
-		Instructions: []PlaceInstruction{instruction},
-		OrderMode:    "EXECUTE",
+	outcomes, err := b.PlaceOrders(ctx, marketID, []PlaceInstruction{instruction})
+	if err != nil {
+		return nil, err
 	}
-
-	params := map[string]interface{}{
-		"marketId":     marketID,
-		"instructions": []PlaceInstruction{instruction},
-		"orderMode":    "EXECUTE",
+	outcome := outcomes[0]
+	if !outcome.Placed() {
+		return nil, fmt.Errorf("bet placement failed: %w", outcome.Err)
 	}
+	report := *outcome.Report
 
-	result, err := b.client.makeRequest(ctx, "placeOrders", params)
-	if err != nil {
-		b.logger.Printf("Failed to place bet: %v", err)
-		return "", err
-	}
+	b.logger.Printf("Bet placed successfully: betId=%s, price=%.2f, stake=%.2f, matched=%.2f@%.2f",
+		report.BetID, price, stake, report.SizeMatched, report.AveragePriceMatched)
+	return &report, nil
+}
 
-	var resp PlaceOrdersResponse
-	if err := json.Unmarshal(result, &resp); err != nil {
-		return "", fmt.Errorf("failed to parse place orders response: %w", err)
+// PlaceBSPBet places a MARKET_ON_CLOSE bet that is matched at the Betfair
+// Starting Price. For back bets liability is the stake; for lay bets it is the
+// maximum amount the lay may lose.
+func (b *BettingService) PlaceBSPBet(
+	ctx context.Context,
+	marketID string,
+	selectionID uint64,
+	liability float64,
+	side string,
+) (string, error) {
+	if liability < b.config.MinStake || liability > b.config.MaxStake {
+		return "", fmt.Errorf("invalid liability: %.2f (must be between %.2f and %.2f)", liability, b.config.MinStake, b.config.MaxStake)
 	}
-
-	if resp.Status != "SUCCESS" {
-		return "", fmt.Errorf("bet placement failed: status=%s, errors=%v", resp.Status, resp.PlaceOrdersErrors)
+	if side != "BACK" && side != "LAY" {
+		return "", fmt.Errorf("invalid side: %s (must be BACK or LAY)", side)
 	}
 
-	if len(resp.InstructionReports) == 0 {
-		return "", fmt.Errorf("no instruction reports in response")
+	instruction := PlaceInstruction{
+		OrderType:   "MARKET_ON_CLOSE",
+		SelectionID: selectionID,
+		Side:        side,
+		MarketOnClose: &MarketOnClose{
+			Liability: liability,
+		},
 	}
 
-	report := resp.InstructionReports[0]
-	if report.Status != "SUCCESS" {
-		return "", fmt.Errorf("instruction failed: %s", report.Status)
+	outcomes, err := b.PlaceOrders(ctx, marketID, []PlaceInstruction{instruction})
+	if err != nil {
+		return "", err
 	}
+	outcome := outcomes[0]
+	if !outcome.Placed() {
+		return "", fmt.Errorf("BSP bet placement failed: %w", outcome.Err)
+	}
+	report := outcome.Report
 
-	b.logger.Printf("Bet placed successfully: betId=%s, price=%.2f, stake=%.2f", report.BetID, price, stake)
+	b.logger.Printf("BSP bet placed successfully: betId=%s, liability=%.2f, side=%s", report.BetID, liability, side)
 	return report.BetID, nil
 }
 
@@ -210,6 +256,111 @@ func (b *BettingService) ListCurrentOrders(ctx context.Context, marketIDs []stri
 	return response.CurrentOrders, nil
 }
 
+// AccountFunds is the wallet balance reported by getAccountFunds. Exposure is
+// negative: the most the open bets can lose.
+type AccountFunds struct {
+	AvailableToBetBalance float64 `json:"availableToBetBalance"`
+	Exposure              float64 `json:"exposure"`
+	RetainedCommission    float64 `json:"retainedCommission"`
+	ExposureLimit         float64 `json:"exposureLimit"`
+	Wallet                string  `json:"wallet"`
+}
+
+// GetAccountFunds fetches the account's available balance and exposure
+func (b *BettingService) GetAccountFunds(ctx context.Context) (*AccountFunds, error) {
+	result, err := b.client.makeRequest(ctx, "getAccountFunds", map[string]interface{}{})
+	if err != nil {
+		b.logger.Printf("Failed to get account funds: %v", err)
+		return nil, err
+	}
+
+	var funds AccountFunds
+	if err := json.Unmarshal(result, &funds); err != nil {
+		return nil, fmt.Errorf("failed to parse account funds response: %w", err)
+	}
+
+	return &funds, nil
+}
+
+// GetMarketLiquidity returns the total amount matched on a market
+func (b *BettingService) GetMarketLiquidity(ctx context.Context, marketID string) (float64, error) {
+	return b.client.GetMarketLiquidity(ctx, marketID)
+}
+
+// GetMarketState returns a market's current status and bet delay
+func (b *BettingService) GetMarketState(ctx context.Context, marketID string) (MarketState, error) {
+	return b.client.GetMarketState(ctx, marketID)
+}
+
+// GetBestPrice returns the best price currently available to a side on a
+// selection: the top of availableToBack for BACK and availableToLay for LAY
+func (b *BettingService) GetBestPrice(ctx context.Context, marketID string, selectionID uint64, side string) (float64, error) {
+	books, err := b.client.ListMarketBook(ctx, []string{marketID}, []string{"EX_BEST_OFFERS"})
+	if err != nil {
+		return 0, err
+	}
+	if len(books) == 0 {
+		return 0, fmt.Errorf("no market book data returned")
+	}
+
+	for _, runner := range books[0].Runners {
+		if runner.SelectionID != selectionID {
+			continue
+		}
+		offers := runner.ExchangePrices.AvailableToBack
+		if side == "LAY" {
+			offers = runner.ExchangePrices.AvailableToLay
+		}
+		if len(offers) == 0 {
+			return 0, fmt.Errorf("no %s prices available for selection %d", side, selectionID)
+		}
+		return offers[0].Price, nil
+	}
+
+	return 0, fmt.Errorf("selection %d not found in market %s", selectionID, marketID)
+}
+
+// bestOffersDepth is how many price levels EX_BEST_OFFERS returns
+const bestOffersDepth = 3
+
+// GetAvailableSize returns the size offered to a side on a selection across
+// the best maxLevels price levels. Zero or less sums the whole ladder.
+func (b *BettingService) GetAvailableSize(ctx context.Context, marketID string, selectionID uint64, side string, maxLevels int) (float64, error) {
+	priceData := "EX_BEST_OFFERS"
+	if maxLevels <= 0 || maxLevels > bestOffersDepth {
+		priceData = "EX_ALL_OFFERS"
+	}
+	books, err := b.client.ListMarketBook(ctx, []string{marketID}, []string{priceData})
+	if err != nil {
+		return 0, err
+	}
+	if len(books) == 0 {
+		return 0, fmt.Errorf("no market book data returned")
+	}
+
+	for _, runner := range books[0].Runners {
+		if runner.SelectionID != selectionID {
+			continue
+		}
+		return toPriceLadder(runner.ExchangePrices.AvailableToBack, runner.ExchangePrices.AvailableToLay, side).Depth(maxLevels), nil
+	}
+
+	return 0, fmt.Errorf("selection %d not found in market %s", selectionID, marketID)
+}
+
+// toPriceLadder converts the offers on a side to a price ladder
+func toPriceLadder(back, lay []PriceSize, side string) models.PriceLadder {
+	offers := back
+	if side == "LAY" {
+		offers = lay
+	}
+	ladder := make(models.PriceLadder, 0, len(offers))
+	for _, offer := range offers {
+		ladder = append(ladder, models.PriceLevel{Price: offer.Price, Size: offer.Size})
+	}
+	return ladder
+}
+
 // CurrentOrderResponse represents current order information from Betfair
 type CurrentOrderResponse struct {
 	BetID           string    `json:"betId"`
@@ -217,6 +368,7 @@ type CurrentOrderResponse struct {
 	SelectionID     uint64    `json:"selectionId"`
 	Price           float64   `json:"price"`
 	Size            float64   `json:"size"`
+	Side            string    `json:"side"`
 	SideMatched     string    `json:"bspLiability"`
 	Status          string    `json:"status"`
 	PlacedDate      time.Time `json:"placedDate"`
@@ -262,6 +414,150 @@ func (b *BettingService) CancelOrders(
 	return nil
 }
 
+// CancelBet cancels a single unmatched bet
+func (b *BettingService) CancelBet(ctx context.Context, marketID, betID string) error {
+	return b.CancelOrders(ctx, marketID, []string{betID})
+}
+
+// ReplaceOrder moves the unmatched part of a bet to a new price. Betfair
+// cancels the original and places a new bet, whose ID is returned.
+func (b *BettingService) ReplaceOrder(ctx context.Context, marketID, betID string, newPrice float64) (string, error) {
+	if newPrice < 1.01 || newPrice > 1000.0 {
+		return "", fmt.Errorf("invalid price: %.2f (must be between 1.01 and 1000)", newPrice)
+	}
+
+	params := map[string]interface{}{
+		"marketId": marketID,
+		"instructions": []map[string]interface{}{
+			{"betId": betID, "newPrice": newPrice},
+		},
+	}
+
+	result, err := b.client.makeRequest(ctx, "replaceOrders", params)
+	if err != nil {
+		b.logger.Printf("Failed to replace order: %v", err)
+		return "", err
+	}
+
+	var response struct {
+		Status             string `json:"status"`
+		ErrorCode          string `json:"errorCode"`
+		InstructionReports []struct {
+			Status                 string            `json:"status"`
+			PlaceInstructionReport InstructionReport `json:"placeInstructionReport"`
+		} `json:"instructionReports"`
+	}
+
+	if err := json.Unmarshal(result, &response); err != nil {
+		return "", fmt.Errorf("failed to parse replace response: %w", err)
+	}
+
+	if response.Status != "SUCCESS" || len(response.InstructionReports) == 0 {
+		return "", fmt.Errorf("replace failed: status=%s, error=%s", response.Status, response.ErrorCode)
+	}
+
+	newBetID := response.InstructionReports[0].PlaceInstructionReport.BetID
+	if newBetID == "" {
+		return "", fmt.Errorf("replace returned no bet ID")
+	}
+
+	b.logger.Printf("Replaced bet %s with %s at %.2f on market %s", betID, newBetID, newPrice, marketID)
+	return newBetID, nil
+}
+
+// CancelAllForMarket cancels every order on a market with unmatched size and
+// returns how many were cancelled. Matched portions are left in place.
+func (b *BettingService) CancelAllForMarket(ctx context.Context, marketID string) (int, error) {
+	orders, err := b.ListCurrentOrders(ctx, []string{marketID})
+	if err != nil {
+		return 0, fmt.Errorf("failed to list current orders: %w", err)
+	}
+
+	betIDs := make([]string, 0, len(orders))
+	for _, order := range orders {
+		if order.MarketID != "" && order.MarketID != marketID {
+			continue
+		}
+		if order.SizeRemaining <= 0 {
+			continue
+		}
+		betIDs = append(betIDs, order.BetID)
+	}
+
+	if len(betIDs) == 0 {
+		return 0, nil
+	}
+
+	if err := b.CancelOrders(ctx, marketID, betIDs); err != nil {
+		return 0, fmt.Errorf("failed to cancel orders on market %s: %w", marketID, err)
+	}
+
+	return len(betIDs), nil
+}
+
+// GreenUpMarket hedges each matched order on a market at the best opposite
+// price, so the position returns the same whichever runner wins. It returns
+// how many hedges were placed.
+func (b *BettingService) GreenUpMarket(ctx context.Context, marketID string) (int, error) {
+	orders, err := b.ListCurrentOrders(ctx, []string{marketID})
+	if err != nil {
+		return 0, fmt.Errorf("failed to list current orders: %w", err)
+	}
+
+	books, err := b.client.ListMarketBook(ctx, []string{marketID}, []string{"EX_BEST_OFFERS"})
+	if err != nil {
+		return 0, fmt.Errorf("failed to get market book: %w", err)
+	}
+	if len(books) == 0 {
+		return 0, fmt.Errorf("no market book data returned")
+	}
+
+	runners := make(map[uint64]Runner, len(books[0].Runners))
+	for _, runner := range books[0].Runners {
+		runners[runner.SelectionID] = runner
+	}
+
+	hedged := 0
+	var errs []error
+	for _, order := range orders {
+		if order.SizeMatched <= 0 || order.AveragePriceMatched <= 1 {
+			continue
+		}
+
+		side, offers := "LAY", runners[order.SelectionID].ExchangePrices.AvailableToLay
+		if order.Side == "LAY" {
+			side, offers = "BACK", runners[order.SelectionID].ExchangePrices.AvailableToBack
+		}
+		if len(offers) == 0 {
+			errs = append(errs, fmt.Errorf("no %s price for selection %d", side, order.SelectionID))
+			continue
+		}
+
+		price := offers[0].Price
+		stake := math.Round(order.SizeMatched*order.AveragePriceMatched/price*100) / 100
+		if _, err := b.PlaceBet(ctx, marketID, order.SelectionID, price, stake, side); err != nil {
+			errs = append(errs, fmt.Errorf("failed to hedge bet %s: %w", order.BetID, err))
+			continue
+		}
+		hedged++
+	}
+
+	if len(errs) > 0 {
+		return hedged, errors.Join(errs...)
+	}
+
+	b.logger.Printf("Greened up %d matched bets on market %s", hedged, marketID)
+	return hedged, nil
+}
+
+// HealthCheck reports whether the Betfair session is usable
+func (b *BettingService) HealthCheck(ctx context.Context) error {
+	if !b.client.IsAuthenticated() {
+		return fmt.Errorf("betfair session is not authenticated")
+	}
+	return nil
+}
+
 // UpdateBetStatus updates bet status in database from Betfair
 func (b *BettingService) UpdateBetStatus(ctx context.Context, bet *models.Bet) error {
 	return b.betRepository.Update(ctx, bet)
@@ -0,0 +1,210 @@
+package betfair
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yourusername/clever-better/internal/config"
+	"github.com/yourusername/clever-better/internal/datasource"
+)
+
+// fakeExchange serves canned JSON-RPC results and records requests by method
+type fakeExchange struct {
+	results  map[string]interface{}
+	mu       sync.Mutex
+	requests map[string][]map[string]interface{}
+}
+
+func (f *fakeExchange) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Method string                 `json:"method"`
+		Params map[string]interface{} `json:"params"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	f.mu.Lock()
+	f.requests[req.Method] = append(f.requests[req.Method], req.Params)
+	f.mu.Unlock()
+
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"result":  f.results[req.Method],
+		"id":      1,
+	})
+}
+
+func newTestClient(t *testing.T, exchange *fakeExchange) *BetfairClient {
+	t.Helper()
+	exchange.requests = make(map[string][]map[string]interface{})
+	server := httptest.NewServer(exchange)
+	t.Cleanup(server.Close)
+
+	logger := log.New(io.Discard, "", 0)
+	httpCfg := datasource.DefaultHTTPClientConfig()
+	httpCfg.MaxRetries = 0
+	httpCfg.RateLimit = 1000
+	client := NewBetfairClient(
+		&config.BetfairConfig{APIURL: server.URL, AppKey: "test-app-key"},
+		datasource.NewRateLimitedHTTPClient(httpCfg, logger),
+		logger,
+	)
+	client.SetSessionToken("test-session", time.Now().Add(time.Hour))
+	return client
+}
+
+func newTestBettingService(t *testing.T, exchange *fakeExchange) *BettingService {
+	t.Helper()
+	return NewBettingService(newTestClient(t, exchange), nil, BettingConfig{MaxStake: 100}, log.New(io.Discard, "", 0))
+}
+
+func TestCancelAllForMarket(t *testing.T) {
+	exchange := &fakeExchange{results: map[string]interface{}{
+		"listCurrentOrders": map[string]interface{}{
+			"currentOrders": []map[string]interface{}{
+				{"betId": "1", "marketId": "1.234", "selectionId": 11, "status": "EXECUTABLE", "sizeRemaining": 5.0},
+				{"betId": "2", "marketId": "1.234", "selectionId": 12, "status": "EXECUTABLE", "sizeMatched": 2.0, "sizeRemaining": 3.0},
+				{"betId": "3", "marketId": "1.234", "selectionId": 13, "status": "EXECUTION_COMPLETE", "sizeMatched": 10.0},
+				{"betId": "4", "marketId": "1.234", "selectionId": 14, "status": "EXECUTABLE", "sizeRemaining": 8.0},
+			},
+		},
+		"cancelOrders": map[string]interface{}{"status": "SUCCESS"},
+	}}
+	service := newTestBettingService(t, exchange)
+
+	cancelled, err := service.CancelAllForMarket(context.Background(), "1.234")
+	require.NoError(t, err)
+	assert.Equal(t, 3, cancelled)
+
+	require.Len(t, exchange.requests["cancelOrders"], 1)
+	cancel := exchange.requests["cancelOrders"][0]
+	assert.Equal(t, "1.234", cancel["marketId"])
+	assert.ElementsMatch(t, []interface{}{"1", "2", "4"}, cancel["betIds"], "fully matched bets must not be cancelled")
+}
+
+func TestCancelAllForMarketWithoutOpenOrders(t *testing.T) {
+	exchange := &fakeExchange{results: map[string]interface{}{
+		"listCurrentOrders": map[string]interface{}{
+			"currentOrders": []map[string]interface{}{
+				{"betId": "3", "marketId": "1.234", "status": "EXECUTION_COMPLETE", "sizeMatched": 10.0},
+			},
+		},
+	}}
+	service := newTestBettingService(t, exchange)
+
+	cancelled, err := service.CancelAllForMarket(context.Background(), "1.234")
+	require.NoError(t, err)
+	assert.Equal(t, 0, cancelled)
+	assert.Empty(t, exchange.requests["cancelOrders"])
+}
+
+func TestCancelAllForMarketCancelFailure(t *testing.T) {
+	exchange := &fakeExchange{results: map[string]interface{}{
+		"listCurrentOrders": map[string]interface{}{
+			"currentOrders": []map[string]interface{}{
+				{"betId": "1", "marketId": "1.234", "status": "EXECUTABLE", "sizeRemaining": 5.0},
+			},
+		},
+		"cancelOrders": map[string]interface{}{"status": "FAILURE"},
+	}}
+	service := newTestBettingService(t, exchange)
+
+	cancelled, err := service.CancelAllForMarket(context.Background(), "1.234")
+	assert.Error(t, err)
+	assert.Equal(t, 0, cancelled)
+}
+
+func TestGreenUpMarketHedgesMatchedBets(t *testing.T) {
+	exchange := &fakeExchange{results: map[string]interface{}{
+		"listCurrentOrders": map[string]interface{}{
+			"currentOrders": []map[string]interface{}{
+				{"betId": "1", "marketId": "1.234", "selectionId": 11, "side": "BACK", "averagePriceMatched": 4.0, "sizeMatched": 10.0},
+				{"betId": "2", "marketId": "1.234", "selectionId": 12, "side": "BACK", "sizeRemaining": 5.0},
+			},
+		},
+		"listMarketBook": []map[string]interface{}{{
+			"marketId": "1.234",
+			"runners": []map[string]interface{}{{
+				"selectionId": 11,
+				"ex": map[string]interface{}{
+					"availableToBack": []map[string]float64{{"price": 3.7, "size": 50}},
+					"availableToLay":  []map[string]float64{{"price": 3.8, "size": 50}},
+				},
+			}},
+		}},
+		"placeOrders": map[string]interface{}{
+			"status":             "SUCCESS",
+			"instructionReports": []map[string]interface{}{{"status": "SUCCESS", "betId": "hedge-1"}},
+		},
+	}}
+	service := newTestBettingService(t, exchange)
+
+	hedged, err := service.GreenUpMarket(context.Background(), "1.234")
+	require.NoError(t, err)
+	assert.Equal(t, 1, hedged, "only matched bets are hedged")
+
+	require.Len(t, exchange.requests["placeOrders"], 1)
+	instructions := exchange.requests["placeOrders"][0]["instructions"].([]interface{})
+	instruction := instructions[0].(map[string]interface{})
+	assert.Equal(t, "LAY", instruction["side"])
+	limit := instruction["limitOrder"].(map[string]interface{})
+	assert.Equal(t, 3.8, limit["price"])
+	assert.InDelta(t, 10.53, limit["size"], 1e-9)
+}
+
+func TestPlaceBetWithReportReturnsMatchedFill(t *testing.T) {
+	exchange := &fakeExchange{results: map[string]interface{}{
+		"placeOrders": map[string]interface{}{
+			"status": "SUCCESS",
+			"instructionReports": []map[string]interface{}{{
+				"status":              "SUCCESS",
+				"betId":               "42",
+				"averagePriceMatched": 3.25,
+				"sizeMatched":         6.0,
+			}},
+		},
+	}}
+	service := newTestBettingService(t, exchange)
+
+	report, err := service.PlaceBetWithReport(context.Background(), "1.234", 11, 3.0, 10, "BACK")
+	require.NoError(t, err)
+	assert.Equal(t, "42", report.BetID)
+	assert.Equal(t, 3.25, report.AveragePriceMatched, "matched at a better price than requested")
+	assert.Equal(t, 6.0, report.SizeMatched, "only part of the stake matched")
+
+	betID, err := service.PlaceBet(context.Background(), "1.234", 11, 3.0, 10, "BACK")
+	require.NoError(t, err)
+	assert.Equal(t, "42", betID)
+}
+
+func TestGetAccountFunds(t *testing.T) {
+	exchange := &fakeExchange{results: map[string]interface{}{
+		"getAccountFunds": map[string]interface{}{
+			"availableToBetBalance": 820.5,
+			"exposure":              -179.5,
+			"retainedCommission":    0.0,
+			"exposureLimit":         -10000.0,
+			"wallet":                "UK",
+		},
+	}}
+	service := newTestBettingService(t, exchange)
+
+	funds, err := service.GetAccountFunds(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 820.5, funds.AvailableToBetBalance)
+	assert.Equal(t, -179.5, funds.Exposure)
+	assert.Equal(t, "UK", funds.Wallet)
+	assert.Len(t, exchange.requests["getAccountFunds"], 1)
+}
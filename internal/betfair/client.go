@@ -7,11 +7,14 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"strings"
 	"sync"
 	"time"
 
+	cache "github.com/patrickmn/go-cache"
 	"github.com/yourusername/clever-better/internal/config"
 	"github.com/yourusername/clever-better/internal/datasource"
+	applogger "github.com/yourusername/clever-better/internal/logger"
 )
 
 // BetfairClient implements Betfair API-NG REST client
@@ -24,9 +27,13 @@ type BetfairClient struct {
 	appKey        string
 	tokenExpiry   time.Time
 	mu            sync.RWMutex
+	catalogCache  *cache.Cache
 	logger        *log.Logger
 }
 
+// defaultCatalogCacheTTL is used when the config does not set a catalog TTL
+const defaultCatalogCacheTTL = 60 * time.Second
+
 // JSONRPCRequest represents a JSON-RPC request
 type JSONRPCRequest struct {
 	JSONRPC string            `json:"jsonrpc"`
@@ -71,13 +78,19 @@ func NewBetfairClient(
 		logger = log.New(nil, "", 0)
 	}
 
+	catalogTTL := time.Duration(cfg.CatalogCacheTTLSeconds) * time.Second
+	if catalogTTL <= 0 {
+		catalogTTL = defaultCatalogCacheTTL
+	}
+
 	return &BetfairClient{
-		httpClient: httpClient,
-		config:     cfg,
-		baseURL:    cfg.APIURL,
-		streamURL:  cfg.StreamURL,
-		appKey:     cfg.AppKey,
-		logger:     logger,
+		httpClient:   httpClient,
+		config:       cfg,
+		baseURL:      cfg.APIURL,
+		streamURL:    cfg.StreamURL,
+		appKey:       cfg.AppKey,
+		catalogCache: cache.New(catalogTTL, catalogTTL*2),
+		logger:       logger,
 	}
 }
 
@@ -87,14 +100,6 @@ func (c *BetfairClient) makeRequest(
 	method string,
 	params map[string]interface{},
 ) (json.RawMessage, error) {
-	c.mu.RLock()
-	sessionToken := c.sessionToken
-	c.mu.RUnlock()
-
-	if sessionToken == "" {
-		return nil, NewAuthenticationError("no active session token", nil)
-	}
-
 	// Build JSON-RPC request
 	reqBody := JSONRPCRequest{
 		JSONRPC: "2.0",
@@ -103,7 +108,101 @@ func (c *BetfairClient) makeRequest(
 		ID:      1,
 	}
 
-	jsonData, err := json.Marshal(reqBody)
+	resp, err := c.post(ctx, reqBody, method)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	// Parse response
+	var jsonResp JSONRPCResponse
+	if err := json.NewDecoder(resp.Body).Decode(&jsonResp); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	// Check for JSON-RPC error
+	if jsonResp.Error != nil {
+		return nil, NewBetfairAPIError(jsonResp.Error.Message, jsonResp.Error.Data, nil)
+	}
+
+	// Check for HTTP error status
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	c.logger.Printf("API request successful: %s", method)
+	return jsonResp.Result, nil
+}
+
+// makeBatchRequest sends several JSON-RPC requests in one HTTP round trip.
+// Requests are numbered in order and the responses, which Betfair may return
+// in any order, are matched back by ID. Results are returned in request order;
+// any failed call fails the batch.
+func (c *BetfairClient) makeBatchRequest(ctx context.Context, calls []JSONRPCRequest) ([]json.RawMessage, error) {
+	if len(calls) == 0 {
+		return nil, nil
+	}
+
+	methods := make([]string, len(calls))
+	batch := make([]JSONRPCRequest, len(calls))
+	for i, call := range calls {
+		batch[i] = JSONRPCRequest{
+			JSONRPC: "2.0",
+			Method:  call.Method,
+			Params:  call.Params,
+			ID:      i + 1,
+		}
+		methods[i] = call.Method
+	}
+	label := strings.Join(methods, ",")
+
+	resp, err := c.post(ctx, batch, label)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	var jsonResps []JSONRPCResponse
+	if err := json.NewDecoder(resp.Body).Decode(&jsonResps); err != nil {
+		return nil, fmt.Errorf("failed to decode batch response: %w", err)
+	}
+
+	byID := make(map[int]JSONRPCResponse, len(jsonResps))
+	for _, jsonResp := range jsonResps {
+		byID[jsonResp.ID] = jsonResp
+	}
+
+	results := make([]json.RawMessage, len(batch))
+	for i, req := range batch {
+		jsonResp, ok := byID[req.ID]
+		if !ok {
+			return nil, fmt.Errorf("no response for batched %s request", req.Method)
+		}
+		if jsonResp.Error != nil {
+			return nil, NewBetfairAPIError(jsonResp.Error.Message, jsonResp.Error.Data, nil)
+		}
+		results[i] = jsonResp.Result
+	}
+
+	c.logger.Printf("API batch request successful: %s", label)
+	return results, nil
+}
+
+// post sends a JSON-RPC payload with the session headers
+func (c *BetfairClient) post(ctx context.Context, payload interface{}, method string) (*http.Response, error) {
+	c.mu.RLock()
+	sessionToken := c.sessionToken
+	c.mu.RUnlock()
+
+	if sessionToken == "" {
+		return nil, NewAuthenticationError("no active session token", nil)
+	}
+
+	jsonData, err := json.Marshal(payload)
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal request: %w", err)
 	}
@@ -118,6 +217,9 @@ func (c *BetfairClient) makeRequest(
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("X-Application", c.appKey)
 	req.Header.Set("X-Authentication", sessionToken)
+	if requestID := applogger.RequestIDFromContext(ctx); requestID != "" {
+		req.Header.Set(applogger.RequestIDHeader, requestID)
+	}
 
 	c.logger.Printf("Making Betfair API request: %s", method)
 
@@ -127,26 +229,7 @@ func (c *BetfairClient) makeRequest(
 		c.logger.Printf("Failed to make request: %v", err)
 		return nil, fmt.Errorf("request failed: %w", err)
 	}
-	defer resp.Body.Close()
-
-	// Parse response
-	var jsonResp JSONRPCResponse
-	if err := json.NewDecoder(resp.Body).Decode(&jsonResp); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
-	}
-
-	// Check for JSON-RPC error
-	if jsonResp.Error != nil {
-		return nil, NewBetfairAPIError(jsonResp.Error.Message, jsonResp.Error.Data, nil)
-	}
-
-	// Check for HTTP error status
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
-	}
-
-	c.logger.Printf("API request successful: %s", method)
-	return jsonResp.Result, nil
+	return resp, nil
 }
 
 // SetSessionToken sets the session token for API requests
@@ -163,6 +163,7 @@ func (c *MarketDataCollector) processMarketChanges(changes []MarketChange) error
 				MarketID:    change.MarketID,
 				SelectionID: runner.SelectionID,
 				Timestamp:   now,
+				Source:      models.OddsSourceBetfair,
 			}
 
 			// Extract prices
@@ -7,9 +7,23 @@ import (
 	"log"
 	"time"
 
+	appmetrics "github.com/yourusername/clever-better/internal/metrics"
 	"github.com/yourusername/clever-better/internal/models"
 )
 
+type catalogCacheBypassKey struct{}
+
+// WithoutCatalogCache returns a context whose ListMarketCatalog calls skip
+// the cache and fetch from Betfair. The fresh result still refreshes the cache.
+func WithoutCatalogCache(ctx context.Context) context.Context {
+	return context.WithValue(ctx, catalogCacheBypassKey{}, true)
+}
+
+func catalogCacheBypassed(ctx context.Context) bool {
+	bypass, _ := ctx.Value(catalogCacheBypassKey{}).(bool)
+	return bypass
+}
+
 // MarketCatalogue represents market catalog information from Betfair
 type MarketCatalogue struct {
 	MarketID    string                 `json:"marketId"`
@@ -51,6 +65,7 @@ type MarketBook struct {
 	MarketID         string        `json:"marketId"`
 	IsMarketDataOnly bool          `json:"isMarketDataOnly"`
 	Status           string        `json:"status"`
+	InPlay           bool          `json:"inplay"`
 	BetDelay         int           `json:"betDelay"`
 	BSPReconciled    bool          `json:"bspReconciled"`
 	Complete         bool          `json:"complete"`
@@ -135,7 +150,8 @@ type ListMarketBookParams struct {
 	KeepAlive       bool          `json:"keepAlive"`
 }
 
-// ListMarketCatalog fetches market catalog for specified filters
+// ListMarketCatalog fetches market catalog for specified filters. Results are
+// cached per filter for the catalog TTL; see WithoutCatalogCache to bypass.
 func (c *BetfairClient) ListMarketCatalog(
 	ctx context.Context,
 	eventTypeID string,
@@ -164,6 +180,19 @@ func (c *BetfairClient) ListMarketCatalog(
 		"maxResults":      maxResults,
 	}
 
+	cacheKey, err := json.Marshal(params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build market catalog cache key: %w", err)
+	}
+
+	if !catalogCacheBypassed(ctx) {
+		if cached, found := c.catalogCache.Get(string(cacheKey)); found {
+			appmetrics.RecordMarketCatalogCache(appmetrics.CacheHit)
+			return append([]MarketCatalogue(nil), cached.([]MarketCatalogue)...), nil
+		}
+	}
+	appmetrics.RecordMarketCatalogCache(appmetrics.CacheMiss)
+
 	result, err := c.makeRequest(ctx, "listMarketCatalogue", params)
 	if err != nil {
 		c.logger.Printf("Failed to list market catalog: %v", err)
@@ -175,8 +204,9 @@ func (c *BetfairClient) ListMarketCatalog(
 		return nil, fmt.Errorf("failed to parse market catalog response: %w", err)
 	}
 
+	c.catalogCache.SetDefault(string(cacheKey), catalogs)
 	c.logger.Printf("Retrieved %d markets", len(catalogs))
-	return catalogs, nil
+	return append([]MarketCatalogue(nil), catalogs...), nil
 }
 
 // ListGreyhoundRaceMarkets fetches greyhound racing markets for upcoming races
@@ -223,6 +253,113 @@ func (c *BetfairClient) ListMarketBook(
 	return books, nil
 }
 
+// MarketCard pairs a market's catalogue with its current book
+type MarketCard struct {
+	Catalogue MarketCatalogue
+	// Book is nil when Betfair returned no book for the market
+	Book *MarketBook
+}
+
+// ListMarketCards fetches the catalogue and book for markets in a single
+// batched request, in catalogue order. The catalogue cache is not used.
+func (c *BetfairClient) ListMarketCards(
+	ctx context.Context,
+	marketIDs []string,
+	priceProjection []string,
+) ([]MarketCard, error) {
+	if len(marketIDs) == 0 {
+		return nil, fmt.Errorf("at least one market ID required")
+	}
+
+	if len(priceProjection) == 0 {
+		priceProjection = []string{"EX_BEST_OFFERS", "EX_TRADED"}
+	}
+
+	results, err := c.makeBatchRequest(ctx, []JSONRPCRequest{
+		{
+			Method: "listMarketCatalogue",
+			Params: map[string]interface{}{
+				"filter":           MarketFilter{MarketIDs: marketIDs},
+				"marketProjection": []string{"RUNNER_DESCRIPTION", "MARKET_DESCRIPTION", "EVENT", "COMPETITION", "EVENT_TYPE"},
+				"maxResults":       len(marketIDs),
+			},
+		},
+		{
+			Method: "listMarketBook",
+			Params: map[string]interface{}{
+				"marketIds":       marketIDs,
+				"priceProjection": priceProjection,
+				"keepAlive":       false,
+			},
+		},
+	})
+	if err != nil {
+		c.logger.Printf("Failed to list market cards: %v", err)
+		return nil, err
+	}
+
+	var catalogs []MarketCatalogue
+	if err := json.Unmarshal(results[0], &catalogs); err != nil {
+		return nil, fmt.Errorf("failed to parse market catalog response: %w", err)
+	}
+	var books []MarketBook
+	if err := json.Unmarshal(results[1], &books); err != nil {
+		return nil, fmt.Errorf("failed to parse market book response: %w", err)
+	}
+
+	booksByMarket := make(map[string]*MarketBook, len(books))
+	for i := range books {
+		booksByMarket[books[i].MarketID] = &books[i]
+	}
+	cards := make([]MarketCard, len(catalogs))
+	for i, catalog := range catalogs {
+		cards[i] = MarketCard{Catalogue: catalog, Book: booksByMarket[catalog.MarketID]}
+	}
+
+	c.logger.Printf("Retrieved %d market cards", len(cards))
+	return cards, nil
+}
+
+// GetMarketLiquidity returns the total amount matched on a market
+func (c *BetfairClient) GetMarketLiquidity(ctx context.Context, marketID string) (float64, error) {
+	books, err := c.ListMarketBook(ctx, []string{marketID}, []string{"EX_TRADED"})
+	if err != nil {
+		return 0, err
+	}
+
+	if len(books) == 0 {
+		return 0, fmt.Errorf("no market book data returned")
+	}
+
+	return books[0].TotalMatched, nil
+}
+
+// MarketState is a market's status, whether it is in-play and the delay
+// Betfair holds in-play orders for before they reach the book
+type MarketState struct {
+	Status   string
+	InPlay   bool
+	BetDelay time.Duration
+}
+
+// GetMarketState returns a market's current status and bet delay
+func (c *BetfairClient) GetMarketState(ctx context.Context, marketID string) (MarketState, error) {
+	books, err := c.ListMarketBook(ctx, []string{marketID}, []string{"EX_BEST_OFFERS"})
+	if err != nil {
+		return MarketState{}, err
+	}
+
+	if len(books) == 0 {
+		return MarketState{}, fmt.Errorf("no market book data returned")
+	}
+
+	return MarketState{
+		Status:   books[0].Status,
+		InPlay:   books[0].InPlay,
+		BetDelay: time.Duration(books[0].BetDelay) * time.Second,
+	}, nil
+}
+
 // GetMarketPrices returns simplified price data for a market
 func (c *BetfairClient) GetMarketPrices(ctx context.Context, marketID string) (map[uint64]*models.Price, error) {
 	books, err := c.ListMarketBook(ctx, []string{marketID}, []string{"EX_BEST_OFFERS"})
@@ -0,0 +1,165 @@
+package betfair
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	cache "github.com/patrickmn/go-cache"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	appmetrics "github.com/yourusername/clever-better/internal/metrics"
+)
+
+// catalogCacheCount returns the catalogue cache counter for a result
+func catalogCacheCount(t *testing.T, result string) float64 {
+	t.Helper()
+	var m dto.Metric
+	require.NoError(t, appmetrics.MarketCatalogCacheTotal.WithLabelValues(result).(prometheus.Metric).Write(&m))
+	return m.GetCounter().GetValue()
+}
+
+func newCatalogExchange() *fakeExchange {
+	return &fakeExchange{results: map[string]interface{}{
+		"listMarketCatalogue": []map[string]interface{}{
+			{"marketId": "1.234", "marketName": "A1 480m", "runners": []map[string]interface{}{{"selectionId": 11, "runnerName": "1. Dog"}}},
+		},
+	}}
+}
+
+func TestListMarketCatalogCachesWithinTTL(t *testing.T) {
+	exchange := newCatalogExchange()
+	client := newTestClient(t, exchange)
+	hits := catalogCacheCount(t, appmetrics.CacheHit)
+	misses := catalogCacheCount(t, appmetrics.CacheMiss)
+
+	first, err := client.ListMarketCatalog(context.Background(), "4339", []string{"WIN"}, nil, 10)
+	require.NoError(t, err)
+	second, err := client.ListMarketCatalog(context.Background(), "4339", []string{"WIN"}, nil, 10)
+	require.NoError(t, err)
+
+	assert.Len(t, exchange.requests["listMarketCatalogue"], 1, "second call is served from cache")
+	assert.Equal(t, first, second)
+	assert.Equal(t, hits+1, catalogCacheCount(t, appmetrics.CacheHit))
+	assert.Equal(t, misses+1, catalogCacheCount(t, appmetrics.CacheMiss))
+
+	// A different filter is a different entry
+	_, err = client.ListMarketCatalog(context.Background(), "4339", []string{"PLACE"}, nil, 10)
+	require.NoError(t, err)
+	assert.Len(t, exchange.requests["listMarketCatalogue"], 2)
+}
+
+func TestListMarketCatalogRefreshesAfterTTL(t *testing.T) {
+	exchange := newCatalogExchange()
+	client := newTestClient(t, exchange)
+	client.catalogCache = cache.New(20*time.Millisecond, time.Minute)
+
+	_, err := client.ListMarketCatalog(context.Background(), "4339", []string{"WIN"}, nil, 10)
+	require.NoError(t, err)
+	time.Sleep(30 * time.Millisecond)
+	_, err = client.ListMarketCatalog(context.Background(), "4339", []string{"WIN"}, nil, 10)
+	require.NoError(t, err)
+
+	assert.Len(t, exchange.requests["listMarketCatalogue"], 2)
+}
+
+func TestListMarketCatalogBypass(t *testing.T) {
+	exchange := newCatalogExchange()
+	client := newTestClient(t, exchange)
+
+	_, err := client.ListMarketCatalog(context.Background(), "4339", []string{"WIN"}, nil, 10)
+	require.NoError(t, err)
+	_, err = client.ListMarketCatalog(WithoutCatalogCache(context.Background()), "4339", []string{"WIN"}, nil, 10)
+	require.NoError(t, err)
+
+	assert.Len(t, exchange.requests["listMarketCatalogue"], 2, "bypass always fetches")
+}
+
+// batchExchange answers batched JSON-RPC requests in reverse order, so
+// results can only be matched to requests by ID
+type batchExchange struct {
+	results map[string]interface{}
+	bodies  []json.RawMessage
+}
+
+func (b *batchExchange) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var body json.RawMessage
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	b.bodies = append(b.bodies, body)
+
+	var reqs []JSONRPCRequest
+	if err := json.Unmarshal(body, &reqs); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	resps := make([]map[string]interface{}, 0, len(reqs))
+	for i := len(reqs) - 1; i >= 0; i-- {
+		resps = append(resps, map[string]interface{}{
+			"jsonrpc": "2.0",
+			"result":  b.results[reqs[i].Method],
+			"id":      reqs[i].ID,
+		})
+	}
+	_ = json.NewEncoder(w).Encode(resps)
+}
+
+func TestListMarketCardsBatchesCatalogueAndBook(t *testing.T) {
+	exchange := &batchExchange{results: map[string]interface{}{
+		"listMarketCatalogue": []map[string]interface{}{
+			{"marketId": "1.1", "marketName": "A1 480m"},
+			{"marketId": "1.2", "marketName": "A2 480m"},
+		},
+		"listMarketBook": []map[string]interface{}{
+			{"marketId": "1.2", "totalMatched": 200.0},
+			{"marketId": "1.1", "totalMatched": 100.0},
+		},
+	}}
+	client := newTestClient(t, &fakeExchange{})
+	server := httptest.NewServer(exchange)
+	t.Cleanup(server.Close)
+	client.baseURL = server.URL
+
+	cards, err := client.ListMarketCards(context.Background(), []string{"1.1", "1.2"}, nil)
+	require.NoError(t, err)
+
+	require.Len(t, exchange.bodies, 1, "catalogue and book share one HTTP request")
+	var sent []JSONRPCRequest
+	require.NoError(t, json.Unmarshal(exchange.bodies[0], &sent))
+	require.Len(t, sent, 2)
+	assert.Equal(t, "listMarketCatalogue", sent[0].Method)
+	assert.Equal(t, "listMarketBook", sent[1].Method)
+	assert.NotEqual(t, sent[0].ID, sent[1].ID)
+
+	require.Len(t, cards, 2)
+	assert.Equal(t, "1.1", cards[0].Catalogue.MarketID)
+	require.NotNil(t, cards[0].Book)
+	assert.Equal(t, 100.0, cards[0].Book.TotalMatched)
+	assert.Equal(t, "1.2", cards[1].Catalogue.MarketID)
+	require.NotNil(t, cards[1].Book)
+	assert.Equal(t, 200.0, cards[1].Book.TotalMatched)
+}
+
+func TestMakeBatchRequestFailsOnCallError(t *testing.T) {
+	client := newTestClient(t, &fakeExchange{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`[{"jsonrpc":"2.0","id":2,"error":{"code":-32099,"message":"ANGX-0003","data":"INVALID_SESSION_INFORMATION"}},{"jsonrpc":"2.0","id":1,"result":[]}]`))
+	}))
+	t.Cleanup(server.Close)
+	client.baseURL = server.URL
+
+	_, err := client.makeBatchRequest(context.Background(), []JSONRPCRequest{
+		{Method: "listMarketCatalogue"},
+		{Method: "listMarketBook"},
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "INVALID_SESSION_INFORMATION")
+}
@@ -7,8 +7,12 @@ import (
 	"sync"
 	"time"
 
+	"github.com/google/uuid"
+
+	appmetrics "github.com/yourusername/clever-better/internal/metrics"
 	"github.com/yourusername/clever-better/internal/models"
 	"github.com/yourusername/clever-better/internal/repository"
+	"github.com/yourusername/clever-better/internal/strategy"
 )
 
 // OrderManager manages the lifecycle of bets
@@ -20,6 +24,30 @@ type OrderManager struct {
 	mu              sync.Mutex
 	metrics         *OrderMetrics
 	logger          *log.Logger
+	unmatched       UnmatchedPolicy
+	raceRepository  repository.RaceRepository
+	repricing       map[uuid.UUID]*repriceState
+	now             func() time.Time
+}
+
+// UnmatchedPolicy controls how bets left unmatched are handled as the off
+// approaches. A bet is held at its price until the off is within
+// RepriceWithin, then moved RepriceStep ticks towards the market each time it
+// has sat at a price for GracePeriod, at most MaxReprices times (0 is no
+// limit). Within CancelWithin of the off it is cancelled. Zero RepriceStep
+// or CancelWithin disables that step.
+type UnmatchedPolicy struct {
+	GracePeriod   time.Duration
+	RepriceWithin time.Duration
+	RepriceStep   int
+	MaxReprices   int
+	CancelWithin  time.Duration
+}
+
+// repriceState tracks the repricing of one unmatched bet
+type repriceState struct {
+	reprices int
+	pricedAt time.Time
 }
 
 // OrderMetrics tracks order management performance
@@ -28,6 +56,7 @@ type OrderMetrics struct {
 	OrdersMatched    int64
 	OrdersSettled    int64
 	OrdersCancelled   int64
+	OrdersRepriced   int64
 	SyncErrors       int64
 	LastSyncTime     time.Time
 	AverageSyncTime  time.Duration
@@ -55,9 +84,20 @@ func NewOrderManager(
 		done:            make(chan struct{}),
 		metrics:         &OrderMetrics{},
 		logger:          logger,
+		repricing:       make(map[uuid.UUID]*repriceState),
+		now:             time.Now,
 	}
 }
 
+// SetUnmatchedPolicy enables repricing and cancelling unmatched bets as the
+// off approaches. Race start times are read from raceRepository.
+func (om *OrderManager) SetUnmatchedPolicy(policy UnmatchedPolicy, raceRepository repository.RaceRepository) {
+	om.mu.Lock()
+	defer om.mu.Unlock()
+	om.unmatched = policy
+	om.raceRepository = raceRepository
+}
+
 // MonitorOrders starts monitoring pending bets
 func (om *OrderManager) MonitorOrders(ctx context.Context) error {
 	om.logger.Printf("Starting order monitoring with interval: %v", om.pollingInterval)
@@ -146,29 +186,133 @@ func (om *OrderManager) syncOrderStatus(ctx context.Context) error {
 		switch order.Status {
 		case "MATCHED":
 			om.handleMatchedBet(ctx, bet, order)
-		case "UNMATCHED":
-			// Still unmatched, no action needed
+		case "UNMATCHED", "EXECUTABLE":
+			om.handleUnmatchedBet(ctx, bet, order)
 		case "CANCELLED":
 			om.handleCancelledBet(ctx, bet)
 		}
 	}
 
+	// Forget repricing state for bets no longer pending
+	pending := make(map[uuid.UUID]bool, len(pendingBets))
+	for _, bet := range pendingBets {
+		if bet.Status == models.BetStatusPending {
+			pending[bet.ID] = true
+		}
+	}
+	for id := range om.repricing {
+		if !pending[id] {
+			delete(om.repricing, id)
+		}
+	}
+
 	om.metrics.OrdersMonitored += int64(len(pendingBets))
 	return nil
 }
 
+// handleUnmatchedBet holds, reprices or cancels an unmatched bet depending on
+// how close its race is to the off. Without an unmatched policy, or when the
+// bet was placed in-play, the bet is left as it is.
+func (om *OrderManager) handleUnmatchedBet(ctx context.Context, bet *models.Bet, order *CurrentOrderResponse) {
+	if om.raceRepository == nil || bet.IsBSP {
+		return
+	}
+	policy := om.unmatched
+	if policy.RepriceStep <= 0 && policy.CancelWithin <= 0 {
+		return
+	}
+
+	race, err := om.raceRepository.GetByID(ctx, bet.RaceID)
+	if err != nil {
+		om.logger.Printf("Failed to get race for unmatched bet %s: %v", bet.BetID, err)
+		return
+	}
+
+	// Bets placed in-play are past the off from the start, so the pre-off
+	// policy would cancel them before their bet delay has run
+	if !bet.PlacedAt.Before(race.ScheduledStart) {
+		return
+	}
+
+	now := om.now()
+	untilOff := race.ScheduledStart.Sub(now)
+
+	if policy.CancelWithin > 0 && untilOff <= policy.CancelWithin {
+		if err := om.bettingService.CancelOrders(ctx, bet.MarketID, []string{bet.BetID}); err != nil {
+			om.logger.Printf("Failed to cancel unmatched bet %s: %v", bet.BetID, err)
+			return
+		}
+		delete(om.repricing, bet.ID)
+		om.handleCancelledBet(ctx, bet)
+		return
+	}
+
+	if policy.RepriceStep <= 0 || untilOff > policy.RepriceWithin {
+		return
+	}
+
+	state, ok := om.repricing[bet.ID]
+	if !ok {
+		state = &repriceState{pricedAt: bet.PlacedAt}
+		om.repricing[bet.ID] = state
+	}
+	if policy.MaxReprices > 0 && state.reprices >= policy.MaxReprices {
+		return
+	}
+	if now.Sub(state.pricedAt) < policy.GracePeriod {
+		return
+	}
+
+	price := order.Price
+	if price <= 0 {
+		price = bet.Odds
+	}
+	// Backs match sooner at a shorter price, lays at a longer one
+	ticks := -policy.RepriceStep
+	if bet.Side == models.BetSideLay {
+		ticks = policy.RepriceStep
+	}
+	newPrice := strategy.OffsetTicks(price, ticks)
+	if newPrice == price {
+		return
+	}
+
+	newBetID, err := om.bettingService.ReplaceOrder(ctx, bet.MarketID, bet.BetID, newPrice)
+	if err != nil {
+		om.logger.Printf("Failed to reprice unmatched bet %s: %v", bet.BetID, err)
+		return
+	}
+
+	om.logger.Printf("Repriced unmatched bet %s from %.2f to %.2f as %s, %v before the off", bet.BetID, price, newPrice, newBetID, untilOff)
+	bet.BetID = newBetID
+	bet.Odds = newPrice
+	state.reprices++
+	state.pricedAt = now
+	om.metrics.OrdersRepriced++
+
+	if err := om.bettingService.UpdateBetStatus(ctx, bet); err != nil {
+		om.logger.Printf("Failed to update repriced bet %s: %v", bet.BetID, err)
+	}
+}
+
 // handleMatchedBet updates bet status to matched
 func (om *OrderManager) handleMatchedBet(ctx context.Context, bet *models.Bet, order *CurrentOrderResponse) {
+	matchedAt := om.now()
+	matchedPrice := order.AveragePriceMatched
+	matchedSize := order.SizeMatched
 	bet.Status = models.BetStatusMatched
-	bet.MatchedTime = time.Now()
-	bet.MatchedPrice = order.AveragePriceMatched
-	bet.MatchedSize = order.SizeMatched
+	bet.MatchedAt = &matchedAt
+	bet.MatchedPrice = &matchedPrice
+	bet.MatchedSize = &matchedSize
 
 	if err := om.bettingService.UpdateBetStatus(ctx, bet); err != nil {
 		om.logger.Printf("Failed to update bet %s to matched: %v", bet.BetID, err)
 	} else {
 		om.logger.Printf("Bet %s matched at %.2f", bet.BetID, order.AveragePriceMatched)
 		om.metrics.OrdersMatched++
+		if bet.MatchedAt != nil {
+			appmetrics.RecordBetMatchTime(bet.MatchedAt.Sub(bet.PlacedAt).Seconds())
+		}
 	}
 }
 
@@ -208,7 +352,8 @@ func (om *OrderManager) handleSettledBet(ctx context.Context, bet *models.Bet, r
 // handleCancelledBet updates bet status to cancelled
 func (om *OrderManager) handleCancelledBet(ctx context.Context, bet *models.Bet) {
 	bet.Status = models.BetStatusCancelled
-	bet.CancelledTime = time.Now()
+	cancelledAt := om.now()
+	bet.CancelledAt = &cancelledAt
 
 	if err := om.bettingService.UpdateBetStatus(ctx, bet); err != nil {
 		om.logger.Printf("Failed to update bet %s to cancelled: %v", bet.BetID, err)
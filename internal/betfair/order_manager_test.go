@@ -0,0 +1,277 @@
+package betfair
+
+import (
+	"context"
+	"io"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	appmetrics "github.com/yourusername/clever-better/internal/metrics"
+	"github.com/yourusername/clever-better/internal/models"
+	"github.com/yourusername/clever-better/internal/repository"
+)
+
+// memoryBetRepo serves the pending bets it holds and records updates in place
+type memoryBetRepo struct {
+	repository.BetRepository
+	bets []*models.Bet
+}
+
+func (r *memoryBetRepo) GetPendingBets(ctx context.Context) ([]*models.Bet, error) {
+	var pending []*models.Bet
+	for _, bet := range r.bets {
+		if bet.Status == models.BetStatusPending {
+			pending = append(pending, bet)
+		}
+	}
+	return pending, nil
+}
+
+func (r *memoryBetRepo) Update(ctx context.Context, bet *models.Bet) error {
+	return nil
+}
+
+// fixedRaceRepo serves a single race
+type fixedRaceRepo struct {
+	repository.RaceRepository
+	race *models.Race
+}
+
+func (r *fixedRaceRepo) GetByID(ctx context.Context, id uuid.UUID) (*models.Race, error) {
+	return r.race, nil
+}
+
+func TestOrderManagerRepricesThenCancelsUnmatchedBet(t *testing.T) {
+	off := time.Date(2024, 3, 1, 14, 0, 0, 0, time.UTC)
+	race := &models.Race{ID: uuid.New(), ScheduledStart: off}
+	bet := &models.Bet{
+		ID:       uuid.New(),
+		BetID:    "1",
+		MarketID: "1.234",
+		RaceID:   race.ID,
+		Side:     models.BetSideBack,
+		Odds:     4.0,
+		Stake:    10,
+		Status:   models.BetStatusPending,
+		PlacedAt: off.Add(-10 * time.Minute),
+	}
+	betRepo := &memoryBetRepo{bets: []*models.Bet{bet}}
+
+	exchange := &fakeExchange{results: map[string]interface{}{
+		"cancelOrders": map[string]interface{}{"status": "SUCCESS"},
+	}}
+	openOrder := func(betID string, price float64) {
+		exchange.results["listCurrentOrders"] = map[string]interface{}{
+			"currentOrders": []map[string]interface{}{
+				{"betId": betID, "marketId": "1.234", "status": "EXECUTABLE", "price": price, "size": 10.0, "sizeRemaining": 10.0},
+			},
+		}
+	}
+	replacedWith := func(betID string) {
+		exchange.results["replaceOrders"] = map[string]interface{}{
+			"status": "SUCCESS",
+			"instructionReports": []map[string]interface{}{
+				{"status": "SUCCESS", "placeInstructionReport": map[string]interface{}{"status": "SUCCESS", "betId": betID}},
+			},
+		}
+	}
+
+	service := NewBettingService(newTestClient(t, exchange), betRepo, BettingConfig{MaxStake: 100}, log.New(io.Discard, "", 0))
+	om := NewOrderManager(service, betRepo, time.Second, log.New(io.Discard, "", 0))
+	om.SetUnmatchedPolicy(UnmatchedPolicy{
+		GracePeriod:   time.Minute,
+		RepriceWithin: 5 * time.Minute,
+		RepriceStep:   1,
+		MaxReprices:   2,
+		CancelWithin:  30 * time.Second,
+	}, &fixedRaceRepo{race: race})
+
+	sync := func(untilOff time.Duration) {
+		t.Helper()
+		om.now = func() time.Time { return off.Add(-untilOff) }
+		require.NoError(t, om.syncOrderStatus(context.Background()))
+	}
+
+	// Outside the reprice window the bet is held at its price
+	openOrder("1", 4.0)
+	sync(6 * time.Minute)
+	assert.Empty(t, exchange.requests["replaceOrders"])
+	assert.Equal(t, 4.0, bet.Odds)
+
+	// Inside it, a bet past its grace period moves one tick shorter
+	replacedWith("2")
+	sync(4 * time.Minute)
+	require.Len(t, exchange.requests["replaceOrders"], 1)
+	assert.Equal(t, "1", exchange.requests["replaceOrders"][0]["instructions"].([]interface{})[0].(map[string]interface{})["betId"])
+	assert.Equal(t, "2", bet.BetID)
+	assert.Equal(t, 3.95, bet.Odds)
+
+	// The new price gets its own grace period
+	openOrder("2", 3.95)
+	sync(3*time.Minute + 30*time.Second)
+	assert.Len(t, exchange.requests["replaceOrders"], 1)
+
+	replacedWith("3")
+	sync(2 * time.Minute)
+	require.Len(t, exchange.requests["replaceOrders"], 2)
+	assert.Equal(t, "3", bet.BetID)
+	assert.Equal(t, 3.9, bet.Odds)
+
+	// Once max reprices is reached the bet is held again
+	openOrder("3", 3.9)
+	sync(time.Minute)
+	assert.Len(t, exchange.requests["replaceOrders"], 2)
+	assert.Equal(t, models.BetStatusPending, bet.Status)
+
+	// Within the cancel threshold it is cancelled
+	sync(20 * time.Second)
+	require.Len(t, exchange.requests["cancelOrders"], 1)
+	assert.Equal(t, []interface{}{"3"}, exchange.requests["cancelOrders"][0]["betIds"])
+	assert.Equal(t, models.BetStatusCancelled, bet.Status)
+	require.NotNil(t, bet.CancelledAt)
+
+	metrics := om.GetMetrics()
+	assert.Equal(t, int64(2), metrics.OrdersRepriced)
+	assert.Equal(t, int64(1), metrics.OrdersCancelled)
+}
+
+func TestOrderManagerRepricesLaysLonger(t *testing.T) {
+	off := time.Date(2024, 3, 1, 14, 0, 0, 0, time.UTC)
+	race := &models.Race{ID: uuid.New(), ScheduledStart: off}
+	bet := &models.Bet{
+		ID:       uuid.New(),
+		BetID:    "1",
+		MarketID: "1.234",
+		RaceID:   race.ID,
+		Side:     models.BetSideLay,
+		Odds:     2.0,
+		Stake:    10,
+		Status:   models.BetStatusPending,
+		PlacedAt: off.Add(-10 * time.Minute),
+	}
+	betRepo := &memoryBetRepo{bets: []*models.Bet{bet}}
+
+	exchange := &fakeExchange{results: map[string]interface{}{
+		"listCurrentOrders": map[string]interface{}{
+			"currentOrders": []map[string]interface{}{
+				{"betId": "1", "marketId": "1.234", "status": "EXECUTABLE", "price": 2.0, "size": 10.0, "sizeRemaining": 10.0},
+			},
+		},
+		"replaceOrders": map[string]interface{}{
+			"status": "SUCCESS",
+			"instructionReports": []map[string]interface{}{
+				{"status": "SUCCESS", "placeInstructionReport": map[string]interface{}{"betId": "2"}},
+			},
+		},
+	}}
+
+	service := NewBettingService(newTestClient(t, exchange), betRepo, BettingConfig{MaxStake: 100}, log.New(io.Discard, "", 0))
+	om := NewOrderManager(service, betRepo, time.Second, log.New(io.Discard, "", 0))
+	om.SetUnmatchedPolicy(UnmatchedPolicy{RepriceWithin: 5 * time.Minute, RepriceStep: 2}, &fixedRaceRepo{race: race})
+	om.now = func() time.Time { return off.Add(-time.Minute) }
+
+	require.NoError(t, om.syncOrderStatus(context.Background()))
+	assert.Equal(t, "2", bet.BetID)
+	assert.Equal(t, 2.04, bet.Odds, "lays move two ticks longer")
+	assert.Empty(t, exchange.requests["cancelOrders"], "cancelling is disabled")
+}
+
+func TestOrderManagerLeavesInPlayBets(t *testing.T) {
+	off := time.Date(2024, 3, 1, 14, 0, 0, 0, time.UTC)
+	race := &models.Race{ID: uuid.New(), ScheduledStart: off}
+	bet := &models.Bet{
+		ID:       uuid.New(),
+		BetID:    "1",
+		MarketID: "1.234",
+		RaceID:   race.ID,
+		Side:     models.BetSideBack,
+		Odds:     4.0,
+		Stake:    10,
+		Status:   models.BetStatusPending,
+		PlacedAt: off.Add(20 * time.Second),
+	}
+	betRepo := &memoryBetRepo{bets: []*models.Bet{bet}}
+
+	exchange := &fakeExchange{results: map[string]interface{}{
+		"listCurrentOrders": map[string]interface{}{
+			"currentOrders": []map[string]interface{}{
+				{"betId": "1", "marketId": "1.234", "status": "EXECUTABLE", "price": 4.0, "size": 10.0, "sizeRemaining": 10.0},
+			},
+		},
+		"cancelOrders": map[string]interface{}{"status": "SUCCESS"},
+	}}
+
+	service := NewBettingService(newTestClient(t, exchange), betRepo, BettingConfig{MaxStake: 100}, log.New(io.Discard, "", 0))
+	om := NewOrderManager(service, betRepo, time.Second, log.New(io.Discard, "", 0))
+	om.SetUnmatchedPolicy(UnmatchedPolicy{RepriceWithin: 5 * time.Minute, RepriceStep: 1, CancelWithin: 30 * time.Second}, &fixedRaceRepo{race: race})
+	om.now = func() time.Time { return off.Add(25 * time.Second) }
+
+	require.NoError(t, om.syncOrderStatus(context.Background()))
+	assert.Empty(t, exchange.requests["cancelOrders"], "a bet placed in-play is not cancelled as past the off")
+	assert.Empty(t, exchange.requests["replaceOrders"])
+	assert.Equal(t, models.BetStatusPending, bet.Status)
+}
+
+func TestOrderManagerRecordsMatchTime(t *testing.T) {
+	placedAt := time.Date(2024, 3, 1, 13, 55, 0, 0, time.UTC)
+	bet := &models.Bet{
+		ID:       uuid.New(),
+		BetID:    "1",
+		MarketID: "1.234",
+		Side:     models.BetSideBack,
+		Odds:     4.0,
+		Stake:    10,
+		Status:   models.BetStatusPending,
+		PlacedAt: placedAt,
+	}
+	betRepo := &memoryBetRepo{bets: []*models.Bet{bet}}
+
+	exchange := &fakeExchange{results: map[string]interface{}{
+		"listCurrentOrders": map[string]interface{}{
+			"currentOrders": []map[string]interface{}{
+				{"betId": "1", "marketId": "1.234", "status": "EXECUTABLE", "price": 4.0, "size": 10.0, "sizeRemaining": 10.0},
+			},
+		},
+	}}
+
+	service := NewBettingService(newTestClient(t, exchange), betRepo, BettingConfig{MaxStake: 100}, log.New(io.Discard, "", 0))
+	om := NewOrderManager(service, betRepo, time.Second, log.New(io.Discard, "", 0))
+
+	matchTime := func() (uint64, float64) {
+		t.Helper()
+		var m dto.Metric
+		require.NoError(t, appmetrics.BetMatchTime.Write(&m))
+		return m.GetHistogram().GetSampleCount(), m.GetHistogram().GetSampleSum()
+	}
+	count, sum := matchTime()
+
+	// An unmatched order records nothing
+	om.now = func() time.Time { return placedAt.Add(10 * time.Second) }
+	require.NoError(t, om.syncOrderStatus(context.Background()))
+	observed, _ := matchTime()
+	assert.Equal(t, count, observed)
+
+	// Once fully matched, the time since placement is observed
+	exchange.results["listCurrentOrders"] = map[string]interface{}{
+		"currentOrders": []map[string]interface{}{
+			{"betId": "1", "marketId": "1.234", "status": "MATCHED", "price": 4.0, "size": 10.0, "averagePriceMatched": 4.1, "sizeMatched": 10.0},
+		},
+	}
+	om.now = func() time.Time { return placedAt.Add(45 * time.Second) }
+	require.NoError(t, om.syncOrderStatus(context.Background()))
+
+	assert.Equal(t, models.BetStatusMatched, bet.Status)
+	require.NotNil(t, bet.MatchedAt)
+	require.NotNil(t, bet.MatchedPrice)
+	assert.Equal(t, 4.1, *bet.MatchedPrice)
+
+	observed, observedSum := matchTime()
+	assert.Equal(t, count+1, observed)
+	assert.InDelta(t, 45, observedSum-sum, 1e-9)
+}
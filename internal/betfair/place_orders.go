@@ -0,0 +1,174 @@
+package betfair
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Statuses placeOrders reports for a request and for each instruction in it
+const (
+	PlaceStatusSuccess             = "SUCCESS"
+	PlaceStatusFailure             = "FAILURE"
+	PlaceStatusProcessedWithErrors = "PROCESSED_WITH_ERRORS"
+	PlaceStatusTimeout             = "TIMEOUT"
+)
+
+// ErrInstructionFailed is returned for an instruction Betfair did not place
+var ErrInstructionFailed = errors.New("place instruction failed")
+
+// ErrNoInstructionReport is returned for an instruction the response has no
+// report for
+var ErrNoInstructionReport = errors.New("no instruction report for instruction")
+
+// InstructionOutcome is the result of one instruction in a placeOrders
+// request. Err says why an instruction was not placed.
+type InstructionOutcome struct {
+	Instruction PlaceInstruction
+	Report      *InstructionReport
+	Err         error
+}
+
+// Placed reports whether Betfair accepted the instruction
+func (o InstructionOutcome) Placed() bool {
+	return o.Err == nil && o.Report != nil
+}
+
+// PlaceOrders places several instructions on one market and returns an
+// outcome for each, in the order given. A request can partly succeed, so
+// callers should check every outcome. The error is only set when the call
+// itself failed and nothing is known about the instructions.
+func (b *BettingService) PlaceOrders(ctx context.Context, marketID string, instructions []PlaceInstruction) ([]InstructionOutcome, error) {
+	params := map[string]interface{}{
+		"marketId":     marketID,
+		"instructions": instructions,
+		"orderMode":    "EXECUTE",
+	}
+
+	result, err := b.client.makeRequest(ctx, "placeOrders", params)
+	if err != nil {
+		b.logger.Printf("Failed to place orders: %v", err)
+		return nil, err
+	}
+
+	var resp PlaceOrdersResponse
+	if err := json.Unmarshal(result, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse place orders response: %w", err)
+	}
+
+	outcomes := MapInstructionReports(instructions, &resp)
+	for _, outcome := range outcomes {
+		if !outcome.Placed() {
+			b.logger.Printf("Place instruction failed: market=%s, selection=%d, side=%s: %v",
+				marketID, outcome.Instruction.SelectionID, outcome.Instruction.Side, outcome.Err)
+		}
+	}
+	return outcomes, nil
+}
+
+// MapInstructionReports pairs each requested instruction with its report.
+// Reports that echo their instruction are matched on it; the rest are
+// matched in request order, which is the order Betfair returns them in.
+// Instructions left without a report take the request's error code.
+func MapInstructionReports(instructions []PlaceInstruction, resp *PlaceOrdersResponse) []InstructionOutcome {
+	outcomes := make([]InstructionOutcome, len(instructions))
+	for i, instruction := range instructions {
+		outcomes[i].Instruction = instruction
+	}
+
+	var unmatched []int
+	for r := range resp.InstructionReports {
+		report := &resp.InstructionReports[r]
+		i := -1
+		if report.Instruction != nil {
+			i = findInstruction(instructions, outcomes, *report.Instruction)
+		}
+		if i < 0 {
+			unmatched = append(unmatched, r)
+			continue
+		}
+		outcomes[i].Report = report
+	}
+	for i := range outcomes {
+		if outcomes[i].Report != nil || len(unmatched) == 0 {
+			continue
+		}
+		outcomes[i].Report = &resp.InstructionReports[unmatched[0]]
+		unmatched = unmatched[1:]
+	}
+
+	for i := range outcomes {
+		outcomes[i].Err = instructionError(outcomes[i].Report, resp)
+	}
+	return outcomes
+}
+
+// findInstruction returns the first instruction without a report that an
+// echoed instruction describes, or -1
+func findInstruction(instructions []PlaceInstruction, outcomes []InstructionOutcome, echoed PlaceInstruction) int {
+	for i, instruction := range instructions {
+		if outcomes[i].Report == nil && sameInstruction(instruction, echoed) {
+			return i
+		}
+	}
+	return -1
+}
+
+// sameInstruction compares the fields Betfair echoes back on a report
+func sameInstruction(a, b PlaceInstruction) bool {
+	if a.SelectionID != b.SelectionID || a.Side != b.Side || a.Handicap != b.Handicap {
+		return false
+	}
+	if a.OrderType != "" && b.OrderType != "" && a.OrderType != b.OrderType {
+		return false
+	}
+	if a.LimitOrder != nil && b.LimitOrder != nil {
+		return samePrice(a.LimitOrder.Price, b.LimitOrder.Price) && samePrice(a.LimitOrder.Size, b.LimitOrder.Size)
+	}
+	if a.MarketOnClose != nil && b.MarketOnClose != nil {
+		return samePrice(a.MarketOnClose.Liability, b.MarketOnClose.Liability)
+	}
+	return true
+}
+
+func samePrice(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+// instructionError explains why an instruction was not placed, or returns
+// nil if it was
+func instructionError(report *InstructionReport, resp *PlaceOrdersResponse) error {
+	if report == nil {
+		if resp.Status == PlaceStatusSuccess {
+			return ErrNoInstructionReport
+		}
+		return fmt.Errorf("%w: %s", ErrInstructionFailed, failureReason(resp.Status, resp.ErrorCode, resp.PlaceOrdersErrors))
+	}
+	if report.Status == PlaceStatusSuccess {
+		return nil
+	}
+
+	var rejects []string
+	for _, reject := range report.OrderRejects {
+		rejects = append(rejects, reject.Reason)
+	}
+	errorCode := report.ErrorCode
+	if errorCode == "" {
+		errorCode = resp.ErrorCode
+	}
+	return fmt.Errorf("%w: %s", ErrInstructionFailed, failureReason(report.Status, errorCode, rejects))
+}
+
+func failureReason(status, errorCode string, details []string) string {
+	reason := "status=" + status
+	if errorCode != "" {
+		reason += ", error=" + errorCode
+	}
+	if len(details) > 0 {
+		reason += ", details=" + strings.Join(details, "; ")
+	}
+	return reason
+}
@@ -0,0 +1,107 @@
+package betfair
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func limitInstruction(selectionID uint64, side string, price, size float64) PlaceInstruction {
+	return PlaceInstruction{
+		OrderType:   "LIMIT",
+		SelectionID: selectionID,
+		Side:        side,
+		LimitOrder:  &LimitOrder{Size: size, Price: price},
+	}
+}
+
+func TestMapInstructionReportsMixedResponse(t *testing.T) {
+	instructions := []PlaceInstruction{
+		limitInstruction(11, "BACK", 3.0, 10),
+		limitInstruction(12, "BACK", 5.0, 10),
+	}
+	resp := &PlaceOrdersResponse{
+		Status:    PlaceStatusFailure,
+		ErrorCode: "BET_ACTION_ERROR",
+		InstructionReports: []InstructionReport{
+			{Status: PlaceStatusSuccess, BetID: "101", SizeMatched: 10, AveragePriceMatched: 3.0},
+			{Status: PlaceStatusFailure, ErrorCode: "INVALID_ODDS"},
+		},
+	}
+
+	outcomes := MapInstructionReports(instructions, resp)
+	require.Len(t, outcomes, 2)
+
+	assert.True(t, outcomes[0].Placed())
+	assert.Equal(t, uint64(11), outcomes[0].Instruction.SelectionID)
+	assert.Equal(t, "101", outcomes[0].Report.BetID)
+
+	assert.False(t, outcomes[1].Placed())
+	assert.Equal(t, uint64(12), outcomes[1].Instruction.SelectionID)
+	assert.ErrorIs(t, outcomes[1].Err, ErrInstructionFailed)
+	assert.Contains(t, outcomes[1].Err.Error(), "INVALID_ODDS", "the failure says why")
+}
+
+func TestMapInstructionReportsMatchesEchoedInstructions(t *testing.T) {
+	first := limitInstruction(11, "BACK", 3.0, 10)
+	second := limitInstruction(11, "LAY", 3.2, 8)
+	third := limitInstruction(13, "BACK", 7.0, 4)
+	resp := &PlaceOrdersResponse{
+		Status: PlaceStatusProcessedWithErrors,
+		InstructionReports: []InstructionReport{
+			{Status: PlaceStatusFailure, ErrorCode: "ERROR_IN_MATCHER", Instruction: &second},
+			{Status: PlaceStatusSuccess, BetID: "201", Instruction: &first},
+		},
+	}
+
+	outcomes := MapInstructionReports([]PlaceInstruction{first, second, third}, resp)
+	require.Len(t, outcomes, 3)
+	assert.True(t, outcomes[0].Placed())
+	assert.Equal(t, "201", outcomes[0].Report.BetID)
+	assert.ErrorIs(t, outcomes[1].Err, ErrInstructionFailed)
+	assert.Contains(t, outcomes[1].Err.Error(), "ERROR_IN_MATCHER")
+	assert.ErrorIs(t, outcomes[2].Err, ErrInstructionFailed, "an instruction without a report failed with the request")
+	assert.Contains(t, outcomes[2].Err.Error(), PlaceStatusProcessedWithErrors)
+}
+
+func TestPlaceOrdersReportsPerInstructionOutcomes(t *testing.T) {
+	exchange := &fakeExchange{results: map[string]interface{}{
+		"placeOrders": map[string]interface{}{
+			"status":    "FAILURE",
+			"errorCode": "BET_ACTION_ERROR",
+			"instructionReports": []map[string]interface{}{
+				{"status": "SUCCESS", "betId": "301", "sizeMatched": 5.0, "averagePriceMatched": 4.1},
+				{"status": "FAILURE", "errorCode": "INSUFFICIENT_FUNDS"},
+			},
+		},
+	}}
+	service := newTestBettingService(t, exchange)
+
+	outcomes, err := service.PlaceOrders(context.Background(), "1.234", []PlaceInstruction{
+		limitInstruction(11, "BACK", 4.0, 5),
+		limitInstruction(12, "LAY", 2.5, 5),
+	})
+	require.NoError(t, err)
+	require.Len(t, outcomes, 2)
+	assert.True(t, outcomes[0].Placed())
+	assert.Equal(t, "301", outcomes[0].Report.BetID)
+	assert.False(t, outcomes[1].Placed())
+	assert.Contains(t, outcomes[1].Err.Error(), "INSUFFICIENT_FUNDS")
+}
+
+func TestPlaceBetWithReportReturnsInstructionFailure(t *testing.T) {
+	exchange := &fakeExchange{results: map[string]interface{}{
+		"placeOrders": map[string]interface{}{
+			"status":             "FAILURE",
+			"errorCode":          "BET_ACTION_ERROR",
+			"instructionReports": []map[string]interface{}{{"status": "FAILURE", "errorCode": "MARKET_NOT_OPEN_FOR_BETTING"}},
+		},
+	}}
+	service := newTestBettingService(t, exchange)
+
+	_, err := service.PlaceBetWithReport(context.Background(), "1.234", 11, 3.0, 10, "BACK")
+	require.ErrorIs(t, err, ErrInstructionFailed)
+	assert.Contains(t, err.Error(), "MARKET_NOT_OPEN_FOR_BETTING")
+}
@@ -0,0 +1,150 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/clever-better/internal/betfair"
+)
+
+// Account routing policies for betfair.account_routing
+const (
+	// RoutingRoundRobin places bets on each account in turn
+	RoutingRoundRobin = "round_robin"
+	// RoutingLeastExposed places each bet on the account with the least open exposure
+	RoutingLeastExposed = "least_exposed"
+	// RoutingByStrategy places a strategy's bets on its assigned account,
+	// falling back to the least exposed account for unassigned strategies
+	RoutingByStrategy = "by_strategy"
+)
+
+// PrimaryAccountName names the account configured by the top-level Betfair
+// credentials. Bets placed through it may also carry an empty account.
+const PrimaryAccountName = "primary"
+
+// BetPlacer places and cancels orders on one Betfair account
+type BetPlacer interface {
+	PlaceBetWithReport(ctx context.Context, marketID string, selectionID uint64, price, stake float64, side string) (*betfair.InstructionReport, error)
+	PlaceBSPBet(ctx context.Context, marketID string, selectionID uint64, liability float64, side string) (string, error)
+	CancelBet(ctx context.Context, marketID, betID string) error
+}
+
+// BettingAccount is a Betfair account live bets can be routed to
+type BettingAccount struct {
+	Name    string
+	Service BetPlacer
+	// MaxExposure caps the open stake on the account; zero is unlimited
+	MaxExposure float64
+}
+
+// AccountRouter chooses the Betfair account for each live bet
+type AccountRouter struct {
+	policy           string
+	accounts         []*BettingAccount
+	strategyAccounts map[uuid.UUID]string
+	next             int
+	mu               sync.Mutex
+}
+
+// NewAccountRouter creates a router over the given accounts. An empty policy
+// routes round robin.
+func NewAccountRouter(policy string, accounts ...*BettingAccount) (*AccountRouter, error) {
+	if len(accounts) == 0 {
+		return nil, fmt.Errorf("at least one betting account is required")
+	}
+	switch policy {
+	case "":
+		policy = RoutingRoundRobin
+	case RoutingRoundRobin, RoutingLeastExposed, RoutingByStrategy:
+	default:
+		return nil, fmt.Errorf("unknown account routing policy: %s", policy)
+	}
+
+	seen := make(map[string]bool, len(accounts))
+	for _, account := range accounts {
+		if account == nil || account.Service == nil {
+			return nil, fmt.Errorf("betting account has no betting service")
+		}
+		if seen[account.Name] {
+			return nil, fmt.Errorf("duplicate betting account: %s", account.Name)
+		}
+		seen[account.Name] = true
+	}
+
+	return &AccountRouter{
+		policy:           policy,
+		accounts:         accounts,
+		strategyAccounts: make(map[uuid.UUID]string),
+	}, nil
+}
+
+// AssignStrategy routes a strategy's bets to the named account under
+// by_strategy routing
+func (r *AccountRouter) AssignStrategy(strategyID uuid.UUID, accountName string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.strategyAccounts[strategyID] = accountName
+}
+
+// Account returns the account with the given name. An empty name is the
+// primary account.
+func (r *AccountRouter) Account(name string) (*BettingAccount, bool) {
+	if name == "" {
+		name = PrimaryAccountName
+	}
+	for _, account := range r.accounts {
+		if account.Name == name {
+			return account, true
+		}
+	}
+	return nil, false
+}
+
+// Route picks the account for a stake given each account's open exposure.
+// Accounts without headroom for the stake are skipped; an error is returned
+// when every account is full.
+func (r *AccountRouter) Route(strategyID uuid.UUID, stake float64, exposure map[string]float64) (*BettingAccount, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	hasRoom := func(account *BettingAccount) bool {
+		return account.MaxExposure <= 0 || exposure[account.Name]+stake <= account.MaxExposure
+	}
+
+	if r.policy == RoutingByStrategy {
+		if name, ok := r.strategyAccounts[strategyID]; ok {
+			for _, account := range r.accounts {
+				if account.Name == name && hasRoom(account) {
+					return account, nil
+				}
+			}
+		}
+	}
+
+	if r.policy == RoutingRoundRobin {
+		for i := range r.accounts {
+			account := r.accounts[(r.next+i)%len(r.accounts)]
+			if hasRoom(account) {
+				r.next = (r.next + i + 1) % len(r.accounts)
+				return account, nil
+			}
+		}
+		return nil, fmt.Errorf("no betting account has room for stake %.2f", stake)
+	}
+
+	var best *BettingAccount
+	for _, account := range r.accounts {
+		if !hasRoom(account) {
+			continue
+		}
+		if best == nil || exposure[account.Name] < exposure[best.Name] {
+			best = account
+		}
+	}
+	if best == nil {
+		return nil, fmt.Errorf("no betting account has room for stake %.2f", stake)
+	}
+	return best, nil
+}
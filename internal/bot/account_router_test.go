@@ -0,0 +1,139 @@
+package bot
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/clever-better/internal/betfair"
+	"github.com/yourusername/clever-better/internal/config"
+	"github.com/yourusername/clever-better/internal/models"
+	"github.com/yourusername/clever-better/internal/strategy"
+)
+
+// fakePlacer records the orders placed on one account and reports the
+// configured fill for each
+type fakePlacer struct {
+	placed    []float64
+	cancelled []string
+	fill      func(price, stake float64) (float64, float64)
+}
+
+func (f *fakePlacer) PlaceBetWithReport(ctx context.Context, marketID string, selectionID uint64, price, stake float64, side string) (*betfair.InstructionReport, error) {
+	f.placed = append(f.placed, stake)
+	report := &betfair.InstructionReport{Status: "SUCCESS", BetID: uuid.NewString()}
+	if f.fill != nil {
+		report.AveragePriceMatched, report.SizeMatched = f.fill(price, stake)
+	}
+	return report, nil
+}
+
+func (f *fakePlacer) PlaceBSPBet(ctx context.Context, marketID string, selectionID uint64, liability float64, side string) (string, error) {
+	return uuid.NewString(), nil
+}
+
+func (f *fakePlacer) CancelBet(ctx context.Context, marketID, betID string) error {
+	f.cancelled = append(f.cancelled, betID)
+	return nil
+}
+
+func TestExecutorRoutesLiveBetsRoundRobin(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	betRepo := new(MockBetRepository)
+	betRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+	betRepo.On("Update", mock.Anything, mock.Anything).Return(nil)
+
+	primary, second := &fakePlacer{}, &fakePlacer{}
+	router, err := NewAccountRouter(RoutingRoundRobin,
+		&BettingAccount{Name: PrimaryAccountName, Service: primary},
+		&BettingAccount{Name: "second", Service: second},
+	)
+	require.NoError(t, err)
+
+	riskManager := NewRiskManager(&config.TradingConfig{MaxStakePerBet: 100, MaxExposure: 1000, MaxDailyLoss: 200}, betRepo, logger)
+	executor := NewExecutor(nil, betRepo, riskManager, false, true, logger, nil)
+	executor.SetAccountRouter(router)
+
+	var bets []*models.Bet
+	for i := 0; i < 4; i++ {
+		signal := strategy.Signal{RunnerID: uuid.New(), Side: models.BetSideBack, Odds: 3.0, Stake: 10}
+		bet, err := executor.ExecuteSignal(context.Background(), signal, uuid.New(), uuid.New(), "1.234", 1)
+		require.NoError(t, err)
+		bets = append(bets, bet)
+	}
+
+	assert.Len(t, primary.placed, 2)
+	assert.Len(t, second.placed, 2)
+	assert.Equal(t, PrimaryAccountName, bets[0].Account)
+	assert.Equal(t, "second", bets[1].Account)
+	assert.InDelta(t, 20.0, riskManager.AccountExposures()["second"], 1e-9)
+
+	// Cancels go back to the account that placed the bet
+	betRepo.On("GetByID", mock.Anything, bets[1].ID).Return(bets[1], nil)
+	require.NoError(t, executor.CancelBet(context.Background(), bets[1].ID))
+	assert.Equal(t, []string{bets[1].BetID}, second.cancelled)
+	assert.Empty(t, primary.cancelled)
+}
+
+func TestAccountRouterLeastExposed(t *testing.T) {
+	a, b := &BettingAccount{Name: "a", Service: &fakePlacer{}}, &BettingAccount{Name: "b", Service: &fakePlacer{}}
+	router, err := NewAccountRouter(RoutingLeastExposed, a, b)
+	require.NoError(t, err)
+
+	account, err := router.Route(uuid.New(), 10, map[string]float64{"a": 50, "b": 20})
+	require.NoError(t, err)
+	assert.Equal(t, "b", account.Name)
+
+	account, err = router.Route(uuid.New(), 10, map[string]float64{"a": 5, "b": 20})
+	require.NoError(t, err)
+	assert.Equal(t, "a", account.Name)
+}
+
+func TestAccountRouterByStrategy(t *testing.T) {
+	a, b := &BettingAccount{Name: "a", Service: &fakePlacer{}}, &BettingAccount{Name: "b", Service: &fakePlacer{}, MaxExposure: 100}
+	router, err := NewAccountRouter(RoutingByStrategy, a, b)
+	require.NoError(t, err)
+
+	assigned, unassigned := uuid.New(), uuid.New()
+	router.AssignStrategy(assigned, "b")
+
+	account, err := router.Route(assigned, 10, map[string]float64{"a": 0, "b": 50})
+	require.NoError(t, err)
+	assert.Equal(t, "b", account.Name)
+
+	account, err = router.Route(unassigned, 10, map[string]float64{"a": 60, "b": 50})
+	require.NoError(t, err)
+	assert.Equal(t, "b", account.Name, "unassigned strategies go to the least exposed account")
+
+	account, err = router.Route(assigned, 60, map[string]float64{"a": 0, "b": 50})
+	require.NoError(t, err)
+	assert.Equal(t, "a", account.Name, "a full assigned account falls back")
+}
+
+func TestAccountRouterSkipsFullAccounts(t *testing.T) {
+	full := &BettingAccount{Name: "full", Service: &fakePlacer{}, MaxExposure: 100}
+	open := &BettingAccount{Name: "open", Service: &fakePlacer{}, MaxExposure: 100}
+	router, err := NewAccountRouter(RoutingRoundRobin, full, open)
+	require.NoError(t, err)
+
+	exposure := map[string]float64{"full": 95, "open": 0}
+	for i := 0; i < 3; i++ {
+		account, err := router.Route(uuid.New(), 10, exposure)
+		require.NoError(t, err)
+		assert.Equal(t, "open", account.Name)
+	}
+
+	_, err = router.Route(uuid.New(), 10, map[string]float64{"full": 95, "open": 95})
+	assert.Error(t, err, "no account has room")
+
+	_, err = NewAccountRouter("random", full)
+	assert.Error(t, err)
+	_, err = NewAccountRouter(RoutingRoundRobin, full, full)
+	assert.Error(t, err, "account names must be unique")
+}
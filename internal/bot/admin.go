@@ -0,0 +1,110 @@
+package bot
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// AdminStrategiesPath is the prefix of the strategy admin endpoints
+const AdminStrategiesPath = "/admin/strategies/"
+
+// AdminSecretHeader carries the admin secret on each admin request
+const AdminSecretHeader = "X-Admin-Secret"
+
+// AdminStrategyResponse is the JSON body returned by the strategy admin
+// endpoints
+type AdminStrategyResponse struct {
+	StrategyID uuid.UUID `json:"strategy_id"`
+	Paused     bool      `json:"paused"`
+}
+
+// PauseStrategy stops a loaded strategy being evaluated until it is resumed.
+// The pause is held in memory only: the strategy stays active in the
+// database, and the pause survives strategy reloads but not a restart.
+func (o *Orchestrator) PauseStrategy(id uuid.UUID) error {
+	return o.setStrategyPaused(id, true)
+}
+
+// ResumeStrategy evaluates a paused strategy again
+func (o *Orchestrator) ResumeStrategy(id uuid.UUID) error {
+	return o.setStrategyPaused(id, false)
+}
+
+// IsStrategyPaused reports whether a strategy is paused
+func (o *Orchestrator) IsStrategyPaused(id uuid.UUID) bool {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	return o.pausedStrategies[id]
+}
+
+func (o *Orchestrator) setStrategyPaused(id uuid.UUID, paused bool) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if _, ok := o.activeStrategies[id]; !ok && !o.pausedStrategies[id] {
+		return ErrStrategyNotActive
+	}
+	if paused {
+		if o.pausedStrategies == nil {
+			o.pausedStrategies = make(map[uuid.UUID]bool)
+		}
+		o.pausedStrategies[id] = true
+	} else {
+		delete(o.pausedStrategies, id)
+	}
+
+	o.logger.WithFields(logrus.Fields{
+		"strategy_id": id,
+		"paused":      paused,
+	}).Warn("Strategy pause changed by operator")
+	return nil
+}
+
+// AdminHandler serves the strategy admin endpoints:
+//
+//	POST /admin/strategies/{id}/pause
+//	POST /admin/strategies/{id}/resume
+//	POST /admin/strategies/{id}/backtest
+//
+// Every request must send secret in X-Admin-Secret. An empty secret rejects
+// every request.
+func (o *Orchestrator) AdminHandler(secret string) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST "+AdminStrategiesPath+"{id}/pause", o.adminSetPaused(true))
+	mux.HandleFunc("POST "+AdminStrategiesPath+"{id}/resume", o.adminSetPaused(false))
+	mux.HandleFunc("POST "+AdminStrategiesPath+"{id}/backtest", o.adminBacktest)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		given := r.Header.Get(AdminSecretHeader)
+		if secret == "" || subtle.ConstantTimeCompare([]byte(given), []byte(secret)) != 1 {
+			o.logger.WithField("remote_addr", r.RemoteAddr).Warn("Rejected unauthenticated admin request")
+			writeJSONError(w, http.StatusUnauthorized, "unauthorized")
+			return
+		}
+		mux.ServeHTTP(w, r)
+	})
+}
+
+func (o *Orchestrator) adminSetPaused(paused bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := uuid.Parse(r.PathValue("id"))
+		if err != nil {
+			writeJSONError(w, http.StatusBadRequest, "strategy id must be a UUID")
+			return
+		}
+
+		err = o.setStrategyPaused(id, paused)
+		if errors.Is(err, ErrStrategyNotActive) {
+			writeJSONError(w, http.StatusNotFound, err.Error())
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(AdminStrategyResponse{StrategyID: id, Paused: paused})
+	}
+}
@@ -0,0 +1,244 @@
+package bot
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"github.com/yourusername/clever-better/internal/backtest"
+	"github.com/yourusername/clever-better/internal/models"
+	"github.com/yourusername/clever-better/internal/repository"
+	"github.com/yourusername/clever-better/internal/strategy"
+)
+
+// onDemandBacktestTag is stamped on results persisted by the admin endpoint
+const onDemandBacktestTag = "on-demand"
+
+var (
+	// ErrBacktestsDisabled is returned when on-demand backtests are not enabled
+	ErrBacktestsDisabled = errors.New("on-demand backtests are not enabled")
+	// ErrBacktestBusy is returned when every on-demand backtest slot is in use
+	ErrBacktestBusy = errors.New("on-demand backtest limit reached")
+)
+
+// AdminBacktestRequest is the JSON body of the admin backtest endpoint. Dates
+// are YYYY-MM-DD and cover races from the start of StartDate to the start of
+// EndDate, as the backtest CLI does.
+type AdminBacktestRequest struct {
+	StartDate string `json:"start_date"`
+	EndDate   string `json:"end_date"`
+	Persist   bool   `json:"persist"`
+}
+
+// AdminBacktestResponse is the JSON body returned by the admin backtest
+// endpoint
+type AdminBacktestResponse struct {
+	StrategyID uuid.UUID        `json:"strategy_id"`
+	StartDate  string           `json:"start_date"`
+	EndDate    string           `json:"end_date"`
+	Metrics    backtest.Metrics `json:"metrics"`
+	Persisted  bool             `json:"persisted"`
+}
+
+// onDemandBacktests bounds the backtests run through the admin endpoint
+type onDemandBacktests struct {
+	repos   *repository.Repositories
+	slots   chan struct{}
+	timeout time.Duration
+}
+
+// EnableBacktests lets RunBacktest replay history over repos, normally built
+// on the bot's own database connection. At most maxConcurrent backtests run
+// at once, each for at most timeout; zero means no limit.
+func (o *Orchestrator) EnableBacktests(repos *repository.Repositories, maxConcurrent int, timeout time.Duration) {
+	if maxConcurrent < 1 {
+		maxConcurrent = 1
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.backtests = &onDemandBacktests{
+		repos:   repos,
+		slots:   make(chan struct{}, maxConcurrent),
+		timeout: timeout,
+	}
+}
+
+type backtestOutcome struct {
+	metrics   backtest.Metrics
+	persisted bool
+	err       error
+}
+
+// RunBacktest backtests a stored strategy from start to end with the bot's
+// trading settings and returns its metrics, saving the result when persist is
+// set. It returns ErrBacktestBusy at once rather than queueing when every slot
+// is taken, so operators cannot pile work onto the process the trading loop
+// runs in. The backtest holds its slot until the engine stops, even if ctx is
+// cancelled first.
+func (o *Orchestrator) RunBacktest(ctx context.Context, strategyID uuid.UUID, start, end time.Time, persist bool) (backtest.Metrics, bool, error) {
+	o.mu.RLock()
+	runner := o.backtests
+	o.mu.RUnlock()
+	if runner == nil {
+		return backtest.Metrics{}, false, ErrBacktestsDisabled
+	}
+
+	select {
+	case runner.slots <- struct{}{}:
+	default:
+		return backtest.Metrics{}, false, ErrBacktestBusy
+	}
+
+	runCtx, cancel := context.WithCancel(ctx)
+	if runner.timeout > 0 {
+		runCtx, cancel = context.WithTimeout(ctx, runner.timeout)
+	}
+	done := make(chan backtestOutcome, 1)
+	go func() {
+		defer func() { <-runner.slots }()
+		defer cancel()
+		metrics, persisted, err := o.runBacktest(runCtx, runner.repos, strategyID, start, end, persist)
+		done <- backtestOutcome{metrics: metrics, persisted: persisted, err: err}
+	}()
+
+	select {
+	case outcome := <-done:
+		return outcome.metrics, outcome.persisted, outcome.err
+	case <-ctx.Done():
+		return backtest.Metrics{}, false, ctx.Err()
+	}
+}
+
+func (o *Orchestrator) runBacktest(ctx context.Context, repos *repository.Repositories, strategyID uuid.UUID, start, end time.Time, persist bool) (backtest.Metrics, bool, error) {
+	stratModel, err := repos.Strategy.GetByID(ctx, strategyID)
+	if err != nil {
+		return backtest.Metrics{}, false, fmt.Errorf("failed to get strategy: %w", err)
+	}
+	strat, err := o.newStrategy(stratModel)
+	if err != nil {
+		return backtest.Metrics{}, false, err
+	}
+
+	btConfig, err := o.backtestConfig(start, end)
+	if err != nil {
+		return backtest.Metrics{}, false, err
+	}
+	engine, err := backtest.NewEngineWithRepositories(btConfig, repos, strat, o.logger)
+	if err != nil {
+		return backtest.Metrics{}, false, fmt.Errorf("failed to create backtest engine: %w", err)
+	}
+
+	startedAt := time.Now()
+	state, metrics, err := engine.Run(ctx, start, end)
+	if err != nil {
+		return backtest.Metrics{}, false, fmt.Errorf("backtest failed: %w", err)
+	}
+
+	o.logger.WithContext(ctx).WithFields(logrus.Fields{
+		"strategy_id":  strategyID,
+		"start_date":   start.Format("2006-01-02"),
+		"end_date":     end.Format("2006-01-02"),
+		"total_bets":   metrics.TotalBets,
+		"total_return": metrics.TotalReturn,
+		"duration_ms":  time.Since(startedAt).Milliseconds(),
+	}).Info("On-demand backtest completed")
+
+	if !persist {
+		return metrics, false, nil
+	}
+	aggregated := backtest.AggregateResults(metrics, backtest.MonteCarloResult{}, backtest.WalkForwardResult{}, btConfig.Weights)
+	err = backtest.ExportToDatabase(ctx, aggregated, repos.BacktestResult, backtest.ExportDBParams{
+		StrategyID:     strategyID,
+		StartDate:      start,
+		EndDate:        end,
+		InitialCapital: btConfig.InitialBankroll,
+		FinalCapital:   state.CurrentBankroll,
+		Tags:           []string{onDemandBacktestTag},
+	})
+	if err != nil {
+		return metrics, false, fmt.Errorf("failed to persist backtest result: %w", err)
+	}
+	return metrics, true, nil
+}
+
+// backtestConfig builds the backtest settings from the bot's config, with
+// the same trading settings the backtest CLI applies
+func (o *Orchestrator) backtestConfig(start, end time.Time) (backtest.BacktestConfig, error) {
+	btConfig, err := backtest.FromConfig(&o.config.Backtest)
+	if err != nil {
+		return backtest.BacktestConfig{}, fmt.Errorf("invalid backtest config: %w", err)
+	}
+	btConfig.StartDate = start
+	btConfig.EndDate = end
+	btConfig.MarketFilter = o.marketFilter
+	btConfig.IncludeReserveRunners = o.config.Trading.IncludeReserveRunners
+	btConfig.MaxLadderDepth = o.config.Trading.MaxLadderDepth
+	btConfig.OddsSourcePreference = o.config.Trading.OddsSourcePreference
+	btConfig.StakeRounding = strategy.StakeRounding{
+		MinStake:  o.config.Trading.StakeRounding.MinStake,
+		Increment: o.config.Trading.StakeRounding.Increment,
+	}
+	return btConfig, btConfig.Validate()
+}
+
+func (o *Orchestrator) adminBacktest(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, "strategy id must be a UUID")
+		return
+	}
+	var req AdminBacktestRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSONError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	start, err := time.Parse("2006-01-02", req.StartDate)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, "start_date must be YYYY-MM-DD")
+		return
+	}
+	end, err := time.Parse("2006-01-02", req.EndDate)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, "end_date must be YYYY-MM-DD")
+		return
+	}
+	if !start.Before(end) {
+		writeJSONError(w, http.StatusBadRequest, "start_date must be before end_date")
+		return
+	}
+
+	metrics, persisted, err := o.RunBacktest(r.Context(), id, start, end, req.Persist)
+	var unknownType *UnknownStrategyTypeError
+	switch {
+	case errors.Is(err, ErrBacktestsDisabled), errors.Is(err, models.ErrNotFound):
+		writeJSONError(w, http.StatusNotFound, err.Error())
+		return
+	case errors.Is(err, ErrBacktestBusy):
+		writeJSONError(w, http.StatusTooManyRequests, err.Error())
+		return
+	case errors.As(err, &unknownType):
+		writeJSONError(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	case errors.Is(err, backtest.ErrCancelled), errors.Is(err, context.DeadlineExceeded):
+		writeJSONError(w, http.StatusGatewayTimeout, err.Error())
+		return
+	case err != nil:
+		writeJSONError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(AdminBacktestResponse{
+		StrategyID: id,
+		StartDate:  req.StartDate,
+		EndDate:    req.EndDate,
+		Metrics:    metrics,
+		Persisted:  persisted,
+	})
+}
@@ -0,0 +1,247 @@
+package bot
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/clever-better/internal/config"
+	"github.com/yourusername/clever-better/internal/models"
+	"github.com/yourusername/clever-better/internal/repository"
+)
+
+func adminRequest(handler http.Handler, secret, path string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, path, nil)
+	if secret != "" {
+		req.Header.Set(AdminSecretHeader, secret)
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestAdminHandlerPausesAndResumesStrategy(t *testing.T) {
+	f := newSimulateFixture(t)
+	handler := f.orchestrator.AdminHandler("s3cret")
+	ctx := context.Background()
+	race, now := f.stratCtx.Race, f.stratCtx.CurrentTime
+	path := AdminStrategiesPath + f.strategyID.String()
+
+	signals, err := f.orchestrator.evaluateStrategies(ctx, race, now, nil)
+	require.NoError(t, err)
+	require.Len(t, signals, 1)
+
+	rec := adminRequest(handler, "s3cret", path+"/pause")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var response AdminStrategyResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&response))
+	assert.Equal(t, AdminStrategyResponse{StrategyID: f.strategyID, Paused: true}, response)
+
+	signals, err = f.orchestrator.evaluateStrategies(ctx, race, now, nil)
+	require.NoError(t, err)
+	assert.Empty(t, signals, "a paused strategy produces no signals")
+	assert.True(t, f.orchestrator.IsStrategyPaused(f.strategyID))
+
+	rec = adminRequest(handler, "s3cret", path+"/resume")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.False(t, f.orchestrator.IsStrategyPaused(f.strategyID))
+
+	signals, err = f.orchestrator.evaluateStrategies(ctx, race, now, nil)
+	require.NoError(t, err)
+	assert.Len(t, signals, 1, "resuming restores the strategy")
+}
+
+func TestAdminHandlerRejectsBadRequests(t *testing.T) {
+	f := newSimulateFixture(t)
+	path := AdminStrategiesPath + f.strategyID.String() + "/pause"
+
+	for _, tc := range []struct {
+		name    string
+		handler http.Handler
+		secret  string
+		method  string
+		path    string
+		code    int
+	}{
+		{"missing secret", f.orchestrator.AdminHandler("s3cret"), "", http.MethodPost, path, http.StatusUnauthorized},
+		{"wrong secret", f.orchestrator.AdminHandler("s3cret"), "guess", http.MethodPost, path, http.StatusUnauthorized},
+		{"no secret configured", f.orchestrator.AdminHandler(""), "", http.MethodPost, path, http.StatusUnauthorized},
+		{"wrong method", f.orchestrator.AdminHandler("s3cret"), "s3cret", http.MethodGet, path, http.StatusMethodNotAllowed},
+		{"bad id", f.orchestrator.AdminHandler("s3cret"), "s3cret", http.MethodPost, AdminStrategiesPath + "nope/pause", http.StatusBadRequest},
+		{"unknown strategy", f.orchestrator.AdminHandler("s3cret"), "s3cret", http.MethodPost, AdminStrategiesPath + uuid.NewString() + "/pause", http.StatusNotFound},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, tc.path, nil)
+			if tc.secret != "" {
+				req.Header.Set(AdminSecretHeader, tc.secret)
+			}
+			rec := httptest.NewRecorder()
+			tc.handler.ServeHTTP(rec, req)
+			assert.Equal(t, tc.code, rec.Code)
+		})
+	}
+	assert.False(t, f.orchestrator.IsStrategyPaused(f.strategyID))
+}
+
+// backtestStrategyRepo serves stored strategies by ID
+type backtestStrategyRepo struct {
+	repository.StrategyRepository
+	strategies map[uuid.UUID]*models.Strategy
+}
+
+func (r *backtestStrategyRepo) GetByID(ctx context.Context, id uuid.UUID) (*models.Strategy, error) {
+	strat, ok := r.strategies[id]
+	if !ok {
+		return nil, models.ErrNotFound
+	}
+	return strat, nil
+}
+
+// savingBacktestResultRepo records persisted backtest results
+type savingBacktestResultRepo struct {
+	repository.BacktestResultRepository
+	saved []*models.BacktestResult
+}
+
+func (r *savingBacktestResultRepo) SaveResult(ctx context.Context, result *models.BacktestResult) error {
+	r.saved = append(r.saved, result)
+	return nil
+}
+
+// blockingRaceRepo holds race loads until released, signalling each one
+type blockingRaceRepo struct {
+	repository.RaceRepository
+	started chan struct{}
+	release chan struct{}
+}
+
+func (r *blockingRaceRepo) GetByDateRange(ctx context.Context, start, end time.Time) ([]*models.Race, error) {
+	r.started <- struct{}{}
+	select {
+	case <-r.release:
+		return nil, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// newBacktestOrchestrator returns an orchestrator with on-demand backtests
+// over one recorded race in which a seeded simple value strategy backs the
+// winner
+func newBacktestOrchestrator(t *testing.T) (*Orchestrator, uuid.UUID, *repository.Repositories) {
+	t.Helper()
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	scheduled := time.Date(2024, 3, 1, 19, 30, 0, 0, time.UTC)
+	race := &models.Race{ID: uuid.New(), ScheduledStart: scheduled, Track: "Romford"}
+	form := 25.0
+	strong := &models.Runner{ID: uuid.New(), RaceID: race.ID, TrapNumber: 1, Name: "Strong", FormRating: &form}
+	weak := &models.Runner{ID: uuid.New(), RaceID: race.ID, TrapNumber: 2, Name: "Weak"}
+	back, lay, size := 2.98, 3.02, 100.0
+	weakBack, weakLay := 3.95, 4.1
+	winner := 1
+
+	strategyID := uuid.New()
+	runners := &replayRunnerRepo{runners: map[uuid.UUID][]*models.Runner{race.ID: {strong, weak}}}
+	odds := &replayOddsRepo{odds: map[uuid.UUID][]*models.OddsSnapshot{race.ID: {
+		{Time: scheduled.Add(-time.Minute), RaceID: race.ID, RunnerID: strong.ID, BackPrice: &back, BackSize: &size, LayPrice: &lay, LaySize: &size},
+		{Time: scheduled.Add(-time.Minute), RaceID: race.ID, RunnerID: weak.ID, BackPrice: &weakBack, BackSize: &size, LayPrice: &weakLay, LaySize: &size},
+	}}}
+	repos := &repository.Repositories{
+		Race:   &replayRaceRepo{races: []*models.Race{race}, runners: runners, odds: odds},
+		Runner: runners,
+		Odds:   odds,
+		RaceResult: &replayResultRepo{results: map[uuid.UUID]*models.RaceResult{
+			race.ID: {RaceID: race.ID, Time: scheduled.Add(time.Minute), WinnerTrap: &winner},
+		}},
+		Strategy: &backtestStrategyRepo{strategies: map[uuid.UUID]*models.Strategy{
+			strategyID: {ID: strategyID, Name: "value", Type: "simple_value", IsActive: true},
+		}},
+		BacktestResult: &savingBacktestResultRepo{},
+	}
+
+	orchestrator := &Orchestrator{
+		config: &config.Config{Backtest: config.BacktestConfig{
+			StartDate:            "2024-01-01",
+			EndDate:              "2024-06-01",
+			InitialBankroll:      1000,
+			CommissionRate:       0.05,
+			MonteCarloIterations: 1,
+		}},
+		logger: logger,
+	}
+	orchestrator.EnableBacktests(repos, 1, time.Minute)
+	return orchestrator, strategyID, repos
+}
+
+func backtestRequest(handler http.Handler, strategyID uuid.UUID, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, AdminStrategiesPath+strategyID.String()+"/backtest", strings.NewReader(body))
+	req.Header.Set(AdminSecretHeader, "s3cret")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestAdminBacktestReturnsMetrics(t *testing.T) {
+	orchestrator, strategyID, repos := newBacktestOrchestrator(t)
+	handler := orchestrator.AdminHandler("s3cret")
+
+	rec := backtestRequest(handler, strategyID, `{"start_date": "2024-03-01", "end_date": "2024-03-02", "persist": true}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var response AdminBacktestResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&response))
+	assert.Equal(t, strategyID, response.StrategyID)
+	assert.Equal(t, 1, response.Metrics.TotalBets)
+	assert.Greater(t, response.Metrics.TotalReturn, 0.0, "the backed runner won")
+	assert.True(t, response.Persisted)
+
+	saved := repos.BacktestResult.(*savingBacktestResultRepo).saved
+	require.Len(t, saved, 1)
+	assert.Equal(t, strategyID, saved[0].StrategyID)
+	assert.Equal(t, []string{onDemandBacktestTag}, saved[0].Tags)
+
+	rec = backtestRequest(handler, uuid.New(), `{"start_date": "2024-03-01", "end_date": "2024-03-02"}`)
+	assert.Equal(t, http.StatusNotFound, rec.Code, "unknown strategy")
+	rec = backtestRequest(handler, strategyID, `{"start_date": "2024-03-02", "end_date": "2024-03-01"}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code, "reversed dates")
+	rec = backtestRequest((&Orchestrator{logger: orchestrator.logger}).AdminHandler("s3cret"), strategyID, `{"start_date": "2024-03-01", "end_date": "2024-03-02"}`)
+	assert.Equal(t, http.StatusNotFound, rec.Code, "backtests not enabled")
+}
+
+func TestAdminBacktestBoundsConcurrentRuns(t *testing.T) {
+	orchestrator, strategyID, repos := newBacktestOrchestrator(t)
+	races := &blockingRaceRepo{started: make(chan struct{}, 1), release: make(chan struct{})}
+	repos.Race = races
+	handler := orchestrator.AdminHandler("s3cret")
+	body := `{"start_date": "2024-03-01", "end_date": "2024-03-02"}`
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	var first *httptest.ResponseRecorder
+	go func() {
+		defer wg.Done()
+		first = backtestRequest(handler, strategyID, body)
+	}()
+	<-races.started
+
+	rec := backtestRequest(handler, strategyID, body)
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code, "the only slot is taken")
+
+	close(races.release)
+	wg.Wait()
+	assert.Equal(t, http.StatusOK, first.Code)
+
+	rec = backtestRequest(handler, strategyID, body)
+	<-races.started
+	assert.Equal(t, http.StatusOK, rec.Code, "the slot is freed once the run finishes")
+}
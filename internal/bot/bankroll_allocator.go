@@ -0,0 +1,173 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/yourusername/clever-better/internal/config"
+	"github.com/yourusername/clever-better/internal/models"
+	"github.com/yourusername/clever-better/internal/repository"
+)
+
+// Bankroll allocation modes for trading.bankroll_allocation.mode
+const (
+	// AllocationFixed splits the bankroll by the configured shares
+	AllocationFixed = "fixed"
+	// AllocationPerformance scales each share by the strategy's recent return
+	AllocationPerformance = "performance"
+)
+
+const defaultAllocationLookback = 14 * 24 * time.Hour
+
+// BankrollAllocator assigns each active strategy a slice of the bankroll so
+// one strategy cannot use up the stake headroom of the others
+type BankrollAllocator struct {
+	mode     string
+	bankroll float64
+	minShare float64
+	lookback time.Duration
+	betRepo  repository.BetRepository
+	shares   map[uuid.UUID]float64
+	mu       sync.RWMutex
+	logger   *logrus.Logger
+}
+
+// NewBankrollAllocator creates an allocator over the given total bankroll
+func NewBankrollAllocator(cfg config.BankrollAllocationConfig, bankroll float64, betRepo repository.BetRepository, logger *logrus.Logger) *BankrollAllocator {
+	mode := cfg.Mode
+	if mode == "" {
+		mode = AllocationFixed
+	}
+	lookback := time.Duration(cfg.LookbackDays) * 24 * time.Hour
+	if lookback <= 0 {
+		lookback = defaultAllocationLookback
+	}
+
+	return &BankrollAllocator{
+		mode:     mode,
+		bankroll: bankroll,
+		minShare: cfg.MinShare,
+		lookback: lookback,
+		betRepo:  betRepo,
+		shares:   make(map[uuid.UUID]float64),
+		logger:   logger,
+	}
+}
+
+// Rebalance recomputes the share of each strategy from its base weight. In
+// performance mode a weight is scaled by one plus the strategy's return on
+// stakes settled in the lookback window, so winners gain bankroll and losers
+// give it up. Every strategy keeps at least the minimum share. Strategies not
+// in weights lose their allocation.
+func (a *BankrollAllocator) Rebalance(ctx context.Context, weights map[uuid.UUID]float64, now time.Time) (map[uuid.UUID]float64, error) {
+	adjusted := make(map[uuid.UUID]float64, len(weights))
+	for strategyID, weight := range weights {
+		if weight < 0 {
+			weight = 0
+		}
+		if a.mode == AllocationPerformance {
+			roi, err := a.recentReturn(ctx, strategyID, now)
+			if err != nil {
+				return nil, err
+			}
+			weight *= max(1+roi, 0)
+		}
+		adjusted[strategyID] = weight
+	}
+
+	shares := normaliseShares(adjusted, a.minShare)
+
+	a.mu.Lock()
+	a.shares = shares
+	a.mu.Unlock()
+
+	for strategyID, share := range shares {
+		a.logger.WithFields(logrus.Fields{
+			"strategy_id": strategyID,
+			"mode":        a.mode,
+			"share":       share,
+			"allocation":  share * a.bankroll,
+		}).Info("Bankroll allocation rebalanced")
+	}
+
+	return shares, nil
+}
+
+// Allocation returns the slice of bankroll assigned to a strategy. The
+// boolean is false for a strategy with no allocation.
+func (a *BankrollAllocator) Allocation(strategyID uuid.UUID) (float64, bool) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	share, ok := a.shares[strategyID]
+	if !ok {
+		return 0, false
+	}
+	return share * a.bankroll, true
+}
+
+// Allocations returns every strategy's current allocation
+func (a *BankrollAllocator) Allocations() map[uuid.UUID]float64 {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	out := make(map[uuid.UUID]float64, len(a.shares))
+	for strategyID, share := range a.shares {
+		out[strategyID] = share * a.bankroll
+	}
+	return out
+}
+
+// recentReturn is profit over total stake for bets settled in the lookback window
+func (a *BankrollAllocator) recentReturn(ctx context.Context, strategyID uuid.UUID, now time.Time) (float64, error) {
+	bets, err := a.betRepo.GetByStrategyID(ctx, strategyID, now.Add(-a.lookback), now)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get bets for strategy %s: %w", strategyID, err)
+	}
+
+	var staked, profit float64
+	for _, bet := range bets {
+		if bet.Status != models.BetStatusSettled || bet.ProfitLoss == nil {
+			continue
+		}
+		staked += bet.Stake
+		profit += *bet.ProfitLoss
+	}
+	if staked == 0 {
+		return 0, nil
+	}
+	return profit / staked, nil
+}
+
+// normaliseShares turns weights into shares summing to one. Each strategy
+// gets minShare and the rest is split in proportion to weight; when the floor
+// cannot be met for everyone, or no strategy has weight, shares are equal.
+func normaliseShares(weights map[uuid.UUID]float64, minShare float64) map[uuid.UUID]float64 {
+	shares := make(map[uuid.UUID]float64, len(weights))
+	if len(weights) == 0 {
+		return shares
+	}
+
+	total := 0.0
+	for _, weight := range weights {
+		total += weight
+	}
+
+	n := float64(len(weights))
+	if total <= 0 || minShare*n >= 1 {
+		for strategyID := range weights {
+			shares[strategyID] = 1 / n
+		}
+		return shares
+	}
+
+	remaining := 1 - minShare*n
+	for strategyID, weight := range weights {
+		shares[strategyID] = minShare + remaining*weight/total
+	}
+	return shares
+}
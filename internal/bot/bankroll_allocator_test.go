@@ -0,0 +1,118 @@
+package bot
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/clever-better/internal/config"
+	"github.com/yourusername/clever-better/internal/models"
+	"github.com/yourusername/clever-better/internal/strategy"
+)
+
+func settledWithPL(strategyID uuid.UUID, stake, pl float64) *models.Bet {
+	return &models.Bet{
+		ID:         uuid.New(),
+		StrategyID: strategyID,
+		Stake:      stake,
+		Status:     models.BetStatusSettled,
+		ProfitLoss: &pl,
+	}
+}
+
+func TestStrategyCannotExceedAllocation(t *testing.T) {
+	ctx := context.Background()
+	logger := logrus.New()
+	hot, cold := uuid.New(), uuid.New()
+
+	betRepo := new(MockBetRepository)
+	betRepo.On("GetPendingBets", mock.Anything).Return([]*models.Bet{
+		{StrategyID: hot, Stake: 450, Status: models.BetStatusPending},
+	}, nil)
+
+	allocator := NewBankrollAllocator(config.BankrollAllocationConfig{Mode: AllocationFixed}, 1000, betRepo, logger)
+	_, err := allocator.Rebalance(ctx, map[uuid.UUID]float64{hot: 1, cold: 1}, time.Now())
+	require.NoError(t, err)
+
+	riskManager := NewRiskManager(&config.TradingConfig{MaxStakePerBet: 200, MaxExposure: 2000, MaxDailyLoss: 200}, betRepo, logger)
+	riskManager.SetAllocator(allocator)
+	require.NoError(t, riskManager.UpdateExposure(ctx))
+
+	// The hot strategy has 450 of its 500 at risk; the cold one has all 500
+	assert.Error(t, riskManager.CheckStrategyAllocation(hot, 100))
+	assert.NoError(t, riskManager.CheckStrategyAllocation(hot, 50))
+	assert.NoError(t, riskManager.CheckStrategyAllocation(cold, 200))
+
+	stake, err := riskManager.SizeStake(ctx, strategy.LevelStake{Amount: 100}, strategy.Signal{}, hot, 1000, time.Now())
+	require.NoError(t, err)
+	assert.InDelta(t, 50.0, stake, 1e-9, "stake is capped at the remaining allocation")
+
+	// Percentage plans stake from the strategy's slice, not the whole bankroll
+	stake, err = riskManager.SizeStake(ctx, strategy.PercentageStake{Percent: 0.1}, strategy.Signal{}, cold, 1000, time.Now())
+	require.NoError(t, err)
+	assert.InDelta(t, 50.0, stake, 1e-9)
+
+	riskManager.RecordPlacement(cold, "", 200)
+	riskManager.RecordPlacement(cold, "", 200)
+	assert.Error(t, riskManager.CheckStrategyAllocation(cold, 200), "placements count until exposure is refreshed")
+
+	unknown := uuid.New()
+	assert.Error(t, riskManager.CheckStrategyAllocation(unknown, 10), "strategies without an allocation cannot bet")
+}
+
+func TestPerformanceRebalanceShiftsAllocationToWinner(t *testing.T) {
+	ctx := context.Background()
+	logger := logrus.New()
+	winner, loser := uuid.New(), uuid.New()
+
+	betRepo := new(MockBetRepository)
+	betRepo.On("GetByStrategyID", mock.Anything, winner, mock.Anything, mock.Anything).Return([]*models.Bet{
+		settledWithPL(winner, 100, 80),
+		settledWithPL(winner, 100, -30),
+	}, nil)
+	betRepo.On("GetByStrategyID", mock.Anything, loser, mock.Anything, mock.Anything).Return([]*models.Bet{
+		settledWithPL(loser, 100, -100),
+		settledWithPL(loser, 100, 0),
+	}, nil)
+
+	weights := map[uuid.UUID]float64{winner: 1, loser: 1}
+	cfg := config.BankrollAllocationConfig{Mode: AllocationPerformance, MinShare: 0.05}
+	allocator := NewBankrollAllocator(cfg, 1000, betRepo, logger)
+
+	shares, err := allocator.Rebalance(ctx, weights, time.Now())
+	require.NoError(t, err)
+
+	// Returns of +25% and -50% weight the strategies 1.25 and 0.5
+	assert.InDelta(t, 0.05+0.9*1.25/1.75, shares[winner], 1e-9)
+	assert.InDelta(t, 0.05+0.9*0.5/1.75, shares[loser], 1e-9)
+	assert.InDelta(t, 1.0, shares[winner]+shares[loser], 1e-9)
+
+	winnerAllocation, ok := allocator.Allocation(winner)
+	require.True(t, ok)
+	loserAllocation, _ := allocator.Allocation(loser)
+	assert.Greater(t, winnerAllocation, 500.0)
+	assert.Less(t, loserAllocation, 500.0)
+
+	fixed := NewBankrollAllocator(config.BankrollAllocationConfig{Mode: AllocationFixed}, 1000, betRepo, logger)
+	shares, err = fixed.Rebalance(ctx, weights, time.Now())
+	require.NoError(t, err)
+	assert.InDelta(t, 0.5, shares[winner], 1e-9, "fixed mode ignores performance")
+}
+
+func TestNormaliseSharesKeepsFloor(t *testing.T) {
+	a, b := uuid.New(), uuid.New()
+
+	shares := normaliseShares(map[uuid.UUID]float64{a: 1, b: 0}, 0.1)
+	assert.InDelta(t, 0.9, shares[a], 1e-9)
+	assert.InDelta(t, 0.1, shares[b], 1e-9)
+
+	shares = normaliseShares(map[uuid.UUID]float64{a: 0, b: 0}, 0.1)
+	assert.InDelta(t, 0.5, shares[a], 1e-9, "no weight splits equally")
+
+	assert.Empty(t, normaliseShares(nil, 0.1))
+}
@@ -0,0 +1,83 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/yourusername/clever-better/internal/betfair"
+)
+
+// Bankroll sources for trading.bankroll_source
+const (
+	// BankrollSourceFixed sizes stakes against the configured bankroll
+	BankrollSourceFixed = "fixed"
+	// BankrollSourceLiveBalance sizes stakes against the Betfair account
+	BankrollSourceLiveBalance = "live_balance"
+)
+
+// accountFundsCacheTTL bounds how long a fetched account balance is reused
+const accountFundsCacheTTL = 30 * time.Second
+
+// BankrollProvider supplies the bankroll that stakes are sized against
+type BankrollProvider interface {
+	Bankroll(ctx context.Context) (float64, error)
+}
+
+// FixedBankroll always reports the same bankroll
+type FixedBankroll struct {
+	amount float64
+}
+
+// NewFixedBankroll creates a provider for a fixed bankroll
+func NewFixedBankroll(amount float64) *FixedBankroll {
+	return &FixedBankroll{amount: amount}
+}
+
+// Bankroll returns the fixed amount
+func (f *FixedBankroll) Bankroll(ctx context.Context) (float64, error) {
+	return f.amount, nil
+}
+
+// FundsSource reports the balance of a Betfair account
+type FundsSource interface {
+	GetAccountFunds(ctx context.Context) (*betfair.AccountFunds, error)
+}
+
+// LiveBalanceBankroll reports the Betfair account's balance: the amount
+// available to bet plus the funds held against open bets, so stakes do not
+// shrink just because other bets are waiting to settle
+type LiveBalanceBankroll struct {
+	source    FundsSource
+	ttl       time.Duration
+	balance   float64
+	fetchedAt time.Time
+	mu        sync.Mutex
+}
+
+// NewLiveBalanceBankroll creates a provider that caches the balance for ttl
+func NewLiveBalanceBankroll(source FundsSource, ttl time.Duration) *LiveBalanceBankroll {
+	return &LiveBalanceBankroll{source: source, ttl: ttl}
+}
+
+// Bankroll returns the account balance, fetching it once the cached value
+// is older than the TTL
+func (l *LiveBalanceBankroll) Bankroll(ctx context.Context) (float64, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if !l.fetchedAt.IsZero() && time.Since(l.fetchedAt) < l.ttl {
+		return l.balance, nil
+	}
+
+	funds, err := l.source.GetAccountFunds(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get account funds: %w", err)
+	}
+
+	l.balance = funds.AvailableToBetBalance + math.Abs(funds.Exposure)
+	l.fetchedAt = time.Now()
+	return l.balance, nil
+}
@@ -0,0 +1,98 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/clever-better/internal/betfair"
+	"github.com/yourusername/clever-better/internal/config"
+	"github.com/yourusername/clever-better/internal/models"
+	"github.com/yourusername/clever-better/internal/strategy"
+)
+
+// fakeFundsSource reports a fixed account balance
+type fakeFundsSource struct {
+	funds *betfair.AccountFunds
+	err   error
+	calls int
+}
+
+func (f *fakeFundsSource) GetAccountFunds(ctx context.Context) (*betfair.AccountFunds, error) {
+	f.calls++
+	if f.err != nil {
+		return nil, f.err
+	}
+	return f.funds, nil
+}
+
+func TestFixedBankrollReturnsConfiguredValue(t *testing.T) {
+	bankroll, err := NewFixedBankroll(1500).Bankroll(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1500.0, bankroll)
+}
+
+func TestLiveBalanceBankrollReflectsAccountFunds(t *testing.T) {
+	source := &fakeFundsSource{funds: &betfair.AccountFunds{AvailableToBetBalance: 820, Exposure: -180}}
+	provider := NewLiveBalanceBankroll(source, time.Minute)
+
+	bankroll, err := provider.Bankroll(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1000.0, bankroll, "funds held against open bets are still bankroll")
+
+	// Within the TTL the cached balance is reused
+	source.funds = &betfair.AccountFunds{AvailableToBetBalance: 2000}
+	bankroll, err = provider.Bankroll(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1000.0, bankroll)
+	assert.Equal(t, 1, source.calls)
+
+	uncached := NewLiveBalanceBankroll(source, 0)
+	bankroll, err = uncached.Bankroll(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2000.0, bankroll)
+
+	source.err = errors.New("session expired")
+	_, err = uncached.Bankroll(context.Background())
+	assert.Error(t, err)
+}
+
+func TestApplyStakingPlansSizesAgainstBankrollProvider(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	strategyID := uuid.New()
+	betRepo := new(MockBetRepository)
+	cfg := &config.Config{
+		Trading:  config.TradingConfig{MaxStakePerBet: 100, MaxExposure: 500, MaxDailyLoss: 200},
+		Backtest: config.BacktestConfig{InitialBankroll: 1000},
+	}
+	source := &fakeFundsSource{funds: &betfair.AccountFunds{AvailableToBetBalance: 400, Exposure: -100}}
+	orchestrator := &Orchestrator{
+		config:       cfg,
+		riskManager:  NewRiskManager(&cfg.Trading, betRepo, logger),
+		stakingPlans: map[uuid.UUID]strategy.StakingPlan{strategyID: strategy.PercentageStake{Percent: 0.02}},
+		logger:       logger,
+	}
+	signals := []SignalWithContext{{
+		Signal:     strategy.Signal{RunnerID: uuid.New(), Side: models.BetSideBack, Odds: 3.0, Stake: 5},
+		StrategyID: strategyID,
+	}}
+
+	sized := orchestrator.applyStakingPlans(context.Background(), signals, time.Now())
+	require.Len(t, sized, 1)
+	assert.InDelta(t, 20.0, sized[0].Signal.Stake, 1e-9, "without a provider the configured bankroll is used")
+
+	orchestrator.SetBankrollProvider(NewLiveBalanceBankroll(source, 0))
+	sized = orchestrator.applyStakingPlans(context.Background(), signals, time.Now())
+	require.Len(t, sized, 1)
+	assert.InDelta(t, 10.0, sized[0].Signal.Stake, 1e-9, "stakes scale with the live balance")
+
+	source.err = errors.New("session expired")
+	assert.Empty(t, orchestrator.applyStakingPlans(context.Background(), signals, time.Now()))
+}
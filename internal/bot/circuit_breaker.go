@@ -38,15 +38,40 @@ func (s CircuitState) String() string {
 // CircuitBreakerConfig defines circuit breaker thresholds
 type CircuitBreakerConfig struct {
 	MaxConsecutiveLosses int           `json:"max_consecutive_losses"`
+	MaxConsecutiveWins   int           `json:"max_consecutive_wins"`
 	MaxDrawdownPercent   float64       `json:"max_drawdown_percent"`
 	MaxFailureCount      int           `json:"max_failure_count"`
 	FailureTimeWindow    time.Duration `json:"failure_time_window"`
 	CooldownPeriod       time.Duration `json:"cooldown_period"`
+	EventLogSize         int           `json:"event_log_size"`
+}
+
+// DataAnomalyReason starts the reason of a trip caused by results that look
+// too good to be true, such as a settlement bug marking every bet a winner
+const DataAnomalyReason = "Data anomaly"
+
+// defaultEventLogSize is the number of transitions kept when EventLogSize is unset
+const defaultEventLogSize = 100
+
+// CircuitEvent records a circuit state transition and the metrics that
+// triggered it
+type CircuitEvent struct {
+	Timestamp         time.Time `json:"timestamp"`
+	From              string    `json:"from"`
+	To                string    `json:"to"`
+	Reason            string    `json:"reason"`
+	ConsecutiveLosses int       `json:"consecutive_losses"`
+	ConsecutiveWins   int       `json:"consecutive_wins"`
+	Drawdown          float64   `json:"drawdown"`
+	FailureCount      int       `json:"failure_count"`
 }
 
 // ShutdownCallback is called when emergency shutdown is triggered
 type ShutdownCallback func(reason string) error
 
+// EventListener is called for every state transition, e.g. to persist it
+type EventListener func(event CircuitEvent)
+
 // CircuitBreaker implements emergency trading shutdown mechanisms
 type CircuitBreaker struct {
 	config            CircuitBreakerConfig
@@ -54,16 +79,23 @@ type CircuitBreaker struct {
 	failureCount      int
 	lastFailureTime   time.Time
 	consecutiveLosses int
+	consecutiveWins   int
 	drawdown          float64
 	peakBankroll      float64
 	mu                sync.RWMutex
 	logger            *logrus.Logger
 	callbacks         []ShutdownCallback
+	listeners         []EventListener
+	events            []CircuitEvent
 	openedAt          time.Time
+	recovery          *RecoveryRamp
 }
 
 // NewCircuitBreaker creates a new circuit breaker with default config
 func NewCircuitBreaker(config CircuitBreakerConfig, logger *logrus.Logger) *CircuitBreaker {
+	if config.EventLogSize <= 0 {
+		config.EventLogSize = defaultEventLogSize
+	}
 	return &CircuitBreaker{
 		config:       config,
 		state:        CircuitClosed,
@@ -73,7 +105,16 @@ func NewCircuitBreaker(config CircuitBreakerConfig, logger *logrus.Logger) *Circ
 	}
 }
 
-// RecordBetResult tracks bet outcomes for loss streaks and drawdown
+// SetRecoveryRamp scales stakes down each time trading resumes after the
+// circuit opens. A nil ramp resumes at full size.
+func (cb *CircuitBreaker) SetRecoveryRamp(ramp *RecoveryRamp) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	cb.recovery = ramp
+}
+
+// RecordBetResult tracks bet outcomes for loss streaks and drawdown, and for
+// win streaks when MaxConsecutiveWins is set
 func (cb *CircuitBreaker) RecordBetResult(bet *models.Bet, currentBankroll float64) {
 	cb.mu.Lock()
 	defer cb.mu.Unlock()
@@ -88,9 +129,14 @@ func (cb *CircuitBreaker) RecordBetResult(bet *models.Bet, currentBankroll float
 		cb.drawdown = (cb.peakBankroll - currentBankroll) / cb.peakBankroll
 	}
 
+	if cb.recovery != nil {
+		cb.recovery.RecordResult(bet)
+	}
+
 	// Check for bet loss
 	if bet.ProfitLoss != nil && *bet.ProfitLoss < 0 {
 		cb.consecutiveLosses++
+		cb.consecutiveWins = 0
 
 		cb.logger.WithFields(logrus.Fields{
 			"consecutive_losses": cb.consecutiveLosses,
@@ -119,6 +165,15 @@ func (cb *CircuitBreaker) RecordBetResult(bet *models.Bet, currentBankroll float
 	} else if bet.ProfitLoss != nil && *bet.ProfitLoss > 0 {
 		// Reset consecutive losses on win
 		cb.consecutiveLosses = 0
+		cb.consecutiveWins++
+
+		// A win streak this long is more likely bad results data than skill
+		if cb.config.MaxConsecutiveWins > 0 && cb.consecutiveWins >= cb.config.MaxConsecutiveWins {
+			cb.triggerEmergencyShutdownLocked(fmt.Sprintf(
+				"%s: max consecutive wins exceeded (%d >= %d), check settlement and results data",
+				DataAnomalyReason, cb.consecutiveWins, cb.config.MaxConsecutiveWins,
+			))
+		}
 	}
 }
 
@@ -169,8 +224,12 @@ func (cb *CircuitBreaker) IsOpen() bool {
 	if cb.state == CircuitOpen && time.Since(cb.openedAt) > cb.config.CooldownPeriod {
 		cb.mu.RUnlock()
 		cb.mu.Lock()
-		cb.state = CircuitHalfOpen
-		cb.logger.Info("Circuit breaker entering half-open state after cooldown")
+		if cb.state == CircuitOpen {
+			cb.state = CircuitHalfOpen
+			cb.recordEventLocked(CircuitOpen, CircuitHalfOpen, "Cooldown period elapsed")
+			cb.startRecoveryLocked()
+			cb.logger.Info("Circuit breaker entering half-open state after cooldown")
+		}
 		cb.mu.Unlock()
 		cb.mu.RLock()
 	}
@@ -193,8 +252,15 @@ func (cb *CircuitBreaker) Reset() {
 
 	oldState := cb.state
 	cb.state = CircuitClosed
+	if oldState != CircuitClosed {
+		cb.recordEventLocked(oldState, CircuitClosed, "Manual reset")
+	}
+	if oldState == CircuitOpen {
+		cb.startRecoveryLocked()
+	}
 	cb.failureCount = 0
 	cb.consecutiveLosses = 0
+	cb.consecutiveWins = 0
 
 	cb.logger.WithFields(logrus.Fields{
 		"old_state": oldState.String(),
@@ -202,6 +268,16 @@ func (cb *CircuitBreaker) Reset() {
 	}).Info("Circuit breaker manually reset")
 }
 
+// startRecoveryLocked starts the recovery ramp as trading resumes. Assumes
+// lock is held.
+func (cb *CircuitBreaker) startRecoveryLocked() {
+	if cb.recovery == nil {
+		return
+	}
+	cb.recovery.Start()
+	cb.logger.Info("Circuit breaker recovery ramp started, stakes scaled down")
+}
+
 // RegisterShutdownCallback registers a callback for emergency shutdown
 func (cb *CircuitBreaker) RegisterShutdownCallback(callback ShutdownCallback) {
 	cb.mu.Lock()
@@ -210,6 +286,49 @@ func (cb *CircuitBreaker) RegisterShutdownCallback(callback ShutdownCallback) {
 	cb.callbacks = append(cb.callbacks, callback)
 }
 
+// RegisterEventListener registers a listener for state transitions
+func (cb *CircuitBreaker) RegisterEventListener(listener EventListener) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	cb.listeners = append(cb.listeners, listener)
+}
+
+// GetEvents returns the recorded state transitions, oldest first
+func (cb *CircuitBreaker) GetEvents() []CircuitEvent {
+	cb.mu.RLock()
+	defer cb.mu.RUnlock()
+
+	events := make([]CircuitEvent, len(cb.events))
+	copy(events, cb.events)
+	return events
+}
+
+// recordEventLocked appends a transition to the event log, dropping the
+// oldest once it is full, and notifies listeners. Assumes lock is held.
+func (cb *CircuitBreaker) recordEventLocked(from, to CircuitState, reason string) {
+	event := CircuitEvent{
+		Timestamp:         time.Now(),
+		From:              from.String(),
+		To:                to.String(),
+		Reason:            reason,
+		ConsecutiveLosses: cb.consecutiveLosses,
+		ConsecutiveWins:   cb.consecutiveWins,
+		Drawdown:          cb.drawdown,
+		FailureCount:      cb.failureCount,
+	}
+
+	if len(cb.events) >= cb.config.EventLogSize {
+		copy(cb.events, cb.events[1:])
+		cb.events = cb.events[:len(cb.events)-1]
+	}
+	cb.events = append(cb.events, event)
+
+	for _, listener := range cb.listeners {
+		listener(event)
+	}
+}
+
 // TriggerEmergencyShutdown opens circuit and executes all callbacks
 func (cb *CircuitBreaker) TriggerEmergencyShutdown(reason string) {
 	cb.mu.Lock()
@@ -228,12 +347,14 @@ func (cb *CircuitBreaker) triggerEmergencyShutdownLocked(reason string) {
 	oldState := cb.state
 	cb.state = CircuitOpen
 	cb.openedAt = time.Now()
+	cb.recordEventLocked(oldState, CircuitOpen, reason)
 
 	cb.logger.WithFields(logrus.Fields{
 		"old_state":          oldState.String(),
 		"new_state":          cb.state.String(),
 		"reason":             reason,
 		"consecutive_losses": cb.consecutiveLosses,
+		"consecutive_wins":   cb.consecutiveWins,
 		"drawdown":           cb.drawdown,
 		"failure_count":      cb.failureCount,
 		"cooldown_period":    cb.config.CooldownPeriod,
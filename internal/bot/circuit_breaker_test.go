@@ -0,0 +1,166 @@
+package bot
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/clever-better/internal/models"
+)
+
+func newTestCircuitBreaker(cooldown time.Duration, eventLogSize int) *CircuitBreaker {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+	return NewCircuitBreaker(CircuitBreakerConfig{
+		MaxConsecutiveLosses: 3,
+		MaxDrawdownPercent:   0.5,
+		MaxFailureCount:      2,
+		FailureTimeWindow:    time.Minute,
+		CooldownPeriod:       cooldown,
+		EventLogSize:         eventLogSize,
+	}, logger)
+}
+
+func losingBet(stake float64) *models.Bet {
+	pl := -stake
+	return &models.Bet{Stake: stake, ProfitLoss: &pl}
+}
+
+func winningBet(stake float64) *models.Bet {
+	pl := stake
+	return &models.Bet{Stake: stake, ProfitLoss: &pl}
+}
+
+func TestCircuitBreakerEventLogRecordsTransitions(t *testing.T) {
+	cb := newTestCircuitBreaker(10*time.Millisecond, 0)
+
+	var listened []CircuitEvent
+	cb.RegisterEventListener(func(event CircuitEvent) {
+		listened = append(listened, event)
+	})
+
+	// Trip on failures
+	cb.RecordFailure(errors.New("timeout"))
+	cb.RecordFailure(errors.New("timeout"))
+	require.True(t, cb.IsOpen())
+
+	// Cooldown moves the circuit to half-open
+	time.Sleep(20 * time.Millisecond)
+	require.False(t, cb.IsOpen())
+
+	// A loss run trips it again
+	for i := 0; i < 3; i++ {
+		cb.RecordBetResult(losingBet(10), 1000-float64(i+1)*10)
+	}
+	require.Equal(t, CircuitOpen, cb.GetState())
+
+	cb.Reset()
+
+	// Drawdown trips it from closed
+	cb.RecordBetResult(losingBet(600), 400)
+
+	events := cb.GetEvents()
+	require.Len(t, events, 5)
+
+	assert.Equal(t, "CLOSED", events[0].From)
+	assert.Equal(t, "OPEN", events[0].To)
+	assert.Contains(t, events[0].Reason, "Max failure count exceeded")
+	assert.Equal(t, 2, events[0].FailureCount)
+
+	assert.Equal(t, "OPEN", events[1].From)
+	assert.Equal(t, "HALF_OPEN", events[1].To)
+	assert.Equal(t, "Cooldown period elapsed", events[1].Reason)
+
+	assert.Equal(t, "HALF_OPEN", events[2].From)
+	assert.Equal(t, "OPEN", events[2].To)
+	assert.Contains(t, events[2].Reason, "Max consecutive losses exceeded")
+	assert.Equal(t, 3, events[2].ConsecutiveLosses)
+
+	assert.Equal(t, "OPEN", events[3].From)
+	assert.Equal(t, "CLOSED", events[3].To)
+	assert.Equal(t, "Manual reset", events[3].Reason)
+
+	assert.Equal(t, "CLOSED", events[4].From)
+	assert.Equal(t, "OPEN", events[4].To)
+	assert.Contains(t, events[4].Reason, "Max drawdown exceeded")
+	assert.GreaterOrEqual(t, events[4].Drawdown, 0.5)
+
+	for i := 1; i < len(events); i++ {
+		assert.False(t, events[i].Timestamp.Before(events[i-1].Timestamp), "events are oldest first")
+	}
+	assert.Equal(t, events, listened, "listeners see every transition")
+}
+
+func TestCircuitBreakerEventLogDropsOldest(t *testing.T) {
+	cb := newTestCircuitBreaker(time.Hour, 2)
+
+	cb.TriggerEmergencyShutdown("first")
+	cb.Reset()
+	cb.TriggerEmergencyShutdown("second")
+
+	events := cb.GetEvents()
+	require.Len(t, events, 2)
+	assert.Equal(t, "Manual reset", events[0].Reason)
+	assert.Equal(t, "second", events[1].Reason)
+
+	// Duplicate trips and resets of a closed circuit are not transitions
+	cb.TriggerEmergencyShutdown("third")
+	cb.Reset()
+	cb.Reset()
+	events = cb.GetEvents()
+	assert.Equal(t, "second", events[0].Reason)
+	assert.Equal(t, "Manual reset", events[1].Reason)
+}
+
+func TestCircuitBreakerTripsOnImplausibleWinStreak(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+	cb := NewCircuitBreaker(CircuitBreakerConfig{
+		MaxConsecutiveLosses: 3,
+		MaxConsecutiveWins:   10,
+		MaxDrawdownPercent:   0.5,
+		CooldownPeriod:       time.Hour,
+	}, logger)
+
+	var shutdownReason string
+	cb.RegisterShutdownCallback(func(reason string) error {
+		shutdownReason = reason
+		return nil
+	})
+
+	// A loss breaks the streak
+	bankroll := 1000.0
+	for i := 0; i < 9; i++ {
+		bankroll += 10
+		cb.RecordBetResult(winningBet(10), bankroll)
+	}
+	cb.RecordBetResult(losingBet(10), bankroll-10)
+	require.Equal(t, CircuitClosed, cb.GetState())
+
+	for i := 0; i < 10; i++ {
+		bankroll += 10
+		cb.RecordBetResult(winningBet(10), bankroll)
+	}
+	require.True(t, cb.IsOpen())
+	assert.True(t, strings.HasPrefix(shutdownReason, DataAnomalyReason), shutdownReason)
+
+	events := cb.GetEvents()
+	require.Len(t, events, 1)
+	assert.Equal(t, shutdownReason, events[0].Reason)
+	assert.Equal(t, 10, events[0].ConsecutiveWins)
+
+	// Reset clears the streak, and the guard is off when unset
+	cb.Reset()
+	cb.RecordBetResult(winningBet(10), bankroll+10)
+	assert.Equal(t, CircuitClosed, cb.GetState())
+
+	unguarded := newTestCircuitBreaker(time.Hour, 0)
+	for i := 0; i < 100; i++ {
+		unguarded.RecordBetResult(winningBet(10), 1000+float64(i+1)*10)
+	}
+	assert.Equal(t, CircuitClosed, unguarded.GetState())
+}
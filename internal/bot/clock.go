@@ -0,0 +1,60 @@
+package bot
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock supplies the current time to the orchestrator, risk manager and
+// monitor so replays and tests can control it
+type Clock interface {
+	Now() time.Time
+}
+
+// RealClock reads the system clock
+type RealClock struct{}
+
+// Now returns the current system time
+func (RealClock) Now() time.Time {
+	return time.Now()
+}
+
+// MockClock is a clock that only moves when set or advanced
+type MockClock struct {
+	now time.Time
+	mu  sync.RWMutex
+}
+
+// NewMockClock creates a mock clock stopped at start
+func NewMockClock(start time.Time) *MockClock {
+	return &MockClock{now: start}
+}
+
+// Now returns the mock clock's current time
+func (c *MockClock) Now() time.Time {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.now
+}
+
+// Set moves the clock to t
+func (c *MockClock) Set(t time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = t
+}
+
+// Advance moves the clock forward by d
+func (c *MockClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// clockOrReal returns clock, or the system clock when clock is nil
+func clockOrReal(clock Clock) Clock {
+	if clock == nil {
+		return RealClock{}
+	}
+	return clock
+}
@@ -0,0 +1,100 @@
+package bot
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/clever-better/internal/config"
+	"github.com/yourusername/clever-better/internal/models"
+)
+
+func TestMockClockSetAndAdvance(t *testing.T) {
+	start := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	clock := NewMockClock(start)
+	assert.Equal(t, start, clock.Now())
+
+	clock.Advance(90 * time.Second)
+	assert.Equal(t, start.Add(90*time.Second), clock.Now())
+
+	clock.Set(start)
+	assert.Equal(t, start, clock.Now())
+}
+
+func TestDailyLossResetsAcrossMidnightOnMockClock(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	cfg := &config.TradingConfig{MaxStakePerBet: 100, MaxExposure: 500, MaxDailyLoss: 200}
+	betRepo := new(MockBetRepository)
+	rm := NewRiskManager(cfg, betRepo, logger)
+
+	day := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	nextDay := day.AddDate(0, 0, 1)
+	clock := NewMockClock(day.Add(23 * time.Hour))
+	rm.SetClock(clock)
+
+	ctx := context.Background()
+	loss := -250.0
+	betRepo.On("GetSettledBets", ctx, day, nextDay).Return([]*models.Bet{{ProfitLoss: &loss}}, nil).Once()
+	require.NoError(t, rm.UpdateDailyLoss(ctx))
+	assert.InDelta(t, 250.0, rm.GetRiskMetrics().DailyLoss, 1e-9)
+
+	// Still the same day: the limit holds and nothing is reloaded
+	clock.Advance(30 * time.Minute)
+	assert.ErrorContains(t, rm.CheckRiskLimits(ctx, 10), "daily loss limit reached")
+	assert.Equal(t, clock.Now(), rm.GetRiskMetrics().LastUpdate)
+
+	// Past midnight the loss is recalculated for the new day
+	betRepo.On("GetSettledBets", ctx, nextDay, nextDay.AddDate(0, 0, 1)).Return([]*models.Bet{}, nil).Once()
+	clock.Advance(time.Hour)
+	assert.NoError(t, rm.CheckRiskLimits(ctx, 10))
+	assert.Zero(t, rm.GetRiskMetrics().DailyLoss)
+	assert.Equal(t, nextDay.AddDate(0, 0, 1), rm.dailyLossResetTime)
+
+	betRepo.AssertExpectations(t)
+}
+
+func TestClockDrivesDayWindows(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+	ctx := context.Background()
+
+	day := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	nextDay := day.AddDate(0, 0, 1)
+	clock := NewMockClock(day.Add(22 * time.Hour))
+	strategyID := uuid.New()
+
+	betRepo := new(MockBetRepository)
+	betRepo.On("GetByStrategyID", ctx, strategyID, day, day.Add(22*time.Hour)).Return([]*models.Bet{}, nil).Once()
+	betRepo.On("GetByStrategyID", ctx, strategyID, nextDay, nextDay.Add(time.Hour)).Return([]*models.Bet{}, nil).Once()
+	betRepo.On("CountPlacedBetween", ctx, day, nextDay).Return(0, nil).Once()
+	betRepo.On("CountPlacedBetween", ctx, nextDay, nextDay.AddDate(0, 0, 1)).Return(0, nil).Once()
+	betRepo.On("GetSettledBets", ctx, nextDay, nextDay.AddDate(0, 0, 1)).Return([]*models.Bet{}, nil).Once()
+
+	riskManager := NewRiskManager(&config.TradingConfig{MaxStakePerBet: 100, MaxExposure: 500, MaxDailyLoss: 200, MaxBetsPerDay: 5}, betRepo, logger)
+	monitor := NewMonitor(betRepo, nil, nil, nil, 1000, time.Minute, logger)
+	orchestrator := &Orchestrator{riskManager: riskManager, monitor: monitor, logger: logger}
+	orchestrator.SetClock(clock)
+
+	executor := NewExecutor(nil, betRepo, riskManager, true, false, logger, nil)
+
+	_, err := monitor.GetLiveMetrics(ctx, strategyID)
+	require.NoError(t, err)
+	_, err = executor.checkRiskLimits(ctx, 10, strategyID, uuid.New())
+	require.NoError(t, err)
+
+	clock.Advance(3 * time.Hour)
+	assert.Equal(t, nextDay.Add(time.Hour), orchestrator.now())
+
+	_, err = monitor.GetLiveMetrics(ctx, strategyID)
+	require.NoError(t, err)
+	_, err = executor.checkRiskLimits(ctx, 10, strategyID, uuid.New())
+	require.NoError(t, err)
+
+	betRepo.AssertExpectations(t)
+}
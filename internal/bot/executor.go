@@ -2,6 +2,7 @@ package bot
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync"
 	"time"
@@ -9,6 +10,8 @@ import (
 	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
 	"github.com/yourusername/clever-better/internal/betfair"
+	"github.com/yourusername/clever-better/internal/config"
+	"github.com/yourusername/clever-better/internal/metrics"
 	"github.com/yourusername/clever-better/internal/models"
 	"github.com/yourusername/clever-better/internal/repository"
 	"github.com/yourusername/clever-better/internal/strategy"
@@ -21,6 +24,21 @@ type SignalWithContext struct {
 	RaceID      uuid.UUID       `json:"race_id"`
 	MarketID    string          `json:"market_id"`
 	SelectionID uint64          `json:"selection_id"`
+	// OffTime is the race's scheduled start, used to prioritise placement
+	OffTime     time.Time       `json:"off_time"`
+	// InPlay marks signals evaluated on a running race. Betfair holds their
+	// orders for the market's BetDelay before they can match.
+	InPlay      bool            `json:"in_play"`
+	BetDelay    time.Duration   `json:"bet_delay"`
+}
+
+// ExpectedMatchAt returns the earliest time an order for the signal sent at
+// sentAt can match, after any in-play bet delay
+func (s SignalWithContext) ExpectedMatchAt(sentAt time.Time) time.Time {
+	if !s.InPlay {
+		return sentAt
+	}
+	return sentAt.Add(s.BetDelay)
 }
 
 // ExecutorMetrics tracks execution statistics
@@ -29,6 +47,8 @@ type ExecutorMetrics struct {
 	OrdersRejected       int64         `json:"orders_rejected"`
 	PaperTrades          int64         `json:"paper_trades"`
 	LiveTrades           int64         `json:"live_trades"`
+	StaleOddsRejections  int64         `json:"stale_odds_rejections"`
+	ThrottledSignals     int64         `json:"throttled_signals"`
 	AverageExecutionTime time.Duration `json:"average_execution_time"`
 	LastExecutionTime    time.Time     `json:"last_execution_time"`
 }
@@ -40,6 +60,16 @@ type Executor struct {
 	riskManager      *RiskManager
 	paperTradingMode bool
 	liveTradingEnabled bool
+	oddsFilter       *strategy.OddsSanityFilter
+	raceRepo         repository.RaceRepository
+	oddsRepo         repository.OddsRepository
+	placementQueue   *PlacementQueue
+	accountRouter    *AccountRouter
+	stalenessGuard   *StalenessGuard
+	depthGuard       *DepthGuard
+	marketThrottle   *MarketThrottle
+	preventSelfMatch bool
+	stakeRounding    strategy.StakeRounding
 	logger           *logrus.Logger
 	auditLogger      *logrus.Entry
 	metrics          *ExecutorMetrics
@@ -73,6 +103,88 @@ func NewExecutor(
 	}
 }
 
+// SetOddsSanityFilter enables the pre-placement odds check. The race decides
+// which discipline's bounds apply and the latest stored snapshot is the
+// reference for the tick move check.
+func (e *Executor) SetOddsSanityFilter(filter *strategy.OddsSanityFilter, raceRepo repository.RaceRepository, oddsRepo repository.OddsRepository) {
+	e.oddsFilter = filter
+	e.raceRepo = raceRepo
+	e.oddsRepo = oddsRepo
+}
+
+// SetStalenessGuard re-checks the live price of each limit order before it is
+// placed. A nil guard places at the signal odds.
+func (e *Executor) SetStalenessGuard(guard *StalenessGuard) {
+	e.stalenessGuard = guard
+}
+
+// SetDepthGuard checks the ladder can take each limit order's stake before
+// it is placed. A nil guard skips the check.
+func (e *Executor) SetDepthGuard(guard *DepthGuard) {
+	e.depthGuard = guard
+}
+
+// SetMarketThrottle drops signals on a market we bet on less than the
+// throttle's interval ago. A nil throttle places them.
+func (e *Executor) SetMarketThrottle(throttle *MarketThrottle) {
+	e.marketThrottle = throttle
+}
+
+// SetSelfMatchPrevention blocks orders that would cross one of our own
+// unmatched orders on the same selection
+func (e *Executor) SetSelfMatchPrevention(enabled bool) {
+	e.preventSelfMatch = enabled
+}
+
+// SetStakeRounding rounds limit order stakes to what Betfair accepts before
+// the risk check, rejecting any that round below the minimum
+func (e *Executor) SetStakeRounding(rounding strategy.StakeRounding) {
+	e.stakeRounding = rounding
+}
+
+// SetAccountRouter spreads live bets across several Betfair accounts. A nil
+// router places every bet through the executor's betting service.
+func (e *Executor) SetAccountRouter(router *AccountRouter) {
+	e.accountRouter = router
+}
+
+// placerFor returns the service for a routed account, or the executor's own
+// betting service when no account was routed
+func (e *Executor) placerFor(account *BettingAccount) BetPlacer {
+	if account != nil {
+		return account.Service
+	}
+	if e.bettingService == nil {
+		return nil
+	}
+	return e.bettingService
+}
+
+// SetPlacementQueue routes batch placements through a rate-limited priority
+// queue. A nil queue places signals immediately in the order given.
+func (e *Executor) SetPlacementQueue(queue *PlacementQueue) {
+	e.placementQueue = queue
+}
+
+// newOddsSanityFilter builds the filter from per-discipline configuration,
+// keeping the default for any unset price bound
+func newOddsSanityFilter(cfg config.OddsSanityConfig) *strategy.OddsSanityFilter {
+	filter := strategy.DefaultOddsSanityFilter()
+	applyOddsBounds(&filter.Greyhound, cfg.Greyhound)
+	applyOddsBounds(&filter.Horse, cfg.Horse)
+	return filter
+}
+
+func applyOddsBounds(bounds *strategy.OddsBounds, cfg config.OddsBoundsConfig) {
+	if cfg.MinOdds > 0 {
+		bounds.MinOdds = cfg.MinOdds
+	}
+	if cfg.MaxOdds > 0 {
+		bounds.MaxOdds = cfg.MaxOdds
+	}
+	bounds.MaxTickMove = cfg.MaxTickMove
+}
+
 // ExecuteSignal executes a single trading signal
 func (e *Executor) ExecuteSignal(
 	ctx context.Context,
@@ -87,9 +199,31 @@ func (e *Executor) ExecuteSignal(
 		e.updateExecutionMetrics(time.Since(startTime))
 	}()
 
-	// Validate signal with risk manager
-	if err := e.riskManager.CheckRiskLimits(ctx, signal.Stake); err != nil {
-		e.logger.WithFields(logrus.Fields{
+	// BSP bets are sized by liability, which has its own minimums
+	if !signal.BSP {
+		stake, err := e.stakeRounding.Round(signal.Stake)
+		if err != nil {
+			e.logger.WithContext(ctx).WithFields(logrus.Fields{
+				"strategy_id": strategyID,
+				"race_id":     raceID,
+				"runner_id":   signal.RunnerID,
+				"stake":       signal.Stake,
+				"reason":      err.Error(),
+			}).Warn("Signal rejected: stake below exchange minimum")
+
+			e.mu.Lock()
+			e.metrics.OrdersRejected++
+			e.mu.Unlock()
+
+			return nil, fmt.Errorf("stake rounding failed: %w", err)
+		}
+		signal.Stake = stake
+	}
+
+	// Validate signal with risk manager, holding its stake until the bet is recorded
+	reservation, err := e.checkRiskLimits(ctx, signal.Stake, strategyID, raceID)
+	if err != nil {
+		e.logger.WithContext(ctx).WithFields(logrus.Fields{
 			"strategy_id": strategyID,
 			"race_id":     raceID,
 			"runner_id":   signal.RunnerID,
@@ -103,34 +237,181 @@ func (e *Executor) ExecuteSignal(
 
 		return nil, fmt.Errorf("risk limit check failed: %w", err)
 	}
+	defer e.riskManager.ReleaseReservation(reservation)
 
-	// Create bet record
+	if err := e.checkOddsSanity(ctx, signal, raceID); err != nil {
+		e.logger.WithContext(ctx).WithFields(logrus.Fields{
+			"strategy_id": strategyID,
+			"race_id":     raceID,
+			"runner_id":   signal.RunnerID,
+			"odds":        signal.Odds,
+			"reason":      err.Error(),
+		}).Warn("Signal rejected by odds sanity filter")
+
+		e.mu.Lock()
+		e.metrics.OrdersRejected++
+		e.mu.Unlock()
+
+		return nil, fmt.Errorf("odds sanity check failed: %w", err)
+	}
+
+	side := signal.Side
+	if side == "" {
+		side = models.BetSideBack
+	}
+
+	if e.stalenessGuard != nil && !signal.BSP && marketID != "" && selectionID != 0 {
+		odds, err := e.stalenessGuard.Check(ctx, marketID, selectionID, side, signal.Odds)
+		if err != nil {
+			e.logger.WithContext(ctx).WithFields(logrus.Fields{
+				"strategy_id": strategyID,
+				"race_id":     raceID,
+				"runner_id":   signal.RunnerID,
+				"side":        side,
+				"odds":        signal.Odds,
+				"reason":      err.Error(),
+			}).Warn("Signal rejected: odds are stale")
+
+			e.mu.Lock()
+			e.metrics.OrdersRejected++
+			if errors.Is(err, ErrStaleOdds) {
+				e.metrics.StaleOddsRejections++
+			}
+			e.mu.Unlock()
+
+			return nil, fmt.Errorf("odds staleness check failed: %w", err)
+		}
+		if odds != signal.Odds {
+			e.logger.WithContext(ctx).WithFields(logrus.Fields{
+				"strategy_id": strategyID,
+				"runner_id":   signal.RunnerID,
+				"signal_odds": signal.Odds,
+				"odds":        odds,
+			}).Info("Stale signal repriced to current odds")
+			signal.Odds = odds
+		}
+	}
+
+	if e.depthGuard != nil && !signal.BSP && marketID != "" && selectionID != 0 {
+		if err := e.depthGuard.Check(ctx, marketID, selectionID, side, signal.Stake); err != nil {
+			e.logger.WithContext(ctx).WithFields(logrus.Fields{
+				"strategy_id": strategyID,
+				"race_id":     raceID,
+				"runner_id":   signal.RunnerID,
+				"side":        side,
+				"stake":       signal.Stake,
+				"reason":      err.Error(),
+			}).Warn("Signal rejected: not enough size on the ladder")
+
+			e.mu.Lock()
+			e.metrics.OrdersRejected++
+			e.mu.Unlock()
+
+			return nil, fmt.Errorf("ladder depth check failed: %w", err)
+		}
+	}
+
+	if err := e.checkSelfMatch(ctx, signal, side, raceID); err != nil {
+		e.logger.WithContext(ctx).WithFields(logrus.Fields{
+			"strategy_id": strategyID,
+			"race_id":     raceID,
+			"runner_id":   signal.RunnerID,
+			"side":        side,
+			"odds":        signal.Odds,
+			"reason":      err.Error(),
+		}).Warn("Signal rejected to avoid self-matching")
+
+		e.mu.Lock()
+		e.metrics.OrdersRejected++
+		e.mu.Unlock()
+
+		return nil, fmt.Errorf("self-match check failed: %w", err)
+	}
+
+	// Claim the market last so signals rejected above don't hold it. The claim
+	// is released if the bet is never placed.
+	placed := false
+	if e.marketThrottle != nil {
+		release, err := e.marketThrottle.Reserve(throttleKey(marketID, raceID))
+		if err != nil {
+			e.logger.WithContext(ctx).WithFields(logrus.Fields{
+				"strategy_id": strategyID,
+				"race_id":     raceID,
+				"market_id":   marketID,
+				"runner_id":   signal.RunnerID,
+				"reason":      err.Error(),
+			}).Warn("Signal dropped: too soon after our last bet on the market")
+
+			e.mu.Lock()
+			e.metrics.OrdersRejected++
+			e.metrics.ThrottledSignals++
+			e.mu.Unlock()
+
+			return nil, fmt.Errorf("market throttle check failed: %w", err)
+		}
+		defer func() {
+			if !placed {
+				release()
+			}
+		}()
+	}
+
+	// Create bet record, keeping the signal's reasoning so the bet can be explained later
+	expectedValue, confidence := signal.ExpectedValue, signal.Confidence
 	bet := &models.Bet{
-		ID:         uuid.New(),
-		MarketID:   marketID,
-		RaceID:     raceID,
-		RunnerID:   signal.RunnerID,
-		StrategyID: strategyID,
-		MarketType: models.MarketTypeWin,
-		Side:       models.BetSideBack,
-		Odds:       signal.Odds,
-		Stake:      signal.Stake,
-		Status:     models.BetStatusPending,
-		PlacedAt:   time.Now(),
+		ID:               uuid.New(),
+		MarketID:         marketID,
+		RaceID:           raceID,
+		RunnerID:         signal.RunnerID,
+		StrategyID:       strategyID,
+		MarketType:       models.MarketTypeWin,
+		Side:             side,
+		Odds:             signal.Odds,
+		Stake:            signal.Stake,
+		IsBSP:            signal.BSP,
+		Reasoning:        signal.Reasoning,
+		ExpectedValue:    &expectedValue,
+		Confidence:       &confidence,
+		ModelProbability: signal.ModelProbability,
+		Status:           models.BetStatusPending,
+		PlacedAt:         time.Now(),
+	}
+
+	// Pick the Betfair account before the bet is recorded against it
+	var account *BettingAccount
+	if !e.paperTradingMode && e.accountRouter != nil {
+		routed, err := e.accountRouter.Route(strategyID, bet.Stake, e.riskManager.AccountExposures())
+		if err != nil {
+			e.logger.WithContext(ctx).WithFields(logrus.Fields{
+				"strategy_id": strategyID,
+				"race_id":     raceID,
+				"stake":       bet.Stake,
+				"reason":      err.Error(),
+			}).Warn("Signal rejected: no betting account available")
+
+			e.mu.Lock()
+			e.metrics.OrdersRejected++
+			e.mu.Unlock()
+
+			return nil, fmt.Errorf("account routing failed: %w", err)
+		}
+		account = routed
+		bet.Account = account.Name
 	}
 
 	// Store bet in database first
 	if err := e.betRepo.Create(ctx, bet); err != nil {
-		e.logger.WithError(err).Error("Failed to create bet record")
+		e.logger.WithContext(ctx).WithError(err).Error("Failed to create bet record")
 		e.mu.Lock()
 		e.metrics.OrdersRejected++
 		e.mu.Unlock()
 		return nil, fmt.Errorf("failed to create bet record: %w", err)
 	}
+	e.riskManager.ConfirmReservation(reservation, strategyID, bet.Account, bet.Stake)
 
 	// Paper trading mode: simulate execution
 	if e.paperTradingMode {
-		e.logger.WithFields(logrus.Fields{
+		e.logger.WithContext(ctx).WithFields(logrus.Fields{
 			"bet_id":      bet.ID,
 			"strategy_id": strategyID,
 			"race_id":     raceID,
@@ -142,7 +423,7 @@ func (e *Executor) ExecuteSignal(
 
 		// Audit log bet placement
 		if e.auditLogger != nil {
-			e.auditLogger.WithFields(logrus.Fields{
+			e.auditLogger.WithContext(ctx).WithFields(logrus.Fields{
 				"bet_id":        bet.ID.String(),
 				"strategy_id":   strategyID.String(),
 				"market_id":     marketID,
@@ -152,7 +433,7 @@ func (e *Executor) ExecuteSignal(
 				"odds":          signal.Odds,
 				"timestamp":     bet.PlacedAt.Unix(),
 				"paper_trading": true,
-			}).Info("Bet placement recorded")
+			}).WithFields(betReasoningFields(bet)).Info("Bet placement recorded")
 		}
 
 		e.mu.Lock()
@@ -160,11 +441,14 @@ func (e *Executor) ExecuteSignal(
 		e.metrics.PaperTrades++
 		e.mu.Unlock()
 
+		metrics.RecordBetPlacementLatency(metrics.ModePaper, time.Since(startTime).Seconds())
+
+		placed = true
 		return bet, nil
 	}
 
 	if !e.liveTradingEnabled {
-		e.logger.WithFields(logrus.Fields{
+		e.logger.WithContext(ctx).WithFields(logrus.Fields{
 			"bet_id":      bet.ID,
 			"strategy_id": strategyID,
 			"race_id":     raceID,
@@ -180,21 +464,34 @@ func (e *Executor) ExecuteSignal(
 		return nil, fmt.Errorf("live trading disabled")
 	}
 
-	if e.bettingService == nil {
+	placer := e.placerFor(account)
+	if placer == nil {
 		return nil, fmt.Errorf("betting service is not initialized")
 	}
 
+	if marketID == "" || selectionID == 0 {
+		e.mu.Lock()
+		e.metrics.OrdersRejected++
+		e.mu.Unlock()
+
+		return nil, fmt.Errorf("runner %s has no Betfair selection mapping", signal.RunnerID)
+	}
+
 	// Live trading mode: execute via Betfair API
-	betfairBetID, err := e.bettingService.PlaceBet(ctx, &betfair.PlaceBetRequest{
-		MarketID:    marketID,
-		SelectionID: selectionID,
-		Side:        string(bet.Side),
-		Odds:        bet.Odds,
-		Stake:       bet.Stake,
-	})
+	var betfairBetID string
+	var report *betfair.InstructionReport
+	if bet.IsBSP {
+		betfairBetID, err = placer.PlaceBSPBet(ctx, marketID, selectionID, bspLiability(bet), string(bet.Side))
+	} else {
+		report, err = placer.PlaceBetWithReport(ctx, marketID, selectionID, bet.Odds, bet.Stake, string(bet.Side))
+		if report != nil {
+			betfairBetID = report.BetID
+		}
+	}
+	metrics.RecordBetPlacementLatency(metrics.ModeLive, time.Since(startTime).Seconds())
 
 	if err != nil {
-		e.logger.WithFields(logrus.Fields{
+		e.logger.WithContext(ctx).WithFields(logrus.Fields{
 			"bet_id":    bet.ID,
 			"market_id": marketID,
 			"runner_id": signal.RunnerID,
@@ -206,7 +503,7 @@ func (e *Executor) ExecuteSignal(
 		now := time.Now()
 		bet.CancelledAt = &now
 		if updateErr := e.betRepo.Update(ctx, bet); updateErr != nil {
-			e.logger.WithError(updateErr).Error("Failed to update cancelled bet")
+			e.logger.WithContext(ctx).WithError(updateErr).Error("Failed to update cancelled bet")
 		}
 
 		e.mu.Lock()
@@ -216,14 +513,17 @@ func (e *Executor) ExecuteSignal(
 		return nil, fmt.Errorf("failed to place bet with Betfair: %w", err)
 	}
 
-	// Update bet record with Betfair bet ID
+	// Update bet record with Betfair bet ID and whatever matched on placement
 	bet.BetID = betfairBetID
+	if report != nil {
+		applyPlacementReport(bet, report, time.Now())
+	}
 	if err := e.betRepo.Update(ctx, bet); err != nil {
-		e.logger.WithError(err).Error("Failed to update bet with Betfair ID")
+		e.logger.WithContext(ctx).WithError(err).Error("Failed to update bet with Betfair ID")
 		// Note: bet was placed successfully, so we don't return error
 	}
 
-	e.logger.WithFields(logrus.Fields{
+	e.logger.WithContext(ctx).WithFields(logrus.Fields{
 		"bet_id":         bet.ID,
 		"betfair_bet_id": betfairBetID,
 		"strategy_id":    strategyID,
@@ -233,12 +533,15 @@ func (e *Executor) ExecuteSignal(
 		"side":           bet.Side,
 		"odds":           bet.Odds,
 		"stake":          bet.Stake,
+		"matched_price":  bet.SettlementPrice(),
+		"unmatched_size": bet.UnmatchedSize(),
+		"status":         bet.Status,
 		"confidence":     signal.Confidence,
 	}).Info("Live bet executed successfully")
 
 	// Audit log live bet placement
 	if e.auditLogger != nil {
-		e.auditLogger.WithFields(logrus.Fields{
+		e.auditLogger.WithContext(ctx).WithFields(logrus.Fields{
 			"bet_id":        bet.ID.String(),
 			"strategy_id":   strategyID.String(),
 			"market_id":     marketID,
@@ -248,7 +551,7 @@ func (e *Executor) ExecuteSignal(
 			"odds":          bet.Odds,
 			"timestamp":     bet.PlacedAt.Unix(),
 			"paper_trading": false,
-		}).Info("Bet placement recorded")
+		}).WithFields(betReasoningFields(bet)).Info("Bet placement recorded")
 	}
 
 	e.mu.Lock()
@@ -256,18 +559,112 @@ func (e *Executor) ExecuteSignal(
 	e.metrics.LiveTrades++
 	e.mu.Unlock()
 
+	placed = true
 	return bet, nil
 }
 
-// ExecuteBatch executes multiple signals efficiently
-func (e *Executor) ExecuteBatch(ctx context.Context, signals []SignalWithContext) ([]*models.Bet, error) {
-	bets := make([]*models.Bet, 0, len(signals))
-	errors := make([]error, 0)
+// throttleKey identifies the market a bet is throttled on, falling back to
+// the race for bets without a Betfair market ID
+func throttleKey(marketID string, raceID uuid.UUID) string {
+	if marketID != "" {
+		return marketID
+	}
+	return raceID.String()
+}
+
+// bspLiability converts a bet stake into the liability Betfair expects on a
+// MARKET_ON_CLOSE order. Lay liability is estimated from the indicative odds
+// since the final SP is unknown at placement.
+func bspLiability(bet *models.Bet) float64 {
+	if bet.Side == models.BetSideLay && bet.Odds > 1 {
+		return (bet.Odds - 1.0) * bet.Stake
+	}
+	return bet.Stake
+}
+
+// checkRiskLimits applies the risk manager's bet count limits, then reserves
+// the stake against the stake, exposure and strategy allocation limits
+func (e *Executor) checkRiskLimits(ctx context.Context, stake float64, strategyID, raceID uuid.UUID) (ExposureReservation, error) {
+	if err := e.riskManager.CheckBetCounts(ctx, raceID, e.riskManager.Now()); err != nil {
+		return 0, err
+	}
+	return e.riskManager.ReserveExposure(ctx, strategyID, stake)
+}
 
-	e.logger.WithField("signal_count", len(signals)).Info("Executing batch of signals")
+// checkSelfMatch rejects an order that would cross one of our own unmatched
+// orders on the selection
+func (e *Executor) checkSelfMatch(ctx context.Context, signal strategy.Signal, side models.BetSide, raceID uuid.UUID) error {
+	if !e.preventSelfMatch || signal.BSP {
+		return nil
+	}
+
+	existing, err := e.betRepo.GetByRaceID(ctx, raceID)
+	if err != nil {
+		return fmt.Errorf("failed to load race bets: %w", err)
+	}
+
+	if crossing := findCrossingOrder(signal.RunnerID, side, signal.Odds, existing); crossing != nil {
+		return fmt.Errorf("%s at %.2f would cross our unmatched %s %s at %.2f",
+			side, signal.Odds, crossing.Side, crossing.ID, crossing.Odds)
+	}
+	return nil
+}
 
-	for _, signalCtx := range signals {
-		bet, err := e.ExecuteSignal(
+// checkOddsSanity rejects implausible prices. BSP bets are exempt since their
+// odds are only indicative. Missing race or odds data falls back to the
+// greyhound bounds without a tick move check.
+func (e *Executor) checkOddsSanity(ctx context.Context, signal strategy.Signal, raceID uuid.UUID) error {
+	if e.oddsFilter == nil || signal.BSP {
+		return nil
+	}
+
+	var race *models.Race
+	if e.raceRepo != nil {
+		if r, err := e.raceRepo.GetByID(ctx, raceID); err == nil {
+			race = r
+		}
+	}
+
+	previous := 0.0
+	if e.oddsRepo != nil {
+		if latest, err := e.oddsRepo.GetLatest(ctx, raceID, signal.RunnerID); err == nil && latest != nil {
+			previous = latest.GetMidPrice()
+		}
+	}
+
+	return e.oddsFilter.Check(race, signal.Odds, previous)
+}
+
+// BatchExecutionResult is the outcome of one signal in a batch. Exactly one of
+// Bet and Err is set.
+type BatchExecutionResult struct {
+	Signal SignalWithContext
+	Bet    *models.Bet
+	Err    error
+}
+
+// ExecuteBatch executes multiple signals efficiently. A failing signal does
+// not stop the rest: the placed bets are returned along with one result per
+// signal, in order, and an aggregate error if any signal failed. With a
+// placement queue set, signals are dispatched soonest-off first within its
+// rate limit.
+func (e *Executor) ExecuteBatch(ctx context.Context, signals []SignalWithContext) ([]*models.Bet, []BatchExecutionResult, error) {
+	bets := make([]*models.Bet, 0, len(signals))
+	var failures []error
+
+	e.logger.WithContext(ctx).WithField("signal_count", len(signals)).Info("Executing batch of signals")
+
+	place := func(signalCtx SignalWithContext) (*models.Bet, error) {
+		if signalCtx.InPlay {
+			e.logger.WithContext(ctx).WithFields(logrus.Fields{
+				"strategy_id":       signalCtx.StrategyID,
+				"race_id":           signalCtx.RaceID,
+				"market_id":         signalCtx.MarketID,
+				"bet_delay":         signalCtx.BetDelay,
+				"expected_match_at": signalCtx.ExpectedMatchAt(time.Now()),
+			}).Debug("Placing in-play signal behind the bet delay")
+		}
+		return e.ExecuteSignal(
 			ctx,
 			signalCtx.Signal,
 			signalCtx.StrategyID,
@@ -275,32 +672,70 @@ func (e *Executor) ExecuteBatch(ctx context.Context, signals []SignalWithContext
 			signalCtx.MarketID,
 			signalCtx.SelectionID,
 		)
+	}
 
-		if err != nil {
-			e.logger.WithFields(logrus.Fields{
-				"strategy_id": signalCtx.StrategyID,
-				"race_id":     signalCtx.RaceID,
-				"error":       err.Error(),
-			}).Warn("Failed to execute signal in batch")
-			errors = append(errors, err)
+	var results []BatchExecutionResult
+	if e.placementQueue != nil {
+		results = e.placementQueue.Dispatch(ctx, signals, place)
+	} else {
+		results = make([]BatchExecutionResult, 0, len(signals))
+		for _, signalCtx := range signals {
+			bet, err := place(signalCtx)
+			results = append(results, BatchExecutionResult{Signal: signalCtx, Bet: bet, Err: err})
+		}
+	}
+
+	for _, result := range results {
+		if result.Err != nil {
+			if errors.Is(result.Err, ErrPlacementWindowClosed) {
+				e.mu.Lock()
+				e.metrics.OrdersRejected++
+				e.mu.Unlock()
+			}
+			failures = append(failures, result.Err)
 			continue
 		}
 
-		bets = append(bets, bet)
+		bets = append(bets, result.Bet)
 	}
 
-	e.logger.WithFields(logrus.Fields{
+	e.logger.WithContext(ctx).WithFields(logrus.Fields{
 		"total_signals":    len(signals),
 		"successful_bets":  len(bets),
-		"failed_bets":      len(errors),
+		"failed_bets":      len(failures),
 		"paper_trading":    e.paperTradingMode,
 	}).Info("Batch execution completed")
 
-	if len(errors) > 0 {
-		return bets, fmt.Errorf("batch execution completed with %d errors", len(errors))
+	if len(failures) > 0 {
+		return bets, results, fmt.Errorf("batch execution completed with %d errors: %w", len(failures), errors.Join(failures...))
 	}
 
-	return bets, nil
+	return bets, results, nil
+}
+
+// applyPlacementReport records the fill Betfair reported on placement. The
+// exchange may match at a better price than requested, so the average matched
+// price is kept for settlement while Odds stays the limit price any unmatched
+// remainder rests at. A fully matched bet is marked matched; one that matched
+// nothing stays pending with its whole stake unmatched, so the order manager
+// can monitor or cancel it.
+func applyPlacementReport(bet *models.Bet, report *betfair.InstructionReport, now time.Time) {
+	if report.SizeMatched <= 0 || report.AveragePriceMatched <= 1 {
+		unmatched := 0.0
+		bet.MatchedPrice = nil
+		bet.MatchedSize = &unmatched
+		bet.MatchedAt = nil
+		bet.Status = models.BetStatusPending
+		return
+	}
+
+	price, size := report.AveragePriceMatched, report.SizeMatched
+	bet.MatchedPrice = &price
+	bet.MatchedSize = &size
+	if size >= bet.Stake-0.005 {
+		bet.Status = models.BetStatusMatched
+		bet.MatchedAt = &now
+	}
 }
 
 // CancelBet cancels an unmatched bet via Betfair API
@@ -323,7 +758,7 @@ func (e *Executor) CancelBet(ctx context.Context, betID uuid.UUID) error {
 			return fmt.Errorf("failed to update cancelled bet: %w", err)
 		}
 
-		e.logger.WithField("bet_id", betID).Info("Paper trade cancelled (simulated)")
+		e.logger.WithContext(ctx).WithField("bet_id", betID).Info("Paper trade cancelled (simulated)")
 		return nil
 	}
 
@@ -332,8 +767,21 @@ func (e *Executor) CancelBet(ctx context.Context, betID uuid.UUID) error {
 		return fmt.Errorf("bet has no Betfair bet ID")
 	}
 
-	if err := e.bettingService.CancelBet(ctx, bet.MarketID, bet.BetID); err != nil {
-		e.logger.WithFields(logrus.Fields{
+	var account *BettingAccount
+	if e.accountRouter != nil {
+		found, ok := e.accountRouter.Account(bet.Account)
+		if !ok {
+			return fmt.Errorf("unknown betting account %q", bet.Account)
+		}
+		account = found
+	}
+	placer := e.placerFor(account)
+	if placer == nil {
+		return fmt.Errorf("betting service is not initialized")
+	}
+
+	if err := placer.CancelBet(ctx, bet.MarketID, bet.BetID); err != nil {
+		e.logger.WithContext(ctx).WithFields(logrus.Fields{
 			"bet_id":         betID,
 			"betfair_bet_id": bet.BetID,
 			"error":          err.Error(),
@@ -349,7 +797,7 @@ func (e *Executor) CancelBet(ctx context.Context, betID uuid.UUID) error {
 		return fmt.Errorf("failed to update cancelled bet: %w", err)
 	}
 
-	e.logger.WithFields(logrus.Fields{
+	e.logger.WithContext(ctx).WithFields(logrus.Fields{
 		"bet_id":         betID,
 		"betfair_bet_id": bet.BetID,
 	}).Info("Bet cancelled successfully")
@@ -365,6 +813,21 @@ func (e *Executor) GetMetrics() ExecutorMetrics {
 	return *e.metrics
 }
 
+// betReasoningFields returns the audit fields explaining why a bet was placed
+func betReasoningFields(bet *models.Bet) logrus.Fields {
+	fields := logrus.Fields{"reasoning": bet.Reasoning}
+	if bet.ExpectedValue != nil {
+		fields["expected_value"] = *bet.ExpectedValue
+	}
+	if bet.Confidence != nil {
+		fields["confidence"] = *bet.Confidence
+	}
+	if bet.ModelProbability != nil {
+		fields["model_probability"] = *bet.ModelProbability
+	}
+	return fields
+}
+
 // updateExecutionMetrics updates execution time statistics
 func (e *Executor) updateExecutionMetrics(duration time.Duration) {
 	e.mu.Lock()
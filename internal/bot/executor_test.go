@@ -0,0 +1,381 @@
+package bot
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/clever-better/internal/betfair"
+	"github.com/yourusername/clever-better/internal/config"
+	"github.com/yourusername/clever-better/internal/metrics"
+	"github.com/yourusername/clever-better/internal/models"
+	"github.com/yourusername/clever-better/internal/strategy"
+)
+
+// histogramSnapshot returns the sample count and sum observed by a histogram
+func histogramSnapshot(t *testing.T, observer prometheus.Observer) (uint64, float64) {
+	t.Helper()
+	var m dto.Metric
+	require.NoError(t, observer.(prometheus.Metric).Write(&m))
+	return m.GetHistogram().GetSampleCount(), m.GetHistogram().GetSampleSum()
+}
+
+func TestExecuteSignalRecordsPaperPlacementLatency(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	betRepo := new(MockBetRepository)
+	betRepo.On("Create", mock.Anything, mock.Anything).Return(nil).After(20 * time.Millisecond)
+
+	riskManager := NewRiskManager(&config.TradingConfig{
+		MaxStakePerBet: 100,
+		MaxExposure:    500,
+		MaxDailyLoss:   200,
+	}, betRepo, logger)
+	executor := NewExecutor(nil, betRepo, riskManager, true, false, logger, nil)
+
+	paper := metrics.BetPlacementLatency.WithLabelValues(metrics.ModePaper)
+	live := metrics.BetPlacementLatency.WithLabelValues(metrics.ModeLive)
+	paperCount, paperSum := histogramSnapshot(t, paper)
+	liveCount, _ := histogramSnapshot(t, live)
+
+	signal := strategy.Signal{RunnerID: uuid.New(), Side: models.BetSideBack, Odds: 3.0, Stake: 10}
+	_, err := executor.ExecuteSignal(context.Background(), signal, uuid.New(), uuid.New(), "1.234", 1)
+	require.NoError(t, err)
+
+	count, sum := histogramSnapshot(t, paper)
+	assert.Equal(t, paperCount+1, count)
+	assert.GreaterOrEqual(t, sum-paperSum, 0.02, "injected latency is observed")
+
+	count, _ = histogramSnapshot(t, live)
+	assert.Equal(t, liveCount, count, "paper orders are not recorded as live")
+}
+
+func TestExecuteSignalRecordsSignalReasoning(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	betRepo := new(MockBetRepository)
+	betRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+
+	riskManager := NewRiskManager(&config.TradingConfig{
+		MaxStakePerBet: 100,
+		MaxExposure:    500,
+		MaxDailyLoss:   200,
+	}, betRepo, logger)
+	executor := NewExecutor(nil, betRepo, riskManager, true, false, logger, nil)
+
+	probability := 0.42
+	signal := strategy.Signal{
+		RunnerID:         uuid.New(),
+		Side:             models.BetSideBack,
+		Odds:             3.0,
+		Stake:            10,
+		Confidence:       0.8,
+		ExpectedValue:    0.26,
+		Reasoning:        "model probability 0.42 beats implied 0.33",
+		ModelProbability: &probability,
+	}
+	bet, err := executor.ExecuteSignal(context.Background(), signal, uuid.New(), uuid.New(), "1.234", 1)
+	require.NoError(t, err)
+
+	created := betRepo.Calls[0].Arguments.Get(1).(*models.Bet)
+	assert.Same(t, bet, created)
+	assert.Equal(t, signal.Reasoning, created.Reasoning)
+	assert.Equal(t, 0.26, *created.ExpectedValue)
+	assert.Equal(t, 0.8, *created.Confidence)
+	assert.Equal(t, 0.42, *created.ModelProbability)
+}
+
+func TestConcurrentSignalsNeverExceedMaxExposure(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	// A slow insert widens the window between risk check and placement
+	betRepo := new(MockBetRepository)
+	betRepo.On("Create", mock.Anything, mock.Anything).Return(nil).After(5 * time.Millisecond)
+
+	riskManager := NewRiskManager(&config.TradingConfig{
+		MaxStakePerBet: 100,
+		MaxExposure:    95,
+		MaxDailyLoss:   200,
+	}, betRepo, logger)
+	executor := NewExecutor(nil, betRepo, riskManager, true, false, logger, nil)
+
+	const signals = 50
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		placed float64
+	)
+	for i := 0; i < signals; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			signal := strategy.Signal{RunnerID: uuid.New(), Side: models.BetSideBack, Odds: 3.0, Stake: 10}
+			bet, err := executor.ExecuteSignal(context.Background(), signal, uuid.New(), uuid.New(), "1.234", 1)
+			if err != nil {
+				return
+			}
+			mu.Lock()
+			placed += bet.Stake
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 90.0, placed, "signals fill capacity without exceeding it")
+	metrics := riskManager.GetRiskMetrics()
+	assert.LessOrEqual(t, metrics.CurrentExposure, 95.0)
+	assert.Zero(t, metrics.ReservedExposure, "every reservation is confirmed or released")
+	assert.Equal(t, int64(signals-9), executor.GetMetrics().OrdersRejected)
+}
+
+func TestExecuteSignalRejectsImplausibleOdds(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	betRepo := new(MockBetRepository)
+	betRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+
+	riskManager := NewRiskManager(&config.TradingConfig{
+		MaxStakePerBet: 100,
+		MaxExposure:    500,
+		MaxDailyLoss:   200,
+	}, betRepo, logger)
+	executor := NewExecutor(nil, betRepo, riskManager, true, false, logger, nil)
+	executor.SetOddsSanityFilter(newOddsSanityFilter(config.OddsSanityConfig{Enabled: true}), nil, nil)
+
+	spike := strategy.Signal{RunnerID: uuid.New(), Side: models.BetSideBack, Odds: 1001.0, Stake: 10}
+	_, err := executor.ExecuteSignal(context.Background(), spike, uuid.New(), uuid.New(), "1.234", 1)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "odds sanity check failed")
+	assert.Equal(t, int64(1), executor.GetMetrics().OrdersRejected)
+	betRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+
+	normal := strategy.Signal{RunnerID: uuid.New(), Side: models.BetSideBack, Odds: 3.5, Stake: 10}
+	_, err = executor.ExecuteSignal(context.Background(), normal, uuid.New(), uuid.New(), "1.234", 1)
+	require.NoError(t, err)
+
+	bsp := strategy.Signal{RunnerID: uuid.New(), Side: models.BetSideBack, Odds: 1001.0, Stake: 10, BSP: true}
+	_, err = executor.ExecuteSignal(context.Background(), bsp, uuid.New(), uuid.New(), "1.234", 1)
+	require.NoError(t, err, "BSP odds are indicative and not checked")
+}
+
+func TestExecuteSignalRoundsStake(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	betRepo := new(MockBetRepository)
+	betRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+
+	riskManager := NewRiskManager(&config.TradingConfig{
+		MaxStakePerBet: 100,
+		MaxExposure:    500,
+		MaxDailyLoss:   200,
+	}, betRepo, logger)
+	executor := NewExecutor(nil, betRepo, riskManager, true, false, logger, nil)
+	executor.SetStakeRounding(strategy.StakeRounding{MinStake: 1, Increment: 0.01})
+
+	signal := strategy.Signal{RunnerID: uuid.New(), Side: models.BetSideBack, Odds: 3.0, Stake: 3.337}
+	bet, err := executor.ExecuteSignal(context.Background(), signal, uuid.New(), uuid.New(), "1.234", 1)
+	require.NoError(t, err)
+	assert.Equal(t, 3.33, bet.Stake)
+
+	dust := strategy.Signal{RunnerID: uuid.New(), Side: models.BetSideBack, Odds: 3.0, Stake: 0.999}
+	_, err = executor.ExecuteSignal(context.Background(), dust, uuid.New(), uuid.New(), "1.234", 1)
+	require.ErrorIs(t, err, strategy.ErrStakeBelowMinimum)
+	assert.Equal(t, int64(1), executor.GetMetrics().OrdersRejected)
+	betRepo.AssertNumberOfCalls(t, "Create", 1)
+}
+
+func TestExecuteBatchReportsPerSignalResults(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	betRepo := new(MockBetRepository)
+	betRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+
+	riskManager := NewRiskManager(&config.TradingConfig{
+		MaxStakePerBet: 100,
+		MaxExposure:    500,
+		MaxDailyLoss:   200,
+	}, betRepo, logger)
+	executor := NewExecutor(nil, betRepo, riskManager, true, false, logger, nil)
+
+	signalFor := func(stake float64) SignalWithContext {
+		return SignalWithContext{
+			Signal:      strategy.Signal{RunnerID: uuid.New(), Side: models.BetSideBack, Odds: 3.0, Stake: stake},
+			StrategyID:  uuid.New(),
+			RaceID:      uuid.New(),
+			MarketID:    "1.234",
+			SelectionID: 1,
+		}
+	}
+	signals := []SignalWithContext{signalFor(10), signalFor(250), signalFor(20)}
+
+	bets, results, err := executor.ExecuteBatch(context.Background(), signals)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "1 errors")
+	assert.Len(t, bets, 2, "existing bets slice still carries the placed bets")
+	require.Len(t, results, 3)
+
+	for i, result := range results {
+		assert.Equal(t, signals[i].Signal.RunnerID, result.Signal.Signal.RunnerID, "results keep signal order")
+	}
+
+	assert.NoError(t, results[0].Err)
+	require.NotNil(t, results[0].Bet)
+	assert.Equal(t, 10.0, results[0].Bet.Stake)
+
+	assert.Nil(t, results[1].Bet)
+	require.Error(t, results[1].Err)
+	assert.Contains(t, results[1].Err.Error(), "risk limit check failed")
+	assert.ErrorIs(t, err, results[1].Err)
+
+	assert.NoError(t, results[2].Err)
+	require.NotNil(t, results[2].Bet)
+	assert.Equal(t, 20.0, results[2].Bet.Stake)
+}
+
+func TestExecuteSignalBlocksSelfMatch(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	raceID := uuid.New()
+	runnerID := uuid.New()
+	otherRunner := uuid.New()
+	existing := []*models.Bet{
+		{ID: uuid.New(), RaceID: raceID, RunnerID: runnerID, Side: models.BetSideBack, Odds: 3.0, Stake: 10, Status: models.BetStatusPending},
+		{ID: uuid.New(), RaceID: raceID, RunnerID: otherRunner, Side: models.BetSideBack, Odds: 2.0, Stake: 10, Status: models.BetStatusMatched},
+	}
+
+	betRepo := new(MockBetRepository)
+	betRepo.On("GetByRaceID", mock.Anything, raceID).Return(existing, nil)
+	betRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+
+	riskManager := NewRiskManager(&config.TradingConfig{
+		MaxStakePerBet: 100,
+		MaxExposure:    500,
+		MaxDailyLoss:   200,
+	}, betRepo, logger)
+	executor := NewExecutor(nil, betRepo, riskManager, true, false, logger, nil)
+	executor.SetSelfMatchPrevention(true)
+
+	crossing := strategy.Signal{RunnerID: runnerID, Side: models.BetSideLay, Odds: 3.2, Stake: 10}
+	_, err := executor.ExecuteSignal(context.Background(), crossing, uuid.New(), raceID, "1.234", 1)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "self-match check failed")
+	assert.Equal(t, int64(1), executor.GetMetrics().OrdersRejected)
+	betRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+
+	atSamePrice := strategy.Signal{RunnerID: runnerID, Side: models.BetSideLay, Odds: 3.0, Stake: 10}
+	_, err = executor.ExecuteSignal(context.Background(), atSamePrice, uuid.New(), raceID, "1.234", 1)
+	require.Error(t, err, "a lay at the back's price matches it")
+
+	belowBack := strategy.Signal{RunnerID: runnerID, Side: models.BetSideLay, Odds: 2.8, Stake: 10}
+	bet, err := executor.ExecuteSignal(context.Background(), belowBack, uuid.New(), raceID, "1.234", 1)
+	require.NoError(t, err, "a lay below our back price does not cross it")
+	assert.Equal(t, models.BetSideLay, bet.Side)
+
+	sameSide := strategy.Signal{RunnerID: runnerID, Side: models.BetSideBack, Odds: 3.5, Stake: 10}
+	_, err = executor.ExecuteSignal(context.Background(), sameSide, uuid.New(), raceID, "1.234", 1)
+	require.NoError(t, err)
+
+	matchedOpposite := strategy.Signal{RunnerID: otherRunner, Side: models.BetSideLay, Odds: 2.5, Stake: 10}
+	_, err = executor.ExecuteSignal(context.Background(), matchedOpposite, uuid.New(), raceID, "1.234", 1)
+	require.NoError(t, err, "matched orders have left the book")
+}
+
+func TestExecuteSignalStoresMatchedPriceFromPlacementReport(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	betRepo := new(MockBetRepository)
+	betRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+	betRepo.On("Update", mock.Anything, mock.Anything).Return(nil)
+
+	// The back at 3.0 is filled in full at the better price of 3.2
+	placer := &fakePlacer{fill: func(price, stake float64) (float64, float64) { return 3.2, stake }}
+	router, err := NewAccountRouter(RoutingRoundRobin, &BettingAccount{Name: PrimaryAccountName, Service: placer})
+	require.NoError(t, err)
+
+	riskManager := NewRiskManager(&config.TradingConfig{MaxStakePerBet: 100, MaxExposure: 500, MaxDailyLoss: 200}, betRepo, logger)
+	executor := NewExecutor(nil, betRepo, riskManager, false, true, logger, nil)
+	executor.SetAccountRouter(router)
+
+	signal := strategy.Signal{RunnerID: uuid.New(), Side: models.BetSideBack, Odds: 3.0, Stake: 10}
+	bet, err := executor.ExecuteSignal(context.Background(), signal, uuid.New(), uuid.New(), "1.234", 1)
+	require.NoError(t, err)
+
+	require.NotNil(t, bet.MatchedPrice)
+	assert.Equal(t, 3.2, *bet.MatchedPrice)
+	assert.Equal(t, 10.0, *bet.MatchedSize)
+	assert.Equal(t, 3.2, bet.SettlementPrice(), "settlement uses the actual fill")
+	assert.Equal(t, 3.0, bet.Odds, "the requested limit price is kept")
+	assert.Equal(t, models.BetStatusMatched, bet.Status)
+	assert.NotNil(t, bet.MatchedAt)
+	betRepo.AssertCalled(t, "Update", mock.Anything, mock.MatchedBy(func(updated *models.Bet) bool {
+		return updated.MatchedPrice != nil && *updated.MatchedPrice == 3.2
+	}))
+}
+
+func TestApplyPlacementReportPartialMatch(t *testing.T) {
+	now := time.Now()
+	bet := &models.Bet{Side: models.BetSideBack, Odds: 4.0, Stake: 20, Status: models.BetStatusPending}
+
+	applyPlacementReport(bet, &betfair.InstructionReport{AveragePriceMatched: 4.2, SizeMatched: 8}, now)
+	require.NotNil(t, bet.MatchedPrice)
+	assert.Equal(t, 4.2, *bet.MatchedPrice)
+	assert.Equal(t, 8.0, *bet.MatchedSize)
+	assert.Equal(t, models.BetStatusPending, bet.Status, "the unmatched remainder keeps the bet open")
+	assert.Nil(t, bet.MatchedAt)
+
+	assert.Equal(t, 12.0, bet.UnmatchedSize())
+
+	unmatched := &models.Bet{Side: models.BetSideLay, Odds: 5.0, Stake: 10, Status: models.BetStatusPending}
+	applyPlacementReport(unmatched, &betfair.InstructionReport{}, now)
+	assert.Nil(t, unmatched.MatchedPrice, "nothing matched on placement")
+	assert.Equal(t, 5.0, unmatched.SettlementPrice())
+}
+
+func TestExecuteSignalRecordsUnmatchedPlacement(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	betRepo := new(MockBetRepository)
+	betRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+	betRepo.On("Update", mock.Anything, mock.Anything).Return(nil)
+
+	// The order is accepted as EXECUTABLE with nothing matched yet
+	placer := &fakePlacer{fill: func(price, stake float64) (float64, float64) { return 0, 0 }}
+	router, err := NewAccountRouter(RoutingRoundRobin, &BettingAccount{Name: PrimaryAccountName, Service: placer})
+	require.NoError(t, err)
+
+	riskManager := NewRiskManager(&config.TradingConfig{MaxStakePerBet: 100, MaxExposure: 500, MaxDailyLoss: 200}, betRepo, logger)
+	executor := NewExecutor(nil, betRepo, riskManager, false, true, logger, nil)
+	executor.SetAccountRouter(router)
+
+	signal := strategy.Signal{RunnerID: uuid.New(), Side: models.BetSideBack, Odds: 6.0, Stake: 15}
+	bet, err := executor.ExecuteSignal(context.Background(), signal, uuid.New(), uuid.New(), "1.234", 1)
+	require.NoError(t, err)
+
+	assert.Equal(t, models.BetStatusPending, bet.Status)
+	assert.NotEmpty(t, bet.BetID, "the resting order can be cancelled by its Betfair ID")
+	assert.Nil(t, bet.MatchedPrice)
+	require.NotNil(t, bet.MatchedSize)
+	assert.Zero(t, *bet.MatchedSize)
+	assert.Equal(t, 15.0, bet.UnmatchedSize())
+	assert.Nil(t, bet.MatchedAt)
+	betRepo.AssertCalled(t, "Update", mock.Anything, mock.MatchedBy(func(updated *models.Bet) bool {
+		return updated.Status == models.BetStatusPending && updated.MatchedSize != nil && *updated.MatchedSize == 0
+	}))
+}
@@ -0,0 +1,138 @@
+package bot
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/yourusername/clever-better/internal/betfair"
+	applogger "github.com/yourusername/clever-better/internal/logger"
+	"github.com/yourusername/clever-better/internal/models"
+)
+
+// inPlayParameter is the stored strategy parameter that opts a strategy in
+// to trading races once their markets are in-play
+const inPlayParameter = "in_play"
+
+// marketStatusOpen is the status of a market accepting bets
+const marketStatusOpen = "OPEN"
+
+// defaultInPlayTick is the in-play loop interval when tick_seconds is unset
+const defaultInPlayTick = 5 * time.Second
+
+// MarketStateSource reports whether a market is in-play and its bet delay
+type MarketStateSource interface {
+	GetMarketState(ctx context.Context, marketID string) (betfair.MarketState, error)
+}
+
+// tradesInPlay reports whether a stored strategy's parameters set in_play
+func tradesInPlay(stratModel *models.Strategy) bool {
+	value, err := stratModel.GetParameter(inPlayParameter)
+	if err != nil {
+		return false
+	}
+	enabled, _ := value.(bool)
+	return enabled
+}
+
+// inPlayLoop runs in-play passes on their own short ticker. A greyhound race
+// can be over within the trading loop's evaluation interval, so the in-play
+// pass cannot wait for it.
+func (o *Orchestrator) inPlayLoop(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = defaultInPlayTick
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	o.logger.WithField("tick_interval", interval).Info("In-play loop started")
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-o.done:
+			return
+		case <-ticker.C:
+			if o.killSwitchEngaged(ctx) || o.inMaintenance(ctx) || o.circuitBreaker.IsOpen() {
+				continue
+			}
+			if !o.riskManager.IsWithinLimits() {
+				continue
+			}
+			o.inPlayTick(ctx, o.now())
+		}
+	}
+}
+
+// inPlayTick evaluates in-play strategies on races that started within the
+// in-play window and whose markets are in-play and open. Markets are looked
+// up every in-play tick, so a race is picked up within one tick of turning.
+func (o *Orchestrator) inPlayTick(ctx context.Context, now time.Time) {
+	if o.marketStates == nil {
+		return
+	}
+	o.mu.RLock()
+	inPlayStrategies := len(o.inPlayStrategies)
+	o.mu.RUnlock()
+	if inPlayStrategies == 0 {
+		return
+	}
+
+	settings := o.config.Trading.InPlay
+	window := time.Duration(settings.WindowMinutes) * time.Minute
+	maxBetDelay := time.Duration(settings.MaxBetDelaySeconds) * time.Second
+
+	races, err := o.raceRepo.GetByDateRange(ctx, now.Add(-window), now)
+	if err != nil {
+		o.logger.WithError(err).Error("Failed to get in-play races")
+		o.circuitBreaker.RecordFailure(err)
+		return
+	}
+
+	for _, race := range races {
+		raceCtx := applogger.WithRequestID(ctx, applogger.NewRequestID())
+
+		marketID, err := o.raceMarketID(raceCtx, race)
+		if err != nil {
+			o.logger.WithContext(raceCtx).WithError(err).WithField("race_id", race.ID).Warn("Failed to resolve race market, skipping in-play race")
+			continue
+		}
+		if marketID == "" {
+			continue
+		}
+
+		market, err := o.marketStates.GetMarketState(raceCtx, marketID)
+		if err != nil {
+			o.logger.WithContext(raceCtx).WithError(err).WithField("race_id", race.ID).Warn("Failed to fetch market state, skipping in-play race")
+			continue
+		}
+		// Suspended markets take no bets and closed ones are over
+		if !market.InPlay || market.Status != marketStatusOpen {
+			continue
+		}
+		if maxBetDelay > 0 && market.BetDelay > maxBetDelay {
+			o.logger.WithContext(raceCtx).WithFields(logrus.Fields{
+				"race_id":       race.ID,
+				"market_id":     marketID,
+				"bet_delay":     market.BetDelay,
+				"max_bet_delay": maxBetDelay,
+			}).Debug("Skipping in-play race with a long bet delay")
+			continue
+		}
+
+		if _, err := o.processInPlayRace(raceCtx, race, now, market); err != nil {
+			o.logger.WithContext(raceCtx).WithFields(logrus.Fields{
+				"race_id": race.ID,
+				"error":   err.Error(),
+			}).Error("Failed to evaluate in-play strategies for race")
+		}
+	}
+}
+
+// processInPlayRace evaluates a race whose market is in-play with the
+// strategies that trade in-play and executes their signals
+func (o *Orchestrator) processInPlayRace(ctx context.Context, race *models.Race, now time.Time, market betfair.MarketState) ([]*models.Bet, error) {
+	return o.runRace(ctx, race, now, &market)
+}
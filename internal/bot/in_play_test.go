@@ -0,0 +1,189 @@
+package bot
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yourusername/clever-better/internal/betfair"
+	"github.com/yourusername/clever-better/internal/config"
+	"github.com/yourusername/clever-better/internal/models"
+	"github.com/yourusername/clever-better/internal/strategy"
+)
+
+// fakeMarketStates serves scripted market states
+type fakeMarketStates struct {
+	states map[string]betfair.MarketState
+}
+
+func (f *fakeMarketStates) GetMarketState(ctx context.Context, marketID string) (betfair.MarketState, error) {
+	return f.states[marketID], nil
+}
+
+// inPlayRunners records a runner against each race's Betfair market
+func inPlayRunners(t *testing.T, markets map[*models.Race]string) *replayRunnerRepo {
+	t.Helper()
+	runners := make(map[uuid.UUID][]*models.Runner)
+	for race, marketID := range markets {
+		runner := &models.Runner{ID: uuid.New(), RaceID: race.ID, TrapNumber: 1}
+		require.NoError(t, runner.SetBetfairSelection(marketID, 101))
+		runners[race.ID] = []*models.Runner{runner}
+	}
+	return &replayRunnerRepo{runners: runners}
+}
+
+func TestInPlayTickEvaluatesOnlyInPlayStrategies(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+	now := time.Now()
+
+	running := &models.Race{ID: uuid.New(), ScheduledStart: now.Add(-2 * time.Minute)}
+	late := &models.Race{ID: uuid.New(), ScheduledStart: now.Add(-time.Minute)}
+	suspended := &models.Race{ID: uuid.New(), ScheduledStart: now.Add(-3 * time.Minute)}
+	slow := &models.Race{ID: uuid.New(), ScheduledStart: now.Add(-4 * time.Minute)}
+	unmapped := &models.Race{ID: uuid.New(), ScheduledStart: now.Add(-time.Minute)}
+	markets := &fakeMarketStates{states: map[string]betfair.MarketState{
+		"1.running":   {Status: "OPEN", InPlay: true, BetDelay: time.Second},
+		"1.late":      {Status: "OPEN"},
+		"1.suspended": {Status: "SUSPENDED", InPlay: true, BetDelay: time.Second},
+		"1.slow":      {Status: "OPEN", InPlay: true, BetDelay: 10 * time.Second},
+	}}
+
+	inPlay := &countingStrategy{}
+	preRace := &countingStrategy{}
+	inPlayID := uuid.New()
+	orchestrator := &Orchestrator{
+		config: &config.Config{Trading: config.TradingConfig{
+			InPlay: config.InPlayConfig{Enabled: true, WindowMinutes: 10, MaxBetDelaySeconds: 5},
+		}},
+		raceRepo: &replayRaceRepo{races: []*models.Race{running, late, suspended, slow, unmapped}},
+		runnerRepo: inPlayRunners(t, map[*models.Race]string{
+			running:   "1.running",
+			late:      "1.late",
+			suspended: "1.suspended",
+			slow:      "1.slow",
+		}),
+		oddsRepo: &replayOddsRepo{},
+		activeStrategies: map[uuid.UUID]strategy.Strategy{
+			inPlayID:   inPlay,
+			uuid.New(): preRace,
+		},
+		inPlayStrategies: map[uuid.UUID]bool{inPlayID: true},
+		marketStates:     markets,
+		logger:           logger,
+	}
+
+	orchestrator.inPlayTick(context.Background(), now)
+	assert.Equal(t, []uuid.UUID{running.ID}, inPlay.evaluated, "suspended, pre-off, long-delay and unmapped markets are skipped")
+	assert.Empty(t, preRace.evaluated, "strategies without in_play never evaluate in-play races")
+
+	// The late race is picked up on the first tick after its market turns
+	markets.states["1.late"] = betfair.MarketState{Status: "OPEN", InPlay: true, BetDelay: time.Second}
+	inPlay.evaluated = nil
+	orchestrator.inPlayTick(context.Background(), now.Add(time.Second))
+	assert.ElementsMatch(t, []uuid.UUID{running.ID, late.ID}, inPlay.evaluated)
+	assert.Empty(t, preRace.evaluated)
+}
+
+func TestInPlayLoopRunsBetweenTradingTicks(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+	now := time.Now()
+
+	race := &models.Race{ID: uuid.New(), ScheduledStart: now.Add(-30 * time.Second)}
+	inPlay := &countingStrategy{}
+	inPlayID := uuid.New()
+	betRepo := new(MockBetRepository)
+	orchestrator := &Orchestrator{
+		config: &config.Config{Trading: config.TradingConfig{
+			StrategyEvaluationInterval: 60,
+			InPlay:                     config.InPlayConfig{Enabled: true, WindowMinutes: 10},
+		}},
+		raceRepo:         &replayRaceRepo{races: []*models.Race{race}},
+		runnerRepo:       inPlayRunners(t, map[*models.Race]string{race: "1.running"}),
+		oddsRepo:         &replayOddsRepo{},
+		riskManager:      NewRiskManager(&config.TradingConfig{MaxStakePerBet: 100, MaxExposure: 500, MaxDailyLoss: 200}, betRepo, logger),
+		activeStrategies: map[uuid.UUID]strategy.Strategy{inPlayID: inPlay},
+		inPlayStrategies: map[uuid.UUID]bool{inPlayID: true},
+		marketStates: &fakeMarketStates{states: map[string]betfair.MarketState{
+			"1.running": {Status: "OPEN", InPlay: true, BetDelay: time.Second},
+		}},
+		circuitBreaker: NewCircuitBreaker(CircuitBreakerConfig{
+			MaxConsecutiveLosses: 5,
+			MaxDrawdownPercent:   0.5,
+			MaxFailureCount:      5,
+			FailureTimeWindow:    time.Minute,
+			CooldownPeriod:       time.Minute,
+		}, logger),
+		logger: logger,
+	}
+
+	// Well inside one trading tick, the in-play ticker evaluates the race
+	// several times
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	orchestrator.inPlayLoop(ctx, 10*time.Millisecond)
+
+	assert.GreaterOrEqual(t, len(inPlay.evaluated), 2)
+	for _, id := range inPlay.evaluated {
+		assert.Equal(t, race.ID, id)
+	}
+}
+
+func TestInPlaySignalsCarryBetDelay(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	inPlayID := uuid.New()
+	orchestrator := &Orchestrator{
+		config:     &config.Config{},
+		runnerRepo: &replayRunnerRepo{},
+		oddsRepo:   &replayOddsRepo{},
+		activeStrategies: map[uuid.UUID]strategy.Strategy{
+			inPlayID: &fixedSignalStrategy{runnerID: uuid.New()},
+		},
+		inPlayStrategies: map[uuid.UUID]bool{inPlayID: true},
+		logger:           logger,
+	}
+
+	now := time.Now()
+	race := &models.Race{ID: uuid.New(), ScheduledStart: now.Add(-time.Minute)}
+	market := &betfair.MarketState{Status: "OPEN", InPlay: true, BetDelay: 5 * time.Second}
+	signals, err := orchestrator.evaluateStrategies(context.Background(), race, now, market)
+	require.NoError(t, err)
+	require.Len(t, signals, 1)
+	assert.True(t, signals[0].InPlay)
+	assert.Equal(t, 5*time.Second, signals[0].BetDelay)
+	assert.Equal(t, now.Add(5*time.Second), signals[0].ExpectedMatchAt(now), "the order cannot match before the bet delay runs")
+
+	preRace, err := orchestrator.evaluateStrategies(context.Background(), race, now, nil)
+	require.NoError(t, err)
+	require.Len(t, preRace, 1)
+	assert.False(t, preRace[0].InPlay)
+	assert.Equal(t, now, preRace[0].ExpectedMatchAt(now))
+
+	// Being past the off does not close an in-play signal's placement window
+	queue := NewPlacementQueue(100, 30*time.Second)
+	results := queue.Dispatch(context.Background(), append(signals, preRace...), func(sc SignalWithContext) (*models.Bet, error) {
+		return &models.Bet{ID: uuid.New()}, nil
+	})
+	require.Len(t, results, 2)
+	assert.NoError(t, results[0].Err)
+	assert.ErrorIs(t, results[1].Err, ErrPlacementWindowClosed)
+}
+
+func TestTradesInPlay(t *testing.T) {
+	params := func(raw string) json.RawMessage { return json.RawMessage(raw) }
+
+	assert.True(t, tradesInPlay(&models.Strategy{Parameters: params(`{"in_play": true}`)}))
+	assert.False(t, tradesInPlay(&models.Strategy{Parameters: params(`{"in_play": false}`)}))
+	assert.False(t, tradesInPlay(&models.Strategy{Parameters: params(`{"in_play": "yes"}`)}))
+	assert.False(t, tradesInPlay(&models.Strategy{Parameters: params(`{"min_edge": 0.05}`)}))
+	assert.False(t, tradesInPlay(&models.Strategy{}))
+}
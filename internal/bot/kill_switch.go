@@ -0,0 +1,62 @@
+package bot
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"sync"
+)
+
+// Kill switch sources reported while engaged
+const (
+	KillSwitchSourceFile   = "file"
+	KillSwitchSourceManual = "manual"
+)
+
+// KillSwitch halts all trading while engaged. Operators engage it by creating
+// its file, so trading can be stopped without a deploy, or from config at
+// startup. Removing the file (or releasing it) resumes trading.
+type KillSwitch struct {
+	path   string
+	manual bool
+	mu     sync.RWMutex
+}
+
+// NewKillSwitch creates a kill switch watching path, which may be empty.
+// engaged starts the switch manually engaged.
+func NewKillSwitch(path string, engaged bool) *KillSwitch {
+	return &KillSwitch{path: path, manual: engaged}
+}
+
+// Engage halts trading until Release is called
+func (k *KillSwitch) Engage() {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.manual = true
+}
+
+// Release clears a manual engagement. A present kill switch file still halts trading.
+func (k *KillSwitch) Release() {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.manual = false
+}
+
+// Engaged reports whether trading must halt and what engaged the switch.
+// A kill switch file that cannot be checked counts as present.
+func (k *KillSwitch) Engaged() (bool, string) {
+	k.mu.RLock()
+	manual := k.manual
+	k.mu.RUnlock()
+
+	if manual {
+		return true, KillSwitchSourceManual
+	}
+	if k.path == "" {
+		return false, ""
+	}
+	if _, err := os.Stat(k.path); err == nil || !errors.Is(err, fs.ErrNotExist) {
+		return true, KillSwitchSourceFile
+	}
+	return false, ""
+}
@@ -0,0 +1,197 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/clever-better/internal/config"
+	"github.com/yourusername/clever-better/internal/models"
+	"github.com/yourusername/clever-better/internal/repository"
+	"github.com/yourusername/clever-better/internal/strategy"
+)
+
+// upcomingRaceRepo serves a fixed set of upcoming races
+type upcomingRaceRepo struct {
+	repository.RaceRepository
+	races []*models.Race
+	err   error
+	calls int
+}
+
+func (r *upcomingRaceRepo) GetByDateRange(ctx context.Context, start, end time.Time) ([]*models.Race, error) {
+	r.calls++
+	if r.err != nil {
+		return nil, r.err
+	}
+	return r.races, nil
+}
+
+func TestKillSwitchEngagedByFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "kill")
+	killSwitch := NewKillSwitch(path, false)
+
+	engaged, _ := killSwitch.Engaged()
+	assert.False(t, engaged)
+
+	require.NoError(t, os.WriteFile(path, nil, 0o600))
+	engaged, source := killSwitch.Engaged()
+	assert.True(t, engaged)
+	assert.Equal(t, KillSwitchSourceFile, source)
+
+	require.NoError(t, os.Remove(path))
+	killSwitch.Engage()
+	engaged, source = killSwitch.Engaged()
+	assert.True(t, engaged)
+	assert.Equal(t, KillSwitchSourceManual, source)
+
+	killSwitch.Release()
+	engaged, _ = killSwitch.Engaged()
+	assert.False(t, engaged)
+}
+
+func TestTradingTickHaltsWhileKillSwitchEngaged(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+	ctx := context.Background()
+
+	start := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	clock := NewMockClock(start)
+	path := filepath.Join(t.TempDir(), "kill")
+
+	race := &models.Race{ID: uuid.New(), ScheduledStart: start.Add(5 * time.Minute), Track: "Romford", Status: "scheduled"}
+	runner := &models.Runner{ID: uuid.New(), RaceID: race.ID, TrapNumber: 1, Name: "Fav"}
+	races := &upcomingRaceRepo{races: []*models.Race{race}}
+	counting := &countingStrategy{}
+
+	betRepo := new(MockBetRepository)
+	betRepo.On("GetPendingBets", mock.Anything).Return(nil, nil)
+	cfg := &config.Config{
+		Trading: config.TradingConfig{MaxStakePerBet: 100, MaxExposure: 500, MaxDailyLoss: 200, PreRaceWindowMinutes: 10},
+		Bot:     config.BotConfig{KillSwitch: config.KillSwitchConfig{File: path, CancelUnmatched: true}},
+	}
+	riskManager := NewRiskManager(&cfg.Trading, betRepo, logger)
+
+	orchestrator := &Orchestrator{
+		config:           cfg,
+		raceRepo:         races,
+		runnerRepo:       &replayRunnerRepo{runners: map[uuid.UUID][]*models.Runner{race.ID: {runner}}},
+		oddsRepo:         &replayOddsRepo{},
+		betRepo:          betRepo,
+		riskManager:      riskManager,
+		executor:         NewExecutor(nil, betRepo, riskManager, true, false, logger, nil),
+		monitor:          NewMonitor(betRepo, nil, nil, nil, 1000, time.Minute, logger),
+		activeStrategies: map[uuid.UUID]strategy.Strategy{uuid.New(): counting},
+		strategiesAt:     start,
+		circuitBreaker: NewCircuitBreaker(CircuitBreakerConfig{
+			MaxConsecutiveLosses: 5,
+			MaxDrawdownPercent:   0.5,
+			MaxFailureCount:      5,
+			FailureTimeWindow:    time.Minute,
+			CooldownPeriod:       time.Minute,
+		}, logger),
+		killSwitch: NewKillSwitch(path, false),
+		logger:     logger,
+	}
+	orchestrator.SetClock(clock)
+
+	orchestrator.tradingTick(ctx)
+	require.Len(t, counting.evaluated, 1)
+	assert.False(t, orchestrator.GetStatus().KillSwitchEngaged)
+
+	// The next tick after the file appears evaluates nothing
+	require.NoError(t, os.WriteFile(path, nil, 0o600))
+	clock.Advance(time.Minute)
+	orchestrator.tradingTick(ctx)
+	orchestrator.tradingTick(ctx)
+	assert.Len(t, counting.evaluated, 1, "no strategies are evaluated while halted")
+	assert.Equal(t, 1, races.calls)
+
+	status := orchestrator.GetStatus()
+	assert.True(t, status.KillSwitchEngaged)
+	assert.Equal(t, KillSwitchSourceFile, status.KillSwitchSource)
+	assert.Equal(t, clock.Now(), status.KillSwitchEngagedAt)
+
+	// Clearing the switch resumes trading on the next tick
+	require.NoError(t, os.Remove(path))
+	orchestrator.tradingTick(ctx)
+	assert.Len(t, counting.evaluated, 2)
+	status = orchestrator.GetStatus()
+	assert.False(t, status.KillSwitchEngaged)
+	assert.True(t, status.KillSwitchEngagedAt.IsZero())
+}
+
+func TestTradingTickPausesForMaintenance(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+	ctx := context.Background()
+
+	start := time.Date(2024, 6, 4, 2, 58, 0, 0, time.UTC)
+	clock := NewMockClock(start)
+
+	race := &models.Race{ID: uuid.New(), ScheduledStart: start.Add(5 * time.Minute), Track: "Romford", Status: "scheduled"}
+	runner := &models.Runner{ID: uuid.New(), RaceID: race.ID, TrapNumber: 1, Name: "Fav"}
+	races := &upcomingRaceRepo{races: []*models.Race{race}}
+	counting := &countingStrategy{}
+
+	betRepo := new(MockBetRepository)
+	betRepo.On("GetPendingBets", mock.Anything).Return(nil, nil)
+	cfg := &config.Config{
+		Trading: config.TradingConfig{MaxStakePerBet: 100, MaxExposure: 500, MaxDailyLoss: 200, PreRaceWindowMinutes: 10},
+		Betfair: config.BetfairConfig{Maintenance: config.MaintenanceConfig{Windows: []config.MaintenanceWindowConfig{
+			{Start: "2024-06-04T03:00:00Z", End: "2024-06-04T03:30:00Z"},
+		}}},
+	}
+	riskManager := NewRiskManager(&cfg.Trading, betRepo, logger)
+
+	orchestrator := &Orchestrator{
+		config:           cfg,
+		raceRepo:         races,
+		runnerRepo:       &replayRunnerRepo{runners: map[uuid.UUID][]*models.Runner{race.ID: {runner}}},
+		oddsRepo:         &replayOddsRepo{},
+		betRepo:          betRepo,
+		riskManager:      riskManager,
+		executor:         NewExecutor(nil, betRepo, riskManager, true, false, logger, nil),
+		monitor:          NewMonitor(betRepo, nil, nil, nil, 1000, time.Minute, logger),
+		activeStrategies: map[uuid.UUID]strategy.Strategy{uuid.New(): counting},
+		strategiesAt:     start,
+		circuitBreaker: NewCircuitBreaker(CircuitBreakerConfig{
+			MaxConsecutiveLosses: 5,
+			MaxDrawdownPercent:   0.5,
+			MaxFailureCount:      1,
+			FailureTimeWindow:    time.Hour,
+			CooldownPeriod:       time.Hour,
+		}, logger),
+		logger: logger,
+	}
+	orchestrator.SetClock(clock)
+
+	orchestrator.tradingTick(ctx)
+	require.Len(t, counting.evaluated, 1)
+
+	// Inside the window nothing is called, so nothing can fail
+	clock.Set(time.Date(2024, 6, 4, 3, 0, 0, 0, time.UTC))
+	races.err = errors.New("service unavailable")
+	orchestrator.tradingTick(ctx)
+	clock.Advance(15 * time.Minute)
+	orchestrator.tradingTick(ctx)
+	assert.Len(t, counting.evaluated, 1, "no strategies are evaluated during maintenance")
+	assert.Equal(t, 1, races.calls)
+	assert.Equal(t, CircuitClosed, orchestrator.circuitBreaker.GetState())
+	assert.Equal(t, time.Date(2024, 6, 4, 3, 30, 0, 0, time.UTC), orchestrator.GetStatus().MaintenanceUntil)
+
+	// Trading resumes once the window ends
+	races.err = nil
+	clock.Set(time.Date(2024, 6, 4, 3, 30, 0, 0, time.UTC))
+	orchestrator.tradingTick(ctx)
+	assert.Len(t, counting.evaluated, 2)
+	assert.True(t, orchestrator.GetStatus().MaintenanceUntil.IsZero())
+}
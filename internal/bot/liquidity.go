@@ -0,0 +1,94 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	cache "github.com/patrickmn/go-cache"
+
+	"github.com/yourusername/clever-better/internal/models"
+)
+
+// liquidityCacheTTL bounds how long a market's matched volume is reused
+const liquidityCacheTTL = 30 * time.Second
+
+// LiquiditySource reports the total amount matched on a market
+type LiquiditySource interface {
+	GetMarketLiquidity(ctx context.Context, marketID string) (float64, error)
+}
+
+// LiquidityFilter skips markets whose matched volume is below a minimum
+type LiquidityFilter struct {
+	source       LiquiditySource
+	minLiquidity float64
+	cache        *cache.Cache
+}
+
+// NewLiquidityFilter creates a liquidity filter caching lookups for ttl
+func NewLiquidityFilter(source LiquiditySource, minLiquidity float64, ttl time.Duration) *LiquidityFilter {
+	return &LiquidityFilter{
+		source:       source,
+		minLiquidity: minLiquidity,
+		cache:        cache.New(ttl, ttl*2),
+	}
+}
+
+// Allow reports whether a market is liquid enough to evaluate, along with
+// the liquidity seen. Races without a market ID are always allowed.
+func (f *LiquidityFilter) Allow(ctx context.Context, marketID string) (bool, float64, error) {
+	if marketID == "" {
+		return true, 0, nil
+	}
+
+	if cached, found := f.cache.Get(marketID); found {
+		liquidity := cached.(float64)
+		return liquidity >= f.minLiquidity, liquidity, nil
+	}
+
+	liquidity, err := f.source.GetMarketLiquidity(ctx, marketID)
+	if err != nil {
+		return false, 0, err
+	}
+
+	f.cache.SetDefault(marketID, liquidity)
+	return liquidity >= f.minLiquidity, liquidity, nil
+}
+
+// ErrInsufficientDepth is returned when the top of the ladder cannot take a
+// bet's stake
+var ErrInsufficientDepth = errors.New("insufficient ladder depth")
+
+// DepthSource reports the size offered to a side of a selection across the
+// best price levels of its ladder
+type DepthSource interface {
+	GetAvailableSize(ctx context.Context, marketID string, selectionID uint64, side string, maxLevels int) (float64, error)
+}
+
+// DepthGuard checks just before placement that the best maxLevels price
+// levels offer at least a bet's stake. Size resting deeper in the ladder is
+// ignored, as it is rarely there by the time the order reaches it.
+type DepthGuard struct {
+	source    DepthSource
+	maxLevels int
+}
+
+// NewDepthGuard creates a guard summing the best maxLevels levels. Zero or
+// less sums the whole ladder.
+func NewDepthGuard(source DepthSource, maxLevels int) *DepthGuard {
+	return &DepthGuard{source: source, maxLevels: maxLevels}
+}
+
+// Check returns an error wrapping ErrInsufficientDepth when the stake is
+// more than the size offered
+func (g *DepthGuard) Check(ctx context.Context, marketID string, selectionID uint64, side models.BetSide, stake float64) error {
+	available, err := g.source.GetAvailableSize(ctx, marketID, selectionID, string(side), g.maxLevels)
+	if err != nil {
+		return fmt.Errorf("failed to read ladder depth: %w", err)
+	}
+	if available < stake {
+		return fmt.Errorf("%w: %.2f offered across %d levels, stake %.2f", ErrInsufficientDepth, available, g.maxLevels, stake)
+	}
+	return nil
+}
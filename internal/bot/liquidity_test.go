@@ -0,0 +1,188 @@
+package bot
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/clever-better/internal/config"
+	"github.com/yourusername/clever-better/internal/models"
+	"github.com/yourusername/clever-better/internal/strategy"
+)
+
+// fakeLiquiditySource serves fixed matched volumes and counts lookups
+type fakeLiquiditySource struct {
+	liquidity map[string]float64
+	err       error
+	calls     int
+}
+
+func (s *fakeLiquiditySource) GetMarketLiquidity(ctx context.Context, marketID string) (float64, error) {
+	s.calls++
+	if s.err != nil {
+		return 0, s.err
+	}
+	return s.liquidity[marketID], nil
+}
+
+// countingStrategy records the races it is asked to evaluate
+type countingStrategy struct {
+	evaluated []uuid.UUID
+}
+
+func (s *countingStrategy) Name() string { return "counting" }
+
+func (s *countingStrategy) Evaluate(ctx context.Context, strategyCtx strategy.Context) ([]strategy.Signal, error) {
+	s.evaluated = append(s.evaluated, strategyCtx.Race.ID)
+	return nil, nil
+}
+
+func (s *countingStrategy) ShouldBet(signal strategy.Signal) bool { return false }
+
+func (s *countingStrategy) CalculateStake(signal strategy.Signal, bankroll float64) float64 {
+	return 0
+}
+
+func (s *countingStrategy) GetParameters() map[string]interface{} { return nil }
+
+func TestLiquidityFilterAllow(t *testing.T) {
+	source := &fakeLiquiditySource{liquidity: map[string]float64{"1.thin": 250, "1.deep": 5000}}
+	filter := NewLiquidityFilter(source, 1000, time.Minute)
+
+	allowed, liquidity, err := filter.Allow(context.Background(), "1.thin")
+	require.NoError(t, err)
+	assert.False(t, allowed)
+	assert.Equal(t, 250.0, liquidity)
+
+	allowed, liquidity, err = filter.Allow(context.Background(), "1.deep")
+	require.NoError(t, err)
+	assert.True(t, allowed)
+	assert.Equal(t, 5000.0, liquidity)
+
+	// Repeat lookups within the TTL are served from cache
+	_, _, err = filter.Allow(context.Background(), "1.deep")
+	require.NoError(t, err)
+	assert.Equal(t, 2, source.calls)
+
+	// Races without a market are not checked
+	allowed, _, err = filter.Allow(context.Background(), "")
+	require.NoError(t, err)
+	assert.True(t, allowed)
+	assert.Equal(t, 2, source.calls)
+}
+
+func TestLiquidityFilterSourceError(t *testing.T) {
+	source := &fakeLiquiditySource{err: assert.AnError}
+	filter := NewLiquidityFilter(source, 1000, time.Minute)
+
+	allowed, _, err := filter.Allow(context.Background(), "1.unknown")
+	assert.Error(t, err)
+	assert.False(t, allowed)
+}
+
+// liquidityOrchestrator builds an orchestrator whose races each have a single
+// runner recorded against the given Betfair market
+func liquidityOrchestrator(t *testing.T, source LiquiditySource, counter *countingStrategy, markets map[*models.Race]string) *Orchestrator {
+	t.Helper()
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	runners := make(map[uuid.UUID][]*models.Runner)
+	for race, marketID := range markets {
+		runner := &models.Runner{ID: uuid.New(), RaceID: race.ID, TrapNumber: 1}
+		require.NoError(t, runner.SetBetfairSelection(marketID, 101))
+		runners[race.ID] = []*models.Runner{runner}
+	}
+
+	betRepo := new(MockBetRepository)
+	riskManager := NewRiskManager(&config.TradingConfig{MaxStakePerBet: 100, MaxExposure: 500, MaxDailyLoss: 200}, betRepo, logger)
+
+	return &Orchestrator{
+		config:           &config.Config{},
+		runnerRepo:       &replayRunnerRepo{runners: runners},
+		oddsRepo:         &replayOddsRepo{},
+		betRepo:          betRepo,
+		riskManager:      riskManager,
+		executor:         NewExecutor(nil, betRepo, riskManager, true, false, logger, nil),
+		activeStrategies: map[uuid.UUID]strategy.Strategy{uuid.New(): counter},
+		liquidityFilter:  NewLiquidityFilter(source, 1000, time.Minute),
+		logger:           logger,
+	}
+}
+
+func TestProcessRaceSkipsIlliquidMarkets(t *testing.T) {
+	thin := &models.Race{ID: uuid.New(), Status: "scheduled"}
+	deep := &models.Race{ID: uuid.New(), Status: "scheduled"}
+
+	source := &fakeLiquiditySource{liquidity: map[string]float64{"1.thin": 250, "1.deep": 5000}}
+	counter := &countingStrategy{}
+	orchestrator := liquidityOrchestrator(t, source, counter, map[*models.Race]string{thin: "1.thin", deep: "1.deep"})
+
+	now := time.Now()
+	_, err := orchestrator.processRace(context.Background(), thin, now)
+	require.NoError(t, err)
+	_, err = orchestrator.processRace(context.Background(), deep, now)
+	require.NoError(t, err)
+
+	assert.Equal(t, []uuid.UUID{deep.ID}, counter.evaluated)
+}
+
+func TestProcessRaceSkipsLiquidityFilterInReplay(t *testing.T) {
+	thin := &models.Race{ID: uuid.New(), Status: "scheduled"}
+
+	source := &fakeLiquiditySource{liquidity: map[string]float64{"1.thin": 250}}
+	counter := &countingStrategy{}
+	orchestrator := liquidityOrchestrator(t, source, counter, map[*models.Race]string{thin: "1.thin"})
+	orchestrator.replaying = true
+
+	_, err := orchestrator.processRace(context.Background(), thin, time.Now())
+	require.NoError(t, err)
+
+	assert.Equal(t, []uuid.UUID{thin.ID}, counter.evaluated)
+	assert.Zero(t, source.calls, "replays must not query the live market")
+}
+
+// fakeDepthSource sums a fixed ladder to the requested depth
+type fakeDepthSource struct {
+	ladder models.PriceLadder
+	levels []int
+}
+
+func (s *fakeDepthSource) GetAvailableSize(ctx context.Context, marketID string, selectionID uint64, side string, maxLevels int) (float64, error) {
+	s.levels = append(s.levels, maxLevels)
+	return s.ladder.Depth(maxLevels), nil
+}
+
+func TestPriceLadderDepthIgnoresDeeperLevels(t *testing.T) {
+	ladder := models.PriceLadder{{Price: 3.0, Size: 5}, {Price: 2.98, Size: 7}, {Price: 2.96, Size: 100}}
+
+	assert.Equal(t, 5.0, ladder.Depth(1))
+	assert.Equal(t, 12.0, ladder.Depth(2), "the third level is ignored")
+	assert.Equal(t, 112.0, ladder.Depth(5))
+	assert.Equal(t, 112.0, ladder.Depth(0), "zero sums the whole ladder")
+}
+
+func TestExecuteSignalRejectsStakeBeyondLadderDepth(t *testing.T) {
+	source := &fakeDepthSource{ladder: models.PriceLadder{{Price: 3.0, Size: 5}, {Price: 2.98, Size: 7}, {Price: 2.96, Size: 100}}}
+	executor, betRepo := newStalenessExecutor(t, nil)
+	executor.SetDepthGuard(NewDepthGuard(source, 2))
+
+	// Only 12 is offered across the best two levels, despite 100 at the third
+	signal := strategy.Signal{RunnerID: uuid.New(), Side: models.BetSideBack, Odds: 3.0, Stake: 20}
+	_, err := executor.ExecuteSignal(context.Background(), signal, uuid.New(), uuid.New(), "1.234", 7)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrInsufficientDepth)
+	assert.Equal(t, []int{2}, source.levels)
+	assert.Equal(t, int64(1), executor.GetMetrics().OrdersRejected)
+	betRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+
+	signal.Stake = 12
+	bet, err := executor.ExecuteSignal(context.Background(), signal, uuid.New(), uuid.New(), "1.234", 7)
+	require.NoError(t, err)
+	assert.Equal(t, 12.0, bet.Stake)
+}
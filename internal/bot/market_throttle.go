@@ -0,0 +1,75 @@
+package bot
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrMarketThrottled is returned when a signal arrives too soon after our
+// last bet on the same market
+var ErrMarketThrottled = errors.New("market bet throttled")
+
+// MarketThrottle enforces a minimum time between our bets on the same market
+// so a strategy cannot keep re-entering as the price wiggles around its
+// threshold. Signals arriving inside the interval are dropped, not deferred;
+// the next evaluation after the interval sees fresh prices anyway.
+type MarketThrottle struct {
+	interval time.Duration
+	clock    Clock
+	lastBet  map[string]time.Time
+	mu       sync.Mutex
+}
+
+// NewMarketThrottle creates a throttle allowing one bet per market per
+// interval
+func NewMarketThrottle(interval time.Duration) *MarketThrottle {
+	return &MarketThrottle{
+		interval: interval,
+		clock:    RealClock{},
+		lastBet:  make(map[string]time.Time),
+	}
+}
+
+// SetClock replaces the clock intervals are measured on
+func (t *MarketThrottle) SetClock(clock Clock) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.clock = clockOrReal(clock)
+}
+
+// Reserve claims the market for a bet, returning ErrMarketThrottled when the
+// last bet on it was less than the interval ago. The returned release undoes
+// the claim and should be called if the bet is not placed after all.
+func (t *MarketThrottle) Reserve(market string) (release func(), err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.clock.Now()
+	// Markets bet on more than an interval ago no longer need tracking
+	for m, last := range t.lastBet {
+		if m != market && now.Sub(last) >= t.interval {
+			delete(t.lastBet, m)
+		}
+	}
+	previous, seen := t.lastBet[market]
+	if seen && now.Sub(previous) < t.interval {
+		wait := t.interval - now.Sub(previous)
+		return nil, fmt.Errorf("%w: market %s, next bet allowed in %s", ErrMarketThrottled, market, wait.Round(time.Millisecond))
+	}
+
+	t.lastBet[market] = now
+	return func() {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		if !t.lastBet[market].Equal(now) {
+			return
+		}
+		if seen {
+			t.lastBet[market] = previous
+		} else {
+			delete(t.lastBet, market)
+		}
+	}, nil
+}
@@ -0,0 +1,121 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/clever-better/internal/config"
+	"github.com/yourusername/clever-better/internal/models"
+	"github.com/yourusername/clever-better/internal/strategy"
+)
+
+func TestMarketThrottleReserve(t *testing.T) {
+	clock := NewMockClock(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+	throttle := NewMarketThrottle(30 * time.Second)
+	throttle.SetClock(clock)
+
+	_, err := throttle.Reserve("1.234")
+	require.NoError(t, err)
+
+	clock.Advance(10 * time.Second)
+	_, err = throttle.Reserve("1.234")
+	assert.ErrorIs(t, err, ErrMarketThrottled)
+	_, err = throttle.Reserve("1.567")
+	assert.NoError(t, err, "other markets are unaffected")
+
+	clock.Advance(20 * time.Second)
+	_, err = throttle.Reserve("1.234")
+	assert.NoError(t, err, "the interval has passed")
+}
+
+func TestMarketThrottleReleaseRestoresPreviousBet(t *testing.T) {
+	clock := NewMockClock(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+	throttle := NewMarketThrottle(30 * time.Second)
+	throttle.SetClock(clock)
+
+	_, err := throttle.Reserve("1.234")
+	require.NoError(t, err)
+
+	clock.Advance(40 * time.Second)
+	release, err := throttle.Reserve("1.234")
+	require.NoError(t, err)
+	release()
+
+	// The released claim no longer counts, and the earlier bet is long past
+	_, err = throttle.Reserve("1.234")
+	assert.NoError(t, err)
+}
+
+func TestExecuteSignalThrottlesRepeatBetsOnMarket(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	betRepo := new(MockBetRepository)
+	betRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+
+	riskManager := NewRiskManager(&config.TradingConfig{
+		MaxStakePerBet: 100,
+		MaxExposure:    500,
+		MaxDailyLoss:   200,
+	}, betRepo, logger)
+	executor := NewExecutor(nil, betRepo, riskManager, true, false, logger, nil)
+
+	clock := NewMockClock(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+	throttle := NewMarketThrottle(30 * time.Second)
+	throttle.SetClock(clock)
+	executor.SetMarketThrottle(throttle)
+
+	raceID := uuid.New()
+	signal := func() strategy.Signal {
+		return strategy.Signal{RunnerID: uuid.New(), Side: models.BetSideBack, Odds: 3.0, Stake: 5}
+	}
+
+	_, err := executor.ExecuteSignal(context.Background(), signal(), uuid.New(), raceID, "1.234", 1)
+	require.NoError(t, err)
+
+	clock.Advance(5 * time.Second)
+	_, err = executor.ExecuteSignal(context.Background(), signal(), uuid.New(), raceID, "1.234", 2)
+	require.ErrorIs(t, err, ErrMarketThrottled)
+
+	clock.Advance(30 * time.Second)
+	_, err = executor.ExecuteSignal(context.Background(), signal(), uuid.New(), raceID, "1.234", 3)
+	require.NoError(t, err)
+
+	betRepo.AssertNumberOfCalls(t, "Create", 2)
+	execMetrics := executor.GetMetrics()
+	assert.Equal(t, int64(1), execMetrics.ThrottledSignals)
+	assert.Equal(t, int64(1), execMetrics.OrdersRejected)
+}
+
+func TestExecuteSignalReleasesThrottleWhenBetNotRecorded(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	betRepo := new(MockBetRepository)
+	betRepo.On("Create", mock.Anything, mock.Anything).Return(errors.New("db down")).Once()
+	betRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+
+	riskManager := NewRiskManager(&config.TradingConfig{
+		MaxStakePerBet: 100,
+		MaxExposure:    500,
+		MaxDailyLoss:   200,
+	}, betRepo, logger)
+	executor := NewExecutor(nil, betRepo, riskManager, true, false, logger, nil)
+	throttle := NewMarketThrottle(time.Minute)
+	throttle.SetClock(NewMockClock(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)))
+	executor.SetMarketThrottle(throttle)
+
+	signal := strategy.Signal{RunnerID: uuid.New(), Side: models.BetSideBack, Odds: 3.0, Stake: 5}
+	_, err := executor.ExecuteSignal(context.Background(), signal, uuid.New(), uuid.New(), "1.234", 1)
+	require.Error(t, err)
+
+	_, err = executor.ExecuteSignal(context.Background(), signal, uuid.New(), uuid.New(), "1.234", 1)
+	assert.NoError(t, err, "a bet that was never recorded does not throttle the market")
+}
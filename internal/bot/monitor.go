@@ -3,6 +3,7 @@ package bot
 import (
 	"context"
 	"fmt"
+	"math"
 	"sort"
 	"sync"
 	"time"
@@ -13,6 +14,10 @@ import (
 	"github.com/yourusername/clever-better/internal/repository"
 )
 
+// defaultEMAHalfLifeBets is the number of settlements after which an
+// observation's weight in the EMA metrics halves
+const defaultEMAHalfLifeBets = 20
+
 // MonitorMetrics tracks monitoring statistics
 type MonitorMetrics struct {
 	UpdatesPerformed int64     `json:"updates_performed"`
@@ -30,6 +35,12 @@ type LivePerformance struct {
 	TotalPL      float64   `json:"total_pl"`
 	WinRate      float64   `json:"win_rate"`
 	ROI          float64   `json:"roi"`
+	EMAROI       float64   `json:"ema_roi"`
+	EMAWinRate   float64   `json:"ema_win_rate"`
+	SettledBets  int       `json:"settled_bets"`
+	// InsufficientData is set while too few bets have settled for the rates
+	// to mean much
+	InsufficientData bool  `json:"insufficient_data"`
 	AverageStake float64   `json:"average_stake"`
 	LargestWin   float64   `json:"largest_win"`
 	LargestLoss  float64   `json:"largest_loss"`
@@ -39,12 +50,13 @@ type LivePerformance struct {
 
 // DashboardData aggregates monitoring information
 type DashboardData struct {
-	TotalStrategies   int                `json:"total_strategies"`
-	ActiveStrategies  int                `json:"active_strategies"`
-	TotalBetsToday    int                `json:"total_bets_today"`
-	TotalPLToday      float64            `json:"total_pl_today"`
-	TopPerformers     []*LivePerformance `json:"top_performers"`
-	RecentBets        []*models.Bet      `json:"recent_bets"`
+	TotalStrategies      int                `json:"total_strategies"`
+	ActiveStrategies     int                `json:"active_strategies"`
+	TotalBetsToday       int                `json:"total_bets_today"`
+	TotalPLToday         float64            `json:"total_pl_today"`
+	TopPerformers        []*LivePerformance `json:"top_performers"`
+	RecentBets           []*models.Bet      `json:"recent_bets"`
+	CircuitBreakerEvents []CircuitEvent     `json:"circuit_breaker_events"`
 }
 
 // Monitor handles live performance tracking
@@ -55,8 +67,12 @@ type Monitor struct {
 	circuitBreaker   *CircuitBreaker
 	baseBankroll     float64
 	updateInterval   time.Duration
+	emaHalfLife      int
+	minSampleBets    int
+	clock            Clock
 	logger           *logrus.Logger
 	metrics          *MonitorMetrics
+	portfolioRisk    *PortfolioRisk
 	mu               sync.RWMutex
 	done             chan struct{}
 }
@@ -78,6 +94,8 @@ func NewMonitor(
 		circuitBreaker:   circuitBreaker,
 		baseBankroll:     baseBankroll,
 		updateInterval:   updateInterval,
+		emaHalfLife:      defaultEMAHalfLifeBets,
+		clock:            RealClock{},
 		logger:           logger,
 		metrics: &MonitorMetrics{
 			LastUpdateTime: time.Now(),
@@ -86,6 +104,38 @@ func NewMonitor(
 	}
 }
 
+// SetClock replaces the clock that sets the day and month windows
+func (m *Monitor) SetClock(clock Clock) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.clock = clockOrReal(clock)
+}
+
+// now returns the monitor's current time
+func (m *Monitor) now() time.Time {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.clock.Now()
+}
+
+// SetEMAHalfLife sets the half-life, in settled bets, of the EMA ROI and win
+// rate. Non-positive values keep the default.
+func (m *Monitor) SetEMAHalfLife(bets int) {
+	if bets <= 0 {
+		return
+	}
+	m.emaHalfLife = bets
+}
+
+// SetMinSampleBets sets how many settled bets live metrics need before they
+// are trusted. Zero never flags them.
+func (m *Monitor) SetMinSampleBets(bets int) {
+	if bets < 0 {
+		bets = 0
+	}
+	m.minSampleBets = bets
+}
+
 // Start begins the monitoring loop
 func (m *Monitor) Start(ctx context.Context) error {
 	m.logger.WithField("update_interval", m.updateInterval).Info("Starting performance monitor")
@@ -138,13 +188,13 @@ func (m *Monitor) UpdatePerformance(ctx context.Context) error {
 	// Filter for active strategies
 	activeStrategies := make([]*models.Strategy, 0)
 	for _, strategy := range strategies {
-		if strategy.IsActive {
+		if strategy.IsActive && !strategy.IsArchived() {
 			activeStrategies = append(activeStrategies, strategy)
 		}
 	}
 
 	// Calculate metrics for each active strategy
-	now := time.Now()
+	now := m.now()
 	startOfMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
 
 	for _, strategy := range activeStrategies {
@@ -201,20 +251,7 @@ func (m *Monitor) UpdatePerformance(ctx context.Context) error {
 
 		// Feed settled bet results into circuit breaker (loss/drawdown tracking)
 		if m.circuitBreaker != nil && len(settledBets) > 0 {
-			sort.Slice(settledBets, func(i, j int) bool {
-				left := settledBets[i].SettledAt
-				right := settledBets[j].SettledAt
-				if left == nil && right == nil {
-					return settledBets[i].PlacedAt.Before(settledBets[j].PlacedAt)
-				}
-				if left == nil {
-					return false
-				}
-				if right == nil {
-					return true
-				}
-				return left.Before(*right)
-			})
+			sortBySettlement(settledBets)
 
 			cumulativePL := 0.0
 			for _, bet := range settledBets {
@@ -279,9 +316,13 @@ func (m *Monitor) UpdatePerformance(ctx context.Context) error {
 		}).Info("Strategy performance updated")
 	}
 
+	if _, err := m.UpdatePortfolioRisk(ctx); err != nil {
+		m.logger.WithError(err).Error("Failed to update portfolio risk")
+	}
+
 	m.mu.Lock()
 	m.metrics.UpdatesPerformed++
-	m.metrics.LastUpdateTime = time.Now()
+	m.metrics.LastUpdateTime = m.clock.Now()
 	m.mu.Unlock()
 
 	m.logger.WithFields(logrus.Fields{
@@ -292,9 +333,41 @@ func (m *Monitor) UpdatePerformance(ctx context.Context) error {
 	return nil
 }
 
+// UpdatePortfolioRisk recalculates the combined risk of the open bets across
+// strategies and keeps it for status reporting
+func (m *Monitor) UpdatePortfolioRisk(ctx context.Context) (PortfolioRisk, error) {
+	bets, err := m.betRepo.GetPendingBets(ctx)
+	if err != nil {
+		return PortfolioRisk{}, fmt.Errorf("failed to get pending bets: %w", err)
+	}
+
+	risk := CalculatePortfolioRisk(bets, m.now())
+
+	m.mu.Lock()
+	m.portfolioRisk = &risk
+	m.mu.Unlock()
+
+	m.logger.WithFields(logrus.Fields{
+		"combined_exposure": risk.CombinedExposure,
+		"portfolio_var":     risk.PortfolioVaR,
+		"undiversified_var": risk.UndiversifiedVaR,
+		"overlapping_races": risk.OverlappingRaces,
+	}).Debug("Portfolio risk updated")
+
+	return risk, nil
+}
+
+// PortfolioRisk returns the last portfolio risk calculation, or nil before
+// the first update
+func (m *Monitor) PortfolioRisk() *PortfolioRisk {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.portfolioRisk
+}
+
 // GetLiveMetrics returns real-time performance for a strategy
 func (m *Monitor) GetLiveMetrics(ctx context.Context, strategyID uuid.UUID) (*LivePerformance, error) {
-	now := time.Now()
+	now := m.now()
 	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
 
 	// Get all bets for this strategy today
@@ -309,13 +382,17 @@ func (m *Monitor) GetLiveMetrics(ctx context.Context, strategyID uuid.UUID) (*Li
 	}
 
 	var (
-		totalStake   = 0.0
+		totalStake    = 0.0
 		currentStreak = 0
 		lastBetWon    = false
+		settledBets   = make([]*models.Bet, 0, len(bets))
 	)
 
 	for _, bet := range bets {
 		totalStake += bet.Stake
+		if bet.ProfitLoss != nil {
+			settledBets = append(settledBets, bet)
+		}
 
 		if bet.Status == models.BetStatusPending {
 			perf.PendingBets++
@@ -363,12 +440,80 @@ func (m *Monitor) GetLiveMetrics(ctx context.Context, strategyID uuid.UUID) (*Li
 		perf.ROI = perf.TotalPL / totalStake
 	}
 
+	// Fold settlements in order so the EMA reflects the most recent results
+	sortBySettlement(settledBets)
+	ema := newEMATracker(m.emaHalfLife)
+	for _, bet := range settledBets {
+		ema.Update(bet)
+	}
+	perf.EMAROI = ema.ROI
+	perf.EMAWinRate = ema.WinRate
+	perf.SettledBets = len(settledBets)
+	perf.InsufficientData = perf.SettledBets < m.minSampleBets
+
 	return perf, nil
 }
 
+// emaTracker keeps exponential moving averages of per-bet ROI and win rate
+type emaTracker struct {
+	alpha   float64
+	count   int
+	ROI     float64
+	WinRate float64
+}
+
+// newEMATracker creates a tracker whose weights halve every halfLife updates
+func newEMATracker(halfLife int) *emaTracker {
+	if halfLife <= 0 {
+		halfLife = defaultEMAHalfLifeBets
+	}
+	return &emaTracker{alpha: 1 - math.Pow(0.5, 1/float64(halfLife))}
+}
+
+// Update folds a settled bet into the averages
+func (e *emaTracker) Update(bet *models.Bet) {
+	if bet.ProfitLoss == nil || bet.Stake <= 0 {
+		return
+	}
+
+	roi := *bet.ProfitLoss / bet.Stake
+	win := 0.0
+	if *bet.ProfitLoss > 0 {
+		win = 1.0
+	}
+
+	if e.count == 0 {
+		e.ROI = roi
+		e.WinRate = win
+	} else {
+		e.ROI += e.alpha * (roi - e.ROI)
+		e.WinRate += e.alpha * (win - e.WinRate)
+	}
+	e.count++
+}
+
+// sortBySettlement orders bets by settlement time, falling back to placement
+// time for bets without one
+func sortBySettlement(bets []*models.Bet) {
+	sort.Slice(bets, func(i, j int) bool {
+		left := bets[i].SettledAt
+		right := bets[j].SettledAt
+		if left == nil && right == nil {
+			return bets[i].PlacedAt.Before(bets[j].PlacedAt)
+		}
+		if left == nil {
+			return false
+		}
+		if right == nil {
+			return true
+		}
+		return left.Before(*right)
+	})
+}
+
 // GetDashboardData aggregates data for monitoring dashboard
 func (m *Monitor) GetDashboardData(ctx context.Context) (*DashboardData, error) {
-	now := time.Now()
+	now := m.now()
 	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
 
 	// Get all strategies
@@ -379,7 +524,7 @@ func (m *Monitor) GetDashboardData(ctx context.Context) (*DashboardData, error)
 
 	activeCount := 0
 	for _, strategy := range strategies {
-		if strategy.IsActive {
+		if strategy.IsActive && !strategy.IsArchived() {
 			activeCount++
 		}
 	}
@@ -423,12 +568,18 @@ func (m *Monitor) GetDashboardData(ctx context.Context) (*DashboardData, error)
 		recentBets = todayBets[:limit]
 	}
 
+	var circuitEvents []CircuitEvent
+	if m.circuitBreaker != nil {
+		circuitEvents = m.circuitBreaker.GetEvents()
+	}
+
 	return &DashboardData{
-		TotalStrategies:  len(strategies),
-		ActiveStrategies: activeCount,
-		TotalBetsToday:   len(todayBets),
-		TotalPLToday:     totalPLToday,
-		TopPerformers:    topPerformers,
-		RecentBets:       recentBets,
+		TotalStrategies:      len(strategies),
+		ActiveStrategies:     activeCount,
+		TotalBetsToday:       len(todayBets),
+		TotalPLToday:         totalPLToday,
+		TopPerformers:        topPerformers,
+		RecentBets:           recentBets,
+		CircuitBreakerEvents: circuitEvents,
 	}, nil
 }
@@ -0,0 +1,100 @@
+package bot
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/clever-better/internal/models"
+)
+
+func settledBet(strategyID uuid.UUID, settledAt time.Time, pl float64) *models.Bet {
+	return &models.Bet{
+		ID:         uuid.New(),
+		StrategyID: strategyID,
+		Stake:      10.0,
+		Status:     models.BetStatusSettled,
+		PlacedAt:   settledAt.Add(-time.Minute),
+		SettledAt:  &settledAt,
+		ProfitLoss: &pl,
+	}
+}
+
+func TestGetLiveMetricsEMAReactsFasterThanCumulative(t *testing.T) {
+	strategyID := uuid.New()
+	start := time.Now().Add(-time.Hour)
+
+	var bets []*models.Bet
+	for i := 0; i < 10; i++ {
+		bets = append(bets, settledBet(strategyID, start.Add(time.Duration(i)*time.Minute), 10.0))
+	}
+	for i := 10; i < 20; i++ {
+		bets = append(bets, settledBet(strategyID, start.Add(time.Duration(i)*time.Minute), -10.0))
+	}
+	// Repository order should not matter
+	bets[0], bets[19] = bets[19], bets[0]
+
+	betRepo := new(MockBetRepository)
+	betRepo.On("GetByStrategyID", mock.Anything, strategyID, mock.Anything, mock.Anything).Return(bets, nil)
+
+	monitor := NewMonitor(betRepo, nil, nil, nil, 1000.0, time.Minute, logrus.New())
+	monitor.SetEMAHalfLife(5)
+
+	perf, err := monitor.GetLiveMetrics(context.Background(), strategyID)
+	require.NoError(t, err)
+
+	assert.InDelta(t, 0.0, perf.ROI, 1e-9)
+	assert.InDelta(t, 0.5, perf.WinRate, 1e-9)
+	assert.Less(t, perf.EMAROI, perf.ROI)
+	assert.Less(t, perf.EMAWinRate, perf.WinRate)
+	// Ten losses at a half-life of five leave a quarter of the winning weight
+	assert.InDelta(t, -0.5, perf.EMAROI, 1e-9)
+	assert.InDelta(t, 0.25, perf.EMAWinRate, 1e-9)
+}
+
+func TestGetLiveMetricsFlagsInsufficientData(t *testing.T) {
+	strategyID := uuid.New()
+	start := time.Now().Add(-time.Hour)
+	bets := []*models.Bet{
+		settledBet(strategyID, start, 10.0),
+		settledBet(strategyID, start.Add(time.Minute), 10.0),
+		{ID: uuid.New(), StrategyID: strategyID, Stake: 10.0, Status: models.BetStatusPending},
+	}
+
+	betRepo := new(MockBetRepository)
+	betRepo.On("GetByStrategyID", mock.Anything, strategyID, mock.Anything, mock.Anything).Return(bets, nil)
+	monitor := NewMonitor(betRepo, nil, nil, nil, 1000.0, time.Minute, logrus.New())
+
+	perf, err := monitor.GetLiveMetrics(context.Background(), strategyID)
+	require.NoError(t, err)
+	assert.False(t, perf.InsufficientData, "no minimum sample is set")
+
+	monitor.SetMinSampleBets(3)
+	perf, err = monitor.GetLiveMetrics(context.Background(), strategyID)
+	require.NoError(t, err)
+	assert.Equal(t, 2, perf.SettledBets)
+	assert.True(t, perf.InsufficientData, "the pending bet does not count towards the sample")
+	assert.InDelta(t, 2.0/3.0, perf.WinRate, 1e-9, "metrics are still reported")
+
+	monitor.SetMinSampleBets(2)
+	perf, err = monitor.GetLiveMetrics(context.Background(), strategyID)
+	require.NoError(t, err)
+	assert.False(t, perf.InsufficientData)
+}
+
+func TestEMATrackerIgnoresUnsettledBets(t *testing.T) {
+	ema := newEMATracker(0)
+
+	ema.Update(&models.Bet{Stake: 10.0})
+	assert.Equal(t, 0, ema.count)
+
+	pl := 5.0
+	ema.Update(&models.Bet{Stake: 10.0, ProfitLoss: &pl})
+	assert.InDelta(t, 0.5, ema.ROI, 1e-9)
+	assert.InDelta(t, 1.0, ema.WinRate, 1e-9)
+}
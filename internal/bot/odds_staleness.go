@@ -0,0 +1,66 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/yourusername/clever-better/internal/models"
+	"github.com/yourusername/clever-better/internal/strategy"
+)
+
+// Staleness actions for trading.odds_staleness.action
+const (
+	// StalenessReject skips a bet whose signal price has gone stale
+	StalenessReject = "reject"
+	// StalenessReprice places a stale bet at the current price instead
+	StalenessReprice = "reprice"
+)
+
+// ErrStaleOdds is returned when the market has moved too far from the signal price
+var ErrStaleOdds = errors.New("signal odds are stale")
+
+// PriceSource reports the best price currently available to a side of a selection
+type PriceSource interface {
+	GetBestPrice(ctx context.Context, marketID string, selectionID uint64, side string) (float64, error)
+}
+
+// StalenessGuard re-checks the market just before placement so a bet is not
+// placed at a price the market has since moved away from
+type StalenessGuard struct {
+	source   PriceSource
+	maxTicks int
+	action   string
+}
+
+// NewStalenessGuard creates a guard allowing the current price to be up to
+// maxTicks ladder ticks from the signal price. An empty action rejects.
+func NewStalenessGuard(source PriceSource, maxTicks int, action string) *StalenessGuard {
+	if action == "" {
+		action = StalenessReject
+	}
+	return &StalenessGuard{
+		source:   source,
+		maxTicks: maxTicks,
+		action:   action,
+	}
+}
+
+// Check fetches the current price and returns the odds to place at. Within
+// tolerance the signal odds are kept; beyond it the guard returns an error
+// wrapping ErrStaleOdds, or the current price when repricing.
+func (g *StalenessGuard) Check(ctx context.Context, marketID string, selectionID uint64, side models.BetSide, odds float64) (float64, error) {
+	current, err := g.source.GetBestPrice(ctx, marketID, selectionID, string(side))
+	if err != nil {
+		return 0, fmt.Errorf("failed to refresh price: %w", err)
+	}
+
+	moved := strategy.TicksBetween(odds, current)
+	if moved <= g.maxTicks {
+		return odds, nil
+	}
+	if g.action == StalenessReprice {
+		return current, nil
+	}
+	return 0, fmt.Errorf("%w: price moved %d ticks from %.2f to %.2f, max %d", ErrStaleOdds, moved, odds, current, g.maxTicks)
+}
@@ -0,0 +1,87 @@
+package bot
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/clever-better/internal/config"
+	"github.com/yourusername/clever-better/internal/models"
+	"github.com/yourusername/clever-better/internal/strategy"
+)
+
+// fixedPriceSource reports the same current price for every selection
+type fixedPriceSource struct {
+	price float64
+	sides []string
+}
+
+func (f *fixedPriceSource) GetBestPrice(ctx context.Context, marketID string, selectionID uint64, side string) (float64, error) {
+	f.sides = append(f.sides, side)
+	return f.price, nil
+}
+
+func newStalenessExecutor(t *testing.T, guard *StalenessGuard) (*Executor, *MockBetRepository) {
+	t.Helper()
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	betRepo := new(MockBetRepository)
+	betRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+
+	riskManager := NewRiskManager(&config.TradingConfig{MaxStakePerBet: 100, MaxExposure: 500, MaxDailyLoss: 200}, betRepo, logger)
+	executor := NewExecutor(nil, betRepo, riskManager, true, false, logger, nil)
+	executor.SetStalenessGuard(guard)
+	return executor, betRepo
+}
+
+func TestExecuteSignalSkipsStaleOdds(t *testing.T) {
+	// 3.0 to 3.3 is six ticks on the 0.05 band
+	source := &fixedPriceSource{price: 3.3}
+	executor, betRepo := newStalenessExecutor(t, NewStalenessGuard(source, 2, StalenessReject))
+
+	signal := strategy.Signal{RunnerID: uuid.New(), Side: models.BetSideLay, Odds: 3.0, Stake: 10}
+	_, err := executor.ExecuteSignal(context.Background(), signal, uuid.New(), uuid.New(), "1.234", 7)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrStaleOdds)
+	assert.Contains(t, err.Error(), "moved 6 ticks")
+
+	metrics := executor.GetMetrics()
+	assert.Equal(t, int64(1), metrics.OrdersRejected)
+	assert.Equal(t, int64(1), metrics.StaleOddsRejections)
+	assert.Equal(t, []string{"LAY"}, source.sides, "the price is refreshed for the order's side")
+	betRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestExecuteSignalProceedsWithinStalenessTolerance(t *testing.T) {
+	source := &fixedPriceSource{price: 3.1}
+	executor, betRepo := newStalenessExecutor(t, NewStalenessGuard(source, 2, StalenessReject))
+
+	signal := strategy.Signal{RunnerID: uuid.New(), Side: models.BetSideBack, Odds: 3.0, Stake: 10}
+	bet, err := executor.ExecuteSignal(context.Background(), signal, uuid.New(), uuid.New(), "1.234", 7)
+	require.NoError(t, err)
+	assert.Equal(t, 3.0, bet.Odds, "within tolerance the signal price is kept")
+	assert.Zero(t, executor.GetMetrics().StaleOddsRejections)
+	betRepo.AssertNumberOfCalls(t, "Create", 1)
+
+	// Without a selection mapping or for BSP orders there is nothing to refresh
+	bsp := strategy.Signal{RunnerID: uuid.New(), Side: models.BetSideBack, Odds: 9.0, Stake: 10, BSP: true}
+	_, err = executor.ExecuteSignal(context.Background(), bsp, uuid.New(), uuid.New(), "1.234", 7)
+	require.NoError(t, err)
+	assert.Len(t, source.sides, 1)
+}
+
+func TestExecuteSignalRepricesStaleOdds(t *testing.T) {
+	source := &fixedPriceSource{price: 3.3}
+	executor, _ := newStalenessExecutor(t, NewStalenessGuard(source, 2, StalenessReprice))
+
+	signal := strategy.Signal{RunnerID: uuid.New(), Side: models.BetSideBack, Odds: 3.0, Stake: 10}
+	bet, err := executor.ExecuteSignal(context.Background(), signal, uuid.New(), uuid.New(), "1.234", 7)
+	require.NoError(t, err)
+	assert.Equal(t, 3.3, bet.Odds)
+	assert.Zero(t, executor.GetMetrics().OrdersRejected)
+}
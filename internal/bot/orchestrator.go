@@ -2,7 +2,10 @@ package bot
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
 	"sync"
 	"time"
 
@@ -11,6 +14,8 @@ import (
 	"github.com/yourusername/clever-better/internal/betfair"
 	"github.com/yourusername/clever-better/internal/config"
 	"github.com/yourusername/clever-better/internal/database"
+	applogger "github.com/yourusername/clever-better/internal/logger"
+	"github.com/yourusername/clever-better/internal/metrics"
 	"github.com/yourusername/clever-better/internal/ml"
 	"github.com/yourusername/clever-better/internal/models"
 	"github.com/yourusername/clever-better/internal/repository"
@@ -25,25 +30,79 @@ type Repositories struct {
 	Odds               repository.OddsRepository
 	Bet                repository.BetRepository
 	StrategyPerformance repository.StrategyPerformanceRepository
+	// Model and Prediction are optional; observe mode stores predictions
+	// only when both are set
+	Model               repository.ModelRepository
+	Prediction          repository.PredictionRepository
+}
+
+// mlPredictor is the part of the ML client the trading loop uses
+type mlPredictor interface {
+	GetPrediction(ctx context.Context, raceID, runnerID, strategyID uuid.UUID, features []float64, modelVersion string) (*ml.PredictionResult, error)
 }
 
 // OrchestratorStatus represents current bot status
 type OrchestratorStatus struct {
-	Running             bool            `json:"running"`
-	PaperTradingMode    bool            `json:"paper_trading_mode"`
-	ActiveStrategies    int             `json:"active_strategies"`
-	CircuitBreakerState CircuitState    `json:"circuit_breaker_state"`
-	RiskMetrics         RiskMetrics     `json:"risk_metrics"`
-	MonitorMetrics      MonitorMetrics  `json:"monitor_metrics"`
-	ExecutorMetrics     ExecutorMetrics `json:"executor_metrics"`
-	LastUpdate          time.Time       `json:"last_update"`
+	Running              bool            `json:"running"`
+	PaperTradingMode     bool            `json:"paper_trading_mode"`
+	ActiveStrategies     int             `json:"active_strategies"`
+	PausedStrategies     int             `json:"paused_strategies"`
+	CircuitBreakerState  CircuitState    `json:"circuit_breaker_state"`
+	CircuitBreakerEvents []CircuitEvent  `json:"circuit_breaker_events"`
+	RiskMetrics          RiskMetrics     `json:"risk_metrics"`
+	MonitorMetrics       MonitorMetrics  `json:"monitor_metrics"`
+	ExecutorMetrics      ExecutorMetrics `json:"executor_metrics"`
+	WarmingUp            bool            `json:"warming_up"`
+	StrategiesLoadedAt   time.Time       `json:"strategies_loaded_at"`
+	KillSwitchEngaged    bool            `json:"kill_switch_engaged"`
+	KillSwitchSource     string          `json:"kill_switch_source,omitempty"`
+	KillSwitchEngagedAt  time.Time       `json:"kill_switch_engaged_at"`
+	// MaintenanceUntil is the end of the Betfair maintenance window trading
+	// is paused for, zero outside one
+	MaintenanceUntil     time.Time       `json:"maintenance_until"`
+	// UnknownStrategyTypes counts active strategies skipped because their
+	// type is unknown
+	UnknownStrategyTypes int             `json:"unknown_strategy_types"`
+	LastUpdate           time.Time       `json:"last_update"`
+}
+
+// oddsHistoryLookback bounds how much odds history is loaded per evaluation
+const oddsHistoryLookback = 2 * time.Hour
+
+// flattenTimeout bounds how long an emergency flatten may take
+const flattenTimeout = 30 * time.Second
+
+// ErrStrategyTimeout is returned when a strategy's evaluation of a race
+// exceeds trading.strategy_evaluation_timeout
+var ErrStrategyTimeout = errors.New("strategy evaluation timed out")
+
+// Actions for bot.unknown_strategy_types, taken at startup when an active
+// strategy has a type the bot cannot instantiate
+const (
+	// UnknownStrategyTypeSkip logs the strategy and trades the others
+	UnknownStrategyTypeSkip = "skip"
+	// UnknownStrategyTypeFail refuses to start the bot
+	UnknownStrategyTypeFail = "fail"
+)
+
+// UnknownStrategyTypeError reports an active strategy whose type the bot
+// cannot instantiate, so it is not trading
+type UnknownStrategyTypeError struct {
+	StrategyID   uuid.UUID
+	StrategyName string
+	Type         string
+}
+
+func (e *UnknownStrategyTypeError) Error() string {
+	return fmt.Sprintf("strategy %q (%s) has unknown type %q", e.StrategyName, e.StrategyID, e.Type)
 }
 
 // Orchestrator coordinates all bot components
 type Orchestrator struct {
 	config           *config.Config
 	db               *database.DB
-	mlClient         *ml.CachedMLClient
+	mlClient         mlPredictor
+	mlRecorder       *PredictionRecorder
 	bettingService   *betfair.BettingService
 	orderManager     *betfair.OrderManager
 	strategyRepo     repository.StrategyRepository
@@ -52,10 +111,37 @@ type Orchestrator struct {
 	oddsRepo         repository.OddsRepository
 	betRepo          repository.BetRepository
 	riskManager      *RiskManager
+	allocator        *BankrollAllocator
+	bankrollProvider BankrollProvider
+	accountRouter    *AccountRouter
 	executor         *Executor
+	marketThrottle   *MarketThrottle
+	recoveryRamp     *RecoveryRamp
+	backtests        *onDemandBacktests
 	monitor          *Monitor
 	circuitBreaker   *CircuitBreaker
 	activeStrategies map[uuid.UUID]strategy.Strategy
+	pausedStrategies map[uuid.UUID]bool
+	strategiesAt     time.Time
+	unknownTypes     error
+	stakingPlans     map[uuid.UUID]strategy.StakingPlan
+	strategyShares   map[uuid.UUID]float64
+	evalTimeout      time.Duration
+	edgeGate         strategy.EdgeGate
+	mlFilterMode     string
+	mlFailurePolicy  string
+	liquidityFilter  *LiquidityFilter
+	marketFilter     *strategy.MarketFilter
+	marketStates     MarketStateSource
+	inPlayStrategies map[uuid.UUID]bool
+	replaying        bool
+	raceBundles      RaceBundleSource
+	clock            Clock
+	warmUpUntil      time.Time
+	killSwitch       *KillSwitch
+	killSwitchSource string
+	killSwitchAt     time.Time
+	maintenanceUntil time.Time
 	logger           *logrus.Logger
 	strategyLogger   *logrus.Entry
 	mlLogger         *logrus.Entry
@@ -81,6 +167,13 @@ func NewOrchestrator(
 	// Initialize risk manager
 	riskManager := NewRiskManager(&cfg.Trading, repos.Bet, logger)
 
+	// Give each strategy its own slice of the bankroll when enabled
+	var allocator *BankrollAllocator
+	if cfg.Trading.BankrollAllocation.Enabled {
+		allocator = NewBankrollAllocator(cfg.Trading.BankrollAllocation, cfg.Backtest.InitialBankroll, repos.Bet, logger)
+		riskManager.SetAllocator(allocator)
+	}
+
 	// Initialize executor (paper trading mode from config)
 	executor := NewExecutor(
 		bettingService,
@@ -91,16 +184,51 @@ func NewOrchestrator(
 		logger,
 		auditLogger,
 	)
+	if cfg.Trading.OddsSanity.Enabled {
+		executor.SetOddsSanityFilter(newOddsSanityFilter(cfg.Trading.OddsSanity), repos.Race, repos.Odds)
+	}
+	executor.SetSelfMatchPrevention(cfg.Trading.PreventSelfMatch)
+	executor.SetStakeRounding(strategy.StakeRounding{
+		MinStake:  cfg.Trading.StakeRounding.MinStake,
+		Increment: cfg.Trading.StakeRounding.Increment,
+	})
+	if cfg.Trading.OddsStaleness.Enabled && bettingService != nil {
+		executor.SetStalenessGuard(NewStalenessGuard(bettingService, cfg.Trading.OddsStaleness.MaxTicks, cfg.Trading.OddsStaleness.Action))
+	}
+	if cfg.Trading.LadderDepthCheck && bettingService != nil {
+		executor.SetDepthGuard(NewDepthGuard(bettingService, cfg.Trading.MaxLadderDepth))
+	}
+	var marketThrottle *MarketThrottle
+	if cfg.Trading.MinMarketBetIntervalSeconds > 0 {
+		marketThrottle = NewMarketThrottle(time.Duration(cfg.Trading.MinMarketBetIntervalSeconds) * time.Second)
+		executor.SetMarketThrottle(marketThrottle)
+	}
+	if cfg.Trading.PlacementRateLimit > 0 {
+		cutoff := time.Duration(cfg.Trading.MinTimeToStartSeconds) * time.Second
+		executor.SetPlacementQueue(NewPlacementQueue(cfg.Trading.PlacementRateLimit, cutoff))
+	}
+
+	// Size stakes against the live account balance when configured
+	var bankrollProvider BankrollProvider = NewFixedBankroll(cfg.Backtest.InitialBankroll)
+	if cfg.Trading.BankrollSource == BankrollSourceLiveBalance && bettingService != nil {
+		bankrollProvider = NewLiveBalanceBankroll(bettingService, accountFundsCacheTTL)
+	}
 
 	// Initialize circuit breaker
 	circuitBreakerConfig := CircuitBreakerConfig{
 		MaxConsecutiveLosses: cfg.Bot.MaxConsecutiveLosses,
+		MaxConsecutiveWins:   cfg.Bot.MaxConsecutiveWins,
 		MaxDrawdownPercent:   cfg.Bot.MaxDrawdownPercent,
 		MaxFailureCount:      10,
 		FailureTimeWindow:    5 * time.Minute,
 		CooldownPeriod:       30 * time.Minute,
 	}
 	circuitBreaker := NewCircuitBreaker(circuitBreakerConfig, logger)
+	var recoveryRamp *RecoveryRamp
+	if recovery := cfg.Bot.CircuitRecovery; recovery.Enabled {
+		recoveryRamp = NewRecoveryRamp(recovery.StartFraction, recovery.Bets, time.Duration(recovery.DurationMinutes)*time.Minute)
+		circuitBreaker.SetRecoveryRamp(recoveryRamp)
+	}
 
 	// Initialize monitor
 	updateInterval := time.Duration(cfg.Bot.PerformanceUpdateInterval) * time.Second
@@ -114,6 +242,8 @@ func NewOrchestrator(
 		updateInterval,
 		logger,
 	)
+	monitor.SetEMAHalfLife(cfg.Bot.EMAHalfLifeBets)
+	monitor.SetMinSampleBets(cfg.Bot.MinSampleBets)
 
 	o := &Orchestrator{
 		config:           cfg,
@@ -127,10 +257,24 @@ func NewOrchestrator(
 		oddsRepo:         repos.Odds,
 		betRepo:          repos.Bet,
 		riskManager:      riskManager,
+		allocator:        allocator,
+		bankrollProvider: bankrollProvider,
 		executor:         executor,
+		marketThrottle:   marketThrottle,
+		recoveryRamp:     recoveryRamp,
 		monitor:          monitor,
 		circuitBreaker:   circuitBreaker,
 		activeStrategies: make(map[uuid.UUID]strategy.Strategy),
+		inPlayStrategies: make(map[uuid.UUID]bool),
+		stakingPlans:     make(map[uuid.UUID]strategy.StakingPlan),
+		strategyShares:   make(map[uuid.UUID]float64),
+		evalTimeout:      time.Duration(cfg.Trading.StrategyEvaluationTimeout) * time.Second,
+		edgeGate:         strategy.NewEdgeGate(cfg.Trading.MinEdgeThreshold, minConfidence(&cfg.Trading)).WithBands(newOddsBands(cfg.Trading.OddsBands)),
+		mlFilterMode:     cfg.Trading.MLFilterMode,
+		mlFailurePolicy:  cfg.Trading.MLFailurePolicy,
+		marketFilter:     newMarketFilter(cfg.Trading.MarketFilter),
+		clock:            RealClock{},
+		killSwitch:       NewKillSwitch(cfg.Bot.KillSwitch.File, cfg.Bot.KillSwitch.Engaged),
 		logger:           logger,
 		strategyLogger:   strategyLogger,
 		mlLogger:         mlLogger,
@@ -138,17 +282,70 @@ func NewOrchestrator(
 		done:             make(chan struct{}),
 	}
 
+	if repos.Prediction != nil && repos.Model != nil {
+		o.mlRecorder = NewPredictionRecorder(repos.Prediction, repos.Model)
+	}
+
+	// Skip thin markets before running strategies
+	if bettingService != nil {
+		minLiquidity := cfg.Trading.MinMarketLiquidity
+		if minLiquidity == 0 {
+			minLiquidity = cfg.Backtest.MinLiquidity
+		}
+		if minLiquidity > 0 {
+			o.liquidityFilter = NewLiquidityFilter(bettingService, minLiquidity, liquidityCacheTTL)
+		}
+	}
+
+	// Load runners and odds for pre-race evaluation in one query
+	if repos.Race != nil {
+		o.raceBundles = repos.Race
+	}
+
+	// Watch started races for their markets turning in-play
+	if cfg.Trading.InPlay.Enabled && bettingService != nil {
+		o.marketStates = bettingService
+	}
+
+	// Keep circuit breaker transitions in the audit trail
+	if auditLogger != nil {
+		circuitBreaker.RegisterEventListener(func(event CircuitEvent) {
+			auditLogger.WithFields(logrus.Fields{
+				"event_type":         "circuit_breaker_transition",
+				"from_state":         event.From,
+				"to_state":           event.To,
+				"reason":             event.Reason,
+				"consecutive_losses": event.ConsecutiveLosses,
+				"consecutive_wins":   event.ConsecutiveWins,
+				"drawdown":           event.Drawdown,
+				"failure_count":      event.FailureCount,
+			}).Warn("Circuit breaker event recorded")
+		})
+	}
+
 	// Register emergency shutdown callback
 	if cfg.Trading.EmergencyShutdownEnabled {
 		circuitBreaker.RegisterShutdownCallback(func(reason string) error {
 			logger.WithField("reason", reason).Error("Emergency shutdown callback triggered")
+			ctx, cancel := context.WithTimeout(context.Background(), flattenTimeout)
+			defer cancel()
+			if err := o.flattenOpenMarkets(ctx, cfg.Trading.GreenUpOnShutdown); err != nil {
+				logger.WithError(err).Error("Failed to flatten open markets during emergency shutdown")
+			}
 			return o.Stop()
 		})
 	}
 
-	// Load active strategies
+	// Load active strategies. If the repository is down, trading stays
+	// halted until a reload from the trading loop succeeds.
 	if err := o.loadActiveStrategies(context.Background()); err != nil {
-		return nil, fmt.Errorf("failed to load active strategies: %w", err)
+		logger.WithError(err).Error("Failed to load active strategies, trading halted until they load")
+	}
+	if err := o.UnknownStrategyTypes(); err != nil {
+		if cfg.Bot.UnknownStrategyTypes == UnknownStrategyTypeFail {
+			return nil, fmt.Errorf("failed to load active strategies: %w", err)
+		}
+		logger.WithError(err).Error("Active strategies with unknown types are not trading")
 	}
 
 	logger.Info("Bot orchestrator initialized successfully")
@@ -156,6 +353,103 @@ func NewOrchestrator(
 	return o, nil
 }
 
+// SetAccountRouter spreads live bets across several Betfair accounts and
+// assigns loaded strategies to the accounts that list them
+func (o *Orchestrator) SetAccountRouter(router *AccountRouter) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.accountRouter = router
+	o.executor.SetAccountRouter(router)
+	if router == nil {
+		return
+	}
+	strategies, err := o.strategyRepo.GetAll(context.Background())
+	if err != nil {
+		o.logger.WithError(err).Warn("Failed to load strategies for account assignment")
+		return
+	}
+	for _, stratModel := range strategies {
+		if _, ok := o.activeStrategies[stratModel.ID]; ok {
+			o.assignStrategyAccount(stratModel)
+		}
+	}
+}
+
+// SetBankrollProvider replaces the source of the bankroll stakes are sized
+// against. Without one, the configured initial bankroll is used.
+func (o *Orchestrator) SetBankrollProvider(provider BankrollProvider) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.bankrollProvider = provider
+}
+
+// currentBankroll returns the bankroll stakes are sized against
+func (o *Orchestrator) currentBankroll(ctx context.Context) (float64, error) {
+	o.mu.RLock()
+	provider := o.bankrollProvider
+	o.mu.RUnlock()
+
+	if provider == nil {
+		return o.config.Backtest.InitialBankroll, nil
+	}
+	return provider.Bankroll(ctx)
+}
+
+// SetClock replaces the clock used by the orchestrator, its risk manager, its
+// market throttle, its recovery ramp and its monitor. Replays and tests use it to run on simulated time.
+func (o *Orchestrator) SetClock(clock Clock) {
+	clock = clockOrReal(clock)
+
+	o.mu.Lock()
+	o.clock = clock
+	o.mu.Unlock()
+
+	if o.riskManager != nil {
+		o.riskManager.SetClock(clock)
+	}
+	if o.marketThrottle != nil {
+		o.marketThrottle.SetClock(clock)
+	}
+	if o.recoveryRamp != nil {
+		o.recoveryRamp.SetClock(clock)
+	}
+	if o.monitor != nil {
+		o.monitor.SetClock(clock)
+	}
+}
+
+// now returns the orchestrator's current time
+func (o *Orchestrator) now() time.Time {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	return clockOrReal(o.clock).Now()
+}
+
+// beginWarmUp starts the warm-up window, during which signals are evaluated
+// and logged but no bets are placed. Exposure, daily loss, the circuit breaker
+// and ML caches have incomplete state straight after startup.
+func (o *Orchestrator) beginWarmUp() {
+	warmUp := time.Duration(o.config.Bot.WarmUpSeconds) * time.Second
+	if warmUp <= 0 {
+		return
+	}
+
+	o.mu.Lock()
+	o.warmUpUntil = clockOrReal(o.clock).Now().Add(warmUp)
+	until := o.warmUpUntil
+	o.mu.Unlock()
+
+	o.logger.WithField("warm_up_until", until).Info("Warming up, bets will not be placed until the window ends")
+}
+
+// warmingUp reports whether now falls inside the warm-up window
+func (o *Orchestrator) warmingUp(now time.Time) bool {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	return now.Before(o.warmUpUntil)
+}
+
 // Start starts all bot components and begins trading loop
 func (o *Orchestrator) Start(ctx context.Context) error {
 	o.mu.Lock()
@@ -166,6 +460,8 @@ func (o *Orchestrator) Start(ctx context.Context) error {
 	o.running = true
 	o.mu.Unlock()
 
+	o.beginWarmUp()
+
 	o.logger.WithFields(logrus.Fields{
 		"paper_trading":      o.config.Features.PaperTradingEnabled,
 		"active_strategies":  len(o.activeStrategies),
@@ -196,14 +492,95 @@ func (o *Orchestrator) Start(ctx context.Context) error {
 		o.logger.WithError(err).Warn("Failed to update initial daily loss")
 	}
 
+	// Allocate the bankroll before the first signals are sized
+	if o.allocator != nil {
+		o.rebalanceAllocations(ctx)
+		if interval := o.config.Trading.BankrollAllocation.RebalanceIntervalMinutes; interval > 0 {
+			go o.rebalanceLoop(ctx, time.Duration(interval)*time.Minute)
+		}
+	}
+
 	// Start trading loop in goroutine
 	go o.tradingLoop(ctx)
 
+	if o.config.Trading.InPlay.Enabled && o.marketStates != nil {
+		go o.inPlayLoop(ctx, time.Duration(o.config.Trading.InPlay.TickSeconds)*time.Second)
+	}
+
 	o.logger.Info("Bot orchestrator started successfully")
 
 	return nil
 }
 
+// FlattenResult summarises an emergency flatten of one market
+type FlattenResult struct {
+	MarketID  string `json:"market_id"`
+	Cancelled int    `json:"cancelled"`
+	Hedged    int    `json:"hedged"`
+}
+
+// FlattenMarket cancels all unmatched bets on a market. When greenUp is set
+// it also hedges matched bets so the result of the race no longer matters.
+func (o *Orchestrator) FlattenMarket(ctx context.Context, marketID string, greenUp bool) (FlattenResult, error) {
+	result := FlattenResult{MarketID: marketID}
+	if o.bettingService == nil {
+		return result, fmt.Errorf("live betting service is not configured")
+	}
+
+	cancelled, err := o.bettingService.CancelAllForMarket(ctx, marketID)
+	result.Cancelled = cancelled
+	if err != nil {
+		return result, err
+	}
+
+	if greenUp {
+		hedged, err := o.bettingService.GreenUpMarket(ctx, marketID)
+		result.Hedged = hedged
+		if err != nil {
+			return result, fmt.Errorf("failed to green up market %s: %w", marketID, err)
+		}
+	}
+
+	if o.auditLogger != nil {
+		o.auditLogger.WithContext(ctx).WithFields(logrus.Fields{
+			"event_type": "market_flattened",
+			"market_id":  marketID,
+			"cancelled":  result.Cancelled,
+			"hedged":     result.Hedged,
+			"green_up":   greenUp,
+		}).Warn("Market flattened")
+	}
+
+	return result, nil
+}
+
+// flattenOpenMarkets flattens every market with current orders on the exchange
+func (o *Orchestrator) flattenOpenMarkets(ctx context.Context, greenUp bool) error {
+	if o.bettingService == nil {
+		return nil
+	}
+
+	orders, err := o.bettingService.ListCurrentOrders(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to list current orders: %w", err)
+	}
+
+	seen := make(map[string]bool)
+	var errs []error
+	for _, order := range orders {
+		if order.MarketID == "" || seen[order.MarketID] {
+			continue
+		}
+		seen[order.MarketID] = true
+
+		if _, err := o.FlattenMarket(ctx, order.MarketID, greenUp); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
 // Stop gracefully stops all bot components
 func (o *Orchestrator) Stop() error {
 	o.mu.Lock()
@@ -231,11 +608,44 @@ func (o *Orchestrator) Stop() error {
 		}
 	}
 
+	o.publishStatus()
 	o.logger.Info("Bot orchestrator stopped")
 
 	return nil
 }
 
+// rebalanceLoop periodically reallocates the bankroll across active strategies
+func (o *Orchestrator) rebalanceLoop(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-o.done:
+			return
+		case <-ticker.C:
+			o.rebalanceAllocations(ctx)
+		}
+	}
+}
+
+// rebalanceAllocations reallocates the bankroll across the active strategies.
+// On failure the previous allocation is kept.
+func (o *Orchestrator) rebalanceAllocations(ctx context.Context) {
+	o.mu.RLock()
+	weights := make(map[uuid.UUID]float64, len(o.strategyShares))
+	for id, weight := range o.strategyShares {
+		weights[id] = weight
+	}
+	o.mu.RUnlock()
+
+	if _, err := o.allocator.Rebalance(ctx, weights, o.now()); err != nil {
+		o.logger.WithError(err).Warn("Failed to rebalance bankroll allocation")
+	}
+}
+
 // tradingLoop main trading loop that evaluates strategies and executes signals
 func (o *Orchestrator) tradingLoop(ctx context.Context) {
 	evaluationInterval := time.Duration(o.config.Trading.StrategyEvaluationInterval) * time.Second
@@ -255,108 +665,343 @@ func (o *Orchestrator) tradingLoop(ctx context.Context) {
 			return
 
 		case <-ticker.C:
-			// Check circuit breaker
-			if o.circuitBreaker.IsOpen() {
-				o.logger.Warn("Trading halted: circuit breaker is open")
-				continue
-			}
+			o.tradingTick(ctx)
+		}
+	}
+}
 
-			// Update risk metrics
-			if err := o.riskManager.UpdateExposure(ctx); err != nil {
-				o.logger.WithError(err).Error("Failed to update exposure")
-				o.circuitBreaker.RecordFailure(err)
-				continue
-			}
+// tradingTick runs one pass of the trading loop over the upcoming races
+func (o *Orchestrator) tradingTick(ctx context.Context) {
+	// Export status however the tick ends, so halts show up in alerts
+	defer o.publishStatus()
 
-			// Check risk limits
-			if !o.riskManager.IsWithinLimits() {
-				o.logger.Warn("Trading halted: risk limits exceeded")
-				continue
+	// Operators can halt all trading without a deploy
+	if o.killSwitchEngaged(ctx) {
+		return
+	}
+
+	// Betfair calls fail during scheduled maintenance, so wait it out
+	// before anything can count them against the circuit breaker
+	if o.inMaintenance(ctx) {
+		return
+	}
+
+	// Check circuit breaker
+	if o.circuitBreaker.IsOpen() {
+		o.logger.Warn("Trading halted: circuit breaker is open")
+		return
+	}
+
+	// Refresh strategies, trading on the last-known-good set if that fails
+	if !o.refreshStrategies(ctx, o.now()) {
+		return
+	}
+
+	// Update risk metrics
+	if err := o.riskManager.UpdateExposure(ctx); err != nil {
+		o.logger.WithError(err).Error("Failed to update exposure")
+		o.circuitBreaker.RecordFailure(err)
+		return
+	}
+
+	// Check risk limits
+	if !o.riskManager.IsWithinLimits() {
+		o.logger.Warn("Trading halted: risk limits exceeded")
+		return
+	}
+
+	// Get upcoming races
+	now := o.now()
+	windowStart := now.Add(time.Duration(o.config.Trading.MinTimeToStartSeconds) * time.Second)
+	windowEnd := now.Add(time.Duration(o.config.Trading.PreRaceWindowMinutes) * time.Minute)
+
+	races, err := o.raceRepo.GetByDateRange(ctx, windowStart, windowEnd)
+	if err != nil {
+		o.logger.WithError(err).Error("Failed to get upcoming races")
+		o.circuitBreaker.RecordFailure(err)
+		return
+	}
+
+	o.logger.WithField("race_count", len(races)).Debug("Processing upcoming races")
+
+	// Evaluate strategies for each race, each under its own request ID
+	// so its logs, ML calls and orders can be correlated
+	for _, race := range races {
+		raceCtx := applogger.WithRequestID(ctx, applogger.NewRequestID())
+		if _, err := o.processRace(raceCtx, race, now); err != nil {
+			o.logger.WithContext(raceCtx).WithFields(logrus.Fields{
+				"race_id": race.ID,
+				"error":   err.Error(),
+			}).Error("Failed to evaluate strategies for race")
+		}
+	}
+}
+
+// killSwitchEngaged reports whether the kill switch halts trading. When the
+// switch is first engaged unmatched bets are optionally cancelled.
+func (o *Orchestrator) killSwitchEngaged(ctx context.Context) bool {
+	if o.killSwitch == nil {
+		return false
+	}
+	engaged, source := o.killSwitch.Engaged()
+	now := o.now()
+
+	o.mu.Lock()
+	wasEngaged := o.killSwitchSource != ""
+	o.killSwitchSource = source
+	if engaged && !wasEngaged {
+		o.killSwitchAt = now
+	}
+	engagedAt := o.killSwitchAt
+	if !engaged {
+		o.killSwitchAt = time.Time{}
+	}
+	o.mu.Unlock()
+
+	switch {
+	case engaged && !wasEngaged:
+		o.logger.WithFields(logrus.Fields{
+			"source": source,
+			"file":   o.config.Bot.KillSwitch.File,
+		}).Warn("Trading halted: kill switch engaged")
+		o.auditKillSwitch(ctx, "kill_switch_engaged", source)
+
+		if o.config.Bot.KillSwitch.CancelUnmatched {
+			cancelCtx, cancel := context.WithTimeout(ctx, flattenTimeout)
+			defer cancel()
+			if err := o.flattenOpenMarkets(cancelCtx, false); err != nil {
+				o.logger.WithError(err).Error("Failed to cancel unmatched bets after kill switch engaged")
 			}
+		}
+	case !engaged && wasEngaged:
+		o.logger.WithField("halted_for", now.Sub(engagedAt)).Info("Kill switch cleared, trading resumed")
+		o.auditKillSwitch(ctx, "kill_switch_cleared", "")
+	case engaged:
+		o.logger.WithField("source", source).Debug("Trading halted: kill switch engaged")
+	}
+	return engaged
+}
 
-			// Get upcoming races
-			now := time.Now()
-			windowStart := now.Add(time.Duration(o.config.Trading.MinTimeToStartSeconds) * time.Second)
-			windowEnd := now.Add(time.Duration(o.config.Trading.PreRaceWindowMinutes) * time.Minute)
+// inMaintenance reports whether now falls in a configured Betfair maintenance
+// window, logging when trading pauses and resumes
+func (o *Orchestrator) inMaintenance(ctx context.Context) bool {
+	if o.config == nil {
+		return false
+	}
+	now := o.now()
+	until, active := o.config.Betfair.Maintenance.Active(now)
 
-			races, err := o.raceRepo.GetUpcomingRaces(ctx, windowStart, windowEnd)
-			if err != nil {
-				o.logger.WithError(err).Error("Failed to get upcoming races")
-				o.circuitBreaker.RecordFailure(err)
+	o.mu.Lock()
+	previous := o.maintenanceUntil
+	o.maintenanceUntil = until
+	o.mu.Unlock()
+
+	switch {
+	case active && previous.IsZero():
+		o.logger.WithContext(ctx).WithField("until", until).Warn("Trading paused for Betfair maintenance")
+	case !active && !previous.IsZero():
+		o.logger.WithContext(ctx).Info("Betfair maintenance window ended, trading resumed")
+	}
+	return active
+}
+
+// auditKillSwitch records a kill switch transition in the audit trail
+func (o *Orchestrator) auditKillSwitch(ctx context.Context, eventType, source string) {
+	if o.auditLogger == nil {
+		return
+	}
+	o.auditLogger.WithContext(ctx).WithFields(logrus.Fields{
+		"event_type": eventType,
+		"source":     source,
+	}).Warn("Kill switch state changed")
+}
+
+// processRace evaluates active strategies for a race as of now and executes
+// the resulting signals. It is shared by the live trading loop and the replay
+// harness so both exercise the same decision pipeline.
+func (o *Orchestrator) processRace(ctx context.Context, race *models.Race, now time.Time) ([]*models.Bet, error) {
+	return o.runRace(ctx, race, now, nil)
+}
+
+// runRace runs the decision pipeline for a race. A non-nil market is the
+// state of a race's in-play market, which only in-play strategies evaluate.
+func (o *Orchestrator) runRace(ctx context.Context, race *models.Race, now time.Time, market *betfair.MarketState) ([]*models.Bet, error) {
+	if !o.marketFilter.Allows(race) {
+		o.logger.WithContext(ctx).WithFields(logrus.Fields{
+			"race_id": race.ID,
+			"track":   race.Track,
+			"grade":   race.Grade,
+		}).Debug("Race excluded by market filter")
+		return nil, nil
+	}
+
+	if !o.hasLiquidity(ctx, race) {
+		return nil, nil
+	}
+
+	signals, err := o.evaluateStrategies(ctx, race, now, market)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(signals) == 0 {
+		return nil, nil
+	}
+
+	// Filter signals with ML predictions if enabled
+	if o.config.Features.MLPredictionsEnabled {
+		signals, err = o.filterSignalsWithML(ctx, signals)
+		if err != nil {
+			o.logger.WithContext(ctx).WithError(err).Warn("Failed to filter signals with ML")
+			// Continue with the signals ml_failure_policy leaves, unscored
+			// ones gated on strategy confidence when failing open
+		}
+	}
+
+	signals = o.applyStakingPlans(ctx, signals, now)
+	signals = o.applyRecoveryRamp(ctx, signals)
+
+	if o.warmingUp(now) {
+		for _, sc := range signals {
+			o.logger.WithContext(ctx).WithFields(logrus.Fields{
+				"strategy_id": sc.StrategyID,
+				"race_id":     sc.RaceID,
+				"runner_id":   sc.Signal.RunnerID,
+				"side":        sc.Signal.Side,
+				"odds":        sc.Signal.Odds,
+				"stake":       sc.Signal.Stake,
+			}).Info("Warm-up: signal not placed")
+		}
+		return nil, nil
+	}
+
+	// Execute approved signals
+	bets, results, err := o.executor.ExecuteBatch(ctx, signals)
+	failed := 0
+	if err != nil {
+		for _, result := range results {
+			if result.Err == nil {
 				continue
 			}
+			failed++
+			o.logger.WithContext(ctx).WithFields(logrus.Fields{
+				"strategy_id":  result.Signal.StrategyID,
+				"race_id":      result.Signal.RaceID,
+				"runner_id":    result.Signal.Signal.RunnerID,
+				"selection_id": result.Signal.SelectionID,
+				"error":        result.Err.Error(),
+			}).Warn("Failed to execute signal")
+		}
+	}
 
-			o.logger.WithField("race_count", len(races)).Debug("Processing upcoming races")
-
-			// Evaluate strategies for each race
-			for _, race := range races {
-				signals, err := o.evaluateStrategies(ctx, race)
-				if err != nil {
-					o.logger.WithFields(logrus.Fields{
-						"race_id": race.ID,
-						"error":   err.Error(),
-					}).Error("Failed to evaluate strategies for race")
-					continue
-				}
-
-				if len(signals) == 0 {
-					continue
-				}
-
-				// Filter signals with ML predictions if enabled
-				if o.config.Features.MLPredictionsEnabled {
-					signals, err = o.filterSignalsWithML(ctx, signals)
-					if err != nil {
-						o.logger.WithError(err).Warn("Failed to filter signals with ML")
-						// Continue with unfiltered signals
-					}
-				}
-
-				// Execute approved signals
-				bets, err := o.executor.ExecuteBatch(ctx, signals)
-				if err != nil {
-					o.logger.WithError(err).Warn("Batch execution had errors")
-				}
-
-				o.logger.WithFields(logrus.Fields{
-					"race_id":     race.ID,
-					"signals":     len(signals),
-					"bets_placed": len(bets),
-				}).Info("Race evaluation completed")
-
-				// Record success
-				o.circuitBreaker.RecordSuccess()
-			}
+	o.logger.WithContext(ctx).WithFields(logrus.Fields{
+		"race_id":        race.ID,
+		"signals":        len(signals),
+		"bets_placed":    len(bets),
+		"signals_failed": failed,
+	}).Info("Race evaluation completed")
+
+	// Record success
+	o.circuitBreaker.RecordSuccess()
+
+	return bets, nil
+}
+
+// hasLiquidity reports whether a race's market has enough matched volume to
+// trade. Replays skip the check: the live market is long gone and its
+// current volume says nothing about the recorded race.
+func (o *Orchestrator) hasLiquidity(ctx context.Context, race *models.Race) bool {
+	if o.liquidityFilter == nil || o.replaying {
+		return true
+	}
+
+	marketID, err := o.raceMarketID(ctx, race)
+	if err != nil {
+		o.logger.WithContext(ctx).WithError(err).WithField("race_id", race.ID).Warn("Failed to resolve race market, skipping race")
+		return false
+	}
+
+	allowed, liquidity, err := o.liquidityFilter.Allow(ctx, marketID)
+	if err != nil {
+		o.logger.WithContext(ctx).WithError(err).WithField("race_id", race.ID).Warn("Failed to fetch market liquidity, skipping race")
+		return false
+	}
+
+	if !allowed {
+		o.logger.WithContext(ctx).WithFields(logrus.Fields{
+			"race_id":       race.ID,
+			"market_id":     marketID,
+			"liquidity":     liquidity,
+			"min_liquidity": o.liquidityFilter.minLiquidity,
+		}).Debug("Skipping race with low market liquidity")
+	}
+
+	return allowed
+}
+
+// raceMarketID returns the Betfair market a race's runners were recorded
+// against at ingestion, or "" when none was recorded
+func (o *Orchestrator) raceMarketID(ctx context.Context, race *models.Race) (string, error) {
+	runners, err := o.runnerRepo.GetByRaceID(ctx, race.ID)
+	if err != nil {
+		return "", fmt.Errorf("failed to load runners: %w", err)
+	}
+	return marketIDOf(runners), nil
+}
+
+// marketIDOf returns the Betfair market recorded on the first runner that
+// has one
+func marketIDOf(runners []*models.Runner) string {
+	for _, runner := range runners {
+		if selection, ok := runner.BetfairSelection(); ok {
+			return selection.MarketID
 		}
 	}
+	return ""
 }
 
-// evaluateStrategies evaluates all active strategies for a race
-func (o *Orchestrator) evaluateStrategies(ctx context.Context, race *models.Race) ([]SignalWithContext, error) {
+// evaluateStrategies evaluates all active strategies for a race. When market
+// is set the race is in-play: only strategies that trade in-play evaluate it,
+// and their signals carry the market's bet delay.
+func (o *Orchestrator) evaluateStrategies(ctx context.Context, race *models.Race, now time.Time, market *betfair.MarketState) ([]SignalWithContext, error) {
 	o.mu.RLock()
 	strategies := make(map[uuid.UUID]strategy.Strategy, len(o.activeStrategies))
 	for id, strat := range o.activeStrategies {
+		if o.pausedStrategies[id] {
+			continue
+		}
+		if market != nil && !o.inPlayStrategies[id] {
+			continue
+		}
 		strategies[id] = strat
 	}
 	o.mu.RUnlock()
 
+	stratCtx, selections, err := o.strategyContext(ctx, race, now)
+	if err != nil {
+		return nil, err
+	}
+
 	signals := make([]SignalWithContext, 0)
 
 	for strategyID, strat := range strategies {
-		// Create strategy context
-		stratCtx := &strategy.StrategyContext{
-			RaceID:    race.ID,
-			EventID:   race.EventID,
-			StartTime: race.StartTime,
-		}
 
 		// Evaluate strategy
 		startTime := time.Now()
-		stratSignals, err := strat.Evaluate(ctx, stratCtx)
+		stratSignals, err := o.evaluateWithTimeout(ctx, strat, stratCtx)
 		duration := time.Since(startTime)
 
+		if errors.Is(err, ErrStrategyTimeout) {
+			metrics.RecordStrategyEvaluationTimeout(strategyID.String(), strat.Name())
+			o.logger.WithContext(ctx).WithFields(logrus.Fields{
+				"strategy_id": strategyID,
+				"race_id":     race.ID,
+				"timeout":     o.evalTimeout,
+			}).Warn("Strategy evaluation timed out, skipping")
+			continue
+		}
 		if err != nil {
-			o.logger.WithFields(logrus.Fields{
+			o.logger.WithContext(ctx).WithFields(logrus.Fields{
 				"strategy_id": strategyID,
 				"race_id":     race.ID,
 				"error":       err.Error(),
@@ -366,18 +1011,18 @@ func (o *Orchestrator) evaluateStrategies(ctx context.Context, race *models.Race
 
 		// Log strategy evaluation with dedicated logger
 		if o.strategyLogger != nil {
-			o.strategyLogger.WithFields(logrus.Fields{
+			o.strategyLogger.WithContext(ctx).WithFields(logrus.Fields{
 				"strategy_id":       strategyID.String(),
 				"race_id":           race.ID.String(),
-				"market_id":         race.MarketID,
+				"market_id":         marketIDOf(stratCtx.Runners),
 				"signals_generated": len(stratSignals),
 				"duration_ms":       duration.Milliseconds(),
-				"timestamp":         time.Now().Unix(),
+				"timestamp":         now.Unix(),
 			}).Info("Strategy evaluation completed")
 
 			// Log each strategy decision
 			for _, sig := range stratSignals {
-				o.strategyLogger.WithFields(logrus.Fields{
+				o.strategyLogger.WithContext(ctx).WithFields(logrus.Fields{
 					"strategy_id": strategyID.String(),
 					"race_id":     race.ID.String(),
 					"runner_id":   sig.RunnerID.String(),
@@ -385,34 +1030,460 @@ func (o *Orchestrator) evaluateStrategies(ctx context.Context, race *models.Race
 					"odds":        sig.Odds,
 					"stake":       sig.Stake,
 					"confidence":  sig.Confidence,
-					"timestamp":   time.Now().Unix(),
+					"timestamp":   now.Unix(),
 				}).Info("Strategy decision made")
 			}
 		}
 
-		// Wrap signals with context
-		for _, sig := range stratSignals {
-			signals = append(signals, SignalWithContext{
-				Signal:      sig,
-				StrategyID:  strategyID,
-				RaceID:      race.ID,
-				MarketID:    race.MarketID,
-				SelectionID: sig.SelectionID,
-			})
+		signals = append(signals, withContext(stratSignals, strategyID, race, selections)...)
+	}
+
+	if market != nil {
+		for i := range signals {
+			signals[i].InPlay = true
+			signals[i].BetDelay = market.BetDelay
 		}
 	}
 
 	return signals, nil
 }
 
+// RaceBundleSource loads a race with its runners and odds history in one query
+type RaceBundleSource interface {
+	GetRaceBundle(ctx context.Context, raceID uuid.UUID, oddsSince time.Time) (*models.RaceBundle, error)
+}
+
+// strategyContext loads a race's runners and recent odds for evaluation, with
+// the Betfair IDs recorded at ingestion that are needed to place live orders
+func (o *Orchestrator) strategyContext(ctx context.Context, race *models.Race, now time.Time) (strategy.Context, map[uuid.UUID]models.BetfairSelection, error) {
+	runners, odds, err := o.raceData(ctx, race, now)
+	if err != nil {
+		return strategy.Context{}, nil, err
+	}
+
+	selections := make(map[uuid.UUID]models.BetfairSelection, len(runners))
+	for _, runner := range runners {
+		if selection, ok := runner.BetfairSelection(); ok {
+			selections[runner.ID] = selection
+		}
+	}
+
+	var preference models.OddsSourcePreference
+	if o.config != nil {
+		preference = o.config.Trading.OddsSourcePreference
+	}
+	odds = preference.Prefer(odds)
+
+	return strategy.Context{
+		Race:            race,
+		Runners:         runners,
+		OddsHistory:     odds,
+		CurrentTime:     now,
+		IncludeReserves: o.config != nil && o.config.Trading.IncludeReserveRunners,
+	}, selections, nil
+}
+
+// raceData loads a race's runners and its odds over the lookback to now.
+// Before the off both come from one race bundle query. The bundle runs to the
+// off, so odds recorded after now, as when replaying a past day, are dropped.
+func (o *Orchestrator) raceData(ctx context.Context, race *models.Race, now time.Time) ([]*models.Runner, []*models.OddsSnapshot, error) {
+	since := now.Add(-oddsHistoryLookback)
+
+	if o.raceBundles != nil && now.Before(race.ScheduledStart) {
+		bundle, err := o.raceBundles.GetRaceBundle(ctx, race.ID, since)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to load runners and odds: %w", err)
+		}
+		odds := make([]*models.OddsSnapshot, 0, len(bundle.Odds))
+		for _, snapshot := range bundle.Odds {
+			if !snapshot.Time.Before(since) && !snapshot.Time.After(now) {
+				odds = append(odds, snapshot)
+			}
+		}
+		return bundle.Runners, odds, nil
+	}
+
+	runners, err := o.runnerRepo.GetByRaceID(ctx, race.ID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load runners: %w", err)
+	}
+	odds, err := o.oddsRepo.GetByRaceID(ctx, race.ID, since, now)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load odds: %w", err)
+	}
+	return runners, odds, nil
+}
+
+// withContext wraps a strategy's signals with the race and exchange IDs
+func withContext(stratSignals []strategy.Signal, strategyID uuid.UUID, race *models.Race, selections map[uuid.UUID]models.BetfairSelection) []SignalWithContext {
+	signals := make([]SignalWithContext, 0, len(stratSignals))
+	for _, sig := range stratSignals {
+		selection := selections[sig.RunnerID]
+		signals = append(signals, SignalWithContext{
+			Signal:      sig,
+			StrategyID:  strategyID,
+			RaceID:      race.ID,
+			MarketID:    selection.MarketID,
+			SelectionID: selection.SelectionID,
+			OffTime:     race.ScheduledStart,
+		})
+	}
+	return signals
+}
+
+// evaluateWithTimeout runs a strategy's evaluation, giving up once the
+// evaluation timeout passes. The strategy's context is cancelled so it can
+// stop early; one that ignores it is left to finish in the background and
+// its signals are discarded.
+func (o *Orchestrator) evaluateWithTimeout(ctx context.Context, strat strategy.Strategy, stratCtx strategy.Context) ([]strategy.Signal, error) {
+	if o.evalTimeout <= 0 {
+		return strat.Evaluate(ctx, stratCtx)
+	}
+
+	evalCtx, cancel := context.WithTimeout(ctx, o.evalTimeout)
+	defer cancel()
+
+	type evaluation struct {
+		signals []strategy.Signal
+		err     error
+	}
+	done := make(chan evaluation, 1)
+	go func() {
+		signals, err := strat.Evaluate(evalCtx, stratCtx)
+		done <- evaluation{signals: signals, err: err}
+	}()
+
+	select {
+	case result := <-done:
+		if result.err != nil && errors.Is(evalCtx.Err(), context.DeadlineExceeded) && ctx.Err() == nil {
+			return nil, ErrStrategyTimeout
+		}
+		return result.signals, result.err
+	case <-evalCtx.Done():
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, ErrStrategyTimeout
+	}
+}
+
+// applyStakingPlans re-sizes signals from strategies that declare a staking
+// plan and drops those sized to zero. Other signals keep their own stake.
+func (o *Orchestrator) applyStakingPlans(ctx context.Context, signals []SignalWithContext, now time.Time) []SignalWithContext {
+	o.mu.RLock()
+	plans := make(map[uuid.UUID]strategy.StakingPlan, len(o.stakingPlans))
+	for id, plan := range o.stakingPlans {
+		plans[id] = plan
+	}
+	o.mu.RUnlock()
+
+	if len(plans) == 0 {
+		return signals
+	}
+
+	bankroll, bankrollErr := o.currentBankroll(ctx)
+	if bankrollErr != nil {
+		o.logger.WithContext(ctx).WithError(bankrollErr).Warn("Failed to get bankroll, skipping signals with staking plans")
+	}
+
+	sized := make([]SignalWithContext, 0, len(signals))
+	for _, sc := range signals {
+		plan, ok := plans[sc.StrategyID]
+		if !ok {
+			sized = append(sized, sc)
+			continue
+		}
+		if bankrollErr != nil {
+			continue
+		}
+
+		stake, err := o.riskManager.SizeStake(ctx, plan, sc.Signal, sc.StrategyID, bankroll, now)
+		if err != nil {
+			o.logger.WithContext(ctx).WithFields(logrus.Fields{
+				"strategy_id": sc.StrategyID,
+				"runner_id":   sc.Signal.RunnerID,
+				"error":       err.Error(),
+			}).Warn("Failed to size stake, skipping signal")
+			continue
+		}
+		if stake <= 0 {
+			continue
+		}
+		sc.Signal.Stake = stake
+		sized = append(sized, sc)
+	}
+	return sized
+}
+
+// applyRecoveryRamp scales stakes down while the circuit breaker's recovery
+// ramp is running
+func (o *Orchestrator) applyRecoveryRamp(ctx context.Context, signals []SignalWithContext) []SignalWithContext {
+	if o.recoveryRamp == nil {
+		return signals
+	}
+	multiplier := o.recoveryRamp.Multiplier()
+	if multiplier >= 1 {
+		return signals
+	}
+
+	o.logger.WithContext(ctx).WithFields(logrus.Fields{
+		"multiplier": multiplier,
+		"signals":    len(signals),
+	}).Info("Circuit breaker recovery: stakes scaled down")
+	for i := range signals {
+		signals[i].Signal.Stake *= multiplier
+	}
+	return signals
+}
+
+// ML filter modes for trading.ml_filter_mode, deciding what happens when the
+// model favours the other side of a signal
+const (
+	// MLFilterVeto drops signals the model disagrees with
+	MLFilterVeto = "veto"
+	// MLFilterOverride flips signals to the side the model favours
+	MLFilterOverride = "override"
+	// MLFilterAdvisory only logs predictions and never changes signals
+	MLFilterAdvisory = "advisory"
+	// MLFilterObserve never changes signals either, but also stores every
+	// prediction and records divergence metrics against the strategies
+	MLFilterObserve = "observe"
+)
+
+// ML failure policies for trading.ml_failure_policy, deciding what happens
+// to signals the model could not score because the ML service failed or was
+// bypassed for exceeding its latency budget
+const (
+	// MLFailOpen gates unscored signals on strategy confidence alone
+	MLFailOpen = "open"
+	// MLFailClosed drops unscored signals
+	MLFailClosed = "closed"
+)
+
 // filterSignalsWithML uses ML predictions to filter/rank signals
 func (o *Orchestrator) filterSignalsWithML(ctx context.Context, signals []SignalWithContext) ([]SignalWithContext, error) {
-	// TODO: Implement ML filtering logic
-	// For now, return all signals
-	return signals, nil
+	predictions := make(map[int]*ml.PredictionResult, len(signals))
+	var lastErr error
+
+	for i, sc := range signals {
+		prediction, err := o.mlClient.GetPrediction(ctx, sc.RaceID, sc.Signal.RunnerID, sc.StrategyID, signalFeatures(sc.Signal), "")
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		predictions[i] = prediction
+	}
+	total, unscored := len(signals), len(signals)-len(predictions)
+
+	// Failing closed, signals the model could not score are not placed
+	if unscored > 0 && o.mlFailurePolicy == MLFailClosed && gatesSignals(o.mlFilterMode) {
+		signals, predictions = dropUnscored(signals, predictions)
+	}
+
+	filtered, disagreements := applyMLFilter(o.mlFilterMode, o.edgeGate, signals, predictions)
+
+	if o.mlFilterMode == MLFilterObserve {
+		o.recordPredictions(ctx, signals, predictions)
+	}
+
+	if o.mlLogger != nil {
+		o.mlLogger.WithFields(logrus.Fields{
+			"mode":           mlFilterModeOrDefault(o.mlFilterMode),
+			"signals_in":     total,
+			"signals_out":    len(filtered),
+			"predictions":    len(predictions),
+			"disagreements":  disagreements,
+			"min_edge":       o.edgeGate.MinEdge,
+			"min_confidence": o.edgeGate.MinConfidence,
+		}).Info("Signals filtered with ML predictions")
+	}
+
+	if lastErr != nil {
+		return filtered, fmt.Errorf("failed to get ML prediction for %d of %d signals: %w", unscored, total, lastErr)
+	}
+	return filtered, nil
+}
+
+// dropUnscored keeps the signals that have a prediction, re-keying the
+// predictions by their new positions
+func dropUnscored(signals []SignalWithContext, predictions map[int]*ml.PredictionResult) ([]SignalWithContext, map[int]*ml.PredictionResult) {
+	scored := make([]SignalWithContext, 0, len(predictions))
+	rekeyed := make(map[int]*ml.PredictionResult, len(predictions))
+	for i, sc := range signals {
+		prediction, ok := predictions[i]
+		if !ok {
+			continue
+		}
+		rekeyed[len(scored)] = prediction
+		scored = append(scored, sc)
+	}
+	return scored, rekeyed
+}
+
+// recordPredictions stores observed predictions and their divergence
+// metrics. Failures are logged and never hold up the signals.
+func (o *Orchestrator) recordPredictions(ctx context.Context, signals []SignalWithContext, predictions map[int]*ml.PredictionResult) {
+	if o.mlRecorder == nil {
+		o.logger.WithContext(ctx).Warn("ML observe mode has no prediction repository, predictions not stored")
+		return
+	}
+	if err := o.mlRecorder.Record(ctx, signals, predictions, o.now()); err != nil {
+		o.logger.WithContext(ctx).WithError(err).Warn("Failed to record observed ML predictions")
+	}
+}
+
+// applyMLFilter resolves side disagreements between signals and the model
+// according to mode, then applies the edge gate using the ML probability.
+// Advisory and observe modes return the signals unchanged. It also returns
+// how many signals the model disagreed with.
+func applyMLFilter(mode string, gate strategy.EdgeGate, signals []SignalWithContext, predictions map[int]*ml.PredictionResult) ([]SignalWithContext, int) {
+	mode = mlFilterModeOrDefault(mode)
+	resolved := make([]SignalWithContext, 0, len(signals))
+	probabilities := make(map[int]float64, len(predictions))
+	disagreements := 0
+
+	for i, sc := range signals {
+		prediction := predictions[i]
+		side, ok := modelSide(prediction, sc.Signal.Odds)
+		if ok && side != signalSide(sc.Signal) {
+			disagreements++
+			switch mode {
+			case MLFilterVeto:
+				continue
+			case MLFilterOverride:
+				sc.Signal.Side = side
+			}
+		}
+		if prediction != nil {
+			probabilities[len(resolved)] = prediction.Probability
+			// Record the probability the bet was gated on; advisory and
+			// observe gate nothing
+			if gatesSignals(mode) {
+				probability := prediction.Probability
+				sc.Signal.ModelProbability = &probability
+			}
+		}
+		resolved = append(resolved, sc)
+	}
+
+	if !gatesSignals(mode) {
+		return resolved, disagreements
+	}
+	return filterSignalsByEdge(gate, resolved, probabilities), disagreements
+}
+
+// gatesSignals reports whether predictions may drop or change signals in mode
+func gatesSignals(mode string) bool {
+	return mode != MLFilterAdvisory && mode != MLFilterObserve
+}
+
+// mlFilterModeOrDefault treats an unset mode as veto
+func mlFilterModeOrDefault(mode string) string {
+	if mode == "" {
+		return MLFilterVeto
+	}
+	return mode
+}
+
+// modelSide returns the side the model favours for a runner at odds. An
+// explicit "back" or "lay" recommendation wins; otherwise the model favours
+// backing when its probability beats the price's implied probability. It
+// reports false when there is no prediction or the model is indifferent.
+func modelSide(prediction *ml.PredictionResult, odds float64) (models.BetSide, bool) {
+	if prediction == nil {
+		return "", false
+	}
+	switch strings.ToLower(strings.TrimSpace(prediction.Recommendation)) {
+	case "back":
+		return models.BetSideBack, true
+	case "lay":
+		return models.BetSideLay, true
+	}
+	if odds <= 1.0 {
+		return "", false
+	}
+	implied := 1.0 / odds
+	switch {
+	case prediction.Probability > implied:
+		return models.BetSideBack, true
+	case prediction.Probability < implied:
+		return models.BetSideLay, true
+	}
+	return "", false
 }
 
-// loadActiveStrategies loads active strategies from database and instantiates them
+// signalSide returns a signal's side, treating an unset side as back as the executor does
+func signalSide(sig strategy.Signal) models.BetSide {
+	if sig.Side == "" {
+		return models.BetSideBack
+	}
+	return sig.Side
+}
+
+// filterSignalsByEdge applies the shared edge gate to each signal. The ML
+// probability is used where available, otherwise the strategy's own
+// confidence, so the decision matches what the strategy would make itself.
+// A lay is gated as a back on the runner losing, at the equivalent odds.
+func filterSignalsByEdge(gate strategy.EdgeGate, signals []SignalWithContext, probabilities map[int]float64) []SignalWithContext {
+	filtered := make([]SignalWithContext, 0, len(signals))
+	for i, sc := range signals {
+		probability, ok := probabilities[i]
+		if !ok {
+			probability = sc.Signal.Confidence
+		}
+		odds := sc.Signal.Odds
+		if sc.Signal.Side == models.BetSideLay && odds > 1.0 {
+			probability, odds = 1-probability, odds/(odds-1)
+		}
+		// Bands follow the quoted price, not the equivalent back odds of a lay
+		if gate.ForOdds(sc.Signal.Odds).Accept(probability, odds) {
+			filtered = append(filtered, sc)
+		}
+	}
+	return filtered
+}
+
+// signalFeatures builds the feature vector sent to the ML service for a signal
+func signalFeatures(sig strategy.Signal) []float64 {
+	return []float64{sig.Odds, sig.Confidence, sig.ExpectedValue}
+}
+
+// refreshStrategies reloads the active strategies once the reload interval
+// has passed since the last successful load. A failed reload keeps the
+// previous set. Returns false when trading must halt because the set is
+// older than the configured staleness bound, or was never loaded.
+func (o *Orchestrator) refreshStrategies(ctx context.Context, now time.Time) bool {
+	o.mu.RLock()
+	loadedAt := o.strategiesAt
+	active := len(o.activeStrategies)
+	o.mu.RUnlock()
+
+	interval := time.Duration(o.config.Bot.StrategyReloadInterval) * time.Second
+	if loadedAt.IsZero() || (interval > 0 && now.Sub(loadedAt) >= interval) {
+		err := o.loadActiveStrategies(ctx)
+		if err == nil {
+			return true
+		}
+		o.logger.WithError(err).WithFields(logrus.Fields{
+			"active_strategies": active,
+			"loaded_at":         loadedAt,
+		}).Warn("Failed to reload strategies, keeping last-known-good set")
+	}
+
+	maxStaleness := time.Duration(o.config.Bot.StrategyMaxStaleness) * time.Second
+	if loadedAt.IsZero() || (maxStaleness > 0 && now.Sub(loadedAt) > maxStaleness) {
+		o.logger.WithFields(logrus.Fields{
+			"loaded_at":     loadedAt,
+			"max_staleness": maxStaleness,
+		}).Warn("Trading halted: active strategies are stale")
+		return false
+	}
+	return true
+}
+
+// loadActiveStrategies loads active strategies from database and instantiates
+// them. Strategies of unknown type are skipped and collected for
+// UnknownStrategyTypes rather than failing the load.
 func (o *Orchestrator) loadActiveStrategies(ctx context.Context) error {
 	strategies, err := o.strategyRepo.GetAll(ctx)
 	if err != nil {
@@ -422,24 +1493,49 @@ func (o *Orchestrator) loadActiveStrategies(ctx context.Context) error {
 	o.mu.Lock()
 	defer o.mu.Unlock()
 
+	o.strategiesAt = clockOrReal(o.clock).Now()
 	o.activeStrategies = make(map[uuid.UUID]strategy.Strategy)
+	o.inPlayStrategies = make(map[uuid.UUID]bool)
+	o.stakingPlans = make(map[uuid.UUID]strategy.StakingPlan)
+	o.strategyShares = make(map[uuid.UUID]float64)
+	metrics.ResetUnknownStrategyTypes()
+	var unknown []error
 
 	for _, stratModel := range strategies {
-		if !stratModel.IsActive {
+		if !stratModel.IsActive || stratModel.IsArchived() {
 			continue
 		}
 
-		// Instantiate strategy based on type
-		var strat strategy.Strategy
-		switch stratModel.Type {
-		case "simple_value":
-			strat = strategy.NewSimpleValueStrategy(o.logger)
-		default:
-			o.logger.WithField("strategy_type", stratModel.Type).Warn("Unknown strategy type, skipping")
+		strat, err := o.newStrategy(stratModel)
+		if err != nil {
+			o.logger.WithFields(logrus.Fields{
+				"strategy_id":   stratModel.ID,
+				"strategy_name": stratModel.Name,
+				"strategy_type": stratModel.Type,
+			}).Warn("Unknown strategy type, skipping")
+			metrics.RecordUnknownStrategyType(stratModel.ID.String(), stratModel.Name, stratModel.Type)
+			unknown = append(unknown, err)
 			continue
 		}
 
 		o.activeStrategies[stratModel.ID] = strat
+		if tradesInPlay(stratModel) {
+			o.inPlayStrategies[stratModel.ID] = true
+		}
+		o.strategyShares[stratModel.ID] = allocationWeight(o.config.Trading.BankrollAllocation, stratModel.Name)
+		if o.accountRouter != nil {
+			o.assignStrategyAccount(stratModel)
+		}
+
+		plan, err := stakingPlanFor(stratModel, strat)
+		if err != nil {
+			o.logger.WithFields(logrus.Fields{
+				"strategy_id": stratModel.ID,
+				"error":       err.Error(),
+			}).Warn("Invalid staking plan, using strategy stakes")
+		} else if plan != nil {
+			o.stakingPlans[stratModel.ID] = plan
+		}
 
 		o.logger.WithFields(logrus.Fields{
 			"strategy_id":   stratModel.ID,
@@ -448,22 +1544,158 @@ func (o *Orchestrator) loadActiveStrategies(ctx context.Context) error {
 		}).Info("Active strategy loaded")
 	}
 
+	o.unknownTypes = errors.Join(unknown...)
 	return nil
 }
 
+// newStrategy instantiates a stored strategy by its type, returning an
+// UnknownStrategyTypeError for types the bot cannot run
+func (o *Orchestrator) newStrategy(stratModel *models.Strategy) (strategy.Strategy, error) {
+	switch stratModel.Type {
+	case "simple_value":
+		value := strategy.NewSimpleValueStrategy(o.logger)
+		value.OddsBands = newOddsBands(o.config.Trading.OddsBands)
+		return value, nil
+	default:
+		return nil, &UnknownStrategyTypeError{
+			StrategyID:   stratModel.ID,
+			StrategyName: stratModel.Name,
+			Type:         stratModel.Type,
+		}
+	}
+}
+
+// UnknownStrategyTypes returns an error joining an UnknownStrategyTypeError
+// for each active strategy skipped on the last load, or nil if there were none
+func (o *Orchestrator) UnknownStrategyTypes() error {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	return o.unknownTypes
+}
+
+// countJoined counts the errors joined into err
+func countJoined(err error) int {
+	if err == nil {
+		return 0
+	}
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		return len(joined.Unwrap())
+	}
+	return 1
+}
+
+// stakingPlanFor resolves the staking plan from the stored strategy
+// parameters, falling back to the parameters the strategy reports itself
+func stakingPlanFor(stratModel *models.Strategy, strat strategy.Strategy) (strategy.StakingPlan, error) {
+	params := strat.GetParameters()
+	if len(stratModel.Parameters) > 0 {
+		stored := make(map[string]interface{})
+		if err := json.Unmarshal(stratModel.Parameters, &stored); err != nil {
+			return nil, fmt.Errorf("failed to parse strategy parameters: %w", err)
+		}
+		if _, ok := stored["staking_plan"]; ok {
+			params = stored
+		}
+	}
+	return strategy.StakingPlanFromParameters(params)
+}
+
+// allocationWeight is a strategy's configured bankroll share, defaulting to 1
+func allocationWeight(cfg config.BankrollAllocationConfig, name string) float64 {
+	if weight, ok := cfg.Shares[name]; ok {
+		return weight
+	}
+	return 1
+}
+
+// assignStrategyAccount routes a strategy to the first account that lists it
+func (o *Orchestrator) assignStrategyAccount(stratModel *models.Strategy) {
+	for _, account := range o.config.Betfair.Accounts {
+		for _, name := range account.Strategies {
+			if name == stratModel.Name {
+				o.accountRouter.AssignStrategy(stratModel.ID, account.Name)
+				return
+			}
+		}
+	}
+}
+
 // GetStatus returns current orchestrator status
 func (o *Orchestrator) GetStatus() *OrchestratorStatus {
 	o.mu.RLock()
 	defer o.mu.RUnlock()
 
+	riskMetrics := o.riskManager.GetRiskMetrics()
+	riskMetrics.Portfolio = o.monitor.PortfolioRisk()
+
 	return &OrchestratorStatus{
-		Running:             o.running,
-		PaperTradingMode:    o.config.Features.PaperTradingEnabled,
-		ActiveStrategies:    len(o.activeStrategies),
-		CircuitBreakerState: o.circuitBreaker.GetState(),
-		RiskMetrics:         o.riskManager.GetRiskMetrics(),
-		MonitorMetrics:      *o.monitor.metrics,
-		ExecutorMetrics:     o.executor.GetMetrics(),
-		LastUpdate:          time.Now(),
+		Running:              o.running,
+		PaperTradingMode:     o.config.Features.PaperTradingEnabled,
+		ActiveStrategies:     len(o.activeStrategies),
+		PausedStrategies:     len(o.pausedStrategies),
+		CircuitBreakerState:  o.circuitBreaker.GetState(),
+		CircuitBreakerEvents: o.circuitBreaker.GetEvents(),
+		RiskMetrics:          riskMetrics,
+		MonitorMetrics:       *o.monitor.metrics,
+		ExecutorMetrics:      o.executor.GetMetrics(),
+		WarmingUp:            clockOrReal(o.clock).Now().Before(o.warmUpUntil),
+		StrategiesLoadedAt:   o.strategiesAt,
+		KillSwitchEngaged:    o.killSwitchSource != "",
+		KillSwitchSource:     o.killSwitchSource,
+		KillSwitchEngagedAt:  o.killSwitchAt,
+		MaintenanceUntil:     o.maintenanceUntil,
+		UnknownStrategyTypes: countJoined(o.unknownTypes),
+		LastUpdate:           clockOrReal(o.clock).Now(),
 	}
 }
+
+// publishStatus exports the orchestrator's status as Prometheus gauges
+func (o *Orchestrator) publishStatus() {
+	status := o.GetStatus()
+	risk := status.RiskMetrics
+	metrics.UpdateBotStatus(metrics.BotStatus{
+		Running:             status.Running,
+		ActiveStrategies:    status.ActiveStrategies,
+		PausedStrategies:    status.PausedStrategies,
+		CircuitBreakerState: circuitStateGaugeValue(status.CircuitBreakerState),
+		Exposure:            risk.CurrentExposure,
+		ReservedExposure:    risk.ReservedExposure,
+		RemainingCapacity:   risk.RemainingCapacity,
+		MaxExposure:         risk.MaxExposure,
+		DailyLoss:           risk.DailyLoss,
+		MaxDailyLoss:        risk.MaxDailyLoss,
+		KillSwitchEngaged:   status.KillSwitchEngaged,
+		WarmingUp:           status.WarmingUp,
+		MaintenancePaused:   status.MaintenanceUntil.After(status.LastUpdate),
+	})
+}
+
+// circuitStateGaugeValue maps a circuit state to the value documented on
+// the circuit breaker state gauge
+func circuitStateGaugeValue(state CircuitState) float64 {
+	switch state {
+	case CircuitHalfOpen:
+		return 1
+	case CircuitOpen:
+		return 2
+	default:
+		return 0
+	}
+}
+
+// newOddsBands converts configured odds bands for the edge gate
+func newOddsBands(cfg []config.OddsBandConfig) []strategy.OddsBand {
+	if len(cfg) == 0 {
+		return nil
+	}
+	bands := make([]strategy.OddsBand, 0, len(cfg))
+	for _, band := range cfg {
+		bands = append(bands, strategy.OddsBand(band))
+	}
+	return bands
+}
+
+// newMarketFilter builds the trading market filter from configuration
+func newMarketFilter(cfg config.MarketFilterConfig) *strategy.MarketFilter {
+	return strategy.NewMarketFilter(strategy.MarketRules(cfg.Allow), strategy.MarketRules(cfg.Deny))
+}
@@ -0,0 +1,689 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/clever-better/internal/config"
+	"github.com/yourusername/clever-better/internal/metrics"
+	"github.com/yourusername/clever-better/internal/ml"
+	"github.com/yourusername/clever-better/internal/models"
+	"github.com/yourusername/clever-better/internal/repository"
+	"github.com/yourusername/clever-better/internal/strategy"
+)
+
+// TestFilterSignalsByEdgeMatchesStrategyGate asserts the live filter makes the
+// same accept/reject decisions as the edge gate used by strategies in backtests.
+func TestFilterSignalsByEdgeMatchesStrategyGate(t *testing.T) {
+	strat := strategy.NewSimpleValueStrategy()
+	strat.MinEdgeThreshold = 0.1
+	strat.MinConfidence = 0.3
+	gate := strat.EdgeGate()
+
+	inputs := []struct {
+		probability float64
+		odds        float64
+	}{
+		{probability: 0.30, odds: 4.0},
+		{probability: 0.25, odds: 4.0},
+		{probability: 0.275, odds: 4.0},
+		{probability: 0.60, odds: 2.0},
+		{probability: 0.54, odds: 2.0},
+	}
+
+	signals := make([]SignalWithContext, 0, len(inputs))
+	probabilities := make(map[int]float64, len(inputs))
+	for i, in := range inputs {
+		signals = append(signals, SignalWithContext{
+			Signal: strategy.Signal{
+				RunnerID:   uuid.New(),
+				Side:       models.BetSideBack,
+				Odds:       in.odds,
+				Stake:      5,
+				Confidence: in.probability,
+			},
+			StrategyID: uuid.New(),
+			RaceID:     uuid.New(),
+		})
+		probabilities[i] = in.probability
+	}
+
+	withML := filterSignalsByEdge(gate, signals, probabilities)
+	withoutML := filterSignalsByEdge(gate, signals, map[int]float64{})
+
+	accepted := make(map[uuid.UUID]bool)
+	for _, sc := range withML {
+		accepted[sc.Signal.RunnerID] = true
+	}
+	for i, in := range inputs {
+		assert.Equal(t, gate.Accept(in.probability, in.odds), accepted[signals[i].Signal.RunnerID], "input %d", i)
+	}
+	assert.Equal(t, withML, withoutML, "falling back to signal confidence should match the gate")
+	assert.Len(t, withML, 2)
+}
+
+// TestApplyMLFilterModes tests each mode on a signal the model disagrees with
+func TestApplyMLFilterModes(t *testing.T) {
+	gate := strategy.NewEdgeGate(0.1, 0.3)
+	newSignal := func(side models.BetSide, odds float64) SignalWithContext {
+		return SignalWithContext{
+			Signal:     strategy.Signal{RunnerID: uuid.New(), Side: side, Odds: odds, Stake: 5, Confidence: 0.4},
+			StrategyID: uuid.New(),
+			RaceID:     uuid.New(),
+		}
+	}
+
+	// Backed at 4.0 (25% implied) but the model gives 15%, so it favours laying
+	disagreeing := newSignal(models.BetSideBack, 4.0)
+	// Backed at 4.0 and the model gives 40%, so it agrees
+	agreeing := newSignal(models.BetSideBack, 4.0)
+	signals := []SignalWithContext{disagreeing, agreeing}
+	predictions := map[int]*ml.PredictionResult{
+		0: {Probability: 0.15},
+		1: {Probability: 0.40},
+	}
+
+	vetoed, disagreements := applyMLFilter(MLFilterVeto, gate, signals, predictions)
+	assert.Equal(t, 1, disagreements)
+	require.Len(t, vetoed, 1)
+	assert.Equal(t, agreeing.Signal.RunnerID, vetoed[0].Signal.RunnerID)
+	assert.Equal(t, models.BetSideBack, vetoed[0].Signal.Side, "veto never changes side")
+	require.NotNil(t, vetoed[0].Signal.ModelProbability, "the gating probability is kept for the bet record")
+	assert.Equal(t, 0.40, *vetoed[0].Signal.ModelProbability)
+
+	defaulted, _ := applyMLFilter("", gate, signals, predictions)
+	assert.Equal(t, vetoed, defaulted, "an unset mode vetoes")
+
+	overridden, disagreements := applyMLFilter(MLFilterOverride, gate, signals, predictions)
+	assert.Equal(t, 1, disagreements)
+	require.Len(t, overridden, 2)
+	assert.Equal(t, disagreeing.Signal.RunnerID, overridden[0].Signal.RunnerID)
+	assert.Equal(t, models.BetSideLay, overridden[0].Signal.Side, "override flips to the model's side")
+	assert.Equal(t, models.BetSideBack, overridden[1].Signal.Side)
+	assert.Equal(t, models.BetSideBack, signals[0].Signal.Side, "input signals are not modified")
+
+	advised, disagreements := applyMLFilter(MLFilterAdvisory, gate, signals, predictions)
+	assert.Equal(t, 1, disagreements)
+	assert.Equal(t, signals, advised, "advisory never affects bets")
+
+	observed, disagreements := applyMLFilter(MLFilterObserve, gate, signals, predictions)
+	assert.Equal(t, 1, disagreements)
+	assert.Equal(t, signals, observed, "observe never affects bets")
+}
+
+// TestModelSide tests how the model's preferred side is derived
+func TestModelSide(t *testing.T) {
+	side, ok := modelSide(&ml.PredictionResult{Probability: 0.4}, 4.0)
+	assert.True(t, ok)
+	assert.Equal(t, models.BetSideBack, side)
+
+	side, ok = modelSide(&ml.PredictionResult{Probability: 0.1}, 4.0)
+	assert.True(t, ok)
+	assert.Equal(t, models.BetSideLay, side)
+
+	side, ok = modelSide(&ml.PredictionResult{Probability: 0.4, Recommendation: "LAY"}, 4.0)
+	assert.True(t, ok)
+	assert.Equal(t, models.BetSideLay, side, "an explicit recommendation wins over the price")
+
+	_, ok = modelSide(&ml.PredictionResult{Probability: 0.25}, 4.0)
+	assert.False(t, ok, "indifferent at the implied probability")
+
+	_, ok = modelSide(nil, 4.0)
+	assert.False(t, ok)
+}
+
+func TestProcessRaceSkipsFilteredMarkets(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	banned := &models.Race{ID: uuid.New(), Track: "Towcester", Grade: "A3", Status: "scheduled"}
+	outsideAllow := &models.Race{ID: uuid.New(), Track: "Hove", Grade: "D2", Status: "scheduled"}
+	allowed := &models.Race{ID: uuid.New(), Track: "Romford", Grade: "A3", Status: "scheduled"}
+
+	counter := &countingStrategy{}
+	betRepo := new(MockBetRepository)
+	riskManager := NewRiskManager(&config.TradingConfig{MaxStakePerBet: 100, MaxExposure: 500, MaxDailyLoss: 200}, betRepo, logger)
+
+	orchestrator := &Orchestrator{
+		config:           &config.Config{},
+		runnerRepo:       &replayRunnerRepo{},
+		oddsRepo:         &replayOddsRepo{},
+		betRepo:          betRepo,
+		riskManager:      riskManager,
+		executor:         NewExecutor(nil, betRepo, riskManager, true, false, logger, nil),
+		activeStrategies: map[uuid.UUID]strategy.Strategy{uuid.New(): counter},
+		marketFilter: newMarketFilter(config.MarketFilterConfig{
+			Allow: config.MarketRulesConfig{Grades: []string{"A*"}},
+			Deny:  config.MarketRulesConfig{Venues: []string{"towcester"}},
+		}),
+		logger: logger,
+	}
+
+	now := time.Now()
+	for _, race := range []*models.Race{banned, outsideAllow, allowed} {
+		bets, err := orchestrator.processRace(context.Background(), race, now)
+		require.NoError(t, err)
+		assert.Empty(t, bets)
+	}
+
+	assert.Equal(t, []uuid.UUID{allowed.ID}, counter.evaluated)
+}
+
+func TestProcessRaceWarmUpDefersPlacement(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	start := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	clock := NewMockClock(start)
+
+	race := &models.Race{ID: uuid.New(), ScheduledStart: start.Add(time.Hour), Track: "Romford", Status: "scheduled"}
+	fav := &models.Runner{ID: uuid.New(), RaceID: race.ID, TrapNumber: 1, Name: "Fav"}
+	price := 3.0
+	odds := &replayOddsRepo{odds: map[uuid.UUID][]*models.OddsSnapshot{
+		race.ID: {{Time: start.Add(-time.Minute), RaceID: race.ID, RunnerID: fav.ID, BackPrice: &price}},
+	}}
+
+	betRepo := new(MockBetRepository)
+	betRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+	cfg := &config.Config{
+		Trading: config.TradingConfig{MaxStakePerBet: 100, MaxExposure: 500, MaxDailyLoss: 200},
+		Bot:     config.BotConfig{WarmUpSeconds: 300},
+	}
+	riskManager := NewRiskManager(&cfg.Trading, betRepo, logger)
+
+	orchestrator := &Orchestrator{
+		config:           cfg,
+		runnerRepo:       &replayRunnerRepo{runners: map[uuid.UUID][]*models.Runner{race.ID: {fav}}},
+		oddsRepo:         odds,
+		betRepo:          betRepo,
+		riskManager:      riskManager,
+		executor:         NewExecutor(nil, betRepo, riskManager, true, false, logger, nil),
+		monitor:          NewMonitor(betRepo, nil, nil, nil, 1000, time.Minute, logger),
+		activeStrategies: map[uuid.UUID]strategy.Strategy{uuid.New(): &favouriteBackStrategy{}},
+		circuitBreaker: NewCircuitBreaker(CircuitBreakerConfig{
+			MaxConsecutiveLosses: 5,
+			MaxDrawdownPercent:   0.5,
+			MaxFailureCount:      5,
+			FailureTimeWindow:    time.Minute,
+			CooldownPeriod:       time.Minute,
+		}, logger),
+		logger: logger,
+	}
+	orchestrator.SetClock(clock)
+	orchestrator.beginWarmUp()
+	assert.True(t, orchestrator.GetStatus().WarmingUp)
+
+	clock.Advance(4 * time.Minute)
+	bets, err := orchestrator.processRace(context.Background(), race, clock.Now())
+	require.NoError(t, err)
+	assert.Empty(t, bets, "signals are only logged during warm-up")
+	betRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+
+	clock.Advance(time.Minute)
+	assert.False(t, orchestrator.GetStatus().WarmingUp)
+	bets, err = orchestrator.processRace(context.Background(), race, clock.Now())
+	require.NoError(t, err)
+	require.Len(t, bets, 1)
+	assert.Equal(t, fav.ID, bets[0].RunnerID)
+	betRepo.AssertNumberOfCalls(t, "Create", 1)
+}
+
+func TestProcessRaceObserveModeRecordsWithoutAffectingBets(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	race := &models.Race{ID: uuid.New(), ScheduledStart: now.Add(time.Hour), Track: "Romford", Status: "scheduled"}
+	fav := &models.Runner{ID: uuid.New(), RaceID: race.ID, TrapNumber: 1, Name: "Fav"}
+	price := 3.0
+	odds := &replayOddsRepo{odds: map[uuid.UUID][]*models.OddsSnapshot{
+		race.ID: {{Time: now.Add(-time.Minute), RaceID: race.ID, RunnerID: fav.ID, BackPrice: &price}},
+	}}
+
+	betRepo := new(MockBetRepository)
+	betRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+	cfg := &config.Config{
+		Trading:  config.TradingConfig{MaxStakePerBet: 100, MaxExposure: 500, MaxDailyLoss: 200, MLFilterMode: MLFilterObserve},
+		Features: config.FeaturesConfig{MLPredictionsEnabled: true},
+	}
+	riskManager := NewRiskManager(&cfg.Trading, betRepo, logger)
+	model := &models.Model{ID: uuid.New(), Version: "v1"}
+	predictionRepo := &storingPredictionRepo{}
+	strategyID := uuid.New()
+
+	orchestrator := &Orchestrator{
+		config:      cfg,
+		runnerRepo:  &replayRunnerRepo{runners: map[uuid.UUID][]*models.Runner{race.ID: {fav}}},
+		oddsRepo:    odds,
+		betRepo:     betRepo,
+		riskManager: riskManager,
+		executor:    NewExecutor(nil, betRepo, riskManager, true, false, logger, nil),
+		monitor:     NewMonitor(betRepo, nil, nil, nil, 1000, time.Minute, logger),
+		// The model strongly favours laying the backed favourite and would veto it
+		mlClient:         &fixedPredictor{probability: 0.05, version: "v1"},
+		mlRecorder:       NewPredictionRecorder(predictionRepo, &activeModelRepo{active: []*models.Model{model}}),
+		mlFilterMode:     MLFilterObserve,
+		edgeGate:         strategy.NewEdgeGate(0.5, 0.9),
+		activeStrategies: map[uuid.UUID]strategy.Strategy{strategyID: &favouriteBackStrategy{}},
+		circuitBreaker: NewCircuitBreaker(CircuitBreakerConfig{
+			MaxConsecutiveLosses: 5,
+			MaxDrawdownPercent:   0.5,
+			MaxFailureCount:      5,
+			FailureTimeWindow:    time.Minute,
+			CooldownPeriod:       time.Minute,
+		}, logger),
+		logger: logger,
+	}
+	orchestrator.SetClock(NewMockClock(now))
+
+	bets, err := orchestrator.processRace(context.Background(), race, now)
+	require.NoError(t, err)
+	require.Len(t, bets, 1, "observe mode never vetoes or gates a bet")
+	assert.Equal(t, fav.ID, bets[0].RunnerID)
+	assert.Equal(t, models.BetSideBack, bets[0].Side)
+	assert.Nil(t, bets[0].ModelProbability, "no probability gated the bet")
+
+	require.Len(t, predictionRepo.stored, 1)
+	assert.Equal(t, model.ID, predictionRepo.stored[0].ModelID)
+	assert.Equal(t, race.ID, predictionRepo.stored[0].RaceID)
+	assert.Equal(t, fav.ID, predictionRepo.stored[0].RunnerID)
+	assert.Equal(t, 0.05, predictionRepo.stored[0].Probability)
+	assert.Equal(t, 1.0, testutil.ToFloat64(metrics.MLObservedPredictionsTotal.WithLabelValues(strategyID.String(), "disagree")))
+	assert.Equal(t, uint64(1), histogramCount(t, metrics.MLProbabilityDivergence.WithLabelValues(strategyID.String())))
+}
+
+// flakyStrategyRepo serves a fixed strategy set until err is set
+type flakyStrategyRepo struct {
+	repository.StrategyRepository
+	strategies []*models.Strategy
+	err        error
+	calls      int
+}
+
+func (r *flakyStrategyRepo) GetAll(ctx context.Context) ([]*models.Strategy, error) {
+	r.calls++
+	if r.err != nil {
+		return nil, r.err
+	}
+	return r.strategies, nil
+}
+
+func TestRefreshStrategiesKeepsLastKnownGoodSet(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+	ctx := context.Background()
+	clock := NewMockClock(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+
+	repo := &flakyStrategyRepo{strategies: []*models.Strategy{
+		{ID: uuid.New(), Name: "value", Type: "simple_value", IsActive: true},
+	}}
+	orchestrator := &Orchestrator{
+		config: &config.Config{Bot: config.BotConfig{
+			StrategyReloadInterval: 60,
+			StrategyMaxStaleness:   600,
+		}},
+		strategyRepo: repo,
+		clock:        clock,
+		logger:       logger,
+	}
+
+	// Never loaded: trading stays halted until the repository answers
+	repo.err = errors.New("connection refused")
+	assert.False(t, orchestrator.refreshStrategies(ctx, clock.Now()))
+	repo.err = nil
+	assert.True(t, orchestrator.refreshStrategies(ctx, clock.Now()))
+	require.Len(t, orchestrator.activeStrategies, 1)
+	loadedAt := orchestrator.strategiesAt
+
+	// Within the reload interval the repository is not asked again
+	assert.True(t, orchestrator.refreshStrategies(ctx, clock.Now().Add(30*time.Second)))
+	assert.Equal(t, 2, repo.calls)
+
+	// A failed reload keeps trading on the previous set
+	repo.err = errors.New("connection refused")
+	clock.Advance(2 * time.Minute)
+	assert.True(t, orchestrator.refreshStrategies(ctx, clock.Now()))
+	assert.Equal(t, 3, repo.calls)
+	assert.Len(t, orchestrator.activeStrategies, 1, "strategies are not forgotten")
+	assert.Equal(t, loadedAt, orchestrator.strategiesAt)
+
+	// Beyond the staleness bound trading halts
+	clock.Advance(9 * time.Minute)
+	assert.False(t, orchestrator.refreshStrategies(ctx, clock.Now()))
+
+	// Recovery resumes trading with a fresh set
+	repo.err = nil
+	assert.True(t, orchestrator.refreshStrategies(ctx, clock.Now()))
+	assert.Equal(t, clock.Now(), orchestrator.strategiesAt)
+}
+
+func TestLoadActiveStrategiesSkipsArchived(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	archivedAt := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	live := &models.Strategy{ID: uuid.New(), Name: "value", Type: "simple_value", IsActive: true}
+	archived := &models.Strategy{ID: uuid.New(), Name: "value", Type: "simple_value", IsActive: true}
+	archived.ArchivedAt = &archivedAt
+
+	orchestrator := &Orchestrator{
+		config:       &config.Config{},
+		strategyRepo: &flakyStrategyRepo{strategies: []*models.Strategy{live, archived}},
+		logger:       logger,
+	}
+
+	require.NoError(t, orchestrator.loadActiveStrategies(context.Background()))
+	assert.Len(t, orchestrator.activeStrategies, 1)
+	assert.Contains(t, orchestrator.activeStrategies, live.ID)
+}
+
+func TestLoadActiveStrategiesSurfacesUnknownTypes(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	known := &models.Strategy{ID: uuid.New(), Name: "value", Type: "simple_value", IsActive: true}
+	typo := &models.Strategy{ID: uuid.New(), Name: "value-typo", Type: "simple_valu", IsActive: true}
+	inactive := &models.Strategy{ID: uuid.New(), Name: "old", Type: "retired_type"}
+	repo := &flakyStrategyRepo{strategies: []*models.Strategy{known, typo, inactive}}
+
+	orchestrator := &Orchestrator{
+		config:       &config.Config{},
+		strategyRepo: repo,
+		logger:       logger,
+	}
+
+	require.NoError(t, orchestrator.loadActiveStrategies(context.Background()), "unknown types do not fail the load")
+	assert.Len(t, orchestrator.activeStrategies, 1)
+	assert.Contains(t, orchestrator.activeStrategies, known.ID)
+
+	err := orchestrator.UnknownStrategyTypes()
+	require.Error(t, err)
+	var unknown *UnknownStrategyTypeError
+	require.ErrorAs(t, err, &unknown)
+	assert.Equal(t, typo.ID, unknown.StrategyID)
+	assert.Equal(t, "simple_valu", unknown.Type)
+	assert.Equal(t, 1, countJoined(err), "inactive strategies are not counted")
+	assert.Equal(t, 1.0, testutil.ToFloat64(metrics.StrategyUnknownType.WithLabelValues(typo.ID.String(), typo.Name, typo.Type)))
+
+	// Fixing the type clears the error and the metric on the next load
+	typo.Type = "simple_value"
+	require.NoError(t, orchestrator.loadActiveStrategies(context.Background()))
+	assert.NoError(t, orchestrator.UnknownStrategyTypes())
+	assert.Len(t, orchestrator.activeStrategies, 2)
+	assert.Equal(t, 0, testutil.CollectAndCount(metrics.StrategyUnknownType))
+}
+
+func TestNewOrchestratorUnknownStrategyTypes(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	typo := &models.Strategy{ID: uuid.New(), Name: "value-typo", Type: "simple_valu", IsActive: true}
+	repos := Repositories{Strategy: &flakyStrategyRepo{strategies: []*models.Strategy{typo}}}
+	newConfig := func(action string) *config.Config {
+		return &config.Config{
+			Trading:  config.TradingConfig{MaxStakePerBet: 100, MaxExposure: 500, MaxDailyLoss: 200},
+			Bot:      config.BotConfig{MaxConsecutiveLosses: 5, MaxDrawdownPercent: 0.5, UnknownStrategyTypes: action},
+			Features: config.FeaturesConfig{PaperTradingEnabled: true},
+		}
+	}
+
+	orchestrator, err := NewOrchestrator(newConfig(UnknownStrategyTypeSkip), nil, nil, nil, nil, repos, logger, nil, nil, nil)
+	require.NoError(t, err, "skip mode starts without the strategy")
+	assert.Empty(t, orchestrator.activeStrategies)
+	assert.Error(t, orchestrator.UnknownStrategyTypes())
+
+	_, err = NewOrchestrator(newConfig(UnknownStrategyTypeFail), nil, nil, nil, nil, repos, logger, nil, nil, nil)
+	require.Error(t, err)
+	var unknown *UnknownStrategyTypeError
+	require.ErrorAs(t, err, &unknown)
+	assert.Contains(t, err.Error(), `"value-typo"`, "the error names the offending strategy")
+	assert.Contains(t, err.Error(), `"simple_valu"`)
+}
+
+func TestApplyMLFilterOddsBands(t *testing.T) {
+	gate := strategy.NewEdgeGate(0.02, 0.05).WithBands([]strategy.OddsBand{
+		{MinOdds: 8.0, MinEdge: 0.10},
+	})
+	signals := []SignalWithContext{
+		{Signal: strategy.Signal{RunnerID: uuid.New(), Side: models.BetSideBack, Odds: 2.0}},
+		{Signal: strategy.Signal{RunnerID: uuid.New(), Side: models.BetSideBack, Odds: 10.0}},
+	}
+	// A 5% edge on each
+	predictions := map[int]*ml.PredictionResult{
+		0: {Probability: 0.525},
+		1: {Probability: 0.105},
+	}
+
+	filtered, _ := applyMLFilter(MLFilterVeto, gate, signals, predictions)
+	require.Len(t, filtered, 1)
+	assert.Equal(t, 2.0, filtered[0].Signal.Odds, "the longshot misses its band's higher edge requirement")
+}
+
+// bypassedPredictor fails for one runner as a slow, bypassed ML service would
+type bypassedPredictor struct {
+	fixedPredictor
+	slowRunner uuid.UUID
+}
+
+func (p *bypassedPredictor) GetPrediction(ctx context.Context, raceID, runnerID, strategyID uuid.UUID, features []float64, modelVersion string) (*ml.PredictionResult, error) {
+	if runnerID == p.slowRunner {
+		return nil, ml.ErrMLServiceSlow
+	}
+	return p.fixedPredictor.GetPrediction(ctx, raceID, runnerID, strategyID, features, modelVersion)
+}
+
+func TestFilterSignalsWithMLFailurePolicy(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	// Both clear the edge gate on their own; the model only scores the first
+	scored := SignalWithContext{Signal: strategy.Signal{RunnerID: uuid.New(), Side: models.BetSideBack, Odds: 4.0, Stake: 5, Confidence: 0.6}}
+	unscored := SignalWithContext{Signal: strategy.Signal{RunnerID: uuid.New(), Side: models.BetSideBack, Odds: 4.0, Stake: 5, Confidence: 0.6}}
+	signals := []SignalWithContext{unscored, scored}
+
+	orchestrator := &Orchestrator{
+		mlClient: &bypassedPredictor{fixedPredictor: fixedPredictor{probability: 0.4}, slowRunner: unscored.Signal.RunnerID},
+		edgeGate: strategy.NewEdgeGate(0.1, 0.3),
+		logger:   logger,
+	}
+
+	orchestrator.mlFailurePolicy = MLFailOpen
+	filtered, err := orchestrator.filterSignalsWithML(context.Background(), signals)
+	assert.ErrorIs(t, err, ml.ErrMLServiceSlow)
+	assert.Len(t, filtered, 2, "failing open gates the unscored signal on strategy confidence")
+
+	orchestrator.mlFailurePolicy = MLFailClosed
+	filtered, err = orchestrator.filterSignalsWithML(context.Background(), signals)
+	assert.ErrorIs(t, err, ml.ErrMLServiceSlow)
+	require.Len(t, filtered, 1, "failing closed drops the unscored signal")
+	assert.Equal(t, scored.Signal.RunnerID, filtered[0].Signal.RunnerID)
+	require.NotNil(t, filtered[0].Signal.ModelProbability)
+	assert.Equal(t, 0.4, *filtered[0].Signal.ModelProbability, "the prediction stays with its signal")
+
+	orchestrator.mlFilterMode = MLFilterAdvisory
+	filtered, _ = orchestrator.filterSignalsWithML(context.Background(), signals)
+	assert.Len(t, filtered, 2, "advisory mode never drops signals")
+}
+
+// gatheredGauge scrapes the metrics registry for an unlabelled gauge's value
+func gatheredGauge(t *testing.T, name string) float64 {
+	t.Helper()
+	families, err := metrics.GetRegistry().Gather()
+	require.NoError(t, err)
+	for _, family := range families {
+		if family.GetName() == name {
+			require.Len(t, family.GetMetric(), 1)
+			return family.GetMetric()[0].GetGauge().GetValue()
+		}
+	}
+	t.Fatalf("metric %s not gathered", name)
+	return 0
+}
+
+func TestTradingTickExportsStatusGauges(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	cfg := &config.Config{
+		Trading:  config.TradingConfig{MaxStakePerBet: 100, MaxExposure: 500, MaxDailyLoss: 200},
+		Bot:      config.BotConfig{MaxConsecutiveLosses: 5, MaxDrawdownPercent: 0.5},
+		Features: config.FeaturesConfig{PaperTradingEnabled: true},
+	}
+	repos := Repositories{Strategy: &flakyStrategyRepo{}}
+	orchestrator, err := NewOrchestrator(cfg, nil, nil, nil, nil, repos, logger, nil, nil, nil)
+	require.NoError(t, err)
+
+	orchestrator.riskManager.mu.Lock()
+	orchestrator.riskManager.currentExposure = 420
+	orchestrator.riskManager.dailyLoss = 75
+	orchestrator.riskManager.mu.Unlock()
+	orchestrator.circuitBreaker.TriggerEmergencyShutdown("test")
+
+	// The tick halts on the open circuit but still exports the status
+	orchestrator.tradingTick(context.Background())
+
+	assert.Equal(t, 2.0, gatheredGauge(t, "clever_better_circuit_breaker_state"))
+	assert.Equal(t, 420.0, gatheredGauge(t, "clever_better_total_exposure"))
+	assert.Equal(t, 80.0, gatheredGauge(t, "clever_better_remaining_exposure_capacity"))
+	assert.Equal(t, 500.0, gatheredGauge(t, "clever_better_max_exposure"))
+	assert.Equal(t, 75.0, gatheredGauge(t, "clever_better_daily_loss"))
+	assert.Equal(t, 200.0, gatheredGauge(t, "clever_better_max_daily_loss"))
+	assert.Equal(t, 0.0, gatheredGauge(t, "clever_better_active_strategies"))
+	assert.Equal(t, 0.0, gatheredGauge(t, "clever_better_kill_switch_engaged"))
+
+	orchestrator.circuitBreaker.Reset()
+	orchestrator.publishStatus()
+	assert.Equal(t, 0.0, gatheredGauge(t, "clever_better_circuit_breaker_state"))
+}
+
+// slowStrategy blocks until released, optionally ignoring cancellation
+type slowStrategy struct {
+	countingStrategy
+	release   chan struct{}
+	honourCtx bool
+	cancelled chan struct{}
+}
+
+func (s *slowStrategy) Evaluate(ctx context.Context, strategyCtx strategy.Context) ([]strategy.Signal, error) {
+	if s.honourCtx {
+		<-ctx.Done()
+		close(s.cancelled)
+		return nil, ctx.Err()
+	}
+	<-s.release
+	return []strategy.Signal{{RunnerID: uuid.New(), Side: models.BetSideBack, Odds: 2.0, Stake: 5}}, nil
+}
+
+// fixedSignalStrategy returns one signal immediately
+type fixedSignalStrategy struct {
+	countingStrategy
+	runnerID uuid.UUID
+}
+
+func (s *fixedSignalStrategy) Evaluate(ctx context.Context, strategyCtx strategy.Context) ([]strategy.Signal, error) {
+	return []strategy.Signal{{RunnerID: s.runnerID, Side: models.BetSideBack, Odds: 3.0, Stake: 5}}, nil
+}
+
+func TestEvaluateStrategiesSkipsSlowStrategies(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	hanging := &slowStrategy{release: make(chan struct{})}
+	defer close(hanging.release)
+	cancellable := &slowStrategy{honourCtx: true, cancelled: make(chan struct{})}
+	fast := &fixedSignalStrategy{runnerID: uuid.New()}
+	fastID := uuid.New()
+
+	orchestrator := &Orchestrator{
+		config:     &config.Config{},
+		runnerRepo: &replayRunnerRepo{},
+		oddsRepo:   &replayOddsRepo{},
+		activeStrategies: map[uuid.UUID]strategy.Strategy{
+			uuid.New(): hanging,
+			uuid.New(): cancellable,
+			fastID:     fast,
+		},
+		evalTimeout: 20 * time.Millisecond,
+		logger:      logger,
+	}
+
+	race := &models.Race{ID: uuid.New(), Status: "scheduled"}
+	start := time.Now()
+	signals, err := orchestrator.evaluateStrategies(context.Background(), race, start, nil)
+	require.NoError(t, err)
+	assert.Less(t, time.Since(start), time.Second, "slow strategies must not stall the loop")
+
+	require.Len(t, signals, 1, "only the fast strategy's signal survives")
+	assert.Equal(t, fastID, signals[0].StrategyID)
+	assert.Equal(t, fast.runnerID, signals[0].Signal.RunnerID)
+
+	select {
+	case <-cancellable.cancelled:
+	case <-time.After(time.Second):
+		t.Fatal("the timed-out strategy's context was not cancelled")
+	}
+}
+
+// histogramCount returns how many observations a histogram has recorded
+func histogramCount(t *testing.T, observer prometheus.Observer) uint64 {
+	t.Helper()
+	var metric dto.Metric
+	require.NoError(t, observer.(prometheus.Metric).Write(&metric))
+	return metric.GetHistogram().GetSampleCount()
+}
+
+func TestStrategyContextUsesRaceBundle(t *testing.T) {
+	now := time.Now()
+	race := &models.Race{ID: uuid.New(), ScheduledStart: now.Add(10 * time.Minute)}
+	runner := &models.Runner{ID: uuid.New(), RaceID: race.ID, TrapNumber: 1, Name: "Runner"}
+	back := 3.0
+	recent := &models.OddsSnapshot{Time: now.Add(-time.Minute), RaceID: race.ID, RunnerID: runner.ID, BackPrice: &back}
+	stale := &models.OddsSnapshot{Time: now.Add(-3 * time.Hour), RaceID: race.ID, RunnerID: runner.ID, BackPrice: &back}
+
+	races := &replayRaceRepo{
+		races:   []*models.Race{race},
+		runners: &replayRunnerRepo{runners: map[uuid.UUID][]*models.Runner{race.ID: {runner}}},
+		odds:    &replayOddsRepo{odds: map[uuid.UUID][]*models.OddsSnapshot{race.ID: {stale, recent}}},
+	}
+	// Without runner or odds repositories, loading them separately would panic
+	orchestrator := &Orchestrator{config: &config.Config{}, raceBundles: races}
+
+	stratCtx, _, err := orchestrator.strategyContext(context.Background(), race, now)
+	require.NoError(t, err)
+	assert.Equal(t, 1, races.bundles)
+	assert.Equal(t, []*models.Runner{runner}, stratCtx.Runners)
+	assert.Equal(t, []*models.OddsSnapshot{recent}, stratCtx.OddsHistory, "odds older than the lookback are dropped")
+}
+
+func TestStrategyContextReplayIgnoresLaterBundleOdds(t *testing.T) {
+	start := time.Date(2024, 3, 1, 19, 30, 0, 0, time.UTC)
+	now := start.Add(-30 * time.Minute)
+	race := &models.Race{ID: uuid.New(), ScheduledStart: start}
+	runner := &models.Runner{ID: uuid.New(), RaceID: race.ID, TrapNumber: 1, Name: "Runner"}
+	early, late := 3.0, 2.5
+	asOfNow := &models.OddsSnapshot{Time: now.Add(-time.Minute), RaceID: race.ID, RunnerID: runner.ID, BackPrice: &early}
+	beforeOff := &models.OddsSnapshot{Time: start.Add(-time.Minute), RaceID: race.ID, RunnerID: runner.ID, BackPrice: &late}
+
+	odds := &replayOddsRepo{odds: map[uuid.UUID][]*models.OddsSnapshot{race.ID: {asOfNow, beforeOff}}}
+	races := &replayRaceRepo{
+		races:   []*models.Race{race},
+		runners: &replayRunnerRepo{runners: map[uuid.UUID][]*models.Runner{race.ID: {runner}}},
+		odds:    odds,
+	}
+	orchestrator := &Orchestrator{config: &config.Config{}, raceBundles: races, oddsRepo: odds}
+
+	stratCtx, _, err := orchestrator.strategyContext(context.Background(), race, now)
+	require.NoError(t, err)
+	assert.Equal(t, []*models.Runner{runner}, stratCtx.Runners)
+	assert.Equal(t, []*models.OddsSnapshot{asOfNow}, stratCtx.OddsHistory, "odds recorded after now are never seen")
+}
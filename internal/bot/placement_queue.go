@@ -0,0 +1,127 @@
+package bot
+
+import (
+	"container/heap"
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/yourusername/clever-better/internal/models"
+	"golang.org/x/time/rate"
+)
+
+// ErrPlacementWindowClosed is returned for signals whose race would be too
+// close to the off by the time the rate limit allowed them to be placed
+var ErrPlacementWindowClosed = errors.New("placement window closed before dispatch")
+
+// PlacementQueue orders pending placements by time to the off, soonest first,
+// then by confidence, and dispatches them within a rate limit. Placements that
+// could not go out before their window closes are skipped rather than sent late.
+type PlacementQueue struct {
+	limiter *rate.Limiter
+	cutoff  time.Duration
+	mu      sync.Mutex
+}
+
+// NewPlacementQueue creates a queue dispatching at most ratePerSecond
+// placements. A placement is skipped unless it can be sent at least cutoff
+// before its race's off.
+func NewPlacementQueue(ratePerSecond float64, cutoff time.Duration) *PlacementQueue {
+	return &PlacementQueue{
+		limiter: rate.NewLimiter(rate.Limit(ratePerSecond), 1),
+		cutoff:  cutoff,
+	}
+}
+
+// Dispatch places signals in priority order, calling place for each one whose
+// window is still open. It returns one result per signal in the input order;
+// skipped signals carry ErrPlacementWindowClosed, and those still queued when
+// ctx is cancelled carry its error. Signals without an off time never expire
+// and go last. In-play signals are past their off, so they go first and are
+// never skipped.
+func (q *PlacementQueue) Dispatch(
+	ctx context.Context,
+	signals []SignalWithContext,
+	place func(SignalWithContext) (*models.Bet, error),
+) []BatchExecutionResult {
+	// One batch at a time so concurrent callers share the limit in order
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	pending := make(placementHeap, len(signals))
+	for i := range signals {
+		pending[i] = &queuedPlacement{signal: signals[i], position: i}
+	}
+	heap.Init(&pending)
+
+	results := make([]BatchExecutionResult, len(signals))
+	for pending.Len() > 0 {
+		next := heap.Pop(&pending).(*queuedPlacement)
+		if err := ctx.Err(); err != nil {
+			results[next.position] = BatchExecutionResult{Signal: next.signal, Err: err}
+			continue
+		}
+
+		reservation := q.limiter.Reserve()
+		delay := reservation.Delay()
+		if offTime := next.signal.OffTime; !next.signal.InPlay && !offTime.IsZero() && !time.Now().Add(delay+q.cutoff).Before(offTime) {
+			reservation.Cancel()
+			results[next.position] = BatchExecutionResult{Signal: next.signal, Err: ErrPlacementWindowClosed}
+			continue
+		}
+
+		if delay > 0 {
+			timer := time.NewTimer(delay)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				reservation.Cancel()
+				results[next.position] = BatchExecutionResult{Signal: next.signal, Err: ctx.Err()}
+				continue
+			case <-timer.C:
+			}
+		}
+
+		bet, err := place(next.signal)
+		results[next.position] = BatchExecutionResult{Signal: next.signal, Bet: bet, Err: err}
+	}
+
+	return results
+}
+
+// queuedPlacement is a signal waiting in the queue and its input position
+type queuedPlacement struct {
+	signal   SignalWithContext
+	position int
+}
+
+// placementHeap is a min-heap on time to off, then highest confidence first
+type placementHeap []*queuedPlacement
+
+func (h placementHeap) Len() int { return len(h) }
+
+func (h placementHeap) Less(i, j int) bool {
+	a, b := h[i].signal, h[j].signal
+	if !a.OffTime.Equal(b.OffTime) {
+		if a.OffTime.IsZero() || b.OffTime.IsZero() {
+			return b.OffTime.IsZero()
+		}
+		return a.OffTime.Before(b.OffTime)
+	}
+	if a.Signal.Confidence != b.Signal.Confidence {
+		return a.Signal.Confidence > b.Signal.Confidence
+	}
+	return h[i].position < h[j].position
+}
+
+func (h placementHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *placementHeap) Push(x interface{}) { *h = append(*h, x.(*queuedPlacement)) }
+
+func (h *placementHeap) Pop() interface{} {
+	old := *h
+	item := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return item
+}
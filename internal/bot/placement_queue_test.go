@@ -0,0 +1,99 @@
+package bot
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/clever-better/internal/models"
+	"github.com/yourusername/clever-better/internal/strategy"
+)
+
+func queuedSignal(offTime time.Time, confidence float64) SignalWithContext {
+	return SignalWithContext{
+		Signal:  strategy.Signal{RunnerID: uuid.New(), Confidence: confidence},
+		OffTime: offTime,
+	}
+}
+
+// recordPlacements returns a place func recording the order signals are placed in
+func recordPlacements() (func(SignalWithContext) (*models.Bet, error), func() []uuid.UUID) {
+	var mu sync.Mutex
+	var placed []uuid.UUID
+	place := func(signal SignalWithContext) (*models.Bet, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		placed = append(placed, signal.Signal.RunnerID)
+		return &models.Bet{ID: uuid.New(), RunnerID: signal.Signal.RunnerID}, nil
+	}
+	return place, func() []uuid.UUID {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]uuid.UUID(nil), placed...)
+	}
+}
+
+func TestPlacementQueueOrdersByTimeToOff(t *testing.T) {
+	now := time.Now()
+	later := queuedSignal(now.Add(10*time.Minute), 0.9)
+	soonLow := queuedSignal(now.Add(2*time.Minute), 0.6)
+	soonHigh := queuedSignal(now.Add(2*time.Minute), 0.8)
+	noOff := queuedSignal(time.Time{}, 0.99)
+	signals := []SignalWithContext{later, noOff, soonLow, soonHigh}
+
+	queue := NewPlacementQueue(1000, 0)
+	place, placed := recordPlacements()
+	results := queue.Dispatch(context.Background(), signals, place)
+
+	assert.Equal(t, []uuid.UUID{
+		soonHigh.Signal.RunnerID,
+		soonLow.Signal.RunnerID,
+		later.Signal.RunnerID,
+		noOff.Signal.RunnerID,
+	}, placed())
+
+	require.Len(t, results, len(signals))
+	for i, result := range results {
+		require.NoError(t, result.Err)
+		assert.Equal(t, signals[i].Signal.RunnerID, result.Bet.RunnerID, "results keep input order")
+	}
+}
+
+func TestPlacementQueueSkipsClosedWindows(t *testing.T) {
+	now := time.Now()
+	// Two placements a second: the second slot is ~500ms away, after the
+	// urgent races' cutoff but well before the later race's
+	urgent := queuedSignal(now.Add(300*time.Millisecond), 0.9)
+	urgentLow := queuedSignal(now.Add(300*time.Millisecond), 0.5)
+	later := queuedSignal(now.Add(5*time.Second), 0.7)
+	signals := []SignalWithContext{later, urgentLow, urgent}
+
+	queue := NewPlacementQueue(2, 100*time.Millisecond)
+	place, placed := recordPlacements()
+	results := queue.Dispatch(context.Background(), signals, place)
+
+	assert.Equal(t, []uuid.UUID{urgent.Signal.RunnerID, later.Signal.RunnerID}, placed())
+
+	require.Len(t, results, 3)
+	assert.NoError(t, results[0].Err)
+	assert.ErrorIs(t, results[1].Err, ErrPlacementWindowClosed)
+	assert.Nil(t, results[1].Bet)
+	assert.NoError(t, results[2].Err)
+}
+
+func TestPlacementQueueStopsOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	queue := NewPlacementQueue(1000, 0)
+	place, placed := recordPlacements()
+	results := queue.Dispatch(ctx, []SignalWithContext{queuedSignal(time.Time{}, 0.5)}, place)
+
+	assert.Empty(t, placed())
+	require.Len(t, results, 1)
+	assert.ErrorIs(t, results[0].Err, context.Canceled)
+}
@@ -0,0 +1,110 @@
+package bot
+
+import (
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/clever-better/internal/models"
+)
+
+// portfolioVaRZ is the one-sided 95% quantile of the standard normal
+const portfolioVaRZ = 1.645
+
+// PortfolioRisk is the combined risk of the open bets across all strategies.
+// VaR figures are 95% one-race-outcome losses under a normal approximation.
+type PortfolioRisk struct {
+	// CombinedExposure is the worst-case loss of every open bet
+	CombinedExposure float64 `json:"combined_exposure"`
+	// StrategyVaR is each strategy's VaR on its own
+	StrategyVaR map[uuid.UUID]float64 `json:"strategy_var"`
+	// UndiversifiedVaR sums the strategy VaRs, ignoring how they interact
+	UndiversifiedVaR float64 `json:"undiversified_var"`
+	// PortfolioVaR accounts for strategies betting the same races
+	PortfolioVaR float64 `json:"portfolio_var"`
+	// OverlappingRaces counts races with open bets from more than one strategy
+	OverlappingRaces int       `json:"overlapping_races"`
+	CalculatedAt     time.Time `json:"calculated_at"`
+}
+
+// openPosition is a bet's P&L as a function of its runner winning: the P&L
+// moves by swing between the runner losing and winning
+type openPosition struct {
+	strategyID uuid.UUID
+	runnerID   uuid.UUID
+	swing      float64
+}
+
+// CalculatePortfolioRisk estimates the combined risk of open bets. Each
+// runner wins with its implied probability; bets on the same runner move
+// together, bets on different runners in a race move against each other as
+// only one can win, and different races are independent.
+func CalculatePortfolioRisk(bets []*models.Bet, now time.Time) PortfolioRisk {
+	risk := PortfolioRisk{
+		StrategyVaR:  make(map[uuid.UUID]float64),
+		CalculatedAt: now,
+	}
+
+	races := make(map[uuid.UUID][]openPosition)
+	probabilitySums := make(map[uuid.UUID]float64)
+	probabilityCounts := make(map[uuid.UUID]int)
+	for _, bet := range bets {
+		price := bet.SettlementPrice()
+		if bet.Side == models.BetSideLay && price > 1 {
+			risk.CombinedExposure += (price - 1) * bet.Stake
+		} else {
+			risk.CombinedExposure += bet.Stake
+		}
+		if price <= 1 {
+			continue
+		}
+
+		swing := bet.Stake * price
+		if bet.Side == models.BetSideLay {
+			swing = -swing
+		}
+		races[bet.RaceID] = append(races[bet.RaceID], openPosition{
+			strategyID: bet.StrategyID,
+			runnerID:   bet.RunnerID,
+			swing:      swing,
+		})
+		probabilitySums[bet.RunnerID] += 1 / price
+		probabilityCounts[bet.RunnerID]++
+	}
+
+	covariance := make(map[uuid.UUID]map[uuid.UUID]float64)
+	for _, positions := range races {
+		strategies := make(map[uuid.UUID]bool)
+		for _, a := range positions {
+			strategies[a.strategyID] = true
+			pa := probabilitySums[a.runnerID] / float64(probabilityCounts[a.runnerID])
+			for _, b := range positions {
+				pb := probabilitySums[b.runnerID] / float64(probabilityCounts[b.runnerID])
+				outcomeCov := -pa * pb
+				if a.runnerID == b.runnerID {
+					outcomeCov = pa * (1 - pa)
+				}
+				if covariance[a.strategyID] == nil {
+					covariance[a.strategyID] = make(map[uuid.UUID]float64)
+				}
+				covariance[a.strategyID][b.strategyID] += a.swing * b.swing * outcomeCov
+			}
+		}
+		if len(strategies) > 1 {
+			risk.OverlappingRaces++
+		}
+	}
+
+	total := 0.0
+	for strategyID, row := range covariance {
+		strategyVaR := portfolioVaRZ * math.Sqrt(math.Max(row[strategyID], 0))
+		risk.StrategyVaR[strategyID] = strategyVaR
+		risk.UndiversifiedVaR += strategyVaR
+		for _, cov := range row {
+			total += cov
+		}
+	}
+	risk.PortfolioVaR = portfolioVaRZ * math.Sqrt(math.Max(total, 0))
+
+	return risk
+}
@@ -0,0 +1,94 @@
+package bot
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/clever-better/internal/models"
+)
+
+func openBet(strategyID, raceID, runnerID uuid.UUID, side models.BetSide, odds, stake float64) *models.Bet {
+	return &models.Bet{
+		ID:         uuid.New(),
+		StrategyID: strategyID,
+		RaceID:     raceID,
+		RunnerID:   runnerID,
+		Side:       side,
+		Odds:       odds,
+		Stake:      stake,
+		Status:     models.BetStatusPending,
+	}
+}
+
+func TestPortfolioRiskHigherWhenStrategiesOverlap(t *testing.T) {
+	a, b := uuid.New(), uuid.New()
+	race, otherRace := uuid.New(), uuid.New()
+	runner, otherRunner := uuid.New(), uuid.New()
+	now := time.Now()
+
+	overlapping := CalculatePortfolioRisk([]*models.Bet{
+		openBet(a, race, runner, models.BetSideBack, 4.0, 10),
+		openBet(b, race, runner, models.BetSideBack, 4.0, 10),
+	}, now)
+	disjoint := CalculatePortfolioRisk([]*models.Bet{
+		openBet(a, race, runner, models.BetSideBack, 4.0, 10),
+		openBet(b, otherRace, otherRunner, models.BetSideBack, 4.0, 10),
+	}, now)
+
+	assert.Equal(t, 1, overlapping.OverlappingRaces)
+	assert.Zero(t, disjoint.OverlappingRaces)
+	assert.Equal(t, overlapping.CombinedExposure, disjoint.CombinedExposure, "worst-case exposure ignores correlation")
+	assert.Greater(t, overlapping.PortfolioVaR, disjoint.PortfolioVaR)
+
+	// Each back of 10 at 4.0 swings 40 with p=0.25: sigma = 40*sqrt(0.1875)
+	sigma := 40 * math.Sqrt(0.1875)
+	assert.InDelta(t, portfolioVaRZ*sigma, overlapping.StrategyVaR[a], 1e-9)
+	assert.InDelta(t, 2*portfolioVaRZ*sigma, overlapping.PortfolioVaR, 1e-9, "identical bets are fully correlated")
+	assert.InDelta(t, math.Sqrt2*portfolioVaRZ*sigma, disjoint.PortfolioVaR, 1e-9, "independent races diversify")
+	assert.InDelta(t, overlapping.UndiversifiedVaR, disjoint.UndiversifiedVaR, 1e-9)
+}
+
+func TestPortfolioRiskOffsettingPositions(t *testing.T) {
+	a, b := uuid.New(), uuid.New()
+	race, runner := uuid.New(), uuid.New()
+
+	// A back and an equal lay on the same runner hedge each other
+	risk := CalculatePortfolioRisk([]*models.Bet{
+		openBet(a, race, runner, models.BetSideBack, 3.0, 10),
+		openBet(b, race, runner, models.BetSideLay, 3.0, 10),
+	}, time.Now())
+
+	assert.InDelta(t, 30.0, risk.CombinedExposure, 1e-9)
+	assert.InDelta(t, 0.0, risk.PortfolioVaR, 1e-9)
+	assert.Greater(t, risk.UndiversifiedVaR, 0.0)
+}
+
+func TestMonitorUpdatesPortfolioRisk(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+	ctx := context.Background()
+	a, b := uuid.New(), uuid.New()
+	race, runner := uuid.New(), uuid.New()
+
+	betRepo := new(MockBetRepository)
+	betRepo.On("GetPendingBets", mock.Anything).Return([]*models.Bet{
+		openBet(a, race, runner, models.BetSideBack, 4.0, 10),
+		openBet(b, race, runner, models.BetSideBack, 4.0, 10),
+	}, nil)
+
+	monitor := NewMonitor(betRepo, nil, nil, nil, 1000, time.Minute, logger)
+	assert.Nil(t, monitor.PortfolioRisk())
+
+	risk, err := monitor.UpdatePortfolioRisk(ctx)
+	require.NoError(t, err)
+	assert.Len(t, risk.StrategyVaR, 2)
+	require.NotNil(t, monitor.PortfolioRisk())
+	assert.Equal(t, risk.PortfolioVaR, monitor.PortfolioRisk().PortfolioVaR)
+}
@@ -0,0 +1,117 @@
+package bot
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/yourusername/clever-better/internal/metrics"
+	"github.com/yourusername/clever-better/internal/ml"
+	"github.com/yourusername/clever-better/internal/models"
+	"github.com/yourusername/clever-better/internal/repository"
+)
+
+// ErrNoActiveModel is returned when predictions cannot be stored because no
+// model is marked active to attribute them to
+var ErrNoActiveModel = errors.New("no active model to record predictions against")
+
+// Agreement labels for observed predictions
+const (
+	mlAgreementAgree       = "agree"
+	mlAgreementDisagree    = "disagree"
+	mlAgreementIndifferent = "indifferent"
+)
+
+// PredictionRecorder persists the predictions made for signals in observe
+// mode and records how far they diverged from the strategies, so the model
+// can be judged on live markets before it is allowed to gate bets.
+type PredictionRecorder struct {
+	predictions repository.PredictionRepository
+	models      repository.ModelRepository
+}
+
+// NewPredictionRecorder creates a prediction recorder
+func NewPredictionRecorder(predictions repository.PredictionRepository, models repository.ModelRepository) *PredictionRecorder {
+	return &PredictionRecorder{
+		predictions: predictions,
+		models:      models,
+	}
+}
+
+// Record records divergence metrics for every signal with a prediction, then
+// stores the predictions against the active model with the matching version,
+// or the first active model when none matches. Metrics are recorded even
+// when storing fails.
+func (r *PredictionRecorder) Record(ctx context.Context, signals []SignalWithContext, predictions map[int]*ml.PredictionResult, now time.Time) error {
+	if len(predictions) == 0 {
+		return nil
+	}
+
+	for i, sc := range signals {
+		prediction := predictions[i]
+		if prediction == nil {
+			continue
+		}
+		agreement := mlAgreementIndifferent
+		if side, ok := modelSide(prediction, sc.Signal.Odds); ok {
+			agreement = mlAgreementDisagree
+			if side == signalSide(sc.Signal) {
+				agreement = mlAgreementAgree
+			}
+		}
+		metrics.RecordMLObservation(sc.StrategyID.String(), agreement, prediction.Probability-sc.Signal.Confidence)
+	}
+
+	active, err := r.models.GetActive(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get active models: %w", err)
+	}
+	if len(active) == 0 {
+		return ErrNoActiveModel
+	}
+
+	rows := make([]*models.Prediction, 0, len(predictions))
+	for i, sc := range signals {
+		prediction := predictions[i]
+		if prediction == nil {
+			continue
+		}
+		features, err := json.Marshal(signalFeatures(sc.Signal))
+		if err != nil {
+			return fmt.Errorf("failed to marshal prediction features: %w", err)
+		}
+		predictedAt := prediction.PredictedAt
+		if predictedAt.IsZero() {
+			predictedAt = now
+		}
+		rows = append(rows, &models.Prediction{
+			ID:          uuid.New(),
+			ModelID:     modelIDForVersion(active, prediction.ModelVersion),
+			RaceID:      sc.RaceID,
+			RunnerID:    sc.Signal.RunnerID,
+			Probability: prediction.Probability,
+			Confidence:  prediction.Confidence,
+			Features:    features,
+			PredictedAt: predictedAt,
+		})
+	}
+
+	if err := r.predictions.InsertBatch(ctx, rows); err != nil {
+		return fmt.Errorf("failed to store predictions: %w", err)
+	}
+	return nil
+}
+
+// modelIDForVersion picks the active model a prediction came from
+func modelIDForVersion(active []*models.Model, version string) uuid.UUID {
+	for _, model := range active {
+		if version != "" && model.Version == version {
+			return model.ID
+		}
+	}
+	return active[0].ID
+}
@@ -0,0 +1,110 @@
+package bot
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/clever-better/internal/metrics"
+	"github.com/yourusername/clever-better/internal/ml"
+	"github.com/yourusername/clever-better/internal/models"
+	"github.com/yourusername/clever-better/internal/repository"
+	"github.com/yourusername/clever-better/internal/strategy"
+)
+
+// activeModelRepo serves a fixed set of active models
+type activeModelRepo struct {
+	repository.ModelRepository
+	active []*models.Model
+}
+
+func (r *activeModelRepo) GetActive(ctx context.Context) ([]*models.Model, error) {
+	return r.active, nil
+}
+
+// storingPredictionRepo keeps inserted predictions in memory
+type storingPredictionRepo struct {
+	repository.PredictionRepository
+	stored []*models.Prediction
+}
+
+func (r *storingPredictionRepo) InsertBatch(ctx context.Context, predictions []*models.Prediction) error {
+	r.stored = append(r.stored, predictions...)
+	return nil
+}
+
+// fixedPredictor answers every prediction request with the same probability
+type fixedPredictor struct {
+	probability float64
+	version     string
+}
+
+func (p *fixedPredictor) GetPrediction(ctx context.Context, raceID, runnerID, strategyID uuid.UUID, features []float64, modelVersion string) (*ml.PredictionResult, error) {
+	return &ml.PredictionResult{
+		RaceID:       raceID,
+		RunnerID:     runnerID,
+		StrategyID:   strategyID,
+		Probability:  p.probability,
+		Confidence:   0.8,
+		ModelVersion: p.version,
+	}, nil
+}
+
+func TestPredictionRecorderStoresAgainstActiveModel(t *testing.T) {
+	current := &models.Model{ID: uuid.New(), Version: "v2"}
+	previous := &models.Model{ID: uuid.New(), Version: "v1"}
+	predictionRepo := &storingPredictionRepo{}
+	recorder := NewPredictionRecorder(predictionRepo, &activeModelRepo{active: []*models.Model{previous, current}})
+
+	strategyID := uuid.New()
+	signals := []SignalWithContext{
+		{Signal: strategy.Signal{RunnerID: uuid.New(), Side: models.BetSideBack, Odds: 4.0, Confidence: 0.4}, StrategyID: strategyID, RaceID: uuid.New()},
+		{Signal: strategy.Signal{RunnerID: uuid.New(), Side: models.BetSideBack, Odds: 4.0, Confidence: 0.4}, StrategyID: strategyID, RaceID: uuid.New()},
+		{Signal: strategy.Signal{RunnerID: uuid.New(), Side: models.BetSideBack, Odds: 4.0, Confidence: 0.4}, StrategyID: strategyID, RaceID: uuid.New()},
+	}
+	// The second signal got no prediction; the third came from an unknown version
+	predictedAt := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	predictions := map[int]*ml.PredictionResult{
+		0: {Probability: 0.15, Confidence: 0.7, ModelVersion: "v2", PredictedAt: predictedAt},
+		2: {Probability: 0.40, Confidence: 0.6, ModelVersion: "v9"},
+	}
+
+	now := predictedAt.Add(time.Second)
+	require.NoError(t, recorder.Record(context.Background(), signals, predictions, now))
+
+	require.Len(t, predictionRepo.stored, 2)
+	first := predictionRepo.stored[0]
+	assert.Equal(t, current.ID, first.ModelID, "matched on version")
+	assert.Equal(t, signals[0].RaceID, first.RaceID)
+	assert.Equal(t, signals[0].Signal.RunnerID, first.RunnerID)
+	assert.Equal(t, 0.15, first.Probability)
+	assert.Equal(t, predictedAt, first.PredictedAt)
+	assert.JSONEq(t, "[4,0.4,0]", string(first.Features))
+
+	second := predictionRepo.stored[1]
+	assert.Equal(t, previous.ID, second.ModelID, "unknown versions fall back to the first active model")
+	assert.Equal(t, now, second.PredictedAt)
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(metrics.MLObservedPredictionsTotal.WithLabelValues(strategyID.String(), "disagree")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(metrics.MLObservedPredictionsTotal.WithLabelValues(strategyID.String(), "agree")))
+}
+
+func TestPredictionRecorderRecordsMetricsWithoutActiveModel(t *testing.T) {
+	predictionRepo := &storingPredictionRepo{}
+	recorder := NewPredictionRecorder(predictionRepo, &activeModelRepo{})
+
+	strategyID := uuid.New()
+	signals := []SignalWithContext{
+		{Signal: strategy.Signal{RunnerID: uuid.New(), Odds: 4.0, Confidence: 0.4}, StrategyID: strategyID, RaceID: uuid.New()},
+	}
+	predictions := map[int]*ml.PredictionResult{0: {Probability: 0.25}}
+
+	err := recorder.Record(context.Background(), signals, predictions, time.Now())
+	assert.ErrorIs(t, err, ErrNoActiveModel)
+	assert.Empty(t, predictionRepo.stored)
+	assert.Equal(t, 1.0, testutil.ToFloat64(metrics.MLObservedPredictionsTotal.WithLabelValues(strategyID.String(), "indifferent")))
+}
@@ -0,0 +1,105 @@
+package bot
+
+import (
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/clever-better/internal/models"
+)
+
+// RecoveryRamp scales stakes down once the circuit breaker lets trading
+// resume, so a strategy coming off a losing streak cannot dig the hole deeper
+// at full size. Stakes start at a fraction of full size and rise linearly as
+// bets settle without a loss, or as time passes, whichever is further along.
+// A loss during the ramp starts it again.
+type RecoveryRamp struct {
+	startFraction float64
+	bets          int
+	duration      time.Duration
+	clock         Clock
+	active        bool
+	startedAt     time.Time
+	progress      int
+	counted       map[uuid.UUID]struct{}
+	mu            sync.Mutex
+}
+
+// NewRecoveryRamp creates a ramp starting stakes at startFraction of full
+// size and returning to full size after bets settled bets or duration. Zero
+// disables either criterion.
+func NewRecoveryRamp(startFraction float64, bets int, duration time.Duration) *RecoveryRamp {
+	return &RecoveryRamp{
+		startFraction: startFraction,
+		bets:          bets,
+		duration:      duration,
+		clock:         RealClock{},
+		counted:       make(map[uuid.UUID]struct{}),
+	}
+}
+
+// SetClock replaces the clock the ramp is timed on
+func (r *RecoveryRamp) SetClock(clock Clock) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.clock = clockOrReal(clock)
+}
+
+// Start begins the ramp from its start fraction
+func (r *RecoveryRamp) Start() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.active = true
+	r.startedAt = r.clock.Now()
+	r.progress = 0
+	r.counted = make(map[uuid.UUID]struct{})
+}
+
+// RecordResult advances the ramp for a bet settled since it started without
+// a loss, and restarts it for a loss. Bets already counted are ignored, since
+// the monitor feeds the same settled bets on every update.
+func (r *RecoveryRamp) RecordResult(bet *models.Bet) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.active || bet.ProfitLoss == nil || bet.SettledAt == nil || bet.SettledAt.Before(r.startedAt) {
+		return
+	}
+	if _, seen := r.counted[bet.ID]; seen {
+		return
+	}
+	r.counted[bet.ID] = struct{}{}
+
+	if *bet.ProfitLoss < 0 {
+		r.startedAt = r.clock.Now()
+		r.progress = 0
+		return
+	}
+	r.progress++
+}
+
+// Multiplier returns the fraction of full size stakes are placed at, 1 once
+// the ramp has finished
+func (r *RecoveryRamp) Multiplier() float64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.active {
+		return 1
+	}
+
+	fraction := 0.0
+	if r.bets > 0 {
+		fraction = float64(r.progress) / float64(r.bets)
+	}
+	if r.duration > 0 {
+		if elapsed := float64(r.clock.Now().Sub(r.startedAt)) / float64(r.duration); elapsed > fraction {
+			fraction = elapsed
+		}
+	}
+	if fraction >= 1 || (r.bets == 0 && r.duration == 0) {
+		r.active = false
+		return 1
+	}
+	return r.startFraction + (1-r.startFraction)*fraction
+}
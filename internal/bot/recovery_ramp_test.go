@@ -0,0 +1,89 @@
+package bot
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/yourusername/clever-better/internal/strategy"
+)
+
+func TestRecoveryRampReturnsToFullSizeAfterWinningBets(t *testing.T) {
+	clock := NewMockClock(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+	ramp := NewRecoveryRamp(0.25, 4, 0)
+	ramp.SetClock(clock)
+	assert.Equal(t, 1.0, ramp.Multiplier(), "full size until the ramp starts")
+
+	ramp.Start()
+	assert.Equal(t, 0.25, ramp.Multiplier())
+
+	clock.Advance(time.Minute)
+	old := settledBet(uuid.Nil, clock.Now().Add(-time.Hour), 5)
+	ramp.RecordResult(old)
+	assert.Equal(t, 0.25, ramp.Multiplier(), "bets settled before the ramp started don't count")
+
+	win := settledBet(uuid.Nil, clock.Now(), 5)
+	ramp.RecordResult(win)
+	ramp.RecordResult(win)
+	assert.InDelta(t, 0.4375, ramp.Multiplier(), 1e-9, "each bet counts once")
+
+	ramp.RecordResult(settledBet(uuid.Nil, clock.Now(), -10))
+	assert.Equal(t, 0.25, ramp.Multiplier(), "a loss starts the ramp again")
+
+	for i := 0; i < 4; i++ {
+		ramp.RecordResult(settledBet(uuid.Nil, clock.Now(), 5))
+	}
+	assert.Equal(t, 1.0, ramp.Multiplier())
+
+	ramp.RecordResult(settledBet(uuid.Nil, clock.Now(), -10))
+	assert.Equal(t, 1.0, ramp.Multiplier(), "losses after the ramp finishes don't restart it")
+}
+
+func TestRecoveryRampReturnsToFullSizeOverTime(t *testing.T) {
+	clock := NewMockClock(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+	ramp := NewRecoveryRamp(0.5, 0, time.Hour)
+	ramp.SetClock(clock)
+	ramp.Start()
+
+	assert.Equal(t, 0.5, ramp.Multiplier())
+	clock.Advance(30 * time.Minute)
+	assert.Equal(t, 0.75, ramp.Multiplier())
+	clock.Advance(30 * time.Minute)
+	assert.Equal(t, 1.0, ramp.Multiplier())
+}
+
+func TestApplyRecoveryRampAfterCircuitBreakerCloses(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+
+	clock := NewMockClock(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+	ramp := NewRecoveryRamp(0.25, 2, 0)
+	ramp.SetClock(clock)
+	cb := newTestCircuitBreaker(time.Hour, 0)
+	cb.SetRecoveryRamp(ramp)
+	orchestrator := &Orchestrator{circuitBreaker: cb, recoveryRamp: ramp, logger: logger}
+
+	signals := func() []SignalWithContext {
+		return []SignalWithContext{{Signal: strategy.Signal{RunnerID: uuid.New(), Stake: 20}}}
+	}
+	assert.Equal(t, 20.0, orchestrator.applyRecoveryRamp(context.Background(), signals())[0].Signal.Stake)
+
+	// Trip on a loss streak, then close the breaker
+	for i := 0; i < 3; i++ {
+		cb.RecordBetResult(losingBet(10), 1000-float64(i+1)*10)
+	}
+	assert.True(t, cb.IsOpen())
+	cb.Reset()
+
+	assert.Equal(t, 5.0, orchestrator.applyRecoveryRamp(context.Background(), signals())[0].Signal.Stake, "stakes are reduced right after the breaker closes")
+
+	clock.Advance(time.Minute)
+	cb.RecordBetResult(settledBet(uuid.Nil, clock.Now(), 8), 978)
+	assert.Equal(t, 12.5, orchestrator.applyRecoveryRamp(context.Background(), signals())[0].Signal.Stake)
+
+	cb.RecordBetResult(settledBet(uuid.Nil, clock.Now(), 8), 986)
+	assert.Equal(t, 20.0, orchestrator.applyRecoveryRamp(context.Background(), signals())[0].Signal.Stake, "full size once the recovery bets have held")
+}
@@ -0,0 +1,189 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/yourusername/clever-better/internal/backtest"
+	applogger "github.com/yourusername/clever-better/internal/logger"
+	"github.com/yourusername/clever-better/internal/models"
+	"github.com/yourusername/clever-better/internal/repository"
+)
+
+// ReplayResult summarises a replayed trading day
+type ReplayResult struct {
+	Day              time.Time     `json:"day"`
+	RacesEvaluated   int           `json:"races_evaluated"`
+	RacesSkipped     int           `json:"races_skipped"`
+	BetsPlaced       int           `json:"bets_placed"`
+	BetsSettled      int           `json:"bets_settled"`
+	StartingBankroll float64       `json:"starting_bankroll"`
+	EndingBankroll   float64       `json:"ending_bankroll"`
+	Bets             []*models.Bet `json:"bets"`
+}
+
+// ProfitLoss returns the net profit or loss over the replayed day
+func (r *ReplayResult) ProfitLoss() float64 {
+	return r.EndingBankroll - r.StartingBankroll
+}
+
+// ReplayHarness feeds a recorded trading day through the orchestrator on a
+// simulated clock. Unlike the backtest engine it exercises the full live
+// decision pipeline: ML filtering, risk checks, the circuit breaker and the
+// executor in paper trading mode.
+type ReplayHarness struct {
+	orchestrator *Orchestrator
+	resultRepo   repository.RaceResultRepository
+	logger       *logrus.Logger
+}
+
+// NewReplayHarness creates a replay harness around an orchestrator
+func NewReplayHarness(o *Orchestrator, resultRepo repository.RaceResultRepository, logger *logrus.Logger) (*ReplayHarness, error) {
+	if o == nil {
+		return nil, fmt.Errorf("orchestrator is required")
+	}
+	if resultRepo == nil {
+		return nil, fmt.Errorf("race result repository is required")
+	}
+	if logger == nil {
+		logger = o.logger
+	}
+	return &ReplayHarness{
+		orchestrator: o,
+		resultRepo:   resultRepo,
+		logger:       logger,
+	}, nil
+}
+
+// ReplayDay replays every race scheduled on the given day. The simulated clock
+// is advanced to each race's decision time (scheduled start minus the
+// configured minimum time to start), the orchestrator evaluates the race, and
+// any paper bets are settled against the recorded result.
+func (h *ReplayHarness) ReplayDay(ctx context.Context, day time.Time) (*ReplayResult, error) {
+	o := h.orchestrator
+	if !o.executor.paperTradingMode {
+		return nil, fmt.Errorf("replay requires paper trading mode")
+	}
+
+	dayStart := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, day.Location())
+	dayEnd := dayStart.Add(24 * time.Hour)
+
+	races, err := o.raceRepo.GetByDateRange(ctx, dayStart, dayEnd)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load races: %w", err)
+	}
+	sort.Slice(races, func(i, j int) bool {
+		return races[i].ScheduledStart.Before(races[j].ScheduledStart)
+	})
+
+	// Run the orchestrator on simulated time for the day, restoring its
+	// clock afterwards
+	previousClock := o.clock
+	clock := NewMockClock(dayStart)
+	o.SetClock(clock)
+	o.replaying = true
+	defer func() {
+		o.SetClock(previousClock)
+		o.replaying = false
+	}()
+
+	leadTime := time.Duration(o.config.Trading.MinTimeToStartSeconds) * time.Second
+	bankroll := o.config.Backtest.InitialBankroll
+	result := &ReplayResult{
+		Day:              dayStart,
+		StartingBankroll: bankroll,
+		Bets:             make([]*models.Bet, 0),
+	}
+
+	h.logger.WithFields(logrus.Fields{
+		"day":   dayStart.Format("2006-01-02"),
+		"races": len(races),
+	}).Info("Starting trading day replay")
+
+	for _, race := range races {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		if o.circuitBreaker.IsOpen() {
+			result.RacesSkipped++
+			continue
+		}
+
+		now := race.ScheduledStart.Add(-leadTime)
+		clock.Set(now)
+		raceCtx := applogger.WithRequestID(ctx, applogger.NewRequestID())
+		bets, err := o.processRace(raceCtx, race, now)
+		if err != nil {
+			h.logger.WithContext(raceCtx).WithFields(logrus.Fields{
+				"race_id": race.ID,
+				"error":   err.Error(),
+			}).Warn("Replay failed to evaluate race")
+			o.circuitBreaker.RecordFailure(err)
+			result.RacesSkipped++
+			continue
+		}
+		result.RacesEvaluated++
+		result.BetsPlaced += len(bets)
+
+		settled, err := h.settleRace(ctx, race, bets, &bankroll)
+		if err != nil {
+			return nil, err
+		}
+		result.BetsSettled += settled
+		result.Bets = append(result.Bets, bets...)
+	}
+
+	result.EndingBankroll = bankroll
+
+	h.logger.WithFields(logrus.Fields{
+		"day":             dayStart.Format("2006-01-02"),
+		"races_evaluated": result.RacesEvaluated,
+		"races_skipped":   result.RacesSkipped,
+		"bets_placed":     result.BetsPlaced,
+		"profit_loss":     result.ProfitLoss(),
+	}).Info("Trading day replay completed")
+
+	return result, nil
+}
+
+// settleRace settles the paper bets for a race against its recorded result and
+// feeds each outcome to the circuit breaker, as live settlement would.
+func (h *ReplayHarness) settleRace(ctx context.Context, race *models.Race, bets []*models.Bet, bankroll *float64) (int, error) {
+	if len(bets) == 0 {
+		return 0, nil
+	}
+	o := h.orchestrator
+
+	raceResult, err := h.resultRepo.GetByRaceID(ctx, race.ID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load race result: %w", err)
+	}
+	runners, err := o.runnerRepo.GetByRaceID(ctx, race.ID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load runners: %w", err)
+	}
+	runnerByID := make(map[string]*models.Runner, len(runners))
+	for _, runner := range runners {
+		runnerByID[runner.ID.String()] = runner
+	}
+
+	settled := 0
+	for _, bet := range bets {
+		pnl := backtest.SettleBet(bet, raceResult, runnerByID[bet.RunnerID.String()], o.config.Backtest.CommissionRate)
+		if err := o.betRepo.Update(ctx, bet); err != nil {
+			return settled, fmt.Errorf("failed to update settled bet: %w", err)
+		}
+		if bet.Status != models.BetStatusSettled {
+			continue
+		}
+		*bankroll += pnl
+		o.circuitBreaker.RecordBetResult(bet, *bankroll)
+		settled++
+	}
+
+	return settled, nil
+}
@@ -0,0 +1,235 @@
+package bot
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/clever-better/internal/config"
+	"github.com/yourusername/clever-better/internal/models"
+	"github.com/yourusername/clever-better/internal/repository"
+	"github.com/yourusername/clever-better/internal/strategy"
+)
+
+// replayRaceRepo serves a fixed set of recorded races, bundled with the
+// runners and odds recorded for them when those are set
+type replayRaceRepo struct {
+	repository.RaceRepository
+	races   []*models.Race
+	runners *replayRunnerRepo
+	odds    *replayOddsRepo
+	bundles int
+}
+
+func (r *replayRaceRepo) GetRaceBundle(ctx context.Context, raceID uuid.UUID, oddsSince time.Time) (*models.RaceBundle, error) {
+	r.bundles++
+	for _, race := range r.races {
+		if race.ID != raceID {
+			continue
+		}
+		bundle := &models.RaceBundle{Race: race}
+		if r.runners != nil {
+			bundle.Runners = r.runners.runners[raceID]
+		}
+		if r.odds != nil {
+			bundle.Odds, _ = r.odds.GetByRaceID(ctx, raceID, oddsSince, race.ScheduledStart)
+		}
+		return bundle, nil
+	}
+	return nil, models.ErrNotFound
+}
+
+func (r *replayRaceRepo) GetByDateRange(ctx context.Context, start, end time.Time) ([]*models.Race, error) {
+	out := make([]*models.Race, 0, len(r.races))
+	for _, race := range r.races {
+		if !race.ScheduledStart.Before(start) && race.ScheduledStart.Before(end) {
+			out = append(out, race)
+		}
+	}
+	return out, nil
+}
+
+// replayRunnerRepo serves recorded runners keyed by race
+type replayRunnerRepo struct {
+	repository.RunnerRepository
+	runners map[uuid.UUID][]*models.Runner
+}
+
+func (r *replayRunnerRepo) GetByRaceID(ctx context.Context, raceID uuid.UUID) ([]*models.Runner, error) {
+	return r.runners[raceID], nil
+}
+
+// replayOddsRepo serves recorded odds and honours the requested time window
+type replayOddsRepo struct {
+	repository.OddsRepository
+	odds map[uuid.UUID][]*models.OddsSnapshot
+}
+
+func (r *replayOddsRepo) GetByRaceID(ctx context.Context, raceID uuid.UUID, start, end time.Time) ([]*models.OddsSnapshot, error) {
+	out := make([]*models.OddsSnapshot, 0)
+	for _, snapshot := range r.odds[raceID] {
+		if snapshot.Time.Before(start) || snapshot.Time.After(end) {
+			continue
+		}
+		out = append(out, snapshot)
+	}
+	return out, nil
+}
+
+// replayResultRepo serves recorded race results
+type replayResultRepo struct {
+	repository.RaceResultRepository
+	results map[uuid.UUID]*models.RaceResult
+}
+
+func (r *replayResultRepo) GetByRaceID(ctx context.Context, raceID uuid.UUID) (*models.RaceResult, error) {
+	result, ok := r.results[raceID]
+	if !ok {
+		return nil, models.ErrNotFound
+	}
+	return result, nil
+}
+
+// favouriteBackStrategy backs trap 1 at the latest visible back price
+type favouriteBackStrategy struct{}
+
+func (s *favouriteBackStrategy) Name() string { return "favourite_back" }
+
+func (s *favouriteBackStrategy) Evaluate(ctx context.Context, strategyCtx strategy.Context) ([]strategy.Signal, error) {
+	for _, runner := range strategyCtx.Runners {
+		if runner.TrapNumber != 1 {
+			continue
+		}
+		var latest *models.OddsSnapshot
+		for _, snapshot := range strategyCtx.OddsHistory {
+			if snapshot.RunnerID == runner.ID && snapshot.Time.After(strategyCtx.CurrentTime) {
+				return nil, assert.AnError
+			}
+			if snapshot.RunnerID == runner.ID && (latest == nil || snapshot.Time.After(latest.Time)) {
+				latest = snapshot
+			}
+		}
+		if latest == nil || latest.BackPrice == nil {
+			return nil, nil
+		}
+		return []strategy.Signal{{
+			RunnerID:   runner.ID,
+			Side:       models.BetSideBack,
+			Odds:       *latest.BackPrice,
+			Stake:      10,
+			Confidence: 0.5,
+		}}, nil
+	}
+	return nil, nil
+}
+
+func (s *favouriteBackStrategy) ShouldBet(signal strategy.Signal) bool { return true }
+
+func (s *favouriteBackStrategy) CalculateStake(signal strategy.Signal, bankroll float64) float64 {
+	return signal.Stake
+}
+
+func (s *favouriteBackStrategy) GetParameters() map[string]interface{} { return nil }
+
+func TestReplayDayPlacesPaperBetsAndMovesEquity(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	day := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	cfg := &config.Config{
+		Trading: config.TradingConfig{
+			MaxStakePerBet:        100,
+			MaxExposure:           500,
+			MaxDailyLoss:          200,
+			MinTimeToStartSeconds: 60,
+		},
+		Backtest: config.BacktestConfig{
+			InitialBankroll: 1000,
+			CommissionRate:  0.05,
+		},
+	}
+
+	races := &replayRaceRepo{}
+	runners := &replayRunnerRepo{runners: make(map[uuid.UUID][]*models.Runner)}
+	odds := &replayOddsRepo{odds: make(map[uuid.UUID][]*models.OddsSnapshot)}
+	results := &replayResultRepo{results: make(map[uuid.UUID]*models.RaceResult)}
+
+	// Trap 1 wins the first two races and loses the last two. A late price
+	// after the decision time must never be visible to the strategy.
+	winners := []int{1, 1, 2, 3}
+	for i, winner := range winners {
+		start := day.Add(time.Duration(12+i) * time.Hour)
+		race := &models.Race{ID: uuid.New(), ScheduledStart: start, Track: "Romford", Status: "scheduled"}
+		races.races = append(races.races, race)
+
+		fav := &models.Runner{ID: uuid.New(), RaceID: race.ID, TrapNumber: 1, Name: "Fav"}
+		other := &models.Runner{ID: uuid.New(), RaceID: race.ID, TrapNumber: 2, Name: "Other"}
+		runners.runners[race.ID] = []*models.Runner{fav, other}
+
+		early, late := 3.0, 1.5
+		odds.odds[race.ID] = []*models.OddsSnapshot{
+			{Time: start.Add(-10 * time.Minute), RaceID: race.ID, RunnerID: fav.ID, BackPrice: &early},
+			{Time: start.Add(-30 * time.Second), RaceID: race.ID, RunnerID: fav.ID, BackPrice: &late},
+		}
+
+		winnerTrap := winner
+		results.results[race.ID] = &models.RaceResult{
+			Time:       start.Add(2 * time.Minute),
+			RaceID:     race.ID,
+			WinnerTrap: &winnerTrap,
+			Status:     "completed",
+		}
+	}
+
+	// A race on the following day must not be replayed
+	races.races = append(races.races, &models.Race{ID: uuid.New(), ScheduledStart: day.Add(30 * time.Hour)})
+
+	betRepo := new(MockBetRepository)
+	betRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+	betRepo.On("Update", mock.Anything, mock.Anything).Return(nil)
+
+	riskManager := NewRiskManager(&cfg.Trading, betRepo, logger)
+	orchestrator := &Orchestrator{
+		config:      cfg,
+		raceRepo:    races,
+		runnerRepo:  runners,
+		oddsRepo:    odds,
+		betRepo:     betRepo,
+		riskManager: riskManager,
+		executor:    NewExecutor(nil, betRepo, riskManager, true, false, logger, nil),
+		circuitBreaker: NewCircuitBreaker(CircuitBreakerConfig{
+			MaxConsecutiveLosses: 5,
+			MaxDrawdownPercent:   0.5,
+			MaxFailureCount:      5,
+			FailureTimeWindow:    time.Minute,
+			CooldownPeriod:       time.Minute,
+		}, logger),
+		activeStrategies: map[uuid.UUID]strategy.Strategy{uuid.New(): &favouriteBackStrategy{}},
+		logger:           logger,
+	}
+
+	harness, err := NewReplayHarness(orchestrator, results, logger)
+	require.NoError(t, err)
+
+	result, err := harness.ReplayDay(context.Background(), day.Add(9*time.Hour))
+	require.NoError(t, err)
+
+	assert.Equal(t, 4, result.RacesEvaluated)
+	assert.Equal(t, 4, result.BetsPlaced)
+	assert.Equal(t, 4, result.BetsSettled)
+	for _, bet := range result.Bets {
+		assert.Equal(t, 3.0, bet.Odds, "bets must use the price visible at decision time")
+		assert.Equal(t, models.BetStatusSettled, bet.Status)
+	}
+
+	// Two winners at 3.0 net of 5% commission, two losers at 10 each
+	assert.Equal(t, 1000.0, result.StartingBankroll)
+	assert.InDelta(t, 18.0, result.ProfitLoss(), 1e-9)
+	assert.NotEqual(t, result.StartingBankroll, result.EndingBankroll)
+	betRepo.AssertNumberOfCalls(t, "Create", 4)
+}
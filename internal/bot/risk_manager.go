@@ -7,11 +7,18 @@ import (
 	"sync"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
 	"github.com/yourusername/clever-better/internal/config"
+	"github.com/yourusername/clever-better/internal/models"
 	"github.com/yourusername/clever-better/internal/repository"
+	"github.com/yourusername/clever-better/internal/strategy"
 )
 
+// stakingHistoryLookback bounds how far back bet history is loaded for
+// staking plans that size stakes from recent results
+const stakingHistoryLookback = 30 * 24 * time.Hour
+
 // RiskMetrics represents current risk exposure and limits
 type RiskMetrics struct {
 	CurrentExposure   float64   `json:"current_exposure"`
@@ -21,6 +28,18 @@ type RiskMetrics struct {
 	RemainingCapacity float64   `json:"remaining_capacity"`
 	BetsToday         int       `json:"bets_today"`
 	LastUpdate        time.Time `json:"last_update"`
+
+	// ReservedExposure is stake held for signals that passed their risk check
+	// but have no bet record yet
+	ReservedExposure float64 `json:"reserved_exposure"`
+
+	// Allocations is each strategy's bankroll slice when allocation is enabled
+	Allocations map[uuid.UUID]float64 `json:"allocations,omitempty"`
+	// AccountExposure is the open stake on each Betfair account
+	AccountExposure map[string]float64 `json:"account_exposure,omitempty"`
+	// Portfolio is the correlation-aware risk across strategies, set by the
+	// orchestrator from the monitor's last calculation
+	Portfolio *PortfolioRisk `json:"portfolio,omitempty"`
 }
 
 // RiskManager handles position sizing and risk limit validation
@@ -28,27 +47,90 @@ type RiskManager struct {
 	config             *config.TradingConfig
 	betRepo            repository.BetRepository
 	currentExposure    float64
+	strategyExposure   map[uuid.UUID]float64
+	accountExposure    map[string]float64
+	reservations       map[ExposureReservation]exposureReservation
+	lastReservation    ExposureReservation
+	allocator          *BankrollAllocator
 	dailyLoss          float64
 	dailyLossResetTime time.Time
+	clock              Clock
 	mu                 sync.RWMutex
 	logger             *logrus.Logger
 }
 
 // NewRiskManager creates a new risk manager
 func NewRiskManager(cfg *config.TradingConfig, betRepo repository.BetRepository, logger *logrus.Logger) *RiskManager {
-	now := time.Now()
-	resetTime := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, now.Location())
+	clock := RealClock{}
 
 	return &RiskManager{
 		config:             cfg,
 		betRepo:            betRepo,
 		currentExposure:    0,
+		strategyExposure:   make(map[uuid.UUID]float64),
+		accountExposure:    make(map[string]float64),
+		reservations:       make(map[ExposureReservation]exposureReservation),
 		dailyLoss:          0,
-		dailyLossResetTime: resetTime,
+		dailyLossResetTime: nextMidnight(clock.Now()),
+		clock:              clock,
 		logger:             logger,
 	}
 }
 
+// SetClock replaces the clock used for the daily loss reset and bet counts.
+// The next reset is rescheduled for midnight after the new clock's time.
+func (rm *RiskManager) SetClock(clock Clock) {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+	rm.clock = clockOrReal(clock)
+	rm.dailyLossResetTime = nextMidnight(rm.clock.Now())
+}
+
+// Now returns the risk manager's current time
+func (rm *RiskManager) Now() time.Time {
+	rm.mu.RLock()
+	defer rm.mu.RUnlock()
+	return rm.clock.Now()
+}
+
+// nextMidnight returns the start of the day after t
+func nextMidnight(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+}
+
+// SetAllocator sizes and limits each strategy's bets against its own slice
+// of the bankroll. A nil allocator leaves every strategy on the global pool.
+func (rm *RiskManager) SetAllocator(allocator *BankrollAllocator) {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+	rm.allocator = allocator
+}
+
+// StrategyBankroll returns the bankroll a strategy stakes from: its
+// allocation when allocation is enabled, otherwise the global bankroll
+func (rm *RiskManager) StrategyBankroll(strategyID uuid.UUID, bankroll float64) float64 {
+	rm.mu.RLock()
+	allocator := rm.allocator
+	rm.mu.RUnlock()
+
+	if allocator == nil {
+		return bankroll
+	}
+	allocation, _ := allocator.Allocation(strategyID)
+	return allocation
+}
+
+// allocationHeadroom is how much more a strategy may have at risk. The
+// boolean is false when allocation is disabled. Callers must hold rm.mu.
+func (rm *RiskManager) allocationHeadroom(strategyID uuid.UUID) (float64, bool) {
+	if rm.allocator == nil {
+		return 0, false
+	}
+	allocation, _ := rm.allocator.Allocation(strategyID)
+	exposure := rm.strategyExposure[strategyID] + rm.reservedLocked(&strategyID)
+	return math.Max(allocation-exposure, 0), true
+}
+
 // CalculatePositionSize calculates stake using Kelly Criterion with fractional sizing
 func (rm *RiskManager) CalculatePositionSize(odds float64, bankroll float64, confidence float64, edgeEstimate float64) (float64, error) {
 	rm.mu.RLock()
@@ -80,8 +162,8 @@ func (rm *RiskManager) CalculatePositionSize(odds float64, bankroll float64, con
 		return 0, nil
 	}
 
-	// Calculate stake
-	stake := bankroll * fractionalKelly
+	// Calculate stake, scaled down for confidence near the floor
+	stake := bankroll * fractionalKelly * confidenceMultiplier(rm.config.ConfidenceScaling, confidence)
 
 	// Apply maximum stake limit
 	if stake > rm.config.MaxStakePerBet {
@@ -114,33 +196,112 @@ func (rm *RiskManager) CalculatePositionSize(odds float64, bankroll float64, con
 	return stake, nil
 }
 
-// CheckRiskLimits validates proposed stake against risk limits
+// confidenceMultiplier returns the share of the full stake bet at a
+// confidence. Threshold mode always stakes in full, leaving the cut-off to
+// the confidence threshold. Linear mode rises from zero at the floor to full
+// at FullAt.
+func confidenceMultiplier(cfg config.ConfidenceScalingConfig, confidence float64) float64 {
+	if cfg.Mode != config.ConfidenceScalingLinear || cfg.FullAt <= cfg.Floor {
+		return 1
+	}
+	if confidence <= cfg.Floor {
+		return 0
+	}
+	return math.Min((confidence-cfg.Floor)/(cfg.FullAt-cfg.Floor), 1)
+}
+
+// minConfidence returns the lowest confidence bet on: the floor in linear
+// confidence scaling, otherwise the confidence threshold
+func minConfidence(cfg *config.TradingConfig) float64 {
+	if cfg.ConfidenceScaling.Mode == config.ConfidenceScalingLinear {
+		return cfg.ConfidenceScaling.Floor
+	}
+	return cfg.MinConfidenceThreshold
+}
+
+// SizeStake applies a strategy's staking plan to a signal. Plans that need
+// recent results are given the strategy's latest settled bets. With bankroll
+// allocation the plan stakes from the strategy's slice instead of bankroll.
+// The stake is capped at the maximum stake per bet and the strategy's
+// remaining allocation.
+func (rm *RiskManager) SizeStake(
+	ctx context.Context,
+	plan strategy.StakingPlan,
+	signal strategy.Signal,
+	strategyID uuid.UUID,
+	bankroll float64,
+	now time.Time,
+) (float64, error) {
+	var history []*models.Bet
+	if aware, ok := plan.(strategy.HistoryAware); ok && aware.HistoryLength() > 0 {
+		bets, err := rm.betRepo.GetByStrategyID(ctx, strategyID, now.Add(-stakingHistoryLookback), now)
+		if err != nil {
+			return 0, fmt.Errorf("failed to get bet history: %w", err)
+		}
+		history = make([]*models.Bet, 0, aware.HistoryLength())
+		for _, bet := range bets {
+			if bet.Status != models.BetStatusSettled {
+				continue
+			}
+			history = append(history, bet)
+			if len(history) == aware.HistoryLength() {
+				break
+			}
+		}
+	}
+
+	stake := plan.Stake(signal, rm.StrategyBankroll(strategyID, bankroll), history)
+	stake *= confidenceMultiplier(rm.config.ConfidenceScaling, signal.Confidence)
+	if stake > rm.config.MaxStakePerBet {
+		stake = rm.config.MaxStakePerBet
+	}
+
+	rm.mu.RLock()
+	headroom, ok := rm.allocationHeadroom(strategyID)
+	rm.mu.RUnlock()
+	if ok && stake > headroom {
+		stake = headroom
+	}
+	return stake, nil
+}
+
+// CheckRiskLimits validates proposed stake against risk limits. Stake
+// reserved for in-flight signals counts towards exposure.
 func (rm *RiskManager) CheckRiskLimits(ctx context.Context, proposedStake float64) error {
+	rm.resetDailyLossIfDue(ctx)
+
 	rm.mu.RLock()
 	defer rm.mu.RUnlock()
+	return rm.checkRiskLimitsLocked(proposedStake)
+}
+
+// resetDailyLossIfDue reloads the daily loss once the reset time has passed
+func (rm *RiskManager) resetDailyLossIfDue(ctx context.Context) {
+	rm.mu.RLock()
+	due := rm.clock.Now().After(rm.dailyLossResetTime)
+	rm.mu.RUnlock()
 
-	// Check if daily loss reset is needed
-	if time.Now().After(rm.dailyLossResetTime) {
-		rm.mu.RUnlock()
+	if due {
 		if err := rm.UpdateDailyLoss(ctx); err != nil {
-			rm.mu.RLock()
 			rm.logger.WithError(err).Error("Failed to update daily loss")
-		} else {
-			rm.mu.RLock()
 		}
 	}
+}
 
+// checkRiskLimitsLocked validates a stake against the limits. Callers must hold rm.mu.
+func (rm *RiskManager) checkRiskLimitsLocked(proposedStake float64) error {
 	// Check max stake per bet
 	if proposedStake > rm.config.MaxStakePerBet {
 		return fmt.Errorf("proposed stake %.2f exceeds max stake per bet %.2f", 
 			proposedStake, rm.config.MaxStakePerBet)
 	}
 
-	// Check max exposure
-	newExposure := rm.currentExposure + proposedStake
+	// Check max exposure, including stake reserved for in-flight signals
+	reserved := rm.reservedLocked(nil)
+	newExposure := rm.currentExposure + reserved + proposedStake
 	if newExposure > rm.config.MaxExposure {
-		return fmt.Errorf("proposed stake would exceed max exposure (current: %.2f, proposed: %.2f, max: %.2f)", 
-			rm.currentExposure, proposedStake, rm.config.MaxExposure)
+		return fmt.Errorf("proposed stake would exceed max exposure (current: %.2f, reserved: %.2f, proposed: %.2f, max: %.2f)", 
+			rm.currentExposure, reserved, proposedStake, rm.config.MaxExposure)
 	}
 
 	// Check max daily loss
@@ -152,6 +313,7 @@ func (rm *RiskManager) CheckRiskLimits(ctx context.Context, proposedStake float6
 	rm.logger.WithFields(logrus.Fields{
 		"proposed_stake":    proposedStake,
 		"current_exposure":  rm.currentExposure,
+		"reserved_exposure": reserved,
 		"daily_loss":        rm.dailyLoss,
 		"max_exposure":      rm.config.MaxExposure,
 		"max_daily_loss":    rm.config.MaxDailyLoss,
@@ -160,6 +322,186 @@ func (rm *RiskManager) CheckRiskLimits(ctx context.Context, proposedStake float6
 	return nil
 }
 
+// CheckStrategyAllocation rejects a stake that would take a strategy's open
+// exposure past its bankroll allocation. It always passes when allocation is
+// disabled.
+func (rm *RiskManager) CheckStrategyAllocation(strategyID uuid.UUID, proposedStake float64) error {
+	rm.mu.RLock()
+	defer rm.mu.RUnlock()
+	return rm.checkStrategyAllocationLocked(strategyID, proposedStake)
+}
+
+// checkStrategyAllocationLocked validates a stake against the strategy's
+// allocation, counting its reservations. Callers must hold rm.mu.
+func (rm *RiskManager) checkStrategyAllocationLocked(strategyID uuid.UUID, proposedStake float64) error {
+	headroom, ok := rm.allocationHeadroom(strategyID)
+	if !ok || proposedStake <= headroom {
+		return nil
+	}
+	allocation, _ := rm.allocator.Allocation(strategyID)
+	return fmt.Errorf("proposed stake would exceed strategy allocation (strategy: %s, exposure: %.2f, proposed: %.2f, allocation: %.2f)",
+		strategyID, rm.strategyExposure[strategyID]+rm.reservedLocked(&strategyID), proposedStake, allocation)
+}
+
+// ExposureReservation identifies stake held by ReserveExposure. The zero
+// value holds nothing.
+type ExposureReservation uint64
+
+// exposureReservation is stake held for a signal between its risk check and
+// the creation of its bet record
+type exposureReservation struct {
+	strategyID uuid.UUID
+	stake      float64
+	expiresAt  time.Time
+}
+
+// ReserveExposure atomically checks a stake against the risk limits and the
+// strategy's allocation and holds it, so concurrent signals see the reduced
+// capacity. The reservation must be confirmed once the bet is recorded, or
+// released. Unconfirmed reservations lapse after trading.exposure_reservation_ttl
+// seconds so a stuck placement cannot hold capacity forever.
+func (rm *RiskManager) ReserveExposure(ctx context.Context, strategyID uuid.UUID, stake float64) (ExposureReservation, error) {
+	rm.resetDailyLossIfDue(ctx)
+
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+
+	now := rm.clock.Now()
+	for id, reservation := range rm.reservations {
+		if reservation.lapsed(now) {
+			delete(rm.reservations, id)
+		}
+	}
+
+	if err := rm.checkRiskLimitsLocked(stake); err != nil {
+		return 0, err
+	}
+	if err := rm.checkStrategyAllocationLocked(strategyID, stake); err != nil {
+		return 0, err
+	}
+
+	rm.lastReservation++
+	reservation := exposureReservation{strategyID: strategyID, stake: stake}
+	if ttl := rm.config.ExposureReservationTTL; ttl > 0 {
+		reservation.expiresAt = now.Add(time.Duration(ttl) * time.Second)
+	}
+	rm.reservations[rm.lastReservation] = reservation
+	return rm.lastReservation, nil
+}
+
+// ConfirmReservation turns a reservation into exposure once its bet is
+// recorded against account. Lapsed or released reservations are still
+// recorded, since the bet exists.
+func (rm *RiskManager) ConfirmReservation(id ExposureReservation, strategyID uuid.UUID, account string, stake float64) {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+	delete(rm.reservations, id)
+	rm.recordPlacementLocked(strategyID, account, stake)
+}
+
+// ReleaseReservation frees a reservation whose signal was not placed.
+// Releasing a confirmed or unknown reservation does nothing.
+func (rm *RiskManager) ReleaseReservation(id ExposureReservation) {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+	delete(rm.reservations, id)
+}
+
+// reservedLocked sums unlapsed reservations, for one strategy when strategyID
+// is set. Callers must hold rm.mu.
+func (rm *RiskManager) reservedLocked(strategyID *uuid.UUID) float64 {
+	now := rm.clock.Now()
+	total := 0.0
+	for _, reservation := range rm.reservations {
+		if reservation.lapsed(now) || (strategyID != nil && reservation.strategyID != *strategyID) {
+			continue
+		}
+		total += reservation.stake
+	}
+	return total
+}
+
+// lapsed reports whether an unconfirmed reservation has expired
+func (r exposureReservation) lapsed(now time.Time) bool {
+	return !r.expiresAt.IsZero() && !now.Before(r.expiresAt)
+}
+
+// RecordPlacement adds a newly placed bet to exposure so later signals in the
+// same pass see it before the next UpdateExposure
+func (rm *RiskManager) RecordPlacement(strategyID uuid.UUID, account string, stake float64) {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+	rm.recordPlacementLocked(strategyID, account, stake)
+}
+
+// recordPlacementLocked adds a placed bet to exposure. Callers must hold rm.mu.
+func (rm *RiskManager) recordPlacementLocked(strategyID uuid.UUID, account string, stake float64) {
+	rm.currentExposure += stake
+	rm.strategyExposure[strategyID] += stake
+	rm.accountExposure[accountKey(account)] += stake
+}
+
+// AccountExposures returns the open stake on each Betfair account
+func (rm *RiskManager) AccountExposures() map[string]float64 {
+	rm.mu.RLock()
+	defer rm.mu.RUnlock()
+	return rm.accountExposureLocked()
+}
+
+// accountExposureLocked copies account exposure. Callers must hold rm.mu.
+func (rm *RiskManager) accountExposureLocked() map[string]float64 {
+	out := make(map[string]float64, len(rm.accountExposure))
+	for account, exposure := range rm.accountExposure {
+		out[account] = exposure
+	}
+	return out
+}
+
+// accountKey maps a bet's account to its exposure key; bets without an
+// account were placed through the primary
+func accountKey(account string) string {
+	if account == "" {
+		return PrimaryAccountName
+	}
+	return account
+}
+
+// CheckBetCounts rejects a bet once the per-race or per-day bet limit is
+// reached. Counts come from the bet repository; a limit of zero is unlimited.
+func (rm *RiskManager) CheckBetCounts(ctx context.Context, raceID uuid.UUID, now time.Time) error {
+	if rm.config.MaxBetsPerRace > 0 {
+		raceBets, err := rm.betRepo.GetByRaceID(ctx, raceID)
+		if err != nil {
+			return fmt.Errorf("failed to get race bets: %w", err)
+		}
+
+		placed := 0
+		for _, bet := range raceBets {
+			if bet.Status != models.BetStatusCancelled {
+				placed++
+			}
+		}
+		if placed >= rm.config.MaxBetsPerRace {
+			return fmt.Errorf("bets per race limit reached (race: %s, placed: %d, max: %d)",
+				raceID, placed, rm.config.MaxBetsPerRace)
+		}
+	}
+
+	if rm.config.MaxBetsPerDay > 0 {
+		startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+		placed, err := rm.betRepo.CountPlacedBetween(ctx, startOfDay, startOfDay.AddDate(0, 0, 1))
+		if err != nil {
+			return fmt.Errorf("failed to count bets placed today: %w", err)
+		}
+		if placed >= rm.config.MaxBetsPerDay {
+			return fmt.Errorf("bets per day limit reached (placed: %d, max: %d)",
+				placed, rm.config.MaxBetsPerDay)
+		}
+	}
+
+	return nil
+}
+
 // UpdateExposure recalculates current exposure from pending bets
 func (rm *RiskManager) UpdateExposure(ctx context.Context) error {
 	pendingBets, err := rm.betRepo.GetPendingBets(ctx)
@@ -171,11 +513,17 @@ func (rm *RiskManager) UpdateExposure(ctx context.Context) error {
 	defer rm.mu.Unlock()
 
 	totalExposure := 0.0
+	byStrategy := make(map[uuid.UUID]float64)
+	byAccount := make(map[string]float64)
 	for _, bet := range pendingBets {
 		totalExposure += bet.Stake
+		byStrategy[bet.StrategyID] += bet.Stake
+		byAccount[accountKey(bet.Account)] += bet.Stake
 	}
 
 	rm.currentExposure = totalExposure
+	rm.strategyExposure = byStrategy
+	rm.accountExposure = byAccount
 
 	rm.logger.WithFields(logrus.Fields{
 		"pending_bets":      len(pendingBets),
@@ -189,7 +537,7 @@ func (rm *RiskManager) UpdateExposure(ctx context.Context) error {
 
 // UpdateDailyLoss calculates P&L for current day and resets at midnight
 func (rm *RiskManager) UpdateDailyLoss(ctx context.Context) error {
-	now := time.Now()
+	now := rm.Now()
 	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
 	endOfDay := startOfDay.Add(24 * time.Hour)
 
@@ -216,7 +564,7 @@ func (rm *RiskManager) UpdateDailyLoss(ctx context.Context) error {
 	}
 
 	// Reset time for next day
-	rm.dailyLossResetTime = time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, now.Location())
+	rm.dailyLossResetTime = nextMidnight(now)
 
 	rm.logger.WithFields(logrus.Fields{
 		"settled_bets_today": len(settledBets),
@@ -252,12 +600,21 @@ func (rm *RiskManager) GetRiskMetrics() RiskMetrics {
 	rm.mu.RLock()
 	defer rm.mu.RUnlock()
 
+	var allocations map[uuid.UUID]float64
+	if rm.allocator != nil {
+		allocations = rm.allocator.Allocations()
+	}
+	reserved := rm.reservedLocked(nil)
+
 	return RiskMetrics{
 		CurrentExposure:   rm.currentExposure,
 		DailyLoss:         rm.dailyLoss,
 		MaxExposure:       rm.config.MaxExposure,
 		MaxDailyLoss:      rm.config.MaxDailyLoss,
-		RemainingCapacity: rm.config.MaxExposure - rm.currentExposure,
-		LastUpdate:        time.Now(),
+		RemainingCapacity: rm.config.MaxExposure - rm.currentExposure - reserved,
+		ReservedExposure:  reserved,
+		LastUpdate:        rm.clock.Now(),
+		Allocations:       allocations,
+		AccountExposure:   rm.accountExposureLocked(),
 	}
 }
@@ -12,6 +12,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"github.com/yourusername/clever-better/internal/config"
 	"github.com/yourusername/clever-better/internal/models"
+	"github.com/yourusername/clever-better/internal/strategy"
 )
 
 // MockBetRepository is a mock implementation of BetRepository
@@ -72,6 +73,11 @@ func (m *MockBetRepository) GetSettledBets(ctx context.Context, start, end time.
 	return args.Get(0).([]*models.Bet), args.Error(1)
 }
 
+func (m *MockBetRepository) CountPlacedBetween(ctx context.Context, start, end time.Time) (int, error) {
+	args := m.Called(ctx, start, end)
+	return args.Int(0), args.Error(1)
+}
+
 func (m *MockBetRepository) GetByBetfairBetID(ctx context.Context, betID string) (*models.Bet, error) {
 	args := m.Called(ctx, betID)
 	if args.Get(0) == nil {
@@ -417,6 +423,73 @@ func TestCalculatePositionSizeMinimumStake(t *testing.T) {
 	assert.Equal(t, 0.0, stake, "stake below minimum should be zero")
 }
 
+func TestCalculatePositionSizeConfidenceScaling(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	cfg := &config.TradingConfig{
+		MaxStakePerBet: 1000.0,
+		MaxExposure:    5000.0,
+		MaxDailyLoss:   200.0,
+	}
+	rm := NewRiskManager(cfg, new(MockBetRepository), logger)
+
+	// The default threshold mode stakes the full Kelly stake at any confidence
+	stake, err := rm.CalculatePositionSize(3.0, 1000.0, 0.45, 0.1)
+	require.NoError(t, err)
+	assert.InDelta(t, 43.75, stake, 1e-9)
+
+	cfg.ConfidenceScaling = config.ConfidenceScalingConfig{Mode: config.ConfidenceScalingLinear, Floor: 0.5, FullAt: 0.7}
+
+	stake, err = rm.CalculatePositionSize(3.0, 1000.0, 0.45, 0.1)
+	require.NoError(t, err)
+	assert.Zero(t, stake, "below the floor nothing is bet")
+
+	// Quarter Kelly at 0.6 is 100, scaled by half between floor and full
+	stake, err = rm.CalculatePositionSize(3.0, 1000.0, 0.6, 0.1)
+	require.NoError(t, err)
+	assert.InDelta(t, 50.0, stake, 1e-9)
+
+	stake, err = rm.CalculatePositionSize(3.0, 1000.0, 0.8, 0.1)
+	require.NoError(t, err)
+	assert.InDelta(t, 175.0, stake, 1e-9, "above full_at the stake is not scaled")
+
+	// Stake rises steadily across the scaled range without a cliff
+	previous, err := rm.CalculatePositionSize(3.0, 1000.0, 0.55, 0.1)
+	require.NoError(t, err)
+	for confidence := 0.56; confidence <= 0.7; confidence += 0.01 {
+		stake, err := rm.CalculatePositionSize(3.0, 1000.0, confidence, 0.1)
+		require.NoError(t, err)
+		assert.Greater(t, stake, previous, "confidence %.2f", confidence)
+		assert.Less(t, stake-previous, 15.0, "confidence %.2f", confidence)
+		previous = stake
+	}
+}
+
+func TestSizeStakeScalesWithConfidence(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	cfg := &config.TradingConfig{
+		MaxStakePerBet:    100.0,
+		MaxExposure:       500.0,
+		MaxDailyLoss:      200.0,
+		ConfidenceScaling: config.ConfidenceScalingConfig{Mode: config.ConfidenceScalingLinear, Floor: 0.4, FullAt: 0.6},
+	}
+	rm := NewRiskManager(cfg, new(MockBetRepository), logger)
+	ctx := context.Background()
+
+	stake, err := rm.SizeStake(ctx, strategy.LevelStake{Amount: 20}, strategy.Signal{Confidence: 0.45}, uuid.New(), 1000, time.Now())
+	require.NoError(t, err)
+	assert.InDelta(t, 5.0, stake, 1e-9)
+
+	stake, err = rm.SizeStake(ctx, strategy.LevelStake{Amount: 20}, strategy.Signal{Confidence: 0.39}, uuid.New(), 1000, time.Now())
+	require.NoError(t, err)
+	assert.Zero(t, stake)
+
+	assert.Equal(t, 0.4, minConfidence(cfg), "the floor replaces the confidence threshold")
+}
+
 func TestUpdateExposureError(t *testing.T) {
 	logger := logrus.New()
 	logger.SetLevel(logrus.ErrorLevel)
@@ -482,3 +555,179 @@ func TestCheckRiskLimitsResetsDailyLoss(t *testing.T) {
 	assert.True(t, rm.dailyLossResetTime.After(time.Now()))
 	mockRepo.AssertExpectations(t)
 }
+
+func TestSizeStakeFeedsHistoryToRecoveryPlan(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	cfg := &config.TradingConfig{
+		MaxStakePerBet: 15.0,
+		MaxExposure:    500.0,
+		MaxDailyLoss:   200.0,
+	}
+
+	mockRepo := new(MockBetRepository)
+	rm := NewRiskManager(cfg, mockRepo, logger)
+
+	ctx := context.Background()
+	strategyID := uuid.New()
+	now := time.Now()
+
+	loss := -2.0
+	settled := func() *models.Bet {
+		return &models.Bet{ID: uuid.New(), Status: models.BetStatusSettled, ProfitLoss: &loss}
+	}
+	// Most recent first; the pending bet is not part of the loss run
+	history := []*models.Bet{
+		{ID: uuid.New(), Status: models.BetStatusPending},
+		settled(), settled(), settled(),
+	}
+	mockRepo.On("GetByStrategyID", ctx, strategyID, mock.Anything, mock.Anything).Return(history, nil)
+
+	signal := strategy.Signal{Odds: 3.0, Confidence: 0.4}
+
+	stake, err := rm.SizeStake(ctx, strategy.FibonacciStake{Unit: 2}, signal, strategyID, 1000, now)
+	require.NoError(t, err)
+	assert.Equal(t, 6.0, stake, "three losses move to the fourth Fibonacci step")
+
+	stake, err = rm.SizeStake(ctx, strategy.FibonacciStake{Unit: 10}, signal, strategyID, 1000, now)
+	require.NoError(t, err)
+	assert.Equal(t, 15.0, stake, "capped at max stake per bet")
+
+	// Plans that ignore history never load it
+	stake, err = rm.SizeStake(ctx, strategy.LevelStake{Amount: 5}, signal, strategyID, 1000, now)
+	require.NoError(t, err)
+	assert.Equal(t, 5.0, stake)
+	mockRepo.AssertNumberOfCalls(t, "GetByStrategyID", 2)
+}
+
+func TestCheckBetCountsPerRace(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	cfg := &config.TradingConfig{
+		MaxStakePerBet: 100.0,
+		MaxExposure:    500.0,
+		MaxDailyLoss:   200.0,
+		MaxBetsPerRace: 2,
+	}
+
+	mockRepo := new(MockBetRepository)
+	rm := NewRiskManager(cfg, mockRepo, logger)
+
+	ctx := context.Background()
+	raceID := uuid.New()
+	placed := make([]*models.Bet, 0, cfg.MaxBetsPerRace)
+	for i := 0; i < cfg.MaxBetsPerRace; i++ {
+		mockRepo.On("GetByRaceID", ctx, raceID).Return(append([]*models.Bet(nil), placed...), nil).Once()
+		require.NoError(t, rm.CheckBetCounts(ctx, raceID, time.Now()))
+		placed = append(placed, &models.Bet{RaceID: raceID, Status: models.BetStatusPending})
+	}
+
+	mockRepo.On("GetByRaceID", ctx, raceID).Return(placed, nil).Once()
+	err := rm.CheckBetCounts(ctx, raceID, time.Now())
+	assert.ErrorContains(t, err, "bets per race limit reached")
+
+	// Cancelled bets do not use up the race allowance
+	withCancelled := []*models.Bet{
+		{RaceID: raceID, Status: models.BetStatusCancelled},
+		{RaceID: raceID, Status: models.BetStatusPending},
+	}
+	mockRepo.On("GetByRaceID", ctx, raceID).Return(withCancelled, nil).Once()
+	assert.NoError(t, rm.CheckBetCounts(ctx, raceID, time.Now()))
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCheckBetCountsPerDayResetsAtMidnight(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	cfg := &config.TradingConfig{
+		MaxStakePerBet: 100.0,
+		MaxExposure:    500.0,
+		MaxDailyLoss:   200.0,
+		MaxBetsPerDay:  3,
+	}
+
+	mockRepo := new(MockBetRepository)
+	rm := NewRiskManager(cfg, mockRepo, logger)
+
+	ctx := context.Background()
+	day := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	nextDay := day.AddDate(0, 0, 1)
+	for i := 0; i < cfg.MaxBetsPerDay; i++ {
+		mockRepo.On("CountPlacedBetween", ctx, day, nextDay).Return(i, nil).Once()
+		require.NoError(t, rm.CheckBetCounts(ctx, uuid.New(), day.Add(18*time.Hour)))
+	}
+
+	mockRepo.On("CountPlacedBetween", ctx, day, nextDay).Return(cfg.MaxBetsPerDay, nil).Once()
+	mockRepo.On("CountPlacedBetween", ctx, nextDay, nextDay.AddDate(0, 0, 1)).Return(0, nil).Once()
+
+	err := rm.CheckBetCounts(ctx, uuid.New(), day.Add(23*time.Hour))
+	assert.ErrorContains(t, err, "bets per day limit reached")
+
+	assert.NoError(t, rm.CheckBetCounts(ctx, uuid.New(), nextDay.Add(time.Hour)))
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCheckBetCountsUnlimitedSkipsRepository(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	cfg := &config.TradingConfig{
+		MaxStakePerBet: 100.0,
+		MaxExposure:    500.0,
+		MaxDailyLoss:   200.0,
+	}
+
+	mockRepo := new(MockBetRepository)
+	rm := NewRiskManager(cfg, mockRepo, logger)
+
+	assert.NoError(t, rm.CheckBetCounts(context.Background(), uuid.New(), time.Now()))
+	mockRepo.AssertNotCalled(t, "GetByRaceID", mock.Anything, mock.Anything)
+	mockRepo.AssertNotCalled(t, "CountPlacedBetween", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestExposureReservationsHoldCapacity(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+	ctx := context.Background()
+
+	rm := NewRiskManager(&config.TradingConfig{
+		MaxStakePerBet:         100.0,
+		MaxExposure:            100.0,
+		MaxDailyLoss:           200.0,
+		ExposureReservationTTL: 60,
+	}, new(MockBetRepository), logger)
+	clock := NewMockClock(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+	rm.SetClock(clock)
+	strategyID := uuid.New()
+
+	first, err := rm.ReserveExposure(ctx, strategyID, 60)
+	require.NoError(t, err)
+	assert.Error(t, rm.CheckRiskLimits(ctx, 50), "reserved stake counts towards exposure")
+	_, err = rm.ReserveExposure(ctx, strategyID, 50)
+	assert.Error(t, err)
+	assert.Equal(t, 60.0, rm.GetRiskMetrics().ReservedExposure)
+	assert.Equal(t, 40.0, rm.GetRiskMetrics().RemainingCapacity)
+
+	// Releasing frees the capacity
+	rm.ReleaseReservation(first)
+	second, err := rm.ReserveExposure(ctx, strategyID, 50)
+	require.NoError(t, err)
+
+	// Confirming moves the stake into exposure; a later release is a no-op
+	rm.ConfirmReservation(second, strategyID, "", 50)
+	rm.ReleaseReservation(second)
+	metrics := rm.GetRiskMetrics()
+	assert.Equal(t, 50.0, metrics.CurrentExposure)
+	assert.Zero(t, metrics.ReservedExposure)
+
+	// Reservations that are never confirmed lapse after the TTL
+	_, err = rm.ReserveExposure(ctx, strategyID, 50)
+	require.NoError(t, err)
+	assert.Error(t, rm.CheckRiskLimits(ctx, 10))
+	clock.Advance(time.Minute)
+	assert.NoError(t, rm.CheckRiskLimits(ctx, 10))
+	assert.Zero(t, rm.GetRiskMetrics().ReservedExposure)
+}
@@ -0,0 +1,35 @@
+package bot
+
+import (
+	"github.com/google/uuid"
+	"github.com/yourusername/clever-better/internal/models"
+)
+
+// findCrossingOrder returns our own unmatched order on the selection that an
+// incoming order would match against, or nil. A lay crosses a back offered at
+// or below its price; a back crosses a lay at or above its price. Placing it
+// would match us against ourselves and pay commission on both sides for no
+// edge. BSP orders take the starting price rather than resting at a price, so
+// they are not compared.
+func findCrossingOrder(runnerID uuid.UUID, side models.BetSide, odds float64, existing []*models.Bet) *models.Bet {
+	for _, bet := range existing {
+		if bet == nil || bet.RunnerID != runnerID || bet.IsBSP {
+			continue
+		}
+		if bet.Status != models.BetStatusPending || bet.Side == side {
+			continue
+		}
+
+		switch side {
+		case models.BetSideLay:
+			if odds >= bet.Odds {
+				return bet
+			}
+		case models.BetSideBack:
+			if odds <= bet.Odds {
+				return bet
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,22 @@
+package bot
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/yourusername/clever-better/internal/models"
+)
+
+func TestFindCrossingOrder(t *testing.T) {
+	runnerID := uuid.New()
+	restingLay := &models.Bet{ID: uuid.New(), RunnerID: runnerID, Side: models.BetSideLay, Odds: 4.0, Status: models.BetStatusPending}
+	bspLay := &models.Bet{ID: uuid.New(), RunnerID: runnerID, Side: models.BetSideLay, Odds: 6.0, IsBSP: true, Status: models.BetStatusPending}
+	existing := []*models.Bet{bspLay, restingLay}
+
+	assert.Equal(t, restingLay, findCrossingOrder(runnerID, models.BetSideBack, 3.8, existing))
+	assert.Equal(t, restingLay, findCrossingOrder(runnerID, models.BetSideBack, 4.0, existing))
+	assert.Nil(t, findCrossingOrder(runnerID, models.BetSideBack, 4.2, existing))
+	assert.Nil(t, findCrossingOrder(uuid.New(), models.BetSideBack, 3.8, existing))
+	assert.Nil(t, findCrossingOrder(runnerID, models.BetSideLay, 3.8, existing))
+}
@@ -0,0 +1,123 @@
+package bot
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"github.com/yourusername/clever-better/internal/models"
+)
+
+// SimulatePath serves on-demand strategy simulations
+const SimulatePath = "/debug/simulate"
+
+var (
+	// ErrStrategyNotActive is returned when simulating a strategy that is not loaded
+	ErrStrategyNotActive = errors.New("strategy is not active")
+	// ErrMarketNotFound is returned when no runners are recorded against a market
+	ErrMarketNotFound = errors.New("market not found")
+)
+
+// SimulationResponse is the JSON body returned by the simulate endpoint
+type SimulationResponse struct {
+	StrategyID uuid.UUID           `json:"strategy_id"`
+	MarketID   string              `json:"market_id"`
+	Signals    []SignalWithContext `json:"signals"`
+}
+
+// SimulateMarket evaluates one active strategy against a single market as it
+// stands now and returns the raw signals, with the strategy's reasoning. No
+// bets are placed, and staking plans, the ML filter and risk checks are not
+// applied.
+func (o *Orchestrator) SimulateMarket(ctx context.Context, strategyID uuid.UUID, marketID string) ([]SignalWithContext, error) {
+	o.mu.RLock()
+	strat, ok := o.activeStrategies[strategyID]
+	o.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrStrategyNotActive, strategyID)
+	}
+
+	runners, err := o.runnerRepo.GetByMarketID(ctx, marketID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load market runners: %w", err)
+	}
+	if len(runners) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrMarketNotFound, marketID)
+	}
+	race, err := o.raceRepo.GetByID(ctx, runners[0].RaceID)
+	if errors.Is(err, models.ErrNotFound) {
+		return nil, fmt.Errorf("%w: %s", ErrMarketNotFound, marketID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load race: %w", err)
+	}
+
+	now := o.now()
+	stratCtx, selections, err := o.strategyContext(ctx, race, now)
+	if err != nil {
+		return nil, err
+	}
+	stratSignals, err := o.evaluateWithTimeout(ctx, strat, stratCtx)
+	if err != nil {
+		return nil, fmt.Errorf("strategy evaluation failed: %w", err)
+	}
+
+	o.logger.WithContext(ctx).WithFields(logrus.Fields{
+		"strategy_id": strategyID,
+		"market_id":   marketID,
+		"race_id":     race.ID,
+		"signals":     len(stratSignals),
+	}).Info("Market simulated")
+
+	return withContext(stratSignals, strategyID, race, selections), nil
+}
+
+// SimulateHandler serves SimulateMarket over HTTP as
+// GET /debug/simulate?strategy_id=<uuid>&market_id=<id>
+func (o *Orchestrator) SimulateHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.Header().Set("Allow", http.MethodGet)
+			writeJSONError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		strategyID, err := uuid.Parse(r.URL.Query().Get("strategy_id"))
+		if err != nil {
+			writeJSONError(w, http.StatusBadRequest, "strategy_id must be a UUID")
+			return
+		}
+		marketID := r.URL.Query().Get("market_id")
+		if marketID == "" {
+			writeJSONError(w, http.StatusBadRequest, "market_id is required")
+			return
+		}
+
+		signals, err := o.SimulateMarket(r.Context(), strategyID, marketID)
+		switch {
+		case errors.Is(err, ErrStrategyNotActive), errors.Is(err, ErrMarketNotFound):
+			writeJSONError(w, http.StatusNotFound, err.Error())
+			return
+		case err != nil:
+			writeJSONError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(SimulationResponse{
+			StrategyID: strategyID,
+			MarketID:   marketID,
+			Signals:    signals,
+		})
+	})
+}
+
+func writeJSONError(w http.ResponseWriter, code int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(map[string]string{"error": message})
+}
@@ -0,0 +1,185 @@
+package bot
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/clever-better/internal/models"
+	"github.com/yourusername/clever-better/internal/repository"
+	"github.com/yourusername/clever-better/internal/strategy"
+)
+
+// simulateRaceRepo serves races by ID
+type simulateRaceRepo struct {
+	repository.RaceRepository
+	races map[uuid.UUID]*models.Race
+}
+
+func (r *simulateRaceRepo) GetByID(ctx context.Context, id uuid.UUID) (*models.Race, error) {
+	race, ok := r.races[id]
+	if !ok {
+		return nil, models.ErrNotFound
+	}
+	return race, nil
+}
+
+// simulateRunnerRepo serves runners by race or by their Betfair market
+type simulateRunnerRepo struct {
+	repository.RunnerRepository
+	runners []*models.Runner
+}
+
+func (r *simulateRunnerRepo) GetByRaceID(ctx context.Context, raceID uuid.UUID) ([]*models.Runner, error) {
+	out := make([]*models.Runner, 0, len(r.runners))
+	for _, runner := range r.runners {
+		if runner.RaceID == raceID {
+			out = append(out, runner)
+		}
+	}
+	return out, nil
+}
+
+func (r *simulateRunnerRepo) GetByMarketID(ctx context.Context, marketID string) ([]*models.Runner, error) {
+	out := make([]*models.Runner, 0, len(r.runners))
+	for _, runner := range r.runners {
+		if selection, ok := runner.BetfairSelection(); ok && selection.MarketID == marketID {
+			out = append(out, runner)
+		}
+	}
+	return out, nil
+}
+
+type simulateFixture struct {
+	orchestrator *Orchestrator
+	betRepo      *MockBetRepository
+	strategyID   uuid.UUID
+	strategy     strategy.Strategy
+	stratCtx     strategy.Context
+	marketID     string
+}
+
+func newSimulateFixture(t *testing.T) *simulateFixture {
+	t.Helper()
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	now := time.Date(2024, 3, 1, 19, 25, 0, 0, time.UTC)
+	marketID := "1.223344556"
+	race := &models.Race{ID: uuid.New(), ScheduledStart: now.Add(5 * time.Minute), Track: "Romford", Status: "scheduled"}
+
+	form := 25.0
+	strong := &models.Runner{ID: uuid.New(), RaceID: race.ID, TrapNumber: 1, Name: "Strong", FormRating: &form}
+	weak := &models.Runner{ID: uuid.New(), RaceID: race.ID, TrapNumber: 2, Name: "Weak"}
+	require.NoError(t, strong.SetBetfairSelection(marketID, 101))
+	require.NoError(t, weak.SetBetfairSelection(marketID, 102))
+
+	back, lay, size := 2.98, 3.02, 100.0
+	weakBack, weakLay := 3.95, 4.1
+	odds := []*models.OddsSnapshot{
+		{Time: now.Add(-time.Minute), RaceID: race.ID, RunnerID: strong.ID, BackPrice: &back, BackSize: &size, LayPrice: &lay, LaySize: &size},
+		{Time: now.Add(-time.Minute), RaceID: race.ID, RunnerID: weak.ID, BackPrice: &weakBack, BackSize: &size, LayPrice: &weakLay, LaySize: &size},
+	}
+
+	betRepo := new(MockBetRepository)
+	strat := strategy.NewSimpleValueStrategy()
+	strategyID := uuid.New()
+	orchestrator := &Orchestrator{
+		raceRepo:         &simulateRaceRepo{races: map[uuid.UUID]*models.Race{race.ID: race}},
+		runnerRepo:       &simulateRunnerRepo{runners: []*models.Runner{strong, weak}},
+		oddsRepo:         &replayOddsRepo{odds: map[uuid.UUID][]*models.OddsSnapshot{race.ID: odds}},
+		betRepo:          betRepo,
+		executor:         NewExecutor(nil, betRepo, nil, true, false, logger, nil),
+		activeStrategies: map[uuid.UUID]strategy.Strategy{strategyID: strat},
+		clock:            NewMockClock(now),
+		logger:           logger,
+	}
+
+	return &simulateFixture{
+		orchestrator: orchestrator,
+		betRepo:      betRepo,
+		strategyID:   strategyID,
+		strategy:     strat,
+		stratCtx: strategy.Context{
+			Race:        race,
+			Runners:     []*models.Runner{strong, weak},
+			OddsHistory: odds,
+			CurrentTime: now,
+		},
+		marketID: marketID,
+	}
+}
+
+func TestSimulateMarketReturnsStrategySignalsWithoutBetting(t *testing.T) {
+	f := newSimulateFixture(t)
+
+	expected, err := f.strategy.Evaluate(context.Background(), f.stratCtx)
+	require.NoError(t, err)
+	require.Len(t, expected, 1, "only the runner with form shows an edge")
+
+	signals, err := f.orchestrator.SimulateMarket(context.Background(), f.strategyID, f.marketID)
+	require.NoError(t, err)
+	require.Len(t, signals, len(expected))
+	for i, signal := range signals {
+		assert.Equal(t, expected[i], signal.Signal)
+		assert.Equal(t, f.strategyID, signal.StrategyID)
+		assert.Equal(t, f.stratCtx.Race.ID, signal.RaceID)
+		assert.Equal(t, f.marketID, signal.MarketID)
+		assert.Equal(t, uint64(101), signal.SelectionID)
+		assert.NotEmpty(t, signal.Signal.Reasoning)
+	}
+
+	f.betRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	metrics := f.orchestrator.executor.GetMetrics()
+	assert.Zero(t, metrics.OrdersExecuted+metrics.PaperTrades+metrics.LiveTrades)
+}
+
+func TestSimulateMarketRejectsUnknownStrategyAndMarket(t *testing.T) {
+	f := newSimulateFixture(t)
+
+	_, err := f.orchestrator.SimulateMarket(context.Background(), uuid.New(), f.marketID)
+	assert.ErrorIs(t, err, ErrStrategyNotActive)
+
+	_, err = f.orchestrator.SimulateMarket(context.Background(), f.strategyID, "1.999")
+	assert.ErrorIs(t, err, ErrMarketNotFound)
+}
+
+func TestSimulateHandlerServesSignals(t *testing.T) {
+	f := newSimulateFixture(t)
+	handler := f.orchestrator.SimulateHandler()
+
+	req := httptest.NewRequest(http.MethodGet, SimulatePath+"?strategy_id="+f.strategyID.String()+"&market_id="+f.marketID, nil)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var response SimulationResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&response))
+	assert.Equal(t, f.strategyID, response.StrategyID)
+	assert.Equal(t, f.marketID, response.MarketID)
+	require.Len(t, response.Signals, 1)
+	assert.Equal(t, "Value edge exceeds threshold", response.Signals[0].Signal.Reasoning)
+
+	for _, tc := range []struct {
+		query string
+		code  int
+	}{
+		{"?strategy_id=nope&market_id=" + f.marketID, http.StatusBadRequest},
+		{"?strategy_id=" + f.strategyID.String(), http.StatusBadRequest},
+		{"?strategy_id=" + uuid.NewString() + "&market_id=" + f.marketID, http.StatusNotFound},
+		{"?strategy_id=" + f.strategyID.String() + "&market_id=1.999", http.StatusNotFound},
+	} {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, SimulatePath+tc.query, nil))
+		assert.Equal(t, tc.code, rec.Code, tc.query)
+	}
+	f.betRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
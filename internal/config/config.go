@@ -3,6 +3,8 @@ package config
 
 import (
 	"fmt"
+	"strings"
+	"time"
 )
 
 // Config represents the complete application configuration
@@ -17,6 +19,25 @@ type Config struct {
 	Metrics        MetricsConfig        `mapstructure:"metrics" validate:"required"`
 	Features       FeaturesConfig       `mapstructure:"features" validate:"required"`
 	Bot            BotConfig            `mapstructure:"bot" validate:"required"`
+	Tracing        TracingConfig        `mapstructure:"tracing"`
+}
+
+// TracingConfig controls X-Ray sampling. Traces that fail or have a
+// subsegment slower than SlowThresholdMs are always kept; the rest are kept at
+// the base sampling rate.
+type TracingConfig struct {
+	SamplingRate    float64            `mapstructure:"sampling_rate" validate:"gte=0,lte=1"`
+	SamplingRates   map[string]float64 `mapstructure:"sampling_rates" validate:"dive,gte=0,lte=1"`
+	SlowThresholdMs int                `mapstructure:"slow_threshold_ms" validate:"gte=0"`
+}
+
+// SamplingRateFor returns the base sampling rate for a binary, overridden per
+// service name by SamplingRates
+func (t TracingConfig) SamplingRateFor(service string) float64 {
+	if rate, ok := t.SamplingRates[service]; ok {
+		return rate
+	}
+	return t.SamplingRate
 }
 
 // AppConfig represents application-level configuration
@@ -24,6 +45,16 @@ type AppConfig struct {
 	Name        string `mapstructure:"name" validate:"required"`
 	Environment string `mapstructure:"environment" validate:"required,environment"`
 	LogLevel    string `mapstructure:"log_level" validate:"required,loglevel"`
+	Log         LogConfig `mapstructure:"log"`
+}
+
+// LogConfig controls where logs are written and in which format
+type LogConfig struct {
+	Format     string `mapstructure:"format" validate:"omitempty,oneof=text json"`
+	Output     string `mapstructure:"output" validate:"omitempty,oneof=stdout file both"`
+	FilePath   string `mapstructure:"file_path" validate:"required_if=Output file,required_if=Output both"`
+	MaxSizeMB  int    `mapstructure:"max_size_mb" validate:"gte=0"`
+	MaxBackups int    `mapstructure:"max_backups" validate:"gte=0"`
 }
 
 // DatabaseConfig represents database connection configuration
@@ -40,13 +71,134 @@ type DatabaseConfig struct {
 
 // BetfairConfig represents Betfair API configuration
 type BetfairConfig struct {
-	APIURL    string `mapstructure:"api_url" validate:"required,url"`
-	StreamURL string `mapstructure:"stream_url" validate:"required"`
-	AppKey    string `mapstructure:"app_key" validate:"required"`
-	Username  string `mapstructure:"username" validate:"required"`
-	Password  string `mapstructure:"password" validate:"required"`
-	CertFile  string `mapstructure:"cert_file" validate:"required"`
-	KeyFile   string `mapstructure:"key_file" validate:"required"`
+	APIURL                 string `mapstructure:"api_url" validate:"required,url"`
+	StreamURL              string `mapstructure:"stream_url" validate:"required"`
+	AppKey                 string `mapstructure:"app_key" validate:"required"`
+	Username               string `mapstructure:"username" validate:"required"`
+	Password               string `mapstructure:"password" validate:"required"`
+	CertFile               string `mapstructure:"cert_file" validate:"required"`
+	KeyFile                string `mapstructure:"key_file" validate:"required"`
+	CatalogCacheTTLSeconds int    `mapstructure:"catalog_cache_ttl_seconds" validate:"gte=0"`
+	// Accounts are additional accounts live bets are spread across. The
+	// credentials above remain the primary account.
+	Accounts       []BetfairAccountConfig `mapstructure:"accounts" validate:"dive"`
+	AccountRouting string                 `mapstructure:"account_routing" validate:"omitempty,oneof=round_robin least_exposed by_strategy"`
+	Maintenance    MaintenanceConfig      `mapstructure:"maintenance"`
+}
+
+// MaintenanceConfig lists Betfair's scheduled maintenance windows. Trading
+// and ingestion polling pause inside a window rather than counting the
+// failed calls against the circuit breaker.
+type MaintenanceConfig struct {
+	Windows []MaintenanceWindowConfig `mapstructure:"windows" validate:"omitempty,dive"`
+}
+
+// MaintenanceWindowConfig is one maintenance window, in UTC. A one-off window
+// sets start and end as RFC 3339 timestamps. A weekly window sets weekday,
+// start_time ("15:04") and duration_minutes.
+type MaintenanceWindowConfig struct {
+	Start           string `mapstructure:"start" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+	End             string `mapstructure:"end" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+	Weekday         string `mapstructure:"weekday" validate:"omitempty,oneof=sunday monday tuesday wednesday thursday friday saturday"`
+	StartTime       string `mapstructure:"start_time" validate:"omitempty,datetime=15:04"`
+	DurationMinutes int    `mapstructure:"duration_minutes" validate:"gte=0"`
+}
+
+// Validate checks each window is either one-off or weekly, and not empty
+func (c MaintenanceConfig) Validate() error {
+	for i, window := range c.Windows {
+		oneOff := window.Start != "" || window.End != ""
+		weekly := window.Weekday != "" || window.StartTime != "" || window.DurationMinutes != 0
+		switch {
+		case oneOff && weekly:
+			return fmt.Errorf("window %d mixes start/end with a weekly schedule", i)
+		case oneOff:
+			start, end, err := window.bounds()
+			if err != nil {
+				return fmt.Errorf("window %d: %w", i, err)
+			}
+			if !end.After(start) {
+				return fmt.Errorf("window %d must end after it starts", i)
+			}
+		case weekly:
+			if window.Weekday == "" || window.StartTime == "" || window.DurationMinutes <= 0 {
+				return fmt.Errorf("weekly window %d needs weekday, start_time and a positive duration_minutes", i)
+			}
+		default:
+			return fmt.Errorf("window %d is empty", i)
+		}
+	}
+	return nil
+}
+
+// Active reports whether t falls inside a maintenance window, and when the
+// window it falls in ends
+func (c MaintenanceConfig) Active(t time.Time) (time.Time, bool) {
+	t = t.UTC()
+	for _, window := range c.Windows {
+		var start, end time.Time
+		if window.Weekday != "" {
+			start, end = window.weeklyBounds(t)
+		} else {
+			var err error
+			if start, end, err = window.bounds(); err != nil {
+				continue
+			}
+		}
+		if !t.Before(start) && t.Before(end) {
+			return end, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// bounds parses a one-off window
+func (w MaintenanceWindowConfig) bounds() (time.Time, time.Time, error) {
+	start, err := time.Parse(time.RFC3339, w.Start)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid start: %w", err)
+	}
+	end, err := time.Parse(time.RFC3339, w.End)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid end: %w", err)
+	}
+	return start, end, nil
+}
+
+// weeklyBounds returns the latest occurrence of a weekly window starting at
+// or before t
+func (w MaintenanceWindowConfig) weeklyBounds(t time.Time) (time.Time, time.Time) {
+	clock, err := time.Parse("15:04", w.StartTime)
+	if err != nil {
+		return time.Time{}, time.Time{}
+	}
+	weekday := time.Sunday
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if strings.EqualFold(day.String(), w.Weekday) {
+			weekday = day
+		}
+	}
+
+	start := time.Date(t.Year(), t.Month(), t.Day(), clock.Hour(), clock.Minute(), 0, 0, time.UTC)
+	start = start.AddDate(0, 0, -((int(t.Weekday()) - int(weekday) + 7) % 7))
+	if start.After(t) {
+		start = start.AddDate(0, 0, -7)
+	}
+	return start, start.Add(time.Duration(w.DurationMinutes) * time.Minute)
+}
+
+// BetfairAccountConfig is an extra Betfair account or sub-account. An unset
+// app key or certificate falls back to the primary account's. Strategies
+// lists the strategy names routed to the account under by_strategy routing.
+type BetfairAccountConfig struct {
+	Name        string   `mapstructure:"name" validate:"required"`
+	AppKey      string   `mapstructure:"app_key"`
+	Username    string   `mapstructure:"username" validate:"required"`
+	Password    string   `mapstructure:"password" validate:"required"`
+	CertFile    string   `mapstructure:"cert_file"`
+	KeyFile     string   `mapstructure:"key_file"`
+	MaxExposure float64  `mapstructure:"max_exposure" validate:"gte=0"`
+	Strategies  []string `mapstructure:"strategies"`
 }
 
 // MLServiceConfig represents ML service configuration
@@ -57,12 +209,84 @@ type MLServiceConfig struct {
 	TimeoutSeconds         int    `mapstructure:"timeout_seconds" validate:"required,gt=0"`
 	RequestTimeoutSeconds  int    `mapstructure:"request_timeout_seconds" validate:"required,gt=0"`
 	RetryAttempts          int    `mapstructure:"retry_attempts" validate:"required,gte=0"`
+	ConnectionPoolSize     int    `mapstructure:"connection_pool_size" validate:"gte=0"`
 	CacheTTLSeconds        int    `mapstructure:"cache_ttl_seconds" validate:"required,gt=0"`
 	CacheMaxSize           int    `mapstructure:"cache_max_size" validate:"required,gt=0"`
 	EnableStrategyGeneration bool `mapstructure:"enable_strategy_generation"`
 	EnableFeedbackLoop     bool   `mapstructure:"enable_feedback_loop"`
 	FeedbackBatchSize      int    `mapstructure:"feedback_batch_size" validate:"required,gt=0"`
 	RetrainingIntervalHours int  `mapstructure:"retraining_interval_hours" validate:"required,gt=0"`
+	Calibration            CalibrationConfig `mapstructure:"calibration"`
+	Activation             ActivationGateConfig `mapstructure:"activation"`
+	Ensemble               EnsembleConfig `mapstructure:"ensemble"`
+	LatencyBudget          MLLatencyBudgetConfig `mapstructure:"latency_budget"`
+}
+
+// MLLatencyBudgetConfig bypasses the ML service while it is slow. When the
+// p95 latency of the last WindowSize predictions (once there are MinSamples)
+// exceeds P95Milliseconds, predictions fail fast and trading.ml_failure_policy
+// decides what happens to signals. One probe per ProbeIntervalSeconds still
+// goes to the service, and a fast answer ends the bypass.
+type MLLatencyBudgetConfig struct {
+	Enabled              bool `mapstructure:"enabled"`
+	P95Milliseconds      int  `mapstructure:"p95_ms" validate:"gte=0"`
+	WindowSize           int  `mapstructure:"window_size" validate:"gte=0"`
+	MinSamples           int  `mapstructure:"min_samples" validate:"gte=0"`
+	ProbeIntervalSeconds int  `mapstructure:"probe_interval_seconds" validate:"gte=0"`
+}
+
+// EnsembleConfig blends several model versions' predictions into their
+// weighted average. Weights are relative to their total. Predictions asked
+// for a specific model version bypass the ensemble.
+type EnsembleConfig struct {
+	Enabled bool                  `mapstructure:"enabled"`
+	Models  []EnsembleModelConfig `mapstructure:"models" validate:"omitempty,dive"`
+}
+
+// EnsembleModelConfig is one model version in an ensemble
+type EnsembleModelConfig struct {
+	Version string  `mapstructure:"version" validate:"required"`
+	Weight  float64 `mapstructure:"weight" validate:"gt=0"`
+}
+
+// ActivationGateConfig sets the backtest evidence an ML-generated strategy
+// needs before it is activated, whatever its composite score. Zero disables
+// a gate.
+type ActivationGateConfig struct {
+	MinTotalBets              int     `mapstructure:"min_total_bets" validate:"gte=0"`
+	MinAverageLiquidity       float64 `mapstructure:"min_average_liquidity" validate:"gte=0"`
+	MinWalkForwardConsistency float64 `mapstructure:"min_walk_forward_consistency" validate:"gte=0,lte=1"`
+}
+
+// CalibrationConfig maps raw model probabilities onto observed win rates.
+// Platt scaling applies sigmoid(platt_a*logit(p) + platt_b). Isotonic
+// calibration interpolates linearly between the (isotonic_x, isotonic_y)
+// points and holds the end values outside them.
+type CalibrationConfig struct {
+	Method    string    `mapstructure:"method" validate:"omitempty,oneof=identity platt isotonic"`
+	PlattA    float64   `mapstructure:"platt_a"`
+	PlattB    float64   `mapstructure:"platt_b"`
+	IsotonicX []float64 `mapstructure:"isotonic_x" validate:"omitempty,dive,gte=0,lte=1"`
+	IsotonicY []float64 `mapstructure:"isotonic_y" validate:"omitempty,dive,gte=0,lte=1"`
+}
+
+// Validate checks the isotonic points form a non-decreasing curve
+func (c CalibrationConfig) Validate() error {
+	if c.Method != "isotonic" {
+		return nil
+	}
+	if len(c.IsotonicX) < 2 || len(c.IsotonicX) != len(c.IsotonicY) {
+		return fmt.Errorf("isotonic calibration needs at least two points with matching isotonic_x and isotonic_y")
+	}
+	for i := 1; i < len(c.IsotonicX); i++ {
+		if c.IsotonicX[i] <= c.IsotonicX[i-1] {
+			return fmt.Errorf("isotonic_x must be strictly increasing")
+		}
+		if c.IsotonicY[i] < c.IsotonicY[i-1] {
+			return fmt.Errorf("isotonic_y must be non-decreasing")
+		}
+	}
+	return nil
 }
 
 // TradingConfig represents trading strategy and risk management configuration
@@ -70,14 +294,152 @@ type TradingConfig struct {
 	MaxStakePerBet               float64  `mapstructure:"max_stake_per_bet" validate:"required,gt=0"`
 	MaxDailyLoss                 float64  `mapstructure:"max_daily_loss" validate:"required,gt=0"`
 	MaxExposure                  float64  `mapstructure:"max_exposure" validate:"required,gt=0"`
+	ExposureReservationTTL       int      `mapstructure:"exposure_reservation_ttl" validate:"gte=0"`
 	MinConfidenceThreshold       float64  `mapstructure:"min_confidence_threshold" validate:"required,gte=0,lte=1"`
 	MinExpectedValue             float64  `mapstructure:"min_expected_value" validate:"required,gte=0"`
+	MinEdgeThreshold             float64  `mapstructure:"min_edge_threshold" validate:"gte=0"`
+	OddsBands                    []OddsBandConfig `mapstructure:"odds_bands" validate:"dive"`
+	MLFilterMode                 string   `mapstructure:"ml_filter_mode" validate:"omitempty,oneof=veto override advisory observe"`
+	MLFailurePolicy              string   `mapstructure:"ml_failure_policy" validate:"omitempty,oneof=open closed"`
+	MinMarketLiquidity           float64  `mapstructure:"min_market_liquidity" validate:"gte=0"`
 	Markets                      []string `mapstructure:"markets" validate:"required,min=1,markets"`
 	PreRaceWindowMinutes         int      `mapstructure:"pre_race_window_minutes" validate:"required,gte=0"`
 	MinTimeToStartSeconds        int      `mapstructure:"min_time_to_start_seconds" validate:"required,gte=0"`
 	MaxConcurrentBets            int      `mapstructure:"max_concurrent_bets" validate:"required,gt=0"`
+	MaxBetsPerRace               int      `mapstructure:"max_bets_per_race" validate:"gte=0"`
+	MaxBetsPerDay                int      `mapstructure:"max_bets_per_day" validate:"gte=0"`
 	StrategyEvaluationInterval   int      `mapstructure:"strategy_evaluation_interval" validate:"required,gt=0"`
+	StrategyEvaluationTimeout    int      `mapstructure:"strategy_evaluation_timeout" validate:"gte=0"`
 	EmergencyShutdownEnabled     bool     `mapstructure:"emergency_shutdown_enabled"`
+	GreenUpOnShutdown            bool     `mapstructure:"green_up_on_shutdown"`
+	PlacementRateLimit           float64  `mapstructure:"placement_rate_limit" validate:"gte=0"`
+	PreventSelfMatch             bool     `mapstructure:"prevent_self_match"`
+	MinMarketBetIntervalSeconds  int      `mapstructure:"min_market_bet_interval_seconds" validate:"gte=0"`
+	BankrollSource               string   `mapstructure:"bankroll_source" validate:"omitempty,oneof=fixed live_balance"`
+	IncludeReserveRunners        bool     `mapstructure:"include_reserve_runners"`
+	MaxLadderDepth               int      `mapstructure:"max_ladder_depth" validate:"gte=0"`
+	LadderDepthCheck             bool     `mapstructure:"ladder_depth_check"`
+	OddsSourcePreference         []string `mapstructure:"odds_source_preference"`
+	OddsSanity                   OddsSanityConfig `mapstructure:"odds_sanity"`
+	OddsStaleness                OddsStalenessConfig `mapstructure:"odds_staleness"`
+	StakeRounding                StakeRoundingConfig `mapstructure:"stake_rounding"`
+	MarketFilter                 MarketFilterConfig `mapstructure:"market_filter"`
+	BankrollAllocation           BankrollAllocationConfig `mapstructure:"bankroll_allocation"`
+	ConfidenceScaling            ConfidenceScalingConfig `mapstructure:"confidence_scaling"`
+	InPlay                       InPlayConfig `mapstructure:"in_play"`
+}
+
+// InPlayConfig controls trading races once their markets turn in-play. Races
+// that started within WindowMinutes are checked every TickSeconds (default 5),
+// apart from the trading loop, and only strategies whose parameters set in_play evaluate
+// them. Markets with a bet delay over MaxBetDelaySeconds are skipped (0 is no
+// limit).
+type InPlayConfig struct {
+	Enabled            bool `mapstructure:"enabled"`
+	WindowMinutes      int  `mapstructure:"window_minutes" validate:"gte=0"`
+	TickSeconds        int  `mapstructure:"tick_seconds" validate:"gte=0"`
+	MaxBetDelaySeconds int  `mapstructure:"max_bet_delay_seconds" validate:"gte=0"`
+}
+
+// Confidence scaling modes for trading.confidence_scaling.mode
+const (
+	// ConfidenceScalingThreshold stakes in full any signal clearing
+	// min_confidence_threshold and nothing below it
+	ConfidenceScalingThreshold = "threshold"
+	// ConfidenceScalingLinear scales stake from zero at the floor to full at
+	// full_at, so there is no cliff at a single confidence
+	ConfidenceScalingLinear = "linear"
+)
+
+// ConfidenceScalingConfig decides how stake responds to signal confidence.
+// In linear mode the floor replaces min_confidence_threshold as the lowest
+// confidence bet on.
+type ConfidenceScalingConfig struct {
+	Mode   string  `mapstructure:"mode" validate:"omitempty,oneof=threshold linear"`
+	Floor  float64 `mapstructure:"floor" validate:"gte=0,lte=1"`
+	FullAt float64 `mapstructure:"full_at" validate:"gte=0,lte=1"`
+}
+
+// Validate checks a linear scale rises from the floor to full_at
+func (c ConfidenceScalingConfig) Validate() error {
+	if c.Mode != ConfidenceScalingLinear {
+		return nil
+	}
+	if c.FullAt <= c.Floor {
+		return fmt.Errorf("full_at must be above floor")
+	}
+	return nil
+}
+
+// BankrollAllocationConfig gives each active strategy its own slice of the
+// bankroll. Shares are relative weights keyed by strategy name; strategies
+// without an entry weigh 1.
+type BankrollAllocationConfig struct {
+	Enabled                  bool               `mapstructure:"enabled"`
+	Mode                     string             `mapstructure:"mode" validate:"omitempty,oneof=fixed performance"`
+	Shares                   map[string]float64 `mapstructure:"shares"`
+	MinShare                 float64            `mapstructure:"min_share" validate:"gte=0,lt=1"`
+	LookbackDays             int                `mapstructure:"lookback_days" validate:"gte=0"`
+	RebalanceIntervalMinutes int                `mapstructure:"rebalance_interval_minutes" validate:"gte=0"`
+}
+
+// MarketFilterConfig excludes races from trading and backtests. Deny rules
+// always win; non-empty allow lists restrict trading to matching races.
+type MarketFilterConfig struct {
+	Allow MarketRulesConfig `mapstructure:"allow"`
+	Deny  MarketRulesConfig `mapstructure:"deny"`
+}
+
+// MarketRulesConfig lists case-insensitive patterns per race attribute.
+// Shell wildcards are supported, e.g. "rom*".
+type MarketRulesConfig struct {
+	Venues    []string `mapstructure:"venues"`
+	RaceTypes []string `mapstructure:"race_types"`
+	Grades    []string `mapstructure:"grades"`
+	Countries []string `mapstructure:"countries"`
+}
+
+// OddsBandConfig overrides min_edge_threshold and the minimum confidence for
+// odds from MinOdds up to MaxOdds (0 for no upper bound). The first band
+// containing a price applies, and a zero threshold keeps the global one.
+type OddsBandConfig struct {
+	MinOdds       float64 `mapstructure:"min_odds" validate:"gte=0"`
+	MaxOdds       float64 `mapstructure:"max_odds" validate:"omitempty,gtfield=MinOdds"`
+	MinEdge       float64 `mapstructure:"min_edge" validate:"gte=0"`
+	MinConfidence float64 `mapstructure:"min_confidence" validate:"gte=0,lte=1"`
+}
+
+// OddsSanityConfig bounds the prices accepted at ingestion and before
+// placement, per racing discipline
+type OddsSanityConfig struct {
+	Enabled   bool             `mapstructure:"enabled"`
+	Greyhound OddsBoundsConfig `mapstructure:"greyhound"`
+	Horse     OddsBoundsConfig `mapstructure:"horse"`
+}
+
+// OddsStalenessConfig re-checks the live price before placement. A bet whose
+// signal odds are more than MaxTicks ladder ticks from the current price is
+// rejected, or placed at the current price when Action is "reprice".
+type OddsStalenessConfig struct {
+	Enabled  bool   `mapstructure:"enabled"`
+	MaxTicks int    `mapstructure:"max_ticks" validate:"gte=0"`
+	Action   string `mapstructure:"action" validate:"omitempty,oneof=reject reprice"`
+}
+
+// StakeRoundingConfig rounds stakes down to Increment before placement and
+// rejects bets that round below MinStake. Backtests apply it to simulated
+// fills too.
+type StakeRoundingConfig struct {
+	MinStake  float64 `mapstructure:"min_stake" validate:"gte=0"`
+	Increment float64 `mapstructure:"increment" validate:"gte=0"`
+}
+
+// OddsBoundsConfig is the plausible price range and maximum tick move
+// between consecutive prices. Unset odds keep the built-in defaults.
+type OddsBoundsConfig struct {
+	MinOdds     float64 `mapstructure:"min_odds" validate:"omitempty,gte=1"`
+	MaxOdds     float64 `mapstructure:"max_odds" validate:"omitempty,gtefield=MinOdds"`
+	MaxTickMove int     `mapstructure:"max_tick_move" validate:"gte=0"`
 }
 
 // BotConfig represents bot-specific configuration
@@ -85,29 +447,160 @@ type BotConfig struct {
 	OrderMonitoringInterval    int     `mapstructure:"order_monitoring_interval" validate:"required,gt=0"`
 	PerformanceUpdateInterval  int     `mapstructure:"performance_update_interval" validate:"required,gt=0"`
 	MaxConsecutiveLosses       int     `mapstructure:"max_consecutive_losses" validate:"required,gt=0"`
+	MaxConsecutiveWins         int     `mapstructure:"max_consecutive_wins" validate:"gte=0"`
 	MaxDrawdownPercent         float64 `mapstructure:"max_drawdown_percent" validate:"required,gt=0,lt=1"`
 	RiskFreeRate               float64 `mapstructure:"risk_free_rate" validate:"gte=0,lte=1"`
+	EMAHalfLifeBets            int     `mapstructure:"ema_half_life_bets" validate:"gte=0"`
+	MinSampleBets              int     `mapstructure:"min_sample_bets" validate:"gte=0"`
+	WarmUpSeconds              int     `mapstructure:"warm_up_seconds" validate:"gte=0"`
+	StrategyReloadInterval     int     `mapstructure:"strategy_reload_interval" validate:"gte=0"`
+	StrategyMaxStaleness       int     `mapstructure:"strategy_max_staleness" validate:"gte=0"`
+	UnknownStrategyTypes       string  `mapstructure:"unknown_strategy_types" validate:"omitempty,oneof=skip fail"`
+	PerformanceDecay           PerformanceDecayConfig `mapstructure:"performance_decay"`
+	KillSwitch                 KillSwitchConfig       `mapstructure:"kill_switch"`
+	UnmatchedBets              UnmatchedBetsConfig    `mapstructure:"unmatched_bets"`
+	CircuitRecovery            CircuitRecoveryConfig  `mapstructure:"circuit_recovery"`
+	Admin                      AdminConfig            `mapstructure:"admin"`
+}
+
+// CircuitRecoveryConfig scales stakes down when trading resumes after the
+// circuit breaker opens, returning to full size after Bets settled bets
+// without a loss or DurationMinutes, whichever comes first. A loss during
+// the ramp starts it again.
+type CircuitRecoveryConfig struct {
+	Enabled         bool    `mapstructure:"enabled"`
+	StartFraction   float64 `mapstructure:"start_fraction" validate:"gte=0,lte=1"`
+	Bets            int     `mapstructure:"bets" validate:"gte=0"`
+	DurationMinutes int     `mapstructure:"duration_minutes" validate:"gte=0"`
+}
+
+// AdminConfig enables the operator admin endpoints on the health server port.
+// Requests must carry Secret in the X-Admin-Secret header.
+type AdminConfig struct {
+	Enabled  bool                `mapstructure:"enabled"`
+	Secret   string              `mapstructure:"secret" validate:"required_if=Enabled true"`
+	Backtest AdminBacktestConfig `mapstructure:"backtest"`
+}
+
+// AdminBacktestConfig enables on-demand backtests through the admin
+// endpoints. At most MaxConcurrent (at least one) run at once, each for at
+// most TimeoutSeconds, zero for no limit.
+type AdminBacktestConfig struct {
+	Enabled        bool `mapstructure:"enabled"`
+	MaxConcurrent  int  `mapstructure:"max_concurrent" validate:"gte=0"`
+	TimeoutSeconds int  `mapstructure:"timeout_seconds" validate:"gte=0"`
+}
+
+// UnmatchedBetsConfig controls how live bets left unmatched are handled as
+// the off approaches: held at their price for a grace period, repriced
+// towards the market, then cancelled
+type UnmatchedBetsConfig struct {
+	Enabled              bool `mapstructure:"enabled"`
+	GracePeriodSeconds   int  `mapstructure:"grace_period_seconds" validate:"gte=0"`
+	RepriceWithinSeconds int  `mapstructure:"reprice_within_seconds" validate:"gte=0"`
+	RepriceStepTicks     int  `mapstructure:"reprice_step_ticks" validate:"gte=0"`
+	MaxReprices          int  `mapstructure:"max_reprices" validate:"gte=0"`
+	CancelWithinSeconds  int  `mapstructure:"cancel_within_seconds" validate:"gte=0"`
+}
+
+// KillSwitchConfig controls the operator kill switch that halts all trading
+type KillSwitchConfig struct {
+	File            string `mapstructure:"file"`
+	Engaged         bool   `mapstructure:"engaged"`
+	CancelUnmatched bool   `mapstructure:"cancel_unmatched"`
+}
+
+// PerformanceDecayConfig controls automatic deactivation of live strategies
+// whose recent performance stays below a floor
+type PerformanceDecayConfig struct {
+	Enabled    bool    `mapstructure:"enabled"`
+	MinROI     float64 `mapstructure:"min_roi"`
+	MinSharpe  float64 `mapstructure:"min_sharpe"`
+	WindowDays int     `mapstructure:"window_days" validate:"required_if=Enabled true,gte=0"`
+	MinDays    int     `mapstructure:"min_days" validate:"gte=0"`
+	MinBets    int     `mapstructure:"min_bets" validate:"gte=0"`
 }
 
 // BacktestConfig represents backtesting configuration
 type BacktestConfig struct {
-	StartDate             string  `mapstructure:"start_date" validate:"required,datetime=2006-01-02"`
-	EndDate               string  `mapstructure:"end_date" validate:"required,datetime=2006-01-02"`
-	InitialBankroll       float64 `mapstructure:"initial_bankroll" validate:"required,gt=0"`
-	MonteCarloIterations  int     `mapstructure:"monte_carlo_iterations" validate:"required,gt=0"`
-	WalkForwardWindows    int     `mapstructure:"walk_forward_windows" validate:"required,gt=0"`
-	CommissionRate        float64 `mapstructure:"commission_rate" validate:"required,gte=0,lte=0.1"`
-	SlippageTicks         int     `mapstructure:"slippage_ticks" validate:"required,gte=0"`
-	MinLiquidity          float64 `mapstructure:"min_liquidity" validate:"required,gte=0"`
-	OutputPath            string  `mapstructure:"output_path" validate:"required"`
-	MLExportEnabled       bool    `mapstructure:"ml_export_enabled"`
-	RiskFreeRate          float64 `mapstructure:"risk_free_rate" validate:"gte=0"`
+	StartDate             string         `mapstructure:"start_date" validate:"required,datetime=2006-01-02"`
+	EndDate               string         `mapstructure:"end_date" validate:"required,datetime=2006-01-02"`
+	InitialBankroll       float64        `mapstructure:"initial_bankroll" validate:"required,gt=0"`
+	MonteCarloIterations  int            `mapstructure:"monte_carlo_iterations" validate:"required,gt=0"`
+	WalkForwardWindows    int            `mapstructure:"walk_forward_windows" validate:"required,gt=0"`
+	CommissionRate        float64        `mapstructure:"commission_rate" validate:"required,gte=0,lte=0.1"`
+	CommissionPromos      []CommissionPromoConfig `mapstructure:"commission_promos" validate:"omitempty,dive"`
+	SlippageTicks         int            `mapstructure:"slippage_ticks" validate:"required,gte=0"`
+	SlippageByMarket      map[string]int `mapstructure:"slippage_by_market" validate:"omitempty,dive,gte=0"`
+	MinLiquidity          float64        `mapstructure:"min_liquidity" validate:"required,gte=0"`
+	OutputPath            string         `mapstructure:"output_path" validate:"required"`
+	MLExportEnabled       bool           `mapstructure:"ml_export_enabled"`
+	RiskFreeRate          float64        `mapstructure:"risk_free_rate" validate:"gte=0"`
+	Streaming             bool           `mapstructure:"streaming"`
+	Benchmark             bool           `mapstructure:"benchmark"`
+	BenchmarkStake        float64        `mapstructure:"benchmark_stake" validate:"gte=0"`
+	CompositeWeights      CompositeWeightsConfig `mapstructure:"composite_weights"`
+	Rejection             RejectionConfig        `mapstructure:"rejection"`
+}
+
+// RejectionConfig randomly rejects a share of simulated bets to mimic live
+// placement failures. Mode is off, flat (every bet at rate) or liquidity
+// (rising from rate to max_rate as the stake takes up the size on offer).
+type RejectionConfig struct {
+	Mode    string  `mapstructure:"mode" validate:"omitempty,oneof=off flat liquidity"`
+	Rate    float64 `mapstructure:"rate" validate:"gte=0,lte=1"`
+	MaxRate float64 `mapstructure:"max_rate" validate:"gte=0,lte=1"`
+	Seed    int64   `mapstructure:"seed"`
+}
+
+// CompositeWeightsConfig weights each backtest method's score in the
+// composite score of an aggregated result
+type CompositeWeightsConfig struct {
+	HistoricalReplay float64 `mapstructure:"historical_replay" validate:"gte=0,lte=1"`
+	MonteCarlo       float64 `mapstructure:"monte_carlo" validate:"gte=0,lte=1"`
+	WalkForward      float64 `mapstructure:"walk_forward" validate:"gte=0,lte=1"`
+}
+
+// CommissionPromoConfig represents a commission-free or reduced commission
+// window, from start_date through end_date inclusive
+type CommissionPromoConfig struct {
+	StartDate string  `mapstructure:"start_date" validate:"required,datetime=2006-01-02"`
+	EndDate   string  `mapstructure:"end_date" validate:"required,datetime=2006-01-02"`
+	Rate      float64 `mapstructure:"rate" validate:"gte=0,lte=0.1"`
 }
 
 // DataIngestionConfig represents data ingestion configuration
 type DataIngestionConfig struct {
-	Sources  []DataSourceConfig `mapstructure:"sources" validate:"required,min=1"`
-	Schedule ScheduleConfig     `mapstructure:"schedule" validate:"required"`
+	Sources       []DataSourceConfig  `mapstructure:"sources" validate:"required,min=1"`
+	Schedule      ScheduleConfig      `mapstructure:"schedule" validate:"required"`
+	ResultWebhook ResultWebhookConfig `mapstructure:"result_webhook"`
+	Failover      FailoverConfig      `mapstructure:"failover"`
+	OddsRetention OddsRetentionConfig `mapstructure:"odds_retention"`
+}
+
+// OddsRetentionConfig controls the scheduled job that compresses and drops
+// odds_snapshots chunks. Zero days disables that step. In dry-run the job
+// only reports what it would do.
+type OddsRetentionConfig struct {
+	Enabled           bool   `mapstructure:"enabled"`
+	Schedule          string `mapstructure:"schedule" validate:"required_if=Enabled true"`
+	CompressAfterDays int    `mapstructure:"compress_after_days" validate:"gte=0"`
+	RetentionDays     int    `mapstructure:"retention_days" validate:"omitempty,gtfield=CompressAfterDays"`
+	DryRun            bool   `mapstructure:"dry_run"`
+}
+
+// FailoverConfig makes the enabled sources fall back to one another in the
+// order they are listed. A source that errors or returns nothing is skipped
+// until it has been unhealthy for ReprobeIntervalSeconds.
+type FailoverConfig struct {
+	Enabled                bool `mapstructure:"enabled"`
+	ReprobeIntervalSeconds int  `mapstructure:"reprobe_interval_seconds" validate:"gte=0"`
+}
+
+// ResultWebhookConfig configures the endpoint providers push race results to
+type ResultWebhookConfig struct {
+	Enabled bool   `mapstructure:"enabled"`
+	Secret  string `mapstructure:"secret" validate:"required_if=Enabled true"`
 }
 
 // DataSourceConfig represents a single data source configuration
@@ -4,6 +4,7 @@ package config
 import (
 	"os"
 	"testing"
+	"time"
 )
 
 const (
@@ -257,6 +258,69 @@ func TestGetMLServiceGRPCAddress(t *testing.T) {
 	}
 }
 
+// TestTracingSamplingRateFor tests per-binary sampling rate overrides
+func TestTracingSamplingRateFor(t *testing.T) {
+	tracing := TracingConfig{
+		SamplingRate:  0.1,
+		SamplingRates: map[string]float64{"clever-better-bot": 0.5},
+	}
+
+	if rate := tracing.SamplingRateFor("clever-better-bot"); rate != 0.5 {
+		t.Errorf("expected bot rate 0.5, got %v", rate)
+	}
+	if rate := tracing.SamplingRateFor("clever-better-ml-feedback"); rate != 0.1 {
+		t.Errorf("expected base rate 0.1, got %v", rate)
+	}
+}
+
+// TestMaintenanceActive tests one-off and weekly maintenance windows
+func TestMaintenanceActive(t *testing.T) {
+	maintenance := MaintenanceConfig{Windows: []MaintenanceWindowConfig{
+		{Start: "2024-06-04T03:00:00Z", End: "2024-06-04T05:00:00Z"},
+		{Weekday: "sunday", StartTime: "23:30", DurationMinutes: 60},
+	}}
+	if err := maintenance.Validate(); err != nil {
+		t.Fatalf(expectedNoErrorMsg, err)
+	}
+
+	cases := []struct {
+		at     string
+		active bool
+		until  string
+	}{
+		{"2024-06-04T02:59:00Z", false, ""},
+		{"2024-06-04T03:00:00Z", true, "2024-06-04T05:00:00Z"},
+		{"2024-06-04T05:00:00Z", false, ""},
+		// The weekly window runs from Sunday night into Monday
+		{"2024-06-09T23:45:00Z", true, "2024-06-10T00:30:00Z"},
+		{"2024-06-10T01:15:00+01:00", true, "2024-06-10T00:30:00Z"},
+		{"2024-06-10T00:30:00Z", false, ""},
+		{"2024-06-16T23:29:00Z", false, ""},
+	}
+	for _, c := range cases {
+		at, _ := time.Parse(time.RFC3339, c.at)
+		until, active := maintenance.Active(at)
+		if active != c.active {
+			t.Errorf("at %s: expected active %v, got %v", c.at, c.active, active)
+		}
+		if c.active && until.Format(time.RFC3339) != c.until {
+			t.Errorf("at %s: expected window to end %s, got %s", c.at, c.until, until.Format(time.RFC3339))
+		}
+	}
+
+	invalid := []MaintenanceWindowConfig{
+		{},
+		{Start: "2024-06-04T05:00:00Z", End: "2024-06-04T03:00:00Z"},
+		{Weekday: "sunday", StartTime: "04:00"},
+		{Start: "2024-06-04T03:00:00Z", End: "2024-06-04T05:00:00Z", Weekday: "sunday"},
+	}
+	for i, window := range invalid {
+		if err := (MaintenanceConfig{Windows: []MaintenanceWindowConfig{window}}).Validate(); err == nil {
+			t.Errorf("expected invalid window %d to fail validation", i)
+		}
+	}
+}
+
 // TestLoadConfigEnvironmentVariableExpansion tests environment variable expansion in config file
 func TestLoadConfigEnvironmentVariableExpansion(t *testing.T) {
 	// Set environment variable
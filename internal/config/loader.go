@@ -80,8 +80,94 @@ func LoadWithDefaults(configPath string) (*Config, error) {
 	// Set some reasonable defaults
 	v.SetDefault("app.environment", "development")
 	v.SetDefault("app.log_level", "info")
+	v.SetDefault("app.log.output", "stdout")
+	v.SetDefault("app.log.max_size_mb", 100)
+	v.SetDefault("app.log.max_backups", 5)
 	v.SetDefault("metrics.enabled", true)
 	v.SetDefault("features.paper_trading_enabled", true)
+	v.SetDefault("bot.ema_half_life_bets", 20)
+	v.SetDefault("bot.min_sample_bets", 30)
+	v.SetDefault("bot.warm_up_seconds", 300)
+	v.SetDefault("bot.strategy_reload_interval", 300)
+	v.SetDefault("bot.strategy_max_staleness", 1800)
+	v.SetDefault("bot.unknown_strategy_types", "skip")
+	v.SetDefault("bot.kill_switch.file", "")
+	v.SetDefault("bot.kill_switch.engaged", false)
+	v.SetDefault("bot.kill_switch.cancel_unmatched", true)
+	v.SetDefault("bot.circuit_recovery.enabled", false)
+	v.SetDefault("bot.circuit_recovery.start_fraction", 0.25)
+	v.SetDefault("bot.circuit_recovery.bets", 20)
+	v.SetDefault("bot.circuit_recovery.duration_minutes", 0)
+	v.SetDefault("bot.unmatched_bets.enabled", false)
+	v.SetDefault("bot.unmatched_bets.grace_period_seconds", 60)
+	v.SetDefault("bot.unmatched_bets.reprice_within_seconds", 300)
+	v.SetDefault("bot.unmatched_bets.reprice_step_ticks", 1)
+	v.SetDefault("bot.unmatched_bets.max_reprices", 3)
+	v.SetDefault("bot.unmatched_bets.cancel_within_seconds", 30)
+	v.SetDefault("bot.admin.enabled", false)
+	v.SetDefault("bot.admin.backtest.enabled", false)
+	v.SetDefault("bot.admin.backtest.max_concurrent", 1)
+	v.SetDefault("bot.admin.backtest.timeout_seconds", 300)
+	v.SetDefault("ml_service.connection_pool_size", 4)
+	v.SetDefault("ml_service.calibration.method", "identity")
+	v.SetDefault("ml_service.ensemble.enabled", false)
+	v.SetDefault("ml_service.latency_budget.enabled", false)
+	v.SetDefault("ml_service.latency_budget.p95_ms", 250)
+	v.SetDefault("ml_service.latency_budget.window_size", 50)
+	v.SetDefault("ml_service.latency_budget.min_samples", 20)
+	v.SetDefault("ml_service.latency_budget.probe_interval_seconds", 10)
+	v.SetDefault("ml_service.activation.min_total_bets", 30)
+	v.SetDefault("ml_service.activation.min_average_liquidity", 50.0)
+	v.SetDefault("ml_service.activation.min_walk_forward_consistency", 0.5)
+	v.SetDefault("trading.placement_rate_limit", 5.0)
+	v.SetDefault("trading.prevent_self_match", true)
+	v.SetDefault("trading.min_market_bet_interval_seconds", 0)
+	v.SetDefault("trading.exposure_reservation_ttl", 60)
+	v.SetDefault("trading.ml_filter_mode", "veto")
+	v.SetDefault("trading.ml_failure_policy", "open")
+	v.SetDefault("trading.strategy_evaluation_timeout", 5)
+	v.SetDefault("trading.bankroll_source", "fixed")
+	v.SetDefault("trading.include_reserve_runners", false)
+	v.SetDefault("trading.max_ladder_depth", 3)
+	v.SetDefault("trading.odds_source_preference", []string{"betfair"})
+	v.SetDefault("trading.confidence_scaling.mode", "threshold")
+	v.SetDefault("trading.ladder_depth_check", false)
+	v.SetDefault("betfair.account_routing", "round_robin")
+	v.SetDefault("trading.bankroll_allocation.mode", "fixed")
+	v.SetDefault("trading.bankroll_allocation.min_share", 0.05)
+	v.SetDefault("trading.bankroll_allocation.lookback_days", 14)
+	v.SetDefault("trading.bankroll_allocation.rebalance_interval_minutes", 60)
+	v.SetDefault("trading.odds_sanity.enabled", true)
+	v.SetDefault("trading.odds_sanity.greyhound.min_odds", 1.1)
+	v.SetDefault("trading.odds_sanity.greyhound.max_odds", 150.0)
+	v.SetDefault("trading.odds_sanity.greyhound.max_tick_move", 30)
+	v.SetDefault("trading.odds_sanity.horse.min_odds", 1.05)
+	v.SetDefault("trading.odds_sanity.horse.max_odds", 500.0)
+	v.SetDefault("trading.odds_sanity.horse.max_tick_move", 30)
+	v.SetDefault("trading.odds_staleness.max_ticks", 2)
+	v.SetDefault("trading.odds_staleness.action", "reject")
+	v.SetDefault("trading.stake_rounding.min_stake", 1.0)
+	v.SetDefault("trading.stake_rounding.increment", 0.01)
+	v.SetDefault("trading.in_play.enabled", false)
+	v.SetDefault("trading.in_play.window_minutes", 10)
+	v.SetDefault("trading.in_play.tick_seconds", 5)
+	v.SetDefault("trading.in_play.max_bet_delay_seconds", 0)
+	v.SetDefault("backtest.composite_weights.historical_replay", 0.4)
+	v.SetDefault("backtest.composite_weights.monte_carlo", 0.3)
+	v.SetDefault("backtest.composite_weights.walk_forward", 0.3)
+	v.SetDefault("backtest.rejection.mode", "off")
+	v.SetDefault("backtest.rejection.rate", 0.0)
+	v.SetDefault("backtest.rejection.max_rate", 0.0)
+	v.SetDefault("backtest.rejection.seed", 1)
+	v.SetDefault("data_ingestion.failover.enabled", false)
+	v.SetDefault("data_ingestion.failover.reprobe_interval_seconds", 300)
+	v.SetDefault("data_ingestion.odds_retention.enabled", true)
+	v.SetDefault("data_ingestion.odds_retention.schedule", "0 3 * * *")
+	v.SetDefault("data_ingestion.odds_retention.compress_after_days", 7)
+	v.SetDefault("data_ingestion.odds_retention.retention_days", 730)
+	v.SetDefault("data_ingestion.odds_retention.dry_run", false)
+	v.SetDefault("tracing.sampling_rate", 0.1)
+	v.SetDefault("tracing.slow_threshold_ms", 1000)
 
 	// Read and expand the configuration file if it exists
 	if data, err := os.ReadFile(configPath); err == nil {
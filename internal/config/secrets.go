@@ -27,6 +27,7 @@ type SecretsOverlay struct {
 	BetfairUsername  string `json:"betfair_username"`
 	BetfairPassword  string `json:"betfair_password"`
 	RacingPostAPIKey string `json:"racing_post_api_key"`
+	AdminSecret      string `json:"admin_secret"`
 }
 
 // fetchSecretsFromAWS retrieves secrets from AWS Secrets Manager
@@ -85,6 +86,9 @@ func overlaySecretsOnConfig(cfg *Config, secrets *SecretsOverlay) {
 	if secrets.BetfairPassword != "" {
 		cfg.Betfair.Password = secrets.BetfairPassword
 	}
+	if secrets.AdminSecret != "" {
+		cfg.Bot.Admin.Secret = secrets.AdminSecret
+	}
 
 	if secrets.RacingPostAPIKey != "" {
 		for i, source := range cfg.DataIngestion.Sources {
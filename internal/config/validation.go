@@ -150,6 +150,18 @@ func validateCrossField(cfg *Config) error {
 		return fmt.Errorf("max_idle_connections cannot exceed max_connections")
 	}
 
+	if err := cfg.MLService.Calibration.Validate(); err != nil {
+		return fmt.Errorf("invalid ml_service.calibration: %w", err)
+	}
+
+	if err := cfg.Trading.ConfidenceScaling.Validate(); err != nil {
+		return fmt.Errorf("invalid trading.confidence_scaling: %w", err)
+	}
+
+	if err := cfg.Betfair.Maintenance.Validate(); err != nil {
+		return fmt.Errorf("invalid betfair.maintenance: %w", err)
+	}
+
 	return nil
 }
 
@@ -5,10 +5,14 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/yourusername/clever-better/internal/config"
 )
 
+// txKey is the context key under which WithTransaction stores its transaction
+type txKey struct{}
+
 // DB wraps the pgxpool.Pool to provide database operations
 type DB struct {
 	pool *pgxpool.Pool
@@ -96,7 +100,7 @@ func (db *DB) WithTransaction(ctx context.Context, fn func(context.Context) erro
 	}
 
 	// Execute function within transaction context
-	txCtx := context.WithValue(ctx, "tx", tx)
+	txCtx := context.WithValue(ctx, txKey{}, tx)
 	if err := fn(txCtx); err != nil {
 		// Rollback on error
 		rollbackErr := tx.Rollback(ctx)
@@ -114,6 +118,41 @@ func (db *DB) WithTransaction(ctx context.Context, fn func(context.Context) erro
 	return nil
 }
 
+// TxFromContext returns the transaction started by WithTransaction, if any
+func TxFromContext(ctx context.Context) (pgx.Tx, bool) {
+	tx, ok := ctx.Value(txKey{}).(pgx.Tx)
+	return tx, ok
+}
+
+// TryAdvisoryLock attempts to take a session-level advisory lock without
+// blocking. The lock is held on a dedicated pooled connection until the
+// returned unlock function is called. acquired is false when another session
+// already holds the lock.
+func (db *DB) TryAdvisoryLock(ctx context.Context, key int64) (unlock func(context.Context) error, acquired bool, err error) {
+	conn, err := db.pool.Acquire(ctx)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to acquire connection: %w", err)
+	}
+
+	if err := conn.QueryRow(ctx, "SELECT pg_try_advisory_lock($1)", key).Scan(&acquired); err != nil {
+		conn.Release()
+		return nil, false, fmt.Errorf("failed to try advisory lock: %w", err)
+	}
+	if !acquired {
+		conn.Release()
+		return nil, false, nil
+	}
+
+	unlock = func(ctx context.Context) error {
+		defer conn.Release()
+		if _, err := conn.Exec(ctx, "SELECT pg_advisory_unlock($1)", key); err != nil {
+			return fmt.Errorf("failed to release advisory lock: %w", err)
+		}
+		return nil
+	}
+	return unlock, true, nil
+}
+
 // HealthCheck performs a simple health check on the database
 func (db *DB) HealthCheck(ctx context.Context) error {
 	_, err := db.pool.Exec(ctx, "SELECT 1")
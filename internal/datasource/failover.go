@@ -0,0 +1,200 @@
+package datasource
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/yourusername/clever-better/internal/metrics"
+)
+
+// DefaultReprobeInterval is how long a failed source is passed over before
+// it is tried again
+const DefaultReprobeInterval = 5 * time.Minute
+
+// errNoRaces marks a poll that succeeded but returned nothing
+var errNoRaces = errors.New("no races returned")
+
+// FailoverSource fetches from an ordered list of sources, falling back to the
+// next when one errors, or returns no races while another has some. A failed
+// source is marked unhealthy and skipped until reprobeInterval has passed,
+// when it is tried first again; the first source is the primary, so
+// ingestion returns to it as soon as it recovers. When every source is
+// unhealthy they are all tried in order rather than none.
+type FailoverSource struct {
+	sources         []DataSource
+	reprobeInterval time.Duration
+	logger          *log.Logger
+	now             func() time.Time
+
+	mu       sync.Mutex
+	failedAt map[string]time.Time
+}
+
+// NewFailoverSource creates a failover source trying sources in order. The
+// first source is the primary and names the failover source.
+func NewFailoverSource(sources []DataSource, reprobeInterval time.Duration, logger *log.Logger) (*FailoverSource, error) {
+	if len(sources) == 0 {
+		return nil, fmt.Errorf("failover requires at least one data source")
+	}
+	if reprobeInterval <= 0 {
+		reprobeInterval = DefaultReprobeInterval
+	}
+
+	for _, source := range sources {
+		metrics.SetDataSourceHealth(source.Name(), true)
+	}
+
+	return &FailoverSource{
+		sources:         sources,
+		reprobeInterval: reprobeInterval,
+		logger:          logger,
+		now:             time.Now,
+		failedAt:        make(map[string]time.Time),
+	}, nil
+}
+
+// FetchRaces retrieves races from the first healthy source that returns any
+func (f *FailoverSource) FetchRaces(ctx context.Context, startDate, endDate time.Time) ([]RaceData, error) {
+	var races []RaceData
+	err := f.try(ctx, func(source DataSource) error {
+		fetched, err := source.FetchRaces(ctx, startDate, endDate)
+		if err != nil {
+			return err
+		}
+		if len(fetched) == 0 {
+			return errNoRaces
+		}
+		races = fetched
+		return nil
+	})
+	if errors.Is(err, errNoRaces) {
+		// Every source agreed there is nothing to ingest
+		return nil, nil
+	}
+	return races, err
+}
+
+// FetchRaceDetails retrieves a race from the first healthy source that has it
+func (f *FailoverSource) FetchRaceDetails(ctx context.Context, raceID string) (*RaceData, error) {
+	var race *RaceData
+	err := f.try(ctx, func(source DataSource) error {
+		fetched, err := source.FetchRaceDetails(ctx, raceID)
+		if err != nil {
+			return err
+		}
+		race = fetched
+		return nil
+	})
+	return race, err
+}
+
+// Name returns the primary source's name, so jobs scheduled against it use
+// the failover
+func (f *FailoverSource) Name() string {
+	return f.sources[0].Name()
+}
+
+// IsEnabled returns whether any source is enabled
+func (f *FailoverSource) IsEnabled() bool {
+	for _, source := range f.sources {
+		if source.IsEnabled() {
+			return true
+		}
+	}
+	return false
+}
+
+// Healthy reports whether the named source is currently healthy
+func (f *FailoverSource) Healthy(name string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	_, failed := f.failedAt[name]
+	return !failed
+}
+
+// try calls fetch on each candidate source until one succeeds. A source
+// returning no races only counts as failed when a later source has some, so
+// a quiet period does not mark every source unhealthy.
+func (f *FailoverSource) try(ctx context.Context, fetch func(DataSource) error) error {
+	var lastErr error
+	var empty []DataSource
+	for _, source := range f.candidates() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		err := fetch(source)
+		switch {
+		case err == nil:
+			for _, skipped := range empty {
+				f.markUnhealthy(skipped, errNoRaces)
+			}
+			f.markHealthy(source)
+			return nil
+		case errors.Is(err, errNoRaces):
+			empty = append(empty, source)
+		default:
+			f.markUnhealthy(source, err)
+			lastErr = err
+		}
+	}
+
+	if len(empty) > 0 {
+		for _, source := range empty {
+			f.markHealthy(source)
+		}
+		return errNoRaces
+	}
+	return lastErr
+}
+
+// candidates returns the enabled sources in order: healthy sources and those
+// due a re-probe first, then the unhealthy rest as a last resort
+func (f *FailoverSource) candidates() []DataSource {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	now := f.now()
+	ready := make([]DataSource, 0, len(f.sources))
+	var resting []DataSource
+	for _, source := range f.sources {
+		if !source.IsEnabled() {
+			continue
+		}
+		failedAt, failed := f.failedAt[source.Name()]
+		if failed && now.Sub(failedAt) < f.reprobeInterval {
+			resting = append(resting, source)
+			continue
+		}
+		ready = append(ready, source)
+	}
+	return append(ready, resting...)
+}
+
+func (f *FailoverSource) markHealthy(source DataSource) {
+	f.mu.Lock()
+	_, wasFailed := f.failedAt[source.Name()]
+	delete(f.failedAt, source.Name())
+	f.mu.Unlock()
+
+	metrics.SetDataSourceHealth(source.Name(), true)
+	if wasFailed && f.logger != nil {
+		f.logger.Printf("Data source %s recovered", source.Name())
+	}
+}
+
+func (f *FailoverSource) markUnhealthy(source DataSource, err error) {
+	f.mu.Lock()
+	f.failedAt[source.Name()] = f.now()
+	f.mu.Unlock()
+
+	metrics.SetDataSourceHealth(source.Name(), false)
+	metrics.RecordDataSourceFailover(source.Name())
+	if f.logger != nil {
+		f.logger.Printf("Data source %s failed, failing over: %v", source.Name(), err)
+	}
+}
@@ -0,0 +1,137 @@
+package datasource
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// stubSource returns a fixed set of races, or fails while err is set
+type stubSource struct {
+	name  string
+	races []RaceData
+	err   error
+	calls int
+}
+
+func (s *stubSource) FetchRaces(ctx context.Context, startDate, endDate time.Time) ([]RaceData, error) {
+	s.calls++
+	if s.err != nil {
+		return nil, s.err
+	}
+	return s.races, nil
+}
+
+func (s *stubSource) FetchRaceDetails(ctx context.Context, raceID string) (*RaceData, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &s.races[0], nil
+}
+
+func (s *stubSource) Name() string    { return s.name }
+func (s *stubSource) IsEnabled() bool { return true }
+
+// TestFailoverSourceFallsBackAndRecovers tests ingestion continues through
+// the secondary while the primary fails, and returns to the primary once a
+// re-probe succeeds
+func TestFailoverSourceFallsBackAndRecovers(t *testing.T) {
+	primary := &stubSource{name: "betfair_historical", races: []RaceData{{SourceID: "primary"}}, err: errors.New("connection refused")}
+	secondary := &stubSource{name: "racing_post", races: []RaceData{{SourceID: "secondary"}}}
+
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	failover, err := NewFailoverSource([]DataSource{primary, secondary}, time.Minute, nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	failover.now = func() time.Time { return now }
+
+	if failover.Name() != "betfair_historical" {
+		t.Errorf("Expected the primary's name, got %s", failover.Name())
+	}
+
+	races, err := failover.FetchRaces(context.Background(), now, now.Add(time.Hour))
+	if err != nil || len(races) != 1 || races[0].SourceID != "secondary" {
+		t.Fatalf("Expected the secondary's races, got %v (%v)", races, err)
+	}
+	if failover.Healthy("betfair_historical") || !failover.Healthy("racing_post") {
+		t.Error("Expected the primary to be unhealthy and the secondary healthy")
+	}
+
+	// Before the re-probe interval the primary is not asked again
+	now = now.Add(30 * time.Second)
+	if _, err := failover.FetchRaces(context.Background(), now, now.Add(time.Hour)); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if primary.calls != 1 {
+		t.Errorf("Expected the primary to be skipped, got %d calls", primary.calls)
+	}
+
+	// Once it is due, the recovered primary is used again
+	primary.err = nil
+	now = now.Add(time.Minute)
+	races, err = failover.FetchRaces(context.Background(), now, now.Add(time.Hour))
+	if err != nil || len(races) != 1 || races[0].SourceID != "primary" {
+		t.Fatalf("Expected the primary's races, got %v (%v)", races, err)
+	}
+	if !failover.Healthy("betfair_historical") {
+		t.Error("Expected the primary to be healthy after recovering")
+	}
+	if secondary.calls != 2 {
+		t.Errorf("Expected the secondary to be left alone after recovery, got %d calls", secondary.calls)
+	}
+}
+
+// TestFailoverSourceEmptyPoll tests an empty primary fails over, but a poll
+// where every source is empty marks none unhealthy
+func TestFailoverSourceEmptyPoll(t *testing.T) {
+	primary := &stubSource{name: "betfair_historical"}
+	secondary := &stubSource{name: "racing_post"}
+
+	failover, err := NewFailoverSource([]DataSource{primary, secondary}, time.Minute, nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	races, err := failover.FetchRaces(context.Background(), time.Now(), time.Now().Add(time.Hour))
+	if err != nil || len(races) != 0 {
+		t.Fatalf("Expected no races and no error, got %v (%v)", races, err)
+	}
+	if !failover.Healthy("betfair_historical") || !failover.Healthy("racing_post") {
+		t.Error("Expected both sources to stay healthy when neither has races")
+	}
+
+	secondary.races = []RaceData{{SourceID: "secondary"}}
+	races, err = failover.FetchRaces(context.Background(), time.Now(), time.Now().Add(time.Hour))
+	if err != nil || len(races) != 1 {
+		t.Fatalf("Expected the secondary's races, got %v (%v)", races, err)
+	}
+	if failover.Healthy("betfair_historical") {
+		t.Error("Expected the empty primary to be unhealthy")
+	}
+}
+
+// TestFailoverSourceAllFailing tests the last error is returned when every
+// source fails
+func TestFailoverSourceAllFailing(t *testing.T) {
+	primary := &stubSource{name: "betfair_historical", err: errors.New("primary down")}
+	secondary := &stubSource{name: "racing_post", err: errors.New("secondary down")}
+
+	failover, err := NewFailoverSource([]DataSource{primary, secondary}, time.Minute, nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if _, err := failover.FetchRaces(context.Background(), time.Now(), time.Now().Add(time.Hour)); err == nil || err.Error() != "secondary down" {
+		t.Errorf("Expected the secondary's error, got %v", err)
+	}
+
+	// Unhealthy sources are still tried as a last resort
+	if _, err := failover.FetchRaces(context.Background(), time.Now(), time.Now().Add(time.Hour)); err == nil {
+		t.Error("Expected an error")
+	}
+	if primary.calls != 2 || secondary.calls != 2 {
+		t.Errorf("Expected both sources to be tried again, got %d and %d calls", primary.calls, secondary.calls)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"net/http"
 	"os"
+	"sort"
 	"sync"
 	"time"
 
@@ -18,13 +19,37 @@ type DatabasePinger interface {
 	Ping(ctx context.Context) error
 }
 
+// CheckFunc reports the health of a single dependency.
+type CheckFunc func(ctx context.Context) error
+
+// Health statuses reported by the /health endpoint.
+const (
+	StatusOK       = "ok"
+	StatusDegraded = "degraded"
+	StatusError    = "error"
+)
+
+// checkTimeout bounds how long a single dependency check may take.
+const checkTimeout = 3 * time.Second
+
+// CheckResult represents the outcome of one dependency check.
+type CheckResult struct {
+	Name        string  `json:"name"`
+	Status      string  `json:"status"`
+	LastChecked string  `json:"last_checked"`
+	LatencyMs   float64 `json:"latency_ms"`
+	Error       string  `json:"error,omitempty"`
+}
+
 // HealthResponse represents the JSON response for health check endpoints.
 type HealthResponse struct {
-	Status    string `json:"status"`
-	Service   string `json:"service"`
-	Timestamp string `json:"timestamp,omitempty"`
-	Version   string `json:"version,omitempty"`
-	Commit    string `json:"commit,omitempty"`
+	Status    string        `json:"status"`
+	Service   string        `json:"service"`
+	Timestamp string        `json:"timestamp,omitempty"`
+	Version   string        `json:"version,omitempty"`
+	Commit    string        `json:"commit,omitempty"`
+	BuildDate string        `json:"build_date,omitempty"`
+	Checks    []CheckResult `json:"checks,omitempty"`
 }
 
 // ReadyResponse represents the JSON response for readiness check endpoints.
@@ -40,10 +65,13 @@ type Server struct {
 	serviceName string
 	version     string
 	commit      string
+	buildDate   string
 	port        string
 	server      *http.Server
 	logger      *logrus.Logger
 	db          DatabasePinger
+	checks      map[string]CheckFunc
+	mux         *http.ServeMux
 	mu          sync.RWMutex
 	ready       bool
 }
@@ -53,6 +81,7 @@ type Config struct {
 	ServiceName string
 	Version     string
 	Commit      string
+	BuildDate   string
 	Port        string
 	Logger      *logrus.Logger
 	DB          DatabasePinger
@@ -68,15 +97,39 @@ func NewServer(cfg Config) *Server {
 		port = "8080"
 	}
 
-	return &Server{
+	s := &Server{
 		serviceName: cfg.ServiceName,
 		version:     cfg.Version,
 		commit:      cfg.Commit,
+		buildDate:   cfg.BuildDate,
 		port:        port,
 		logger:      cfg.Logger,
 		db:          cfg.DB,
+		checks:      make(map[string]CheckFunc),
+		mux:         http.NewServeMux(),
 		ready:       false,
 	}
+	s.mux.HandleFunc("/health", s.handleHealth)
+	s.mux.HandleFunc("/ready", s.handleReady)
+	s.mux.HandleFunc("/live", s.handleLive)
+	s.mux.HandleFunc("/livez", s.handleLive)
+	if cfg.DB != nil {
+		s.RegisterCheck("database", cfg.DB.Ping)
+	}
+	return s
+}
+
+// RegisterCheck adds a dependency check reported by the /health endpoint.
+func (s *Server) RegisterCheck(name string, check CheckFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.checks[name] = check
+}
+
+// Handle registers an extra endpoint, such as a webhook, served alongside the
+// health endpoints. It may be called before or after Start.
+func (s *Server) Handle(pattern string, handler http.Handler) {
+	s.mux.Handle(pattern, handler)
 }
 
 // SetReady marks the server as ready to accept traffic.
@@ -95,14 +148,9 @@ func (s *Server) IsReady() bool {
 
 // Start starts the health check server in the background.
 func (s *Server) Start(ctx context.Context) error {
-	mux := http.NewServeMux()
-	mux.HandleFunc("/health", s.handleHealth)
-	mux.HandleFunc("/ready", s.handleReady)
-	mux.HandleFunc("/live", s.handleLive)
-
 	s.server = &http.Server{
 		Addr:         ":" + s.port,
-		Handler:      mux,
+		Handler:      s.mux,
 		ReadTimeout:  5 * time.Second,
 		WriteTimeout: 10 * time.Second,
 		IdleTimeout:  60 * time.Second,
@@ -149,14 +197,29 @@ func (s *Server) Shutdown() error {
 	return s.server.Shutdown(ctx)
 }
 
-// handleHealth handles the /health endpoint - basic liveness check.
+// handleHealth handles the /health endpoint. It runs every registered
+// dependency check and reports "degraded" if any fail. The response is
+// always 200 so container health checks only restart on liveness; use
+// /ready to gate traffic.
 func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	checks := s.runChecks(r.Context())
+
+	status := StatusOK
+	for _, check := range checks {
+		if check.Status != StatusOK {
+			status = StatusDegraded
+			break
+		}
+	}
+
 	response := HealthResponse{
-		Status:    "ok",
+		Status:    status,
 		Service:   s.serviceName,
 		Timestamp: time.Now().UTC().Format(time.RFC3339),
 		Version:   s.version,
 		Commit:    s.commit,
+		BuildDate: s.buildDate,
+		Checks:    checks,
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -164,7 +227,56 @@ func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(response)
 }
 
-// handleLive handles the /live endpoint - kubernetes liveness probe.
+// runChecks runs all registered checks concurrently, sorted by name.
+func (s *Server) runChecks(ctx context.Context) []CheckResult {
+	s.mu.RLock()
+	checks := make(map[string]CheckFunc, len(s.checks))
+	for name, check := range s.checks {
+		checks[name] = check
+	}
+	s.mu.RUnlock()
+
+	results := make([]CheckResult, 0, len(checks))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for name, check := range checks {
+		wg.Add(1)
+		go func(name string, check CheckFunc) {
+			defer wg.Done()
+			result := runCheck(ctx, name, check)
+			mu.Lock()
+			results = append(results, result)
+			mu.Unlock()
+		}(name, check)
+	}
+	wg.Wait()
+
+	sort.Slice(results, func(i, j int) bool { return results[i].Name < results[j].Name })
+	return results
+}
+
+// runCheck runs a single check with a timeout and records its latency.
+func runCheck(ctx context.Context, name string, check CheckFunc) CheckResult {
+	ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+	defer cancel()
+
+	start := time.Now()
+	err := check(ctx)
+	result := CheckResult{
+		Name:        name,
+		Status:      StatusOK,
+		LastChecked: start.UTC().Format(time.RFC3339Nano),
+		LatencyMs:   float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if err != nil {
+		result.Status = StatusError
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// handleLive handles the /live and /livez endpoints - liveness probe that
+// never touches dependencies.
 func (s *Server) handleLive(w http.ResponseWriter, r *http.Request) {
 	response := HealthResponse{
 		Status:  "ok",
@@ -192,7 +304,7 @@ func (s *Server) handleReady(w http.ResponseWriter, r *http.Request) {
 
 	// Check database connectivity if available
 	if s.db != nil {
-		ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
+		ctx, cancel := context.WithTimeout(r.Context(), checkTimeout)
 		defer cancel()
 
 		if err := s.db.Ping(ctx); err != nil {
@@ -0,0 +1,98 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakePinger struct {
+	err error
+}
+
+func (p fakePinger) Ping(ctx context.Context) error { return p.err }
+
+func newTestServer(db DatabasePinger) *Server {
+	s := NewServer(Config{
+		ServiceName: "bot",
+		Version:     "1.2.3",
+		Commit:      "abc123",
+		BuildDate:   "2024-03-01",
+		Port:        "0",
+		DB:          db,
+	})
+	s.RegisterCheck("ml", func(ctx context.Context) error { return nil })
+	s.RegisterCheck("betfair", func(ctx context.Context) error { return nil })
+	return s
+}
+
+func getHealth(t *testing.T, s *Server) HealthResponse {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	s.handleHealth(rec, httptest.NewRequest(http.MethodGet, "/health", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var response HealthResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&response))
+	return response
+}
+
+func TestHealthReportsEachCheck(t *testing.T) {
+	response := getHealth(t, newTestServer(fakePinger{}))
+
+	assert.Equal(t, StatusOK, response.Status)
+	assert.Equal(t, "1.2.3", response.Version)
+	assert.Equal(t, "abc123", response.Commit)
+	assert.Equal(t, "2024-03-01", response.BuildDate)
+
+	require.Len(t, response.Checks, 3)
+	names := make([]string, 0, len(response.Checks))
+	for _, check := range response.Checks {
+		names = append(names, check.Name)
+		assert.Equal(t, StatusOK, check.Status)
+		assert.NotEmpty(t, check.LastChecked)
+		assert.GreaterOrEqual(t, check.LatencyMs, 0.0)
+		assert.Empty(t, check.Error)
+	}
+	assert.Equal(t, []string{"betfair", "database", "ml"}, names)
+}
+
+func TestHealthDegradedWhenCheckFails(t *testing.T) {
+	s := newTestServer(fakePinger{err: errors.New("connection refused")})
+
+	response := getHealth(t, s)
+	assert.Equal(t, StatusDegraded, response.Status)
+	for _, check := range response.Checks {
+		if check.Name == "database" {
+			assert.Equal(t, StatusError, check.Status)
+			assert.Equal(t, "connection refused", check.Error)
+			continue
+		}
+		assert.Equal(t, StatusOK, check.Status)
+	}
+
+	// Liveness must not depend on dependency checks
+	rec := httptest.NewRecorder()
+	s.handleLive(rec, httptest.NewRequest(http.MethodGet, "/livez", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var live HealthResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&live))
+	assert.Equal(t, StatusOK, live.Status)
+	assert.Empty(t, live.Checks)
+}
+
+func TestReadyFailsWhenDatabaseDown(t *testing.T) {
+	s := newTestServer(fakePinger{err: errors.New("connection refused")})
+	s.SetReady(true)
+
+	rec := httptest.NewRecorder()
+	s.handleReady(rec, httptest.NewRequest(http.MethodGet, "/ready", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+}
@@ -0,0 +1,84 @@
+package logger
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// RequestIDHeader carries the request ID on outgoing HTTP and gRPC calls
+const RequestIDHeader = "X-Request-ID"
+
+type requestIDKey struct{}
+
+// NewRequestID returns a new random request ID
+func NewRequestID() string {
+	return uuid.NewString()
+}
+
+// WithRequestID returns a context carrying the request ID
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestIDFromContext returns the request ID carried by ctx, or ""
+func RequestIDFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
+
+// TraceFunc returns the trace and span IDs active in ctx, or empty strings
+type TraceFunc func(ctx context.Context) (traceID, spanID string)
+
+// CorrelationHook adds the request ID and, when a TraceFunc is set, the
+// active trace and span IDs to every entry logged with a context, e.g. via
+// logger.WithContext(ctx)
+type CorrelationHook struct {
+	traceFunc TraceFunc
+}
+
+// Levels returns the levels the hook fires for
+func (h *CorrelationHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire adds correlation fields from the entry's context
+func (h *CorrelationHook) Fire(entry *logrus.Entry) error {
+	ctx := entry.Context
+	if ctx == nil {
+		return nil
+	}
+
+	if requestID := RequestIDFromContext(ctx); requestID != "" {
+		entry.Data["request_id"] = requestID
+	}
+	if h.traceFunc != nil {
+		traceID, spanID := h.traceFunc(ctx)
+		if traceID != "" {
+			entry.Data["trace_id"] = traceID
+		}
+		if spanID != "" {
+			entry.Data["span_id"] = spanID
+		}
+	}
+	return nil
+}
+
+// AddCorrelationHook installs the correlation hook on logger. If one is
+// already installed it is reused, and traceFunc replaces its trace source
+// when non-nil.
+func AddCorrelationHook(logger *logrus.Logger, traceFunc TraceFunc) {
+	for _, hook := range logger.Hooks[logrus.InfoLevel] {
+		if existing, ok := hook.(*CorrelationHook); ok {
+			if traceFunc != nil {
+				existing.traceFunc = traceFunc
+			}
+			return
+		}
+	}
+	logger.AddHook(&CorrelationHook{traceFunc: traceFunc})
+}
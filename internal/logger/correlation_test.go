@@ -0,0 +1,70 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type traceKey struct{}
+
+// fakeTrace reads trace and span IDs stored in the context by the test
+func fakeTrace(ctx context.Context) (string, string) {
+	ids, ok := ctx.Value(traceKey{}).([2]string)
+	if !ok {
+		return "", ""
+	}
+	return ids[0], ids[1]
+}
+
+func newCorrelationLogger(buf *bytes.Buffer) *logrus.Logger {
+	log := logrus.New()
+	log.SetOutput(buf)
+	log.SetFormatter(&logrus.JSONFormatter{})
+	AddCorrelationHook(log, fakeTrace)
+	return log
+}
+
+func TestCorrelationHookAddsTraceAndRequestIDs(t *testing.T) {
+	var buf bytes.Buffer
+	log := newCorrelationLogger(&buf)
+
+	ctx := context.WithValue(context.Background(), traceKey{}, [2]string{"1-abc-def", "span-1"})
+	ctx = WithRequestID(ctx, "req-1")
+	log.WithContext(ctx).WithField("race_id", "r1").Info("Evaluating race")
+
+	var entry map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	assert.Equal(t, "req-1", entry["request_id"])
+	assert.Equal(t, "1-abc-def", entry["trace_id"])
+	assert.Equal(t, "span-1", entry["span_id"])
+	assert.Equal(t, "r1", entry["race_id"])
+}
+
+func TestCorrelationHookWithoutContext(t *testing.T) {
+	var buf bytes.Buffer
+	log := newCorrelationLogger(&buf)
+
+	log.Info("No context")
+
+	var entry map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	assert.NotContains(t, entry, "request_id")
+	assert.NotContains(t, entry, "trace_id")
+	assert.NotContains(t, entry, "span_id")
+}
+
+func TestAddCorrelationHookReusesExistingHook(t *testing.T) {
+	log := logrus.New()
+	AddCorrelationHook(log, nil)
+	AddCorrelationHook(log, fakeTrace)
+
+	require.Len(t, log.Hooks[logrus.InfoLevel], 1)
+	hook := log.Hooks[logrus.InfoLevel][0].(*CorrelationHook)
+	assert.NotNil(t, hook.traceFunc)
+}
@@ -2,36 +2,104 @@
 package logger
 
 import (
+	"fmt"
+	"io"
 	"os"
 
 	"github.com/sirupsen/logrus"
+	"github.com/yourusername/clever-better/internal/config"
 )
 
-// NewLogger creates a new configured logger instance
+// Log formats and outputs accepted in app.log
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+
+	OutputStdout = "stdout"
+	OutputFile   = "file"
+	OutputBoth   = "both"
+)
+
+// NewLogger creates a new configured logger instance writing to stdout
 func NewLogger(logLevel string) *logrus.Logger {
 	logger := logrus.New()
-	
-	// Set output to stdout
-	logger.SetOutput(os.Stdout)
-	
+	// Stdout output cannot fail to configure
+	_ = Configure(logger, config.AppConfig{
+		Environment: os.Getenv("ENVIRONMENT"),
+		LogLevel:    logLevel,
+	})
+	return logger
+}
+
+// NewFromConfig creates a logger with the level, format and output from the
+// application config
+func NewFromConfig(cfg config.AppConfig) (*logrus.Logger, error) {
+	logger := logrus.New()
+	if err := Configure(logger, cfg); err != nil {
+		return nil, err
+	}
+	return logger, nil
+}
+
+// Configure applies the level, format and output from the application config
+// to an existing logger. Binaries that log before loading their config use it
+// to switch over once the config is available.
+func Configure(logger *logrus.Logger, cfg config.AppConfig) error {
+	output, err := newOutput(cfg.Log)
+	if err != nil {
+		return err
+	}
+	logger.SetOutput(output)
+	AddCorrelationHook(logger, nil)
+
 	// Parse and set log level
-	level, err := logrus.ParseLevel(logLevel)
+	level, err := logrus.ParseLevel(cfg.LogLevel)
 	if err != nil {
-		logger.Warnf("Invalid log level '%s', defaulting to info", logLevel)
+		logger.Warnf("Invalid log level '%s', defaulting to info", cfg.LogLevel)
 		level = logrus.InfoLevel
 	}
 	logger.SetLevel(level)
-	
-	// Use JSON formatter for structured logging in production
-	if os.Getenv("ENVIRONMENT") == "production" {
+
+	format := cfg.Log.Format
+	if format == "" {
+		// Use JSON formatter for structured logging in production
+		format = FormatText
+		if cfg.Environment == "production" {
+			format = FormatJSON
+		}
+	}
+
+	switch format {
+	case FormatJSON:
 		logger.SetFormatter(&logrus.JSONFormatter{})
-	} else {
-		// Use text formatter with colors for development
+	case FormatText:
+		// Colors only make sense on a terminal
 		logger.SetFormatter(&logrus.TextFormatter{
 			FullTimestamp: true,
-			ForceColors:   true,
+			ForceColors:   cfg.Log.Output == "" || cfg.Log.Output == OutputStdout,
 		})
+	default:
+		return fmt.Errorf("unknown log format: %s", format)
+	}
+
+	return nil
+}
+
+// newOutput builds the log writer for the configured output
+func newOutput(cfg config.LogConfig) (io.Writer, error) {
+	switch cfg.Output {
+	case "", OutputStdout:
+		return os.Stdout, nil
+	case OutputFile, OutputBoth:
+		file, err := NewRotatingFile(cfg.FilePath, int64(cfg.MaxSizeMB)*1024*1024, cfg.MaxBackups)
+		if err != nil {
+			return nil, err
+		}
+		if cfg.Output == OutputBoth {
+			return io.MultiWriter(os.Stdout, file), nil
+		}
+		return file, nil
+	default:
+		return nil, fmt.Errorf("unknown log output: %s", cfg.Output)
 	}
-	
-	return logger
 }
@@ -3,12 +3,15 @@ package logger
 import (
 	"bytes"
 	"encoding/json"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/yourusername/clever-better/internal/config"
 )
 
 func setupTestLogger() (*logrus.Logger, *bytes.Buffer) {
@@ -253,3 +256,55 @@ func BenchmarkAuditLoggerBetPlacement(b *testing.B) {
 		)
 	}
 }
+
+func TestConfigureJSONFormat(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	log, err := NewFromConfig(config.AppConfig{
+		Environment: "development",
+		LogLevel:    "info",
+		Log:         config.LogConfig{Format: FormatJSON, Output: OutputFile, FilePath: path},
+	})
+	require.NoError(t, err)
+
+	log.WithField("component", "test").Info("hello")
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var entry map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &entry))
+	assert.Equal(t, "hello", entry["msg"])
+	assert.Equal(t, "test", entry["component"])
+}
+
+func TestConfigureTextFormat(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	log, err := NewFromConfig(config.AppConfig{
+		Environment: "production",
+		LogLevel:    "info",
+		Log:         config.LogConfig{Format: FormatText, Output: OutputFile, FilePath: path},
+	})
+	require.NoError(t, err)
+
+	log.WithField("component", "test").Info("hello")
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `msg=hello`)
+	assert.Contains(t, string(data), `component=test`)
+	assert.NotContains(t, string(data), "\x1b[", "no colour codes in files")
+}
+
+func TestConfigureDefaultsFormatByEnvironment(t *testing.T) {
+	log := logrus.New()
+	require.NoError(t, Configure(log, config.AppConfig{Environment: "production", LogLevel: "warn"}))
+	assert.IsType(t, &logrus.JSONFormatter{}, log.Formatter)
+	assert.Equal(t, logrus.WarnLevel, log.Level)
+
+	require.NoError(t, Configure(log, config.AppConfig{Environment: "development", LogLevel: "debug"}))
+	assert.IsType(t, &logrus.TextFormatter{}, log.Formatter)
+}
+
+func TestConfigureRejectsUnknownOutput(t *testing.T) {
+	_, err := NewFromConfig(config.AppConfig{LogLevel: "info", Log: config.LogConfig{Output: "syslog"}})
+	assert.Error(t, err)
+}
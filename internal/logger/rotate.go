@@ -0,0 +1,108 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// RotatingFile is an io.Writer that appends to a log file and rotates it
+// once it reaches a maximum size. Rotated files are renamed path.1, path.2,
+// and so on, newest first, keeping at most maxBackups of them.
+type RotatingFile struct {
+	path       string
+	maxBytes   int64
+	maxBackups int
+	file       *os.File
+	size       int64
+	mu         sync.Mutex
+}
+
+// NewRotatingFile opens path for appending, creating it and its directory if
+// needed. A maxBytes of zero disables rotation.
+func NewRotatingFile(path string, maxBytes int64, maxBackups int) (*RotatingFile, error) {
+	if path == "" {
+		return nil, fmt.Errorf("log file path is required")
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create log directory: %w", err)
+	}
+
+	r := &RotatingFile{
+		path:       path,
+		maxBytes:   maxBytes,
+		maxBackups: maxBackups,
+	}
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Write appends p to the file, rotating first if p would take the file past
+// the maximum size
+func (r *RotatingFile) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.maxBytes > 0 && r.size > 0 && r.size+int64(len(p)) > r.maxBytes {
+		if err := r.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := r.file.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+// Close closes the current file
+func (r *RotatingFile) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.file.Close()
+}
+
+func (r *RotatingFile) open() error {
+	file, err := os.OpenFile(r.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open log file: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("failed to stat log file: %w", err)
+	}
+	r.file = file
+	r.size = info.Size()
+	return nil
+}
+
+// rotate shifts the backups along, moves the current file to path.1 and
+// opens a fresh file
+func (r *RotatingFile) rotate() error {
+	if err := r.file.Close(); err != nil {
+		return fmt.Errorf("failed to close log file: %w", err)
+	}
+
+	if r.maxBackups > 0 {
+		_ = os.Remove(r.backupName(r.maxBackups))
+		for i := r.maxBackups - 1; i >= 1; i-- {
+			if err := os.Rename(r.backupName(i), r.backupName(i+1)); err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf("failed to rotate log backup: %w", err)
+			}
+		}
+		if err := os.Rename(r.path, r.backupName(1)); err != nil {
+			return fmt.Errorf("failed to rotate log file: %w", err)
+		}
+	} else if err := os.Remove(r.path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to truncate log file: %w", err)
+	}
+
+	return r.open()
+}
+
+func (r *RotatingFile) backupName(n int) string {
+	return fmt.Sprintf("%s.%d", r.path, n)
+}
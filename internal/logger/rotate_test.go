@@ -0,0 +1,77 @@
+package logger
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRotatingFileRotatesAtMaxSize(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "app.log")
+	file, err := NewRotatingFile(path, 100, 2)
+	require.NoError(t, err)
+	defer file.Close()
+
+	line := strings.Repeat("a", 39) + "\n"
+
+	// Two 40 byte lines fit in 100 bytes; the third rotates
+	for i := 0; i < 2; i++ {
+		_, err := file.Write([]byte(line))
+		require.NoError(t, err)
+	}
+	assert.NoFileExists(t, path+".1")
+
+	_, err = file.Write([]byte(line))
+	require.NoError(t, err)
+	assert.FileExists(t, path+".1")
+
+	rotated, err := os.ReadFile(path + ".1")
+	require.NoError(t, err)
+	assert.Len(t, rotated, 80)
+
+	current, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Len(t, current, 40)
+}
+
+func TestRotatingFileKeepsMaxBackups(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	file, err := NewRotatingFile(path, 10, 2)
+	require.NoError(t, err)
+	defer file.Close()
+
+	for _, line := range []string{"first....\n", "second...\n", "third....\n", "fourth...\n"} {
+		_, err := file.Write([]byte(line))
+		require.NoError(t, err)
+	}
+
+	assertFileContent(t, path, "fourth...\n")
+	assertFileContent(t, path+".1", "third....\n")
+	assertFileContent(t, path+".2", "second...\n")
+	assert.NoFileExists(t, path+".3")
+}
+
+func TestRotatingFileResumesExistingSize(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	require.NoError(t, os.WriteFile(path, []byte("previous run\n"), 0o644))
+
+	file, err := NewRotatingFile(path, 20, 1)
+	require.NoError(t, err)
+	defer file.Close()
+
+	_, err = file.Write([]byte("next run\n"))
+	require.NoError(t, err)
+	assertFileContent(t, path+".1", "previous run\n")
+	assertFileContent(t, path, "next run\n")
+}
+
+func assertFileContent(t *testing.T, path, expected string) {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, expected, string(data))
+}
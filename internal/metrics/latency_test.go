@@ -0,0 +1,28 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func histogramCount(t *testing.T, observer prometheus.Observer) uint64 {
+	t.Helper()
+	var m dto.Metric
+	require.NoError(t, observer.(prometheus.Metric).Write(&m))
+	return m.GetHistogram().GetSampleCount()
+}
+
+func TestRecordBetPlacementLatencyByMode(t *testing.T) {
+	InitRegistry()
+	paper := histogramCount(t, BetPlacementLatency.WithLabelValues(ModePaper))
+	live := histogramCount(t, BetPlacementLatency.WithLabelValues(ModeLive))
+
+	RecordBetPlacementLatency(ModeLive, 0.12)
+
+	assert.Equal(t, paper, histogramCount(t, BetPlacementLatency.WithLabelValues(ModePaper)))
+	assert.Equal(t, live+1, histogramCount(t, BetPlacementLatency.WithLabelValues(ModeLive)))
+}
@@ -78,14 +78,90 @@ var (
 	}, []string{"strategy_id", "strategy_name"})
 )
 
+// Bot status metrics, exported from the orchestrator's status each trading tick
+var (
+	BotRunning = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: "clever_better",
+		Name:      "bot_running",
+		Help:      "Whether the trading bot is running (1) or stopped (0)",
+	})
+	PausedStrategies = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: "clever_better",
+		Name:      "paused_strategies",
+		Help:      "Number of strategies paused by the monitor",
+	})
+	CircuitBreakerState = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: "clever_better",
+		Name:      "circuit_breaker_state",
+		Help:      "Circuit breaker state: 0 closed, 1 half-open, 2 open",
+	})
+	DailyLoss = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: "clever_better",
+		Name:      "daily_loss",
+		Help:      "Loss so far today counted against the daily loss limit",
+	})
+	MaxDailyLoss = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: "clever_better",
+		Name:      "max_daily_loss",
+		Help:      "Configured daily loss limit",
+	})
+	MaxExposure = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: "clever_better",
+		Name:      "max_exposure",
+		Help:      "Configured exposure cap",
+	})
+	ReservedExposure = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: "clever_better",
+		Name:      "reserved_exposure",
+		Help:      "Stake held for signals that passed their risk check but have no bet yet",
+	})
+	RemainingExposureCapacity = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: "clever_better",
+		Name:      "remaining_exposure_capacity",
+		Help:      "Exposure that can still be taken on before the cap",
+	})
+	KillSwitchEngaged = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: "clever_better",
+		Name:      "kill_switch_engaged",
+		Help:      "Whether the kill switch is halting trading (1) or not (0)",
+	})
+	WarmingUp = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: "clever_better",
+		Name:      "warming_up",
+		Help:      "Whether the bot is in its startup warm-up (1) or not (0)",
+	})
+	MaintenancePaused = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: "clever_better",
+		Name:      "maintenance_paused",
+		Help:      "Whether trading is paused for a Betfair maintenance window (1) or not (0)",
+	})
+)
+
+// BotStatus is the orchestrator state exported by UpdateBotStatus
+type BotStatus struct {
+	Running             bool
+	ActiveStrategies    int
+	PausedStrategies    int
+	CircuitBreakerState float64
+	Exposure            float64
+	ReservedExposure    float64
+	RemainingCapacity   float64
+	MaxExposure         float64
+	DailyLoss           float64
+	MaxDailyLoss        float64
+	KillSwitchEngaged   bool
+	WarmingUp           bool
+	MaintenancePaused   bool
+}
+
 // Histogram metrics
 var (
-	BetPlacementLatency = prometheus.NewHistogram(prometheus.HistogramOpts{
+	BetPlacementLatency = prometheus.NewHistogramVec(prometheus.HistogramOpts{
 		Namespace: "clever_better",
 		Name:      "bet_placement_latency_seconds",
 		Help:      "Latency of bet placement operations in seconds",
 		Buckets:   prometheus.DefBuckets,
-	})
+	}, []string{"mode"})
 	StrategyEvaluationDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
 		Namespace: "clever_better",
 		Name:      "strategy_evaluation_duration_seconds",
@@ -98,6 +174,67 @@ var (
 		Help:      "Duration of backtest runs in seconds",
 		Buckets:   []float64{1, 5, 10, 30, 60, 300, 600, 1800},
 	})
+	BetMatchTime = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: "clever_better",
+		Name:      "bet_match_time_seconds",
+		Help:      "Time from bet placement to full match in seconds",
+		Buckets:   []float64{0.5, 1, 5, 15, 30, 60, 120, 300, 600},
+	})
+)
+
+// Cache metrics
+var (
+	MarketCatalogCacheTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "clever_better",
+		Name:      "market_catalog_cache_total",
+		Help:      "Market catalogue lookups by cache result",
+	}, []string{"result"})
+)
+
+// Data source metrics
+var (
+	DataSourceHealthy = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "clever_better",
+		Name:      "data_source_healthy",
+		Help:      "Whether each ingestion data source is healthy (1) or failed over (0)",
+	}, []string{"source"})
+	DataSourceFailoversTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "clever_better",
+		Name:      "data_source_failovers_total",
+		Help:      "Total number of polls that failed over away from each data source",
+	}, []string{"source"})
+)
+
+// Odds retention metrics
+var (
+	OddsRetentionChunksTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "clever_better",
+		Name:      "odds_retention_chunks_total",
+		Help:      "Total number of odds_snapshots chunks compressed or dropped by retention",
+	}, []string{"action"})
+	OddsRetentionBytesReclaimedTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "clever_better",
+		Name:      "odds_retention_bytes_reclaimed_total",
+		Help:      "Total bytes reclaimed from odds_snapshots by compression and retention",
+	})
+)
+
+// Odds retention actions used as the "action" label
+const (
+	RetentionCompressed = "compressed"
+	RetentionDropped    = "dropped"
+)
+
+// Cache results used as the "result" label
+const (
+	CacheHit  = "hit"
+	CacheMiss = "miss"
+)
+
+// Order placement modes used as the "mode" label
+const (
+	ModePaper = "paper"
+	ModeLive  = "live"
 )
 
 // InitRegistry initializes the global Prometheus registry.
@@ -120,16 +257,46 @@ func InitRegistry() *prometheus.Registry {
 		registry.MustRegister(DailyPnL)
 		registry.MustRegister(StrategyCompositeScore)
 
+		// Register bot status metrics
+		registry.MustRegister(BotRunning)
+		registry.MustRegister(PausedStrategies)
+		registry.MustRegister(CircuitBreakerState)
+		registry.MustRegister(DailyLoss)
+		registry.MustRegister(MaxDailyLoss)
+		registry.MustRegister(MaxExposure)
+		registry.MustRegister(ReservedExposure)
+		registry.MustRegister(RemainingExposureCapacity)
+		registry.MustRegister(KillSwitchEngaged)
+		registry.MustRegister(WarmingUp)
+		registry.MustRegister(MaintenancePaused)
+
 		// Register histogram metrics
 		registry.MustRegister(BetPlacementLatency)
 		registry.MustRegister(StrategyEvaluationDuration)
 		registry.MustRegister(BacktestDuration)
+		registry.MustRegister(BetMatchTime)
+
+		// Register cache metrics
+		registry.MustRegister(MarketCatalogCacheTotal)
+
+		// Register data source metrics
+		registry.MustRegister(DataSourceHealthy)
+		registry.MustRegister(DataSourceFailoversTotal)
+
+		// Register odds retention metrics
+		registry.MustRegister(OddsRetentionChunksTotal)
+		registry.MustRegister(OddsRetentionBytesReclaimedTotal)
 
 		// Register strategy metrics
 		registry.MustRegister(StrategyDecisionsTotal)
 		registry.MustRegister(StrategyConfidenceScore)
 		registry.MustRegister(StrategyActiveBets)
+		registry.MustRegister(StrategyUnknownType)
 		registry.MustRegister(MLStrategyRecommendationsTotal)
+		registry.MustRegister(StrategyDeactivationsTotal)
+		registry.MustRegister(StrategyEvaluationTimeoutsTotal)
+		registry.MustRegister(MLObservedPredictionsTotal)
+		registry.MustRegister(MLProbabilityDivergence)
 
 		// Register backtest metrics
 		registry.MustRegister(BacktestRunsTotal)
@@ -203,9 +370,72 @@ func UpdateDailyPnL(pnl float64) {
 	DailyPnL.Set(pnl)
 }
 
-// RecordBetPlacementLatency records bet placement latency.
-func RecordBetPlacementLatency(durationSeconds float64) {
-	BetPlacementLatency.Observe(durationSeconds)
+// UpdateBotStatus sets the bot status gauges, including the active
+// strategies and total exposure gauges.
+func UpdateBotStatus(status BotStatus) {
+	BotRunning.Set(boolGauge(status.Running))
+	ActiveStrategies.Set(float64(status.ActiveStrategies))
+	PausedStrategies.Set(float64(status.PausedStrategies))
+	CircuitBreakerState.Set(status.CircuitBreakerState)
+	TotalExposure.Set(status.Exposure)
+	ReservedExposure.Set(status.ReservedExposure)
+	RemainingExposureCapacity.Set(status.RemainingCapacity)
+	MaxExposure.Set(status.MaxExposure)
+	DailyLoss.Set(status.DailyLoss)
+	MaxDailyLoss.Set(status.MaxDailyLoss)
+	KillSwitchEngaged.Set(boolGauge(status.KillSwitchEngaged))
+	WarmingUp.Set(boolGauge(status.WarmingUp))
+	MaintenancePaused.Set(boolGauge(status.MaintenancePaused))
+}
+
+// boolGauge converts a flag to a gauge value
+func boolGauge(value bool) float64 {
+	if value {
+		return 1
+	}
+	return 0
+}
+
+// RecordBetPlacementLatency records bet placement latency for a paper or
+// live order.
+func RecordBetPlacementLatency(mode string, durationSeconds float64) {
+	BetPlacementLatency.WithLabelValues(mode).Observe(durationSeconds)
+}
+
+// RecordBetMatchTime records the time from placement to full match.
+func RecordBetMatchTime(durationSeconds float64) {
+	if durationSeconds < 0 {
+		return
+	}
+	BetMatchTime.Observe(durationSeconds)
+}
+
+// RecordMarketCatalogCache records a market catalogue cache hit or miss.
+func RecordMarketCatalogCache(result string) {
+	MarketCatalogCacheTotal.WithLabelValues(result).Inc()
+}
+
+// SetDataSourceHealth records whether a data source is healthy.
+func SetDataSourceHealth(source string, healthy bool) {
+	value := 0.0
+	if healthy {
+		value = 1
+	}
+	DataSourceHealthy.WithLabelValues(source).Set(value)
+}
+
+// RecordDataSourceFailover records a poll failing over away from a source.
+func RecordDataSourceFailover(source string) {
+	DataSourceFailoversTotal.WithLabelValues(source).Inc()
+}
+
+// RecordOddsRetention records chunks compressed or dropped by retention and
+// the bytes reclaimed.
+func RecordOddsRetention(action string, chunks int, bytesReclaimed int64) {
+	OddsRetentionChunksTotal.WithLabelValues(action).Add(float64(chunks))
+	if bytesReclaimed > 0 {
+		OddsRetentionBytesReclaimedTotal.Add(float64(bytesReclaimed))
+	}
 }
 
 // RecordBacktestDuration records backtest duration.
@@ -16,6 +16,24 @@ var (
 		Name:      "ml_strategy_recommendations_total",
 		Help:      "Total number of ML strategy recommendations by type",
 	}, []string{"recommendation", "confidence_bucket"})
+
+	StrategyDeactivationsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "clever_better",
+		Name:      "strategy_deactivations_total",
+		Help:      "Total number of automatic strategy deactivations by reason",
+	}, []string{"strategy_id", "strategy_name", "reason"})
+
+	StrategyEvaluationTimeoutsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "clever_better",
+		Name:      "strategy_evaluation_timeouts_total",
+		Help:      "Total number of strategy evaluations skipped after exceeding the evaluation timeout",
+	}, []string{"strategy_id", "strategy_name"})
+
+	MLObservedPredictionsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "clever_better",
+		Name:      "ml_observed_predictions_total",
+		Help:      "Total number of ML predictions recorded in observe mode by agreement with the signal's side",
+	}, []string{"strategy_id", "agreement"})
 )
 
 // Strategy-specific histogram vectors
@@ -26,6 +44,13 @@ var (
 		Help:      "Confidence scores for strategy decisions",
 		Buckets:   []float64{0.1, 0.2, 0.3, 0.4, 0.5, 0.6, 0.7, 0.8, 0.9, 1.0},
 	}, []string{"strategy_id", "strategy_name"})
+
+	MLProbabilityDivergence = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "clever_better",
+		Name:      "ml_probability_divergence",
+		Help:      "ML probability minus the strategy's confidence for signals observed in observe mode",
+		Buckets:   []float64{-0.5, -0.3, -0.2, -0.1, -0.05, 0, 0.05, 0.1, 0.2, 0.3, 0.5},
+	}, []string{"strategy_id"})
 )
 
 // Strategy-specific gauge vectors
@@ -35,6 +60,12 @@ var (
 		Name:      "strategy_active_bets",
 		Help:      "Number of active bets for each strategy",
 	}, []string{"strategy_id", "strategy_name"})
+
+	StrategyUnknownType = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "clever_better",
+		Name:      "strategy_unknown_type",
+		Help:      "Active strategies not trading because their type is unknown, set to 1 per strategy",
+	}, []string{"strategy_id", "strategy_name", "strategy_type"})
 )
 
 // RecordStrategyDecision records a strategy decision.
@@ -56,3 +87,32 @@ func UpdateStrategyActiveBets(strategyID, strategyName string, count float64) {
 func RecordMLRecommendation(recommendation, confidenceBucket string) {
 	MLStrategyRecommendationsTotal.WithLabelValues(recommendation, confidenceBucket).Inc()
 }
+
+// RecordStrategyDeactivation records an automatic strategy deactivation.
+func RecordStrategyDeactivation(strategyID, strategyName, reason string) {
+	StrategyDeactivationsTotal.WithLabelValues(strategyID, strategyName, reason).Inc()
+}
+
+// RecordStrategyEvaluationTimeout records a strategy evaluation that timed out.
+func RecordStrategyEvaluationTimeout(strategyID, strategyName string) {
+	StrategyEvaluationTimeoutsTotal.WithLabelValues(strategyID, strategyName).Inc()
+}
+
+// RecordMLObservation records an ML prediction observed without gating a
+// signal, with whether the model agreed with the signal's side and how far
+// its probability was from the strategy's confidence.
+func RecordMLObservation(strategyID, agreement string, divergence float64) {
+	MLObservedPredictionsTotal.WithLabelValues(strategyID, agreement).Inc()
+	MLProbabilityDivergence.WithLabelValues(strategyID).Observe(divergence)
+}
+
+// ResetUnknownStrategyTypes clears the strategies reported as having an
+// unknown type, before strategies are reloaded.
+func ResetUnknownStrategyTypes() {
+	StrategyUnknownType.Reset()
+}
+
+// RecordUnknownStrategyType records an active strategy skipped for its unknown type.
+func RecordUnknownStrategyType(strategyID, strategyName, strategyType string) {
+	StrategyUnknownType.WithLabelValues(strategyID, strategyName, strategyType).Set(1)
+}
@@ -3,7 +3,12 @@ package ml
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
 	"fmt"
+	"math"
+	"strings"
 	"sync"
 	"time"
 
@@ -24,6 +29,35 @@ func (k CacheKey) String() string {
 	return fmt.Sprintf("%s:%s:%s:%s", k.RaceID, k.RunnerID, k.StrategyID, k.ModelVersion)
 }
 
+// featureKeyPrefix marks cache entries keyed by feature hash
+const featureKeyPrefix = "features:"
+
+// FeatureKey identifies a prediction by its feature vector and model version,
+// so identical inputs share a result whichever strategy asked for it
+type FeatureKey struct {
+	FeatureHash  string
+	ModelVersion string
+}
+
+// NewFeatureKey hashes a feature vector into a cache key
+func NewFeatureKey(features []float64, modelVersion string) FeatureKey {
+	hash := sha256.New()
+	buf := make([]byte, 8)
+	for _, feature := range features {
+		binary.LittleEndian.PutUint64(buf, math.Float64bits(feature))
+		hash.Write(buf)
+	}
+	return FeatureKey{
+		FeatureHash:  hex.EncodeToString(hash.Sum(nil)),
+		ModelVersion: modelVersion,
+	}
+}
+
+// String returns string representation of feature key
+func (k FeatureKey) String() string {
+	return featureKeyPrefix + k.FeatureHash + ":" + k.ModelVersion
+}
+
 // PredictionCache provides in-memory caching for ML predictions
 type PredictionCache struct {
 	cache      *cache.Cache
@@ -63,16 +97,62 @@ func (pc *PredictionCache) Get(ctx context.Context, key CacheKey) *PredictionRes
 
 // Set stores a prediction in cache
 func (pc *PredictionCache) Set(ctx context.Context, key CacheKey, prediction *PredictionResult) {
+	pc.set(key.String(), prediction)
+}
+
+// GetByFeatures retrieves a prediction cached for an identical feature vector
+func (pc *PredictionCache) GetByFeatures(ctx context.Context, key FeatureKey) *PredictionResult {
+	pc.mu.RLock()
+	defer pc.mu.RUnlock()
+
+	if result, found := pc.cache.Get(key.String()); found {
+		pc.hitCount++
+		pc.updateMetrics()
+		if pred, ok := result.(*PredictionResult); ok {
+			return pred
+		}
+	}
+
+	pc.missCount++
+	pc.updateMetrics()
+	return nil
+}
+
+// SetByFeatures stores a prediction keyed by its feature vector
+func (pc *PredictionCache) SetByFeatures(ctx context.Context, key FeatureKey, prediction *PredictionResult) {
+	pc.set(key.String(), prediction)
+}
+
+// set stores a prediction, evicting the oldest entries to stay within maxSize
+func (pc *PredictionCache) set(key string, prediction *PredictionResult) {
 	pc.mu.Lock()
 	defer pc.mu.Unlock()
 
-	// Check size limit
-	if pc.cache.ItemCount() >= pc.maxSize {
-		// Remove expired items first
-		pc.cache.DeleteExpired()
+	if pc.maxSize > 0 {
+		if _, found := pc.cache.Get(key); !found && pc.cache.ItemCount() >= pc.maxSize {
+			// Remove expired items first
+			pc.cache.DeleteExpired()
+			for pc.cache.ItemCount() >= pc.maxSize {
+				pc.evictOldestLocked()
+			}
+		}
 	}
 
-	pc.cache.Set(key.String(), prediction, pc.ttl)
+	pc.cache.Set(key, prediction, pc.ttl)
+}
+
+// evictOldestLocked removes the entry closest to expiry. All entries share
+// one TTL, so this is the least recently stored.
+func (pc *PredictionCache) evictOldestLocked() {
+	oldestKey := ""
+	var oldestExpiry int64
+	for k, item := range pc.cache.Items() {
+		if oldestKey == "" || item.Expiration < oldestExpiry {
+			oldestKey = k
+			oldestExpiry = item.Expiration
+		}
+	}
+	pc.cache.Delete(oldestKey)
 }
 
 // Invalidate removes all cache entries for a specific strategy
@@ -85,7 +165,15 @@ func (pc *PredictionCache) Invalidate(ctx context.Context, strategyID uuid.UUID)
 	items := pc.cache.Items()
 	strategyIDStr := strategyID.String()
 	
-	for k := range items {
+	for k, item := range items {
+		// Feature entries are shared, so drop those this strategy produced
+		if strings.HasPrefix(k, featureKeyPrefix) {
+			if pred, ok := item.Object.(*PredictionResult); ok && pred.StrategyID == strategyID {
+				pc.cache.Delete(k)
+			}
+			continue
+		}
+
 		// Parse the cache key to extract strategy ID
 		parts := extractStrategyFromCacheKey(k)
 		if parts == strategyIDStr {
@@ -247,3 +247,34 @@ func TestCacheKeyEquality(t *testing.T) {
 	assert.Equal(t, key1.String(), key2.String())
 	assert.NotEqual(t, key1.String(), key3.String())
 }
+
+// TestFeatureKeyHashing tests identical feature vectors share a key
+func TestFeatureKeyHashing(t *testing.T) {
+	key := NewFeatureKey([]float64{0.1, 2.5, 7}, "1.0")
+
+	assert.Equal(t, key, NewFeatureKey([]float64{0.1, 2.5, 7}, "1.0"))
+	assert.NotEqual(t, key, NewFeatureKey([]float64{0.1, 2.5, 8}, "1.0"))
+	assert.NotEqual(t, key, NewFeatureKey([]float64{0.1, 2.5, 7}, "2.0"))
+}
+
+// TestPredictionCacheEvictsAtMaxSize tests the oldest entry is evicted once the cache is full
+func TestPredictionCacheEvictsAtMaxSize(t *testing.T) {
+	cache := NewPredictionCache(time.Hour, 2)
+	defer cache.Clear()
+
+	ctx := context.Background()
+	keys := []FeatureKey{
+		NewFeatureKey([]float64{1}, "1.0"),
+		NewFeatureKey([]float64{2}, "1.0"),
+		NewFeatureKey([]float64{3}, "1.0"),
+	}
+	for i, key := range keys {
+		cache.SetByFeatures(ctx, key, &PredictionResult{Probability: float64(i)})
+		time.Sleep(time.Millisecond)
+	}
+
+	assert.Equal(t, 2, cache.ItemCount())
+	assert.Nil(t, cache.GetByFeatures(ctx, keys[0]), "oldest entry should be evicted")
+	assert.NotNil(t, cache.GetByFeatures(ctx, keys[1]))
+	assert.NotNil(t, cache.GetByFeatures(ctx, keys[2]))
+}
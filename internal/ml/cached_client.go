@@ -3,6 +3,7 @@ package ml
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
@@ -12,15 +13,44 @@ import (
 	"github.com/yourusername/clever-better/internal/models"
 )
 
-// CachedMLClient wraps MLClient with prediction caching
+// predictionClient is the upstream ML service used by CachedMLClient
+type predictionClient interface {
+	GetPrediction(ctx context.Context, raceID, runnerID, strategyID uuid.UUID, features []float64, modelVersion string) (*PredictionResult, error)
+	EvaluateStrategy(ctx context.Context, strategyID uuid.UUID) (float64, string, error)
+	SubmitBacktestFeedback(ctx context.Context, result *models.BacktestResult) error
+	GenerateStrategy(ctx context.Context, constraints StrategyConstraints) ([]*GeneratedStrategy, error)
+	BatchPredict(ctx context.Context, requests []PredictionRequest) ([]*PredictionResult, error)
+	Close() error
+}
+
+// CachedMLClient wraps MLClient with prediction caching. Predictions are
+// keyed by a hash of the feature vector and model version, so strategies
+// asking about the same runner with the same features share one result.
+// Upstream predictions are calibrated before they are cached. With an
+// ensemble configured, predictions not pinned to a model version blend the
+// ensemble's models. With a latency budget, uncached predictions fail with
+// ErrMLServiceSlow while the service is over budget.
 type CachedMLClient struct {
-	client *MLClient
-	cache  *PredictionCache
-	logger *logrus.Logger
+	client     predictionClient
+	cache      *PredictionCache
+	calibrator Calibrator
+	ensemble   []EnsembleMember
+	latency    *latencyBudget
+	now        func() time.Time
+	logger     *logrus.Logger
 }
 
 // NewCachedMLClient creates a new cached ML client
 func NewCachedMLClient(cfg *config.MLServiceConfig, logger *logrus.Logger) (*CachedMLClient, error) {
+	calibrator, err := NewCalibrator(cfg.Calibration)
+	if err != nil {
+		return nil, err
+	}
+	ensemble, err := NewEnsemble(cfg.Ensemble)
+	if err != nil {
+		return nil, err
+	}
+
 	client, err := NewMLClient(cfg, logger)
 	if err != nil {
 		return nil, err
@@ -32,45 +62,126 @@ func NewCachedMLClient(cfg *config.MLServiceConfig, logger *logrus.Logger) (*Cac
 	)
 
 	return &CachedMLClient{
-		client: client,
-		cache:  cache,
-		logger: logger,
+		client:     client,
+		cache:      cache,
+		calibrator: calibrator,
+		ensemble:   ensemble,
+		latency:    newLatencyBudget(cfg.LatencyBudget),
+		now:        time.Now,
+		logger:     logger,
 	}, nil
 }
 
-// GetPrediction retrieves prediction with caching
+// GetPrediction retrieves a calibrated prediction with caching. With an
+// ensemble configured and no model version given, it returns the ensemble's
+// blended prediction; every member must answer.
 func (c *CachedMLClient) GetPrediction(ctx context.Context, raceID, runnerID, strategyID uuid.UUID, features []float64, modelVersion string) (*PredictionResult, error) {
-	// Check cache first
-	cacheKey := CacheKey{
-		RaceID:       raceID,
-		RunnerID:     runnerID,
-		StrategyID:   strategyID,
-		ModelVersion: modelVersion,
+	if len(c.ensemble) > 0 && modelVersion == "" {
+		return c.ensemblePrediction(ctx, raceID, runnerID, strategyID, features)
 	}
+	return c.predict(ctx, raceID, runnerID, strategyID, features, modelVersion)
+}
+
+// ensemblePrediction blends each ensemble member's cached prediction
+func (c *CachedMLClient) ensemblePrediction(ctx context.Context, raceID, runnerID, strategyID uuid.UUID, features []float64) (*PredictionResult, error) {
+	predictions := make([]*PredictionResult, len(c.ensemble))
+	for i, member := range c.ensemble {
+		prediction, err := c.predict(ctx, raceID, runnerID, strategyID, features, member.ModelVersion)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get prediction from ensemble model %s: %w", member.ModelVersion, err)
+		}
+		predictions[i] = prediction
+	}
+
+	blended, err := BlendPredictions(c.ensemble, predictions)
+	if err != nil {
+		return nil, err
+	}
+	blended.RaceID = raceID
+	blended.RunnerID = runnerID
+	blended.StrategyID = strategyID
+	return blended, nil
+}
+
+// predict returns one model version's calibrated prediction, from the cache
+// when it holds one
+func (c *CachedMLClient) predict(ctx context.Context, raceID, runnerID, strategyID uuid.UUID, features []float64, modelVersion string) (*PredictionResult, error) {
+	// Check cache first
+	cacheKey := NewFeatureKey(features, modelVersion)
 
-	if cached := c.cache.Get(ctx, cacheKey); cached != nil {
+	if cached := c.cache.GetByFeatures(ctx, cacheKey); cached != nil {
 		c.logger.WithField("cache_key", cacheKey.String()).Debug("Cache hit for prediction")
 		MLPredictionsTotal.WithLabelValues("cached", "true").Inc()
-		return cached, nil
+		return forRequester(cached, raceID, runnerID, strategyID), nil
 	}
 
-	// Cache miss, call ML service
+	// Cache miss, call ML service unless it is over its latency budget
+	if c.latency != nil && !c.latency.allow(c.clock()) {
+		return nil, ErrMLServiceSlow
+	}
 	c.logger.WithField("cache_key", cacheKey.String()).Debug("Cache miss, fetching from ML service")
+	started := c.clock()
 	result, err := c.client.GetPrediction(ctx, raceID, runnerID, strategyID, features, modelVersion)
+	c.recordLatency(c.clock().Sub(started))
 	if err != nil {
 		return nil, err
 	}
 
+	calibrate(c.calibrator, result)
+
 	// Store in cache
 	result.RunnerID = runnerID
 	result.StrategyID = strategyID
 	result.ModelVersion = modelVersion
-	c.cache.Set(ctx, cacheKey, result)
+	c.cache.SetByFeatures(ctx, cacheKey, result)
 
 	MLPredictionsTotal.WithLabelValues("grpc", "false").Inc()
 	return result, nil
 }
 
+// recordLatency feeds an upstream prediction's latency to the latency
+// budget, logging when the service is bypassed or restored
+func (c *CachedMLClient) recordLatency(latency time.Duration) {
+	if c.latency == nil {
+		return
+	}
+	changed, bypassed, p95 := c.latency.record(latency, c.clock())
+	MLPredictionLatencyP95.Set(p95.Seconds())
+	if !changed {
+		return
+	}
+
+	fields := logrus.Fields{
+		"p95_ms":    p95.Milliseconds(),
+		"budget_ms": c.latency.budget.Milliseconds(),
+	}
+	if bypassed {
+		MLLatencyBypassActive.Set(1)
+		MLLatencyBypassTotal.Inc()
+		c.logger.WithFields(fields).Warn("ML service over latency budget, bypassing predictions")
+		return
+	}
+	MLLatencyBypassActive.Set(0)
+	c.logger.WithFields(fields).Info("ML service back within latency budget")
+}
+
+// clock returns the current time
+func (c *CachedMLClient) clock() time.Time {
+	if c.now == nil {
+		return time.Now()
+	}
+	return c.now()
+}
+
+// forRequester copies a cached prediction and stamps it with the caller's IDs
+func forRequester(cached *PredictionResult, raceID, runnerID, strategyID uuid.UUID) *PredictionResult {
+	result := *cached
+	result.RaceID = raceID
+	result.RunnerID = runnerID
+	result.StrategyID = strategyID
+	return &result
+}
+
 // EvaluateStrategy evaluates a strategy (not cached)
 func (c *CachedMLClient) EvaluateStrategy(ctx context.Context, strategyID uuid.UUID) (float64, string, error) {
 	return c.client.EvaluateStrategy(ctx, strategyID)
@@ -103,15 +214,10 @@ func (c *CachedMLClient) BatchPredict(ctx context.Context, requests []Prediction
 
 	// Check cache for each request
 	for i, req := range requests {
-		cacheKey := CacheKey{
-			RaceID:       req.RaceID,
-			RunnerID:     req.RunnerID,
-			StrategyID:   req.StrategyID,
-			ModelVersion: req.ModelVersion,
-		}
+		cacheKey := NewFeatureKey(req.Features, req.ModelVersion)
 
-		if cached := c.cache.Get(ctx, cacheKey); cached != nil {
-			results[i] = cached
+		if cached := c.cache.GetByFeatures(ctx, cacheKey); cached != nil {
+			results[i] = forRequester(cached, req.RaceID, req.RunnerID, req.StrategyID)
 		} else {
 			uncachedRequests = append(uncachedRequests, req)
 			uncachedIndices = append(uncachedIndices, i)
@@ -136,13 +242,8 @@ func (c *CachedMLClient) BatchPredict(ctx context.Context, requests []Prediction
 			idx := uncachedIndices[i]
 			req := uncachedRequests[i]
 
-			cacheKey := CacheKey{
-				RaceID:       req.RaceID,
-				RunnerID:     req.RunnerID,
-				StrategyID:   req.StrategyID,
-				ModelVersion: req.ModelVersion,
-			}
-			c.cache.Set(ctx, cacheKey, result)
+			calibrate(c.calibrator, result)
+			c.cache.SetByFeatures(ctx, NewFeatureKey(req.Features, req.ModelVersion), result)
 			results[idx] = result
 		}
 	}
@@ -165,6 +266,16 @@ func (c *CachedMLClient) GetCacheStats() (hits, misses uint64, hitRatio float64)
 	return c.cache.Stats()
 }
 
+// GetLatencyStats returns the rolling p95 latency of upstream predictions and
+// whether they are bypassed for exceeding the latency budget. Both are zero
+// without a latency budget.
+func (c *CachedMLClient) GetLatencyStats() (p95 time.Duration, bypassed bool) {
+	if c.latency == nil {
+		return 0, false
+	}
+	return c.latency.stats()
+}
+
 // Close closes the underlying ML client
 func (c *CachedMLClient) Close() error {
 	return c.client.Close()
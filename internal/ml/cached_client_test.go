@@ -0,0 +1,123 @@
+package ml
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yourusername/clever-better/internal/models"
+)
+
+// countingPredictionClient returns a fixed prediction and counts upstream calls
+type countingPredictionClient struct {
+	predictCalls int
+	batchCalls   int
+}
+
+func (c *countingPredictionClient) GetPrediction(ctx context.Context, raceID, runnerID, strategyID uuid.UUID, features []float64, modelVersion string) (*PredictionResult, error) {
+	c.predictCalls++
+	return &PredictionResult{RaceID: raceID, Probability: features[0], Confidence: 0.8}, nil
+}
+
+func (c *countingPredictionClient) EvaluateStrategy(ctx context.Context, strategyID uuid.UUID) (float64, string, error) {
+	return 0, "", nil
+}
+
+func (c *countingPredictionClient) SubmitBacktestFeedback(ctx context.Context, result *models.BacktestResult) error {
+	return nil
+}
+
+func (c *countingPredictionClient) GenerateStrategy(ctx context.Context, constraints StrategyConstraints) ([]*GeneratedStrategy, error) {
+	return nil, nil
+}
+
+func (c *countingPredictionClient) BatchPredict(ctx context.Context, requests []PredictionRequest) ([]*PredictionResult, error) {
+	c.batchCalls++
+	results := make([]*PredictionResult, len(requests))
+	for i, req := range requests {
+		results[i] = &PredictionResult{RaceID: req.RaceID, RunnerID: req.RunnerID, StrategyID: req.StrategyID, Probability: req.Features[0]}
+	}
+	return results, nil
+}
+
+func (c *countingPredictionClient) Close() error { return nil }
+
+func newTestCachedClient(upstream predictionClient) *CachedMLClient {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+	return &CachedMLClient{
+		client: upstream,
+		cache:  NewPredictionCache(time.Hour, 100),
+		logger: logger,
+	}
+}
+
+// TestCachedClientSharesIdenticalFeatures tests strategies asking with the same features share one upstream call
+func TestCachedClientSharesIdenticalFeatures(t *testing.T) {
+	upstream := &countingPredictionClient{}
+	client := newTestCachedClient(upstream)
+	ctx := context.Background()
+
+	raceID, runnerID := uuid.New(), uuid.New()
+	firstStrategy, secondStrategy := uuid.New(), uuid.New()
+	features := []float64{0.4, 3.5, 12}
+
+	first, err := client.GetPrediction(ctx, raceID, runnerID, firstStrategy, features, "v1")
+	require.NoError(t, err)
+	second, err := client.GetPrediction(ctx, raceID, runnerID, secondStrategy, []float64{0.4, 3.5, 12}, "v1")
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, upstream.predictCalls)
+	assert.Equal(t, first.Probability, second.Probability)
+	assert.Equal(t, firstStrategy, first.StrategyID)
+	assert.Equal(t, secondStrategy, second.StrategyID, "cached result must carry the requesting strategy")
+
+	hits, misses, _ := client.GetCacheStats()
+	assert.Equal(t, uint64(1), hits)
+	assert.Equal(t, uint64(1), misses)
+}
+
+// TestCachedClientDistinctFeatures tests different features or model versions are predicted separately
+func TestCachedClientDistinctFeatures(t *testing.T) {
+	upstream := &countingPredictionClient{}
+	client := newTestCachedClient(upstream)
+	ctx := context.Background()
+
+	raceID, runnerID, strategyID := uuid.New(), uuid.New(), uuid.New()
+
+	first, err := client.GetPrediction(ctx, raceID, runnerID, strategyID, []float64{0.4, 3.5}, "v1")
+	require.NoError(t, err)
+	second, err := client.GetPrediction(ctx, raceID, runnerID, strategyID, []float64{0.6, 3.5}, "v1")
+	require.NoError(t, err)
+	_, err = client.GetPrediction(ctx, raceID, runnerID, strategyID, []float64{0.4, 3.5}, "v2")
+	require.NoError(t, err)
+
+	assert.Equal(t, 3, upstream.predictCalls)
+	assert.NotEqual(t, first.Probability, second.Probability)
+}
+
+// TestCachedClientBatchUsesFeatureCache tests batch requests reuse single predictions with the same features
+func TestCachedClientBatchUsesFeatureCache(t *testing.T) {
+	upstream := &countingPredictionClient{}
+	client := newTestCachedClient(upstream)
+	ctx := context.Background()
+
+	raceID, runnerID := uuid.New(), uuid.New()
+	_, err := client.GetPrediction(ctx, raceID, runnerID, uuid.New(), []float64{0.4}, "v1")
+	require.NoError(t, err)
+
+	otherStrategy := uuid.New()
+	results, err := client.BatchPredict(ctx, []PredictionRequest{
+		{RaceID: raceID, RunnerID: runnerID, StrategyID: otherStrategy, Features: []float64{0.4}, ModelVersion: "v1"},
+	})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+
+	assert.Equal(t, 0, upstream.batchCalls)
+	assert.Equal(t, otherStrategy, results[0].StrategyID)
+}
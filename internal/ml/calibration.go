@@ -0,0 +1,92 @@
+// Package ml provides prediction confidence calibration.
+package ml
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/yourusername/clever-better/internal/config"
+)
+
+// Calibrator maps a raw model probability onto a calibrated one
+type Calibrator interface {
+	Calibrate(p float64) float64
+}
+
+// IdentityCalibrator leaves probabilities unchanged
+type IdentityCalibrator struct{}
+
+// Calibrate returns p unchanged
+func (IdentityCalibrator) Calibrate(p float64) float64 { return p }
+
+// PlattCalibrator applies sigmoid(A*logit(p) + B). A=1, B=0 is the identity.
+type PlattCalibrator struct {
+	A float64
+	B float64
+}
+
+// Calibrate applies Platt scaling to p
+func (c PlattCalibrator) Calibrate(p float64) float64 {
+	// Keep logit finite at the ends
+	const eps = 1e-9
+	p = math.Min(math.Max(p, eps), 1-eps)
+	return 1 / (1 + math.Exp(-(c.A*math.Log(p/(1-p)) + c.B)))
+}
+
+// IsotonicCalibrator interpolates linearly between fitted points of a
+// non-decreasing curve, holding the end values outside them
+type IsotonicCalibrator struct {
+	x []float64
+	y []float64
+}
+
+// Calibrate maps p onto the fitted curve
+func (c IsotonicCalibrator) Calibrate(p float64) float64 {
+	if p <= c.x[0] {
+		return c.y[0]
+	}
+	last := len(c.x) - 1
+	if p >= c.x[last] {
+		return c.y[last]
+	}
+
+	i := sort.SearchFloat64s(c.x, p)
+	if c.x[i] == p {
+		return c.y[i]
+	}
+	x0, x1 := c.x[i-1], c.x[i]
+	y0, y1 := c.y[i-1], c.y[i]
+	return y0 + (y1-y0)*(p-x0)/(x1-x0)
+}
+
+// NewCalibrator builds the calibrator described by cfg. An empty method is
+// the identity.
+func NewCalibrator(cfg config.CalibrationConfig) (Calibrator, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid calibration config: %w", err)
+	}
+
+	switch cfg.Method {
+	case "", "identity":
+		return IdentityCalibrator{}, nil
+	case "platt":
+		return PlattCalibrator{A: cfg.PlattA, B: cfg.PlattB}, nil
+	case "isotonic":
+		return IsotonicCalibrator{
+			x: append([]float64(nil), cfg.IsotonicX...),
+			y: append([]float64(nil), cfg.IsotonicY...),
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown calibration method %q", cfg.Method)
+	}
+}
+
+// calibrate applies c to a prediction's probability and confidence in place
+func calibrate(c Calibrator, result *PredictionResult) {
+	if c == nil || result == nil {
+		return
+	}
+	result.Probability = c.Calibrate(result.Probability)
+	result.Confidence = c.Calibrate(result.Confidence)
+}
@@ -0,0 +1,86 @@
+package ml
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yourusername/clever-better/internal/config"
+)
+
+func TestIdentityCalibrationLeavesProbabilitiesUnchanged(t *testing.T) {
+	for _, cfg := range []config.CalibrationConfig{{}, {Method: "identity"}, {Method: "platt", PlattA: 1}} {
+		calibrator, err := NewCalibrator(cfg)
+		require.NoError(t, err)
+		for _, p := range []float64{0.05, 0.3, 0.5, 0.72, 0.99} {
+			assert.InDelta(t, p, calibrator.Calibrate(p), 1e-9, "method %q", cfg.Method)
+		}
+	}
+}
+
+func TestPlattCalibration(t *testing.T) {
+	calibrator, err := NewCalibrator(config.CalibrationConfig{Method: "platt", PlattA: 0.5, PlattB: -0.2})
+	require.NoError(t, err)
+
+	// logit(0.8) = ln 4, so sigmoid(0.5*ln 4 - 0.2) = sigmoid(ln 2 - 0.2)
+	expected := 1 / (1 + math.Exp(-(math.Log(2) - 0.2)))
+	assert.InDelta(t, expected, calibrator.Calibrate(0.8), 1e-9)
+	// A < 1 pulls overconfident probabilities towards the middle
+	assert.Less(t, calibrator.Calibrate(0.95), 0.95)
+	assert.Greater(t, calibrator.Calibrate(0.05), 0.05)
+}
+
+func TestIsotonicCalibration(t *testing.T) {
+	calibrator, err := NewCalibrator(config.CalibrationConfig{
+		Method:    "isotonic",
+		IsotonicX: []float64{0.2, 0.5, 0.8},
+		IsotonicY: []float64{0.1, 0.4, 0.6},
+	})
+	require.NoError(t, err)
+
+	assert.InDelta(t, 0.1, calibrator.Calibrate(0.05), 1e-9, "held below the first point")
+	assert.InDelta(t, 0.4, calibrator.Calibrate(0.5), 1e-9)
+	assert.InDelta(t, 0.25, calibrator.Calibrate(0.35), 1e-9)
+	assert.InDelta(t, 0.5, calibrator.Calibrate(0.65), 1e-9)
+	assert.InDelta(t, 0.6, calibrator.Calibrate(0.95), 1e-9, "held above the last point")
+}
+
+func TestNewCalibratorRejectsInvalidConfig(t *testing.T) {
+	cases := []config.CalibrationConfig{
+		{Method: "isotonic", IsotonicX: []float64{0.5}, IsotonicY: []float64{0.5}},
+		{Method: "isotonic", IsotonicX: []float64{0.2, 0.8}, IsotonicY: []float64{0.5}},
+		{Method: "isotonic", IsotonicX: []float64{0.8, 0.2}, IsotonicY: []float64{0.1, 0.5}},
+		{Method: "isotonic", IsotonicX: []float64{0.2, 0.8}, IsotonicY: []float64{0.5, 0.1}},
+		{Method: "beta"},
+	}
+	for _, cfg := range cases {
+		_, err := NewCalibrator(cfg)
+		assert.Error(t, err)
+	}
+}
+
+// TestCachedClientCalibratesPredictions tests upstream and cached predictions are calibrated once
+func TestCachedClientCalibratesPredictions(t *testing.T) {
+	upstream := &countingPredictionClient{}
+	client := newTestCachedClient(upstream)
+	client.calibrator = IsotonicCalibrator{x: []float64{0, 1}, y: []float64{0, 0.5}}
+
+	features := []float64{0.6, 1.0}
+	first, err := client.GetPrediction(context.Background(), uuid.New(), uuid.New(), uuid.New(), features, "v1")
+	require.NoError(t, err)
+	assert.InDelta(t, 0.3, first.Probability, 1e-9)
+	assert.InDelta(t, 0.4, first.Confidence, 1e-9)
+
+	cached, err := client.GetPrediction(context.Background(), uuid.New(), uuid.New(), uuid.New(), features, "v1")
+	require.NoError(t, err)
+	assert.Equal(t, 1, upstream.predictCalls)
+	assert.InDelta(t, 0.3, cached.Probability, 1e-9, "cached predictions are not calibrated twice")
+
+	batch, err := client.BatchPredict(context.Background(), []PredictionRequest{{Features: []float64{0.8}, ModelVersion: "v1"}})
+	require.NoError(t, err)
+	assert.InDelta(t, 0.4, batch[0].Probability, 1e-9)
+}
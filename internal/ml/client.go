@@ -3,9 +3,12 @@ package ml
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/google/uuid"
@@ -15,25 +18,32 @@ import (
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
 
 	"github.com/yourusername/clever-better/internal/config"
+	applogger "github.com/yourusername/clever-better/internal/logger"
 	"github.com/yourusername/clever-better/internal/models"
 	mlpb "github.com/yourusername/clever-better/internal/ml/mlpb"
 )
 
 // MLClient provides gRPC client for ML service
 type MLClient struct {
-	conn   *grpc.ClientConn
+	conns   []*grpc.ClientConn
+	clients []mlpb.MLServiceClient
+	next    atomic.Uint64
+	// client serves calls when no pool was opened
 	client mlpb.MLServiceClient
-	config *config.MLServiceConfig
-	logger *logrus.Logger
+	config  *config.MLServiceConfig
+	logger  *logrus.Logger
 }
 
-// NewMLClient creates a new ML service client
+// NewMLClient creates a new ML service client. It opens a pool of
+// cfg.ConnectionPoolSize connections, so concurrent predictions are not
+// serialised over one HTTP/2 connection. Connections are made in the
+// background: creation succeeds while the ML service is down and calls fail
+// until it is reachable.
 func NewMLClient(cfg *config.MLServiceConfig, logger *logrus.Logger) (*MLClient, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
 	creds := grpc.WithTransportCredentials(insecure.NewCredentials())
 	if strings.HasPrefix(cfg.URL, "https://") {
 		creds = grpc.WithTransportCredentials(credentials.NewClientTLSFromCert(nil, ""))
@@ -55,29 +65,57 @@ func NewMLClient(cfg *config.MLServiceConfig, logger *logrus.Logger) (*MLClient,
 		PermitWithoutStream: true,
 	}
 
-	// Establish gRPC connection with retry
-	conn, err := grpc.DialContext(ctx, cfg.GRPCAddress,
-		creds,
-		grpc.WithBlock(),
-		grpc.WithConnectParams(connectParams),
-		grpc.WithKeepaliveParams(keepAlive),
-	)
-	if err != nil {
-		logger.WithError(err).Error("Failed to connect to ML service")
-		return nil, fmt.Errorf("%w: %v", ErrConnectionFailed, err)
+	poolSize := cfg.ConnectionPoolSize
+	if poolSize < 1 {
+		poolSize = 1
 	}
 
 	client := &MLClient{
-		conn:   conn,
-		client: mlpb.NewMLServiceClient(conn),
-		config: cfg,
-		logger: logger,
+		conns:   make([]*grpc.ClientConn, 0, poolSize),
+		clients: make([]mlpb.MLServiceClient, 0, poolSize),
+		config:  cfg,
+		logger:  logger,
+	}
+	for i := 0; i < poolSize; i++ {
+		conn, err := grpc.DialContext(context.Background(), cfg.GRPCAddress,
+			creds,
+			grpc.WithConnectParams(connectParams),
+			grpc.WithKeepaliveParams(keepAlive),
+			grpc.WithUnaryInterceptor(requestIDInterceptor),
+		)
+		if err != nil {
+			logger.WithError(err).Error("Failed to connect to ML service")
+			_ = client.Close()
+			return nil, fmt.Errorf("%w: %v", ErrConnectionFailed, err)
+		}
+		client.conns = append(client.conns, conn)
+		client.clients = append(client.clients, mlpb.NewMLServiceClient(conn))
 	}
 
-	logger.WithField("address", cfg.GRPCAddress).Info("Connected to ML service")
+	logger.WithFields(logrus.Fields{
+		"address":   cfg.GRPCAddress,
+		"pool_size": poolSize,
+	}).Info("Created ML service client")
 	return client, nil
 }
 
+// rpc returns the next pooled service client, round robin
+func (c *MLClient) rpc() mlpb.MLServiceClient {
+	if len(c.clients) == 0 {
+		return c.client
+	}
+	return c.clients[(c.next.Add(1)-1)%uint64(len(c.clients))]
+}
+
+// requestIDInterceptor forwards the caller's request ID as gRPC metadata so
+// ML service logs can be correlated with the bot's
+func requestIDInterceptor(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	if requestID := applogger.RequestIDFromContext(ctx); requestID != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, strings.ToLower(applogger.RequestIDHeader), requestID)
+	}
+	return invoker(ctx, method, req, reply, cc, opts...)
+}
+
 // GetPrediction gets a prediction from the ML service
 func (c *MLClient) GetPrediction(ctx context.Context, raceID, runnerID, strategyID uuid.UUID, features []float64, modelVersion string) (*PredictionResult, error) {
 	start := time.Now()
@@ -99,7 +137,7 @@ func (c *MLClient) GetPrediction(ctx context.Context, raceID, runnerID, strategy
 	}
 
 	// Make actual RPC call
-	resp, err := c.client.GetPrediction(ctx, req)
+	resp, err := c.rpc().GetPrediction(ctx, req)
 	if err != nil {
 		MLGRPCErrorsTotal.WithLabelValues("GetPrediction", "rpc_failed").Inc()
 		c.logger.WithError(err).Error("Failed to get prediction from ML service")
@@ -144,7 +182,7 @@ func (c *MLClient) EvaluateStrategy(ctx context.Context, strategyID uuid.UUID) (
 		StrategyId: strategyID.String(),
 	}
 
-	resp, err := c.client.EvaluateStrategy(ctx, req)
+	resp, err := c.rpc().EvaluateStrategy(ctx, req)
 	if err != nil {
 		MLGRPCErrorsTotal.WithLabelValues("EvaluateStrategy", "rpc_failed").Inc()
 		c.logger.WithError(err).Error("Failed to evaluate strategy from ML service")
@@ -161,26 +199,18 @@ func (c *MLClient) SubmitBacktestFeedback(ctx context.Context, result *models.Ba
 		MLPredictionLatency.WithLabelValues("grpc").Observe(time.Since(start).Seconds())
 	}()
 
-	mlFeatures := parseMLFeatures(result.MLFeatures)
-
-	// Populate request with backtest result fields
-	req := &mlpb.BacktestFeedbackRequest{
-		StrategyId:     result.StrategyID.String(),
-		CompositeScore: result.CompositeScore,
-		SharpeRatio:    result.SharpeRatio,
-		Roi:            result.TotalReturn,
-		MaxDrawdown:    result.MaxDrawdown,
-		WinRate:        result.WinRate,
-		ProfitFactor:   result.ProfitFactor,
-		TotalBets:      int32(result.TotalBets),
-		Method:         result.Method,
-		MlFeatures:     mlFeatures,
+	req, err := newBacktestFeedbackRequest(result)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrFeedbackSubmissionFailed, err)
 	}
 
-	resp, err := c.client.SubmitBacktestFeedback(ctx, req)
+	resp, err := c.rpc().SubmitBacktestFeedback(ctx, req)
 	if err != nil {
 		MLGRPCErrorsTotal.WithLabelValues("SubmitBacktestFeedback", "rpc_failed").Inc()
-		c.logger.WithError(err).Error("Failed to submit backtest feedback")
+		c.logger.WithError(err).WithFields(logrus.Fields{
+			"result_id":       result.ID,
+			"idempotency_key": req.IdempotencyKey,
+		}).Error("Failed to submit backtest feedback")
 		return fmt.Errorf("%w: %v", ErrFeedbackSubmissionFailed, err)
 	}
 
@@ -211,7 +241,7 @@ func (c *MLClient) GenerateStrategy(ctx context.Context, constraints StrategyCon
 		TopMetrics:         constraints.TopMetrics,
 	}
 
-	resp, err := c.client.GenerateStrategy(ctx, req)
+	resp, err := c.rpc().GenerateStrategy(ctx, req)
 	if err != nil {
 		MLGRPCErrorsTotal.WithLabelValues("GenerateStrategy", "rpc_failed").Inc()
 		c.logger.WithError(err).Error("Failed to generate strategy from ML service")
@@ -260,7 +290,7 @@ func (c *MLClient) BatchPredict(ctx context.Context, requests []PredictionReques
 		Predictions: protoRequests,
 	}
 
-	resp, err := c.client.BatchPredict(ctx, batchReq)
+	resp, err := c.rpc().BatchPredict(ctx, batchReq)
 	if err != nil {
 		MLGRPCErrorsTotal.WithLabelValues("BatchPredict", "rpc_failed").Inc()
 		c.logger.WithError(err).Error("Failed to batch predict from ML service")
@@ -301,16 +331,57 @@ func (c *MLClient) BatchPredict(ctx context.Context, requests []PredictionReques
 	return results, nil
 }
 
-// Close closes the gRPC connection
+// Close closes the pooled gRPC connections
 func (c *MLClient) Close() error {
-	if c.conn != nil {
-		return c.conn.Close()
+	var firstErr error
+	for _, conn := range c.conns {
+		if err := conn.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
 	}
-	return nil
+	return firstErr
 }
 
 // Helper functions for type conversion between gRPC and internal types
 
+// newBacktestFeedbackRequest builds the feedback request for a result and
+// stamps it with its idempotency key
+func newBacktestFeedbackRequest(result *models.BacktestResult) (*mlpb.BacktestFeedbackRequest, error) {
+	req := &mlpb.BacktestFeedbackRequest{
+		StrategyId:     result.StrategyID.String(),
+		CompositeScore: result.CompositeScore,
+		SharpeRatio:    result.SharpeRatio,
+		Roi:            result.TotalReturn,
+		MaxDrawdown:    result.MaxDrawdown,
+		WinRate:        result.WinRate,
+		ProfitFactor:   result.ProfitFactor,
+		TotalBets:      int32(result.TotalBets),
+		Method:         result.Method,
+		MlFeatures:     parseMLFeatures(result.MLFeatures),
+	}
+
+	// Deterministic marshalling orders the feature map, so retries of the
+	// same result hash identically
+	payload, err := proto.MarshalOptions{Deterministic: true}.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal feedback request: %w", err)
+	}
+	sum := sha256.Sum256(payload)
+	req.IdempotencyKey = result.ID.String() + ":" + hex.EncodeToString(sum[:])
+	return req, nil
+}
+
+// FeedbackIdempotencyKey returns the key sent with a result's feedback. It is
+// the result ID plus a hash of the submitted fields, so a retried delivery of
+// the same result carries the same key and the ML service can drop it.
+func FeedbackIdempotencyKey(result *models.BacktestResult) (string, error) {
+	req, err := newBacktestFeedbackRequest(result)
+	if err != nil {
+		return "", err
+	}
+	return req.IdempotencyKey, nil
+}
+
 func parseMLFeatures(raw json.RawMessage) map[string]float64 {
 	if len(raw) == 0 {
 		return nil
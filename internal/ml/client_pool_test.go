@@ -0,0 +1,102 @@
+package ml
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/peer"
+
+	"github.com/yourusername/clever-better/internal/config"
+	mlpb "github.com/yourusername/clever-better/internal/ml/mlpb"
+)
+
+// peerRecordingService answers predictions and records which client
+// connection each arrived on
+type peerRecordingService struct {
+	mlpb.UnimplementedMLServiceServer
+	mu    sync.Mutex
+	peers map[string]int
+}
+
+func (s *peerRecordingService) GetPrediction(ctx context.Context, req *mlpb.PredictionRequest) (*mlpb.PredictionResponse, error) {
+	if p, ok := peer.FromContext(ctx); ok {
+		s.mu.Lock()
+		s.peers[p.Addr.String()]++
+		s.mu.Unlock()
+	}
+	return &mlpb.PredictionResponse{PredictedProbability: 0.4, Confidence: 0.8}, nil
+}
+
+func TestMLClientPoolsConnectionsAndConnectsLazily(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	// Reserve an address, then free it so the service is down at creation
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := listener.Addr().String()
+	require.NoError(t, listener.Close())
+
+	cfg := &config.MLServiceConfig{URL: "http://localhost", GRPCAddress: addr, ConnectionPoolSize: 3}
+	client, err := NewMLClient(cfg, logger)
+	require.NoError(t, err, "creation must not wait for the ML service")
+	defer client.Close()
+	require.Len(t, client.conns, 3)
+
+	_, err = client.GetPrediction(context.Background(), uuid.New(), uuid.New(), uuid.New(), []float64{1}, "")
+	require.ErrorIs(t, err, ErrInvalidPrediction, "calls fail while the service is down")
+
+	listener, err = net.Listen("tcp", addr)
+	require.NoError(t, err)
+	service := &peerRecordingService{peers: make(map[string]int)}
+	server := grpc.NewServer()
+	mlpb.RegisterMLServiceServer(server, service)
+	go func() { _ = server.Serve(listener) }()
+	defer server.Stop()
+
+	for _, conn := range client.conns {
+		conn.Connect()
+	}
+	require.Eventually(t, func() bool {
+		for _, conn := range client.conns {
+			if conn.GetState() != connectivity.Ready {
+				conn.Connect()
+				return false
+			}
+		}
+		return true
+	}, 15*time.Second, 50*time.Millisecond)
+
+	const calls = 30
+	var wg sync.WaitGroup
+	errs := make(chan error, calls)
+	for i := 0; i < calls; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := client.GetPrediction(context.Background(), uuid.New(), uuid.New(), uuid.New(), []float64{1}, "")
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		require.NoError(t, err)
+	}
+
+	service.mu.Lock()
+	defer service.mu.Unlock()
+	require.Len(t, service.peers, 3, "predictions spread over every pooled connection")
+	for addr, count := range service.peers {
+		assert.Equal(t, calls/3, count, "connection %s", addr)
+	}
+}
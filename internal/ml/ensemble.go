@@ -0,0 +1,93 @@
+package ml
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/yourusername/clever-better/internal/config"
+)
+
+// EnsembleModelVersion is the model version stamped on blended predictions
+const EnsembleModelVersion = "ensemble"
+
+// ErrEnsembleMismatch is returned when blending a different number of
+// predictions than the ensemble has members
+var ErrEnsembleMismatch = errors.New("ensemble predictions do not match its members")
+
+// EnsembleMember is a model version and its weight in an ensemble
+type EnsembleMember struct {
+	ModelVersion string
+	Weight       float64
+}
+
+// NewEnsemble converts configured ensemble members, returning nil when the
+// ensemble is disabled
+func NewEnsemble(cfg config.EnsembleConfig) ([]EnsembleMember, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+	if len(cfg.Models) == 0 {
+		return nil, fmt.Errorf("ensemble needs at least one model")
+	}
+
+	members := make([]EnsembleMember, 0, len(cfg.Models))
+	seen := make(map[string]bool, len(cfg.Models))
+	for _, model := range cfg.Models {
+		if model.Version == "" {
+			return nil, fmt.Errorf("ensemble model version is required")
+		}
+		if model.Weight <= 0 {
+			return nil, fmt.Errorf("ensemble weight for model %s must be positive", model.Version)
+		}
+		if seen[model.Version] {
+			return nil, fmt.Errorf("ensemble model %s is listed twice", model.Version)
+		}
+		seen[model.Version] = true
+		members = append(members, EnsembleMember{ModelVersion: model.Version, Weight: model.Weight})
+	}
+	return members, nil
+}
+
+// BlendPredictions combines one prediction per member into their weighted
+// average, weights being relative to their total. Each member's output is
+// kept in Constituents. The recommendation is kept only when every member
+// makes the same one, so a split ensemble falls back on its probability.
+func BlendPredictions(members []EnsembleMember, predictions []*PredictionResult) (*PredictionResult, error) {
+	if len(members) == 0 || len(predictions) != len(members) {
+		return nil, fmt.Errorf("%w: %d members, %d predictions", ErrEnsembleMismatch, len(members), len(predictions))
+	}
+
+	var totalWeight float64
+	for _, member := range members {
+		totalWeight += member.Weight
+	}
+
+	blended := &PredictionResult{
+		ModelVersion:   EnsembleModelVersion,
+		Recommendation: predictions[0].Recommendation,
+		Constituents:   make([]ModelPrediction, 0, len(members)),
+	}
+	for i, member := range members {
+		prediction := predictions[i]
+		if prediction == nil {
+			return nil, fmt.Errorf("%w: no prediction from model %s", ErrEnsembleMismatch, member.ModelVersion)
+		}
+		share := member.Weight / totalWeight
+		blended.Probability += share * prediction.Probability
+		blended.Confidence += share * prediction.Confidence
+		if !strings.EqualFold(strings.TrimSpace(prediction.Recommendation), strings.TrimSpace(blended.Recommendation)) {
+			blended.Recommendation = ""
+		}
+		if prediction.PredictedAt.After(blended.PredictedAt) {
+			blended.PredictedAt = prediction.PredictedAt
+		}
+		blended.Constituents = append(blended.Constituents, ModelPrediction{
+			ModelVersion: member.ModelVersion,
+			Weight:       member.Weight,
+			Probability:  prediction.Probability,
+			Confidence:   prediction.Confidence,
+		})
+	}
+	return blended, nil
+}
@@ -0,0 +1,90 @@
+package ml
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yourusername/clever-better/internal/config"
+)
+
+// versionedPredictionClient answers with a fixed prediction per model version
+type versionedPredictionClient struct {
+	countingPredictionClient
+	predictions map[string]PredictionResult
+}
+
+func (c *versionedPredictionClient) GetPrediction(ctx context.Context, raceID, runnerID, strategyID uuid.UUID, features []float64, modelVersion string) (*PredictionResult, error) {
+	c.predictCalls++
+	prediction, ok := c.predictions[modelVersion]
+	if !ok {
+		return nil, ErrMLServiceUnavailable
+	}
+	return &prediction, nil
+}
+
+func TestCachedClientBlendsEnsemble(t *testing.T) {
+	upstream := &versionedPredictionClient{predictions: map[string]PredictionResult{
+		"v1": {Probability: 0.6, Confidence: 0.8, Recommendation: "back"},
+		"v2": {Probability: 0.2, Confidence: 0.4, Recommendation: "lay"},
+	}}
+	client := newTestCachedClient(upstream)
+	client.ensemble, _ = NewEnsemble(config.EnsembleConfig{
+		Enabled: true,
+		Models: []config.EnsembleModelConfig{
+			{Version: "v1", Weight: 3},
+			{Version: "v2", Weight: 1},
+		},
+	})
+	ctx := context.Background()
+	raceID, runnerID, strategyID := uuid.New(), uuid.New(), uuid.New()
+
+	blended, err := client.GetPrediction(ctx, raceID, runnerID, strategyID, []float64{0.4}, "")
+	require.NoError(t, err)
+	assert.InDelta(t, 0.75*0.6+0.25*0.2, blended.Probability, 1e-9)
+	assert.InDelta(t, 0.75*0.8+0.25*0.4, blended.Confidence, 1e-9)
+	assert.Equal(t, EnsembleModelVersion, blended.ModelVersion)
+	assert.Empty(t, blended.Recommendation, "the models disagree")
+	assert.Equal(t, runnerID, blended.RunnerID)
+	assert.Equal(t, []ModelPrediction{
+		{ModelVersion: "v1", Weight: 3, Probability: 0.6, Confidence: 0.8},
+		{ModelVersion: "v2", Weight: 1, Probability: 0.2, Confidence: 0.4},
+	}, blended.Constituents)
+
+	_, err = client.GetPrediction(ctx, raceID, runnerID, uuid.New(), []float64{0.4}, "")
+	require.NoError(t, err)
+	assert.Equal(t, 2, upstream.predictCalls, "members are cached individually")
+
+	pinned, err := client.GetPrediction(ctx, raceID, runnerID, strategyID, []float64{0.4}, "v2")
+	require.NoError(t, err)
+	assert.Equal(t, 0.2, pinned.Probability, "a pinned model version bypasses the ensemble")
+	assert.Empty(t, pinned.Constituents)
+}
+
+func TestCachedClientEnsembleNeedsEveryMember(t *testing.T) {
+	upstream := &versionedPredictionClient{predictions: map[string]PredictionResult{"v1": {Probability: 0.6}}}
+	client := newTestCachedClient(upstream)
+	client.ensemble = []EnsembleMember{{ModelVersion: "v1", Weight: 1}, {ModelVersion: "retired", Weight: 1}}
+
+	_, err := client.GetPrediction(context.Background(), uuid.New(), uuid.New(), uuid.New(), []float64{0.4}, "")
+	assert.ErrorIs(t, err, ErrMLServiceUnavailable)
+}
+
+func TestNewEnsembleValidatesMembers(t *testing.T) {
+	members, err := NewEnsemble(config.EnsembleConfig{Models: []config.EnsembleModelConfig{{Version: "v1", Weight: 1}}})
+	require.NoError(t, err)
+	assert.Nil(t, members, "a disabled ensemble has no members")
+
+	for _, models := range [][]config.EnsembleModelConfig{
+		nil,
+		{{Version: "v1", Weight: 0}},
+		{{Version: "", Weight: 1}},
+		{{Version: "v1", Weight: 1}, {Version: "v1", Weight: 2}},
+	} {
+		_, err := NewEnsemble(config.EnsembleConfig{Enabled: true, Models: models})
+		assert.Error(t, err)
+	}
+}
@@ -6,6 +6,10 @@ import "errors"
 var (
 	// ErrMLServiceUnavailable indicates the ML service is unreachable
 	ErrMLServiceUnavailable = errors.New("ml service unavailable")
+
+	// ErrMLServiceSlow indicates the ML service is bypassed for exceeding its
+	// latency budget
+	ErrMLServiceSlow = errors.New("ml service over latency budget")
 	
 	// ErrInvalidPrediction indicates the prediction response is invalid
 	ErrInvalidPrediction = errors.New("invalid prediction response")
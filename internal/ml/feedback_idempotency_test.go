@@ -0,0 +1,90 @@
+package ml
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+
+	"github.com/yourusername/clever-better/internal/models"
+	mlpb "github.com/yourusername/clever-better/internal/ml/mlpb"
+)
+
+// recordingFeedbackClient records feedback requests and fails the first
+// failures calls, as a timed-out delivery would look to the caller
+type recordingFeedbackClient struct {
+	mlpb.MLServiceClient
+	failures int
+	requests []*mlpb.BacktestFeedbackRequest
+}
+
+func (c *recordingFeedbackClient) SubmitBacktestFeedback(ctx context.Context, in *mlpb.BacktestFeedbackRequest, opts ...grpc.CallOption) (*mlpb.BacktestFeedbackResponse, error) {
+	c.requests = append(c.requests, in)
+	if len(c.requests) <= c.failures {
+		return nil, errors.New("context deadline exceeded")
+	}
+	return &mlpb.BacktestFeedbackResponse{Success: true}, nil
+}
+
+func feedbackResult() *models.BacktestResult {
+	return &models.BacktestResult{
+		ID:             uuid.New(),
+		StrategyID:     uuid.New(),
+		TotalReturn:    0.12,
+		SharpeRatio:    1.4,
+		WinRate:        0.55,
+		TotalBets:      120,
+		Method:         "walk_forward",
+		CompositeScore: 0.8,
+		MLFeatures:     json.RawMessage(`{"edge":0.03,"avg_odds":4.2,"volatility":0.1}`),
+	}
+}
+
+func TestSubmitBacktestFeedbackRetrySendsSameIdempotencyKey(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+	grpcClient := &recordingFeedbackClient{failures: 1}
+	client := &MLClient{client: grpcClient, logger: logger}
+	result := feedbackResult()
+
+	err := client.SubmitBacktestFeedback(context.Background(), result)
+	require.ErrorIs(t, err, ErrFeedbackSubmissionFailed)
+	require.NoError(t, client.SubmitBacktestFeedback(context.Background(), result))
+
+	require.Len(t, grpcClient.requests, 2)
+	key := grpcClient.requests[0].IdempotencyKey
+	assert.NotEmpty(t, key)
+	assert.Equal(t, key, grpcClient.requests[1].IdempotencyKey)
+	assert.Contains(t, key, result.ID.String())
+
+	expected, err := FeedbackIdempotencyKey(result)
+	require.NoError(t, err)
+	assert.Equal(t, expected, key)
+}
+
+func TestFeedbackIdempotencyKeyTracksContent(t *testing.T) {
+	result := feedbackResult()
+	key, err := FeedbackIdempotencyKey(result)
+	require.NoError(t, err)
+
+	// A dead-lettered payload round-trips through JSON before redelivery
+	payload, err := json.Marshal(result)
+	require.NoError(t, err)
+	redelivered := &models.BacktestResult{}
+	require.NoError(t, json.Unmarshal(payload, redelivered))
+	redeliveredKey, err := FeedbackIdempotencyKey(redelivered)
+	require.NoError(t, err)
+	assert.Equal(t, key, redeliveredKey)
+
+	changed := *result
+	changed.CompositeScore = 0.6
+	changedKey, err := FeedbackIdempotencyKey(&changed)
+	require.NoError(t, err)
+	assert.NotEqual(t, key, changedKey, "different feedback for the same result is not a duplicate")
+}
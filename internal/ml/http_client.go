@@ -13,6 +13,7 @@ import (
 	"github.com/sirupsen/logrus"
 
 	"github.com/yourusername/clever-better/internal/config"
+	applogger "github.com/yourusername/clever-better/internal/logger"
 )
 
 // HTTPClient provides HTTP client for ML service
@@ -33,6 +34,14 @@ func NewHTTPClient(cfg *config.MLServiceConfig, logger *logrus.Logger) *HTTPClie
 	}
 }
 
+// do sends req, forwarding the request ID carried by its context
+func (c *HTTPClient) do(req *http.Request) (*http.Response, error) {
+	if requestID := applogger.RequestIDFromContext(req.Context()); requestID != "" {
+		req.Header.Set(applogger.RequestIDHeader, requestID)
+	}
+	return c.client.Do(req)
+}
+
 // TrainModelsRequest represents training request payload
 type TrainModelsRequest struct {
 	ModelType            string            `json:"model_type"`
@@ -76,7 +85,7 @@ func (c *HTTPClient) TrainModels(ctx context.Context, config TrainingConfig) (*T
 	}
 	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := c.client.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		MLGRPCErrorsTotal.WithLabelValues("train_models", "network").Inc()
 		return nil, fmt.Errorf("%w: %v", ErrConnectionFailed, err)
@@ -118,7 +127,7 @@ func (c *HTTPClient) GetTrainingStatus(ctx context.Context, jobID string) (*Trai
 		return nil, err
 	}
 
-	resp, err := c.client.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("%w: %v", ErrConnectionFailed, err)
 	}
@@ -143,7 +152,7 @@ func (c *HTTPClient) GetModelMetrics(ctx context.Context, modelType string, vers
 		return nil, err
 	}
 
-	resp, err := c.client.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("%w: %v", ErrConnectionFailed, err)
 	}
@@ -168,7 +177,7 @@ func (c *HTTPClient) HealthCheck(ctx context.Context) error {
 		return err
 	}
 
-	resp, err := c.client.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return fmt.Errorf("%w: %v", ErrMLServiceUnavailable, err)
 	}
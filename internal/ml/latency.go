@@ -0,0 +1,131 @@
+package ml
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/yourusername/clever-better/internal/config"
+)
+
+// Latency budget defaults, used where the config leaves a setting at zero
+const (
+	defaultLatencyWindowSize    = 50
+	defaultLatencyMinSamples    = 20
+	defaultLatencyProbeInterval = 10 * time.Second
+)
+
+// latencyBudget tracks upstream prediction latency over a rolling window of
+// requests. Once the window's p95 is over budget the service is bypassed.
+// While bypassed, one request per probe interval still goes upstream, and a
+// probe answered within budget ends the bypass with a fresh window.
+type latencyBudget struct {
+	budget        time.Duration
+	minSamples    int
+	probeInterval time.Duration
+	samples       []time.Duration
+	next          int
+	count         int
+	bypassed      bool
+	lastProbe     time.Time
+	mu            sync.Mutex
+}
+
+// newLatencyBudget creates a latency budget, returning nil when it is
+// disabled
+func newLatencyBudget(cfg config.MLLatencyBudgetConfig) *latencyBudget {
+	if !cfg.Enabled || cfg.P95Milliseconds <= 0 {
+		return nil
+	}
+	windowSize := cfg.WindowSize
+	if windowSize <= 0 {
+		windowSize = defaultLatencyWindowSize
+	}
+	minSamples := cfg.MinSamples
+	if minSamples <= 0 {
+		minSamples = defaultLatencyMinSamples
+	}
+	if minSamples > windowSize {
+		minSamples = windowSize
+	}
+	probeInterval := time.Duration(cfg.ProbeIntervalSeconds) * time.Second
+	if probeInterval <= 0 {
+		probeInterval = defaultLatencyProbeInterval
+	}
+	return &latencyBudget{
+		budget:        time.Duration(cfg.P95Milliseconds) * time.Millisecond,
+		minSamples:    minSamples,
+		probeInterval: probeInterval,
+		samples:       make([]time.Duration, windowSize),
+	}
+}
+
+// allow reports whether a request may go upstream at now. While bypassed
+// only one probe per probe interval is let through.
+func (b *latencyBudget) allow(now time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !b.bypassed {
+		return true
+	}
+	if now.Sub(b.lastProbe) < b.probeInterval {
+		return false
+	}
+	b.lastProbe = now
+	return true
+}
+
+// record adds an upstream request's latency at now. It reports whether that
+// tripped or ended the bypass, whether the service is now bypassed and the
+// window's p95.
+func (b *latencyBudget) record(latency time.Duration, now time.Time) (changed, bypassed bool, p95 time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.bypassed {
+		if latency > b.budget {
+			return false, true, b.p95()
+		}
+		b.bypassed = false
+		b.next, b.count = 0, 0
+		b.add(latency)
+		return true, false, latency
+	}
+
+	b.add(latency)
+	p95 = b.p95()
+	if b.count >= b.minSamples && p95 > b.budget {
+		b.bypassed = true
+		b.lastProbe = now
+		return true, true, p95
+	}
+	return false, false, p95
+}
+
+// stats returns the window's p95 and whether the service is bypassed
+func (b *latencyBudget) stats() (time.Duration, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.p95(), b.bypassed
+}
+
+func (b *latencyBudget) add(latency time.Duration) {
+	b.samples[b.next] = latency
+	b.next = (b.next + 1) % len(b.samples)
+	if b.count < len(b.samples) {
+		b.count++
+	}
+}
+
+// p95 returns the nearest-rank 95th percentile of the window
+func (b *latencyBudget) p95() time.Duration {
+	if b.count == 0 {
+		return 0
+	}
+	window := make([]time.Duration, b.count)
+	copy(window, b.samples[:b.count])
+	sort.Slice(window, func(i, j int) bool { return window[i] < window[j] })
+	rank := int(math.Ceil(0.95 * float64(len(window))))
+	return window[rank-1]
+}
@@ -0,0 +1,104 @@
+package ml
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yourusername/clever-better/internal/config"
+)
+
+// slowPredictionClient advances a test clock by its latency on every call
+type slowPredictionClient struct {
+	countingPredictionClient
+	now     *time.Time
+	latency time.Duration
+}
+
+func (c *slowPredictionClient) GetPrediction(ctx context.Context, raceID, runnerID, strategyID uuid.UUID, features []float64, modelVersion string) (*PredictionResult, error) {
+	*c.now = c.now.Add(c.latency)
+	return c.countingPredictionClient.GetPrediction(ctx, raceID, runnerID, strategyID, features, modelVersion)
+}
+
+func TestCachedClientBypassesSlowService(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	upstream := &slowPredictionClient{now: &now, latency: 300 * time.Millisecond}
+	client := newTestCachedClient(upstream)
+	client.now = func() time.Time { return now }
+	client.latency = newLatencyBudget(config.MLLatencyBudgetConfig{
+		Enabled:              true,
+		P95Milliseconds:      100,
+		WindowSize:           10,
+		MinSamples:           5,
+		ProbeIntervalSeconds: 10,
+	})
+	ctx := context.Background()
+	predict := func(feature float64) (*PredictionResult, error) {
+		return client.GetPrediction(ctx, uuid.New(), uuid.New(), uuid.New(), []float64{feature}, "v1")
+	}
+
+	for i := 0; i < 4; i++ {
+		_, err := predict(float64(i) / 10)
+		require.NoError(t, err, "too few samples to judge the service")
+	}
+	_, err := predict(0.4)
+	require.NoError(t, err)
+	p95, bypassed := client.GetLatencyStats()
+	assert.True(t, bypassed)
+	assert.Equal(t, 300*time.Millisecond, p95)
+
+	_, err = predict(0.5)
+	assert.ErrorIs(t, err, ErrMLServiceSlow)
+	assert.Equal(t, 5, upstream.predictCalls, "a bypassed service is not called")
+	_, err = predict(0.1)
+	assert.NoError(t, err, "cached predictions are still served")
+
+	// A slow probe keeps the bypass in place
+	now = now.Add(10 * time.Second)
+	_, err = predict(0.6)
+	require.NoError(t, err)
+	assert.Equal(t, 6, upstream.predictCalls)
+	_, err = predict(0.7)
+	assert.ErrorIs(t, err, ErrMLServiceSlow, "only one probe per interval")
+
+	// A fast probe ends it
+	upstream.latency = 20 * time.Millisecond
+	now = now.Add(10 * time.Second)
+	_, err = predict(0.7)
+	require.NoError(t, err)
+	p95, bypassed = client.GetLatencyStats()
+	assert.False(t, bypassed)
+	assert.Equal(t, 20*time.Millisecond, p95)
+
+	_, err = predict(0.8)
+	require.NoError(t, err)
+	assert.Equal(t, 8, upstream.predictCalls)
+}
+
+func TestLatencyBudgetP95(t *testing.T) {
+	budget := newLatencyBudget(config.MLLatencyBudgetConfig{Enabled: true, P95Milliseconds: 100, WindowSize: 20, MinSamples: 20})
+	now := time.Now()
+
+	budget.record(500*time.Millisecond, now)
+	for i := 0; i < 19; i++ {
+		budget.record(10*time.Millisecond, now)
+	}
+	p95, bypassed := budget.stats()
+	assert.Equal(t, 10*time.Millisecond, p95, "one outlier in twenty is under the 95th percentile")
+	assert.False(t, bypassed)
+
+	// The window rolls, so the outlier drops out as another arrives
+	changed, bypassed, _ := budget.record(500*time.Millisecond, now)
+	assert.False(t, changed)
+	assert.False(t, bypassed)
+	changed, bypassed, p95 = budget.record(500*time.Millisecond, now)
+	assert.True(t, changed)
+	assert.True(t, bypassed)
+	assert.Equal(t, 500*time.Millisecond, p95)
+
+	assert.Nil(t, newLatencyBudget(config.MLLatencyBudgetConfig{P95Milliseconds: 100}), "disabled budgets are nil")
+}
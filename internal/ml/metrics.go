@@ -68,4 +68,36 @@ var (
 		},
 		[]string{"model_type", "status"},
 	)
+
+	// MLLatencyBypassActive is 1 while predictions bypass a slow ML service
+	MLLatencyBypassActive = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "ml_latency_bypass_active",
+			Help: "Whether ML predictions are bypassed for exceeding the latency budget (1) or not (0)",
+		},
+	)
+
+	// MLLatencyBypassTotal counts trips into latency bypass
+	MLLatencyBypassTotal = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "ml_latency_bypass_total",
+			Help: "Total number of times ML predictions were bypassed for exceeding the latency budget",
+		},
+	)
+
+	// MLPredictionLatencyP95 tracks the p95 latency the budget is judged on
+	MLPredictionLatencyP95 = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "ml_prediction_latency_p95_seconds",
+			Help: "Rolling p95 latency of upstream ML predictions in seconds",
+		},
+	)
+
+	// MLFeedbackDeadLetterDepth tracks failed feedback submissions awaiting redelivery
+	MLFeedbackDeadLetterDepth = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "ml_feedback_dead_letter_depth",
+			Help: "Number of failed feedback submissions waiting to be retried",
+		},
+	)
 )
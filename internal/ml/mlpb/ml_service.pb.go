@@ -465,6 +465,8 @@ type BacktestFeedbackRequest struct {
 	TotalBets      int32                  `protobuf:"varint,8,opt,name=total_bets,json=totalBets,proto3" json:"total_bets,omitempty"`
 	MlFeatures     map[string]float64     `protobuf:"bytes,9,rep,name=ml_features,json=mlFeatures,proto3" json:"ml_features,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"fixed64,2,opt,name=value"`
 	Method         string                 `protobuf:"bytes,10,opt,name=method,proto3" json:"method,omitempty"`
+	// Stable per result and payload so the service can drop retried deliveries
+	IdempotencyKey string `protobuf:"bytes,11,opt,name=idempotency_key,json=idempotencyKey,proto3" json:"idempotency_key,omitempty"`
 	unknownFields  protoimpl.UnknownFields
 	sizeCache      protoimpl.SizeCache
 }
@@ -569,6 +571,13 @@ func (x *BacktestFeedbackRequest) GetMethod() string {
 	return ""
 }
 
+func (x *BacktestFeedbackRequest) GetIdempotencyKey() string {
+	if x != nil {
+		return x.IdempotencyKey
+	}
+	return ""
+}
+
 type BacktestFeedbackResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
@@ -1119,7 +1128,7 @@ const file_ml_service_proto_rawDesc = "" +
 	"\x05value\x18\x02 \x01(\x01R\x05value:\x028\x01\"&\n" +
 	"\fHealthStatus\x12\x16\n" +
 	"\x06status\x18\x01 \x01(\tR\x06status\"\a\n" +
-	"\x05Empty\"\xc6\x03\n" +
+	"\x05Empty\"\xef\x03\n" +
 	"\x17BacktestFeedbackRequest\x12\x1f\n" +
 	"\vstrategy_id\x18\x01 \x01(\tR\n" +
 	"strategyId\x12'\n" +
@@ -1134,7 +1143,8 @@ const file_ml_service_proto_rawDesc = "" +
 	"\vml_features\x18\t \x03(\v22.mlservice.BacktestFeedbackRequest.MlFeaturesEntryR\n" +
 	"mlFeatures\x12\x16\n" +
 	"\x06method\x18\n" +
-	" \x01(\tR\x06method\x1a=\n" +
+	" \x01(\tR\x06method\x12'\n" +
+	"\x0fidempotency_key\x18\v \x01(\tR\x0eidempotencyKey\x1a=\n" +
 	"\x0fMlFeaturesEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
 	"\x05value\x18\x02 \x01(\x01R\x05value:\x028\x01\"o\n" +
@@ -48,6 +48,17 @@ type PredictionResult struct {
 	Recommendation      string
 	ModelVersion        string
 	PredictedAt         time.Time
+	// Constituents holds each model's output when the result blends an
+	// ensemble, and is empty otherwise
+	Constituents        []ModelPrediction
+}
+
+// ModelPrediction is one model's output within an ensemble prediction
+type ModelPrediction struct {
+	ModelVersion string
+	Weight       float64
+	Probability  float64
+	Confidence   float64
 }
 
 // BacktestFilters defines filters for querying backtest results
@@ -2,6 +2,8 @@ package models
 
 import (
 	"encoding/json"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -27,5 +29,23 @@ type BacktestResult struct {
 	Recommendation string          `db:"recommendation" json:"recommendation"`
 	MLFeatures     json.RawMessage `db:"ml_features" json:"ml_features"`
 	FullResults    json.RawMessage `db:"full_results" json:"full_results"`
+	Tags           []string        `db:"tags" json:"tags,omitempty"`
 	CreatedAt      time.Time       `db:"created_at" json:"created_at"`
 }
+
+// NormalizeTags trims and lower-cases tags, dropping blanks and duplicates,
+// and returns them sorted. The result is never nil.
+func NormalizeTags(tags []string) []string {
+	seen := make(map[string]bool, len(tags))
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	sort.Strings(normalized)
+	return normalized
+}
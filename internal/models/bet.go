@@ -42,8 +42,17 @@ type Bet struct {
 	StrategyID uuid.UUID  `db:"strategy_id" json:"strategy_id" validate:"required,uuid4"`
 	MarketType MarketType `db:"market_type" json:"market_type" validate:"required,oneof=WIN PLACE"`
 	Side      BetSide    `db:"side" json:"side" validate:"required,oneof=BACK LAY"`
-	Odds      float64    `db:"odds" json:"odds" validate:"required,gt=1"`
+	Odds      float64    `db:"odds" json:"odds" validate:"required_unless=IsBSP true,omitempty,gt=1"`
 	Stake     float64    `db:"stake" json:"stake" validate:"required,gt=0"`
+	IsBSP     bool       `db:"is_bsp" json:"is_bsp"` // Placed at Betfair Starting Price; Odds is indicative only
+	EachWay       bool    `db:"each_way" json:"each_way"` // Stake is placed on both a win leg and a place leg
+	PlaceFraction float64 `db:"place_fraction" json:"place_fraction,omitempty" validate:"required_if=EachWay true,omitempty,gt=0,lte=1"` // Share of the win odds' profit paid on the place leg
+	PlacesPaid    int     `db:"places_paid" json:"places_paid,omitempty" validate:"required_if=EachWay true,omitempty,gt=0"`         // Finishing positions the place leg pays on
+	Account   string     `db:"account" json:"account,omitempty"` // Betfair account the bet was placed through; empty for the primary account
+	Reasoning     string   `db:"reasoning" json:"reasoning,omitempty"` // Strategy's explanation of the signal behind the bet
+	ExpectedValue *float64 `db:"expected_value" json:"expected_value"` // Signal's expected value per unit staked
+	Confidence    *float64 `db:"confidence" json:"confidence"`         // Strategy's confidence in the signal
+	ModelProbability *float64 `db:"model_probability" json:"model_probability"` // ML win probability the signal was filtered on, if any
 	MatchedPrice *float64  `db:"matched_price" json:"matched_price"` // Actual matched price
 	MatchedSize  *float64  `db:"matched_size" json:"matched_size"`   // Actual matched size
 	Status    BetStatus  `db:"status" json:"status" validate:"required"`
@@ -53,6 +62,8 @@ type Bet struct {
 	CancelledAt *time.Time `db:"cancelled_at" json:"cancelled_at"`
 	ProfitLoss *float64  `db:"profit_loss" json:"profit_loss"`
 	Commission *float64  `db:"commission" json:"commission"`
+	WinProfitLoss   *float64 `db:"win_profit_loss" json:"win_profit_loss,omitempty"`     // Each-way win leg's P&L once settled
+	PlaceProfitLoss *float64 `db:"place_profit_loss" json:"place_profit_loss,omitempty"` // Each-way place leg's P&L once settled
 	CreatedAt time.Time  `db:"created_at" json:"created_at"`
 	UpdatedAt time.Time  `db:"updated_at" json:"updated_at"`
 }
@@ -75,9 +86,47 @@ func (b *Bet) IsSettled() bool {
 
 // GetROI returns the return on investment percentage
 func (b *Bet) GetROI() float64 {
-	if b.Stake == 0 {
+	stake := b.TotalStake()
+	if stake == 0 {
 		return 0
 	}
 	pl := b.CalculateProfitLoss()
-	return (pl / b.Stake) * 100
+	return (pl / stake) * 100
+}
+
+// TotalStake returns the amount staked across the bet's legs. Each-way bets
+// stake Stake on each of their two legs.
+func (b *Bet) TotalStake() float64 {
+	if b.EachWay {
+		return 2 * b.Stake
+	}
+	return b.Stake
+}
+
+// PlaceOdds returns the price the place leg of an each-way bet settles at:
+// the win price's profit scaled by PlaceFraction
+func (b *Bet) PlaceOdds() float64 {
+	return 1 + (b.SettlementPrice()-1)*b.PlaceFraction
+}
+
+// SettlementPrice returns the price the bet settles at. BSP bets and partially
+// filled orders carry their final price in MatchedPrice; otherwise the
+// requested Odds are used.
+func (b *Bet) SettlementPrice() float64 {
+	if b.MatchedPrice != nil && *b.MatchedPrice > 1 {
+		return *b.MatchedPrice
+	}
+	return b.Odds
+}
+
+// UnmatchedSize returns the stake still resting on the exchange. Bets without
+// a reported fill are treated as wholly unmatched.
+func (b *Bet) UnmatchedSize() float64 {
+	if b.MatchedSize == nil {
+		return b.Stake
+	}
+	if remaining := b.Stake - *b.MatchedSize; remaining > 0 {
+		return remaining
+	}
+	return 0
 }
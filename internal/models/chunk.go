@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// Chunk is one TimescaleDB chunk of a hypertable, covering rows with times in
+// [RangeStart, RangeEnd)
+type Chunk struct {
+	Name       string    `db:"chunk_name" json:"chunk_name"`
+	RangeStart time.Time `db:"range_start" json:"range_start"`
+	RangeEnd   time.Time `db:"range_end" json:"range_end"`
+	Compressed bool      `db:"is_compressed" json:"is_compressed"`
+	SizeBytes  int64     `db:"total_bytes" json:"total_bytes"`
+}
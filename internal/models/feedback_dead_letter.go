@@ -0,0 +1,21 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// FeedbackDeadLetter is an ML feedback submission that failed and is waiting
+// to be redelivered. Payload is the backtest result as it was submitted.
+type FeedbackDeadLetter struct {
+	ID               uuid.UUID       `db:"id" json:"id"`
+	BacktestResultID uuid.UUID       `db:"backtest_result_id" json:"backtest_result_id" validate:"required"`
+	Payload          json.RawMessage `db:"payload" json:"payload" validate:"required"`
+	LastError        string          `db:"last_error" json:"last_error"`
+	Attempts         int             `db:"attempts" json:"attempts"`
+	NextAttemptAt    time.Time       `db:"next_attempt_at" json:"next_attempt_at"`
+	CreatedAt        time.Time       `db:"created_at" json:"created_at"`
+	UpdatedAt        time.Time       `db:"updated_at" json:"updated_at"`
+}
@@ -1,6 +1,10 @@
 package models
 
 import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"math"
 	"time"
 
 	"github.com/google/uuid"
@@ -17,6 +21,88 @@ type OddsSnapshot struct {
 	LaySize     *float64   `db:"lay_size" json:"lay_size"`
 	LTP         *float64   `db:"ltp" json:"ltp"`
 	TotalVolume *float64   `db:"total_volume" json:"total_volume"`
+	// BackLadder and LayLadder are the price levels on offer, best first,
+	// when the source recorded more than the top of book
+	BackLadder  PriceLadder `db:"back_ladder" json:"back_ladder,omitempty"`
+	LayLadder   PriceLadder `db:"lay_ladder" json:"lay_ladder,omitempty"`
+	// Source names the data source that recorded the snapshot; empty for
+	// snapshots recorded before sources were tracked
+	Source      string      `db:"source" json:"source,omitempty"`
+}
+
+// PriceLevel is one price on the exchange ladder and the size offered at it
+type PriceLevel struct {
+	Price float64 `json:"price"`
+	Size  float64 `json:"size"`
+}
+
+// PriceLadder is a side of the exchange ladder, best price first. It is
+// stored as JSON.
+type PriceLadder []PriceLevel
+
+// Depth returns the size offered across the best maxLevels levels. Zero or
+// less sums the whole ladder.
+func (l PriceLadder) Depth(maxLevels int) float64 {
+	total := 0.0
+	for i, level := range l {
+		if maxLevels > 0 && i >= maxLevels {
+			break
+		}
+		total += math.Max(level.Size, 0)
+	}
+	return total
+}
+
+// Value encodes the ladder for storage; an empty ladder is stored as NULL
+func (l PriceLadder) Value() (driver.Value, error) {
+	if len(l) == 0 {
+		return nil, nil
+	}
+	encoded, err := json.Marshal([]PriceLevel(l))
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode price ladder: %w", err)
+	}
+	return string(encoded), nil
+}
+
+// Scan decodes a stored ladder
+func (l *PriceLadder) Scan(src interface{}) error {
+	var data []byte
+	switch v := src.(type) {
+	case nil:
+		*l = nil
+		return nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("unsupported price ladder type %T", src)
+	}
+	var levels []PriceLevel
+	if err := json.Unmarshal(data, &levels); err != nil {
+		return fmt.Errorf("failed to decode price ladder: %w", err)
+	}
+	*l = levels
+	return nil
+}
+
+// AvailableSize returns the size offered to a bet on side across the best
+// maxLevels ladder levels, reading the same side as BackSize and LaySize.
+// Snapshots without a ladder fall back to the top-of-book size. The boolean
+// is false when no size was recorded.
+func (o *OddsSnapshot) AvailableSize(side BetSide, maxLevels int) (float64, bool) {
+	ladder, top := o.BackLadder, o.BackSize
+	if side == BetSideLay {
+		ladder, top = o.LayLadder, o.LaySize
+	}
+	if len(ladder) > 0 {
+		return ladder.Depth(maxLevels), true
+	}
+	if top == nil {
+		return 0, false
+	}
+	return math.Max(*top, 0), true
 }
 
 // GetSpread returns the bid-ask spread (lay_price - back_price)
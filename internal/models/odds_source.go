@@ -0,0 +1,64 @@
+package models
+
+import (
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// OddsSourceBetfair marks odds snapshots recorded from the Betfair exchange
+const OddsSourceBetfair = "betfair"
+
+// OddsSourcePreference orders odds sources from most to least trusted.
+// Sources are matched ignoring case, and unlisted sources rank after every
+// listed one.
+type OddsSourcePreference []string
+
+// Prefer keeps one snapshot per runner and time, from the most preferred
+// source present. Sources of equal rank are ordered by name, so the choice
+// never depends on the order rows were returned in. The kept snapshots stay
+// in their original order.
+func (p OddsSourcePreference) Prefer(snapshots []*OddsSnapshot) []*OddsSnapshot {
+	type key struct {
+		runnerID uuid.UUID
+		time     int64
+	}
+
+	best := make(map[key]*OddsSnapshot, len(snapshots))
+	for _, snapshot := range snapshots {
+		k := key{snapshot.RunnerID, snapshot.Time.UnixNano()}
+		if current, ok := best[k]; !ok || p.before(snapshot, current) {
+			best[k] = snapshot
+		}
+	}
+	if len(best) == len(snapshots) {
+		return snapshots
+	}
+
+	kept := make([]*OddsSnapshot, 0, len(best))
+	for _, snapshot := range snapshots {
+		if best[key{snapshot.RunnerID, snapshot.Time.UnixNano()}] == snapshot {
+			kept = append(kept, snapshot)
+		}
+	}
+	return kept
+}
+
+// before reports whether a's source is preferred to b's
+func (p OddsSourcePreference) before(a, b *OddsSnapshot) bool {
+	rankA, rankB := p.rank(a.Source), p.rank(b.Source)
+	if rankA != rankB {
+		return rankA < rankB
+	}
+	return a.Source < b.Source
+}
+
+// rank returns the position of source in the preference
+func (p OddsSourcePreference) rank(source string) int {
+	for i, preferred := range p {
+		if strings.EqualFold(preferred, source) {
+			return i
+		}
+	}
+	return len(p)
+}
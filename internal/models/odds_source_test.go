@@ -0,0 +1,41 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOddsSourcePreferenceSelectsPreferredSource(t *testing.T) {
+	runnerID := uuid.New()
+	at := time.Date(2024, 3, 1, 13, 55, 0, 0, time.UTC)
+	price := func(p float64) *float64 { return &p }
+
+	racingPost := &OddsSnapshot{RunnerID: runnerID, Time: at, Source: "racing_post", BackPrice: price(3.5)}
+	betfair := &OddsSnapshot{RunnerID: runnerID, Time: at, Source: OddsSourceBetfair, BackPrice: price(3.2)}
+	later := &OddsSnapshot{RunnerID: runnerID, Time: at.Add(time.Minute), Source: "racing_post", BackPrice: price(3.4)}
+	snapshots := []*OddsSnapshot{racingPost, betfair, later}
+
+	kept := OddsSourcePreference{"Betfair", "racing_post"}.Prefer(snapshots)
+	require.Len(t, kept, 2)
+	assert.Same(t, betfair, kept[0], "sources are matched ignoring case")
+	assert.Same(t, later, kept[1], "the only source at a time falls back")
+
+	kept = OddsSourcePreference{"racing_post", "betfair"}.Prefer(snapshots)
+	require.Len(t, kept, 2)
+	assert.Equal(t, 3.5, *kept[0].BackPrice)
+
+	// Unlisted sources rank last and tie by name, whatever the row order
+	unlisted := &OddsSnapshot{RunnerID: runnerID, Time: at, Source: "zebra"}
+	for _, order := range [][]*OddsSnapshot{{racingPost, unlisted}, {unlisted, racingPost}} {
+		kept = OddsSourcePreference{OddsSourceBetfair}.Prefer(order)
+		require.Len(t, kept, 1)
+		assert.Same(t, racingPost, kept[0])
+	}
+
+	// Snapshots without duplicates are returned untouched
+	assert.Equal(t, []*OddsSnapshot{betfair, later}, OddsSourcePreference(nil).Prefer([]*OddsSnapshot{betfair, later}))
+}
@@ -2,6 +2,8 @@ package models
 
 import (
 	"encoding/json"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -36,3 +38,85 @@ func (r *Race) IsFinished() bool {
 func (r *Race) TimeToStart() time.Duration {
 	return time.Until(r.ScheduledStart)
 }
+
+// Race disciplines, derived from RaceType
+const (
+	DisciplineGreyhound = "greyhound"
+	DisciplineFlat      = "flat"
+	DisciplineJumps     = "jumps"
+)
+
+// horseRaceTypes maps lower-cased horse racing race types to their discipline.
+// Anything else is treated as a greyhound grade (A1, Open Race, ...).
+var horseRaceTypes = map[string]string{
+	"flat":          DisciplineFlat,
+	"all weather":   DisciplineFlat,
+	"hurdle":        DisciplineJumps,
+	"steeplechase":  DisciplineJumps,
+	"nh flat":       DisciplineJumps,
+	"bumper":        DisciplineJumps,
+	"national hunt": DisciplineJumps,
+}
+
+// DisciplineForRaceType returns the discipline a race type belongs to
+func DisciplineForRaceType(raceType string) string {
+	if discipline, ok := horseRaceTypes[strings.ToLower(strings.TrimSpace(raceType))]; ok {
+		return discipline
+	}
+	return DisciplineGreyhound
+}
+
+// Discipline returns the race's discipline
+func (r *Race) Discipline() string {
+	return DisciplineForRaceType(r.RaceType)
+}
+
+// IsHorseRace reports whether the race is a flat or jumps horse race
+func (r *Race) IsHorseRace() bool {
+	return r.Discipline() != DisciplineGreyhound
+}
+
+// SetConditions records the race conditions, stamped with the current
+// conditions schema version
+func (r *Race) SetConditions(conditions map[string]interface{}) error {
+	stamped := make(map[string]interface{}, len(conditions)+1)
+	for key, value := range conditions {
+		stamped[key] = value
+	}
+	stamped[SchemaVersionKey] = RaceConditionsSchemaVersion
+
+	encoded, err := json.Marshal(stamped)
+	if err != nil {
+		return fmt.Errorf("failed to encode race conditions: %w", err)
+	}
+	r.Conditions = encoded
+	return nil
+}
+
+// CountryCode returns the country recorded in the race conditions at
+// ingestion, or "" when none was recorded
+func (r *Race) CountryCode() string {
+	raw, err := MigrateRaceConditions(r.Conditions)
+	if err != nil || len(raw) == 0 {
+		return ""
+	}
+	var conditions struct {
+		CountryCode string `json:"country_code"`
+	}
+	if err := json.Unmarshal(raw, &conditions); err != nil {
+		return ""
+	}
+	return conditions.CountryCode
+}
+
+// RaceBundle is a race with its runners and their odds history, everything
+// needed to evaluate strategies on the race
+type RaceBundle struct {
+	Race *Race
+	// Runners are in trap order. Withdrawn runners are left out; reserves
+	// are kept so the caller can choose whether to include them.
+	Runners []*Runner
+	// Odds holds every snapshot from the requested start to the race's
+	// scheduled start, oldest first
+	Odds []*OddsSnapshot
+}
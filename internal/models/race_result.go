@@ -9,6 +9,14 @@ import (
 	"github.com/shopspring/decimal"
 )
 
+// Race result statuses
+const (
+	RaceResultStatusPending   = "pending"
+	RaceResultStatusCompleted = "completed"
+	RaceResultStatusCancelled = "cancelled"
+	RaceResultStatusVoid      = "void"
+)
+
 // RaceResult represents the outcome and results of a completed race
 type RaceResult struct {
 	Time         time.Time       `db:"time" json:"time"`
@@ -16,7 +24,7 @@ type RaceResult struct {
 	WinnerTrap   *int            `db:"winner_trap" json:"winner_trap"`
 	Positions    json.RawMessage `db:"positions" json:"positions"` // JSON array of runner positions
 	TotalPayouts decimal.Decimal `db:"total_payouts" json:"total_payouts"`
-	Status       string          `db:"status" json:"status" validate:"required,oneof=pending completed cancelled"`
+	Status       string          `db:"status" json:"status" validate:"required,oneof=pending completed cancelled void"`
 	CreatedAt    time.Time       `db:"created_at" json:"created_at"`
 	UpdatedAt    time.Time       `db:"updated_at" json:"updated_at"`
 }
@@ -61,6 +69,96 @@ func (rr *RaceResult) ParsePositions() (*PositionsData, error) {
 	return &posData, nil
 }
 
+// IsVoid reports whether the race was voided or cancelled, in which case
+// every bet on it is refunded
+func (rr *RaceResult) IsVoid() bool {
+	return rr.Status == RaceResultStatusVoid || rr.Status == RaceResultStatusCancelled
+}
+
+// DeadHeatCount returns the number of runners sharing first place. It is 1
+// unless the positions record a dead heat.
+func (rr *RaceResult) DeadHeatCount() int {
+	positions, err := rr.ParsePositions()
+	if err != nil {
+		return 1
+	}
+	winners := 0
+	for _, entry := range positions.Runners {
+		if entry.Position == 1 {
+			winners++
+		}
+	}
+	if winners < 1 {
+		return 1
+	}
+	return winners
+}
+
+// FinishingPosition returns the position recorded for a runner. The boolean
+// is false when the runner has no recorded position.
+func (rr *RaceResult) FinishingPosition(runnerID uuid.UUID, trapNumber int) (int, bool) {
+	entry, ok := rr.findRunner(runnerID, trapNumber)
+	if !ok || entry.Position <= 0 {
+		return 0, false
+	}
+	return entry.Position, true
+}
+
+// IsWinner reports whether a runner won, counting each runner in a dead heat
+// for first. Results record only one winner trap, so positions are checked too.
+func (rr *RaceResult) IsWinner(runnerID uuid.UUID, trapNumber int) bool {
+	if rr.WinnerTrap != nil && trapNumber == *rr.WinnerTrap {
+		return true
+	}
+	position, ok := rr.FinishingPosition(runnerID, trapNumber)
+	return ok && position == 1
+}
+
+// IsPlaced reports whether a runner finished within the first places,
+// counting a winner as placed. The second value is false when the result
+// cannot tell because no position was recorded for a runner that did not win.
+func (rr *RaceResult) IsPlaced(runnerID uuid.UUID, trapNumber int, places int) (bool, bool) {
+	if rr.IsWinner(runnerID, trapNumber) {
+		return places > 0, true
+	}
+	entry, ok := rr.findRunner(runnerID, trapNumber)
+	if !ok {
+		return false, false
+	}
+	return entry.Position > 0 && entry.Position <= places, true
+}
+
+// StartingPrice returns the Betfair Starting Price recorded for a runner.
+// The boolean is false when no SP is available.
+func (rr *RaceResult) StartingPrice(runnerID uuid.UUID, trapNumber int) (float64, bool) {
+	entry, ok := rr.findRunner(runnerID, trapNumber)
+	if !ok {
+		return 0, false
+	}
+	sp, _ := entry.SP.Float64()
+	if sp <= 1 {
+		return 0, false
+	}
+	return sp, true
+}
+
+// findRunner locates a runner's entry in the positions. Runners are matched
+// by ID first, falling back to trap number for results that were ingested
+// without runner IDs.
+func (rr *RaceResult) findRunner(runnerID uuid.UUID, trapNumber int) (RunnerPosition, bool) {
+	positions, err := rr.ParsePositions()
+	if err != nil {
+		return RunnerPosition{}, false
+	}
+	for _, entry := range positions.Runners {
+		if entry.RunnerID != runnerID && (entry.RunnerID != uuid.Nil || entry.TrapNumber != trapNumber) {
+			continue
+		}
+		return entry, true
+	}
+	return RunnerPosition{}, false
+}
+
 // Errors
 var (
 	ErrRaceResultNotFound    = fmt.Errorf("race result not found")
@@ -2,27 +2,60 @@ package models
 
 import (
 	"encoding/json"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 )
 
-// Runner represents a runner (horse) in a race
+// Runner represents a runner in a race. TrapNumber is the greyhound trap or
+// the horse's stall (draw); it is zero for horse races run without stalls.
+// Weight is in kg for greyhounds and lbs for horses.
 type Runner struct {
 	ID                  uuid.UUID       `db:"id" json:"id" validate:"required,uuid4"`
 	RaceID              uuid.UUID       `db:"race_id" json:"race_id" validate:"required,uuid4"`
-	TrapNumber          int             `db:"trap_number" json:"trap_number" validate:"required,gt=0,lt=15"`
+	TrapNumber          int             `db:"trap_number" json:"trap_number" validate:"gte=0,lte=40"`
 	Name                string          `db:"name" json:"name" validate:"required"`
 	FormRating          *float64        `db:"form_rating" json:"form_rating"`
 	Weight              *float64        `db:"weight" json:"weight"`
 	Trainer             string          `db:"trainer" json:"trainer"`
 	DaysSinceLastRace   *int            `db:"days_since_last_race" json:"days_since_last_race"`
+	Status              string          `db:"status" json:"status"`
 	Metadata            json.RawMessage `db:"metadata" json:"metadata"`
 	CreatedAt           time.Time       `db:"created_at" json:"created_at"`
 	UpdatedAt           time.Time       `db:"updated_at" json:"updated_at"`
 	Race                *Race           `db:"-" json:"race,omitempty"`
 }
 
+// Runner statuses. Withdrawn runners are non-runners still listed in the
+// race; reserves only run if another runner is withdrawn.
+const (
+	RunnerStatusActive    = "active"
+	RunnerStatusWithdrawn = "withdrawn"
+	RunnerStatusReserve   = "reserve"
+)
+
+// IsActive reports whether the runner is expected to run. Runners stored
+// before statuses were recorded have no status and count as active.
+func (r *Runner) IsActive() bool {
+	return r.Status == "" || r.Status == RunnerStatusActive
+}
+
+// RunnerStatusFromBetfair maps a Betfair runner status to a runner status.
+// Removed selections are withdrawn and hidden selections are reserves; any
+// other status, including winner and loser, is active.
+func RunnerStatusFromBetfair(status string) string {
+	switch strings.ToUpper(strings.TrimSpace(status)) {
+	case "REMOVED", "REMOVED_VACANT":
+		return RunnerStatusWithdrawn
+	case "HIDDEN":
+		return RunnerStatusReserve
+	default:
+		return RunnerStatusActive
+	}
+}
+
 // GetFormRating returns the form rating or 0 if nil
 func (r *Runner) GetFormRating() float64 {
 	if r.FormRating == nil {
@@ -38,3 +71,49 @@ func (r *Runner) GetRecentForm() int {
 	}
 	return *r.DaysSinceLastRace
 }
+
+// BetfairSelection identifies a runner on the Betfair exchange
+type BetfairSelection struct {
+	MarketID    string `json:"market_id"`
+	SelectionID uint64 `json:"selection_id"`
+}
+
+// SetBetfairSelection records the runner's Betfair market and selection IDs in
+// its metadata, keeping any other metadata keys and stamping the current
+// metadata schema version
+func (r *Runner) SetBetfairSelection(marketID string, selectionID uint64) error {
+	current, err := MigrateRunnerMetadata(r.Metadata)
+	if err != nil {
+		return err
+	}
+	metadata, err := decodeBlob(current)
+	if err != nil {
+		return fmt.Errorf("failed to decode runner metadata: %w", err)
+	}
+	if metadata == nil {
+		metadata = make(map[string]interface{})
+	}
+	metadata["market_id"] = marketID
+	metadata["selection_id"] = selectionID
+	metadata[SchemaVersionKey] = RunnerMetadataSchemaVersion
+
+	encoded, err := json.Marshal(metadata)
+	if err != nil {
+		return fmt.Errorf("failed to encode runner metadata: %w", err)
+	}
+	r.Metadata = encoded
+	return nil
+}
+
+// BetfairSelection returns the runner's Betfair IDs, if recorded
+func (r *Runner) BetfairSelection() (BetfairSelection, bool) {
+	var selection BetfairSelection
+	metadata, err := MigrateRunnerMetadata(r.Metadata)
+	if err != nil || len(metadata) == 0 {
+		return selection, false
+	}
+	if err := json.Unmarshal(metadata, &selection); err != nil {
+		return selection, false
+	}
+	return selection, selection.SelectionID != 0
+}
@@ -0,0 +1,152 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// SchemaVersionKey records the shape of a free-form JSON blob
+const SchemaVersionKey = "schema_version"
+
+// Current shapes of the free-form JSON columns. Blobs written before
+// versioning carry no schema_version and are treated as version 1.
+const (
+	RunnerMetadataSchemaVersion = 2
+	RaceConditionsSchemaVersion = 2
+)
+
+// jsonMigration upgrades a decoded blob from one version to the next
+type jsonMigration func(blob map[string]interface{}) error
+
+// runnerMetadataMigrations upgrades runner metadata, keyed by source version
+var runnerMetadataMigrations = map[int]jsonMigration{
+	// v1 blobs used Betfair's camelCase keys and sometimes a string selection ID
+	1: func(blob map[string]interface{}) error {
+		renameKey(blob, "marketId", "market_id")
+		renameKey(blob, "selectionId", "selection_id")
+		if id, ok := blob["selection_id"].(string); ok {
+			if _, err := strconv.ParseUint(id, 10, 64); err != nil {
+				return fmt.Errorf("invalid selection_id %q: %w", id, err)
+			}
+			blob["selection_id"] = json.Number(id)
+		}
+		return nil
+	},
+}
+
+// raceConditionsMigrations upgrades race conditions, keyed by source version
+var raceConditionsMigrations = map[int]jsonMigration{
+	// v1 blobs recorded the country as countryCode or country, in any case
+	1: func(blob map[string]interface{}) error {
+		renameKey(blob, "countryCode", "country_code")
+		renameKey(blob, "country", "country_code")
+		if code, ok := blob["country_code"].(string); ok {
+			blob["country_code"] = strings.ToUpper(strings.TrimSpace(code))
+		}
+		return nil
+	},
+}
+
+// MigrateRunnerMetadata upgrades runner metadata to the current shape.
+// Current-version and empty blobs are returned unchanged.
+func MigrateRunnerMetadata(raw json.RawMessage) (json.RawMessage, error) {
+	migrated, err := migrateJSON(raw, RunnerMetadataSchemaVersion, runnerMetadataMigrations)
+	if err != nil {
+		return nil, fmt.Errorf("failed to migrate runner metadata: %w", err)
+	}
+	return migrated, nil
+}
+
+// MigrateRaceConditions upgrades race conditions to the current shape.
+// Current-version and empty blobs are returned unchanged.
+func MigrateRaceConditions(raw json.RawMessage) (json.RawMessage, error) {
+	migrated, err := migrateJSON(raw, RaceConditionsSchemaVersion, raceConditionsMigrations)
+	if err != nil {
+		return nil, fmt.Errorf("failed to migrate race conditions: %w", err)
+	}
+	return migrated, nil
+}
+
+// migrateJSON applies each migration from the blob's version up to current
+// and stamps the result with the current version
+func migrateJSON(raw json.RawMessage, current int, migrations map[int]jsonMigration) (json.RawMessage, error) {
+	blob, err := decodeBlob(raw)
+	if err != nil || blob == nil {
+		return raw, err
+	}
+
+	version, err := blobSchemaVersion(blob)
+	if err != nil {
+		return nil, err
+	}
+	if version == current {
+		return raw, nil
+	}
+	if version > current {
+		return nil, fmt.Errorf("schema version %d is newer than supported version %d", version, current)
+	}
+
+	for v := version; v < current; v++ {
+		migrate, ok := migrations[v]
+		if !ok {
+			return nil, fmt.Errorf("no migration from schema version %d", v)
+		}
+		if err := migrate(blob); err != nil {
+			return nil, fmt.Errorf("failed to migrate from schema version %d: %w", v, err)
+		}
+	}
+	blob[SchemaVersionKey] = current
+
+	encoded, err := json.Marshal(blob)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode migrated blob: %w", err)
+	}
+	return encoded, nil
+}
+
+// decodeBlob decodes a JSON object, keeping numbers exact so large
+// selection IDs survive a round trip. Empty and null blobs decode to nil.
+func decodeBlob(raw json.RawMessage) (map[string]interface{}, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, nil
+	}
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	var blob map[string]interface{}
+	if err := decoder.Decode(&blob); err != nil {
+		return nil, fmt.Errorf("failed to decode blob: %w", err)
+	}
+	return blob, nil
+}
+
+// blobSchemaVersion returns the blob's schema_version, or 1 when unset
+func blobSchemaVersion(blob map[string]interface{}) (int, error) {
+	value, ok := blob[SchemaVersionKey]
+	if !ok {
+		return 1, nil
+	}
+	number, ok := value.(json.Number)
+	if !ok {
+		return 0, fmt.Errorf("invalid schema_version %v", value)
+	}
+	version, err := strconv.Atoi(number.String())
+	if err != nil || version < 1 {
+		return 0, fmt.Errorf("invalid schema_version %v", value)
+	}
+	return version, nil
+}
+
+// renameKey moves a value to a new key unless the new key is already set
+func renameKey(blob map[string]interface{}, from, to string) {
+	value, ok := blob[from]
+	if !ok {
+		return
+	}
+	delete(blob, from)
+	if _, exists := blob[to]; !exists {
+		blob[to] = value
+	}
+}
@@ -0,0 +1,78 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrateRunnerMetadataUpgradesUnversionedBlob(t *testing.T) {
+	old := json.RawMessage(`{"marketId":"1.234567","selectionId":"18446744073709551615","breed":"greyhound"}`)
+
+	migrated, err := MigrateRunnerMetadata(old)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"market_id": "1.234567",
+		"selection_id": 18446744073709551615,
+		"breed": "greyhound",
+		"schema_version": 2
+	}`, string(migrated))
+
+	runner := &Runner{Metadata: old}
+	selection, ok := runner.BetfairSelection()
+	require.True(t, ok)
+	assert.Equal(t, BetfairSelection{MarketID: "1.234567", SelectionID: 18446744073709551615}, selection)
+
+	// Migrating an upgraded blob is a no-op
+	again, err := MigrateRunnerMetadata(migrated)
+	require.NoError(t, err)
+	assert.Equal(t, string(migrated), string(again))
+}
+
+func TestMigrateRaceConditionsUpgradesUnversionedBlob(t *testing.T) {
+	old := json.RawMessage(`{"country":"gb ","going":"good"}`)
+
+	migrated, err := MigrateRaceConditions(old)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"country_code":"GB","going":"good","schema_version":2}`, string(migrated))
+	assert.Equal(t, "GB", (&Race{Conditions: old}).CountryCode())
+}
+
+func TestMigrateCurrentVersionBlobsPassThrough(t *testing.T) {
+	metadata := json.RawMessage(`{"market_id":"1.1","selectionId":"kept as-is","schema_version":2}`)
+	migrated, err := MigrateRunnerMetadata(metadata)
+	require.NoError(t, err)
+	assert.Equal(t, string(metadata), string(migrated))
+
+	conditions := json.RawMessage(`{"country":"ie","schema_version":2}`)
+	migrated, err = MigrateRaceConditions(conditions)
+	require.NoError(t, err)
+	assert.Equal(t, string(conditions), string(migrated))
+
+	for _, empty := range []json.RawMessage{nil, json.RawMessage(`null`)} {
+		migrated, err = MigrateRunnerMetadata(empty)
+		require.NoError(t, err)
+		assert.Equal(t, string(empty), string(migrated))
+	}
+}
+
+func TestMigrateRejectsUnknownSchemaVersion(t *testing.T) {
+	_, err := MigrateRunnerMetadata(json.RawMessage(`{"schema_version":99}`))
+	assert.Error(t, err)
+
+	_, err = MigrateRaceConditions(json.RawMessage(`{"schema_version":"two"}`))
+	assert.Error(t, err)
+}
+
+func TestIngestionStampsSchemaVersion(t *testing.T) {
+	runner := &Runner{Metadata: json.RawMessage(`{"selectionId":"42","breed":"greyhound"}`)}
+	require.NoError(t, runner.SetBetfairSelection("1.99", 7))
+	assert.JSONEq(t, `{"market_id":"1.99","selection_id":7,"breed":"greyhound","schema_version":2}`, string(runner.Metadata))
+
+	race := &Race{}
+	require.NoError(t, race.SetConditions(map[string]interface{}{"country_code": "GB"}))
+	assert.JSONEq(t, `{"country_code":"GB","schema_version":2}`, string(race.Conditions))
+	assert.Equal(t, "GB", race.CountryCode())
+}
@@ -16,6 +16,12 @@ type Strategy struct {
 	Active      bool            `db:"active" json:"active"`
 	CreatedAt   time.Time       `db:"created_at" json:"created_at"`
 	UpdatedAt   time.Time       `db:"updated_at" json:"updated_at"`
+	ArchivedAt  *time.Time      `db:"archived_at" json:"archived_at,omitempty"`
+}
+
+// IsArchived returns whether the strategy has been archived
+func (s *Strategy) IsArchived() bool {
+	return s.ArchivedAt != nil
 }
 
 // GetParameter retrieves a parameter value from the Parameters JSON
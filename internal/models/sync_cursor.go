@@ -0,0 +1,11 @@
+package models
+
+import "time"
+
+// SyncCursor records the scheduled start of the latest race successfully
+// ingested from a data source, so historical sync can resume from it
+type SyncCursor struct {
+	Source       string    `db:"source" json:"source" validate:"required"`
+	LastRaceTime time.Time `db:"last_race_time" json:"last_race_time" validate:"required"`
+	UpdatedAt    time.Time `db:"updated_at" json:"updated_at"`
+}
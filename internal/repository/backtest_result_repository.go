@@ -29,15 +29,15 @@ func (r *PostgresBacktestResultRepository) SaveResult(ctx context.Context, resul
 			id, strategy_id, run_date, start_date, end_date,
 			initial_capital, final_capital, total_return, sharpe_ratio, max_drawdown,
 			total_bets, win_rate, profit_factor, method, composite_score, recommendation,
-			ml_features, full_results, created_at
-		) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,$15,$16,$17,$18,$19)
+			ml_features, full_results, tags, created_at
+		) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,$15,$16,$17,$18,$19,$20)
 	`
 
 	_, err := r.db.GetPool().Exec(ctx, query,
 		result.ID, result.StrategyID, result.RunDate, result.StartDate, result.EndDate,
 		result.InitialCapital, result.FinalCapital, result.TotalReturn, result.SharpeRatio, result.MaxDrawdown,
 		result.TotalBets, result.WinRate, result.ProfitFactor, result.Method, result.CompositeScore, result.Recommendation,
-		result.MLFeatures, result.FullResults, result.CreatedAt,
+		result.MLFeatures, result.FullResults, models.NormalizeTags(result.Tags), result.CreatedAt,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to save backtest result: %w", err)
@@ -50,8 +50,8 @@ func (r *PostgresBacktestResultRepository) GetByStrategyID(ctx context.Context,
 	query := `
 		SELECT id, strategy_id, run_date, start_date, end_date, initial_capital, final_capital,
 			total_return, sharpe_ratio, max_drawdown, total_bets, win_rate, profit_factor,
-			method, composite_score, recommendation, ml_features, full_results, created_at
-		FROM backtest_results WHERE strategy_id = $1 ORDER BY run_date DESC
+			method, composite_score, recommendation, ml_features, full_results, tags, created_at
+		FROM backtest_results WHERE strategy_id = $1 ORDER BY run_date DESC, id DESC
 	`
 	rows, err := r.db.GetPool().Query(ctx, query, strategyID)
 	if err != nil {
@@ -66,7 +66,7 @@ func (r *PostgresBacktestResultRepository) GetByStrategyID(ctx context.Context,
 			&result.ID, &result.StrategyID, &result.RunDate, &result.StartDate, &result.EndDate,
 			&result.InitialCapital, &result.FinalCapital, &result.TotalReturn, &result.SharpeRatio, &result.MaxDrawdown,
 			&result.TotalBets, &result.WinRate, &result.ProfitFactor, &result.Method, &result.CompositeScore, &result.Recommendation,
-			&result.MLFeatures, &result.FullResults, &result.CreatedAt,
+			&result.MLFeatures, &result.FullResults, &result.Tags, &result.CreatedAt,
 		); err != nil {
 			return nil, fmt.Errorf(errScanBacktestResult, err)
 		}
@@ -80,8 +80,8 @@ func (r *PostgresBacktestResultRepository) GetLatest(ctx context.Context, limit
 	query := `
 		SELECT id, strategy_id, run_date, start_date, end_date, initial_capital, final_capital,
 			total_return, sharpe_ratio, max_drawdown, total_bets, win_rate, profit_factor,
-			method, composite_score, recommendation, ml_features, full_results, created_at
-		FROM backtest_results ORDER BY run_date DESC LIMIT $1
+			method, composite_score, recommendation, ml_features, full_results, tags, created_at
+		FROM backtest_results ORDER BY run_date DESC, id DESC LIMIT $1
 	`
 	rows, err := r.db.GetPool().Query(ctx, query, limit)
 	if err != nil {
@@ -96,7 +96,7 @@ func (r *PostgresBacktestResultRepository) GetLatest(ctx context.Context, limit
 			&result.ID, &result.StrategyID, &result.RunDate, &result.StartDate, &result.EndDate,
 			&result.InitialCapital, &result.FinalCapital, &result.TotalReturn, &result.SharpeRatio, &result.MaxDrawdown,
 			&result.TotalBets, &result.WinRate, &result.ProfitFactor, &result.Method, &result.CompositeScore, &result.Recommendation,
-			&result.MLFeatures, &result.FullResults, &result.CreatedAt,
+			&result.MLFeatures, &result.FullResults, &result.Tags, &result.CreatedAt,
 		); err != nil {
 			return nil, fmt.Errorf(errScanBacktestResult, err)
 		}
@@ -110,8 +110,8 @@ func (r *PostgresBacktestResultRepository) GetByDateRange(ctx context.Context, s
 	query := `
 		SELECT id, strategy_id, run_date, start_date, end_date, initial_capital, final_capital,
 			total_return, sharpe_ratio, max_drawdown, total_bets, win_rate, profit_factor,
-			method, composite_score, recommendation, ml_features, full_results, created_at
-		FROM backtest_results WHERE run_date >= $1 AND run_date <= $2 ORDER BY run_date DESC
+			method, composite_score, recommendation, ml_features, full_results, tags, created_at
+		FROM backtest_results WHERE run_date >= $1 AND run_date <= $2 ORDER BY run_date DESC, id DESC
 	`
 	rows, err := r.db.GetPool().Query(ctx, query, start, end)
 	if err != nil {
@@ -126,7 +126,7 @@ func (r *PostgresBacktestResultRepository) GetByDateRange(ctx context.Context, s
 			&result.ID, &result.StrategyID, &result.RunDate, &result.StartDate, &result.EndDate,
 			&result.InitialCapital, &result.FinalCapital, &result.TotalReturn, &result.SharpeRatio, &result.MaxDrawdown,
 			&result.TotalBets, &result.WinRate, &result.ProfitFactor, &result.Method, &result.CompositeScore, &result.Recommendation,
-			&result.MLFeatures, &result.FullResults, &result.CreatedAt,
+			&result.MLFeatures, &result.FullResults, &result.Tags, &result.CreatedAt,
 		); err != nil {
 			return nil, fmt.Errorf(errScanBacktestResult, err)
 		}
@@ -135,14 +135,16 @@ func (r *PostgresBacktestResultRepository) GetByDateRange(ctx context.Context, s
 	return results, rows.Err()
 }
 
-// GetTopPerforming retrieves top N backtest results by composite score
+// GetTopPerforming retrieves top N backtest results by composite score.
+// Ties are broken by the newest run date, then by ID, so the order is stable
+// across calls.
 func (r *PostgresBacktestResultRepository) GetTopPerforming(ctx context.Context, limit int) ([]*models.BacktestResult, error) {
 	query := `
 		SELECT id, strategy_id, run_date, start_date, end_date, initial_capital, final_capital,
 			total_return, sharpe_ratio, max_drawdown, total_bets, win_rate, profit_factor,
-			method, composite_score, recommendation, ml_features, full_results, created_at
+			method, composite_score, recommendation, ml_features, full_results, tags, created_at
 		FROM backtest_results 
-		ORDER BY composite_score DESC, run_date DESC 
+		ORDER BY composite_score DESC, run_date DESC, id DESC
 		LIMIT $1
 	`
 	rows, err := r.db.GetPool().Query(ctx, query, limit)
@@ -158,7 +160,7 @@ func (r *PostgresBacktestResultRepository) GetTopPerforming(ctx context.Context,
 			&result.ID, &result.StrategyID, &result.RunDate, &result.StartDate, &result.EndDate,
 			&result.InitialCapital, &result.FinalCapital, &result.TotalReturn, &result.SharpeRatio, &result.MaxDrawdown,
 			&result.TotalBets, &result.WinRate, &result.ProfitFactor, &result.Method, &result.CompositeScore, &result.Recommendation,
-			&result.MLFeatures, &result.FullResults, &result.CreatedAt,
+			&result.MLFeatures, &result.FullResults, &result.Tags, &result.CreatedAt,
 		); err != nil {
 			return nil, fmt.Errorf(errScanBacktestResult, err)
 		}
@@ -172,10 +174,10 @@ func (r *PostgresBacktestResultRepository) GetRecentUnprocessed(ctx context.Cont
 	query := `
 		SELECT id, strategy_id, run_date, start_date, end_date, initial_capital, final_capital,
 			total_return, sharpe_ratio, max_drawdown, total_bets, win_rate, profit_factor,
-			method, composite_score, recommendation, ml_features, full_results, created_at
+			method, composite_score, recommendation, ml_features, full_results, tags, created_at
 		FROM backtest_results 
 		WHERE ml_feedback_submitted = FALSE OR ml_feedback_submitted IS NULL
-		ORDER BY run_date DESC 
+		ORDER BY run_date DESC, id DESC
 		LIMIT $1
 	`
 	rows, err := r.db.GetPool().Query(ctx, query, limit)
@@ -191,7 +193,41 @@ func (r *PostgresBacktestResultRepository) GetRecentUnprocessed(ctx context.Cont
 			&result.ID, &result.StrategyID, &result.RunDate, &result.StartDate, &result.EndDate,
 			&result.InitialCapital, &result.FinalCapital, &result.TotalReturn, &result.SharpeRatio, &result.MaxDrawdown,
 			&result.TotalBets, &result.WinRate, &result.ProfitFactor, &result.Method, &result.CompositeScore, &result.Recommendation,
-			&result.MLFeatures, &result.FullResults, &result.CreatedAt,
+			&result.MLFeatures, &result.FullResults, &result.Tags, &result.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf(errScanBacktestResult, err)
+		}
+		results = append(results, result)
+	}
+	return results, rows.Err()
+}
+
+// GetUnprocessedSince retrieves unprocessed backtest results created at or
+// after since, oldest first, so callers can advance a high-water mark
+func (r *PostgresBacktestResultRepository) GetUnprocessedSince(ctx context.Context, since time.Time, limit int) ([]*models.BacktestResult, error) {
+	query := `
+		SELECT id, strategy_id, run_date, start_date, end_date, initial_capital, final_capital,
+			total_return, sharpe_ratio, max_drawdown, total_bets, win_rate, profit_factor,
+			method, composite_score, recommendation, ml_features, full_results, tags, created_at
+		FROM backtest_results
+		WHERE ml_feedback_submitted = FALSE AND created_at >= $1
+		ORDER BY created_at ASC, id ASC
+		LIMIT $2
+	`
+	rows, err := r.db.GetPool().Query(ctx, query, since, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query unprocessed backtest results: %w", err)
+	}
+	defer rows.Close()
+
+	var results []*models.BacktestResult
+	for rows.Next() {
+		result := &models.BacktestResult{}
+		if err := rows.Scan(
+			&result.ID, &result.StrategyID, &result.RunDate, &result.StartDate, &result.EndDate,
+			&result.InitialCapital, &result.FinalCapital, &result.TotalReturn, &result.SharpeRatio, &result.MaxDrawdown,
+			&result.TotalBets, &result.WinRate, &result.ProfitFactor, &result.Method, &result.CompositeScore, &result.Recommendation,
+			&result.MLFeatures, &result.FullResults, &result.Tags, &result.CreatedAt,
 		); err != nil {
 			return nil, fmt.Errorf(errScanBacktestResult, err)
 		}
@@ -204,7 +240,7 @@ func (r *PostgresBacktestResultRepository) GetRecentUnprocessed(ctx context.Cont
 func (r *PostgresBacktestResultRepository) MarkAsProcessed(ctx context.Context, resultID uuid.UUID) error {
 	query := `
 		UPDATE backtest_results 
-		SET ml_feedback_submitted = TRUE 
+		SET ml_feedback_submitted = TRUE, ml_feedback_submitted_at = NOW()
 		WHERE id = $1
 	`
 	_, err := r.db.GetPool().Exec(ctx, query, resultID)
@@ -214,15 +250,34 @@ func (r *PostgresBacktestResultRepository) MarkAsProcessed(ctx context.Context,
 	return nil
 }
 
-// GetByCompositeScoreRange retrieves backtest results within a score range
+// UpdateCompositeScore replaces a backtest result's composite score and
+// recommendation, e.g. after the scoring weights change
+func (r *PostgresBacktestResultRepository) UpdateCompositeScore(ctx context.Context, resultID uuid.UUID, score float64, recommendation string) error {
+	query := `
+		UPDATE backtest_results
+		SET composite_score = $2, recommendation = $3
+		WHERE id = $1
+	`
+	commandTag, err := r.db.GetPool().Exec(ctx, query, resultID, score, recommendation)
+	if err != nil {
+		return fmt.Errorf("failed to update backtest result composite score: %w", err)
+	}
+	if commandTag.RowsAffected() == 0 {
+		return models.ErrNotFound
+	}
+	return nil
+}
+
+// GetByCompositeScoreRange retrieves backtest results within a score range,
+// ordered as GetTopPerforming
 func (r *PostgresBacktestResultRepository) GetByCompositeScoreRange(ctx context.Context, minScore, maxScore float64, limit int) ([]*models.BacktestResult, error) {
 	query := `
 		SELECT id, strategy_id, run_date, start_date, end_date, initial_capital, final_capital,
 			total_return, sharpe_ratio, max_drawdown, total_bets, win_rate, profit_factor,
-			method, composite_score, recommendation, ml_features, full_results, created_at
+			method, composite_score, recommendation, ml_features, full_results, tags, created_at
 		FROM backtest_results 
 		WHERE composite_score >= $1 AND composite_score <= $2
-		ORDER BY composite_score DESC, run_date DESC 
+		ORDER BY composite_score DESC, run_date DESC, id DESC
 		LIMIT $3
 	`
 	rows, err := r.db.GetPool().Query(ctx, query, minScore, maxScore, limit)
@@ -238,7 +293,7 @@ func (r *PostgresBacktestResultRepository) GetByCompositeScoreRange(ctx context.
 			&result.ID, &result.StrategyID, &result.RunDate, &result.StartDate, &result.EndDate,
 			&result.InitialCapital, &result.FinalCapital, &result.TotalReturn, &result.SharpeRatio, &result.MaxDrawdown,
 			&result.TotalBets, &result.WinRate, &result.ProfitFactor, &result.Method, &result.CompositeScore, &result.Recommendation,
-			&result.MLFeatures, &result.FullResults, &result.CreatedAt,
+			&result.MLFeatures, &result.FullResults, &result.Tags, &result.CreatedAt,
 		); err != nil {
 			return nil, fmt.Errorf(errScanBacktestResult, err)
 		}
@@ -247,3 +302,44 @@ func (r *PostgresBacktestResultRepository) GetByCompositeScoreRange(ctx context.
 	return results, rows.Err()
 }
 
+// GetByTag retrieves backtest results carrying the given tags, newest first.
+// TagMatchAll requires every tag; TagMatchAny requires at least one.
+func (r *PostgresBacktestResultRepository) GetByTag(ctx context.Context, tags []string, match TagMatch) ([]*models.BacktestResult, error) {
+	tags = models.NormalizeTags(tags)
+	if len(tags) == 0 {
+		return nil, nil
+	}
+
+	operator := "&&"
+	if match == TagMatchAll {
+		operator = "@>"
+	}
+	query := `
+		SELECT id, strategy_id, run_date, start_date, end_date, initial_capital, final_capital,
+			total_return, sharpe_ratio, max_drawdown, total_bets, win_rate, profit_factor,
+			method, composite_score, recommendation, ml_features, full_results, tags, created_at
+		FROM backtest_results
+		WHERE tags ` + operator + ` $1
+		ORDER BY run_date DESC, id DESC
+	`
+	rows, err := r.db.GetPool().Query(ctx, query, tags)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query backtest results by tag: %w", err)
+	}
+	defer rows.Close()
+
+	var results []*models.BacktestResult
+	for rows.Next() {
+		result := &models.BacktestResult{}
+		if err := rows.Scan(
+			&result.ID, &result.StrategyID, &result.RunDate, &result.StartDate, &result.EndDate,
+			&result.InitialCapital, &result.FinalCapital, &result.TotalReturn, &result.SharpeRatio, &result.MaxDrawdown,
+			&result.TotalBets, &result.WinRate, &result.ProfitFactor, &result.Method, &result.CompositeScore, &result.Recommendation,
+			&result.MLFeatures, &result.FullResults, &result.Tags, &result.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf(errScanBacktestResult, err)
+		}
+		results = append(results, result)
+	}
+	return results, rows.Err()
+}
@@ -27,13 +27,17 @@ func NewPostgresBetRepository(db *database.DB) BetRepository {
 func (b *PostgresBetRepository) Create(ctx context.Context, bet *models.Bet) error {
 	query := `
 		INSERT INTO bets (id, bet_id, market_id, race_id, runner_id, strategy_id, market_type, side, 
-		                  odds, stake, matched_price, matched_size, status, placed_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+		                  odds, stake, is_bsp, account, reasoning, expected_value, confidence, model_probability,
+		                  each_way, place_fraction, places_paid,
+		                  matched_price, matched_size, status, placed_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23)
 	`
 
 	_, err := b.db.GetPool().Exec(ctx, query,
 		bet.ID, bet.BetID, bet.MarketID, bet.RaceID, bet.RunnerID, bet.StrategyID, bet.MarketType,
-		bet.Side, bet.Odds, bet.Stake, bet.MatchedPrice, bet.MatchedSize, bet.Status, bet.PlacedAt,
+		bet.Side, bet.Odds, bet.Stake, bet.IsBSP, bet.Account, bet.Reasoning, bet.ExpectedValue, bet.Confidence, bet.ModelProbability,
+		bet.EachWay, bet.PlaceFraction, bet.PlacesPaid,
+		bet.MatchedPrice, bet.MatchedSize, bet.Status, bet.PlacedAt,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to create bet: %w", err)
@@ -45,17 +49,20 @@ func (b *PostgresBetRepository) Create(ctx context.Context, bet *models.Bet) err
 // GetByID retrieves a bet by ID
 func (b *PostgresBetRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Bet, error) {
 	query := `
-		SELECT id, bet_id, market_id, race_id, runner_id, strategy_id, market_type, side, odds, stake,
+		SELECT id, bet_id, market_id, race_id, runner_id, strategy_id, market_type, side, odds, stake, is_bsp, account,
+		       reasoning, expected_value, confidence, model_probability, each_way, place_fraction, places_paid,
 		       matched_price, matched_size, status, placed_at, matched_at, settled_at, cancelled_at,
-		       profit_loss, commission, created_at, updated_at
+		       profit_loss, commission, win_profit_loss, place_profit_loss, created_at, updated_at
 		FROM bets WHERE id = $1
 	`
 
 	bet := &models.Bet{}
 	err := b.db.GetPool().QueryRow(ctx, query, id).Scan(
 		&bet.ID, &bet.BetID, &bet.MarketID, &bet.RaceID, &bet.RunnerID, &bet.StrategyID, &bet.MarketType,
-		&bet.Side, &bet.Odds, &bet.Stake, &bet.MatchedPrice, &bet.MatchedSize, &bet.Status, &bet.PlacedAt,
-		&bet.MatchedAt, &bet.SettledAt, &bet.CancelledAt, &bet.ProfitLoss, &bet.Commission, &bet.CreatedAt, &bet.UpdatedAt,
+		&bet.Side, &bet.Odds, &bet.Stake, &bet.IsBSP, &bet.Account, &bet.Reasoning, &bet.ExpectedValue, &bet.Confidence, &bet.ModelProbability,
+		&bet.EachWay, &bet.PlaceFraction, &bet.PlacesPaid,
+		&bet.MatchedPrice, &bet.MatchedSize, &bet.Status, &bet.PlacedAt,
+		&bet.MatchedAt, &bet.SettledAt, &bet.CancelledAt, &bet.ProfitLoss, &bet.Commission, &bet.WinProfitLoss, &bet.PlaceProfitLoss, &bet.CreatedAt, &bet.UpdatedAt,
 	)
 	if err == pgx.ErrNoRows {
 		return nil, models.ErrNotFound
@@ -70,9 +77,10 @@ func (b *PostgresBetRepository) GetByID(ctx context.Context, id uuid.UUID) (*mod
 // GetByRaceID retrieves all bets for a specific race
 func (b *PostgresBetRepository) GetByRaceID(ctx context.Context, raceID uuid.UUID) ([]*models.Bet, error) {
 	query := `
-		SELECT id, bet_id, market_id, race_id, runner_id, strategy_id, market_type, side, odds, stake,
+		SELECT id, bet_id, market_id, race_id, runner_id, strategy_id, market_type, side, odds, stake, is_bsp, account,
+		       reasoning, expected_value, confidence, model_probability, each_way, place_fraction, places_paid,
 		       matched_price, matched_size, status, placed_at, matched_at, settled_at, cancelled_at,
-		       profit_loss, commission, created_at, updated_at
+		       profit_loss, commission, win_profit_loss, place_profit_loss, created_at, updated_at
 		FROM bets
 		WHERE race_id = $1
 		ORDER BY placed_at DESC
@@ -89,8 +97,10 @@ func (b *PostgresBetRepository) GetByRaceID(ctx context.Context, raceID uuid.UUI
 		bet := &models.Bet{}
 		err := rows.Scan(
 			&bet.ID, &bet.BetID, &bet.MarketID, &bet.RaceID, &bet.RunnerID, &bet.StrategyID, &bet.MarketType,
-			&bet.Side, &bet.Odds, &bet.Stake, &bet.MatchedPrice, &bet.MatchedSize, &bet.Status, &bet.PlacedAt,
-			&bet.MatchedAt, &bet.SettledAt, &bet.CancelledAt, &bet.ProfitLoss, &bet.Commission, &bet.CreatedAt, &bet.UpdatedAt,
+			&bet.Side, &bet.Odds, &bet.Stake, &bet.IsBSP, &bet.Account, &bet.Reasoning, &bet.ExpectedValue, &bet.Confidence, &bet.ModelProbability,
+			&bet.EachWay, &bet.PlaceFraction, &bet.PlacesPaid,
+			&bet.MatchedPrice, &bet.MatchedSize, &bet.Status, &bet.PlacedAt,
+			&bet.MatchedAt, &bet.SettledAt, &bet.CancelledAt, &bet.ProfitLoss, &bet.Commission, &bet.WinProfitLoss, &bet.PlaceProfitLoss, &bet.CreatedAt, &bet.UpdatedAt,
 		)
 		if err != nil {
 			return nil, fmt.Errorf(errScanBet, err)
@@ -104,9 +114,10 @@ func (b *PostgresBetRepository) GetByRaceID(ctx context.Context, raceID uuid.UUI
 // GetByStrategyID retrieves all bets for a specific strategy within a date range
 func (b *PostgresBetRepository) GetByStrategyID(ctx context.Context, strategyID uuid.UUID, start, end time.Time) ([]*models.Bet, error) {
 	query := `
-		SELECT id, bet_id, market_id, race_id, runner_id, strategy_id, market_type, side, odds, stake,
+		SELECT id, bet_id, market_id, race_id, runner_id, strategy_id, market_type, side, odds, stake, is_bsp, account,
+		       reasoning, expected_value, confidence, model_probability, each_way, place_fraction, places_paid,
 		       matched_price, matched_size, status, placed_at, matched_at, settled_at, cancelled_at,
-		       profit_loss, commission, created_at, updated_at
+		       profit_loss, commission, win_profit_loss, place_profit_loss, created_at, updated_at
 		FROM bets
 		WHERE strategy_id = $1 AND placed_at >= $2 AND placed_at <= $3
 		ORDER BY placed_at DESC
@@ -123,8 +134,10 @@ func (b *PostgresBetRepository) GetByStrategyID(ctx context.Context, strategyID
 		bet := &models.Bet{}
 		err := rows.Scan(
 			&bet.ID, &bet.BetID, &bet.MarketID, &bet.RaceID, &bet.RunnerID, &bet.StrategyID, &bet.MarketType,
-			&bet.Side, &bet.Odds, &bet.Stake, &bet.MatchedPrice, &bet.MatchedSize, &bet.Status, &bet.PlacedAt,
-			&bet.MatchedAt, &bet.SettledAt, &bet.CancelledAt, &bet.ProfitLoss, &bet.Commission, &bet.CreatedAt, &bet.UpdatedAt,
+			&bet.Side, &bet.Odds, &bet.Stake, &bet.IsBSP, &bet.Account, &bet.Reasoning, &bet.ExpectedValue, &bet.Confidence, &bet.ModelProbability,
+			&bet.EachWay, &bet.PlaceFraction, &bet.PlacesPaid,
+			&bet.MatchedPrice, &bet.MatchedSize, &bet.Status, &bet.PlacedAt,
+			&bet.MatchedAt, &bet.SettledAt, &bet.CancelledAt, &bet.ProfitLoss, &bet.Commission, &bet.WinProfitLoss, &bet.PlaceProfitLoss, &bet.CreatedAt, &bet.UpdatedAt,
 		)
 		if err != nil {
 			return nil, fmt.Errorf(errScanBet, err)
@@ -141,13 +154,15 @@ func (b *PostgresBetRepository) Update(ctx context.Context, bet *models.Bet) err
 		UPDATE bets SET
 			bet_id = $2, market_id = $3, matched_price = $4, matched_size = $5,
 			status = $6, matched_at = $7, settled_at = $8, cancelled_at = $9,
-			profit_loss = $10, commission = $11, updated_at = NOW()
+			profit_loss = $10, commission = $11, win_profit_loss = $12, place_profit_loss = $13,
+			updated_at = NOW()
 		WHERE id = $1
 	`
 
 	commandTag, err := b.db.GetPool().Exec(ctx, query,
 		bet.ID, bet.BetID, bet.MarketID, bet.MatchedPrice, bet.MatchedSize,
 		bet.Status, bet.MatchedAt, bet.SettledAt, bet.CancelledAt, bet.ProfitLoss, bet.Commission,
+		bet.WinProfitLoss, bet.PlaceProfitLoss,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to update bet: %w", err)
@@ -163,9 +178,10 @@ func (b *PostgresBetRepository) Update(ctx context.Context, bet *models.Bet) err
 // GetPendingBets retrieves all pending bets
 func (b *PostgresBetRepository) GetPendingBets(ctx context.Context) ([]*models.Bet, error) {
 	query := `
-		SELECT id, bet_id, market_id, race_id, runner_id, strategy_id, market_type, side, odds, stake,
+		SELECT id, bet_id, market_id, race_id, runner_id, strategy_id, market_type, side, odds, stake, is_bsp, account,
+		       reasoning, expected_value, confidence, model_probability, each_way, place_fraction, places_paid,
 		       matched_price, matched_size, status, placed_at, matched_at, settled_at, cancelled_at,
-		       profit_loss, commission, created_at, updated_at
+		       profit_loss, commission, win_profit_loss, place_profit_loss, created_at, updated_at
 		FROM bets
 		WHERE status = 'pending'
 		ORDER BY placed_at ASC
@@ -182,8 +198,10 @@ func (b *PostgresBetRepository) GetPendingBets(ctx context.Context) ([]*models.B
 		bet := &models.Bet{}
 		err := rows.Scan(
 			&bet.ID, &bet.BetID, &bet.MarketID, &bet.RaceID, &bet.RunnerID, &bet.StrategyID, &bet.MarketType,
-			&bet.Side, &bet.Odds, &bet.Stake, &bet.MatchedPrice, &bet.MatchedSize, &bet.Status, &bet.PlacedAt,
-			&bet.MatchedAt, &bet.SettledAt, &bet.CancelledAt, &bet.ProfitLoss, &bet.Commission, &bet.CreatedAt, &bet.UpdatedAt,
+			&bet.Side, &bet.Odds, &bet.Stake, &bet.IsBSP, &bet.Account, &bet.Reasoning, &bet.ExpectedValue, &bet.Confidence, &bet.ModelProbability,
+			&bet.EachWay, &bet.PlaceFraction, &bet.PlacesPaid,
+			&bet.MatchedPrice, &bet.MatchedSize, &bet.Status, &bet.PlacedAt,
+			&bet.MatchedAt, &bet.SettledAt, &bet.CancelledAt, &bet.ProfitLoss, &bet.Commission, &bet.WinProfitLoss, &bet.PlaceProfitLoss, &bet.CreatedAt, &bet.UpdatedAt,
 		)
 		if err != nil {
 			return nil, fmt.Errorf(errScanBet, err)
@@ -197,9 +215,10 @@ func (b *PostgresBetRepository) GetPendingBets(ctx context.Context) ([]*models.B
 // GetSettledBets retrieves all settled bets within a date range
 func (b *PostgresBetRepository) GetSettledBets(ctx context.Context, start, end time.Time) ([]*models.Bet, error) {
 	query := `
-		SELECT id, bet_id, market_id, race_id, runner_id, strategy_id, market_type, side, odds, stake,
+		SELECT id, bet_id, market_id, race_id, runner_id, strategy_id, market_type, side, odds, stake, is_bsp, account,
+		       reasoning, expected_value, confidence, model_probability, each_way, place_fraction, places_paid,
 		       matched_price, matched_size, status, placed_at, matched_at, settled_at, cancelled_at,
-		       profit_loss, commission, created_at, updated_at
+		       profit_loss, commission, win_profit_loss, place_profit_loss, created_at, updated_at
 		FROM bets
 		WHERE status = 'settled' AND settled_at >= $1 AND settled_at <= $2
 		ORDER BY settled_at DESC
@@ -216,8 +235,10 @@ func (b *PostgresBetRepository) GetSettledBets(ctx context.Context, start, end t
 		bet := &models.Bet{}
 		err := rows.Scan(
 			&bet.ID, &bet.BetID, &bet.MarketID, &bet.RaceID, &bet.RunnerID, &bet.StrategyID, &bet.MarketType,
-			&bet.Side, &bet.Odds, &bet.Stake, &bet.MatchedPrice, &bet.MatchedSize, &bet.Status, &bet.PlacedAt,
-			&bet.MatchedAt, &bet.SettledAt, &bet.CancelledAt, &bet.ProfitLoss, &bet.Commission, &bet.CreatedAt, &bet.UpdatedAt,
+			&bet.Side, &bet.Odds, &bet.Stake, &bet.IsBSP, &bet.Account, &bet.Reasoning, &bet.ExpectedValue, &bet.Confidence, &bet.ModelProbability,
+			&bet.EachWay, &bet.PlaceFraction, &bet.PlacesPaid,
+			&bet.MatchedPrice, &bet.MatchedSize, &bet.Status, &bet.PlacedAt,
+			&bet.MatchedAt, &bet.SettledAt, &bet.CancelledAt, &bet.ProfitLoss, &bet.Commission, &bet.WinProfitLoss, &bet.PlaceProfitLoss, &bet.CreatedAt, &bet.UpdatedAt,
 		)
 		if err != nil {
 			return nil, fmt.Errorf(errScanBet, err)
@@ -228,20 +249,39 @@ func (b *PostgresBetRepository) GetSettledBets(ctx context.Context, start, end t
 	return bets, rows.Err()
 }
 
+// CountPlacedBetween counts bets placed within a time range, excluding cancelled bets
+func (b *PostgresBetRepository) CountPlacedBetween(ctx context.Context, start, end time.Time) (int, error) {
+	query := `
+		SELECT COUNT(*)
+		FROM bets
+		WHERE placed_at >= $1 AND placed_at < $2 AND status != 'cancelled'
+	`
+
+	var count int
+	if err := b.db.GetPool().QueryRow(ctx, query, start, end).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count placed bets: %w", err)
+	}
+
+	return count, nil
+}
+
 // GetByBetfairBetID retrieves a bet by Betfair bet ID
 func (b *PostgresBetRepository) GetByBetfairBetID(ctx context.Context, betID string) (*models.Bet, error) {
 	query := `
-		SELECT id, bet_id, market_id, race_id, runner_id, strategy_id, market_type, side, odds, stake,
+		SELECT id, bet_id, market_id, race_id, runner_id, strategy_id, market_type, side, odds, stake, is_bsp, account,
+		       reasoning, expected_value, confidence, model_probability, each_way, place_fraction, places_paid,
 		       matched_price, matched_size, status, placed_at, matched_at, settled_at, cancelled_at,
-		       profit_loss, commission, created_at, updated_at
+		       profit_loss, commission, win_profit_loss, place_profit_loss, created_at, updated_at
 		FROM bets WHERE bet_id = $1
 	`
 
 	bet := &models.Bet{}
 	err := b.db.GetPool().QueryRow(ctx, query, betID).Scan(
 		&bet.ID, &bet.BetID, &bet.MarketID, &bet.RaceID, &bet.RunnerID, &bet.StrategyID, &bet.MarketType,
-		&bet.Side, &bet.Odds, &bet.Stake, &bet.MatchedPrice, &bet.MatchedSize, &bet.Status, &bet.PlacedAt,
-		&bet.MatchedAt, &bet.SettledAt, &bet.CancelledAt, &bet.ProfitLoss, &bet.Commission, &bet.CreatedAt, &bet.UpdatedAt,
+		&bet.Side, &bet.Odds, &bet.Stake, &bet.IsBSP, &bet.Account, &bet.Reasoning, &bet.ExpectedValue, &bet.Confidence, &bet.ModelProbability,
+		&bet.EachWay, &bet.PlaceFraction, &bet.PlacesPaid,
+		&bet.MatchedPrice, &bet.MatchedSize, &bet.Status, &bet.PlacedAt,
+		&bet.MatchedAt, &bet.SettledAt, &bet.CancelledAt, &bet.ProfitLoss, &bet.Commission, &bet.WinProfitLoss, &bet.PlaceProfitLoss, &bet.CreatedAt, &bet.UpdatedAt,
 	)
 	if err == pgx.ErrNoRows {
 		return nil, models.ErrNotFound
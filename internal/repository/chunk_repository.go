@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/yourusername/clever-better/internal/database"
+	"github.com/yourusername/clever-better/internal/models"
+)
+
+// PostgresChunkRepository implements ChunkRepository using TimescaleDB
+type PostgresChunkRepository struct {
+	db *database.DB
+}
+
+// NewPostgresChunkRepository creates a new chunk repository
+func NewPostgresChunkRepository(db *database.DB) ChunkRepository {
+	return &PostgresChunkRepository{db: db}
+}
+
+// GetChunks returns the chunks of a hypertable whose time range ends at or
+// before endBefore, with their current on-disk size
+func (r *PostgresChunkRepository) GetChunks(ctx context.Context, hypertable string, endBefore time.Time) ([]*models.Chunk, error) {
+	query := `
+		SELECT c.chunk_schema || '.' || c.chunk_name, c.range_start, c.range_end, c.is_compressed, COALESCE(s.total_bytes, 0)
+		FROM timescaledb_information.chunks c
+		LEFT JOIN chunks_detailed_size($1::text::regclass) s
+			ON s.chunk_schema = c.chunk_schema AND s.chunk_name = c.chunk_name
+		WHERE c.hypertable_name = $1::text AND c.range_end <= $2
+		ORDER BY c.range_start ASC
+	`
+
+	rows, err := r.db.GetPool().Query(ctx, query, hypertable, endBefore)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query chunks: %w", err)
+	}
+	defer rows.Close()
+
+	var chunks []*models.Chunk
+	for rows.Next() {
+		chunk := &models.Chunk{}
+		if err := rows.Scan(&chunk.Name, &chunk.RangeStart, &chunk.RangeEnd, &chunk.Compressed, &chunk.SizeBytes); err != nil {
+			return nil, fmt.Errorf("failed to scan chunk: %w", err)
+		}
+		chunks = append(chunks, chunk)
+	}
+
+	return chunks, rows.Err()
+}
+
+// CompressChunk compresses a chunk of a hypertable and returns its compressed
+// size. Already compressed chunks are left as they are.
+func (r *PostgresChunkRepository) CompressChunk(ctx context.Context, hypertable, chunk string) (int64, error) {
+	if _, err := r.db.GetPool().Exec(ctx, `SELECT compress_chunk($1::text::regclass, if_not_compressed => TRUE)`, chunk); err != nil {
+		return 0, fmt.Errorf("failed to compress chunk %s: %w", chunk, err)
+	}
+
+	query := `
+		SELECT COALESCE(after_compression_total_bytes, 0)
+		FROM chunk_compression_stats($1::text::regclass)
+		WHERE chunk_schema || '.' || chunk_name = $2
+	`
+
+	var size int64
+	if err := r.db.GetPool().QueryRow(ctx, query, hypertable, chunk).Scan(&size); err != nil {
+		return 0, fmt.Errorf("failed to get compressed size of chunk %s: %w", chunk, err)
+	}
+
+	return size, nil
+}
+
+// DropChunks drops the chunks of a hypertable whose time range ends at or
+// before olderThan and returns their names
+func (r *PostgresChunkRepository) DropChunks(ctx context.Context, hypertable string, olderThan time.Time) ([]string, error) {
+	rows, err := r.db.GetPool().Query(ctx, `SELECT drop_chunks($1::text::regclass, older_than => $2::timestamptz)`, hypertable, olderThan)
+	if err != nil {
+		return nil, fmt.Errorf("failed to drop chunks: %w", err)
+	}
+	defer rows.Close()
+
+	var dropped []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("failed to scan dropped chunk: %w", err)
+		}
+		dropped = append(dropped, name)
+	}
+
+	return dropped, rows.Err()
+}
@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/clever-better/internal/database"
+	"github.com/yourusername/clever-better/internal/models"
+)
+
+// PostgresFeedbackDeadLetterRepository implements FeedbackDeadLetterRepository for PostgreSQL
+type PostgresFeedbackDeadLetterRepository struct {
+	db *database.DB
+}
+
+// NewPostgresFeedbackDeadLetterRepository creates a new feedback dead-letter repository
+func NewPostgresFeedbackDeadLetterRepository(db *database.DB) FeedbackDeadLetterRepository {
+	return &PostgresFeedbackDeadLetterRepository{db: db}
+}
+
+// Save stores a failed submission. A result already in the store keeps its
+// row; the payload and error are replaced and the attempt count incremented.
+func (r *PostgresFeedbackDeadLetterRepository) Save(ctx context.Context, letter *models.FeedbackDeadLetter) error {
+	if letter.ID == uuid.Nil {
+		letter.ID = uuid.New()
+	}
+
+	query := `
+		INSERT INTO ml_feedback_dead_letters
+			(id, backtest_result_id, payload, last_error, attempts, next_attempt_at, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, 1, $5, NOW(), NOW())
+		ON CONFLICT (backtest_result_id) DO UPDATE SET
+			payload = EXCLUDED.payload,
+			last_error = EXCLUDED.last_error,
+			attempts = ml_feedback_dead_letters.attempts + 1,
+			next_attempt_at = EXCLUDED.next_attempt_at,
+			updated_at = NOW()
+		RETURNING id, attempts, created_at, updated_at
+	`
+
+	err := conn(ctx, r.db).QueryRow(ctx, query,
+		letter.ID, letter.BacktestResultID, letter.Payload, letter.LastError, letter.NextAttemptAt,
+	).Scan(&letter.ID, &letter.Attempts, &letter.CreatedAt, &letter.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save feedback dead letter: %w", err)
+	}
+
+	return nil
+}
+
+// GetDue returns up to limit dead letters whose next attempt is at or before now, oldest first
+func (r *PostgresFeedbackDeadLetterRepository) GetDue(ctx context.Context, now time.Time, limit int) ([]*models.FeedbackDeadLetter, error) {
+	query := `
+		SELECT id, backtest_result_id, payload, last_error, attempts, next_attempt_at, created_at, updated_at
+		FROM ml_feedback_dead_letters
+		WHERE next_attempt_at <= $1
+		ORDER BY next_attempt_at ASC, id ASC
+		LIMIT $2
+	`
+
+	rows, err := r.db.GetPool().Query(ctx, query, now, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query feedback dead letters: %w", err)
+	}
+	defer rows.Close()
+
+	var letters []*models.FeedbackDeadLetter
+	for rows.Next() {
+		letter := &models.FeedbackDeadLetter{}
+		if err := rows.Scan(
+			&letter.ID, &letter.BacktestResultID, &letter.Payload, &letter.LastError,
+			&letter.Attempts, &letter.NextAttemptAt, &letter.CreatedAt, &letter.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan feedback dead letter: %w", err)
+		}
+		letters = append(letters, letter)
+	}
+
+	return letters, rows.Err()
+}
+
+// RecordFailure records another failed delivery attempt and when to try next
+func (r *PostgresFeedbackDeadLetterRepository) RecordFailure(ctx context.Context, id uuid.UUID, lastError string, nextAttemptAt time.Time) error {
+	query := `
+		UPDATE ml_feedback_dead_letters
+		SET attempts = attempts + 1, last_error = $2, next_attempt_at = $3, updated_at = NOW()
+		WHERE id = $1
+	`
+
+	if _, err := conn(ctx, r.db).Exec(ctx, query, id, lastError, nextAttemptAt); err != nil {
+		return fmt.Errorf("failed to record feedback dead letter failure: %w", err)
+	}
+
+	return nil
+}
+
+// Delete removes a dead letter once it has been delivered
+func (r *PostgresFeedbackDeadLetterRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	query := `DELETE FROM ml_feedback_dead_letters WHERE id = $1`
+
+	if _, err := conn(ctx, r.db).Exec(ctx, query, id); err != nil {
+		return fmt.Errorf("failed to delete feedback dead letter: %w", err)
+	}
+
+	return nil
+}
+
+// Count returns the number of dead letters awaiting redelivery
+func (r *PostgresFeedbackDeadLetterRepository) Count(ctx context.Context) (int, error) {
+	var count int
+	if err := conn(ctx, r.db).QueryRow(ctx, `SELECT COUNT(*) FROM ml_feedback_dead_letters`).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count feedback dead letters: %w", err)
+	}
+
+	return count, nil
+}
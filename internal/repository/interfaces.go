@@ -14,7 +14,14 @@ type RaceRepository interface {
 	GetByID(ctx context.Context, id uuid.UUID) (*models.Race, error)
 	GetUpcoming(ctx context.Context, limit int) ([]*models.Race, error)
 	GetByDateRange(ctx context.Context, start, end time.Time) ([]*models.Race, error)
+	StreamByDateRange(ctx context.Context, start, end time.Time, fn func(*models.Race) error) error
 	GetByTrackAndDate(ctx context.Context, track string, date time.Time) ([]*models.Race, error)
+	// GetRaceBundle loads a race with its runners and its odds from oddsSince
+	// to the off in one query
+	GetRaceBundle(ctx context.Context, raceID uuid.UUID, oddsSince time.Time) (*models.RaceBundle, error)
+	// GetRaceBundles loads several races' bundles in one query, in scheduled
+	// start order. Unknown races are left out.
+	GetRaceBundles(ctx context.Context, raceIDs []uuid.UUID, oddsSince time.Time) ([]*models.RaceBundle, error)
 	Update(ctx context.Context, race *models.Race) error
 	Delete(ctx context.Context, id uuid.UUID) error
 }
@@ -24,6 +31,8 @@ type RunnerRepository interface {
 	Create(ctx context.Context, runner *models.Runner) error
 	GetByID(ctx context.Context, id uuid.UUID) (*models.Runner, error)
 	GetByRaceID(ctx context.Context, raceID uuid.UUID) ([]*models.Runner, error)
+	GetBySelectionID(ctx context.Context, marketID string, selectionID uint64) (*models.Runner, error)
+	GetByMarketID(ctx context.Context, marketID string) ([]*models.Runner, error)
 	Update(ctx context.Context, runner *models.Runner) error
 	Delete(ctx context.Context, id uuid.UUID) error
 }
@@ -46,6 +55,7 @@ type BetRepository interface {
 	Update(ctx context.Context, bet *models.Bet) error
 	GetPendingBets(ctx context.Context) ([]*models.Bet, error)
 	GetSettledBets(ctx context.Context, start, end time.Time) ([]*models.Bet, error)
+	CountPlacedBetween(ctx context.Context, start, end time.Time) (int, error)
 }
 
 // StrategyRepository defines the interface for strategy data access
@@ -54,8 +64,12 @@ type StrategyRepository interface {
 	GetByID(ctx context.Context, id uuid.UUID) (*models.Strategy, error)
 	GetByName(ctx context.Context, name string) (*models.Strategy, error)
 	GetActive(ctx context.Context) ([]*models.Strategy, error)
+	GetAll(ctx context.Context) ([]*models.Strategy, error)
+	GetArchived(ctx context.Context) ([]*models.Strategy, error)
 	Update(ctx context.Context, strategy *models.Strategy) error
 	Delete(ctx context.Context, id uuid.UUID) error
+	Archive(ctx context.Context, id uuid.UUID) error
+	Unarchive(ctx context.Context, id uuid.UUID) error
 }
 
 // ModelRepository defines the interface for ML model data access
@@ -96,6 +110,44 @@ type BacktestResultRepository interface {
 	// ML Integration methods
 	GetTopPerforming(ctx context.Context, limit int) ([]*models.BacktestResult, error)
 	GetRecentUnprocessed(ctx context.Context, limit int) ([]*models.BacktestResult, error)
+	GetUnprocessedSince(ctx context.Context, since time.Time, limit int) ([]*models.BacktestResult, error)
 	MarkAsProcessed(ctx context.Context, resultID uuid.UUID) error
+	UpdateCompositeScore(ctx context.Context, resultID uuid.UUID, score float64, recommendation string) error
 	GetByCompositeScoreRange(ctx context.Context, minScore, maxScore float64, limit int) ([]*models.BacktestResult, error)
+	GetByTag(ctx context.Context, tags []string, match TagMatch) ([]*models.BacktestResult, error)
+}
+
+// TagMatch selects how GetByTag combines several tags
+type TagMatch int
+
+const (
+	// TagMatchAny returns results carrying at least one of the tags
+	TagMatchAny TagMatch = iota
+	// TagMatchAll returns results carrying every tag
+	TagMatchAll
+)
+
+// SyncCursorRepository persists how far historical sync has progressed per source
+type SyncCursorRepository interface {
+	Get(ctx context.Context, source string) (*models.SyncCursor, error)
+	Advance(ctx context.Context, source string, lastRaceTime time.Time) error
+}
+
+// FeedbackDeadLetterRepository persists failed ML feedback submissions for redelivery
+type FeedbackDeadLetterRepository interface {
+	Save(ctx context.Context, letter *models.FeedbackDeadLetter) error
+	GetDue(ctx context.Context, now time.Time, limit int) ([]*models.FeedbackDeadLetter, error)
+	RecordFailure(ctx context.Context, id uuid.UUID, lastError string, nextAttemptAt time.Time) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	Count(ctx context.Context) (int, error)
+}
+
+// ChunkRepository manages the chunks of a TimescaleDB hypertable
+type ChunkRepository interface {
+	// GetChunks returns the chunks of a hypertable ending at or before endBefore, oldest first
+	GetChunks(ctx context.Context, hypertable string, endBefore time.Time) ([]*models.Chunk, error)
+	// CompressChunk compresses a chunk of a hypertable and returns its size afterwards
+	CompressChunk(ctx context.Context, hypertable, chunk string) (int64, error)
+	// DropChunks drops the chunks of a hypertable ending at or before olderThan
+	DropChunks(ctx context.Context, hypertable string, olderThan time.Time) ([]string, error)
 }
@@ -24,13 +24,14 @@ func NewPostgresOddsRepository(db *database.DB) OddsRepository {
 // Insert inserts a single odds snapshot
 func (o *PostgresOddsRepository) Insert(ctx context.Context, odds *models.OddsSnapshot) error {
 	query := `
-		INSERT INTO odds_snapshots (time, race_id, runner_id, back_price, back_size, lay_price, lay_size, ltp, total_volume)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		INSERT INTO odds_snapshots (time, race_id, runner_id, back_price, back_size, lay_price, lay_size, ltp, total_volume,
+		                            back_ladder, lay_ladder, source)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, NULLIF($12, ''))
 	`
 
 	_, err := o.db.GetPool().Exec(ctx, query,
 		odds.Time, odds.RaceID, odds.RunnerID, odds.BackPrice, odds.BackSize,
-		odds.LayPrice, odds.LaySize, odds.LTP, odds.TotalVolume,
+		odds.LayPrice, odds.LaySize, odds.LTP, odds.TotalVolume, odds.BackLadder, odds.LayLadder, odds.Source,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to insert odds snapshot: %w", err)
@@ -46,13 +47,13 @@ func (o *PostgresOddsRepository) InsertBatch(ctx context.Context, odds []*models
 	}
 
 	// Use COPY for high-performance bulk insert
-	columns := []string{"time", "race_id", "runner_id", "back_price", "back_size", "lay_price", "lay_size", "ltp", "total_volume"}
+	columns := []string{"time", "race_id", "runner_id", "back_price", "back_size", "lay_price", "lay_size", "ltp", "total_volume", "back_ladder", "lay_ladder", "source"}
 	
 	copyFromSource := make([][]interface{}, len(odds))
 	for i, o := range odds {
 		copyFromSource[i] = []interface{}{
 			o.Time, o.RaceID, o.RunnerID, o.BackPrice, o.BackSize,
-			o.LayPrice, o.LaySize, o.LTP, o.TotalVolume,
+			o.LayPrice, o.LaySize, o.LTP, o.TotalVolume, o.BackLadder, o.LayLadder, nullableSource(o.Source),
 		}
 	}
 
@@ -71,7 +72,8 @@ func (o *PostgresOddsRepository) InsertBatch(ctx context.Context, odds []*models
 // GetByRaceID retrieves odds snapshots for a specific race within a time range
 func (o *PostgresOddsRepository) GetByRaceID(ctx context.Context, raceID uuid.UUID, start, end time.Time) ([]*models.OddsSnapshot, error) {
 	query := `
-		SELECT time, race_id, runner_id, back_price, back_size, lay_price, lay_size, ltp, total_volume
+		SELECT time, race_id, runner_id, back_price, back_size, lay_price, lay_size, ltp, total_volume, back_ladder, lay_ladder,
+		       COALESCE(source, '')
 		FROM odds_snapshots
 		WHERE race_id = $1 AND time >= $2 AND time <= $3
 		ORDER BY time ASC
@@ -88,7 +90,8 @@ func (o *PostgresOddsRepository) GetByRaceID(ctx context.Context, raceID uuid.UU
 		snapshot := &models.OddsSnapshot{}
 		err := rows.Scan(
 			&snapshot.Time, &snapshot.RaceID, &snapshot.RunnerID, &snapshot.BackPrice, &snapshot.BackSize,
-			&snapshot.LayPrice, &snapshot.LaySize, &snapshot.LTP, &snapshot.TotalVolume,
+			&snapshot.LayPrice, &snapshot.LaySize, &snapshot.LTP, &snapshot.TotalVolume, &snapshot.BackLadder, &snapshot.LayLadder,
+			&snapshot.Source,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan odds: %w", err)
@@ -99,20 +102,23 @@ func (o *PostgresOddsRepository) GetByRaceID(ctx context.Context, raceID uuid.UU
 	return snapshots, rows.Err()
 }
 
-// GetLatest retrieves the most recent odds snapshot for a runner in a race
+// GetLatest retrieves the most recent odds snapshot for a runner in a race.
+// When several sources recorded that time, the first by source name is used.
 func (o *PostgresOddsRepository) GetLatest(ctx context.Context, raceID, runnerID uuid.UUID) (*models.OddsSnapshot, error) {
 	query := `
-		SELECT time, race_id, runner_id, back_price, back_size, lay_price, lay_size, ltp, total_volume
+		SELECT time, race_id, runner_id, back_price, back_size, lay_price, lay_size, ltp, total_volume, back_ladder, lay_ladder,
+		       COALESCE(source, '')
 		FROM odds_snapshots
 		WHERE race_id = $1 AND runner_id = $2
-		ORDER BY time DESC
+		ORDER BY time DESC, source ASC NULLS LAST
 		LIMIT 1
 	`
 
 	snapshot := &models.OddsSnapshot{}
 	err := o.db.GetPool().QueryRow(ctx, query, raceID, runnerID).Scan(
 		&snapshot.Time, &snapshot.RaceID, &snapshot.RunnerID, &snapshot.BackPrice, &snapshot.BackSize,
-		&snapshot.LayPrice, &snapshot.LaySize, &snapshot.LTP, &snapshot.TotalVolume,
+		&snapshot.LayPrice, &snapshot.LaySize, &snapshot.LTP, &snapshot.TotalVolume, &snapshot.BackLadder, &snapshot.LayLadder,
+		&snapshot.Source,
 	)
 	if err == pgx.ErrNoRows {
 		return nil, models.ErrNotFound
@@ -127,7 +133,8 @@ func (o *PostgresOddsRepository) GetLatest(ctx context.Context, raceID, runnerID
 // GetTimeSeriesForRunner retrieves time-series odds data for a specific runner
 func (o *PostgresOddsRepository) GetTimeSeriesForRunner(ctx context.Context, runnerID uuid.UUID, start, end time.Time) ([]*models.OddsSnapshot, error) {
 	query := `
-		SELECT time, race_id, runner_id, back_price, back_size, lay_price, lay_size, ltp, total_volume
+		SELECT time, race_id, runner_id, back_price, back_size, lay_price, lay_size, ltp, total_volume, back_ladder, lay_ladder,
+		       COALESCE(source, '')
 		FROM odds_snapshots
 		WHERE runner_id = $1 AND time >= $2 AND time <= $3
 		ORDER BY time ASC
@@ -144,7 +151,8 @@ func (o *PostgresOddsRepository) GetTimeSeriesForRunner(ctx context.Context, run
 		snapshot := &models.OddsSnapshot{}
 		err := rows.Scan(
 			&snapshot.Time, &snapshot.RaceID, &snapshot.RunnerID, &snapshot.BackPrice, &snapshot.BackSize,
-			&snapshot.LayPrice, &snapshot.LaySize, &snapshot.LTP, &snapshot.TotalVolume,
+			&snapshot.LayPrice, &snapshot.LaySize, &snapshot.LTP, &snapshot.TotalVolume, &snapshot.BackLadder, &snapshot.LayLadder,
+			&snapshot.Source,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan odds: %w", err)
@@ -154,3 +162,11 @@ func (o *PostgresOddsRepository) GetTimeSeriesForRunner(ctx context.Context, run
 
 	return snapshots, rows.Err()
 }
+
+// nullableSource stores an empty snapshot source as NULL
+func nullableSource(source string) interface{} {
+	if source == "" {
+		return nil
+	}
+	return source
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"time"
 
@@ -30,7 +31,7 @@ func (r *PostgresRaceRepository) Create(ctx context.Context, race *models.Race)
 		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 	`
 
-	_, err := r.db.GetPool().Exec(ctx, query,
+	_, err := conn(ctx, r.db).Exec(ctx, query,
 		race.ID, race.ScheduledStart, race.Track, race.RaceType, race.Distance,
 		race.Grade, race.Conditions, race.Status,
 	)
@@ -122,7 +123,7 @@ func (r *PostgresRaceRepository) GetByDateRange(ctx context.Context, start, end
 		       conditions, status, created_at, updated_at
 		FROM races
 		WHERE scheduled_start >= $1 AND scheduled_start <= $2
-		ORDER BY scheduled_start ASC
+		ORDER BY scheduled_start ASC, id ASC
 	`
 
 	rows, err := r.db.GetPool().Query(ctx, query, start, end)
@@ -147,6 +148,69 @@ func (r *PostgresRaceRepository) GetByDateRange(ctx context.Context, start, end
 	return races, rows.Err()
 }
 
+// raceStreamBatchSize is how many races StreamByDateRange fetches per round trip
+const raceStreamBatchSize = 500
+
+// StreamByDateRange calls fn for each race in the range, in the same order as
+// GetByDateRange. Races are read from a server-side cursor a batch at a time,
+// so memory stays bounded however long the range is. An error from fn stops
+// the stream and is returned as is.
+func (r *PostgresRaceRepository) StreamByDateRange(ctx context.Context, start, end time.Time, fn func(*models.Race) error) error {
+	// Cursors only live inside a transaction; nothing is written, so it is
+	// always rolled back
+	tx, err := r.db.GetPool().BeginTx(ctx, pgx.TxOptions{AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return fmt.Errorf("failed to begin race stream: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	declare := `
+		DECLARE race_stream NO SCROLL CURSOR FOR
+		SELECT id, scheduled_start, actual_start, track, race_type, distance, grade,
+		       conditions, status, created_at, updated_at
+		FROM races
+		WHERE scheduled_start >= $1 AND scheduled_start <= $2
+		ORDER BY scheduled_start ASC, id ASC
+	`
+	if _, err := tx.Exec(ctx, declare, start, end); err != nil {
+		return fmt.Errorf("failed to declare race stream cursor: %w", err)
+	}
+
+	fetch := fmt.Sprintf("FETCH FORWARD %d FROM race_stream", raceStreamBatchSize)
+	batch := make([]*models.Race, 0, raceStreamBatchSize)
+	for {
+		batch = batch[:0]
+		rows, err := tx.Query(ctx, fetch)
+		if err != nil {
+			return fmt.Errorf("failed to fetch races from stream: %w", err)
+		}
+		for rows.Next() {
+			race := &models.Race{}
+			if err := rows.Scan(
+				&race.ID, &race.ScheduledStart, &race.ActualStart, &race.Track, &race.RaceType,
+				&race.Distance, &race.Grade, &race.Conditions, &race.Status, &race.CreatedAt, &race.UpdatedAt,
+			); err != nil {
+				rows.Close()
+				return fmt.Errorf(errScanRace, err)
+			}
+			batch = append(batch, race)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("failed to fetch races from stream: %w", err)
+		}
+
+		if len(batch) == 0 {
+			return nil
+		}
+		for _, race := range batch {
+			if err := fn(race); err != nil {
+				return err
+			}
+		}
+	}
+}
+
 // GetByTrackAndDate retrieves races by track and date for deduplication
 func (r *PostgresRaceRepository) GetByTrackAndDate(ctx context.Context, track string, date time.Time) ([]*models.Race, error) {
 	// Find races on the same track on the same day (within 24 hours)
@@ -183,6 +247,78 @@ func (r *PostgresRaceRepository) GetByTrackAndDate(ctx context.Context, track st
 	return races, rows.Err()
 }
 
+// GetRaceBundle retrieves a race with its runners and their odds from
+// oddsSince to the off in a single query
+func (r *PostgresRaceRepository) GetRaceBundle(ctx context.Context, raceID uuid.UUID, oddsSince time.Time) (*models.RaceBundle, error) {
+	bundles, err := r.GetRaceBundles(ctx, []uuid.UUID{raceID}, oddsSince)
+	if err != nil {
+		return nil, err
+	}
+	if len(bundles) == 0 {
+		return nil, models.ErrNotFound
+	}
+	return bundles[0], nil
+}
+
+// GetRaceBundles retrieves several races with their runners and their odds
+// from oddsSince to each race's off in a single query. Runners and odds are
+// aggregated to JSON per race so each race is one row however many runners
+// and snapshots it has.
+func (r *PostgresRaceRepository) GetRaceBundles(ctx context.Context, raceIDs []uuid.UUID, oddsSince time.Time) ([]*models.RaceBundle, error) {
+	if len(raceIDs) == 0 {
+		return nil, nil
+	}
+
+	query := `
+		SELECT r.id, r.scheduled_start, r.actual_start, r.track, r.race_type, r.distance, r.grade,
+		       r.conditions, r.status, r.created_at, r.updated_at,
+		       COALESCE((
+		           SELECT json_agg(ru ORDER BY ru.trap_number)
+		           FROM runners ru
+		           WHERE ru.race_id = r.id AND ru.status <> 'withdrawn'
+		       ), '[]'),
+		       COALESCE((
+		           SELECT json_agg(o ORDER BY o.time ASC)
+		           FROM odds_snapshots o
+		           WHERE o.race_id = r.id AND o.time >= $2 AND o.time <= r.scheduled_start
+		       ), '[]')
+		FROM races r
+		WHERE r.id = ANY($1)
+		ORDER BY r.scheduled_start ASC, r.id ASC
+	`
+
+	rows, err := r.db.GetPool().Query(ctx, query, raceIDs, oddsSince)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query race bundles: %w", err)
+	}
+	defer rows.Close()
+
+	var bundles []*models.RaceBundle
+	for rows.Next() {
+		race := &models.Race{}
+		var runnersJSON, oddsJSON []byte
+		err := rows.Scan(
+			&race.ID, &race.ScheduledStart, &race.ActualStart, &race.Track, &race.RaceType,
+			&race.Distance, &race.Grade, &race.Conditions, &race.Status, &race.CreatedAt, &race.UpdatedAt,
+			&runnersJSON, &oddsJSON,
+		)
+		if err != nil {
+			return nil, fmt.Errorf(errScanRace, err)
+		}
+
+		bundle := &models.RaceBundle{Race: race}
+		if err := json.Unmarshal(runnersJSON, &bundle.Runners); err != nil {
+			return nil, fmt.Errorf("failed to decode runners for race %s: %w", race.ID, err)
+		}
+		if err := json.Unmarshal(oddsJSON, &bundle.Odds); err != nil {
+			return nil, fmt.Errorf("failed to decode odds for race %s: %w", race.ID, err)
+		}
+		bundles = append(bundles, bundle)
+	}
+
+	return bundles, rows.Err()
+}
+
 // Update updates an existing race
 func (r *PostgresRaceRepository) Update(ctx context.Context, race *models.Race) error {
 	query := `
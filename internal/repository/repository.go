@@ -1,8 +1,11 @@
 package repository
 
 import (
+	"context"
 	"fmt"
 
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/yourusername/clever-better/internal/database"
 )
 
@@ -18,6 +21,9 @@ type Repositories struct {
 	StrategyPerformance StrategyPerformanceRepository
 	RaceResult          RaceResultRepository
 	BacktestResult      BacktestResultRepository
+	SyncCursor          SyncCursorRepository
+	FeedbackDeadLetter  FeedbackDeadLetterRepository
+	Chunk               ChunkRepository
 }
 
 // NewRepositories creates and returns all repository implementations
@@ -37,5 +43,23 @@ func NewRepositories(db *database.DB) (*Repositories, error) {
 		StrategyPerformance: NewPostgresStrategyPerformanceRepository(db),
 		RaceResult:          NewPostgresRaceResultRepository(db),
 		BacktestResult:      NewPostgresBacktestResultRepository(db),
+		SyncCursor:          NewPostgresSyncCursorRepository(db),
+		FeedbackDeadLetter:  NewPostgresFeedbackDeadLetterRepository(db),
+		Chunk:               NewPostgresChunkRepository(db),
 	}, nil
 }
+
+// querier is implemented by both the connection pool and a transaction
+type querier interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// conn returns the transaction started by database.WithTransaction when ctx
+// carries one, so writes join it, and the pool otherwise
+func conn(ctx context.Context, db *database.DB) querier {
+	if tx, ok := database.TxFromContext(ctx); ok {
+		return tx
+	}
+	return db.GetPool()
+}
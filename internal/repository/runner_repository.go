@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"fmt"
+	"strconv"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
@@ -23,13 +24,13 @@ func NewPostgresRunnerRepository(db *database.DB) RunnerRepository {
 // Create inserts a new runner
 func (r *PostgresRunnerRepository) Create(ctx context.Context, runner *models.Runner) error {
 	query := `
-		INSERT INTO runners (id, race_id, trap_number, name, form_rating, weight, trainer, days_since_last_race, metadata)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		INSERT INTO runners (id, race_id, trap_number, name, form_rating, weight, trainer, days_since_last_race, metadata, status)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, COALESCE(NULLIF($10, ''), 'active'))
 	`
 
-	_, err := r.db.GetPool().Exec(ctx, query,
+	_, err := conn(ctx, r.db).Exec(ctx, query,
 		runner.ID, runner.RaceID, runner.TrapNumber, runner.Name, runner.FormRating,
-		runner.Weight, runner.Trainer, runner.DaysSinceLastRace, runner.Metadata,
+		runner.Weight, runner.Trainer, runner.DaysSinceLastRace, runner.Metadata, runner.Status,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to create runner: %w", err)
@@ -42,14 +43,14 @@ func (r *PostgresRunnerRepository) Create(ctx context.Context, runner *models.Ru
 func (r *PostgresRunnerRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Runner, error) {
 	query := `
 		SELECT id, race_id, trap_number, name, form_rating, weight, trainer, 
-		       days_since_last_race, metadata, created_at, updated_at
+		       days_since_last_race, metadata, status, created_at, updated_at
 		FROM runners WHERE id = $1
 	`
 
 	runner := &models.Runner{}
 	err := r.db.GetPool().QueryRow(ctx, query, id).Scan(
 		&runner.ID, &runner.RaceID, &runner.TrapNumber, &runner.Name, &runner.FormRating,
-		&runner.Weight, &runner.Trainer, &runner.DaysSinceLastRace, &runner.Metadata, &runner.CreatedAt, &runner.UpdatedAt,
+		&runner.Weight, &runner.Trainer, &runner.DaysSinceLastRace, &runner.Metadata, &runner.Status, &runner.CreatedAt, &runner.UpdatedAt,
 	)
 	if err == pgx.ErrNoRows {
 		return nil, models.ErrNotFound
@@ -65,7 +66,7 @@ func (r *PostgresRunnerRepository) GetByID(ctx context.Context, id uuid.UUID) (*
 func (r *PostgresRunnerRepository) GetByRaceID(ctx context.Context, raceID uuid.UUID) ([]*models.Runner, error) {
 	query := `
 		SELECT id, race_id, trap_number, name, form_rating, weight, trainer,
-		       days_since_last_race, metadata, created_at, updated_at
+		       days_since_last_race, metadata, status, created_at, updated_at
 		FROM runners
 		WHERE race_id = $1
 		ORDER BY trap_number ASC
@@ -82,7 +83,7 @@ func (r *PostgresRunnerRepository) GetByRaceID(ctx context.Context, raceID uuid.
 		runner := &models.Runner{}
 		err := rows.Scan(
 			&runner.ID, &runner.RaceID, &runner.TrapNumber, &runner.Name, &runner.FormRating,
-			&runner.Weight, &runner.Trainer, &runner.DaysSinceLastRace, &runner.Metadata, &runner.CreatedAt, &runner.UpdatedAt,
+			&runner.Weight, &runner.Trainer, &runner.DaysSinceLastRace, &runner.Metadata, &runner.Status, &runner.CreatedAt, &runner.UpdatedAt,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan runner: %w", err)
@@ -93,18 +94,77 @@ func (r *PostgresRunnerRepository) GetByRaceID(ctx context.Context, raceID uuid.
 	return runners, rows.Err()
 }
 
+// GetByMarketID retrieves the runners recorded against a Betfair market
+func (r *PostgresRunnerRepository) GetByMarketID(ctx context.Context, marketID string) ([]*models.Runner, error) {
+	query := `
+		SELECT id, race_id, trap_number, name, form_rating, weight, trainer,
+		       days_since_last_race, metadata, status, created_at, updated_at
+		FROM runners
+		WHERE metadata->>'market_id' = $1
+		ORDER BY trap_number ASC
+	`
+
+	rows, err := r.db.GetPool().Query(ctx, query, marketID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query runners by market: %w", err)
+	}
+	defer rows.Close()
+
+	var runners []*models.Runner
+	for rows.Next() {
+		runner := &models.Runner{}
+		err := rows.Scan(
+			&runner.ID, &runner.RaceID, &runner.TrapNumber, &runner.Name, &runner.FormRating,
+			&runner.Weight, &runner.Trainer, &runner.DaysSinceLastRace, &runner.Metadata, &runner.Status, &runner.CreatedAt, &runner.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan runner: %w", err)
+		}
+		runners = append(runners, runner)
+	}
+
+	return runners, rows.Err()
+}
+
+// GetBySelectionID retrieves the runner recorded against a Betfair market and selection
+func (r *PostgresRunnerRepository) GetBySelectionID(ctx context.Context, marketID string, selectionID uint64) (*models.Runner, error) {
+	query := `
+		SELECT id, race_id, trap_number, name, form_rating, weight, trainer,
+		       days_since_last_race, metadata, status, created_at, updated_at
+		FROM runners
+		WHERE metadata->>'market_id' = $1 AND metadata->>'selection_id' = $2
+		ORDER BY created_at DESC
+		LIMIT 1
+	`
+
+	runner := &models.Runner{}
+	err := r.db.GetPool().QueryRow(ctx, query, marketID, strconv.FormatUint(selectionID, 10)).Scan(
+		&runner.ID, &runner.RaceID, &runner.TrapNumber, &runner.Name, &runner.FormRating,
+		&runner.Weight, &runner.Trainer, &runner.DaysSinceLastRace, &runner.Metadata, &runner.Status, &runner.CreatedAt, &runner.UpdatedAt,
+	)
+	if err == pgx.ErrNoRows {
+		return nil, models.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get runner by selection: %w", err)
+	}
+
+	return runner, nil
+}
+
 // Update updates an existing runner
 func (r *PostgresRunnerRepository) Update(ctx context.Context, runner *models.Runner) error {
 	query := `
 		UPDATE runners SET
 			trap_number = $2, name = $3, form_rating = $4, weight = $5,
-			trainer = $6, days_since_last_race = $7, metadata = $8, updated_at = NOW()
+			trainer = $6, days_since_last_race = $7, metadata = $8,
+			status = COALESCE(NULLIF($9, ''), 'active'), updated_at = NOW()
 		WHERE id = $1
 	`
 
 	commandTag, err := r.db.GetPool().Exec(ctx, query,
 		runner.ID, runner.TrapNumber, runner.Name, runner.FormRating,
-		runner.Weight, runner.Trainer, runner.DaysSinceLastRace, runner.Metadata,
+		runner.Weight, runner.Trainer, runner.DaysSinceLastRace, runner.Metadata, runner.Status,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to update runner: %w", err)
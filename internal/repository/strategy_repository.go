@@ -45,14 +45,14 @@ func (s *PostgresStrategyRepository) Create(ctx context.Context, strategy *model
 // GetByID retrieves a strategy by ID
 func (s *PostgresStrategyRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Strategy, error) {
 	query := `
-		SELECT id, name, description, parameters, active, created_at, updated_at
+		SELECT id, name, description, parameters, active, created_at, updated_at, archived_at
 		FROM strategies WHERE id = $1
 	`
 
 	strategy := &models.Strategy{}
 	err := s.db.GetPool().QueryRow(ctx, query, id).Scan(
 		&strategy.ID, &strategy.Name, &strategy.Description, &strategy.Parameters,
-		&strategy.Active, &strategy.CreatedAt, &strategy.UpdatedAt,
+		&strategy.Active, &strategy.CreatedAt, &strategy.UpdatedAt, &strategy.ArchivedAt,
 	)
 	if err == pgx.ErrNoRows {
 		return nil, models.ErrNotFound
@@ -67,7 +67,7 @@ func (s *PostgresStrategyRepository) GetByID(ctx context.Context, id uuid.UUID)
 // GetByName retrieves a strategy by name
 func (s *PostgresStrategyRepository) GetByName(ctx context.Context, name string) (*models.Strategy, error) {
 	query := `
-		SELECT id, name, description, parameters, active, created_at, updated_at
+		SELECT id, name, description, parameters, active, created_at, updated_at, archived_at
 		FROM strategies
 		WHERE name = $1
 		LIMIT 1
@@ -76,7 +76,7 @@ func (s *PostgresStrategyRepository) GetByName(ctx context.Context, name string)
 	strategy := &models.Strategy{}
 	err := s.db.GetPool().QueryRow(ctx, query, name).Scan(
 		&strategy.ID, &strategy.Name, &strategy.Description, &strategy.Parameters,
-		&strategy.Active, &strategy.CreatedAt, &strategy.UpdatedAt,
+		&strategy.Active, &strategy.CreatedAt, &strategy.UpdatedAt, &strategy.ArchivedAt,
 	)
 	if err == pgx.ErrNoRows {
 		return nil, models.ErrNotFound
@@ -88,19 +88,60 @@ func (s *PostgresStrategyRepository) GetByName(ctx context.Context, name string)
 	return strategy, nil
 }
 
-// GetActive retrieves all active strategies
+// GetActive retrieves all active strategies that are not archived
 func (s *PostgresStrategyRepository) GetActive(ctx context.Context) ([]*models.Strategy, error) {
 	query := `
-		SELECT id, name, description, parameters, active, created_at, updated_at
+		SELECT id, name, description, parameters, active, created_at, updated_at, archived_at
 		FROM strategies
-		WHERE active = true
+		WHERE active = true AND archived_at IS NULL
 		ORDER BY name ASC
 	`
 
-	rows, err := s.db.GetPool().Query(ctx, query)
+	strategies, err := s.list(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query active strategies: %w", err)
 	}
+	return strategies, nil
+}
+
+// GetAll retrieves every strategy that is not archived
+func (s *PostgresStrategyRepository) GetAll(ctx context.Context) ([]*models.Strategy, error) {
+	query := `
+		SELECT id, name, description, parameters, active, created_at, updated_at, archived_at
+		FROM strategies
+		WHERE archived_at IS NULL
+		ORDER BY name ASC
+	`
+
+	strategies, err := s.list(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query strategies: %w", err)
+	}
+	return strategies, nil
+}
+
+// GetArchived retrieves archived strategies, most recently archived first
+func (s *PostgresStrategyRepository) GetArchived(ctx context.Context) ([]*models.Strategy, error) {
+	query := `
+		SELECT id, name, description, parameters, active, created_at, updated_at, archived_at
+		FROM strategies
+		WHERE archived_at IS NOT NULL
+		ORDER BY archived_at DESC
+	`
+
+	strategies, err := s.list(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query archived strategies: %w", err)
+	}
+	return strategies, nil
+}
+
+// list runs a strategy query and scans every row
+func (s *PostgresStrategyRepository) list(ctx context.Context, query string, args ...interface{}) ([]*models.Strategy, error) {
+	rows, err := s.db.GetPool().Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
 	defer rows.Close()
 
 	var strategies []*models.Strategy
@@ -108,7 +149,7 @@ func (s *PostgresStrategyRepository) GetActive(ctx context.Context) ([]*models.S
 		strategy := &models.Strategy{}
 		err := rows.Scan(
 			&strategy.ID, &strategy.Name, &strategy.Description, &strategy.Parameters,
-			&strategy.Active, &strategy.CreatedAt, &strategy.UpdatedAt,
+			&strategy.Active, &strategy.CreatedAt, &strategy.UpdatedAt, &strategy.ArchivedAt,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan strategy: %w", err)
@@ -156,3 +197,44 @@ func (s *PostgresStrategyRepository) Delete(ctx context.Context, id uuid.UUID) e
 
 	return nil
 }
+
+// Archive soft-deletes a strategy and deactivates it. Its bets and backtest
+// results are kept. Archiving an archived strategy keeps its original time.
+func (s *PostgresStrategyRepository) Archive(ctx context.Context, id uuid.UUID) error {
+	query := `
+		UPDATE strategies SET
+			active = false, archived_at = COALESCE(archived_at, NOW()), updated_at = NOW()
+		WHERE id = $1
+	`
+
+	commandTag, err := s.db.GetPool().Exec(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to archive strategy: %w", err)
+	}
+
+	if commandTag.RowsAffected() == 0 {
+		return models.ErrNotFound
+	}
+
+	return nil
+}
+
+// Unarchive restores an archived strategy. It stays inactive until it is
+// activated again.
+func (s *PostgresStrategyRepository) Unarchive(ctx context.Context, id uuid.UUID) error {
+	query := `
+		UPDATE strategies SET archived_at = NULL, updated_at = NOW()
+		WHERE id = $1
+	`
+
+	commandTag, err := s.db.GetPool().Exec(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to unarchive strategy: %w", err)
+	}
+
+	if commandTag.RowsAffected() == 0 {
+		return models.ErrNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/yourusername/clever-better/internal/database"
+	"github.com/yourusername/clever-better/internal/models"
+)
+
+// PostgresSyncCursorRepository implements SyncCursorRepository for PostgreSQL
+type PostgresSyncCursorRepository struct {
+	db *database.DB
+}
+
+// NewPostgresSyncCursorRepository creates a new sync cursor repository
+func NewPostgresSyncCursorRepository(db *database.DB) SyncCursorRepository {
+	return &PostgresSyncCursorRepository{db: db}
+}
+
+// Get retrieves the cursor for a source
+func (r *PostgresSyncCursorRepository) Get(ctx context.Context, source string) (*models.SyncCursor, error) {
+	query := `
+		SELECT source, last_race_time, updated_at
+		FROM sync_cursors
+		WHERE source = $1
+	`
+
+	cursor := &models.SyncCursor{}
+	err := conn(ctx, r.db).QueryRow(ctx, query, source).Scan(
+		&cursor.Source, &cursor.LastRaceTime, &cursor.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, models.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get sync cursor: %w", err)
+	}
+
+	return cursor, nil
+}
+
+// Advance moves the cursor for a source forward to lastRaceTime. The cursor
+// never moves backwards. Inside database.WithTransaction the update commits
+// or rolls back with the rest of the batch.
+func (r *PostgresSyncCursorRepository) Advance(ctx context.Context, source string, lastRaceTime time.Time) error {
+	query := `
+		INSERT INTO sync_cursors (source, last_race_time, updated_at)
+		VALUES ($1, $2, NOW())
+		ON CONFLICT (source) DO UPDATE SET
+			last_race_time = GREATEST(sync_cursors.last_race_time, EXCLUDED.last_race_time),
+			updated_at = NOW()
+	`
+
+	if _, err := conn(ctx, r.db).Exec(ctx, query, source, lastRaceTime); err != nil {
+		return fmt.Errorf("failed to advance sync cursor: %w", err)
+	}
+
+	return nil
+}
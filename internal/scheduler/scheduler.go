@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/robfig/cron/v3"
+	"github.com/yourusername/clever-better/internal/config"
 	"github.com/yourusername/clever-better/internal/service"
 )
 
@@ -20,6 +21,8 @@ type Scheduler struct {
 	isRunning      bool
 	jobIDs         []cron.EntryID
 	gracefulTimeout time.Duration
+	maintenance    config.MaintenanceConfig
+	now            func() time.Time
 }
 
 // NewScheduler creates a new scheduler
@@ -30,9 +33,30 @@ func NewScheduler(ingestionSvc *service.IngestionService, logger *log.Logger) *S
 		logger:          logger,
 		jobIDs:          make([]cron.EntryID, 0),
 		gracefulTimeout: 30 * time.Second,
+		now:             time.Now,
 	}
 }
 
+// SetMaintenance skips ingestion runs that start inside a Betfair maintenance
+// window. The next run after the window picks up where they left off.
+func (s *Scheduler) SetMaintenance(maintenance config.MaintenanceConfig) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.maintenance = maintenance
+}
+
+// inMaintenance reports whether a job should skip this run, logging why
+func (s *Scheduler) inMaintenance(job string) bool {
+	s.mu.RLock()
+	until, active := s.maintenance.Active(s.now())
+	s.mu.RUnlock()
+
+	if active {
+		s.logger.Printf("Skipping %s during Betfair maintenance until %s", job, until.Format(time.RFC3339))
+	}
+	return active
+}
+
 // ScheduleHistoricalSync schedules historical data synchronization
 func (s *Scheduler) ScheduleHistoricalSync(cronExpression string, sourceName string) error {
 	s.mu.Lock()
@@ -43,17 +67,17 @@ func (s *Scheduler) ScheduleHistoricalSync(cronExpression string, sourceName str
 	}
 
 	jobFunc := func() {
+		if s.inMaintenance("historical sync") {
+			return
+		}
+
 		ctx, cancel := context.WithTimeout(context.Background(), 4*time.Hour)
 		defer cancel()
 
-		// Default: sync last 7 days
-		endDate := time.Now()
-		startDate := endDate.Add(-7 * 24 * time.Hour)
-
-		s.logger.Printf("Starting scheduled historical sync from %s for %s to %s",
-			sourceName, startDate.Format("2006-01-02"), endDate.Format("2006-01-02"))
+		// Default: last 7 days, or from the persisted cursor when enabled
+		s.logger.Printf("Starting scheduled historical sync from %s", sourceName)
 
-		metrics, err := s.ingestionSvc.IngestHistoricalData(ctx, sourceName, startDate, endDate)
+		metrics, err := s.ingestionSvc.SyncHistoricalData(ctx, sourceName, 7*24*time.Hour)
 		if err != nil {
 			s.logger.Printf("Error during scheduled historical sync: %v", err)
 		} else {
@@ -86,6 +110,10 @@ func (s *Scheduler) ScheduleLivePolling(intervalSeconds int, sourceName string)
 	}
 
 	jobFunc := func() {
+		if s.inMaintenance("live polling") {
+			return
+		}
+
 		ctx, cancel := context.WithTimeout(context.Background(), time.Duration(intervalSeconds-1)*time.Second)
 		defer cancel()
 
@@ -105,6 +133,40 @@ func (s *Scheduler) ScheduleLivePolling(intervalSeconds int, sourceName string)
 	return nil
 }
 
+// ScheduleOddsRetention schedules compression and dropping of old odds
+// snapshot chunks
+func (s *Scheduler) ScheduleOddsRetention(cronExpression string, retention *service.OddsRetentionService) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.isRunning {
+		return fmt.Errorf("cannot schedule job while scheduler is running")
+	}
+
+	jobFunc := func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Hour)
+		defer cancel()
+
+		result, err := retention.Enforce(ctx)
+		if err != nil {
+			s.logger.Printf("Error during odds retention: %v", err)
+			return
+		}
+		s.logger.Printf("Odds retention completed: %d chunks compressed, %d dropped, %d bytes reclaimed (dry run: %t)",
+			result.ChunksCompressed, result.ChunksDropped, result.BytesReclaimed, result.DryRun)
+	}
+
+	entryID, err := s.cron.AddFunc(cronExpression, jobFunc)
+	if err != nil {
+		return fmt.Errorf("failed to add job: %w", err)
+	}
+
+	s.jobIDs = append(s.jobIDs, entryID)
+	s.logger.Printf("Scheduled odds retention job with cron expression: %s", cronExpression)
+
+	return nil
+}
+
 // Start starts the scheduler
 func (s *Scheduler) Start() error {
 	s.mu.Lock()
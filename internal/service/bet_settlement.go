@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/yourusername/clever-better/internal/backtest"
+	"github.com/yourusername/clever-better/internal/models"
+	"github.com/yourusername/clever-better/internal/repository"
+)
+
+// BetSettlementService settles a race's open bets once its result is known
+type BetSettlementService struct {
+	betRepo        repository.BetRepository
+	runnerRepo     repository.RunnerRepository
+	commissionRate float64
+	logger         *logrus.Logger
+}
+
+// NewBetSettlementService creates a new bet settlement service
+func NewBetSettlementService(
+	betRepo repository.BetRepository,
+	runnerRepo repository.RunnerRepository,
+	commissionRate float64,
+	logger *logrus.Logger,
+) *BetSettlementService {
+	return &BetSettlementService{
+		betRepo:        betRepo,
+		runnerRepo:     runnerRepo,
+		commissionRate: commissionRate,
+		logger:         logger,
+	}
+}
+
+// SettleRace settles every matched bet on the result's race, and every BSP bet
+// still waiting for its starting price. Bets that are already settled or
+// cancelled are left alone, so settling a race twice is harmless.
+func (s *BetSettlementService) SettleRace(ctx context.Context, result *models.RaceResult) error {
+	bets, err := s.betRepo.GetByRaceID(ctx, result.RaceID)
+	if err != nil {
+		return fmt.Errorf("failed to load bets: %w", err)
+	}
+	runners, err := s.runnerRepo.GetByRaceID(ctx, result.RaceID)
+	if err != nil {
+		return fmt.Errorf("failed to load runners: %w", err)
+	}
+	runnerByID := make(map[string]*models.Runner, len(runners))
+	for _, runner := range runners {
+		runnerByID[runner.ID.String()] = runner
+	}
+
+	settled := 0
+	for _, bet := range bets {
+		if !settleable(bet) {
+			continue
+		}
+		backtest.SettleBet(bet, result, runnerByID[bet.RunnerID.String()], s.commissionRate)
+		if err := s.betRepo.Update(ctx, bet); err != nil {
+			return fmt.Errorf("failed to update settled bet: %w", err)
+		}
+		settled++
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"race_id": result.RaceID,
+		"status":  result.Status,
+		"settled": settled,
+	}).Info("Race settled")
+	return nil
+}
+
+// settleable reports whether a bet is waiting on its race result
+func settleable(bet *models.Bet) bool {
+	switch bet.Status {
+	case models.BetStatusMatched:
+		return true
+	case models.BetStatusPending:
+		return bet.IsBSP
+	default:
+		return false
+	}
+}
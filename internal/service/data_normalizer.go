@@ -48,6 +48,17 @@ func (n *DataNormalizer) NormalizeRace(sourceRace *datasource.RaceData) (*models
 		UpdatedAt:          time.Now(),
 	}
 
+	conditions := make(map[string]interface{})
+	if sourceRace.GoingDescription != nil {
+		conditions["going"] = *sourceRace.GoingDescription
+	}
+	if sourceRace.WeatherCode != nil {
+		conditions["weather_code"] = *sourceRace.WeatherCode
+	}
+	if err := race.SetConditions(conditions); err != nil {
+		return nil, err
+	}
+
 	// Convert runners
 	race.Runners = make([]*models.Runner, len(sourceRace.Runners))
 	for i, sourceRunner := range sourceRace.Runners {
@@ -6,16 +6,28 @@ import (
 	"time"
 
 	"github.com/yourusername/clever-better/internal/models"
+	"github.com/yourusername/clever-better/internal/strategy"
 )
 
-// DataValidator validates race and runner data
+// DataValidator validates race, runner and odds data
 type DataValidator struct {
-	logger *log.Logger
+	logger     *log.Logger
+	oddsFilter *strategy.OddsSanityFilter
 }
 
-// NewDataValidator creates a new data validator
+// NewDataValidator creates a new data validator using the default odds bounds
 func NewDataValidator(logger *log.Logger) *DataValidator {
-	return &DataValidator{logger: logger}
+	return &DataValidator{
+		logger:     logger,
+		oddsFilter: strategy.DefaultOddsSanityFilter(),
+	}
+}
+
+// SetOddsSanityFilter replaces the odds bounds used by ValidateOdds
+func (v *DataValidator) SetOddsSanityFilter(filter *strategy.OddsSanityFilter) {
+	if filter != nil {
+		v.oddsFilter = filter
+	}
 }
 
 // ValidateRace validates race data for required fields and constraints
@@ -56,8 +68,20 @@ func (v *DataValidator) ValidateRace(race *models.Race) []string {
 	return errors
 }
 
-// ValidateRunner validates runner data for required fields and constraints
-func (v *DataValidator) ValidateRunner(runner *models.Runner) []string {
+// Runner limits by discipline. Greyhound weights are in kg, horse weights in lbs.
+const (
+	maxGreyhoundTrap     = 8
+	minGreyhoundWeightKg = 20.0
+	maxGreyhoundWeightKg = 45.0
+	maxHorseStall        = 40
+	minHorseWeightLbs    = 84.0
+	maxHorseWeightLbs    = 196.0
+)
+
+// ValidateRunner validates runner data for required fields and constraints.
+// Trap and weight rules follow the race's discipline; a nil race is treated
+// as a greyhound race.
+func (v *DataValidator) ValidateRunner(runner *models.Runner, race *models.Race) []string {
 	var errors []string
 
 	// Check required fields
@@ -65,8 +89,10 @@ func (v *DataValidator) ValidateRunner(runner *models.Runner) []string {
 		errors = append(errors, "runner name is required")
 	}
 
-	if runner.TrapNumber < 1 || runner.TrapNumber > 8 {
-		errors = append(errors, fmt.Sprintf("trap_number must be 1-8 for greyhounds, got %d", runner.TrapNumber))
+	if race != nil && race.IsHorseRace() {
+		errors = append(errors, validateHorseRunner(runner)...)
+	} else {
+		errors = append(errors, validateGreyhoundRunner(runner)...)
 	}
 
 	// Validate optional fields if present
@@ -87,6 +113,74 @@ func (v *DataValidator) ValidateRunner(runner *models.Runner) []string {
 	return errors
 }
 
+// validateGreyhoundRunner requires a trap and a plausible weight in kg
+func validateGreyhoundRunner(runner *models.Runner) []string {
+	var errors []string
+
+	if runner.TrapNumber < 1 || runner.TrapNumber > maxGreyhoundTrap {
+		errors = append(errors, fmt.Sprintf("trap_number must be 1-%d for greyhounds, got %d", maxGreyhoundTrap, runner.TrapNumber))
+	}
+
+	if runner.Weight != nil && (*runner.Weight < minGreyhoundWeightKg || *runner.Weight > maxGreyhoundWeightKg) {
+		errors = append(errors, fmt.Sprintf("weight must be %.0f-%.0fkg for greyhounds, got %.1f", minGreyhoundWeightKg, maxGreyhoundWeightKg, *runner.Weight))
+	}
+
+	return errors
+}
+
+// validateHorseRunner allows races without stalls (trap 0) and checks the
+// weight carried in lbs
+func validateHorseRunner(runner *models.Runner) []string {
+	var errors []string
+
+	if runner.TrapNumber < 0 || runner.TrapNumber > maxHorseStall {
+		errors = append(errors, fmt.Sprintf("stall must be 0-%d for horses, got %d", maxHorseStall, runner.TrapNumber))
+	}
+
+	if runner.Weight != nil && (*runner.Weight < minHorseWeightLbs || *runner.Weight > maxHorseWeightLbs) {
+		errors = append(errors, fmt.Sprintf("weight must be %.0f-%.0flbs for horses, got %.1f", minHorseWeightLbs, maxHorseWeightLbs, *runner.Weight))
+	}
+
+	return errors
+}
+
+// ValidateOdds checks each price in a snapshot against the plausible range for
+// the race and, when previous is set, against the maximum tick move from the
+// previous snapshot for the same runner
+func (v *DataValidator) ValidateOdds(snapshot, previous *models.OddsSnapshot, race *models.Race) []string {
+	var errors []string
+
+	prices := []struct {
+		name     string
+		price    *float64
+		previous *float64
+	}{
+		{"back_price", snapshot.BackPrice, nil},
+		{"lay_price", snapshot.LayPrice, nil},
+		{"ltp", snapshot.LTP, nil},
+	}
+	if previous != nil {
+		prices[0].previous = previous.BackPrice
+		prices[1].previous = previous.LayPrice
+		prices[2].previous = previous.LTP
+	}
+
+	for _, p := range prices {
+		if p.price == nil {
+			continue
+		}
+		last := 0.0
+		if p.previous != nil {
+			last = *p.previous
+		}
+		if err := v.oddsFilter.Check(race, *p.price, last); err != nil {
+			errors = append(errors, fmt.Sprintf("%s: %v", p.name, err))
+		}
+	}
+
+	return errors
+}
+
 // ValidateRaceUniqueness checks if race is unique by track and scheduled start
 func (v *DataValidator) ValidateRaceUniqueness(race *models.Race, existingRaces []*models.Race) error {
 	for _, existing := range existingRaces {
@@ -103,8 +197,9 @@ func (v *DataValidator) ValidateRaceUniqueness(race *models.Race, existingRaces
 func (v *DataValidator) ValidateRunnerInRace(runner *models.Runner, race *models.Race) []string {
 	var errors []string
 
-	// Trap number should be <= number of runners
-	if runner.TrapNumber > race.NumberOfRunners {
+	// Trap number should be <= number of runners. Horse stalls keep their
+	// draw after non-runners are withdrawn, so they can exceed the field size.
+	if !race.IsHorseRace() && runner.TrapNumber > race.NumberOfRunners {
 		errors = append(errors, fmt.Sprintf("trap_number %d exceeds race runners %d", runner.TrapNumber, race.NumberOfRunners))
 	}
 
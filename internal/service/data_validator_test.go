@@ -240,12 +240,80 @@ func TestRunnerDataValidation(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			errors := validator.ValidateRunner(tt.runner)
+			errors := validator.ValidateRunner(tt.runner, nil)
 			assertValidationErrors(t, errors, tt.expectValid, tt.shouldHave)
 		})
 	}
 }
 
+// TestRunnerValidationByRaceType tests that trap and weight rules follow the race discipline
+func TestRunnerValidationByRaceType(t *testing.T) {
+	validator := newTestValidator()
+
+	flat := &models.Race{RaceType: "Flat", NumberOfRunners: 10}
+	hurdle := &models.Race{RaceType: "hurdle", NumberOfRunners: 14}
+	greyhound := &models.Race{RaceType: "A1", NumberOfRunners: 6}
+
+	tests := []struct {
+		name        string
+		race        *models.Race
+		runner      *models.Runner
+		expectValid bool
+		shouldHave  string
+	}{
+		{
+			name:        "Horse in stall 12 on the flat",
+			race:        flat,
+			runner:      &models.Runner{Name: runnerName, TrapNumber: 12, Weight: ptr(126.0)},
+			expectValid: true,
+		},
+		{
+			name:        "Greyhound in trap 12",
+			race:        greyhound,
+			runner:      &models.Runner{Name: runnerName, TrapNumber: 12},
+			expectValid: false,
+			shouldHave:  "trap_number must be 1-8",
+		},
+		{
+			name:        "Jumps runner without a stall",
+			race:        hurdle,
+			runner:      &models.Runner{Name: runnerName, Weight: ptr(154.0)},
+			expectValid: true,
+		},
+		{
+			name:        "Horse stall out of range",
+			race:        flat,
+			runner:      &models.Runner{Name: runnerName, TrapNumber: 41},
+			expectValid: false,
+			shouldHave:  "stall must be 0-40",
+		},
+		{
+			name:        "Horse weight in kg rejected",
+			race:        flat,
+			runner:      &models.Runner{Name: runnerName, TrapNumber: 3, Weight: ptr(32.0)},
+			expectValid: false,
+			shouldHave:  "weight must be 84-196lbs",
+		},
+		{
+			name:        "Greyhound weight in lbs rejected",
+			race:        greyhound,
+			runner:      &models.Runner{Name: runnerName, TrapNumber: 3, Weight: ptr(126.0)},
+			expectValid: false,
+			shouldHave:  "weight must be 20-45kg",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errors := validator.ValidateRunner(tt.runner, tt.race)
+			assertValidationErrors(t, errors, tt.expectValid, tt.shouldHave)
+			if tt.expectValid {
+				assert.Empty(t, validator.ValidateRunnerInRace(tt.runner, tt.race))
+			}
+		})
+	}
+}
+
 // TestValidateRunnerInRace tests runner-in-race validation
 func TestValidateRunnerInRace(t *testing.T) {
 	validator := newTestValidator()
@@ -419,6 +487,52 @@ func ptr[T any](v T) *T {
 	return &v
 }
 
+func TestValidateOdds(t *testing.T) {
+	validator := newTestValidator()
+	race := &models.Race{RaceType: "A1"}
+	price := func(v float64) *float64 { return &v }
+
+	tests := []struct {
+		name        string
+		snapshot    *models.OddsSnapshot
+		previous    *models.OddsSnapshot
+		expectValid bool
+		shouldHave  string
+	}{
+		{
+			name:        "Normal price passes",
+			snapshot:    &models.OddsSnapshot{BackPrice: price(3.5), LayPrice: price(3.6), LTP: price(3.5)},
+			expectValid: true,
+		},
+		{
+			name:        "Spike rejected",
+			snapshot:    &models.OddsSnapshot{BackPrice: price(1001.0)},
+			expectValid: false,
+			shouldHave:  "back_price: odds 1001.00 outside plausible range",
+		},
+		{
+			name:        "Implausibly short lay price rejected",
+			snapshot:    &models.OddsSnapshot{BackPrice: price(3.5), LayPrice: price(1.01)},
+			expectValid: false,
+			shouldHave:  "lay_price",
+		},
+		{
+			name:        "Jump from previous snapshot rejected",
+			snapshot:    &models.OddsSnapshot{LTP: price(60)},
+			previous:    &models.OddsSnapshot{LTP: price(3.5)},
+			expectValid: false,
+			shouldHave:  "ltp: odds moved",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errors := validator.ValidateOdds(tt.snapshot, tt.previous, race)
+			assertValidationErrors(t, errors, tt.expectValid, tt.shouldHave)
+		})
+	}
+}
+
 func contains(s, substr string) bool {
 	for i := 0; i <= len(s)-len(substr); i++ {
 		if s[i:i+len(substr)] == substr {
@@ -2,8 +2,10 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"sort"
 	"time"
 
 	"github.com/google/uuid"
@@ -12,11 +14,23 @@ import (
 	"github.com/yourusername/clever-better/internal/repository"
 )
 
+// errRaceRejected marks a race that failed normalization or validation. Such
+// races are skipped during sync rather than failing the whole batch.
+var errRaceRejected = errors.New("race rejected")
+
+// TxRunner runs a function inside a database transaction. database.DB
+// implements it.
+type TxRunner interface {
+	WithTransaction(ctx context.Context, fn func(context.Context) error) error
+}
+
 // IngestionService handles the data ingestion workflow
 type IngestionService struct {
 	sources   []datasource.DataSource
 	raceRepo  repository.RaceRepository
 	runnerRepo repository.RunnerRepository
+	cursorRepo repository.SyncCursorRepository
+	txRunner   TxRunner
 	validator *DataValidator
 	normalizer *DataNormalizer
 	metrics   *IngestionMetrics
@@ -50,6 +64,13 @@ func NewIngestionService(
 	}
 }
 
+// EnableSyncCursor makes SyncHistoricalData resumable. Each batch is written
+// in a transaction together with the source's cursor.
+func (s *IngestionService) EnableSyncCursor(cursorRepo repository.SyncCursorRepository, txRunner TxRunner) {
+	s.cursorRepo = cursorRepo
+	s.txRunner = txRunner
+}
+
 // IngestHistoricalData fetches and ingests historical data from a specific source
 func (s *IngestionService) IngestHistoricalData(ctx context.Context, sourceName string, startDate, endDate time.Time) (*IngestionMetrics, error) {
 	s.metrics.Reset()
@@ -103,6 +124,108 @@ func (s *IngestionService) IngestHistoricalData(ctx context.Context, sourceName
 	return s.metrics, nil
 }
 
+// SyncHistoricalData ingests races from a source up to now. It resumes from
+// the source's persisted cursor when one exists, however old, so gaps left by
+// missed runs are backfilled; otherwise it starts lookback ago. Races are
+// committed in batches and the cursor only advances with a committed batch,
+// so a failed batch is retried by the next sync. Without a cursor repository
+// it falls back to IngestHistoricalData over the lookback window.
+func (s *IngestionService) SyncHistoricalData(ctx context.Context, sourceName string, lookback time.Duration) (*IngestionMetrics, error) {
+	endDate := time.Now()
+	startDate := endDate.Add(-lookback)
+
+	if s.cursorRepo == nil || s.txRunner == nil {
+		return s.IngestHistoricalData(ctx, sourceName, startDate, endDate)
+	}
+
+	s.metrics.Reset()
+	startTime := time.Now()
+
+	source := s.findSource(sourceName)
+	if source == nil {
+		return nil, fmt.Errorf("data source not found: %s", sourceName)
+	}
+
+	cursor, err := s.cursorRepo.Get(ctx, sourceName)
+	switch {
+	case err == nil:
+		// Start at the cursor itself: races sharing its start time are
+		// deduplicated by processRace, so re-reading them is harmless
+		startDate = cursor.LastRaceTime
+	case errors.Is(err, models.ErrNotFound):
+	default:
+		return nil, fmt.Errorf("failed to get sync cursor: %w", err)
+	}
+
+	s.logger.Printf("Starting historical sync from %s (%s to %s)", sourceName, startDate.Format(time.RFC3339), endDate.Format(time.RFC3339))
+
+	races, err := source.FetchRaces(ctx, startDate, endDate)
+	if err != nil {
+		s.metrics.Errors++
+		return s.metrics, fmt.Errorf("failed to fetch races: %w", err)
+	}
+	s.metrics.TotalRaces = len(races)
+
+	// The cursor is a high-water mark, so batches must be in start order
+	sort.SliceStable(races, func(i, j int) bool {
+		return races[i].ScheduledStartTime.Before(races[j].ScheduledStartTime)
+	})
+
+	for i := 0; i < len(races); i += s.batchSize {
+		end := i + s.batchSize
+		if end > len(races) {
+			end = len(races)
+		}
+
+		if err := s.commitBatch(ctx, sourceName, races[i:end]); err != nil {
+			s.metrics.Errors++
+			s.metrics.Duration = time.Since(startTime)
+			return s.metrics, fmt.Errorf("sync stopped at batch starting %s: %w",
+				races[i].ScheduledStartTime.Format(time.RFC3339), err)
+		}
+	}
+
+	s.metrics.Duration = time.Since(startTime)
+	s.logger.Printf("Historical sync complete: %d races, %d runners, %d errors, duration: %v",
+		s.metrics.SuccessfulRaces, s.metrics.TotalRunners, s.metrics.Errors, s.metrics.Duration)
+
+	return s.metrics, nil
+}
+
+// commitBatch writes a batch of races and advances the cursor to the last
+// race's start time in a single transaction. Rejected races are skipped; any
+// other failure rolls the whole batch back.
+func (s *IngestionService) commitBatch(ctx context.Context, sourceName string, batch []datasource.RaceData) error {
+	return s.txRunner.WithTransaction(ctx, func(txCtx context.Context) error {
+		for i := range batch {
+			if err := s.processRace(txCtx, &batch[i]); err != nil {
+				if errors.Is(err, errRaceRejected) {
+					s.metrics.Errors++
+					s.logger.Printf("Skipping race %s: %v", batch[i].SourceID, err)
+					continue
+				}
+				return err
+			}
+		}
+
+		lastRaceTime := batch[len(batch)-1].ScheduledStartTime
+		if err := s.cursorRepo.Advance(txCtx, sourceName, lastRaceTime); err != nil {
+			return err
+		}
+		return nil
+	})
+}
+
+// findSource returns the configured data source with the given name
+func (s *IngestionService) findSource(sourceName string) datasource.DataSource {
+	for _, src := range s.sources {
+		if src.Name() == sourceName {
+			return src
+		}
+	}
+	return nil
+}
+
 // IngestLiveData fetches and ingests upcoming/live races
 func (s *IngestionService) IngestLiveData(ctx context.Context, sourceName string) error {
 	// Find the specified data source
@@ -178,7 +301,7 @@ func (s *IngestionService) processRace(ctx context.Context, sourceRace *datasour
 
 	// Validate and process runners
 	for _, runner := range race.Runners {
-		validationErrors := s.validator.ValidateRunner(runner)
+		validationErrors := s.validator.ValidateRunner(runner, race)
 		if len(validationErrors) > 0 {
 			s.metrics.ValidationErrors++
 			s.logger.Printf("Runner validation failed for %s: %v", runner.Name, validationErrors)
@@ -0,0 +1,187 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/clever-better/internal/datasource"
+	"github.com/yourusername/clever-better/internal/models"
+	"github.com/yourusername/clever-better/internal/repository"
+)
+
+// fakeSyncSource serves a fixed set of races and records each fetch window
+type fakeSyncSource struct {
+	races  []datasource.RaceData
+	starts []time.Time
+}
+
+func (s *fakeSyncSource) FetchRaces(ctx context.Context, startDate, endDate time.Time) ([]datasource.RaceData, error) {
+	s.starts = append(s.starts, startDate)
+	out := make([]datasource.RaceData, 0)
+	for _, race := range s.races {
+		if race.ScheduledStartTime.Before(startDate) || race.ScheduledStartTime.After(endDate) {
+			continue
+		}
+		out = append(out, race)
+	}
+	return out, nil
+}
+
+func (s *fakeSyncSource) FetchRaceDetails(ctx context.Context, raceID string) (*datasource.RaceData, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (s *fakeSyncSource) Name() string    { return "test" }
+func (s *fakeSyncSource) IsEnabled() bool { return true }
+
+// fakeSyncRaceRepo stores races in memory and fails inserts for one track
+type fakeSyncRaceRepo struct {
+	repository.RaceRepository
+	races     []*models.Race
+	failTrack string
+}
+
+func (r *fakeSyncRaceRepo) Create(ctx context.Context, race *models.Race) error {
+	if race.Track == r.failTrack {
+		return errors.New("insert failed")
+	}
+	r.races = append(r.races, race)
+	return nil
+}
+
+func (r *fakeSyncRaceRepo) GetByTrackAndDate(ctx context.Context, track string, date time.Time) ([]*models.Race, error) {
+	out := make([]*models.Race, 0)
+	for _, race := range r.races {
+		if race.Track == track && race.ScheduledStart.Equal(date) {
+			out = append(out, race)
+		}
+	}
+	return out, nil
+}
+
+// fakeSyncCursorRepo keeps cursors in memory
+type fakeSyncCursorRepo struct {
+	cursors map[string]time.Time
+}
+
+func (r *fakeSyncCursorRepo) Get(ctx context.Context, source string) (*models.SyncCursor, error) {
+	last, ok := r.cursors[source]
+	if !ok {
+		return nil, models.ErrNotFound
+	}
+	return &models.SyncCursor{Source: source, LastRaceTime: last}, nil
+}
+
+func (r *fakeSyncCursorRepo) Advance(ctx context.Context, source string, lastRaceTime time.Time) error {
+	if lastRaceTime.After(r.cursors[source]) {
+		r.cursors[source] = lastRaceTime
+	}
+	return nil
+}
+
+// fakeTxRunner restores the in-memory repositories when a transaction fails
+type fakeTxRunner struct {
+	races   *fakeSyncRaceRepo
+	cursors *fakeSyncCursorRepo
+}
+
+func (t *fakeTxRunner) WithTransaction(ctx context.Context, fn func(context.Context) error) error {
+	races := append([]*models.Race(nil), t.races.races...)
+	cursors := make(map[string]time.Time, len(t.cursors.cursors))
+	for k, v := range t.cursors.cursors {
+		cursors[k] = v
+	}
+
+	if err := fn(ctx); err != nil {
+		t.races.races = races
+		t.cursors.cursors = cursors
+		return err
+	}
+	return nil
+}
+
+func newTestSyncService(source *fakeSyncSource, batchSize int) (*IngestionService, *fakeSyncRaceRepo, *fakeSyncCursorRepo) {
+	logger := log.New(io.Discard, "", 0)
+	races := &fakeSyncRaceRepo{}
+	cursors := &fakeSyncCursorRepo{cursors: make(map[string]time.Time)}
+
+	svc := NewIngestionService(
+		[]datasource.DataSource{source},
+		races,
+		nil,
+		NewDataValidator(logger),
+		NewDataNormalizer(logger),
+		logger,
+		batchSize,
+	)
+	svc.EnableSyncCursor(cursors, &fakeTxRunner{races: races, cursors: cursors})
+	return svc, races, cursors
+}
+
+func syncRace(track string, start time.Time) datasource.RaceData {
+	return datasource.RaceData{
+		SourceID:           track + start.Format(time.RFC3339),
+		Track:              track,
+		ScheduledStartTime: start,
+		RaceType:           "A1",
+		Distance:           480,
+		RaceNumber:         1,
+	}
+}
+
+func TestSyncHistoricalDataResumesFromCursor(t *testing.T) {
+	base := time.Now().Add(-20 * time.Hour).Truncate(time.Minute)
+	source := &fakeSyncSource{races: []datasource.RaceData{
+		syncRace("Romford", base),
+		syncRace("Romford", base.Add(time.Hour)),
+		syncRace("Romford", base.Add(2*time.Hour)),
+	}}
+	svc, races, cursors := newTestSyncService(source, 2)
+
+	_, err := svc.SyncHistoricalData(context.Background(), "test", 7*24*time.Hour)
+	require.NoError(t, err)
+	assert.Len(t, races.races, 3)
+	assert.Equal(t, base.Add(2*time.Hour), cursors.cursors["test"])
+
+	source.races = append(source.races, syncRace("Romford", base.Add(3*time.Hour)))
+	metrics, err := svc.SyncHistoricalData(context.Background(), "test", 7*24*time.Hour)
+	require.NoError(t, err)
+
+	require.Len(t, source.starts, 2)
+	assert.Equal(t, base.Add(2*time.Hour), source.starts[1], "second sync starts at the cursor")
+	assert.Equal(t, 2, metrics.TotalRaces, "only the cursor race and the new race are fetched")
+	assert.Equal(t, 1, metrics.Duplicates)
+	assert.Len(t, races.races, 4)
+	assert.Equal(t, base.Add(3*time.Hour), cursors.cursors["test"])
+}
+
+func TestSyncHistoricalDataFailedBatchKeepsCursor(t *testing.T) {
+	base := time.Now().Add(-20 * time.Hour).Truncate(time.Minute)
+	source := &fakeSyncSource{races: []datasource.RaceData{
+		syncRace("Romford", base),
+		syncRace("Romford", base.Add(time.Hour)),
+		syncRace("Hove", base.Add(2*time.Hour)),
+		syncRace("Romford", base.Add(3*time.Hour)),
+	}}
+	svc, races, cursors := newTestSyncService(source, 2)
+	races.failTrack = "Hove"
+
+	_, err := svc.SyncHistoricalData(context.Background(), "test", 7*24*time.Hour)
+	require.Error(t, err)
+
+	assert.Equal(t, base.Add(time.Hour), cursors.cursors["test"], "cursor stays at the last committed batch")
+	assert.Len(t, races.races, 2, "the failed batch is rolled back")
+
+	races.failTrack = ""
+	_, err = svc.SyncHistoricalData(context.Background(), "test", 7*24*time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, base.Add(time.Hour), source.starts[1])
+	assert.Len(t, races.races, 4)
+	assert.Equal(t, base.Add(3*time.Hour), cursors.cursors["test"])
+}
@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"time"
@@ -20,6 +21,7 @@ type MarketDataService struct {
 	raceRepository   repository.RaceRepository
 	runnerRepository repository.RunnerRepository
 	oddsRepository   repository.OddsRepository
+	validator        *DataValidator
 	logger           *log.Logger
 }
 
@@ -40,10 +42,18 @@ func NewMarketDataService(
 		raceRepository:   raceRepository,
 		runnerRepository: runnerRepository,
 		oddsRepository:   oddsRepository,
+		validator:        NewDataValidator(logger),
 		logger:           logger,
 	}
 }
 
+// SetValidator replaces the validator used to screen odds snapshots
+func (m *MarketDataService) SetValidator(validator *DataValidator) {
+	if validator != nil {
+		m.validator = validator
+	}
+}
+
 // FetchAndStoreMarketData fetches market data for a date range and stores it
 func (m *MarketDataService) FetchAndStoreMarketData(
 	ctx context.Context,
@@ -62,7 +72,7 @@ func (m *MarketDataService) FetchAndStoreMarketData(
 
 	// Process each market
 	for _, catalog := range catalogs {
-		if err := m.storeMarketCatalog(ctx, &catalog); err != nil {
+		if _, err := m.storeMarketCatalog(ctx, &catalog); err != nil {
 			m.logger.Printf("Error storing market catalog: %v", err)
 			continue
 		}
@@ -71,8 +81,35 @@ func (m *MarketDataService) FetchAndStoreMarketData(
 	return nil
 }
 
-// storeMarketCatalog converts and stores market catalog data
-func (m *MarketDataService) storeMarketCatalog(ctx context.Context, catalog *betfair.MarketCatalogue) error {
+// StoreMarketCards stores the races, runners and current prices for a card of
+// markets, fetching catalogue and book in one Betfair round trip
+func (m *MarketDataService) StoreMarketCards(ctx context.Context, marketIDs []string) error {
+	cards, err := m.betfairClient.ListMarketCards(ctx, marketIDs, []string{"EX_BEST_OFFERS", "EX_TRADED"})
+	if err != nil {
+		return fmt.Errorf("failed to fetch market cards: %w", err)
+	}
+
+	for i := range cards {
+		card := &cards[i]
+		raceID, err := m.storeMarketCatalog(ctx, &card.Catalogue)
+		if err != nil {
+			m.logger.Printf("Error storing market catalog: %v", err)
+			continue
+		}
+		if raceID == uuid.Nil || card.Book == nil {
+			continue
+		}
+		if err := m.storeMarketBook(ctx, card.Book, raceID); err != nil {
+			m.logger.Printf("Error storing prices for market %s: %v", card.Catalogue.MarketID, err)
+		}
+	}
+
+	return nil
+}
+
+// storeMarketCatalog converts and stores market catalog data. It returns the
+// new race's ID, or uuid.Nil when the race was already stored.
+func (m *MarketDataService) storeMarketCatalog(ctx context.Context, catalog *betfair.MarketCatalogue) (uuid.UUID, error) {
 	// Check if race already exists
 	existingRaces, err := m.raceRepository.GetByDateRange(
 		ctx,
@@ -81,7 +118,7 @@ func (m *MarketDataService) storeMarketCatalog(ctx context.Context, catalog *bet
 	)
 	if err == nil && len(existingRaces) > 0 {
 		m.logger.Printf("Race already exists for market %s, skipping", catalog.MarketID)
-		return nil
+		return uuid.Nil, nil
 	}
 
 	// Create Race record
@@ -103,7 +140,7 @@ func (m *MarketDataService) storeMarketCatalog(ctx context.Context, catalog *bet
 
 	// Store race
 	if err := m.raceRepository.Create(ctx, race); err != nil {
-		return fmt.Errorf("failed to create race: %w", err)
+		return uuid.Nil, fmt.Errorf("failed to create race: %w", err)
 	}
 
 	m.logger.Printf("Stored race: %s (%s)", race.Track, catalog.MarketName)
@@ -116,10 +153,14 @@ func (m *MarketDataService) storeMarketCatalog(ctx context.Context, catalog *bet
 			SourceID:     fmt.Sprintf("%d", runner.SelectionID),
 			Name:         runner.RunnerName,
 			TrackNumber:  m.extractTrapNumber(runner.RunnerName),
-			Status:       models.RunnerStatusActive,
+			Status:       models.RunnerStatusFromBetfair(runner.Status),
 			CreatedAt:    time.Now(),
 			UpdatedAt:    time.Now(),
 		}
+		if err := runnerModel.SetBetfairSelection(catalog.MarketID, runner.SelectionID); err != nil {
+			m.logger.Printf("Error recording selection for runner %s: %v", runner.RunnerName, err)
+			continue
+		}
 
 		if err := m.runnerRepository.Create(ctx, runnerModel); err != nil {
 			m.logger.Printf("Error storing runner %s: %v", runner.RunnerName, err)
@@ -128,28 +169,25 @@ func (m *MarketDataService) storeMarketCatalog(ctx context.Context, catalog *bet
 	}
 
 	m.logger.Printf("Stored %d runners for race %s", len(catalog.Runners), race.Track)
-	return nil
+	return race.ID, nil
 }
 
 // storeHistoricalPrices stores historical odds data
-// findRunnerIDBySourceID finds the runner ID matching the given selection ID
-func (m *MarketDataService) findRunnerIDBySourceID(
+// findRunnerIDBySelection finds the runner ID recorded for a Betfair selection
+func (m *MarketDataService) findRunnerIDBySelection(
 	ctx context.Context,
-	raceID uuid.UUID,
-	selectionID int64,
+	marketID string,
+	selectionID uint64,
 ) (uuid.UUID, error) {
-	runners, err := m.runnerRepository.GetByRaceID(ctx, raceID)
+	runner, err := m.runnerRepository.GetBySelectionID(ctx, marketID, selectionID)
 	if err != nil {
-		return uuid.Nil, err
-	}
-
-	for _, r := range runners {
-		if r.SourceID == fmt.Sprintf("%d", selectionID) {
-			return r.ID, nil
+		if errors.Is(err, models.ErrNotFound) {
+			return uuid.Nil, nil
 		}
+		return uuid.Nil, err
 	}
 
-	return uuid.Nil, nil
+	return runner.ID, nil
 }
 
 // extractPricesFromRunner extracts back and lay prices from a runner
@@ -190,12 +228,17 @@ func (m *MarketDataService) storeHistoricalPrices(
 		return fmt.Errorf("no market book data returned")
 	}
 
-	book := books[0]
+	return m.storeMarketBook(ctx, &books[0], raceID)
+}
+
+// storeMarketBook stores the validated prices from a market book
+func (m *MarketDataService) storeMarketBook(ctx context.Context, book *betfair.MarketBook, raceID uuid.UUID) error {
+	marketID := book.MarketID
 	snapshots := make([]*models.OddsSnapshot, 0, len(book.Runners))
 
 	for _, runner := range book.Runners {
 		// Find runner ID from database
-		runnerID, err := m.findRunnerIDBySourceID(ctx, raceID, runner.SelectionID)
+		runnerID, err := m.findRunnerIDBySelection(ctx, marketID, runner.SelectionID)
 		if err != nil || runnerID == uuid.Nil {
 			continue
 		}
@@ -215,6 +258,14 @@ func (m *MarketDataService) storeHistoricalPrices(
 			TradedVolume:    tradedVolume,
 			LastPriceTraded: runner.LastPriceTraded,
 			Timestamp:       time.Now(),
+			Source:          models.OddsSourceBetfair,
+		}
+
+		// Drop implausible prices rather than storing a glitch
+		previous, _ := m.oddsRepository.GetLatest(ctx, raceID, runnerID)
+		if errs := m.validator.ValidateOdds(snapshot, previous, nil); len(errs) > 0 {
+			m.logger.Printf("Rejected odds snapshot for runner %s in market %s: %v", runnerID, marketID, errs)
+			continue
 		}
 
 		snapshots = append(snapshots, snapshot)
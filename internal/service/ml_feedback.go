@@ -3,7 +3,10 @@ package service
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
@@ -14,12 +17,45 @@ import (
 	"github.com/yourusername/clever-better/internal/repository"
 )
 
+// feedbackLockKey is the advisory lock key guarding batch feedback submission
+const feedbackLockKey int64 = 0x6d6c666565646261 // "mlfeedba"
+
+// ErrFeedbackInProgress is returned when another process holds the feedback lock
+var ErrFeedbackInProgress = errors.New("feedback submission already in progress")
+
+// ErrFeedbackDeadLettered is returned when a submission failed and the result
+// was stored for redelivery by RetryDeadLetters
+var ErrFeedbackDeadLettered = errors.New("feedback submission failed and was dead-lettered")
+
+// Dead-letter redelivery backs off exponentially from the base to the cap
+const (
+	deadLetterBaseBackoff = time.Minute
+	deadLetterMaxBackoff  = time.Hour
+)
+
+// FeedbackSubmitter submits backtest results to the ML service
+type FeedbackSubmitter interface {
+	SubmitBacktestFeedback(ctx context.Context, result *models.BacktestResult) error
+}
+
+// AdvisoryLocker takes cross-process locks, typically a database advisory lock
+type AdvisoryLocker interface {
+	TryAdvisoryLock(ctx context.Context, key int64) (func(context.Context) error, bool, error)
+}
+
 // MLFeedbackService manages feedback submission to ML service
 type MLFeedbackService struct {
-	mlClient     *ml.CachedMLClient
+	mlClient     FeedbackSubmitter
 	httpClient   *ml.HTTPClient
 	backtestRepo repository.BacktestResultRepository
+	locker       AdvisoryLocker
+	deadLetters  repository.FeedbackDeadLetterRepository
 	logger       *logrus.Logger
+
+	// watermark is the created_at of the newest result such that it and
+	// every older result have been submitted. Scans resume from here.
+	watermark time.Time
+	mu        sync.Mutex
 }
 
 // NewMLFeedbackService creates a new ML feedback service
@@ -37,7 +73,26 @@ func NewMLFeedbackService(
 	}
 }
 
-// SubmitBacktestResult submits a single backtest result as feedback
+// SetLocker sets the lock used to stop concurrent batch submissions across
+// processes. Without one, only submissions within this process are serialised.
+func (s *MLFeedbackService) SetLocker(locker AdvisoryLocker) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.locker = locker
+}
+
+// SetDeadLetterStore sets where failed submissions are kept for redelivery.
+// Without one, failed results are left unprocessed and rescanned by batches.
+func (s *MLFeedbackService) SetDeadLetterStore(store repository.FeedbackDeadLetterRepository) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.deadLetters = store
+}
+
+// SubmitBacktestResult submits a single backtest result as feedback and marks
+// it processed. An error is returned if either step fails, so the result is
+// retried by the next batch. With a dead-letter store, a failed submission is
+// stored and marked processed instead, and ErrFeedbackDeadLettered returned.
 func (s *MLFeedbackService) SubmitBacktestResult(ctx context.Context, result *models.BacktestResult) error {
 	s.logger.WithFields(logrus.Fields{
 		"strategy_id":     result.StrategyID,
@@ -46,25 +101,57 @@ func (s *MLFeedbackService) SubmitBacktestResult(ctx context.Context, result *mo
 
 	if err := s.mlClient.SubmitBacktestFeedback(ctx, result); err != nil {
 		s.logger.WithError(err).Error("Failed to submit feedback")
-		return fmt.Errorf("failed to submit backtest feedback: %w", err)
+		if s.deadLetters == nil {
+			return fmt.Errorf("failed to submit backtest feedback: %w", err)
+		}
+		if dlErr := s.deadLetter(ctx, result, err); dlErr != nil {
+			s.logger.WithError(dlErr).WithField("result_id", result.ID).Error("Failed to dead-letter feedback")
+			return fmt.Errorf("failed to submit backtest feedback: %w", err)
+		}
+		return fmt.Errorf("%w: %v", ErrFeedbackDeadLettered, err)
 	}
 
 	// Mark as processed in database
 	if err := s.backtestRepo.MarkAsProcessed(ctx, result.ID); err != nil {
 		s.logger.WithError(err).Warn("Failed to mark backtest result as processed")
-		// Don't fail the whole operation for this
+		return fmt.Errorf("feedback submitted but not marked processed: %w", err)
 	}
 
 	s.logger.WithField("result_id", result.ID).Debug("Successfully submitted feedback")
 	return nil
 }
 
-// SubmitBatch submits multiple backtest results in batch
+// SubmitBatch submits up to batchSize unprocessed backtest results, oldest
+// first. Each result is marked processed only after its own submission
+// succeeds. The high-water mark advances past a result only while every
+// earlier result in the batch succeeded, so failures are rescanned next time.
+// Returns ErrFeedbackInProgress if another process is already submitting.
 func (s *MLFeedbackService) SubmitBatch(ctx context.Context, batchSize int) (int, error) {
-	s.logger.WithField("batch_size", batchSize).Info("Submitting batch feedback")
+	s.mu.Lock()
+	defer s.mu.Unlock()
 
-	// Get recent unprocessed backtest results
-	results, err := s.backtestRepo.GetRecentUnprocessed(ctx, batchSize)
+	if s.locker != nil {
+		unlock, acquired, err := s.locker.TryAdvisoryLock(ctx, feedbackLockKey)
+		if err != nil {
+			return 0, fmt.Errorf("failed to acquire feedback lock: %w", err)
+		}
+		if !acquired {
+			s.logger.Info("Feedback submission already in progress elsewhere, skipping")
+			return 0, ErrFeedbackInProgress
+		}
+		defer func() {
+			if err := unlock(ctx); err != nil {
+				s.logger.WithError(err).Warn("Failed to release feedback lock")
+			}
+		}()
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"batch_size": batchSize,
+		"watermark":  s.watermark,
+	}).Info("Submitting batch feedback")
+
+	results, err := s.backtestRepo.GetUnprocessedSince(ctx, s.watermark, batchSize)
 	if err != nil {
 		return 0, fmt.Errorf("failed to get unprocessed results: %w", err)
 	}
@@ -74,24 +161,177 @@ func (s *MLFeedbackService) SubmitBatch(ctx context.Context, batchSize int) (int
 		return 0, nil
 	}
 
+	seen := make(map[uuid.UUID]bool, len(results))
 	successCount := 0
+	failedCount := 0
+	deadLetteredCount := 0
+	contiguous := true
 	for _, result := range results {
-		if err := s.SubmitBacktestResult(ctx, result); err != nil {
+		if seen[result.ID] {
+			continue
+		}
+		seen[result.ID] = true
+
+		err := s.SubmitBacktestResult(ctx, result)
+		if errors.Is(err, ErrFeedbackDeadLettered) {
+			// Redelivery is owned by the dead-letter store, so the watermark may pass it
+			deadLetteredCount++
+			if contiguous && result.CreatedAt.After(s.watermark) {
+				s.watermark = result.CreatedAt
+			}
+			continue
+		}
+		if err != nil {
 			s.logger.WithError(err).WithField("result_id", result.ID).Error("Failed to submit result in batch")
+			failedCount++
+			contiguous = false
 			continue
 		}
 		successCount++
+		if contiguous && result.CreatedAt.After(s.watermark) {
+			s.watermark = result.CreatedAt
+		}
 	}
 
 	s.logger.WithFields(logrus.Fields{
-		"total":   len(results),
-		"success": successCount,
-		"failed":  len(results) - successCount,
+		"total":         len(seen),
+		"success":       successCount,
+		"failed":        failedCount,
+		"dead_lettered": deadLetteredCount,
+		"watermark":     s.watermark,
 	}).Info("Batch feedback submission complete")
 
 	return successCount, nil
 }
 
+// deadLetter stores a failed submission for redelivery and marks the result
+// processed so batches do not resubmit it alongside the retry job
+func (s *MLFeedbackService) deadLetter(ctx context.Context, result *models.BacktestResult, submitErr error) error {
+	payload, err := json.Marshal(result)
+	if err != nil {
+		return fmt.Errorf("failed to marshal feedback payload: %w", err)
+	}
+
+	letter := &models.FeedbackDeadLetter{
+		BacktestResultID: result.ID,
+		Payload:          payload,
+		LastError:        submitErr.Error(),
+		NextAttemptAt:    time.Now(),
+	}
+	if err := s.deadLetters.Save(ctx, letter); err != nil {
+		return err
+	}
+	s.updateDeadLetterDepth(ctx)
+
+	if err := s.backtestRepo.MarkAsProcessed(ctx, result.ID); err != nil {
+		// The row is unique per result, so a rescan only refreshes it
+		s.logger.WithError(err).WithField("result_id", result.ID).Warn("Failed to mark dead-lettered result as processed")
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"result_id": result.ID,
+		"attempts":  letter.Attempts,
+	}).Warn("Feedback submission dead-lettered")
+	return nil
+}
+
+// RetryDeadLetters redelivers up to limit dead-lettered submissions that are
+// due. Delivered rows are removed; failures are retried later with
+// exponential backoff. Returns the number delivered.
+func (s *MLFeedbackService) RetryDeadLetters(ctx context.Context, limit int) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.deadLetters == nil {
+		return 0, nil
+	}
+
+	now := time.Now()
+	letters, err := s.deadLetters.GetDue(ctx, now, limit)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get due dead letters: %w", err)
+	}
+
+	delivered := 0
+	for _, letter := range letters {
+		result := &models.BacktestResult{}
+		err := json.Unmarshal(letter.Payload, result)
+		if err == nil {
+			err = s.mlClient.SubmitBacktestFeedback(ctx, result)
+		}
+		if err != nil {
+			next := now.Add(deadLetterBackoff(letter.Attempts + 1))
+			s.logger.WithError(err).WithFields(logrus.Fields{
+				"result_id":       letter.BacktestResultID,
+				"attempts":        letter.Attempts + 1,
+				"next_attempt_at": next,
+			}).Warn("Dead-lettered feedback redelivery failed")
+			if err := s.deadLetters.RecordFailure(ctx, letter.ID, err.Error(), next); err != nil {
+				return delivered, fmt.Errorf("failed to record dead letter failure: %w", err)
+			}
+			continue
+		}
+
+		if err := s.deadLetters.Delete(ctx, letter.ID); err != nil {
+			return delivered, fmt.Errorf("failed to delete delivered dead letter: %w", err)
+		}
+		delivered++
+	}
+
+	s.updateDeadLetterDepth(ctx)
+
+	if len(letters) > 0 {
+		s.logger.WithFields(logrus.Fields{
+			"due":       len(letters),
+			"delivered": delivered,
+		}).Info("Dead-lettered feedback redelivery complete")
+	}
+
+	return delivered, nil
+}
+
+// ScheduleDeadLetterRetry runs RetryDeadLetters every interval until ctx is done
+func (s *MLFeedbackService) ScheduleDeadLetterRetry(ctx context.Context, interval time.Duration, limit int) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	s.logger.WithField("interval", interval).Info("Starting dead-letter retry scheduler")
+
+	for {
+		select {
+		case <-ctx.Done():
+			s.logger.Info("Stopping dead-letter retry scheduler")
+			return
+		case <-ticker.C:
+			if _, err := s.RetryDeadLetters(ctx, limit); err != nil {
+				s.logger.WithError(err).Error("Failed to retry dead-lettered feedback")
+			}
+		}
+	}
+}
+
+// updateDeadLetterDepth refreshes the dead-letter depth gauge
+func (s *MLFeedbackService) updateDeadLetterDepth(ctx context.Context) {
+	depth, err := s.deadLetters.Count(ctx)
+	if err != nil {
+		s.logger.WithError(err).Warn("Failed to count dead-lettered feedback")
+		return
+	}
+	ml.MLFeedbackDeadLetterDepth.Set(float64(depth))
+}
+
+// deadLetterBackoff is the delay before the given delivery attempt
+func deadLetterBackoff(attempt int) time.Duration {
+	backoff := deadLetterBaseBackoff
+	for i := 1; i < attempt && backoff < deadLetterMaxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > deadLetterMaxBackoff {
+		return deadLetterMaxBackoff
+	}
+	return backoff
+}
+
 // TriggerRetraining initiates model retraining with specified config
 func (s *MLFeedbackService) TriggerRetraining(ctx context.Context, config ml.TrainingConfig) (*ml.TrainingStatus, error) {
 	s.logger.WithFields(logrus.Fields{
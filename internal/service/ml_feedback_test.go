@@ -0,0 +1,363 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/clever-better/internal/ml"
+	"github.com/yourusername/clever-better/internal/models"
+	"github.com/yourusername/clever-better/internal/repository"
+)
+
+// fakeBacktestResultRepo keeps backtest results and their processed flag in memory
+type fakeBacktestResultRepo struct {
+	repository.BacktestResultRepository
+	results   []*models.BacktestResult
+	processed map[uuid.UUID]bool
+	markErr   map[uuid.UUID]error
+}
+
+func newFakeBacktestResultRepo(results ...*models.BacktestResult) *fakeBacktestResultRepo {
+	return &fakeBacktestResultRepo{
+		results:   results,
+		processed: make(map[uuid.UUID]bool),
+		markErr:   make(map[uuid.UUID]error),
+	}
+}
+
+func (r *fakeBacktestResultRepo) GetUnprocessedSince(ctx context.Context, since time.Time, limit int) ([]*models.BacktestResult, error) {
+	out := make([]*models.BacktestResult, 0)
+	for _, result := range r.results {
+		if r.processed[result.ID] || result.CreatedAt.Before(since) {
+			continue
+		}
+		out = append(out, result)
+		if len(out) == limit {
+			break
+		}
+	}
+	return out, nil
+}
+
+func (r *fakeBacktestResultRepo) MarkAsProcessed(ctx context.Context, resultID uuid.UUID) error {
+	if err := r.markErr[resultID]; err != nil {
+		return err
+	}
+	r.processed[resultID] = true
+	return nil
+}
+
+// fakeFeedbackSubmitter fails submission for selected results
+type fakeFeedbackSubmitter struct {
+	failFor   map[uuid.UUID]bool
+	submitted []uuid.UUID
+	keys      []string
+}
+
+func (f *fakeFeedbackSubmitter) SubmitBacktestFeedback(ctx context.Context, result *models.BacktestResult) error {
+	f.submitted = append(f.submitted, result.ID)
+	key, err := ml.FeedbackIdempotencyKey(result)
+	if err != nil {
+		return err
+	}
+	f.keys = append(f.keys, key)
+	if f.failFor[result.ID] {
+		return errors.New("ml service unavailable")
+	}
+	return nil
+}
+
+// fakeLocker reports whether the advisory lock is free
+type fakeLocker struct {
+	held     bool
+	released int
+}
+
+func (l *fakeLocker) TryAdvisoryLock(ctx context.Context, key int64) (func(context.Context) error, bool, error) {
+	if l.held {
+		return nil, false, nil
+	}
+	l.held = true
+	return func(context.Context) error {
+		l.held = false
+		l.released++
+		return nil
+	}, true, nil
+}
+
+func newTestFeedbackService(submitter FeedbackSubmitter, repo repository.BacktestResultRepository) *MLFeedbackService {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+	return &MLFeedbackService{
+		mlClient:     submitter,
+		backtestRepo: repo,
+		logger:       logger,
+	}
+}
+
+func seedBacktestResults(n int) []*models.BacktestResult {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	results := make([]*models.BacktestResult, n)
+	for i := range results {
+		results[i] = &models.BacktestResult{
+			ID:         uuid.New(),
+			StrategyID: uuid.New(),
+			CreatedAt:  base.Add(time.Duration(i) * time.Minute),
+		}
+	}
+	return results
+}
+
+func TestSubmitBatchPartialFailureMarksOnlySuccesses(t *testing.T) {
+	results := seedBacktestResults(4)
+	repo := newFakeBacktestResultRepo(results...)
+	submitter := &fakeFeedbackSubmitter{failFor: map[uuid.UUID]bool{results[1].ID: true}}
+	svc := newTestFeedbackService(submitter, repo)
+
+	count, err := svc.SubmitBatch(context.Background(), 10)
+	require.NoError(t, err)
+	assert.Equal(t, 3, count)
+
+	assert.True(t, repo.processed[results[0].ID])
+	assert.False(t, repo.processed[results[1].ID], "failed submission must not be marked processed")
+	assert.True(t, repo.processed[results[2].ID])
+	assert.True(t, repo.processed[results[3].ID])
+
+	// The watermark stops before the failure so it is retried
+	assert.Equal(t, results[0].CreatedAt, svc.watermark)
+
+	delete(submitter.failFor, results[1].ID)
+	submitter.submitted = nil
+
+	count, err = svc.SubmitBatch(context.Background(), 10)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+	assert.Equal(t, []uuid.UUID{results[1].ID}, submitter.submitted, "only the failed result is resubmitted")
+	assert.True(t, repo.processed[results[1].ID])
+	assert.Equal(t, results[1].CreatedAt, svc.watermark)
+}
+
+func TestSubmitBatchRetriesUnconfirmedResultWithSameKey(t *testing.T) {
+	results := seedBacktestResults(1)
+	repo := newFakeBacktestResultRepo(results...)
+	submitter := &fakeFeedbackSubmitter{failFor: map[uuid.UUID]bool{results[0].ID: true}}
+	svc := newTestFeedbackService(submitter, repo)
+
+	// A timed-out delivery may have reached the service, but without a
+	// confirmed success the result stays unprocessed
+	count, err := svc.SubmitBatch(context.Background(), 10)
+	require.NoError(t, err)
+	assert.Equal(t, 0, count)
+	assert.False(t, repo.processed[results[0].ID])
+
+	submitter.failFor = nil
+	count, err = svc.SubmitBatch(context.Background(), 10)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+	assert.True(t, repo.processed[results[0].ID])
+
+	require.Len(t, submitter.keys, 2)
+	assert.Equal(t, submitter.keys[0], submitter.keys[1], "the retry must be recognisable as a duplicate")
+}
+
+func TestSubmitBatchMarkFailureIsNotCounted(t *testing.T) {
+	results := seedBacktestResults(2)
+	repo := newFakeBacktestResultRepo(results...)
+	repo.markErr[results[0].ID] = errors.New("connection reset")
+	svc := newTestFeedbackService(&fakeFeedbackSubmitter{}, repo)
+
+	count, err := svc.SubmitBatch(context.Background(), 10)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+	assert.False(t, repo.processed[results[0].ID])
+	assert.True(t, repo.processed[results[1].ID])
+	assert.True(t, svc.watermark.IsZero(), "watermark must not pass an unmarked result")
+}
+
+func TestSubmitBatchDeduplicatesResults(t *testing.T) {
+	results := seedBacktestResults(1)
+	repo := newFakeBacktestResultRepo(results[0], results[0])
+	submitter := &fakeFeedbackSubmitter{}
+	svc := newTestFeedbackService(submitter, repo)
+
+	count, err := svc.SubmitBatch(context.Background(), 10)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+	assert.Len(t, submitter.submitted, 1)
+}
+
+func TestSubmitBatchSkipsWhenLockHeld(t *testing.T) {
+	results := seedBacktestResults(2)
+	repo := newFakeBacktestResultRepo(results...)
+	submitter := &fakeFeedbackSubmitter{}
+	locker := &fakeLocker{held: true}
+	svc := newTestFeedbackService(submitter, repo)
+	svc.SetLocker(locker)
+
+	count, err := svc.SubmitBatch(context.Background(), 10)
+	assert.ErrorIs(t, err, ErrFeedbackInProgress)
+	assert.Equal(t, 0, count)
+	assert.Empty(t, submitter.submitted)
+
+	locker.held = false
+	count, err = svc.SubmitBatch(context.Background(), 10)
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+	assert.Equal(t, 1, locker.released)
+	assert.False(t, locker.held)
+}
+
+// fakeDeadLetterStore keeps dead letters in memory, one per backtest result
+type fakeDeadLetterStore struct {
+	letters map[uuid.UUID]*models.FeedbackDeadLetter
+}
+
+func newFakeDeadLetterStore() *fakeDeadLetterStore {
+	return &fakeDeadLetterStore{letters: make(map[uuid.UUID]*models.FeedbackDeadLetter)}
+}
+
+func (f *fakeDeadLetterStore) Save(ctx context.Context, letter *models.FeedbackDeadLetter) error {
+	for _, existing := range f.letters {
+		if existing.BacktestResultID == letter.BacktestResultID {
+			existing.Payload = letter.Payload
+			existing.LastError = letter.LastError
+			existing.NextAttemptAt = letter.NextAttemptAt
+			existing.Attempts++
+			*letter = *existing
+			return nil
+		}
+	}
+	letter.ID = uuid.New()
+	letter.Attempts = 1
+	stored := *letter
+	f.letters[letter.ID] = &stored
+	return nil
+}
+
+func (f *fakeDeadLetterStore) GetDue(ctx context.Context, now time.Time, limit int) ([]*models.FeedbackDeadLetter, error) {
+	out := make([]*models.FeedbackDeadLetter, 0)
+	for _, letter := range f.letters {
+		if !letter.NextAttemptAt.After(now) && len(out) < limit {
+			copied := *letter
+			out = append(out, &copied)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeDeadLetterStore) RecordFailure(ctx context.Context, id uuid.UUID, lastError string, nextAttemptAt time.Time) error {
+	letter := f.letters[id]
+	letter.Attempts++
+	letter.LastError = lastError
+	letter.NextAttemptAt = nextAttemptAt
+	return nil
+}
+
+func (f *fakeDeadLetterStore) Delete(ctx context.Context, id uuid.UUID) error {
+	delete(f.letters, id)
+	return nil
+}
+
+func (f *fakeDeadLetterStore) Count(ctx context.Context) (int, error) {
+	return len(f.letters), nil
+}
+
+func TestSubmitBatchDeadLettersFailedSubmission(t *testing.T) {
+	results := seedBacktestResults(3)
+	repo := newFakeBacktestResultRepo(results...)
+	submitter := &fakeFeedbackSubmitter{failFor: map[uuid.UUID]bool{results[1].ID: true}}
+	store := newFakeDeadLetterStore()
+	svc := newTestFeedbackService(submitter, repo)
+	svc.SetDeadLetterStore(store)
+
+	count, err := svc.SubmitBatch(context.Background(), 10)
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	require.Len(t, store.letters, 1)
+	var letter *models.FeedbackDeadLetter
+	for _, l := range store.letters {
+		letter = l
+	}
+	assert.Equal(t, results[1].ID, letter.BacktestResultID)
+	assert.Equal(t, "ml service unavailable", letter.LastError)
+	assert.Equal(t, 1, letter.Attempts)
+	assert.JSONEq(t, mustJSON(t, results[1]), string(letter.Payload))
+
+	// The store owns redelivery, so batches move on past the failure
+	assert.True(t, repo.processed[results[1].ID])
+	assert.Equal(t, results[2].CreatedAt, svc.watermark)
+	assert.Equal(t, 1.0, gaugeValue(t, ml.MLFeedbackDeadLetterDepth))
+}
+
+func TestRetryDeadLettersDrainsOnceServiceRecovers(t *testing.T) {
+	results := seedBacktestResults(2)
+	repo := newFakeBacktestResultRepo(results...)
+	submitter := &fakeFeedbackSubmitter{failFor: map[uuid.UUID]bool{results[0].ID: true, results[1].ID: true}}
+	store := newFakeDeadLetterStore()
+	svc := newTestFeedbackService(submitter, repo)
+	svc.SetDeadLetterStore(store)
+
+	_, err := svc.SubmitBatch(context.Background(), 10)
+	require.NoError(t, err)
+	require.Len(t, store.letters, 2)
+
+	// Still down: each failure is recorded and backed off
+	delivered, err := svc.RetryDeadLetters(context.Background(), 10)
+	require.NoError(t, err)
+	assert.Equal(t, 0, delivered)
+	for _, letter := range store.letters {
+		assert.Equal(t, 2, letter.Attempts)
+		assert.True(t, letter.NextAttemptAt.After(time.Now()), "failed redelivery is backed off")
+	}
+
+	// Backed-off letters are not retried early
+	submitter.failFor = nil
+	submitter.submitted = nil
+	delivered, err = svc.RetryDeadLetters(context.Background(), 10)
+	require.NoError(t, err)
+	assert.Equal(t, 0, delivered)
+	assert.Empty(t, submitter.submitted)
+
+	// Once the backoff has elapsed and the service is back, the store drains
+	for _, letter := range store.letters {
+		letter.NextAttemptAt = time.Now().Add(-time.Second)
+	}
+	delivered, err = svc.RetryDeadLetters(context.Background(), 10)
+	require.NoError(t, err)
+	assert.Equal(t, 2, delivered)
+	assert.Empty(t, store.letters)
+	assert.ElementsMatch(t, []uuid.UUID{results[0].ID, results[1].ID}, submitter.submitted)
+	assert.Equal(t, 0.0, gaugeValue(t, ml.MLFeedbackDeadLetterDepth))
+}
+
+func TestDeadLetterBackoff(t *testing.T) {
+	assert.Equal(t, time.Minute, deadLetterBackoff(1))
+	assert.Equal(t, 2*time.Minute, deadLetterBackoff(2))
+	assert.Equal(t, 32*time.Minute, deadLetterBackoff(6))
+	assert.Equal(t, time.Hour, deadLetterBackoff(7))
+	assert.Equal(t, time.Hour, deadLetterBackoff(50))
+}
+
+func mustJSON(t *testing.T, v any) string {
+	t.Helper()
+	data, err := json.Marshal(v)
+	require.NoError(t, err)
+	return string(data)
+}
+
+func gaugeValue(t *testing.T, gauge prometheus.Gauge) float64 {
+	t.Helper()
+	var m dto.Metric
+	require.NoError(t, gauge.Write(&m))
+	return m.GetGauge().GetValue()
+}
@@ -19,6 +19,7 @@ type MLOrchestratorService struct {
 	strategyGenerator *StrategyGeneratorService
 	mlFeedback        *MLFeedbackService
 	strategyEvaluator *StrategyEvaluatorService
+	decayEvaluator    *PerformanceDecayEvaluator
 	mlClient          *ml.CachedMLClient
 	predictionRepo    repository.PredictionRepository
 	logger            *logrus.Logger
@@ -43,6 +44,12 @@ func NewMLOrchestratorService(
 	}
 }
 
+// SetDecayEvaluator enables deactivation of strategies whose live
+// performance has decayed as part of the discovery pipeline
+func (o *MLOrchestratorService) SetDecayEvaluator(evaluator *PerformanceDecayEvaluator) {
+	o.decayEvaluator = evaluator
+}
+
 // PipelineReport represents the result of a discovery pipeline run
 type PipelineReport struct {
 	RunID              uuid.UUID
@@ -51,6 +58,9 @@ type PipelineReport struct {
 	DeactivatedCount   int
 	FeedbackSubmitted  int
 	RetrainingTriggered bool
+	DryRun             bool
+	// WouldActivate lists the generated strategies a dry run would have activated
+	WouldActivate      []uuid.UUID
 	TopStrategies      []*StrategyEvaluation
 	Duration           time.Duration
 	CompletedAt        time.Time
@@ -65,6 +75,9 @@ type DiscoveryConfig struct {
 	DeactivateThreshold float64
 	SubmitFeedback      bool
 	TriggerRetraining   bool
+	// DryRun generates and backtests strategies but saves, activates,
+	// deactivates and submits nothing; TopStrategies ranks the candidates
+	DryRun bool
 }
 
 // RunStrategyDiscoveryPipeline executes full ML-driven strategy discovery
@@ -76,16 +89,18 @@ func (o *MLOrchestratorService) RunStrategyDiscoveryPipeline(ctx context.Context
 		"run_id":         runID,
 		"generate_count": config.GenerateCount,
 		"risk_level":     config.RiskLevel,
+		"dry_run":        config.DryRun,
 	}).Info("Starting strategy discovery pipeline")
 
 	report := &PipelineReport{
-		RunID: runID,
+		RunID:  runID,
+		DryRun: config.DryRun,
 	}
 
 	// Step 1: Submit backtest feedback
 	var feedbackCount int
 	var err error
-	if config.SubmitFeedback {
+	if config.SubmitFeedback && !config.DryRun {
 		feedbackCount, err = o.mlFeedback.SubmitBatch(ctx, 100)
 		if err != nil {
 			o.logger.WithError(err).Warn("Failed to submit feedback, continuing pipeline")
@@ -95,7 +110,7 @@ func (o *MLOrchestratorService) RunStrategyDiscoveryPipeline(ctx context.Context
 	}
 
 	// Step 2: Trigger retraining if sufficient feedback
-	if config.TriggerRetraining && feedbackCount >= 20 {
+	if config.TriggerRetraining && !config.DryRun && feedbackCount >= 20 {
 		trainingConfig := ml.TrainingConfig{
 			ModelType:            "ensemble",
 			Epochs:               50,
@@ -129,6 +144,10 @@ func (o *MLOrchestratorService) RunStrategyDiscoveryPipeline(ctx context.Context
 	report.GeneratedCount = len(generatedStrategies)
 	o.logger.WithField("generated_count", len(generatedStrategies)).Info("Generated new strategies")
 
+	if config.DryRun {
+		return o.completeDryRun(ctx, report, generatedStrategies, start), nil
+	}
+
 	// Step 4: Evaluate and activate top performers
 	activatedIDs, err := o.strategyGenerator.ActivateTopStrategies(ctx, generatedStrategies)
 	if err != nil {
@@ -145,6 +164,16 @@ func (o *MLOrchestratorService) RunStrategyDiscoveryPipeline(ctx context.Context
 	report.DeactivatedCount = len(deactivatedIDs)
 	o.logger.WithField("deactivated_count", len(deactivatedIDs)).Info("Deactivated underperformers")
 
+	// Step 5b: Deactivate strategies whose live performance has decayed
+	if o.decayEvaluator != nil {
+		decayedIDs, err := o.decayEvaluator.DeactivateDecayed(ctx)
+		if err != nil {
+			o.logger.WithError(err).Warn("Failed to deactivate decayed strategies")
+		}
+		report.DeactivatedCount += len(decayedIDs)
+		o.logger.WithField("decayed_count", len(decayedIDs)).Info("Deactivated strategies with decayed live performance")
+	}
+
 	// Step 6: Get final rankings
 	topStrategies, err := o.strategyEvaluator.GetTopPerformers(ctx, 10)
 	if err != nil {
@@ -167,6 +196,29 @@ func (o *MLOrchestratorService) RunStrategyDiscoveryPipeline(ctx context.Context
 	return report, nil
 }
 
+// completeDryRun ranks the generated strategies without persisting them and
+// records which would have been activated
+func (o *MLOrchestratorService) completeDryRun(ctx context.Context, report *PipelineReport, generated []*ml.GeneratedStrategy, start time.Time) *PipelineReport {
+	report.TopStrategies = o.strategyGenerator.PreviewTopStrategies(ctx, generated)
+	for _, preview := range report.TopStrategies {
+		if o.strategyGenerator.activationRejection(preview.BacktestMetrics) == "" {
+			report.WouldActivate = append(report.WouldActivate, preview.StrategyID)
+		}
+	}
+
+	report.Duration = time.Since(start)
+	report.CompletedAt = time.Now()
+
+	o.logger.WithFields(logrus.Fields{
+		"run_id":         report.RunID,
+		"generated":      report.GeneratedCount,
+		"would_activate": len(report.WouldActivate),
+		"duration":       report.Duration,
+	}).Info("Strategy discovery dry run complete; nothing was saved or activated")
+
+	return report
+}
+
 // GetLivePredictions retrieves predictions for active races
 func (o *MLOrchestratorService) GetLivePredictions(ctx context.Context, raceID uuid.UUID, runnerIDs []uuid.UUID, strategyID uuid.UUID) ([]*ml.PredictionResult, error) {
 	o.logger.WithFields(logrus.Fields{
@@ -0,0 +1,194 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yourusername/clever-better/internal/config"
+	"github.com/yourusername/clever-better/internal/ml"
+	"github.com/yourusername/clever-better/internal/models"
+	"github.com/yourusername/clever-better/internal/repository"
+)
+
+// fakeGenerationClient returns a fixed set of generated strategies
+type fakeGenerationClient struct {
+	strategies []*ml.GeneratedStrategy
+}
+
+func (c *fakeGenerationClient) GenerateStrategy(ctx context.Context, constraints ml.StrategyConstraints) ([]*ml.GeneratedStrategy, error) {
+	return c.strategies, nil
+}
+
+// recordingStrategyRepo stores strategies in memory and counts writes
+type recordingStrategyRepo struct {
+	strategies map[uuid.UUID]*models.Strategy
+	creates    int
+	updates    int
+	deletes    int
+}
+
+func (r *recordingStrategyRepo) Create(ctx context.Context, strategy *models.Strategy) error {
+	if r.strategies == nil {
+		r.strategies = make(map[uuid.UUID]*models.Strategy)
+	}
+	r.strategies[strategy.ID] = strategy
+	r.creates++
+	return nil
+}
+
+func (r *recordingStrategyRepo) GetByID(ctx context.Context, id uuid.UUID) (*models.Strategy, error) {
+	if strategy, ok := r.strategies[id]; ok {
+		return strategy, nil
+	}
+	return nil, models.ErrNotFound
+}
+
+func (r *recordingStrategyRepo) GetByName(ctx context.Context, name string) (*models.Strategy, error) {
+	return nil, models.ErrNotFound
+}
+
+func (r *recordingStrategyRepo) GetActive(ctx context.Context) ([]*models.Strategy, error) {
+	return nil, nil
+}
+
+func (r *recordingStrategyRepo) GetAll(ctx context.Context) ([]*models.Strategy, error) {
+	return nil, nil
+}
+
+func (r *recordingStrategyRepo) GetArchived(ctx context.Context) ([]*models.Strategy, error) {
+	return nil, nil
+}
+
+func (r *recordingStrategyRepo) Update(ctx context.Context, strategy *models.Strategy) error {
+	r.updates++
+	return nil
+}
+
+func (r *recordingStrategyRepo) Delete(ctx context.Context, id uuid.UUID) error {
+	r.deletes++
+	return nil
+}
+
+func (r *recordingStrategyRepo) Archive(ctx context.Context, id uuid.UUID) error {
+	return nil
+}
+
+func (r *recordingStrategyRepo) Unarchive(ctx context.Context, id uuid.UUID) error {
+	return nil
+}
+
+// recordingBacktestRepo serves top backtest results and counts writes
+type recordingBacktestRepo struct {
+	top    []*models.BacktestResult
+	writes int
+}
+
+func (r *recordingBacktestRepo) SaveResult(ctx context.Context, result *models.BacktestResult) error {
+	r.writes++
+	return nil
+}
+
+func (r *recordingBacktestRepo) GetByStrategyID(ctx context.Context, strategyID uuid.UUID) ([]*models.BacktestResult, error) {
+	return nil, nil
+}
+
+func (r *recordingBacktestRepo) GetLatest(ctx context.Context, limit int) ([]*models.BacktestResult, error) {
+	return nil, nil
+}
+
+func (r *recordingBacktestRepo) GetByDateRange(ctx context.Context, start, end time.Time) ([]*models.BacktestResult, error) {
+	return nil, nil
+}
+
+func (r *recordingBacktestRepo) GetTopPerforming(ctx context.Context, limit int) ([]*models.BacktestResult, error) {
+	return r.top, nil
+}
+
+func (r *recordingBacktestRepo) GetRecentUnprocessed(ctx context.Context, limit int) ([]*models.BacktestResult, error) {
+	return nil, nil
+}
+
+func (r *recordingBacktestRepo) GetUnprocessedSince(ctx context.Context, since time.Time, limit int) ([]*models.BacktestResult, error) {
+	return nil, nil
+}
+
+func (r *recordingBacktestRepo) MarkAsProcessed(ctx context.Context, resultID uuid.UUID) error {
+	r.writes++
+	return nil
+}
+
+func (r *recordingBacktestRepo) UpdateCompositeScore(ctx context.Context, resultID uuid.UUID, score float64, recommendation string) error {
+	r.writes++
+	return nil
+}
+
+func (r *recordingBacktestRepo) GetByCompositeScoreRange(ctx context.Context, minScore, maxScore float64, limit int) ([]*models.BacktestResult, error) {
+	return nil, nil
+}
+
+func (r *recordingBacktestRepo) GetByTag(ctx context.Context, tags []string, match repository.TagMatch) ([]*models.BacktestResult, error) {
+	return nil, nil
+}
+
+func generatedStrategy(sharpe, roi, winRate, confidence float64) *ml.GeneratedStrategy {
+	return &ml.GeneratedStrategy{
+		StrategyID:      uuid.New(),
+		Parameters:      map[string]float64{"min_edge_threshold": 0.05, "kelly_fraction": confidence / 2},
+		Confidence:      confidence,
+		ExpectedReturn:  roi,
+		ExpectedSharpe:  sharpe,
+		ExpectedWinRate: winRate,
+	}
+}
+
+func TestDiscoveryDryRunRanksWithoutPersisting(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	strong := generatedStrategy(2.0, 0.2, 0.6, 0.9)
+	weak := generatedStrategy(0.5, 0.05, 0.5, 0.5)
+	middling := generatedStrategy(1.2, 0.1, 0.55, 0.7)
+
+	strategyRepo := &recordingStrategyRepo{}
+	backtestRepo := &recordingBacktestRepo{top: []*models.BacktestResult{{ID: uuid.New(), CompositeScore: 0.7}}}
+	// With no database the generator falls back to the ML estimates
+	generator := NewStrategyGeneratorService(
+		&fakeGenerationClient{strategies: []*ml.GeneratedStrategy{weak, strong, middling}},
+		strategyRepo, backtestRepo, nil, logger,
+	)
+	orchestrator := NewMLOrchestratorService(generator, nil, nil, nil, nil, logger)
+	orchestrator.SetDecayEvaluator(NewPerformanceDecayEvaluator(strategyRepo, nil, config.PerformanceDecayConfig{Enabled: true}, logger))
+
+	report, err := orchestrator.RunStrategyDiscoveryPipeline(context.Background(), DiscoveryConfig{
+		GenerateCount:     3,
+		RiskLevel:         "medium",
+		SubmitFeedback:    true,
+		TriggerRetraining: true,
+		DryRun:            true,
+	})
+	require.NoError(t, err)
+
+	assert.True(t, report.DryRun)
+	assert.Equal(t, 3, report.GeneratedCount)
+	require.Len(t, report.TopStrategies, 3)
+	for i, expected := range []*ml.GeneratedStrategy{strong, middling, weak} {
+		assert.Equal(t, expected.StrategyID, report.TopStrategies[i].StrategyID)
+		assert.Equal(t, i+1, report.TopStrategies[i].Rank)
+	}
+	assert.ElementsMatch(t, []uuid.UUID{strong.StrategyID, middling.StrategyID}, report.WouldActivate)
+
+	assert.Zero(t, report.ActivatedCount)
+	assert.Zero(t, report.DeactivatedCount)
+	assert.Zero(t, report.FeedbackSubmitted)
+	assert.False(t, report.RetrainingTriggered)
+	assert.Zero(t, strategyRepo.creates, "dry run must not save strategies")
+	assert.Zero(t, strategyRepo.updates, "dry run must not activate or deactivate strategies")
+	assert.Zero(t, strategyRepo.deletes)
+	assert.Zero(t, backtestRepo.writes)
+}
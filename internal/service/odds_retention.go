@@ -0,0 +1,169 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/yourusername/clever-better/internal/config"
+	"github.com/yourusername/clever-better/internal/metrics"
+	"github.com/yourusername/clever-better/internal/models"
+	"github.com/yourusername/clever-better/internal/repository"
+)
+
+// OddsSnapshotsTable is the hypertable odds retention applies to
+const OddsSnapshotsTable = "odds_snapshots"
+
+// OddsRetentionResult reports what a retention run did, or would have done
+// in dry-run. Compression savings are only known once a chunk is compressed,
+// so a dry run counts dropped bytes alone.
+type OddsRetentionResult struct {
+	DryRun           bool
+	ChunksCompressed int
+	ChunksDropped    int
+	BytesReclaimed   int64
+}
+
+// OddsRetentionService enforces the odds_snapshots retention policy: chunks
+// older than CompressAfterDays are compressed and chunks older than
+// RetentionDays are dropped. A chunk's age is measured from the end of its
+// time range, so no chunk holding recent rows is touched.
+type OddsRetentionService struct {
+	chunkRepo repository.ChunkRepository
+	config    config.OddsRetentionConfig
+	logger    *logrus.Logger
+	now       func() time.Time
+}
+
+// NewOddsRetentionService creates a new odds retention service
+func NewOddsRetentionService(
+	chunkRepo repository.ChunkRepository,
+	cfg config.OddsRetentionConfig,
+	logger *logrus.Logger,
+) *OddsRetentionService {
+	return &OddsRetentionService{
+		chunkRepo: chunkRepo,
+		config:    cfg,
+		logger:    logger,
+		now:       time.Now,
+	}
+}
+
+// Enforce applies the retention policy once. Chunks due to be dropped are
+// dropped first rather than compressed. A failure stops the run; chunks
+// already handled stay handled and the rest are picked up by the next run.
+func (s *OddsRetentionService) Enforce(ctx context.Context) (*OddsRetentionResult, error) {
+	result := &OddsRetentionResult{DryRun: s.config.DryRun}
+	if s.config.CompressAfterDays <= 0 && s.config.RetentionDays <= 0 {
+		return result, nil
+	}
+
+	now := s.now()
+	dropBefore := now.AddDate(0, 0, -s.config.RetentionDays)
+	compressBefore := now.AddDate(0, 0, -s.config.CompressAfterDays)
+	cutoff := compressBefore
+	if s.config.CompressAfterDays <= 0 {
+		cutoff = dropBefore
+	}
+
+	chunks, err := s.chunkRepo.GetChunks(ctx, OddsSnapshotsTable, cutoff)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get odds chunks: %w", err)
+	}
+
+	var toDrop, toCompress []*models.Chunk
+	for _, chunk := range chunks {
+		switch {
+		case s.config.RetentionDays > 0 && !chunk.RangeEnd.After(dropBefore):
+			toDrop = append(toDrop, chunk)
+		case s.config.CompressAfterDays > 0 && !chunk.Compressed:
+			toCompress = append(toCompress, chunk)
+		}
+	}
+
+	if err := s.drop(ctx, toDrop, dropBefore, result); err != nil {
+		return result, err
+	}
+	if err := s.compress(ctx, toCompress, result); err != nil {
+		return result, err
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"dry_run":           result.DryRun,
+		"chunks_compressed": result.ChunksCompressed,
+		"chunks_dropped":    result.ChunksDropped,
+		"bytes_reclaimed":   result.BytesReclaimed,
+	}).Info("Odds retention applied")
+
+	return result, nil
+}
+
+// drop drops the chunks ending at or before dropBefore
+func (s *OddsRetentionService) drop(ctx context.Context, chunks []*models.Chunk, dropBefore time.Time, result *OddsRetentionResult) error {
+	if len(chunks) == 0 {
+		return nil
+	}
+
+	sizes := make(map[string]int64, len(chunks))
+	for _, chunk := range chunks {
+		sizes[chunk.Name] = chunk.SizeBytes
+	}
+
+	if s.config.DryRun {
+		for _, chunk := range chunks {
+			s.logger.WithFields(logrus.Fields{
+				"chunk":     chunk.Name,
+				"range_end": chunk.RangeEnd,
+				"bytes":     chunk.SizeBytes,
+			}).Info("Dry run: would drop odds chunk")
+			result.ChunksDropped++
+			result.BytesReclaimed += chunk.SizeBytes
+		}
+		return nil
+	}
+
+	dropped, err := s.chunkRepo.DropChunks(ctx, OddsSnapshotsTable, dropBefore)
+	if err != nil {
+		return fmt.Errorf("failed to drop odds chunks: %w", err)
+	}
+
+	var reclaimed int64
+	for _, name := range dropped {
+		reclaimed += sizes[name]
+	}
+	result.ChunksDropped += len(dropped)
+	result.BytesReclaimed += reclaimed
+	metrics.RecordOddsRetention(metrics.RetentionDropped, len(dropped), reclaimed)
+	return nil
+}
+
+// compress compresses each chunk, counting the bytes saved
+func (s *OddsRetentionService) compress(ctx context.Context, chunks []*models.Chunk, result *OddsRetentionResult) error {
+	for _, chunk := range chunks {
+		if s.config.DryRun {
+			s.logger.WithFields(logrus.Fields{
+				"chunk":     chunk.Name,
+				"range_end": chunk.RangeEnd,
+				"bytes":     chunk.SizeBytes,
+			}).Info("Dry run: would compress odds chunk")
+			result.ChunksCompressed++
+			continue
+		}
+
+		compressed, err := s.chunkRepo.CompressChunk(ctx, OddsSnapshotsTable, chunk.Name)
+		if err != nil {
+			return fmt.Errorf("failed to compress odds chunk: %w", err)
+		}
+
+		reclaimed := chunk.SizeBytes - compressed
+		if reclaimed < 0 {
+			reclaimed = 0
+		}
+		result.ChunksCompressed++
+		result.BytesReclaimed += reclaimed
+		metrics.RecordOddsRetention(metrics.RetentionCompressed, 1, reclaimed)
+	}
+	return nil
+}
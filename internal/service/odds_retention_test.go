@@ -0,0 +1,120 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/clever-better/internal/config"
+	"github.com/yourusername/clever-better/internal/models"
+)
+
+// fakeChunkRepo holds chunks in memory, compressing each to a tenth of its size
+type fakeChunkRepo struct {
+	chunks     []*models.Chunk
+	compressed []string
+}
+
+func (r *fakeChunkRepo) GetChunks(ctx context.Context, hypertable string, endBefore time.Time) ([]*models.Chunk, error) {
+	var out []*models.Chunk
+	for _, chunk := range r.chunks {
+		if !chunk.RangeEnd.After(endBefore) {
+			copied := *chunk
+			out = append(out, &copied)
+		}
+	}
+	return out, nil
+}
+
+func (r *fakeChunkRepo) CompressChunk(ctx context.Context, hypertable, name string) (int64, error) {
+	for _, chunk := range r.chunks {
+		if chunk.Name == name {
+			chunk.Compressed = true
+			chunk.SizeBytes /= 10
+			r.compressed = append(r.compressed, name)
+			return chunk.SizeBytes, nil
+		}
+	}
+	return 0, nil
+}
+
+func (r *fakeChunkRepo) DropChunks(ctx context.Context, hypertable string, olderThan time.Time) ([]string, error) {
+	var kept []*models.Chunk
+	var dropped []string
+	for _, chunk := range r.chunks {
+		if !chunk.RangeEnd.After(olderThan) {
+			dropped = append(dropped, chunk.Name)
+			continue
+		}
+		kept = append(kept, chunk)
+	}
+	r.chunks = kept
+	return dropped, nil
+}
+
+func (r *fakeChunkRepo) names() []string {
+	names := make([]string, 0, len(r.chunks))
+	for _, chunk := range r.chunks {
+		names = append(names, chunk.Name)
+	}
+	return names
+}
+
+// retentionChunks returns weekly chunks ending 400, 100, 30 and 1 days ago
+func retentionChunks(now time.Time) *fakeChunkRepo {
+	chunk := func(name string, ageDays int) *models.Chunk {
+		end := now.AddDate(0, 0, -ageDays)
+		return &models.Chunk{Name: name, RangeStart: end.AddDate(0, 0, -7), RangeEnd: end, SizeBytes: 1000}
+	}
+	return &fakeChunkRepo{chunks: []*models.Chunk{
+		chunk("ancient", 400),
+		chunk("old", 100),
+		chunk("month", 30),
+		chunk("recent", 1),
+	}}
+}
+
+func TestOddsRetentionCompressesAndDrops(t *testing.T) {
+	now := time.Date(2024, 6, 1, 3, 0, 0, 0, time.UTC)
+	repo := retentionChunks(now)
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	svc := NewOddsRetentionService(repo, config.OddsRetentionConfig{CompressAfterDays: 7, RetentionDays: 365}, logger)
+	svc.now = func() time.Time { return now }
+
+	result, err := svc.Enforce(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.ChunksDropped)
+	assert.Equal(t, 2, result.ChunksCompressed)
+	assert.Equal(t, int64(1000+900+900), result.BytesReclaimed)
+	assert.Equal(t, []string{"old", "month", "recent"}, repo.names())
+	assert.Equal(t, []string{"old", "month"}, repo.compressed, "the recent chunk is left uncompressed")
+
+	// A second run has nothing left to do
+	result, err = svc.Enforce(context.Background())
+	require.NoError(t, err)
+	assert.Zero(t, result.ChunksCompressed+result.ChunksDropped)
+}
+
+func TestOddsRetentionDryRun(t *testing.T) {
+	now := time.Date(2024, 6, 1, 3, 0, 0, 0, time.UTC)
+	repo := retentionChunks(now)
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	svc := NewOddsRetentionService(repo, config.OddsRetentionConfig{CompressAfterDays: 7, RetentionDays: 90, DryRun: true}, logger)
+	svc.now = func() time.Time { return now }
+
+	result, err := svc.Enforce(context.Background())
+	require.NoError(t, err)
+	assert.True(t, result.DryRun)
+	assert.Equal(t, 2, result.ChunksDropped)
+	assert.Equal(t, 1, result.ChunksCompressed)
+	assert.Equal(t, int64(2000), result.BytesReclaimed, "only dropped bytes are known before compressing")
+	assert.Len(t, repo.chunks, 4, "nothing is dropped")
+	assert.Empty(t, repo.compressed, "nothing is compressed")
+}
@@ -0,0 +1,172 @@
+// Package service provides live performance decay detection.
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"github.com/yourusername/clever-better/internal/config"
+	applogger "github.com/yourusername/clever-better/internal/logger"
+	"github.com/yourusername/clever-better/internal/metrics"
+	"github.com/yourusername/clever-better/internal/models"
+	"github.com/yourusername/clever-better/internal/repository"
+)
+
+// DecayAssessment summarises a strategy's recent live performance
+type DecayAssessment struct {
+	StrategyID   uuid.UUID
+	StrategyName string
+	Days         int
+	DaysBelow    int
+	TotalBets    int
+	ROI          float64
+	Decayed      bool
+	Reason       string
+}
+
+// PerformanceDecayEvaluator deactivates live strategies whose real
+// performance has stayed below a floor for a sustained window. Unlike
+// StrategyEvaluatorService.DeactivateUnderperformers, which scores backtests,
+// it only looks at recorded live StrategyPerformance.
+type PerformanceDecayEvaluator struct {
+	strategyRepo   repository.StrategyRepository
+	perfRepo       repository.StrategyPerformanceRepository
+	config         config.PerformanceDecayConfig
+	minSampleBets  int
+	strategyLogger *applogger.StrategyLogger
+	logger         *logrus.Logger
+	now            func() time.Time
+}
+
+// NewPerformanceDecayEvaluator creates a new performance decay evaluator
+func NewPerformanceDecayEvaluator(
+	strategyRepo repository.StrategyRepository,
+	perfRepo repository.StrategyPerformanceRepository,
+	cfg config.PerformanceDecayConfig,
+	logger *logrus.Logger,
+) *PerformanceDecayEvaluator {
+	return &PerformanceDecayEvaluator{
+		strategyRepo:   strategyRepo,
+		perfRepo:       perfRepo,
+		config:         cfg,
+		strategyLogger: applogger.NewStrategyLogger(logger),
+		logger:         logger,
+		now:            time.Now,
+	}
+}
+
+// SetMinSampleBets sets the bot-wide minimum sample below which live metrics
+// are not trusted. Strategies need at least this many bets in the window, as
+// well as MinBets, before they can be deactivated.
+func (e *PerformanceDecayEvaluator) SetMinSampleBets(bets int) {
+	e.minSampleBets = bets
+}
+
+// Assess evaluates a strategy's daily performance over the configured window.
+// A strategy has decayed when it has at least MinDays of data and enough bets
+// (MinBets and the minimum sample), and every day in the window is below the
+// ROI or Sharpe floor.
+func (e *PerformanceDecayEvaluator) Assess(ctx context.Context, strategy *models.Strategy) (*DecayAssessment, error) {
+	end := e.now()
+	start := end.AddDate(0, 0, -e.config.WindowDays)
+
+	days, err := e.perfRepo.GetDailyRollup(ctx, strategy.ID, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get daily performance: %w", err)
+	}
+
+	assessment := &DecayAssessment{
+		StrategyID:   strategy.ID,
+		StrategyName: strategy.Name,
+		Days:         len(days),
+	}
+
+	netProfit := 0.0
+	roiSum := 0.0
+	for _, day := range days {
+		assessment.TotalBets += day.TotalBets
+		netProfit += day.NetProfit
+		roiSum += day.ROI
+		if e.belowFloor(day) {
+			assessment.DaysBelow++
+		}
+	}
+	if len(days) > 0 {
+		assessment.ROI = roiSum / float64(len(days))
+	}
+
+	minBets := max(e.config.MinBets, e.minSampleBets)
+	if len(days) == 0 || len(days) < e.config.MinDays || assessment.TotalBets < minBets {
+		assessment.Reason = "insufficient live data"
+		return assessment, nil
+	}
+
+	if assessment.DaysBelow == len(days) {
+		assessment.Decayed = true
+		assessment.Reason = fmt.Sprintf(
+			"below performance floor on all %d days in the last %d (avg ROI %.4f, net %.2f)",
+			len(days), e.config.WindowDays, assessment.ROI, netProfit,
+		)
+	}
+
+	return assessment, nil
+}
+
+// belowFloor reports whether a day's ROI or Sharpe ratio is under the floor
+func (e *PerformanceDecayEvaluator) belowFloor(day *models.StrategyPerformance) bool {
+	if day.ROI < e.config.MinROI {
+		return true
+	}
+	return day.SharpeRatio != nil && *day.SharpeRatio < e.config.MinSharpe
+}
+
+// DeactivateDecayed deactivates every active strategy whose live performance
+// has decayed and returns the IDs that were deactivated
+func (e *PerformanceDecayEvaluator) DeactivateDecayed(ctx context.Context) ([]uuid.UUID, error) {
+	if !e.config.Enabled {
+		return nil, nil
+	}
+
+	strategies, err := e.strategyRepo.GetActive(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get active strategies: %w", err)
+	}
+
+	deactivatedIDs := make([]uuid.UUID, 0)
+	for _, strategy := range strategies {
+		assessment, err := e.Assess(ctx, strategy)
+		if err != nil {
+			e.logger.WithError(err).WithField("strategy_id", strategy.ID).Error("Failed to assess live performance")
+			continue
+		}
+		if !assessment.Decayed {
+			continue
+		}
+
+		strategy.Active = false
+		strategy.UpdatedAt = e.now()
+		if err := e.strategyRepo.Update(ctx, strategy); err != nil {
+			e.logger.WithError(err).WithField("strategy_id", strategy.ID).Error("Failed to deactivate strategy")
+			continue
+		}
+
+		deactivatedIDs = append(deactivatedIDs, strategy.ID)
+		metrics.RecordStrategyDeactivation(strategy.ID.String(), strategy.Name, "performance_decay")
+		e.strategyLogger.LogStrategyDeactivation(strategy.ID.String(), strategy.Name, assessment.Reason)
+		e.logger.WithFields(logrus.Fields{
+			"strategy_id":   strategy.ID,
+			"strategy_name": strategy.Name,
+			"days":          assessment.Days,
+			"total_bets":    assessment.TotalBets,
+			"avg_roi":       assessment.ROI,
+			"min_roi":       e.config.MinROI,
+			"min_sharpe":    e.config.MinSharpe,
+		}).Warn("Deactivated strategy after sustained live performance decay")
+	}
+
+	return deactivatedIDs, nil
+}
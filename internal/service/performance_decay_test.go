@@ -0,0 +1,176 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/clever-better/internal/config"
+	"github.com/yourusername/clever-better/internal/models"
+	"github.com/yourusername/clever-better/internal/repository"
+)
+
+// fakeDecayStrategyRepo serves active strategies and records updates
+type fakeDecayStrategyRepo struct {
+	repository.StrategyRepository
+	strategies []*models.Strategy
+	updated    []uuid.UUID
+}
+
+func (r *fakeDecayStrategyRepo) GetActive(ctx context.Context) ([]*models.Strategy, error) {
+	active := make([]*models.Strategy, 0, len(r.strategies))
+	for _, s := range r.strategies {
+		if s.Active {
+			active = append(active, s)
+		}
+	}
+	return active, nil
+}
+
+func (r *fakeDecayStrategyRepo) Update(ctx context.Context, strategy *models.Strategy) error {
+	r.updated = append(r.updated, strategy.ID)
+	return nil
+}
+
+// fakeDailyPerfRepo serves daily performance rollups per strategy
+type fakeDailyPerfRepo struct {
+	repository.StrategyPerformanceRepository
+	daily map[uuid.UUID][]*models.StrategyPerformance
+}
+
+func (r *fakeDailyPerfRepo) GetDailyRollup(ctx context.Context, strategyID uuid.UUID, start, end time.Time) ([]*models.StrategyPerformance, error) {
+	out := make([]*models.StrategyPerformance, 0)
+	for _, day := range r.daily[strategyID] {
+		if day.Time.Before(start) || day.Time.After(end) {
+			continue
+		}
+		out = append(out, day)
+	}
+	return out, nil
+}
+
+func dailyPerformance(strategyID uuid.UUID, end time.Time, rois ...float64) []*models.StrategyPerformance {
+	days := make([]*models.StrategyPerformance, len(rois))
+	for i, roi := range rois {
+		days[i] = &models.StrategyPerformance{
+			Time:       end.AddDate(0, 0, -i),
+			StrategyID: strategyID,
+			TotalBets:  10,
+			ROI:        roi,
+			NetProfit:  roi * 100,
+		}
+	}
+	return days
+}
+
+func newTestDecayEvaluator(strategies *fakeDecayStrategyRepo, perf *fakeDailyPerfRepo, now time.Time) *PerformanceDecayEvaluator {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+	evaluator := NewPerformanceDecayEvaluator(strategies, perf, config.PerformanceDecayConfig{
+		Enabled:    true,
+		MinROI:     -0.05,
+		MinSharpe:  0,
+		WindowDays: 7,
+		MinDays:    5,
+		MinBets:    40,
+	}, logger)
+	evaluator.now = func() time.Time { return now }
+	return evaluator
+}
+
+func TestDeactivateDecayedStrategies(t *testing.T) {
+	now := time.Date(2024, 6, 30, 12, 0, 0, 0, time.UTC)
+	decaying := &models.Strategy{ID: uuid.New(), Name: "decaying", Active: true}
+	healthy := &models.Strategy{ID: uuid.New(), Name: "healthy", Active: true}
+	recovering := &models.Strategy{ID: uuid.New(), Name: "recovering", Active: true}
+
+	strategies := &fakeDecayStrategyRepo{strategies: []*models.Strategy{decaying, healthy, recovering}}
+	perf := &fakeDailyPerfRepo{daily: map[uuid.UUID][]*models.StrategyPerformance{
+		decaying.ID:   dailyPerformance(decaying.ID, now, -0.10, -0.08, -0.12, -0.09, -0.20, -0.07),
+		healthy.ID:    dailyPerformance(healthy.ID, now, 0.04, 0.02, -0.01, 0.05, 0.03, 0.01),
+		recovering.ID: dailyPerformance(recovering.ID, now, 0.02, -0.08, -0.12, -0.09, -0.20, -0.07),
+	}}
+
+	evaluator := newTestDecayEvaluator(strategies, perf, now)
+	deactivated, err := evaluator.DeactivateDecayed(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, []uuid.UUID{decaying.ID}, deactivated)
+	assert.Equal(t, []uuid.UUID{decaying.ID}, strategies.updated)
+	assert.False(t, decaying.Active)
+	assert.True(t, healthy.Active)
+	assert.True(t, recovering.Active, "a single day above the floor breaks the sustained window")
+}
+
+func TestDecayAssessmentRequiresEnoughData(t *testing.T) {
+	now := time.Date(2024, 6, 30, 12, 0, 0, 0, time.UTC)
+	strategy := &models.Strategy{ID: uuid.New(), Name: "new", Active: true}
+	perf := &fakeDailyPerfRepo{daily: map[uuid.UUID][]*models.StrategyPerformance{
+		strategy.ID: dailyPerformance(strategy.ID, now, -0.30, -0.30, -0.30),
+	}}
+
+	evaluator := newTestDecayEvaluator(&fakeDecayStrategyRepo{}, perf, now)
+	assessment, err := evaluator.Assess(context.Background(), strategy)
+	require.NoError(t, err)
+	assert.False(t, assessment.Decayed)
+	assert.Equal(t, 3, assessment.DaysBelow)
+}
+
+func TestDecaySkipsUnderSampledStrategies(t *testing.T) {
+	now := time.Date(2024, 6, 30, 12, 0, 0, 0, time.UTC)
+	strategy := &models.Strategy{ID: uuid.New(), Name: "thin", Active: true}
+	strategies := &fakeDecayStrategyRepo{strategies: []*models.Strategy{strategy}}
+	perf := &fakeDailyPerfRepo{daily: map[uuid.UUID][]*models.StrategyPerformance{
+		// Sixty bets clear MinBets but not the minimum sample
+		strategy.ID: dailyPerformance(strategy.ID, now, -0.10, -0.10, -0.10, -0.10, -0.10, -0.10),
+	}}
+
+	evaluator := newTestDecayEvaluator(strategies, perf, now)
+	evaluator.SetMinSampleBets(100)
+	deactivated, err := evaluator.DeactivateDecayed(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, deactivated)
+	assert.Empty(t, strategies.updated)
+	assert.True(t, strategy.Active)
+
+	evaluator.SetMinSampleBets(60)
+	deactivated, err = evaluator.DeactivateDecayed(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{strategy.ID}, deactivated, "enough bets let decay apply")
+}
+
+func TestDecayUsesSharpeFloor(t *testing.T) {
+	now := time.Date(2024, 6, 30, 12, 0, 0, 0, time.UTC)
+	strategy := &models.Strategy{ID: uuid.New(), Name: "volatile", Active: true}
+	days := dailyPerformance(strategy.ID, now, 0.01, 0.01, 0.01, 0.01, 0.01)
+	negative := -0.5
+	for _, day := range days {
+		day.SharpeRatio = &negative
+	}
+	perf := &fakeDailyPerfRepo{daily: map[uuid.UUID][]*models.StrategyPerformance{strategy.ID: days}}
+
+	evaluator := newTestDecayEvaluator(&fakeDecayStrategyRepo{}, perf, now)
+	assessment, err := evaluator.Assess(context.Background(), strategy)
+	require.NoError(t, err)
+	assert.True(t, assessment.Decayed)
+}
+
+func TestDeactivateDecayedDisabled(t *testing.T) {
+	now := time.Date(2024, 6, 30, 12, 0, 0, 0, time.UTC)
+	strategy := &models.Strategy{ID: uuid.New(), Name: "decaying", Active: true}
+	strategies := &fakeDecayStrategyRepo{strategies: []*models.Strategy{strategy}}
+	perf := &fakeDailyPerfRepo{daily: map[uuid.UUID][]*models.StrategyPerformance{
+		strategy.ID: dailyPerformance(strategy.ID, now, -0.1, -0.1, -0.1, -0.1, -0.1),
+	}}
+
+	evaluator := newTestDecayEvaluator(strategies, perf, now)
+	evaluator.config.Enabled = false
+	deactivated, err := evaluator.DeactivateDecayed(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, deactivated)
+	assert.True(t, strategy.Active)
+}
@@ -0,0 +1,116 @@
+// Package service provides strategy P&L attribution.
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"github.com/yourusername/clever-better/internal/models"
+	"github.com/yourusername/clever-better/internal/repository"
+)
+
+// StrategyAttribution splits a strategy's realized P&L into the expected
+// value its signals claimed when the bets were placed (edge) and the
+// remainder (variance, or luck). A residual that stays negative over many
+// bets suggests the model overstates its edge.
+type StrategyAttribution struct {
+	StrategyID   uuid.UUID `json:"strategy_id"`
+	StrategyName string    `json:"strategy_name,omitempty"`
+	Bets         int       `json:"bets"`
+	Stake        float64   `json:"stake"`
+	ExpectedPnL  float64   `json:"expected_pnl"`
+	RealizedPnL  float64   `json:"realized_pnl"`
+	// ResidualPnL is RealizedPnL - ExpectedPnL
+	ResidualPnL float64 `json:"residual_pnl"`
+	// UnattributedBets settled without a stored expected value. Their P&L is
+	// kept apart so expected and realized cover the same bets.
+	UnattributedBets int     `json:"unattributed_bets"`
+	UnattributedPnL  float64 `json:"unattributed_pnl"`
+}
+
+// PnLAttributionReporter builds per-strategy edge vs luck reports from
+// settled bets
+type PnLAttributionReporter struct {
+	betRepo      repository.BetRepository
+	strategyRepo repository.StrategyRepository
+	logger       *logrus.Logger
+}
+
+// NewPnLAttributionReporter creates a new P&L attribution reporter
+func NewPnLAttributionReporter(
+	betRepo repository.BetRepository,
+	strategyRepo repository.StrategyRepository,
+	logger *logrus.Logger,
+) *PnLAttributionReporter {
+	return &PnLAttributionReporter{
+		betRepo:      betRepo,
+		strategyRepo: strategyRepo,
+		logger:       logger,
+	}
+}
+
+// Report attributes the P&L of every bet settled between start and end,
+// one entry per strategy ordered by name. Expected P&L is the signal's
+// ExpectedValue as stored on the bet; realized P&L is the bet's net
+// ProfitLoss.
+func (r *PnLAttributionReporter) Report(ctx context.Context, start, end time.Time) ([]StrategyAttribution, error) {
+	bets, err := r.betRepo.GetSettledBets(ctx, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get settled bets: %w", err)
+	}
+
+	byStrategy := make(map[uuid.UUID]*StrategyAttribution)
+	for _, bet := range bets {
+		if bet.ProfitLoss == nil {
+			continue
+		}
+		attribution, ok := byStrategy[bet.StrategyID]
+		if !ok {
+			attribution = &StrategyAttribution{StrategyID: bet.StrategyID}
+			byStrategy[bet.StrategyID] = attribution
+		}
+
+		if bet.ExpectedValue == nil {
+			attribution.UnattributedBets++
+			attribution.UnattributedPnL += *bet.ProfitLoss
+			continue
+		}
+		attribution.Bets++
+		attribution.Stake += bet.Stake
+		attribution.ExpectedPnL += *bet.ExpectedValue
+		attribution.RealizedPnL += *bet.ProfitLoss
+	}
+
+	report := make([]StrategyAttribution, 0, len(byStrategy))
+	for id, attribution := range byStrategy {
+		strategy, err := r.strategyRepo.GetByID(ctx, id)
+		switch {
+		case err == nil:
+			attribution.StrategyName = strategy.Name
+		case !errors.Is(err, models.ErrNotFound):
+			return nil, fmt.Errorf("failed to get strategy %s: %w", id, err)
+		}
+		attribution.ResidualPnL = attribution.RealizedPnL - attribution.ExpectedPnL
+		report = append(report, *attribution)
+	}
+
+	sort.Slice(report, func(i, j int) bool {
+		if report[i].StrategyName != report[j].StrategyName {
+			return report[i].StrategyName < report[j].StrategyName
+		}
+		return report[i].StrategyID.String() < report[j].StrategyID.String()
+	})
+
+	r.logger.WithFields(logrus.Fields{
+		"settled_bets": len(bets),
+		"strategies":   len(report),
+	}).Debug("P&L attribution report built")
+
+	return report, nil
+}
@@ -0,0 +1,74 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/clever-better/internal/models"
+	"github.com/yourusername/clever-better/internal/repository"
+)
+
+// fakeSettledBetRepo serves a fixed set of settled bets
+type fakeSettledBetRepo struct {
+	repository.BetRepository
+	bets []*models.Bet
+}
+
+func (r *fakeSettledBetRepo) GetSettledBets(ctx context.Context, start, end time.Time) ([]*models.Bet, error) {
+	return r.bets, nil
+}
+
+func settledBet(strategyID uuid.UUID, stake float64, expected *float64, profitLoss float64) *models.Bet {
+	return &models.Bet{
+		ID:            uuid.New(),
+		StrategyID:    strategyID,
+		Stake:         stake,
+		ExpectedValue: expected,
+		ProfitLoss:    &profitLoss,
+		Status:        models.BetStatusSettled,
+	}
+}
+
+func TestPnLAttributionSplitsExpectedAndRealized(t *testing.T) {
+	value := &models.Strategy{ID: uuid.New(), Name: "value"}
+	arb := &models.Strategy{ID: uuid.New(), Name: "arb"}
+	strategies := &recordingStrategyRepo{strategies: map[uuid.UUID]*models.Strategy{value.ID: value, arb.ID: arb}}
+	ev := func(v float64) *float64 { return &v }
+
+	bets := &fakeSettledBetRepo{bets: []*models.Bet{
+		// value claims 1.5 + 0.5 of edge, wins 20 and loses 10
+		settledBet(value.ID, 10, ev(1.5), 20),
+		settledBet(value.ID, 10, ev(0.5), -10),
+		// A bet placed before expected values were stored stays apart
+		settledBet(value.ID, 10, nil, 7),
+		// arb claims 2 of edge and loses both bets
+		settledBet(arb.ID, 5, ev(1), -5),
+		settledBet(arb.ID, 5, ev(1), -5),
+	}}
+
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+	report, err := NewPnLAttributionReporter(bets, strategies, logger).Report(context.Background(), time.Time{}, time.Now())
+	require.NoError(t, err)
+	require.Len(t, report, 2)
+
+	assert.Equal(t, "arb", report[0].StrategyName, "ordered by name")
+	assert.Equal(t, 2, report[0].Bets)
+	assert.InDelta(t, 2.0, report[0].ExpectedPnL, 1e-9)
+	assert.InDelta(t, -10.0, report[0].RealizedPnL, 1e-9)
+	assert.InDelta(t, -12.0, report[0].ResidualPnL, 1e-9)
+
+	assert.Equal(t, "value", report[1].StrategyName)
+	assert.Equal(t, 2, report[1].Bets)
+	assert.InDelta(t, 20.0, report[1].Stake, 1e-9)
+	assert.InDelta(t, 2.0, report[1].ExpectedPnL, 1e-9)
+	assert.InDelta(t, 10.0, report[1].RealizedPnL, 1e-9)
+	assert.InDelta(t, 8.0, report[1].ResidualPnL, 1e-9)
+	assert.Equal(t, 1, report[1].UnattributedBets)
+	assert.InDelta(t, 7.0, report[1].UnattributedPnL, 1e-9)
+}
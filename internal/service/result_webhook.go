@@ -0,0 +1,330 @@
+package service
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/sirupsen/logrus"
+
+	"github.com/yourusername/clever-better/internal/models"
+	"github.com/yourusername/clever-better/internal/repository"
+)
+
+// ResultWebhookPath is where result providers post race results
+const ResultWebhookPath = "/webhooks/results"
+
+// ResultWebhookSecretHeader carries the shared secret on each delivery
+const ResultWebhookSecretHeader = "X-Webhook-Secret"
+
+// maxResultWebhookBody bounds the size of a delivery
+const maxResultWebhookBody = 1 << 20
+
+// maxTrap is the highest trap number a greyhound race can have
+const maxTrap = 8
+
+// offTimeTolerance is how far a delivery's off time may be from the race's
+// scheduled start when the race is matched by track
+const offTimeTolerance = 15 * time.Minute
+
+// Result webhook response statuses
+const (
+	ResultWebhookStatusSettled   = "settled"
+	ResultWebhookStatusDuplicate = "duplicate"
+)
+
+var (
+	errWebhookRaceNotFound = errors.New("race not found")
+	errWebhookInvalid      = errors.New("invalid result")
+)
+
+// RaceSettler settles open bets once a race result is known.
+// BetSettlementService implements it.
+type RaceSettler interface {
+	SettleRace(ctx context.Context, result *models.RaceResult) error
+}
+
+// ResultWebhookPayload is the body a provider posts for one race. The race is
+// identified by race_id, or by track and off_time when the provider does not
+// know our IDs.
+type ResultWebhookPayload struct {
+	RaceID     string                  `json:"race_id"`
+	Track      string                  `json:"track"`
+	OffTime    time.Time               `json:"off_time"`
+	ResultTime *time.Time              `json:"result_time"`
+	Status     string                  `json:"status"`
+	Positions  []ResultWebhookPosition `json:"positions"`
+}
+
+// ResultWebhookPosition is one runner's finishing position. A position of 0
+// means the runner did not finish.
+type ResultWebhookPosition struct {
+	Trap     int     `json:"trap"`
+	Position int     `json:"position"`
+	SP       float64 `json:"sp"`
+}
+
+// ResultWebhookResponse is returned for every accepted delivery
+type ResultWebhookResponse struct {
+	Status string `json:"status"`
+	RaceID string `json:"race_id,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// ResultWebhookHandler accepts pushed race results, stores them and settles
+// the race's bets. A race that already has a result is reported as a
+// duplicate and left untouched, so providers may safely redeliver.
+type ResultWebhookHandler struct {
+	secret     string
+	raceRepo   repository.RaceRepository
+	runnerRepo repository.RunnerRepository
+	resultRepo repository.RaceResultRepository
+	settler    RaceSettler
+	logger     *logrus.Logger
+
+	// mu serialises the duplicate check and insert
+	mu sync.Mutex
+}
+
+// NewResultWebhookHandler creates a new result webhook handler
+func NewResultWebhookHandler(
+	secret string,
+	raceRepo repository.RaceRepository,
+	runnerRepo repository.RunnerRepository,
+	resultRepo repository.RaceResultRepository,
+	settler RaceSettler,
+	logger *logrus.Logger,
+) *ResultWebhookHandler {
+	return &ResultWebhookHandler{
+		secret:     secret,
+		raceRepo:   raceRepo,
+		runnerRepo: runnerRepo,
+		resultRepo: resultRepo,
+		settler:    settler,
+		logger:     logger,
+	}
+}
+
+// ServeHTTP handles a result delivery
+func (h *ResultWebhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeWebhookResponse(w, http.StatusMethodNotAllowed, ResultWebhookResponse{Error: "method not allowed"})
+		return
+	}
+	if !h.authenticated(r) {
+		h.logger.WithField("remote_addr", r.RemoteAddr).Warn("Rejected unauthenticated result webhook")
+		writeWebhookResponse(w, http.StatusUnauthorized, ResultWebhookResponse{Error: "unauthorized"})
+		return
+	}
+
+	var payload ResultWebhookPayload
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxResultWebhookBody))
+	if err := decoder.Decode(&payload); err != nil {
+		writeWebhookResponse(w, http.StatusBadRequest, ResultWebhookResponse{Error: fmt.Sprintf("invalid payload: %v", err)})
+		return
+	}
+
+	status, result, err := h.ingest(r.Context(), &payload)
+	switch {
+	case errors.Is(err, errWebhookRaceNotFound):
+		writeWebhookResponse(w, http.StatusNotFound, ResultWebhookResponse{Error: err.Error()})
+		return
+	case errors.Is(err, errWebhookInvalid):
+		writeWebhookResponse(w, http.StatusUnprocessableEntity, ResultWebhookResponse{Error: err.Error()})
+		return
+	case err != nil:
+		h.logger.WithError(err).Error("Failed to ingest result webhook")
+		writeWebhookResponse(w, http.StatusInternalServerError, ResultWebhookResponse{Error: "failed to ingest result"})
+		return
+	}
+
+	h.logger.WithFields(logrus.Fields{
+		"race_id": result.RaceID,
+		"status":  status,
+	}).Info("Result webhook processed")
+	writeWebhookResponse(w, http.StatusOK, ResultWebhookResponse{Status: status, RaceID: result.RaceID.String()})
+}
+
+// authenticated compares the delivery's secret in constant time. An empty
+// configured secret rejects every delivery.
+func (h *ResultWebhookHandler) authenticated(r *http.Request) bool {
+	if h.secret == "" {
+		return false
+	}
+	given := r.Header.Get(ResultWebhookSecretHeader)
+	return subtle.ConstantTimeCompare([]byte(given), []byte(h.secret)) == 1
+}
+
+// ingest stores the delivery's result and settles the race, unless the race
+// already has a result
+func (h *ResultWebhookHandler) ingest(ctx context.Context, payload *ResultWebhookPayload) (string, *models.RaceResult, error) {
+	race, err := h.resolveRace(ctx, payload)
+	if err != nil {
+		return "", nil, err
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	existing, err := h.resultRepo.GetByRaceID(ctx, race.ID)
+	switch {
+	case err == nil && existing != nil:
+		return ResultWebhookStatusDuplicate, existing, nil
+	case err != nil && !errors.Is(err, models.ErrRaceResultNotFound):
+		return "", nil, fmt.Errorf("failed to check for existing result: %w", err)
+	}
+
+	result, err := h.normalize(ctx, race, payload)
+	if err != nil {
+		return "", nil, err
+	}
+	if err := h.resultRepo.Insert(ctx, result); err != nil {
+		return "", nil, fmt.Errorf("failed to store race result: %w", err)
+	}
+	if err := h.settler.SettleRace(ctx, result); err != nil {
+		// Remove the result so a redelivery is not mistaken for a duplicate
+		if deleteErr := h.resultRepo.Delete(ctx, result.RaceID, result.Time); deleteErr != nil {
+			h.logger.WithError(deleteErr).WithField("race_id", result.RaceID).Error("Failed to remove unsettled race result")
+		}
+		return "", nil, fmt.Errorf("failed to settle race: %w", err)
+	}
+	return ResultWebhookStatusSettled, result, nil
+}
+
+// resolveRace finds the delivery's race by ID, or by track and off time
+func (h *ResultWebhookHandler) resolveRace(ctx context.Context, payload *ResultWebhookPayload) (*models.Race, error) {
+	if payload.RaceID != "" {
+		raceID, err := uuid.Parse(payload.RaceID)
+		if err != nil {
+			return nil, fmt.Errorf("%w: race_id: %v", errWebhookInvalid, err)
+		}
+		race, err := h.raceRepo.GetByID(ctx, raceID)
+		if errors.Is(err, models.ErrNotFound) || (err == nil && race == nil) {
+			return nil, fmt.Errorf("%w: %s", errWebhookRaceNotFound, raceID)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to load race: %w", err)
+		}
+		return race, nil
+	}
+
+	track := strings.TrimSpace(payload.Track)
+	if track == "" || payload.OffTime.IsZero() {
+		return nil, fmt.Errorf("%w: race_id or track and off_time are required", errWebhookInvalid)
+	}
+	races, err := h.raceRepo.GetByTrackAndDate(ctx, track, payload.OffTime)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up races: %w", err)
+	}
+	var best *models.Race
+	for _, race := range races {
+		if !strings.EqualFold(race.Track, track) {
+			continue
+		}
+		gap := absDuration(race.ScheduledStart.Sub(payload.OffTime))
+		if gap > offTimeTolerance {
+			continue
+		}
+		if best == nil || gap < absDuration(best.ScheduledStart.Sub(payload.OffTime)) {
+			best = race
+		}
+	}
+	if best == nil {
+		return nil, fmt.Errorf("%w: %s at %s", errWebhookRaceNotFound, track, payload.OffTime.Format(time.RFC3339))
+	}
+	return best, nil
+}
+
+// normalize validates a delivery and converts it to a race result, matching
+// traps to the race's runners
+func (h *ResultWebhookHandler) normalize(ctx context.Context, race *models.Race, payload *ResultWebhookPayload) (*models.RaceResult, error) {
+	status := strings.ToLower(strings.TrimSpace(payload.Status))
+	if status == "" {
+		status = models.RaceResultStatusCompleted
+	}
+	switch status {
+	case models.RaceResultStatusCompleted, models.RaceResultStatusVoid, models.RaceResultStatusCancelled:
+	default:
+		return nil, fmt.Errorf("%w: unsupported status %q", errWebhookInvalid, payload.Status)
+	}
+
+	runners, err := h.runnerRepo.GetByRaceID(ctx, race.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load runners: %w", err)
+	}
+	runnerByTrap := make(map[int]uuid.UUID, len(runners))
+	for _, runner := range runners {
+		runnerByTrap[runner.TrapNumber] = runner.ID
+	}
+
+	positions := models.PositionsData{Runners: make([]models.RunnerPosition, 0, len(payload.Positions))}
+	seen := make(map[int]bool, len(payload.Positions))
+	var winnerTrap *int
+	for _, entry := range payload.Positions {
+		if entry.Trap < 1 || entry.Trap > maxTrap {
+			return nil, fmt.Errorf("%w: trap %d out of range", errWebhookInvalid, entry.Trap)
+		}
+		if seen[entry.Trap] {
+			return nil, fmt.Errorf("%w: trap %d listed twice", errWebhookInvalid, entry.Trap)
+		}
+		seen[entry.Trap] = true
+		if entry.Position < 0 {
+			return nil, fmt.Errorf("%w: trap %d has negative position", errWebhookInvalid, entry.Trap)
+		}
+		if entry.Position == 1 && (winnerTrap == nil || entry.Trap < *winnerTrap) {
+			trap := entry.Trap
+			winnerTrap = &trap
+		}
+		positions.Runners = append(positions.Runners, models.RunnerPosition{
+			RunnerID:   runnerByTrap[entry.Trap],
+			TrapNumber: entry.Trap,
+			Position:   entry.Position,
+			SP:         decimal.NewFromFloat(entry.SP),
+		})
+	}
+	if status == models.RaceResultStatusCompleted && winnerTrap == nil {
+		return nil, fmt.Errorf("%w: completed result has no winner", errWebhookInvalid)
+	}
+	sort.Slice(positions.Runners, func(i, j int) bool {
+		return positions.Runners[i].TrapNumber < positions.Runners[j].TrapNumber
+	})
+
+	encoded, err := json.Marshal(positions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode positions: %w", err)
+	}
+	resultTime := race.ScheduledStart
+	if payload.ResultTime != nil {
+		resultTime = *payload.ResultTime
+	}
+	return &models.RaceResult{
+		Time:       resultTime.UTC(),
+		RaceID:     race.ID,
+		WinnerTrap: winnerTrap,
+		Positions:  encoded,
+		Status:     status,
+	}, nil
+}
+
+func writeWebhookResponse(w http.ResponseWriter, code int, response ResultWebhookResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(response)
+}
+
+func absDuration(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
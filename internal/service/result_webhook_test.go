@@ -0,0 +1,216 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yourusername/clever-better/internal/models"
+	"github.com/yourusername/clever-better/internal/repository"
+)
+
+const testWebhookSecret = "s3cret"
+
+// fakeWebhookRaceRepo serves a fixed set of races
+type fakeWebhookRaceRepo struct {
+	repository.RaceRepository
+	races []*models.Race
+}
+
+func (r *fakeWebhookRaceRepo) GetByID(ctx context.Context, id uuid.UUID) (*models.Race, error) {
+	for _, race := range r.races {
+		if race.ID == id {
+			return race, nil
+		}
+	}
+	return nil, models.ErrNotFound
+}
+
+func (r *fakeWebhookRaceRepo) GetByTrackAndDate(ctx context.Context, track string, date time.Time) ([]*models.Race, error) {
+	return r.races, nil
+}
+
+// fakeWebhookRunnerRepo serves the runners of every race
+type fakeWebhookRunnerRepo struct {
+	repository.RunnerRepository
+	runners []*models.Runner
+}
+
+func (r *fakeWebhookRunnerRepo) GetByRaceID(ctx context.Context, raceID uuid.UUID) ([]*models.Runner, error) {
+	return r.runners, nil
+}
+
+// fakeResultRepo keeps results in memory
+type fakeResultRepo struct {
+	repository.RaceResultRepository
+	results map[uuid.UUID]*models.RaceResult
+	inserts int
+}
+
+func (r *fakeResultRepo) GetByRaceID(ctx context.Context, raceID uuid.UUID) (*models.RaceResult, error) {
+	result, ok := r.results[raceID]
+	if !ok {
+		return nil, models.ErrRaceResultNotFound
+	}
+	return result, nil
+}
+
+func (r *fakeResultRepo) Insert(ctx context.Context, result *models.RaceResult) error {
+	r.inserts++
+	r.results[result.RaceID] = result
+	return nil
+}
+
+// fakeSettlementBetRepo keeps bets in memory and records updates
+type fakeSettlementBetRepo struct {
+	repository.BetRepository
+	bets    []*models.Bet
+	updates int
+}
+
+func (r *fakeSettlementBetRepo) GetByRaceID(ctx context.Context, raceID uuid.UUID) ([]*models.Bet, error) {
+	return r.bets, nil
+}
+
+func (r *fakeSettlementBetRepo) Update(ctx context.Context, bet *models.Bet) error {
+	r.updates++
+	return nil
+}
+
+type webhookFixture struct {
+	handler *ResultWebhookHandler
+	race    *models.Race
+	results *fakeResultRepo
+	bets    *fakeSettlementBetRepo
+}
+
+func newWebhookFixture(t *testing.T) *webhookFixture {
+	t.Helper()
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	race := &models.Race{ID: uuid.New(), Track: "Romford", ScheduledStart: time.Date(2024, 3, 1, 19, 30, 0, 0, time.UTC)}
+	winner := &models.Runner{ID: uuid.New(), RaceID: race.ID, TrapNumber: 2}
+	loser := &models.Runner{ID: uuid.New(), RaceID: race.ID, TrapNumber: 5}
+	runners := &fakeWebhookRunnerRepo{runners: []*models.Runner{winner, loser}}
+	bets := &fakeSettlementBetRepo{bets: []*models.Bet{
+		{ID: uuid.New(), RaceID: race.ID, RunnerID: winner.ID, Side: models.BetSideBack, Odds: 3.0, Stake: 10, Status: models.BetStatusMatched},
+		{ID: uuid.New(), RaceID: race.ID, RunnerID: loser.ID, Side: models.BetSideBack, Odds: 5.0, Stake: 10, Status: models.BetStatusPending},
+	}}
+	results := &fakeResultRepo{results: make(map[uuid.UUID]*models.RaceResult)}
+	settler := NewBetSettlementService(bets, runners, 0.05, logger)
+
+	handler := NewResultWebhookHandler(testWebhookSecret, &fakeWebhookRaceRepo{races: []*models.Race{race}}, runners, results, settler, logger)
+	return &webhookFixture{handler: handler, race: race, results: results, bets: bets}
+}
+
+func (f *webhookFixture) post(t *testing.T, secret string, payload ResultWebhookPayload) (*httptest.ResponseRecorder, ResultWebhookResponse) {
+	t.Helper()
+	body, err := json.Marshal(payload)
+	require.NoError(t, err)
+	req := httptest.NewRequest(http.MethodPost, ResultWebhookPath, bytes.NewReader(body))
+	if secret != "" {
+		req.Header.Set(ResultWebhookSecretHeader, secret)
+	}
+	rec := httptest.NewRecorder()
+	f.handler.ServeHTTP(rec, req)
+
+	var response ResultWebhookResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&response))
+	return rec, response
+}
+
+func validPayload(race *models.Race) ResultWebhookPayload {
+	return ResultWebhookPayload{
+		Track:   "romford",
+		OffTime: race.ScheduledStart.Add(2 * time.Minute),
+		Status:  "Completed",
+		Positions: []ResultWebhookPosition{
+			{Trap: 5, Position: 2, SP: 4.5},
+			{Trap: 2, Position: 1, SP: 2.75},
+		},
+	}
+}
+
+func TestResultWebhookStoresResultAndSettles(t *testing.T) {
+	f := newWebhookFixture(t)
+
+	rec, response := f.post(t, testWebhookSecret, validPayload(f.race))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, ResultWebhookStatusSettled, response.Status)
+	assert.Equal(t, f.race.ID.String(), response.RaceID)
+
+	stored := f.results.results[f.race.ID]
+	require.NotNil(t, stored)
+	assert.Equal(t, models.RaceResultStatusCompleted, stored.Status)
+	require.NotNil(t, stored.WinnerTrap)
+	assert.Equal(t, 2, *stored.WinnerTrap)
+	assert.Equal(t, f.race.ScheduledStart, stored.Time)
+	position, ok := stored.FinishingPosition(f.bets.bets[1].RunnerID, 5)
+	require.True(t, ok, "traps are matched to the race's runners")
+	assert.Equal(t, 2, position)
+
+	matched := f.bets.bets[0]
+	assert.Equal(t, models.BetStatusSettled, matched.Status)
+	require.NotNil(t, matched.ProfitLoss)
+	assert.InDelta(t, 19.0, *matched.ProfitLoss, 1e-9, "20 profit less 5% commission")
+	assert.Equal(t, models.BetStatusPending, f.bets.bets[1].Status, "unmatched bets are left to the order manager")
+	assert.Equal(t, 1, f.bets.updates)
+}
+
+func TestResultWebhookIgnoresDuplicateDelivery(t *testing.T) {
+	f := newWebhookFixture(t)
+
+	rec, _ := f.post(t, testWebhookSecret, validPayload(f.race))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	payload := validPayload(f.race)
+	payload.RaceID = f.race.ID.String()
+	rec, response := f.post(t, testWebhookSecret, payload)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, ResultWebhookStatusDuplicate, response.Status)
+	assert.Equal(t, 1, f.results.inserts, "a redelivery is not stored again")
+	assert.Equal(t, 1, f.bets.updates, "a redelivery does not settle again")
+}
+
+func TestResultWebhookRejectsUnauthenticated(t *testing.T) {
+	f := newWebhookFixture(t)
+
+	for _, secret := range []string{"", "wrong"} {
+		rec, response := f.post(t, secret, validPayload(f.race))
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+		assert.Equal(t, "unauthorized", response.Error)
+	}
+	assert.Zero(t, f.results.inserts)
+	assert.Zero(t, f.bets.updates)
+}
+
+func TestResultWebhookRejectsInvalidResults(t *testing.T) {
+	f := newWebhookFixture(t)
+
+	noWinner := validPayload(f.race)
+	noWinner.Positions = []ResultWebhookPosition{{Trap: 2, Position: 2}}
+	rec, _ := f.post(t, testWebhookSecret, noWinner)
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+
+	badTrap := validPayload(f.race)
+	badTrap.Positions = append(badTrap.Positions, ResultWebhookPosition{Trap: 9, Position: 3})
+	rec, _ = f.post(t, testWebhookSecret, badTrap)
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+
+	unknownRace := validPayload(f.race)
+	unknownRace.OffTime = f.race.ScheduledStart.Add(2 * time.Hour)
+	rec, _ = f.post(t, testWebhookSecret, unknownRace)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	assert.Zero(t, f.results.inserts)
+}
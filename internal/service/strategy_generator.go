@@ -4,15 +4,19 @@ package service
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"math"
 	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
 
 	"github.com/yourusername/clever-better/internal/backtest"
+	"github.com/yourusername/clever-better/internal/config"
 	"github.com/yourusername/clever-better/internal/database"
 	"github.com/yourusername/clever-better/internal/ml"
 	"github.com/yourusername/clever-better/internal/models"
@@ -20,20 +24,45 @@ import (
 	"github.com/yourusername/clever-better/internal/strategy"
 )
 
+// generatedStrategyNamespace scopes content-addressed IDs of generated strategies
+var generatedStrategyNamespace = uuid.MustParse("005471a9-ea30-4099-9de9-a01e13167ace")
+
+// StrategyGenerationClient generates candidate strategies, typically the ML service
+type StrategyGenerationClient interface {
+	GenerateStrategy(ctx context.Context, constraints ml.StrategyConstraints) ([]*ml.GeneratedStrategy, error)
+}
+
 // StrategyGeneratorService generates betting strategies using ML
 type StrategyGeneratorService struct {
-	mlClient          *ml.CachedMLClient
+	mlClient          StrategyGenerationClient
 	strategyRepo      repository.StrategyRepository
 	backtestRepo      repository.BacktestResultRepository
 	db                *database.DB
 	logger            *logrus.Logger
 	minCompositeScore float64
+	activationGates   config.ActivationGateConfig
 	backtestConfig    backtest.BacktestConfig
 }
 
+// generatedWalkForwardConfig fits several walk-forward folds into the
+// generator's five-month backtest window
+var generatedWalkForwardConfig = backtest.WalkForwardConfig{
+	TrainingWindowDays:   60,
+	ValidationWindowDays: 15,
+	TestWindowDays:       15,
+	StepSizeDays:         15,
+	MinTradesPerWindow:   5,
+}
+
+// ML feature keys read by the activation gates
+const (
+	featureAverageLiquidity       = "average_liquidity"
+	featureWalkForwardConsistency = "walk_forward_consistency"
+)
+
 // NewStrategyGeneratorService creates a new strategy generator service
 func NewStrategyGeneratorService(
-	mlClient *ml.CachedMLClient,
+	mlClient StrategyGenerationClient,
 	strategyRepo repository.StrategyRepository,
 	backtestRepo repository.BacktestResultRepository,
 	db *database.DB,
@@ -63,6 +92,12 @@ func NewStrategyGeneratorService(
 	}
 }
 
+// SetActivationGates requires a generated strategy's backtest to show enough
+// bets, liquidity and walk-forward consistency before it is activated
+func (s *StrategyGeneratorService) SetActivationGates(gates config.ActivationGateConfig) {
+	s.activationGates = gates
+}
+
 // GenerateFromBacktestResults analyzes top backtest results and generates new strategies
 func (s *StrategyGeneratorService) GenerateFromBacktestResults(ctx context.Context, topN int, constraints ml.StrategyConstraints) ([]*ml.GeneratedStrategy, error) {
 	s.logger.WithField("top_n", topN).Info("Generating strategies from backtest results")
@@ -103,6 +138,7 @@ func (s *StrategyGeneratorService) GenerateFromBacktestResults(ctx context.Conte
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate strategies: %w", err)
 	}
+	strategies = assignGeneratedStrategyIDs(strategies)
 
 	s.logger.WithFields(logrus.Fields{
 		"generated_count": len(strategies),
@@ -131,17 +167,20 @@ func (s *StrategyGeneratorService) GenerateOptimizedStrategy(ctx context.Context
 		return nil, fmt.Errorf("no strategy generated for constraints: %+v", constraints)
 	}
 
-	return strategies[0], nil
+	return assignGeneratedStrategyIDs(strategies)[0], nil
 }
 
-// EvaluateGeneratedStrategy evaluates a generated strategy via REAL backtesting
+// EvaluateGeneratedStrategy evaluates a generated strategy via REAL backtesting.
+// A strategy regenerated with the same parameters keeps its ID, so it is
+// updated and re-backtested rather than saved again.
 func (s *StrategyGeneratorService) EvaluateGeneratedStrategy(ctx context.Context, generatedStrategy *ml.GeneratedStrategy) (*models.BacktestResult, error) {
+	generatedStrategy.StrategyID = GeneratedStrategyID(generatedStrategy.Parameters)
 	s.logger.WithField("strategy_id", generatedStrategy.StrategyID).Info("Evaluating generated strategy with real backtest")
 
 	// Convert generated strategy to actual strategy model
 	strategyModel := &models.Strategy{
 		ID:          generatedStrategy.StrategyID,
-		Name:        fmt.Sprintf("ML-Generated-%s", generatedStrategy.StrategyID),
+		Name:        generatedStrategyName(generatedStrategy),
 		Description: fmt.Sprintf("ML-generated strategy with confidence %.2f", generatedStrategy.Confidence),
 		Parameters:  generatedStrategy.Parameters,
 		IsActive:    false, // Not active until proven successful
@@ -149,11 +188,43 @@ func (s *StrategyGeneratorService) EvaluateGeneratedStrategy(ctx context.Context
 		UpdatedAt:   time.Now(),
 	}
 
-	// Save strategy to database
-	if err := s.strategyRepo.Create(ctx, strategyModel); err != nil {
-		return nil, fmt.Errorf("failed to save generated strategy: %w", err)
+	// Save strategy to database, refreshing it if it was generated before
+	existing, err := s.strategyRepo.GetByID(ctx, strategyModel.ID)
+	switch {
+	case err == nil:
+		existing.Description = strategyModel.Description
+		existing.Parameters = strategyModel.Parameters
+		existing.UpdatedAt = time.Now()
+		if err := s.strategyRepo.Update(ctx, existing); err != nil {
+			return nil, fmt.Errorf("failed to update generated strategy: %w", err)
+		}
+		s.logger.WithField("strategy_id", strategyModel.ID).Info("Strategy regenerated with identical parameters, re-backtesting")
+	case errors.Is(err, models.ErrNotFound):
+		if err := s.strategyRepo.Create(ctx, strategyModel); err != nil {
+			return nil, fmt.Errorf("failed to save generated strategy: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("failed to look up generated strategy: %w", err)
+	}
+
+	result, backtested := s.backtestGeneratedStrategy(ctx, generatedStrategy)
+	if !backtested {
+		return result, nil
+	}
+
+	// Store backtest result
+	if err := s.backtestRepo.Create(ctx, result); err != nil {
+		s.logger.WithError(err).Error("Failed to store backtest result")
+		return nil, fmt.Errorf("failed to store backtest result: %w", err)
 	}
 
+	return result, nil
+}
+
+// backtestGeneratedStrategy runs a real backtest of a generated strategy
+// without persisting anything. It reports false when the backtest could not
+// run and the result is the ML-estimate fallback.
+func (s *StrategyGeneratorService) backtestGeneratedStrategy(ctx context.Context, generatedStrategy *ml.GeneratedStrategy) (*models.BacktestResult, bool) {
 	// Create strategy implementation from ML parameters
 	stratImpl := s.createStrategyFromMLParams(generatedStrategy)
 
@@ -161,7 +232,7 @@ func (s *StrategyGeneratorService) EvaluateGeneratedStrategy(ctx context.Context
 	engine, err := backtest.NewEngine(s.backtestConfig, s.db, stratImpl, s.logger)
 	if err != nil {
 		s.logger.WithError(err).Error("Failed to create backtest engine, using ML estimates")
-		return s.createFallbackResult(generatedStrategy), nil
+		return s.createFallbackResult(generatedStrategy), false
 	}
 	defer engine.Close(ctx)
 
@@ -174,7 +245,7 @@ func (s *StrategyGeneratorService) EvaluateGeneratedStrategy(ctx context.Context
 	state, metrics, err := engine.Run(ctx, s.backtestConfig.StartDate, s.backtestConfig.EndDate)
 	if err != nil {
 		s.logger.WithError(err).Error("Backtest execution failed, using ML estimates")
-		return s.createFallbackResult(generatedStrategy), nil
+		return s.createFallbackResult(generatedStrategy), false
 	}
 
 	// Calculate composite score from REAL backtest metrics
@@ -183,9 +254,16 @@ func (s *StrategyGeneratorService) EvaluateGeneratedStrategy(ctx context.Context
 
 	// Create ML features from backtest state for feedback
 	mlFeatures := s.extractMLFeaturesFromBacktest(state, metrics)
+	if s.activationGates.MinWalkForwardConsistency > 0 {
+		walkForward, err := backtest.RunWalkForward(ctx, engine, stratImpl, generatedWalkForwardConfig)
+		if err != nil {
+			s.logger.WithError(err).WithField("strategy_id", generatedStrategy.StrategyID).Warn("Walk-forward validation failed")
+		} else {
+			mlFeatures[featureWalkForwardConsistency] = walkForward.ConsistencyScore
+		}
+	}
 	mlFeaturesJSON, _ := json.Marshal(mlFeatures)
 
-	// Store REAL backtest result
 	result := &models.BacktestResult{
 		ID:             uuid.New(),
 		StrategyID:     generatedStrategy.StrategyID,
@@ -207,12 +285,6 @@ func (s *StrategyGeneratorService) EvaluateGeneratedStrategy(ctx context.Context
 		CreatedAt:      time.Now(),
 	}
 
-	// Store backtest result
-	if err := s.backtestRepo.Create(ctx, result); err != nil {
-		s.logger.WithError(err).Error("Failed to store backtest result")
-		return nil, fmt.Errorf("failed to store backtest result: %w", err)
-	}
-
 	s.logger.WithFields(logrus.Fields{
 		"strategy_id":     generatedStrategy.StrategyID,
 		"composite_score": result.CompositeScore,
@@ -224,7 +296,63 @@ func (s *StrategyGeneratorService) EvaluateGeneratedStrategy(ctx context.Context
 		"recommendation":  result.Recommendation,
 	}).Info("Real backtest evaluation complete")
 
-	return result, nil
+	return result, true
+}
+
+// PreviewTopStrategies backtests generated strategies without saving or
+// activating them and returns them ranked by composite score
+func (s *StrategyGeneratorService) PreviewTopStrategies(ctx context.Context, strategies []*ml.GeneratedStrategy) []*StrategyEvaluation {
+	previews := make([]*StrategyEvaluation, 0, len(strategies))
+	for _, generated := range strategies {
+		result, _ := s.backtestGeneratedStrategy(ctx, generated)
+		previews = append(previews, &StrategyEvaluation{
+			StrategyID:      generated.StrategyID,
+			StrategyName:    generatedStrategyName(generated),
+			CompositeScore:  result.CompositeScore,
+			MLConfidence:    generated.Confidence,
+			BacktestMetrics: result,
+			Recommendation:  result.Recommendation,
+			EvaluatedAt:     time.Now(),
+		})
+	}
+
+	sort.SliceStable(previews, func(i, j int) bool {
+		return previews[i].CompositeScore > previews[j].CompositeScore
+	})
+	for i, preview := range previews {
+		preview.Rank = i + 1
+	}
+	return previews
+}
+
+// activationRejection returns why ActivateTopStrategies would not activate a
+// strategy with this backtest result, or "" when it would
+func (s *StrategyGeneratorService) activationRejection(result *models.BacktestResult) string {
+	if result.CompositeScore < s.minCompositeScore {
+		return fmt.Sprintf("composite score %.2f below %.2f", result.CompositeScore, s.minCompositeScore)
+	}
+
+	gates := s.activationGates
+	if result.TotalBets < gates.MinTotalBets {
+		return fmt.Sprintf("%d bets below minimum %d", result.TotalBets, gates.MinTotalBets)
+	}
+	if gates.MinAverageLiquidity <= 0 && gates.MinWalkForwardConsistency <= 0 {
+		return ""
+	}
+
+	var features map[string]float64
+	if len(result.MLFeatures) > 0 {
+		if err := json.Unmarshal(result.MLFeatures, &features); err != nil {
+			return fmt.Sprintf("unreadable backtest features: %v", err)
+		}
+	}
+	if liquidity := features[featureAverageLiquidity]; liquidity < gates.MinAverageLiquidity {
+		return fmt.Sprintf("average liquidity %.2f below minimum %.2f", liquidity, gates.MinAverageLiquidity)
+	}
+	if consistency := features[featureWalkForwardConsistency]; consistency < gates.MinWalkForwardConsistency {
+		return fmt.Sprintf("walk-forward consistency %.2f below minimum %.2f", consistency, gates.MinWalkForwardConsistency)
+	}
+	return ""
 }
 
 // ActivateTopStrategies activates strategies that exceed minimum composite score
@@ -239,28 +367,35 @@ func (s *StrategyGeneratorService) ActivateTopStrategies(ctx context.Context, st
 			continue
 		}
 
-		// Activate if composite score exceeds threshold
-		if result.CompositeScore >= s.minCompositeScore {
-			strategyModel, err := s.strategyRepo.GetByID(ctx, strategy.StrategyID)
-			if err != nil {
-				s.logger.WithError(err).WithField("strategy_id", strategy.StrategyID).Error("Failed to retrieve strategy")
-				continue
-			}
+		// Activate if composite score exceeds threshold and the backtest
+		// passes the activation gates
+		if reason := s.activationRejection(result); reason != "" {
+			s.logger.WithFields(logrus.Fields{
+				"strategy_id": strategy.StrategyID,
+				"reason":      reason,
+			}).Info("Generated strategy not activated")
+			continue
+		}
 
-			strategyModel.IsActive = true
-			strategyModel.UpdatedAt = time.Now()
+		strategyModel, err := s.strategyRepo.GetByID(ctx, strategy.StrategyID)
+		if err != nil {
+			s.logger.WithError(err).WithField("strategy_id", strategy.StrategyID).Error("Failed to retrieve strategy")
+			continue
+		}
 
-			if err := s.strategyRepo.Update(ctx, strategyModel); err != nil {
-				s.logger.WithError(err).WithField("strategy_id", strategy.StrategyID).Error("Failed to activate strategy")
-				continue
-			}
+		strategyModel.IsActive = true
+		strategyModel.UpdatedAt = time.Now()
 
-			activatedIDs = append(activatedIDs, strategy.StrategyID)
-			s.logger.WithFields(logrus.Fields{
-				"strategy_id":     strategy.StrategyID,
-				"composite_score": result.CompositeScore,
-			}).Info("Activated high-performing strategy")
+		if err := s.strategyRepo.Update(ctx, strategyModel); err != nil {
+			s.logger.WithError(err).WithField("strategy_id", strategy.StrategyID).Error("Failed to activate strategy")
+			continue
 		}
+
+		activatedIDs = append(activatedIDs, strategy.StrategyID)
+		s.logger.WithFields(logrus.Fields{
+			"strategy_id":     strategy.StrategyID,
+			"composite_score": result.CompositeScore,
+		}).Info("Activated high-performing strategy")
 	}
 
 	return activatedIDs, nil
@@ -410,6 +545,56 @@ func (s *StrategyGeneratorService) extractTopMetrics(results []*models.BacktestR
 	return metrics
 }
 
+// GeneratedStrategyID derives a strategy ID from its parameters. Keys are
+// trimmed and lower-cased and values rounded to six decimal places, so the
+// same parameter set always maps to the same ID whatever its order.
+func GeneratedStrategyID(parameters map[string]float64) uuid.UUID {
+	normalized := make(map[string]string, len(parameters))
+	keys := make([]string, 0, len(parameters))
+	for key, value := range parameters {
+		key = strings.ToLower(strings.TrimSpace(key))
+		rounded := math.Round(value*1e6) / 1e6
+		if rounded == 0 {
+			rounded = 0 // drop the sign of negative zero
+		}
+		if _, seen := normalized[key]; !seen {
+			keys = append(keys, key)
+		}
+		normalized[key] = strconv.FormatFloat(rounded, 'f', -1, 64)
+	}
+	sort.Strings(keys)
+
+	var canonical strings.Builder
+	for _, key := range keys {
+		canonical.WriteString(key)
+		canonical.WriteByte('=')
+		canonical.WriteString(normalized[key])
+		canonical.WriteByte(';')
+	}
+	return uuid.NewSHA1(generatedStrategyNamespace, []byte(canonical.String()))
+}
+
+// assignGeneratedStrategyIDs replaces the IDs returned by the ML service with
+// content-addressed ones and drops repeated parameter sets
+func assignGeneratedStrategyIDs(strategies []*ml.GeneratedStrategy) []*ml.GeneratedStrategy {
+	seen := make(map[uuid.UUID]bool, len(strategies))
+	unique := make([]*ml.GeneratedStrategy, 0, len(strategies))
+	for _, generated := range strategies {
+		generated.StrategyID = GeneratedStrategyID(generated.Parameters)
+		if seen[generated.StrategyID] {
+			continue
+		}
+		seen[generated.StrategyID] = true
+		unique = append(unique, generated)
+	}
+	return unique
+}
+
+// generatedStrategyName is the name a generated strategy is saved under
+func generatedStrategyName(gen *ml.GeneratedStrategy) string {
+	return fmt.Sprintf("ML-Generated-%s", gen.StrategyID)
+}
+
 // createStrategyFromMLParams creates a strategy implementation from ML parameters
 func (s *StrategyGeneratorService) createStrategyFromMLParams(gen *ml.GeneratedStrategy) strategy.Strategy {
 	// Create a value strategy with ML-generated parameters
@@ -431,6 +616,9 @@ func (s *StrategyGeneratorService) createStrategyFromMLParams(gen *ml.GeneratedS
 	if maxOdds, ok := gen.Parameters["max_odds"]; ok {
 		strat.MaxOdds = maxOdds
 	}
+	if offset, ok := gen.Parameters["entry_tick_offset"]; ok {
+		strat.EntryTickOffset = int(offset)
+	}
 
 	strat.NameValue = fmt.Sprintf("ml_gen_%s", gen.StrategyID.String()[:8])
 
@@ -507,6 +695,8 @@ func (s *StrategyGeneratorService) extractMLFeaturesFromBacktest(state *backtest
 		"trades_per_day": float64(metrics.TotalBets) / float64(metrics.TradingDays),
 	}
 
+	features[featureAverageLiquidity] = metrics.AverageLiquidity
+
 	return features
 }
 
@@ -1,12 +1,16 @@
 package service
 
 import (
+	"context"
 	"encoding/json"
+	"math"
 	"testing"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
+	"github.com/yourusername/clever-better/internal/config"
+	"github.com/yourusername/clever-better/internal/ml"
 	"github.com/yourusername/clever-better/internal/models"
 )
 
@@ -179,3 +183,136 @@ func TestEmptyBacktestResults(t *testing.T) {
 		t.Errorf("Expected empty metrics, got %d", len(metrics))
 	}
 }
+
+func TestGeneratedStrategyIDIsContentAddressed(t *testing.T) {
+	params := map[string]float64{"min_edge_threshold": 0.05, "kelly_fraction": 0.25, "max_odds": 10}
+	reordered := map[string]float64{"max_odds": 10, " Kelly_Fraction": 0.2500000001, "min_edge_threshold": 0.05}
+	different := map[string]float64{"min_edge_threshold": 0.06, "kelly_fraction": 0.25, "max_odds": 10}
+
+	id := GeneratedStrategyID(params)
+	if id != GeneratedStrategyID(reordered) {
+		t.Errorf("Expected identical normalized parameters to share an ID")
+	}
+	if id == GeneratedStrategyID(different) {
+		t.Errorf("Expected distinct parameters to produce distinct IDs")
+	}
+	if GeneratedStrategyID(map[string]float64{"min_odds": 0}) != GeneratedStrategyID(map[string]float64{"min_odds": math.Copysign(0, -1)}) {
+		t.Errorf("Expected negative zero to normalize to zero")
+	}
+}
+
+func TestRegeneratedStrategiesShareIDs(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+
+	params := map[string]float64{"min_edge_threshold": 0.05, "kelly_fraction": 0.25}
+	client := &fakeGenerationClient{}
+	strategyRepo := &recordingStrategyRepo{}
+	backtestRepo := &recordingBacktestRepo{top: []*models.BacktestResult{{ID: uuid.New(), CompositeScore: 0.7}}}
+	svc := NewStrategyGeneratorService(client, strategyRepo, backtestRepo, nil, logger)
+
+	generate := func(strategies ...*ml.GeneratedStrategy) []*ml.GeneratedStrategy {
+		client.strategies = strategies
+		generated, err := svc.GenerateFromBacktestResults(context.Background(), 10, ml.StrategyConstraints{})
+		if err != nil {
+			t.Fatalf("Failed to generate strategies: %v", err)
+		}
+		return generated
+	}
+
+	first := generate(
+		&ml.GeneratedStrategy{StrategyID: uuid.New(), Parameters: params},
+		&ml.GeneratedStrategy{StrategyID: uuid.New(), Parameters: map[string]float64{"min_edge_threshold": 0.08}},
+		&ml.GeneratedStrategy{StrategyID: uuid.New(), Parameters: params},
+	)
+	if len(first) != 2 {
+		t.Fatalf("Expected repeated parameters to be dropped, got %d strategies", len(first))
+	}
+	if first[0].StrategyID == first[1].StrategyID {
+		t.Errorf("Expected distinct parameters to produce distinct IDs")
+	}
+
+	second := generate(&ml.GeneratedStrategy{StrategyID: uuid.New(), Parameters: params})
+	if second[0].StrategyID != first[0].StrategyID {
+		t.Errorf("Expected regenerated parameters to keep ID %s, got %s", first[0].StrategyID, second[0].StrategyID)
+	}
+
+	// Evaluating both generations saves the strategy once and then refreshes it
+	for _, generated := range []*ml.GeneratedStrategy{first[0], second[0]} {
+		if _, err := svc.EvaluateGeneratedStrategy(context.Background(), generated); err != nil {
+			t.Fatalf("Failed to evaluate strategy: %v", err)
+		}
+	}
+	if strategyRepo.creates != 1 || strategyRepo.updates != 1 {
+		t.Errorf("Expected 1 create and 1 update, got %d creates and %d updates", strategyRepo.creates, strategyRepo.updates)
+	}
+}
+
+func TestActivationGatesRejectThinBacktests(t *testing.T) {
+	svc := &StrategyGeneratorService{minCompositeScore: 0.6}
+	svc.SetActivationGates(config.ActivationGateConfig{
+		MinTotalBets:              30,
+		MinAverageLiquidity:       50,
+		MinWalkForwardConsistency: 0.5,
+	})
+
+	result := func(score float64, bets int, features string) *models.BacktestResult {
+		return &models.BacktestResult{CompositeScore: score, TotalBets: bets, MLFeatures: json.RawMessage(features)}
+	}
+	cases := []struct {
+		name     string
+		result   *models.BacktestResult
+		activate bool
+	}{
+		{"MeetsAllGates", result(0.9, 120, `{"average_liquidity":180,"walk_forward_consistency":0.75}`), true},
+		{"TooFewBets", result(2.5, 3, `{"average_liquidity":180,"walk_forward_consistency":1}`), false},
+		{"ThinMarkets", result(0.9, 120, `{"average_liquidity":12,"walk_forward_consistency":0.75}`), false},
+		{"Inconsistent", result(0.9, 120, `{"average_liquidity":180,"walk_forward_consistency":0.25}`), false},
+		{"NoWalkForward", result(0.9, 120, `{"average_liquidity":180}`), false},
+		{"LowScore", result(0.4, 120, `{"average_liquidity":180,"walk_forward_consistency":0.75}`), false},
+	}
+	for _, tc := range cases {
+		reason := svc.activationRejection(tc.result)
+		if tc.activate && reason != "" {
+			t.Errorf("%s: expected activation, rejected with %q", tc.name, reason)
+		}
+		if !tc.activate && reason == "" {
+			t.Errorf("%s: expected rejection", tc.name)
+		}
+	}
+}
+
+func TestActivateTopStrategiesAppliesGates(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.FatalLevel)
+
+	// Without a database the backtest falls back to ML estimates, which
+	// score highly but record no bets
+	generated := &ml.GeneratedStrategy{
+		Parameters:      map[string]float64{"min_edge_threshold": 0.05},
+		Confidence:      0.9,
+		ExpectedReturn:  0.3,
+		ExpectedSharpe:  2.0,
+		ExpectedWinRate: 0.6,
+	}
+
+	strategyRepo := &recordingStrategyRepo{}
+	svc := NewStrategyGeneratorService(&fakeGenerationClient{}, strategyRepo, &recordingBacktestRepo{}, nil, logger)
+	svc.SetActivationGates(config.ActivationGateConfig{MinTotalBets: 30})
+	activated, err := svc.ActivateTopStrategies(context.Background(), []*ml.GeneratedStrategy{generated})
+	if err != nil {
+		t.Fatalf("Failed to activate strategies: %v", err)
+	}
+	if len(activated) != 0 || strategyRepo.updates != 0 {
+		t.Errorf("Expected a high-score strategy with too few bets to stay inactive, activated %v", activated)
+	}
+
+	svc.SetActivationGates(config.ActivationGateConfig{})
+	activated, err = svc.ActivateTopStrategies(context.Background(), []*ml.GeneratedStrategy{generated})
+	if err != nil {
+		t.Fatalf("Failed to activate strategies: %v", err)
+	}
+	if len(activated) != 1 || activated[0] != generated.StrategyID {
+		t.Errorf("Expected the strategy to be activated once the gates are met, got %v", activated)
+	}
+}
@@ -0,0 +1,224 @@
+// Package service provides export of labelled training examples.
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"github.com/yourusername/clever-better/internal/models"
+	"github.com/yourusername/clever-better/internal/repository"
+)
+
+// TrainingExample is one settled bet in the feature store's row layout:
+// entity keys, the event timestamp, the feature vector the model saw before
+// the bet was placed, and the realized outcome as the label
+type TrainingExample struct {
+	BetID          uuid.UUID          `json:"bet_id"`
+	RaceID         uuid.UUID          `json:"race_id"`
+	RunnerID       uuid.UUID          `json:"runner_id"`
+	StrategyID     uuid.UUID          `json:"strategy_id"`
+	ModelID        uuid.UUID          `json:"model_id"`
+	EventTimestamp time.Time          `json:"event_timestamp"`
+	Features       map[string]float64 `json:"features"`
+	Probability    float64            `json:"predicted_probability"`
+	Side           models.BetSide     `json:"side"`
+	Odds           float64            `json:"odds"`
+	Stake          float64            `json:"stake"`
+	ProfitLoss     float64            `json:"profit_loss"`
+	// Label is 1 when the runner won and 0 when it lost, matching the
+	// win probability the model predicts
+	Label int `json:"label"`
+	// BetWon is whether the bet itself won, given its side
+	BetWon bool `json:"bet_won"`
+}
+
+// TrainingExampleExporter joins settled bets with the prediction made before
+// each was placed and the race result, for supervised training
+type TrainingExampleExporter struct {
+	betRepo        repository.BetRepository
+	runnerRepo     repository.RunnerRepository
+	predictionRepo repository.PredictionRepository
+	resultRepo     repository.RaceResultRepository
+	logger         *logrus.Logger
+}
+
+// NewTrainingExampleExporter creates a new training example exporter
+func NewTrainingExampleExporter(
+	betRepo repository.BetRepository,
+	runnerRepo repository.RunnerRepository,
+	predictionRepo repository.PredictionRepository,
+	resultRepo repository.RaceResultRepository,
+	logger *logrus.Logger,
+) *TrainingExampleExporter {
+	return &TrainingExampleExporter{
+		betRepo:        betRepo,
+		runnerRepo:     runnerRepo,
+		predictionRepo: predictionRepo,
+		resultRepo:     resultRepo,
+		logger:         logger,
+	}
+}
+
+// ExportTrainingExamples returns a labelled example for each bet settled
+// between start and end. Features come from the runner's latest prediction
+// made at or before the bet was placed. Bets without such a prediction, or on
+// races that were voided or have no result, are skipped.
+func (e *TrainingExampleExporter) ExportTrainingExamples(ctx context.Context, start, end time.Time) ([]TrainingExample, error) {
+	bets, err := e.betRepo.GetSettledBets(ctx, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get settled bets: %w", err)
+	}
+
+	predictionsByRace := make(map[uuid.UUID][]*models.Prediction)
+	resultsByRace := make(map[uuid.UUID]*models.RaceResult)
+	runnersByRace := make(map[uuid.UUID]map[uuid.UUID]*models.Runner)
+
+	examples := make([]TrainingExample, 0, len(bets))
+	skipped := 0
+	for _, bet := range bets {
+		raceID := bet.RaceID
+		if _, ok := resultsByRace[raceID]; !ok {
+			if err := e.loadRace(ctx, raceID, predictionsByRace, resultsByRace, runnersByRace); err != nil {
+				return nil, err
+			}
+		}
+
+		result := resultsByRace[raceID]
+		runner := runnersByRace[raceID][bet.RunnerID]
+		prediction := latestPredictionBefore(predictionsByRace[raceID], bet.RunnerID, bet.PlacedAt)
+		if result == nil || result.IsVoid() || runner == nil || prediction == nil {
+			skipped++
+			continue
+		}
+
+		features, err := decodeFeatures(prediction.Features)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode features for prediction %s: %w", prediction.ID, err)
+		}
+
+		won := result.IsWinner(runner.ID, runner.TrapNumber)
+		example := TrainingExample{
+			BetID:          bet.ID,
+			RaceID:         raceID,
+			RunnerID:       bet.RunnerID,
+			StrategyID:     bet.StrategyID,
+			ModelID:        prediction.ModelID,
+			EventTimestamp: bet.PlacedAt,
+			Features:       features,
+			Probability:    prediction.Probability,
+			Side:           bet.Side,
+			Odds:           bet.SettlementPrice(),
+			Stake:          bet.Stake,
+			ProfitLoss:     bet.CalculateProfitLoss(),
+			BetWon:         won == (bet.Side != models.BetSideLay),
+		}
+		if won {
+			example.Label = 1
+		}
+		examples = append(examples, example)
+	}
+
+	e.logger.WithFields(logrus.Fields{
+		"start":    start,
+		"end":      end,
+		"bets":     len(bets),
+		"examples": len(examples),
+		"skipped":  skipped,
+	}).Info("Exported training examples")
+
+	return examples, nil
+}
+
+// loadRace caches a race's predictions, result and runners. A missing result
+// is cached as nil so the race is only looked up once.
+func (e *TrainingExampleExporter) loadRace(
+	ctx context.Context,
+	raceID uuid.UUID,
+	predictions map[uuid.UUID][]*models.Prediction,
+	results map[uuid.UUID]*models.RaceResult,
+	runners map[uuid.UUID]map[uuid.UUID]*models.Runner,
+) error {
+	result, err := e.resultRepo.GetByRaceID(ctx, raceID)
+	if err != nil && !errors.Is(err, models.ErrRaceResultNotFound) {
+		return fmt.Errorf("failed to get race result: %w", err)
+	}
+	results[raceID] = result
+
+	racePredictions, err := e.predictionRepo.GetByRaceID(ctx, raceID)
+	if err != nil {
+		return fmt.Errorf("failed to get predictions: %w", err)
+	}
+	predictions[raceID] = racePredictions
+
+	raceRunners, err := e.runnerRepo.GetByRaceID(ctx, raceID)
+	if err != nil {
+		return fmt.Errorf("failed to get runners: %w", err)
+	}
+	byID := make(map[uuid.UUID]*models.Runner, len(raceRunners))
+	for _, runner := range raceRunners {
+		byID[runner.ID] = runner
+	}
+	runners[raceID] = byID
+
+	return nil
+}
+
+// latestPredictionBefore returns the runner's most recent prediction made at or before placedAt
+func latestPredictionBefore(predictions []*models.Prediction, runnerID uuid.UUID, placedAt time.Time) *models.Prediction {
+	var latest *models.Prediction
+	for _, prediction := range predictions {
+		if prediction.RunnerID != runnerID || prediction.PredictedAt.After(placedAt) {
+			continue
+		}
+		if latest == nil || prediction.PredictedAt.After(latest.PredictedAt) {
+			latest = prediction
+		}
+	}
+	return latest
+}
+
+// decodeFeatures reads a prediction's stored features. Objects keep their
+// numeric fields by name; arrays are named feature_0, feature_1 and so on.
+func decodeFeatures(raw json.RawMessage) (map[string]float64, error) {
+	features := make(map[string]float64)
+	if len(raw) == 0 || string(raw) == "null" {
+		return features, nil
+	}
+
+	var named map[string]interface{}
+	if err := json.Unmarshal(raw, &named); err == nil {
+		for name, value := range named {
+			if number, ok := value.(float64); ok {
+				features[name] = number
+			}
+		}
+		return features, nil
+	}
+
+	var vector []float64
+	if err := json.Unmarshal(raw, &vector); err != nil {
+		return nil, err
+	}
+	for i, value := range vector {
+		features[fmt.Sprintf("feature_%d", i)] = value
+	}
+	return features, nil
+}
+
+// WriteTrainingExamples writes examples as JSON lines, one example per line
+func WriteTrainingExamples(w io.Writer, examples []TrainingExample) error {
+	encoder := json.NewEncoder(w)
+	for _, example := range examples {
+		if err := encoder.Encode(example); err != nil {
+			return fmt.Errorf("failed to write training example: %w", err)
+		}
+	}
+	return nil
+}
@@ -15,6 +15,9 @@ type BaseStrategy struct {
 	MinLiquidity     float64
 	KellyFraction    float64
 	MinEdgeThreshold float64
+	// EntryTickOffset posts orders this many ticks more generous than the
+	// reference price, trading edge for a better chance of being filled
+	EntryTickOffset int
 }
 
 // ValidateOdds ensures odds are within acceptable bounds
@@ -31,6 +34,18 @@ func (b *BaseStrategy) ValidateOdds(odds float64) error {
 	return nil
 }
 
+// EntryPrice returns the price to post an order at. A back is offset down the
+// ladder and a lay up it, each by EntryTickOffset ticks.
+func (b *BaseStrategy) EntryPrice(side models.BetSide, price float64) float64 {
+	if b.EntryTickOffset <= 0 {
+		return price
+	}
+	if side == models.BetSideLay {
+		return OffsetTicks(price, b.EntryTickOffset)
+	}
+	return OffsetTicks(price, -b.EntryTickOffset)
+}
+
 // CheckLiquidity ensures the odds snapshot has sufficient liquidity
 func (b *BaseStrategy) CheckLiquidity(snapshot *models.OddsSnapshot) bool {
 	if snapshot == nil {
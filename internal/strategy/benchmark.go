@@ -0,0 +1,90 @@
+package strategy
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/yourusername/clever-better/internal/models"
+)
+
+// FavouriteBenchmarkStrategy backs the shortest-priced runner in every race
+// at a level stake. Backtests run it over the same races as a tested strategy
+// to show whether the strategy adds value over naive favourite backing.
+type FavouriteBenchmarkStrategy struct {
+	Stake float64
+}
+
+// NewFavouriteBenchmarkStrategy creates the benchmark with a level stake
+func NewFavouriteBenchmarkStrategy(stake float64) *FavouriteBenchmarkStrategy {
+	return &FavouriteBenchmarkStrategy{Stake: stake}
+}
+
+// Name returns strategy name
+func (s *FavouriteBenchmarkStrategy) Name() string {
+	return "favourite_benchmark"
+}
+
+// Evaluate backs the runner with the lowest back price at decision time.
+// Ties go to the runner listed first.
+func (s *FavouriteBenchmarkStrategy) Evaluate(ctx context.Context, strategyCtx Context) ([]Signal, error) {
+	_ = ctx
+	if strategyCtx.Race == nil {
+		return nil, fmt.Errorf("race is required")
+	}
+
+	latestOdds := latestOddsByRunner(strategyCtx.OddsHistory, strategyCtx.CurrentTime)
+	var favourite *models.Runner
+	favouriteOdds := 0.0
+	for _, runner := range strategyCtx.ActiveRunners() {
+		snapshot, ok := latestOdds[runner.ID]
+		if !ok {
+			continue
+		}
+		odds := snapshot.GetMidPrice()
+		if snapshot.BackPrice != nil {
+			odds = *snapshot.BackPrice
+		}
+		if odds <= 1 {
+			continue
+		}
+		if favourite == nil || odds < favouriteOdds {
+			favourite = runner
+			favouriteOdds = odds
+		}
+	}
+	if favourite == nil {
+		return nil, nil
+	}
+
+	return []Signal{{
+		RunnerID:   favourite.ID,
+		Side:       models.BetSideBack,
+		Odds:       favouriteOdds,
+		Stake:      s.Stake,
+		Confidence: 1 / favouriteOdds,
+		Reasoning:  "benchmark: back the favourite",
+	}}, nil
+}
+
+// ShouldBet accepts every priced favourite
+func (s *FavouriteBenchmarkStrategy) ShouldBet(signal Signal) bool {
+	return signal.Odds > 1 && signal.Stake > 0
+}
+
+// CalculateStake returns the level stake, capped at the bankroll
+func (s *FavouriteBenchmarkStrategy) CalculateStake(signal Signal, bankroll float64) float64 {
+	if bankroll <= 0 {
+		return 0
+	}
+	if s.Stake > bankroll {
+		return bankroll
+	}
+	return s.Stake
+}
+
+// GetParameters returns strategy parameters for ML export
+func (s *FavouriteBenchmarkStrategy) GetParameters() map[string]interface{} {
+	return map[string]interface{}{
+		"stake": s.Stake,
+	}
+}
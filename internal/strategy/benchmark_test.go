@@ -0,0 +1,49 @@
+package strategy
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/clever-better/internal/models"
+)
+
+func TestFavouriteBenchmarkBacksShortestPrice(t *testing.T) {
+	now := time.Date(2024, 3, 1, 14, 0, 0, 0, time.UTC)
+	race := &models.Race{ID: uuid.New(), ScheduledStart: now}
+	outsider := &models.Runner{ID: uuid.New(), TrapNumber: 1}
+	favourite := &models.Runner{ID: uuid.New(), TrapNumber: 2}
+	unpriced := &models.Runner{ID: uuid.New(), TrapNumber: 3}
+	price := func(v float64) *float64 { return &v }
+
+	ctx := Context{
+		Race:    race,
+		Runners: []*models.Runner{outsider, favourite, unpriced},
+		OddsHistory: []*models.OddsSnapshot{
+			{RunnerID: outsider.ID, Time: now.Add(-2 * time.Minute), BackPrice: price(1.5)},
+			{RunnerID: outsider.ID, Time: now.Add(-time.Minute), BackPrice: price(4.0)},
+			{RunnerID: favourite.ID, Time: now.Add(-time.Minute), BackPrice: price(2.2)},
+			{RunnerID: favourite.ID, Time: now.Add(time.Minute), BackPrice: price(1.2)},
+		},
+		CurrentTime: now,
+	}
+
+	benchmark := NewFavouriteBenchmarkStrategy(10)
+	signals, err := benchmark.Evaluate(context.Background(), ctx)
+	require.NoError(t, err)
+	require.Len(t, signals, 1)
+	assert.Equal(t, favourite.ID, signals[0].RunnerID, "latest prices before the decision time pick the favourite")
+	assert.Equal(t, models.BetSideBack, signals[0].Side)
+	assert.Equal(t, 2.2, signals[0].Odds)
+	assert.True(t, benchmark.ShouldBet(signals[0]))
+	assert.Equal(t, 10.0, benchmark.CalculateStake(signals[0], 1000))
+	assert.Equal(t, 4.0, benchmark.CalculateStake(signals[0], 4), "capped at bankroll")
+
+	ctx.OddsHistory = nil
+	signals, err = benchmark.Evaluate(context.Background(), ctx)
+	require.NoError(t, err)
+	assert.Empty(t, signals, "no prices, no bet")
+}
@@ -0,0 +1,80 @@
+package strategy
+
+// EdgeGate decides whether a priced selection clears the minimum edge and
+// confidence thresholds. Strategies use it during evaluation and the live
+// orchestrator uses it when filtering signals with ML predictions, so both
+// backtest and live paths accept and reject identical inputs.
+type EdgeGate struct {
+	MinEdge       float64
+	MinConfidence float64
+	// Bands raise or lower the thresholds for particular odds ranges
+	Bands []OddsBand
+}
+
+// OddsBand sets the edge and confidence thresholds for odds from MinOdds up
+// to but excluding MaxOdds, so longshots can be made to clear a higher bar.
+// A zero MaxOdds has no upper bound and a zero threshold keeps the gate's.
+type OddsBand struct {
+	MinOdds       float64
+	MaxOdds       float64
+	MinEdge       float64
+	MinConfidence float64
+}
+
+// Contains reports whether odds fall in the band
+func (b OddsBand) Contains(odds float64) bool {
+	return odds >= b.MinOdds && (b.MaxOdds == 0 || odds < b.MaxOdds)
+}
+
+// NewEdgeGate creates an edge gate with the given thresholds
+func NewEdgeGate(minEdge, minConfidence float64) EdgeGate {
+	return EdgeGate{
+		MinEdge:       minEdge,
+		MinConfidence: minConfidence,
+	}
+}
+
+// Edge returns the value edge of backing at odds given a win probability
+func Edge(probability, odds float64) float64 {
+	return (probability * odds) - 1.0
+}
+
+// WithBands returns a copy of the gate using bands
+func (g EdgeGate) WithBands(bands []OddsBand) EdgeGate {
+	g.Bands = bands
+	return g
+}
+
+// ForOdds returns the gate with the thresholds of the first band containing
+// odds, and no bands
+func (g EdgeGate) ForOdds(odds float64) EdgeGate {
+	bands := g.Bands
+	g.Bands = nil
+	for _, band := range bands {
+		if !band.Contains(odds) {
+			continue
+		}
+		if band.MinEdge > 0 {
+			g.MinEdge = band.MinEdge
+		}
+		if band.MinConfidence > 0 {
+			g.MinConfidence = band.MinConfidence
+		}
+		break
+	}
+	return g
+}
+
+// Accept reports whether the probability and odds clear both thresholds of
+// the odds band they fall in. The edge must strictly exceed MinEdge;
+// confidence may equal MinConfidence.
+func (g EdgeGate) Accept(probability, odds float64) bool {
+	g = g.ForOdds(odds)
+	if odds <= 1.0 {
+		return false
+	}
+	if probability < g.MinConfidence {
+		return false
+	}
+	return Edge(probability, odds) > g.MinEdge
+}
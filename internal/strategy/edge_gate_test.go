@@ -0,0 +1,137 @@
+package strategy
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/clever-better/internal/models"
+)
+
+func TestEdgeGateAccept(t *testing.T) {
+	gate := NewEdgeGate(0.1, 0.3)
+
+	tests := []struct {
+		name        string
+		probability float64
+		odds        float64
+		expected    bool
+	}{
+		{name: "clears both thresholds", probability: 0.35, odds: 4.0, expected: true},
+		{name: "edge below minimum", probability: 0.5, odds: 2.1, expected: false},
+		{name: "confidence below minimum", probability: 0.25, odds: 6.0, expected: false},
+		{name: "negative edge", probability: 0.4, odds: 2.0, expected: false},
+		{name: "invalid odds", probability: 0.9, odds: 1.0, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, gate.Accept(tt.probability, tt.odds))
+		})
+	}
+}
+
+// TestSimpleValueStrategyUsesEdgeGate asserts the strategy accepts exactly the
+// runners its edge gate accepts, which is the same gate the live ML filter uses.
+func TestSimpleValueStrategyUsesEdgeGate(t *testing.T) {
+	strat := NewSimpleValueStrategy()
+	strat.MinEdgeThreshold = 0.1
+	strat.MinConfidence = 0.3
+	gate := strat.EdgeGate()
+
+	tests := []struct {
+		name       string
+		odds       float64
+		formRating float64
+		expected   bool
+	}{
+		{name: "value at long odds", odds: 4.0, formRating: 5, expected: true},
+		{name: "no edge over market", odds: 4.0, formRating: 0, expected: false},
+		{name: "confidence too low", odds: 4.0, formRating: 2.5, expected: false},
+		{name: "value at short odds", odds: 2.0, formRating: 10, expected: true},
+		{name: "edge below threshold", odds: 2.0, formRating: 4, expected: false},
+	}
+
+	now := time.Now()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runner := &models.Runner{ID: uuid.New(), TrapNumber: 1, Name: "Runner", FormRating: &tt.formRating}
+			size := 100.0
+			snapshot := &models.OddsSnapshot{
+				Time:      now.Add(-time.Minute),
+				RunnerID:  runner.ID,
+				BackPrice: &tt.odds,
+				LayPrice:  &tt.odds,
+				BackSize:  &size,
+				LaySize:   &size,
+			}
+
+			signals, err := strat.Evaluate(context.Background(), Context{
+				Race:        &models.Race{ID: uuid.New()},
+				Runners:     []*models.Runner{runner},
+				OddsHistory: []*models.OddsSnapshot{snapshot},
+				CurrentTime: now,
+			})
+			require.NoError(t, err)
+
+			probability := strat.NormalizeProbability(1.0/tt.odds + tt.formRating*0.01)
+			assert.Equal(t, tt.expected, gate.Accept(probability, tt.odds))
+			assert.Equal(t, tt.expected, len(signals) == 1)
+		})
+	}
+}
+
+func longshotBands() []OddsBand {
+	return []OddsBand{
+		{MinOdds: 1.01, MaxOdds: 4.0, MinEdge: 0.02},
+		{MinOdds: 8.0, MinEdge: 0.10, MinConfidence: 0.08},
+	}
+}
+
+func TestEdgeGateOddsBands(t *testing.T) {
+	gate := NewEdgeGate(0.03, 0.05).WithBands(longshotBands())
+
+	assert.True(t, gate.Accept(0.525, 2.0), "a 5% edge clears the short odds band")
+	assert.False(t, gate.Accept(0.105, 10.0), "a 5% edge at 10.0 misses the longshot band")
+	assert.True(t, gate.Accept(0.115, 10.0), "a 15% edge at 10.0 clears it")
+	assert.True(t, gate.Accept(0.175, 6.0), "odds outside every band use the gate's own thresholds")
+
+	longshot := gate.ForOdds(12.0)
+	assert.Equal(t, 0.10, longshot.MinEdge)
+	assert.Equal(t, 0.08, longshot.MinConfidence)
+	assert.Equal(t, 0.05, gate.ForOdds(2.0).MinConfidence, "a zero band threshold keeps the gate's")
+}
+
+func TestSimpleValueStrategyAppliesOddsBands(t *testing.T) {
+	strat := NewSimpleValueStrategy()
+	strat.MinConfidence = 0.05
+	strat.OddsBands = longshotBands()
+
+	evaluate := func(odds, formRating float64) []Signal {
+		runner := &models.Runner{ID: uuid.New(), TrapNumber: 1, Name: "Runner", FormRating: &formRating}
+		size := 100.0
+		now := time.Now()
+		signals, err := strat.Evaluate(context.Background(), Context{
+			Race:    &models.Race{ID: uuid.New()},
+			Runners: []*models.Runner{runner},
+			OddsHistory: []*models.OddsSnapshot{{
+				Time:      now.Add(-time.Minute),
+				RunnerID:  runner.ID,
+				BackPrice: &odds,
+				LayPrice:  &odds,
+				BackSize:  &size,
+				LaySize:   &size,
+			}},
+			CurrentTime: now,
+		})
+		require.NoError(t, err)
+		return signals
+	}
+
+	// Form adds formRating% to the implied probability, a 5% edge at both prices
+	assert.Len(t, evaluate(2.0, 2.5), 1)
+	assert.Empty(t, evaluate(10.0, 0.5))
+}
@@ -27,6 +27,12 @@ type Signal struct {
 	ExpectedValue float64           `json:"expected_value"`
 	Reasoning     string            `json:"reasoning"`
 	Features      map[string]any    `json:"features,omitempty"`
+	// BSP requests the bet be taken at the Betfair Starting Price. Odds is
+	// then only the indicative price at decision time.
+	BSP           bool              `json:"bsp,omitempty"`
+	// ModelProbability is the ML win probability the signal was filtered on,
+	// set by the live ML filter
+	ModelProbability *float64       `json:"model_probability,omitempty"`
 }
 
 // Context provides the strategy with temporal-safe inputs
@@ -36,6 +42,39 @@ type Context struct {
 	OddsHistory       []*models.OddsSnapshot
 	HistoricalResults []*models.RaceResult
 	CurrentTime       time.Time
+	// IncludeReserves treats reserve runners as active. Withdrawn runners
+	// are always excluded.
+	IncludeReserves bool
+}
+
+// ActiveRunners returns the runners expected to run. Strategies evaluate
+// these rather than Runners so non-runners never get signals.
+func (c Context) ActiveRunners() []*models.Runner {
+	active := make([]*models.Runner, 0, len(c.Runners))
+	for _, runner := range c.Runners {
+		if runner.IsActive() || (c.IncludeReserves && runner.Status == models.RunnerStatusReserve) {
+			active = append(active, runner)
+		}
+	}
+	return active
+}
+
+// Overround returns the sum of implied probabilities of the active runners'
+// latest mid prices at CurrentTime. A fair book sums to 1; withdrawn runners
+// are left out so their stale prices do not inflate it.
+func (c Context) Overround() float64 {
+	latestOdds := latestOddsByRunner(c.OddsHistory, c.CurrentTime)
+	total := 0.0
+	for _, runner := range c.ActiveRunners() {
+		snapshot, ok := latestOdds[runner.ID]
+		if !ok {
+			continue
+		}
+		if mid := snapshot.GetMidPrice(); mid > 1 {
+			total += 1 / mid
+		}
+	}
+	return total
 }
 
 // StrategyMetadata describes a strategy for tracking and ML export
@@ -0,0 +1,83 @@
+package strategy
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/clever-better/internal/models"
+)
+
+// nonRunnerContext has an active runner, a withdrawn runner with a stale
+// short price and a reserve, each showing a value edge
+func nonRunnerContext() (Context, *models.Runner, *models.Runner, *models.Runner) {
+	now := time.Date(2024, 3, 1, 14, 0, 0, 0, time.UTC)
+	form := 25.0
+	active := &models.Runner{ID: uuid.New(), TrapNumber: 1, FormRating: &form, Status: models.RunnerStatusActive}
+	withdrawn := &models.Runner{ID: uuid.New(), TrapNumber: 2, FormRating: &form, Status: models.RunnerStatusWithdrawn}
+	reserve := &models.Runner{ID: uuid.New(), TrapNumber: 7, FormRating: &form, Status: models.RunnerStatusReserve}
+	price := func(v float64) *float64 { return &v }
+	snapshot := func(runner *models.Runner, back, lay float64) *models.OddsSnapshot {
+		return &models.OddsSnapshot{RunnerID: runner.ID, Time: now.Add(-time.Minute),
+			BackPrice: price(back), BackSize: price(100), LayPrice: price(lay), LaySize: price(100)}
+	}
+
+	return Context{
+		Race:    &models.Race{ID: uuid.New(), ScheduledStart: now.Add(5 * time.Minute)},
+		Runners: []*models.Runner{active, withdrawn, reserve},
+		OddsHistory: []*models.OddsSnapshot{
+			snapshot(active, 2.98, 3.02),
+			snapshot(withdrawn, 1.98, 2.02),
+			snapshot(reserve, 3.15, 3.25),
+		},
+		CurrentTime: now,
+	}, active, withdrawn, reserve
+}
+
+func TestStrategiesSkipNonRunners(t *testing.T) {
+	strategyCtx, active, withdrawn, reserve := nonRunnerContext()
+
+	signals, err := NewSimpleValueStrategy().Evaluate(context.Background(), strategyCtx)
+	require.NoError(t, err)
+	require.Len(t, signals, 1)
+	assert.Equal(t, active.ID, signals[0].RunnerID)
+
+	favourite, err := NewFavouriteBenchmarkStrategy(10).Evaluate(context.Background(), strategyCtx)
+	require.NoError(t, err)
+	require.Len(t, favourite, 1)
+	assert.Equal(t, active.ID, favourite[0].RunnerID, "the withdrawn runner's shorter price is ignored")
+
+	strategyCtx.IncludeReserves = true
+	signals, err = NewSimpleValueStrategy().Evaluate(context.Background(), strategyCtx)
+	require.NoError(t, err)
+	runnerIDs := make([]uuid.UUID, 0, len(signals))
+	for _, signal := range signals {
+		runnerIDs = append(runnerIDs, signal.RunnerID)
+	}
+	assert.ElementsMatch(t, []uuid.UUID{active.ID, reserve.ID}, runnerIDs, "reserves are evaluated when included")
+	assert.NotContains(t, runnerIDs, withdrawn.ID)
+}
+
+func TestOverroundExcludesNonRunners(t *testing.T) {
+	strategyCtx, _, _, _ := nonRunnerContext()
+
+	assert.InDelta(t, 1/3.0, strategyCtx.Overround(), 1e-9, "only the active runner's 3.0 mid counts")
+
+	strategyCtx.IncludeReserves = true
+	assert.InDelta(t, 1/3.0+1/3.2, strategyCtx.Overround(), 1e-9)
+
+	// Runners stored before statuses were recorded count as active
+	strategyCtx.Runners[0].Status = ""
+	assert.Len(t, strategyCtx.ActiveRunners(), 2)
+}
+
+func TestRunnerStatusFromBetfair(t *testing.T) {
+	assert.Equal(t, models.RunnerStatusActive, models.RunnerStatusFromBetfair("ACTIVE"))
+	assert.Equal(t, models.RunnerStatusActive, models.RunnerStatusFromBetfair(""))
+	assert.Equal(t, models.RunnerStatusWithdrawn, models.RunnerStatusFromBetfair("REMOVED"))
+	assert.Equal(t, models.RunnerStatusWithdrawn, models.RunnerStatusFromBetfair("removed_vacant"))
+	assert.Equal(t, models.RunnerStatusReserve, models.RunnerStatusFromBetfair("HIDDEN"))
+}
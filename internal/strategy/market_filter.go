@@ -0,0 +1,75 @@
+package strategy
+
+import (
+	"path"
+	"strings"
+
+	"github.com/yourusername/clever-better/internal/models"
+)
+
+// MarketRules lists race attributes to match. Patterns are case-insensitive
+// and may use shell wildcards, so "rom*" matches every venue starting "Rom".
+type MarketRules struct {
+	Venues    []string
+	RaceTypes []string
+	Grades    []string
+	Countries []string
+}
+
+// MarketFilter decides which races may be traded. A race matching any deny
+// pattern is excluded. When allow patterns are set for an attribute, the race
+// must match one of them. The live orchestrator and the backtest engine share
+// it so backtests trade the same markets as the bot.
+type MarketFilter struct {
+	allow MarketRules
+	deny  MarketRules
+}
+
+// NewMarketFilter creates a market filter from allow and deny rules
+func NewMarketFilter(allow, deny MarketRules) *MarketFilter {
+	return &MarketFilter{allow: allow, deny: deny}
+}
+
+// Allows reports whether a race may be traded. A nil filter allows every race.
+func (f *MarketFilter) Allows(race *models.Race) bool {
+	if f == nil {
+		return true
+	}
+	if race == nil {
+		return false
+	}
+
+	attributes := []struct {
+		value string
+		allow []string
+		deny  []string
+	}{
+		{race.Track, f.allow.Venues, f.deny.Venues},
+		{race.RaceType, f.allow.RaceTypes, f.deny.RaceTypes},
+		{race.Grade, f.allow.Grades, f.deny.Grades},
+		{race.CountryCode(), f.allow.Countries, f.deny.Countries},
+	}
+
+	for _, attribute := range attributes {
+		if matchesAny(attribute.deny, attribute.value) {
+			return false
+		}
+		if len(attribute.allow) > 0 && !matchesAny(attribute.allow, attribute.value) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchesAny reports whether value matches one of the patterns. Malformed
+// patterns never match.
+func matchesAny(patterns []string, value string) bool {
+	value = strings.ToLower(strings.TrimSpace(value))
+	for _, pattern := range patterns {
+		matched, err := path.Match(strings.ToLower(strings.TrimSpace(pattern)), value)
+		if err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,45 @@
+package strategy
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yourusername/clever-better/internal/models"
+)
+
+func TestMarketFilterDeny(t *testing.T) {
+	filter := NewMarketFilter(MarketRules{}, MarketRules{
+		Venues: []string{"Towcester", "rom*"},
+		Grades: []string{"A1?"},
+	})
+
+	assert.False(t, filter.Allows(&models.Race{Track: "towcester", Grade: "A3"}))
+	assert.False(t, filter.Allows(&models.Race{Track: "Romford", Grade: "A3"}))
+	assert.False(t, filter.Allows(&models.Race{Track: "Hove", Grade: "A10"}))
+	assert.True(t, filter.Allows(&models.Race{Track: "Hove", Grade: "A1"}))
+	assert.True(t, filter.Allows(&models.Race{Track: "Hove", Grade: "A3"}))
+}
+
+func TestMarketFilterAllow(t *testing.T) {
+	filter := NewMarketFilter(MarketRules{
+		Countries: []string{"GB", "IE"},
+		RaceTypes: []string{"flat", "hurdle"},
+	}, MarketRules{Venues: []string{"Kempton"}})
+
+	gb := json.RawMessage(`{"country_code":"GB"}`)
+	fr := json.RawMessage(`{"country_code":"FR"}`)
+
+	assert.True(t, filter.Allows(&models.Race{Track: "Ascot", RaceType: "Flat", Conditions: gb}))
+	assert.False(t, filter.Allows(&models.Race{Track: "Ascot", RaceType: "Steeplechase", Conditions: gb}), "race type not allowed")
+	assert.False(t, filter.Allows(&models.Race{Track: "Chantilly", RaceType: "Flat", Conditions: fr}), "country not allowed")
+	assert.False(t, filter.Allows(&models.Race{Track: "Ascot", RaceType: "Flat"}), "unknown country is not in the allowlist")
+	assert.False(t, filter.Allows(&models.Race{Track: "Kempton", RaceType: "Flat", Conditions: gb}), "deny wins over allow")
+}
+
+func TestMarketFilterNil(t *testing.T) {
+	var filter *MarketFilter
+	assert.True(t, filter.Allows(&models.Race{Track: "Hove"}))
+	assert.True(t, NewMarketFilter(MarketRules{}, MarketRules{}).Allows(&models.Race{Track: "Hove"}))
+	assert.False(t, NewMarketFilter(MarketRules{}, MarketRules{}).Allows(nil))
+}
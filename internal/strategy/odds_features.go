@@ -0,0 +1,139 @@
+package strategy
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/clever-better/internal/models"
+)
+
+// OddsFeatures are derived from one runner's odds history, oldest to newest
+type OddsFeatures struct {
+	// Momentum is the fractional change in mid price over each requested
+	// window of snapshots. Negative momentum means the price is shortening.
+	Momentum map[int]float64
+	// Volatility is the standard deviation of log returns of the mid price
+	Volatility float64
+	// VWAP is the last traded price weighted by volume traded between
+	// snapshots, or the mean last traded price when no volume was recorded
+	VWAP float64
+	// LTPBackSpread is the latest last traded price minus the best back price
+	LTPBackSpread float64
+	Snapshots     int
+}
+
+// RunnerSeries returns a runner's snapshots from a race's odds history
+func RunnerSeries(history []*models.OddsSnapshot, runnerID uuid.UUID) []*models.OddsSnapshot {
+	series := make([]*models.OddsSnapshot, 0)
+	for _, snapshot := range history {
+		if snapshot != nil && snapshot.RunnerID == runnerID {
+			series = append(series, snapshot)
+		}
+	}
+	return series
+}
+
+// ComputeOddsFeatures derives rolling features from a runner's snapshots.
+// The series is ordered by time first; snapshots without a price are ignored.
+// A window longer than the series has no momentum entry.
+func ComputeOddsFeatures(series []*models.OddsSnapshot, momentumWindows ...int) OddsFeatures {
+	priced := make([]*models.OddsSnapshot, 0, len(series))
+	for _, snapshot := range series {
+		if snapshot != nil && snapshot.GetMidPrice() > 1 {
+			priced = append(priced, snapshot)
+		}
+	}
+	sort.SliceStable(priced, func(i, j int) bool { return priced[i].Time.Before(priced[j].Time) })
+
+	features := OddsFeatures{Momentum: make(map[int]float64, len(momentumWindows)), Snapshots: len(priced)}
+	if len(priced) == 0 {
+		return features
+	}
+
+	last := len(priced) - 1
+	current := priced[last].GetMidPrice()
+	for _, window := range momentumWindows {
+		if window <= 0 || window > last {
+			continue
+		}
+		past := priced[last-window].GetMidPrice()
+		features.Momentum[window] = (current - past) / past
+	}
+
+	features.Volatility = logReturnVolatility(priced)
+	features.VWAP = volumeWeightedLTP(priced)
+	if latest := priced[last]; latest.LTP != nil && latest.BackPrice != nil {
+		features.LTPBackSpread = *latest.LTP - *latest.BackPrice
+	}
+
+	return features
+}
+
+// ToMap flattens the features for a signal's feature map or the ML service
+func (f OddsFeatures) ToMap() map[string]float64 {
+	out := map[string]float64{
+		"odds_volatility":      f.Volatility,
+		"odds_vwap":            f.VWAP,
+		"odds_ltp_back_spread": f.LTPBackSpread,
+	}
+	for window, momentum := range f.Momentum {
+		out[fmt.Sprintf("odds_momentum_%d", window)] = momentum
+	}
+	return out
+}
+
+func logReturnVolatility(series []*models.OddsSnapshot) float64 {
+	if len(series) < 3 {
+		return 0
+	}
+	returns := make([]float64, 0, len(series)-1)
+	for i := 1; i < len(series); i++ {
+		returns = append(returns, math.Log(series[i].GetMidPrice()/series[i-1].GetMidPrice()))
+	}
+
+	mean := 0.0
+	for _, r := range returns {
+		mean += r
+	}
+	mean /= float64(len(returns))
+
+	variance := 0.0
+	for _, r := range returns {
+		variance += (r - mean) * (r - mean)
+	}
+	return math.Sqrt(variance / float64(len(returns)-1))
+}
+
+// volumeWeightedLTP weights each last traded price by the growth in total
+// matched volume since the previous snapshot
+func volumeWeightedLTP(series []*models.OddsSnapshot) float64 {
+	var weighted, volume, sum float64
+	var count int
+	previousVolume := -1.0
+	for _, snapshot := range series {
+		if snapshot.LTP == nil {
+			continue
+		}
+		sum += *snapshot.LTP
+		count++
+		if snapshot.TotalVolume == nil {
+			continue
+		}
+		if previousVolume >= 0 && *snapshot.TotalVolume > previousVolume {
+			traded := *snapshot.TotalVolume - previousVolume
+			weighted += *snapshot.LTP * traded
+			volume += traded
+		}
+		previousVolume = *snapshot.TotalVolume
+	}
+
+	if volume > 0 {
+		return weighted / volume
+	}
+	if count > 0 {
+		return sum / float64(count)
+	}
+	return 0
+}
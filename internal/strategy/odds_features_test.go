@@ -0,0 +1,108 @@
+package strategy
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/clever-better/internal/models"
+)
+
+// priceSeries builds snapshots a minute apart with back = lay = ltp = price,
+// supplied newest last, and total volume growing by the given amounts
+func priceSeries(runnerID uuid.UUID, prices []float64, volumes []float64) []*models.OddsSnapshot {
+	start := time.Date(2024, 5, 1, 18, 0, 0, 0, time.UTC)
+	total := 0.0
+	series := make([]*models.OddsSnapshot, 0, len(prices))
+	for i, price := range prices {
+		p := price
+		snapshot := &models.OddsSnapshot{
+			Time:      start.Add(time.Duration(i) * time.Minute),
+			RunnerID:  runnerID,
+			BackPrice: &p,
+			LayPrice:  &p,
+			LTP:       &p,
+		}
+		if volumes != nil {
+			total += volumes[i]
+			v := total
+			snapshot.TotalVolume = &v
+		}
+		series = append(series, snapshot)
+	}
+	return series
+}
+
+func TestComputeOddsFeaturesMomentum(t *testing.T) {
+	runnerID := uuid.New()
+	steaming := priceSeries(runnerID, []float64{5.0, 4.8, 4.6, 4.4, 4.0}, nil)
+	drifting := priceSeries(runnerID, []float64{3.0, 3.1, 3.2, 3.4, 3.6}, nil)
+
+	// Shuffle to check the series is ordered by time
+	steaming[0], steaming[3] = steaming[3], steaming[0]
+
+	features := ComputeOddsFeatures(steaming, 1, 4, 10)
+	assert.InDelta(t, (4.0-4.4)/4.4, features.Momentum[1], 1e-9)
+	assert.InDelta(t, -0.2, features.Momentum[4], 1e-9)
+	assert.Less(t, features.Momentum[4], 0.0, "shortening price has negative momentum")
+	_, ok := features.Momentum[10]
+	assert.False(t, ok, "window longer than the series is omitted")
+
+	features = ComputeOddsFeatures(drifting, 4)
+	assert.InDelta(t, 0.2, features.Momentum[4], 1e-9)
+	assert.Greater(t, features.Momentum[4], 0.0, "drifting price has positive momentum")
+}
+
+func TestComputeOddsFeaturesVolatility(t *testing.T) {
+	runnerID := uuid.New()
+
+	flat := ComputeOddsFeatures(priceSeries(runnerID, []float64{4.0, 4.0, 4.0, 4.0}, nil))
+	assert.Zero(t, flat.Volatility)
+
+	// Log returns alternate +ln(1.1) and -ln(1.1): mean 0, sample stddev
+	// ln(1.1) * sqrt(4/3) over four returns
+	choppy := ComputeOddsFeatures(priceSeries(runnerID, []float64{4.0, 4.4, 4.0, 4.4, 4.0}, nil))
+	assert.InDelta(t, math.Log(1.1)*math.Sqrt(4.0/3.0), choppy.Volatility, 1e-9)
+
+	steady := ComputeOddsFeatures(priceSeries(runnerID, []float64{4.0, 4.04, 4.0, 4.04, 4.0}, nil))
+	assert.Greater(t, choppy.Volatility, steady.Volatility)
+}
+
+func TestComputeOddsFeaturesVWAPAndSpread(t *testing.T) {
+	runnerID := uuid.New()
+	series := priceSeries(runnerID, []float64{4.0, 5.0, 3.0}, []float64{100, 100, 300})
+
+	latest := series[2]
+	back := 2.9
+	latest.BackPrice = &back
+
+	features := ComputeOddsFeatures(series)
+	// Volume traded since the previous snapshot: 100 at 5.0, 300 at 3.0
+	assert.InDelta(t, (5.0*100+3.0*300)/400, features.VWAP, 1e-9)
+	assert.InDelta(t, 0.1, features.LTPBackSpread, 1e-9)
+	assert.Equal(t, 3, features.Snapshots)
+
+	noVolume := ComputeOddsFeatures(priceSeries(runnerID, []float64{4.0, 5.0, 3.0}, nil))
+	assert.InDelta(t, 4.0, noVolume.VWAP, 1e-9)
+}
+
+func TestRunnerSeriesAndToMap(t *testing.T) {
+	runnerID := uuid.New()
+	history := append(priceSeries(runnerID, []float64{4.0, 3.5}, nil), priceSeries(uuid.New(), []float64{9.0}, nil)...)
+
+	series := RunnerSeries(history, runnerID)
+	require.Len(t, series, 2)
+
+	features := ComputeOddsFeatures(series, 1).ToMap()
+	assert.InDelta(t, -0.125, features["odds_momentum_1"], 1e-9)
+	assert.Contains(t, features, "odds_volatility")
+	assert.Contains(t, features, "odds_vwap")
+	assert.Contains(t, features, "odds_ltp_back_spread")
+
+	empty := ComputeOddsFeatures(nil, 1)
+	assert.Empty(t, empty.Momentum)
+	assert.Zero(t, empty.Snapshots)
+}
@@ -0,0 +1,134 @@
+package strategy
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/yourusername/clever-better/internal/models"
+)
+
+// OddsBounds are the plausible prices for one racing discipline. A zero
+// MaxTickMove disables the jump check between consecutive prices.
+type OddsBounds struct {
+	MinOdds     float64
+	MaxOdds     float64
+	MaxTickMove int
+}
+
+// OddsSanityFilter rejects prices that are outside the plausible range for
+// the race's discipline or that jump too far from the previous price. The
+// ingestion validator and the executor share it so glitches are caught both
+// before storage and before placement.
+type OddsSanityFilter struct {
+	Greyhound OddsBounds
+	Horse     OddsBounds
+}
+
+// NewOddsSanityFilter creates an odds sanity filter with per-discipline bounds
+func NewOddsSanityFilter(greyhound, horse OddsBounds) *OddsSanityFilter {
+	return &OddsSanityFilter{
+		Greyhound: greyhound,
+		Horse:     horse,
+	}
+}
+
+// DefaultOddsSanityFilter returns the bounds used when none are configured
+func DefaultOddsSanityFilter() *OddsSanityFilter {
+	return NewOddsSanityFilter(
+		OddsBounds{MinOdds: 1.1, MaxOdds: 150, MaxTickMove: 30},
+		OddsBounds{MinOdds: 1.05, MaxOdds: 500, MaxTickMove: 30},
+	)
+}
+
+// BoundsFor returns the bounds for a race. A nil race uses greyhound bounds.
+func (f *OddsSanityFilter) BoundsFor(race *models.Race) OddsBounds {
+	if race != nil && race.IsHorseRace() {
+		return f.Horse
+	}
+	return f.Greyhound
+}
+
+// Check returns an error describing why price is implausible for the race.
+// previous is the last known price for the selection; zero skips the jump check.
+func (f *OddsSanityFilter) Check(race *models.Race, price, previous float64) error {
+	bounds := f.BoundsFor(race)
+
+	if price < bounds.MinOdds || price > bounds.MaxOdds {
+		return fmt.Errorf("odds %.2f outside plausible range %.2f-%.2f", price, bounds.MinOdds, bounds.MaxOdds)
+	}
+
+	if bounds.MaxTickMove > 0 && previous > 1.0 {
+		if moved := TicksBetween(previous, price); moved > bounds.MaxTickMove {
+			return fmt.Errorf("odds moved %d ticks from %.2f to %.2f, max %d", moved, previous, price, bounds.MaxTickMove)
+		}
+	}
+
+	return nil
+}
+
+// tickLadder is the Betfair price ladder: each band's upper price and increment
+var tickLadder = []struct {
+	upTo      float64
+	increment float64
+}{
+	{2, 0.01},
+	{3, 0.02},
+	{4, 0.05},
+	{6, 0.1},
+	{10, 0.2},
+	{20, 0.5},
+	{30, 1},
+	{50, 2},
+	{100, 5},
+	{1000, 10},
+}
+
+// TickIndex returns the number of ladder ticks between 1.0 and price
+func TickIndex(price float64) int {
+	ticks := 0.0
+	lower := 1.0
+	for _, band := range tickLadder {
+		if price <= band.upTo {
+			return int(math.Round(ticks + (price-lower)/band.increment))
+		}
+		ticks += (band.upTo - lower) / band.increment
+		lower = band.upTo
+	}
+	last := tickLadder[len(tickLadder)-1]
+	return int(math.Round(ticks + (price-lower)/last.increment))
+}
+
+// TicksBetween returns the number of ladder ticks separating two prices
+func TicksBetween(a, b float64) int {
+	diff := TickIndex(a) - TickIndex(b)
+	if diff < 0 {
+		return -diff
+	}
+	return diff
+}
+
+// PriceAtTick returns the ladder price index ticks above 1.0, clamped to the
+// ladder
+func PriceAtTick(index int) float64 {
+	if index < 1 {
+		index = 1
+	}
+	ticks := 0
+	lower := 1.0
+	for _, band := range tickLadder {
+		bandTicks := int(math.Round((band.upTo - lower) / band.increment))
+		if index <= ticks+bandTicks {
+			price := lower + float64(index-ticks)*band.increment
+			return math.Round(price*100) / 100
+		}
+		ticks += bandTicks
+		lower = band.upTo
+	}
+	return lower
+}
+
+// OffsetTicks moves price by ticks along the ladder, up for positive ticks.
+// The result is snapped to the ladder and clamped to 1.01-1000.
+func OffsetTicks(price float64, ticks int) float64 {
+	return PriceAtTick(TickIndex(price) + ticks)
+}
@@ -0,0 +1,70 @@
+package strategy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yourusername/clever-better/internal/models"
+)
+
+func TestTickIndex(t *testing.T) {
+	assert.Equal(t, 1, TickIndex(1.01))
+	assert.Equal(t, 100, TickIndex(2.0))
+	assert.Equal(t, 1, TicksBetween(2.0, 2.02))
+	assert.Equal(t, 2, TicksBetween(3.5, 3.4))
+	assert.Equal(t, 1, TicksBetween(990, 1000))
+}
+
+func TestOddsSanityFilterCheck(t *testing.T) {
+	filter := DefaultOddsSanityFilter()
+	greyhound := &models.Race{RaceType: "A1"}
+	horse := &models.Race{RaceType: "Flat"}
+
+	tests := []struct {
+		name     string
+		race     *models.Race
+		price    float64
+		previous float64
+		valid    bool
+	}{
+		{name: "normal price", race: greyhound, price: 3.5, valid: true},
+		{name: "spike above range", race: greyhound, price: 1001.0, valid: false},
+		{name: "below range", race: greyhound, price: 1.01, valid: false},
+		{name: "long odds allowed for horses", race: horse, price: 400, valid: true},
+		{name: "nil race uses greyhound bounds", race: nil, price: 400, valid: false},
+		{name: "small move", race: greyhound, price: 3.6, previous: 3.5, valid: true},
+		{name: "large jump", race: greyhound, price: 40, previous: 3.5, valid: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := filter.Check(tt.race, tt.price, tt.previous)
+			if tt.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestOffsetTicks(t *testing.T) {
+	assert.Equal(t, 2.02, OffsetTicks(2.0, 1))
+	assert.Equal(t, 1.99, OffsetTicks(2.0, -1))
+	assert.Equal(t, 3.1, OffsetTicks(3.0, 2))
+	assert.Equal(t, 2.96, OffsetTicks(3.0, -2))
+	assert.Equal(t, 1.01, OffsetTicks(1.02, -5), "clamped to the bottom of the ladder")
+	assert.Equal(t, 1000.0, OffsetTicks(990, 5), "clamped to the top of the ladder")
+	for _, price := range []float64{1.01, 1.5, 2.5, 3.45, 5.8, 9.4, 15.5, 27, 48, 95, 500} {
+		assert.Equal(t, price, PriceAtTick(TickIndex(price)))
+	}
+}
+
+func TestEntryPriceOffsetsTowardsFill(t *testing.T) {
+	base := BaseStrategy{EntryTickOffset: 2}
+	assert.Equal(t, 2.96, base.EntryPrice(models.BetSideBack, 3.0), "backs are posted lower")
+	assert.Equal(t, 3.1, base.EntryPrice(models.BetSideLay, 3.0), "lays are posted higher")
+
+	base.EntryTickOffset = 0
+	assert.Equal(t, 3.03, base.EntryPrice(models.BetSideLay, 3.03), "no offset leaves the price untouched")
+}
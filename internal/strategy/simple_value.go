@@ -17,6 +17,8 @@ type SimpleValueStrategy struct {
 	MinEdgeThreshold  float64
 	MinConfidence     float64
 	DefaultStake      float64
+	// OddsBands vary the edge and confidence thresholds by odds
+	OddsBands         []OddsBand
 }
 
 // NewSimpleValueStrategy creates a new simple value strategy
@@ -56,7 +58,7 @@ func (s *SimpleValueStrategy) Evaluate(ctx context.Context, strategyCtx Context)
 	latestOdds := latestOddsByRunner(strategyCtx.OddsHistory, currentTime)
 	var signals []Signal
 
-	for _, runner := range strategyCtx.Runners {
+	for _, runner := range strategyCtx.ActiveRunners() {
 		signal, ok := s.buildSignal(runner, latestOdds)
 		if !ok {
 			continue
@@ -89,12 +91,19 @@ func (s *SimpleValueStrategy) CalculateStake(signal Signal, bankroll float64) fl
 	return stake
 }
 
+// EdgeGate returns the edge gate built from the strategy thresholds and
+// odds bands
+func (s *SimpleValueStrategy) EdgeGate() EdgeGate {
+	return NewEdgeGate(s.MinEdgeThreshold, s.MinConfidence).WithBands(s.OddsBands)
+}
+
 // GetParameters returns strategy parameters for ML export
 func (s *SimpleValueStrategy) GetParameters() map[string]interface{} {
 	return map[string]interface{}{
 		"min_edge_threshold": s.MinEdgeThreshold,
 		"min_confidence":     s.MinConfidence,
 		"default_stake":      s.DefaultStake,
+		"entry_tick_offset":  s.EntryTickOffset,
 	}
 }
 
@@ -106,16 +115,18 @@ func (s *SimpleValueStrategy) buildSignal(runner *models.Runner, latestOdds map[
 	if !s.CheckLiquidity(snapshot) {
 		return Signal{}, false
 	}
-	odds := snapshot.GetMidPrice()
-	if err := s.ValidateOdds(odds); err != nil {
+	midPrice := snapshot.GetMidPrice()
+	if err := s.ValidateOdds(midPrice); err != nil {
 		return Signal{}, false
 	}
 
-	modelProbability := s.NormalizeProbability(s.estimateProbability(runner, odds))
-	edge := (modelProbability * odds) - 1.0
-	if edge <= s.MinEdgeThreshold || modelProbability < s.MinConfidence {
+	// Edge is judged at the price the order is posted at
+	modelProbability := s.NormalizeProbability(s.estimateProbability(runner, midPrice))
+	odds := s.EntryPrice(models.BetSideBack, midPrice)
+	if !s.EdgeGate().Accept(modelProbability, odds) {
 		return Signal{}, false
 	}
+	edge := Edge(modelProbability, odds)
 
 	stake := s.DefaultStake
 	if stake <= 0 {
@@ -0,0 +1,39 @@
+package strategy
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// ErrStakeBelowMinimum is returned when a stake rounds below the exchange
+// minimum
+var ErrStakeBelowMinimum = errors.New("stake below exchange minimum")
+
+// StakeRounding turns computed stakes into ones the exchange accepts. Betfair
+// rejects stakes below its minimum and only takes whole pence, so a Kelly
+// stake of 3.337 has to become 3.33 before it is placed.
+type StakeRounding struct {
+	MinStake  float64
+	Increment float64
+}
+
+// Round rounds stake down to a multiple of Increment, so the risk-approved
+// amount is never exceeded, and rejects it when the result is below
+// MinStake. The zero value leaves stakes unchanged.
+func (r StakeRounding) Round(stake float64) (float64, error) {
+	if r.MinStake <= 0 && r.Increment <= 0 {
+		return stake, nil
+	}
+
+	rounded := stake
+	if r.Increment > 0 {
+		// The epsilon keeps 3.33 / 0.01 = 332.999... from losing a penny
+		rounded = math.Floor(stake/r.Increment+1e-9) * r.Increment
+		rounded = math.Round(rounded*1e6) / 1e6
+	}
+	if rounded <= 0 || rounded < r.MinStake {
+		return 0, fmt.Errorf("%w: %.4f rounds to %.2f, minimum %.2f", ErrStakeBelowMinimum, stake, rounded, r.MinStake)
+	}
+	return rounded, nil
+}
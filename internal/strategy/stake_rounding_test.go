@@ -0,0 +1,31 @@
+package strategy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStakeRounding(t *testing.T) {
+	pence := StakeRounding{MinStake: 1, Increment: 0.01}
+
+	stake, err := pence.Round(3.337)
+	require.NoError(t, err)
+	assert.Equal(t, 3.33, stake, "rounds down to the increment")
+
+	stake, err = pence.Round(3.33)
+	require.NoError(t, err)
+	assert.Equal(t, 3.33, stake, "exact multiples are kept")
+
+	_, err = pence.Round(0.999)
+	assert.ErrorIs(t, err, ErrStakeBelowMinimum)
+
+	stake, err = StakeRounding{MinStake: 2, Increment: 0.5}.Round(7.9)
+	require.NoError(t, err)
+	assert.Equal(t, 7.5, stake)
+
+	stake, err = StakeRounding{}.Round(3.337)
+	require.NoError(t, err)
+	assert.Equal(t, 3.337, stake, "the zero value leaves stakes unchanged")
+}
@@ -0,0 +1,184 @@
+package strategy
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/yourusername/clever-better/internal/models"
+)
+
+// Staking plan names accepted in the "staking_plan" strategy parameter
+const (
+	StakingPlanLevel      = "level"
+	StakingPlanPercentage = "percentage"
+	StakingPlanKelly      = "kelly"
+	StakingPlanFibonacci  = "fibonacci"
+)
+
+// StakingPlan sizes the stake for a signal. history holds the strategy's
+// recent bets, most recent first, as returned by BetRepository.GetByStrategyID.
+// A zero stake means no bet.
+type StakingPlan interface {
+	Stake(signal Signal, bankroll float64, history []*models.Bet) float64
+}
+
+// HistoryAware is implemented by staking plans that size stakes from recent
+// results. HistoryLength is the number of settled bets the plan needs; plans
+// that do not implement it are given no history.
+type HistoryAware interface {
+	HistoryLength() int
+}
+
+// LevelStake stakes the same amount on every bet
+type LevelStake struct {
+	Amount float64
+}
+
+// Stake returns the level amount, capped at the bankroll
+func (p LevelStake) Stake(signal Signal, bankroll float64, history []*models.Bet) float64 {
+	return capStake(p.Amount, bankroll)
+}
+
+// PercentageStake stakes a fixed fraction of the current bankroll
+type PercentageStake struct {
+	Percent float64
+}
+
+// Stake returns Percent of the bankroll
+func (p PercentageStake) Stake(signal Signal, bankroll float64, history []*models.Bet) float64 {
+	return capStake(bankroll*p.Percent, bankroll)
+}
+
+// KellyStake sizes stakes with fractional Kelly, using the signal confidence
+// as the win probability
+type KellyStake struct {
+	Fraction float64
+}
+
+// Stake returns the fractional Kelly stake for the signal. For lay signals
+// the Kelly fraction applies to the liability.
+func (p KellyStake) Stake(signal Signal, bankroll float64, history []*models.Bet) float64 {
+	if bankroll <= 0 || signal.Odds <= 1 || signal.Confidence <= 0 || signal.Confidence >= 1 {
+		return 0
+	}
+	fraction := p.Fraction
+	if fraction <= 0 {
+		fraction = 0.5
+	}
+
+	prob := signal.Confidence
+	b := signal.Odds - 1.0
+	if signal.Side == models.BetSideLay {
+		// Laying risks b per unit of stake to win 1 when the runner loses
+		kelly := (1 - prob) - prob*b
+		if kelly <= 0 {
+			return 0
+		}
+		return capStake(bankroll*kelly*fraction/b, bankroll)
+	}
+
+	kelly := (b*prob - (1 - prob)) / b
+	if kelly <= 0 {
+		return 0
+	}
+	return capStake(bankroll*kelly*fraction, bankroll)
+}
+
+// FibonacciStake is a recovery plan. Each loss moves one step along the
+// Fibonacci sequence and each win moves back two, so a loss run is recovered
+// by a couple of wins. MaxSteps caps how far a loss run can escalate.
+type FibonacciStake struct {
+	Unit     float64
+	MaxSteps int
+}
+
+// HistoryLength returns how many settled bets are needed to find the
+// current position in the sequence
+func (p FibonacciStake) HistoryLength() int {
+	return p.maxSteps() * 4
+}
+
+// Stake returns Unit times the Fibonacci number for the current position
+func (p FibonacciStake) Stake(signal Signal, bankroll float64, history []*models.Bet) float64 {
+	step := 0
+	for i := len(history) - 1; i >= 0; i-- {
+		bet := history[i]
+		if bet.Status != models.BetStatusSettled || bet.ProfitLoss == nil {
+			continue
+		}
+		if *bet.ProfitLoss < 0 {
+			step = min(step+1, p.maxSteps())
+		} else if *bet.ProfitLoss > 0 {
+			step = max(step-2, 0)
+		}
+	}
+	return capStake(p.Unit*fibonacci(step), bankroll)
+}
+
+func (p FibonacciStake) maxSteps() int {
+	if p.MaxSteps <= 0 {
+		return 8
+	}
+	return p.MaxSteps
+}
+
+// fibonacci returns the nth term of 1, 1, 2, 3, 5, ...
+func fibonacci(n int) float64 {
+	a, b := 1.0, 1.0
+	for i := 0; i < n; i++ {
+		a, b = b, a+b
+	}
+	return a
+}
+
+func capStake(stake, bankroll float64) float64 {
+	if bankroll <= 0 || stake <= 0 || math.IsNaN(stake) {
+		return 0
+	}
+	return math.Min(stake, bankroll)
+}
+
+// StakingPlanFromParameters builds the staking plan a strategy declares in
+// its parameters. It returns nil when no "staking_plan" is set, leaving the
+// strategy's own signal stakes in place.
+func StakingPlanFromParameters(params map[string]interface{}) (StakingPlan, error) {
+	name, _ := params["staking_plan"].(string)
+	switch name {
+	case "":
+		return nil, nil
+	case StakingPlanLevel:
+		amount := floatParam(params, "stake_amount", 0)
+		if amount <= 0 {
+			return nil, fmt.Errorf("level staking requires a positive stake_amount")
+		}
+		return LevelStake{Amount: amount}, nil
+	case StakingPlanPercentage:
+		percent := floatParam(params, "stake_percent", 0)
+		if percent <= 0 || percent > 1 {
+			return nil, fmt.Errorf("percentage staking requires stake_percent in (0, 1]")
+		}
+		return PercentageStake{Percent: percent}, nil
+	case StakingPlanKelly:
+		return KellyStake{Fraction: floatParam(params, "kelly_fraction", 0.5)}, nil
+	case StakingPlanFibonacci:
+		unit := floatParam(params, "stake_unit", 0)
+		if unit <= 0 {
+			return nil, fmt.Errorf("fibonacci staking requires a positive stake_unit")
+		}
+		return FibonacciStake{Unit: unit, MaxSteps: int(floatParam(params, "max_steps", 0))}, nil
+	default:
+		return nil, fmt.Errorf("unknown staking plan: %s", name)
+	}
+}
+
+// floatParam reads a numeric parameter decoded from JSON
+func floatParam(params map[string]interface{}, key string, fallback float64) float64 {
+	switch v := params[key].(type) {
+	case float64:
+		return v
+	case int:
+		return float64(v)
+	default:
+		return fallback
+	}
+}
@@ -0,0 +1,113 @@
+package strategy
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/clever-better/internal/models"
+)
+
+// settledHistory builds settled bets from profit/loss values given oldest
+// first, returned most recent first as the bet repository orders them
+func settledHistory(results ...float64) []*models.Bet {
+	base := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	history := make([]*models.Bet, len(results))
+	for i, pl := range results {
+		pl := pl
+		settledAt := base.Add(time.Duration(i) * time.Minute)
+		history[len(results)-1-i] = &models.Bet{
+			ID:         uuid.New(),
+			Stake:      10,
+			Status:     models.BetStatusSettled,
+			PlacedAt:   settledAt,
+			SettledAt:  &settledAt,
+			ProfitLoss: &pl,
+		}
+	}
+	return history
+}
+
+func TestLevelStake(t *testing.T) {
+	plan := LevelStake{Amount: 10}
+	signal := Signal{Odds: 3.0, Confidence: 0.4}
+
+	assert.Equal(t, 10.0, plan.Stake(signal, 1000, nil), "first bet")
+	assert.Equal(t, 10.0, plan.Stake(signal, 1000, settledHistory(-10, -10, -10)), "unchanged after a loss run")
+	assert.Equal(t, 5.0, plan.Stake(signal, 5, nil), "capped at bankroll")
+}
+
+func TestPercentageStake(t *testing.T) {
+	plan := PercentageStake{Percent: 0.02}
+	signal := Signal{Odds: 3.0, Confidence: 0.4}
+
+	assert.InDelta(t, 20.0, plan.Stake(signal, 1000, nil), 1e-9, "first bet")
+	// After a loss run the bank has shrunk, and so does the stake
+	assert.InDelta(t, 19.4, plan.Stake(signal, 970, settledHistory(-10, -10, -10)), 1e-9)
+	assert.Zero(t, plan.Stake(signal, 0, nil))
+}
+
+func TestKellyStake(t *testing.T) {
+	plan := KellyStake{Fraction: 0.5}
+
+	// p=0.4 at 3.0: full Kelly (2*0.4 - 0.6)/2 = 0.1, half Kelly = 5% of bank
+	back := Signal{Side: models.BetSideBack, Odds: 3.0, Confidence: 0.4}
+	assert.InDelta(t, 50.0, plan.Stake(back, 1000, nil), 1e-9, "first bet")
+	assert.InDelta(t, 50.0, plan.Stake(back, 1000, settledHistory(-50, -50, -50)), 1e-9, "history does not change Kelly")
+
+	noEdge := Signal{Side: models.BetSideBack, Odds: 2.0, Confidence: 0.4}
+	assert.Zero(t, plan.Stake(noEdge, 1000, nil))
+
+	// p=0.2 at 3.0 laid: liability fraction 0.8 - 0.4 = 0.4, half = 200 liability, stake 100
+	lay := Signal{Side: models.BetSideLay, Odds: 3.0, Confidence: 0.2}
+	assert.InDelta(t, 100.0, plan.Stake(lay, 1000, nil), 1e-9)
+}
+
+func TestFibonacciStake(t *testing.T) {
+	plan := FibonacciStake{Unit: 2, MaxSteps: 5}
+	signal := Signal{Odds: 3.0, Confidence: 0.4}
+
+	assert.Equal(t, 2.0, plan.Stake(signal, 1000, nil), "first bet is one unit")
+
+	// Losses step along 1, 1, 2, 3, 5, 8
+	assert.Equal(t, 2.0, plan.Stake(signal, 1000, settledHistory(-2)))
+	assert.Equal(t, 6.0, plan.Stake(signal, 1000, settledHistory(-2, -2, -4)))
+	assert.Equal(t, 16.0, plan.Stake(signal, 1000, settledHistory(-2, -2, -4, -6, -10, -16, -26)), "capped at MaxSteps")
+
+	// A win after the loss run steps back two
+	assert.Equal(t, 4.0, plan.Stake(signal, 1000, settledHistory(-2, -2, -4, -6, 12)))
+
+	// Unsettled bets are ignored
+	pending := &models.Bet{ID: uuid.New(), Status: models.BetStatusPending}
+	assert.Equal(t, 2.0, plan.Stake(signal, 1000, append([]*models.Bet{pending}, settledHistory(-2)...)))
+}
+
+func TestStakingPlanFromParameters(t *testing.T) {
+	plan, err := StakingPlanFromParameters(map[string]interface{}{"default_stake": 5.0})
+	require.NoError(t, err)
+	assert.Nil(t, plan, "no plan declared")
+
+	plan, err = StakingPlanFromParameters(map[string]interface{}{"staking_plan": "level", "stake_amount": 10.0})
+	require.NoError(t, err)
+	assert.Equal(t, LevelStake{Amount: 10}, plan)
+
+	plan, err = StakingPlanFromParameters(map[string]interface{}{"staking_plan": "percentage", "stake_percent": 0.01})
+	require.NoError(t, err)
+	assert.Equal(t, PercentageStake{Percent: 0.01}, plan)
+
+	plan, err = StakingPlanFromParameters(map[string]interface{}{"staking_plan": "kelly"})
+	require.NoError(t, err)
+	assert.Equal(t, KellyStake{Fraction: 0.5}, plan)
+
+	plan, err = StakingPlanFromParameters(map[string]interface{}{"staking_plan": "fibonacci", "stake_unit": 2.0, "max_steps": 6.0})
+	require.NoError(t, err)
+	assert.Equal(t, FibonacciStake{Unit: 2, MaxSteps: 6}, plan)
+
+	_, err = StakingPlanFromParameters(map[string]interface{}{"staking_plan": "level"})
+	assert.Error(t, err)
+
+	_, err = StakingPlanFromParameters(map[string]interface{}{"staking_plan": "martingale"})
+	assert.Error(t, err)
+}
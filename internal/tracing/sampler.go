@@ -0,0 +1,53 @@
+package tracing
+
+import (
+	"hash/fnv"
+	"math"
+	"time"
+)
+
+// TailSampler decides which finished traces to keep. Every trace is recorded,
+// and the decision is made once the outcome is known, so failures and slow
+// trading evaluations are never lost to the base rate.
+type TailSampler struct {
+	// BaseRate is the fraction of healthy, fast traces kept
+	BaseRate float64
+	// SlowThreshold keeps any trace that took at least this long. Zero
+	// disables the slow rule.
+	SlowThreshold time.Duration
+}
+
+// Sample reports whether to keep a finished trace. Failed and slow traces are
+// always kept. The rest are kept at BaseRate, decided by trace ID so every
+// document belonging to a trace gets the same answer.
+func (s TailSampler) Sample(traceID string, duration time.Duration, failed bool) bool {
+	if failed || s.IsSlow(duration) {
+		return true
+	}
+	if s.BaseRate <= 0 {
+		return false
+	}
+	if s.BaseRate >= 1 {
+		return true
+	}
+	return float64(traceHash(traceID))/math.MaxUint64 < s.BaseRate
+}
+
+// traceHash hashes a trace ID uniformly over uint64. FNV alone leaves
+// similar IDs clustered, so its output is passed through a mixing finaliser.
+func traceHash(traceID string) uint64 {
+	hash := fnv.New64a()
+	hash.Write([]byte(traceID))
+	h := hash.Sum64()
+	h ^= h >> 33
+	h *= 0xff51afd7ed558ccd
+	h ^= h >> 33
+	h *= 0xc4ceb9fe1a85ec53
+	h ^= h >> 33
+	return h
+}
+
+// IsSlow reports whether a duration meets the slow threshold
+func (s TailSampler) IsSlow(duration time.Duration) bool {
+	return s.SlowThreshold > 0 && duration >= s.SlowThreshold
+}
@@ -0,0 +1,48 @@
+package tracing
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTailSamplerKeepsErrorsAndSlowTraces(t *testing.T) {
+	sampler := TailSampler{BaseRate: 0.1, SlowThreshold: time.Second}
+
+	for i := 0; i < 1000; i++ {
+		traceID := fmt.Sprintf("1-5f84c7a1-%024d", i)
+		assert.True(t, sampler.Sample(traceID, 10*time.Millisecond, true), "failed traces are always kept")
+		assert.True(t, sampler.Sample(traceID, time.Second, false), "slow traces are always kept")
+	}
+
+	none := TailSampler{BaseRate: 0, SlowThreshold: time.Second}
+	assert.True(t, none.Sample("1-5f84c7a1-a", 0, true), "errors are kept even at a zero base rate")
+	assert.True(t, none.Sample("1-5f84c7a1-a", 2*time.Second, false))
+	assert.False(t, none.Sample("1-5f84c7a1-a", 999*time.Millisecond, false))
+}
+
+func TestTailSamplerKeepsNormalTracesAtBaseRate(t *testing.T) {
+	for _, rate := range []float64{0.05, 0.1, 0.5} {
+		sampler := TailSampler{BaseRate: rate, SlowThreshold: time.Second}
+		const traces = 20000
+		kept := 0
+		for i := 0; i < traces; i++ {
+			if sampler.Sample(fmt.Sprintf("1-5f84c7a1-%024d", i), 50*time.Millisecond, false) {
+				kept++
+			}
+		}
+		assert.InDelta(t, rate, float64(kept)/traces, 0.01, "rate %v", rate)
+	}
+}
+
+func TestTailSamplerDecidesConsistentlyPerTrace(t *testing.T) {
+	sampler := TailSampler{BaseRate: 0.5}
+	for i := 0; i < 100; i++ {
+		traceID := fmt.Sprintf("1-5f84c7a1-%024d", i)
+		first := sampler.Sample(traceID, time.Millisecond, false)
+		assert.Equal(t, first, sampler.Sample(traceID, time.Millisecond, false))
+	}
+	assert.False(t, sampler.IsSlow(time.Hour), "no threshold means nothing is slow")
+}
@@ -3,20 +3,47 @@ package tracing
 
 import (
 	"context"
+	"fmt"
+	"math"
+	"net"
+	"sync"
+	"time"
 
+	"github.com/aws/aws-xray-sdk-go/daemoncfg"
+	"github.com/aws/aws-xray-sdk-go/strategy/sampling"
 	"github.com/aws/aws-xray-sdk-go/xray"
 	"github.com/aws/aws-xray-sdk-go/xraylog"
 	"github.com/sirupsen/logrus"
+
+	applogger "github.com/yourusername/clever-better/internal/logger"
+)
+
+// tailSampleAnnotation is set on a trace's segment when it is kept
+// regardless of the base rate, recording why.
+const tailSampleAnnotation = "tail_sampled"
+
+// Reasons recorded in the tail_sampled annotation.
+const (
+	tailSampleError = "error"
+	tailSampleSlow  = "slow"
 )
 
 // Config contains X-Ray configuration.
 type Config struct {
 	ServiceName    string
 	Enabled        bool
+	// SamplingRate is the fraction of healthy, fast traces kept
 	SamplingRate   float64
+	// SlowThreshold keeps every trace with a subsegment at least this slow
+	SlowThreshold  time.Duration
 	DaemonAddr     string
 }
 
+var (
+	samplerMu sync.RWMutex
+	sampler   = TailSampler{BaseRate: 1}
+)
+
 // Logger adapter for X-Ray SDK.
 type xrayLoggerAdapter struct {
 	logger *logrus.Logger
@@ -44,21 +71,105 @@ func Initialize(cfg Config, logger *logrus.Logger) error {
 	// Set X-Ray logger
 	xraylog.SetLogger(&xrayLoggerAdapter{logger: logger})
 
-	// Configure X-Ray
-	xray.Configure(xray.Config{
-		DaemonAddr:   cfg.DaemonAddr,
-		SamplingRate: cfg.SamplingRate,
-	})
+	if cfg.SamplingRate < 0 || cfg.SamplingRate > 1 {
+		return fmt.Errorf("sampling rate must be between 0 and 1, got %v", cfg.SamplingRate)
+	}
+	endpoints, err := daemoncfg.GetDaemonEndpointsFromString(cfg.DaemonAddr)
+	if err != nil {
+		return fmt.Errorf("failed to parse daemon address: %w", err)
+	}
+	if endpoints == nil {
+		endpoints = daemoncfg.GetDefaultDaemonEndpoints()
+	}
+	emitter, err := xray.NewDefaultEmitter(endpoints.UDPAddr)
+	if err != nil {
+		return fmt.Errorf("failed to create emitter: %w", err)
+	}
+	// Subsegments are never streamed early, so the sampling decision sees
+	// each trace whole
+	streaming, err := xray.NewDefaultStreamingStrategyWithMaxSubsegmentCount(math.MaxInt32)
+	if err != nil {
+		return fmt.Errorf("failed to create streaming strategy: %w", err)
+	}
+
+	samplerMu.Lock()
+	sampler = TailSampler{BaseRate: cfg.SamplingRate, SlowThreshold: cfg.SlowThreshold}
+	samplerMu.Unlock()
+
+	// Record every trace and decide what to keep once it has finished
+	if err := xray.Configure(xray.Config{
+		DaemonAddr:        cfg.DaemonAddr,
+		Emitter:           &tailSamplingEmitter{next: emitter},
+		SamplingStrategy:  recordAll{},
+		StreamingStrategy: streaming,
+	}); err != nil {
+		return fmt.Errorf("failed to configure X-Ray: %w", err)
+	}
+
+	// Tag log entries written with a traced context with their trace
+	applogger.AddCorrelationHook(logger, TraceIDs)
 
 	logger.WithFields(logrus.Fields{
 		"daemon_addr":    cfg.DaemonAddr,
 		"sampling_rate":  cfg.SamplingRate,
+		"slow_threshold": cfg.SlowThreshold,
 		"service_name":   cfg.ServiceName,
 	}).Info("AWS X-Ray initialized")
 
 	return nil
 }
 
+// currentSampler returns the sampler set by Initialize
+func currentSampler() TailSampler {
+	samplerMu.RLock()
+	defer samplerMu.RUnlock()
+	return sampler
+}
+
+// recordAll records every trace; tailSamplingEmitter decides what is kept
+type recordAll struct{}
+
+func (recordAll) ShouldTrace(*sampling.Request) *sampling.Decision {
+	return &sampling.Decision{Sample: true}
+}
+
+// tailSamplingEmitter forwards finished segments that the tail sampler keeps
+type tailSamplingEmitter struct {
+	next xray.Emitter
+}
+
+// Emit is called with the segment locked, so it reads fields directly
+func (e *tailSamplingEmitter) Emit(seg *xray.Segment) {
+	_, marked := seg.Annotations[tailSampleAnnotation]
+	failed := marked || seg.Error || seg.Fault || seg.Throttle
+	duration := time.Duration((seg.EndTime - seg.StartTime) * float64(time.Second))
+	if currentSampler().Sample(seg.TraceID, duration, failed) {
+		e.next.Emit(seg)
+	}
+}
+
+func (e *tailSamplingEmitter) RefreshEmitterWithAddress(raddr *net.UDPAddr) {
+	e.next.RefreshEmitterWithAddress(raddr)
+}
+
+// markTrace records on a segment's trace that it must be kept
+func markTrace(seg *xray.Segment, reason string) {
+	if seg == nil || seg.ParentSegment == nil {
+		return
+	}
+	seg.ParentSegment.AddAnnotation(tailSampleAnnotation, reason)
+}
+
+// TraceIDs returns the X-Ray trace ID and the current segment or subsegment
+// ID for ctx. Both are empty outside a traced context.
+func TraceIDs(ctx context.Context) (string, string) {
+	seg := xray.GetSegment(ctx)
+	if seg == nil {
+		return "", ""
+	}
+	return xray.TraceID(ctx), seg.ID
+}
+
 // StartSegment starts a new X-Ray segment.
 func StartSegment(ctx context.Context, segmentName string) (context.Context, *xray.Segment) {
 	return xray.BeginSegment(ctx, segmentName)
@@ -83,9 +194,31 @@ func AddMetadata(ctx context.Context, key string, value interface{}) {
 	}
 }
 
-// AddError adds an error to the current segment.
+// AddError adds an error to the current segment and keeps its trace.
 func AddError(ctx context.Context, err error) {
 	if seg := xray.GetSegment(ctx); seg != nil {
 		seg.AddError(err)
+		markTrace(seg, tailSampleError)
 	}
 }
+
+// EndSubsegment closes a subsegment. Its trace is kept if err is non-nil or
+// the subsegment ran for at least the slow threshold.
+func EndSubsegment(seg *xray.Segment, err error) {
+	if seg == nil {
+		return
+	}
+	if err != nil {
+		markTrace(seg, tailSampleError)
+	} else if currentSampler().IsSlow(time.Since(segmentStart(seg))) {
+		markTrace(seg, tailSampleSlow)
+	}
+	seg.Close(err)
+}
+
+// segmentStart returns when a segment began
+func segmentStart(seg *xray.Segment) time.Time {
+	seg.RLock()
+	defer seg.RUnlock()
+	return time.Unix(0, int64(seg.StartTime*float64(time.Second)))
+}
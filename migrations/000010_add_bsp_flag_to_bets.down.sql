@@ -0,0 +1,2 @@
+-- Remove BSP flag from bets table
+ALTER TABLE bets DROP COLUMN IF EXISTS is_bsp;
@@ -0,0 +1,4 @@
+-- Flag bets placed at Betfair Starting Price (MARKET_ON_CLOSE orders).
+-- For these bets odds holds the indicative price at placement and may be 0;
+-- matched_price holds the final starting price once known.
+ALTER TABLE bets ADD COLUMN is_bsp BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_backtest_results_unprocessed;
+ALTER TABLE backtest_results DROP COLUMN IF EXISTS ml_feedback_submitted_at;
+ALTER TABLE backtest_results DROP COLUMN IF EXISTS ml_feedback_submitted;
@@ -0,0 +1,9 @@
+-- Track which backtest results have been submitted to the ML service as
+-- feedback. Submission scans unprocessed rows oldest first from a
+-- high-water mark, so index that access path.
+ALTER TABLE backtest_results ADD COLUMN IF NOT EXISTS ml_feedback_submitted BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE backtest_results ADD COLUMN IF NOT EXISTS ml_feedback_submitted_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_backtest_results_unprocessed
+    ON backtest_results(created_at, id)
+    WHERE ml_feedback_submitted = FALSE;
@@ -0,0 +1,7 @@
+-- Per-source cursor for resumable historical sync. last_race_time is the
+-- scheduled start of the latest race committed by a successful batch.
+CREATE TABLE IF NOT EXISTS sync_cursors (
+    source TEXT PRIMARY KEY,
+    last_race_time TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_runners_race_trap;
+CREATE UNIQUE INDEX idx_runners_race_trap ON runners(race_id, trap_number);
@@ -0,0 +1,4 @@
+-- Horse races without stalls store trap_number 0 for every runner, so only
+-- enforce unique traps/stalls when one is set.
+DROP INDEX IF EXISTS idx_runners_race_trap;
+CREATE UNIQUE INDEX idx_runners_race_trap ON runners(race_id, trap_number) WHERE trap_number > 0;
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_runners_betfair_selection;
@@ -0,0 +1,5 @@
+-- Runners ingested from Betfair carry their market and selection IDs in
+-- metadata; index them so placement can resolve a runner by selection.
+CREATE INDEX IF NOT EXISTS idx_runners_betfair_selection
+    ON runners ((metadata->>'market_id'), (metadata->>'selection_id'))
+    WHERE metadata ? 'selection_id';
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS ml_feedback_dead_letters;
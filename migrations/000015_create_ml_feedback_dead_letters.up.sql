@@ -0,0 +1,15 @@
+-- ML feedback submissions that failed, kept for redelivery. One row per
+-- backtest result; the retry job deletes it once delivery succeeds.
+CREATE TABLE IF NOT EXISTS ml_feedback_dead_letters (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    backtest_result_id UUID NOT NULL UNIQUE REFERENCES backtest_results(id) ON DELETE CASCADE,
+    payload JSONB NOT NULL,
+    last_error TEXT NOT NULL DEFAULT '',
+    attempts INTEGER NOT NULL DEFAULT 1,
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_ml_feedback_dead_letters_next_attempt
+    ON ml_feedback_dead_letters(next_attempt_at);
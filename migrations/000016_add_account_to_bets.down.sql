@@ -0,0 +1,3 @@
+-- Remove account from bets table
+DROP INDEX IF EXISTS idx_bets_account_status;
+ALTER TABLE bets DROP COLUMN IF EXISTS account;
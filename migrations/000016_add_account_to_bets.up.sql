@@ -0,0 +1,5 @@
+-- Record which Betfair account a bet was placed through so it can be
+-- cancelled and reconciled on the same account. Empty means the primary.
+ALTER TABLE bets ADD COLUMN account VARCHAR(100) NOT NULL DEFAULT '';
+
+CREATE INDEX idx_bets_account_status ON bets(account, status);
@@ -0,0 +1,3 @@
+-- Remove tags from backtest_results table
+DROP INDEX IF EXISTS idx_backtest_results_tags;
+ALTER TABLE backtest_results DROP COLUMN IF EXISTS tags;
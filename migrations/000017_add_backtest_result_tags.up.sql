@@ -0,0 +1,5 @@
+-- Tag backtest runs (strategy, date range, market subset, ...) so analysts
+-- can find earlier runs with GetByTag.
+ALTER TABLE backtest_results ADD COLUMN tags TEXT[] NOT NULL DEFAULT '{}';
+
+CREATE INDEX idx_backtest_results_tags ON backtest_results USING GIN (tags);
@@ -0,0 +1,5 @@
+-- Remove signal reasoning from bets table
+ALTER TABLE bets DROP COLUMN IF EXISTS model_probability;
+ALTER TABLE bets DROP COLUMN IF EXISTS confidence;
+ALTER TABLE bets DROP COLUMN IF EXISTS expected_value;
+ALTER TABLE bets DROP COLUMN IF EXISTS reasoning;
@@ -0,0 +1,6 @@
+-- Keep why each bet was placed so it can be explained later. Bets placed
+-- before this migration have no reasoning and NULL scores.
+ALTER TABLE bets ADD COLUMN reasoning TEXT NOT NULL DEFAULT '';
+ALTER TABLE bets ADD COLUMN expected_value DECIMAL(10, 6);
+ALTER TABLE bets ADD COLUMN confidence DECIMAL(10, 6);
+ALTER TABLE bets ADD COLUMN model_probability DECIMAL(10, 6);
@@ -0,0 +1,2 @@
+-- Remove runner status from runners table
+ALTER TABLE runners DROP COLUMN IF EXISTS status;
@@ -0,0 +1,5 @@
+-- Record whether each runner is expected to run. Withdrawn runners and
+-- reserves stay listed so results still match by trap, but strategies skip
+-- them. Runners stored before this migration are treated as active.
+ALTER TABLE runners ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'active'
+    CHECK (status IN ('active', 'withdrawn', 'reserve'));
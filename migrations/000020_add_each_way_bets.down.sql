@@ -0,0 +1,6 @@
+-- Remove each-way legs from bets table
+ALTER TABLE bets DROP COLUMN IF EXISTS place_profit_loss;
+ALTER TABLE bets DROP COLUMN IF EXISTS win_profit_loss;
+ALTER TABLE bets DROP COLUMN IF EXISTS places_paid;
+ALTER TABLE bets DROP COLUMN IF EXISTS place_fraction;
+ALTER TABLE bets DROP COLUMN IF EXISTS each_way;
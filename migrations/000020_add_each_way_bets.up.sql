@@ -0,0 +1,8 @@
+-- Each-way bets stake the same amount on a win leg and a place leg. The
+-- legs settle independently, so each leg's result is kept alongside the
+-- combined profit_loss.
+ALTER TABLE bets ADD COLUMN each_way BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE bets ADD COLUMN place_fraction DECIMAL(6, 4) NOT NULL DEFAULT 0;
+ALTER TABLE bets ADD COLUMN places_paid INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE bets ADD COLUMN win_profit_loss DECIMAL(10, 2);
+ALTER TABLE bets ADD COLUMN place_profit_loss DECIMAL(10, 2);
@@ -0,0 +1,3 @@
+-- Remove price ladders from odds_snapshots table
+ALTER TABLE odds_snapshots DROP COLUMN IF EXISTS lay_ladder;
+ALTER TABLE odds_snapshots DROP COLUMN IF EXISTS back_ladder;
@@ -0,0 +1,5 @@
+-- Keep the price levels on offer beyond the top of book so fills and
+-- liquidity checks can consider a bounded ladder depth. Snapshots recorded
+-- before this migration only have the top-of-book size.
+ALTER TABLE odds_snapshots ADD COLUMN back_ladder JSONB;
+ALTER TABLE odds_snapshots ADD COLUMN lay_ladder JSONB;
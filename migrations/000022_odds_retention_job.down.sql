@@ -0,0 +1,4 @@
+-- Restore the built-in odds_snapshots compression and retention policies.
+-- Compression stays enabled, as chunks compressed by the job may remain.
+SELECT add_compression_policy('odds_snapshots', INTERVAL '7 days', if_not_exists => TRUE);
+SELECT add_retention_policy('odds_snapshots', INTERVAL '2 years', if_not_exists => TRUE);
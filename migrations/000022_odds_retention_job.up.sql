@@ -0,0 +1,20 @@
+-- Hand odds_snapshots compression and retention to the configurable odds
+-- retention job, so the ages come from config and every run is reported.
+-- Compression must be enabled on the hypertable before chunks can be
+-- compressed; runner_id segments keep per-runner reads fast.
+DO $$
+BEGIN
+    IF NOT EXISTS (
+        SELECT 1 FROM timescaledb_information.hypertables
+        WHERE hypertable_name = 'odds_snapshots' AND compression_enabled
+    ) THEN
+        ALTER TABLE odds_snapshots SET (
+            timescaledb.compress,
+            timescaledb.compress_segmentby = 'runner_id',
+            timescaledb.compress_orderby = 'time DESC'
+        );
+    END IF;
+END $$;
+
+SELECT remove_compression_policy('odds_snapshots', if_exists => TRUE);
+SELECT remove_retention_policy('odds_snapshots', if_exists => TRUE);
@@ -0,0 +1,3 @@
+-- Remove strategy archiving
+DROP INDEX IF EXISTS idx_strategies_unarchived;
+ALTER TABLE strategies DROP COLUMN IF EXISTS archived_at;
@@ -0,0 +1,5 @@
+-- Archive retired strategies instead of deleting them, so the bets and
+-- backtest results that reference them stay queryable.
+ALTER TABLE strategies ADD COLUMN archived_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_strategies_unarchived ON strategies (name) WHERE archived_at IS NULL;
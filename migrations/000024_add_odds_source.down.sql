@@ -0,0 +1,2 @@
+-- Remove the odds snapshot source
+ALTER TABLE odds_snapshots DROP COLUMN IF EXISTS source;
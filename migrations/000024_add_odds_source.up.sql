@@ -0,0 +1,4 @@
+-- Record which data source wrote each odds snapshot, so pricing can prefer
+-- the most trusted source when several record a runner at the same time.
+-- Snapshots recorded before this migration have no source.
+ALTER TABLE odds_snapshots ADD COLUMN source VARCHAR(50);
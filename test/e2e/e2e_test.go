@@ -270,8 +270,9 @@ func seedRacesAndRunners(t *testing.T, ctx context.Context, repos *repository.Re
 				Name:       runnerFixture.Name,
 				Trainer:    runnerFixture.Trainer,
 				Weight:     &weight,
-				Metadata:   json.RawMessage(fmt.Sprintf(`{"selection_id":%d,"form":"%s"}`, runnerFixture.SelectionID, runnerFixture.Form)),
+				Metadata:   json.RawMessage(fmt.Sprintf(`{"form":"%s"}`, runnerFixture.Form)),
 			}
+			require.NoError(t, runner.SetBetfairSelection(fixture.MarketID, runnerFixture.SelectionID))
 			require.NoError(t, repos.Runner.Create(ctx, runner))
 			selectionToRunner[runnerFixture.SelectionID] = runnerID
 		}
@@ -280,7 +281,7 @@ func seedRacesAndRunners(t *testing.T, ctx context.Context, repos *repository.Re
 	return marketToRace, selectionToRunner
 }
 
-func seedOddsSnapshots(t *testing.T, ctx context.Context, repos *repository.Repositories, fixtures []oddsFixture, marketToRace map[string]uuid.UUID, selectionToRunner map[uint64]uuid.UUID) {
+func seedOddsSnapshots(t *testing.T, ctx context.Context, repos *repository.Repositories, fixtures []oddsFixture, marketToRace map[string]uuid.UUID) {
 	var snapshots []*models.OddsSnapshot
 
 	for _, fixture := range fixtures {
@@ -291,8 +292,9 @@ func seedOddsSnapshots(t *testing.T, ctx context.Context, repos *repository.Repo
 		require.NoError(t, err)
 
 		for _, runner := range fixture.Runners {
-			runnerID, ok := selectionToRunner[runner.SelectionID]
-			require.True(t, ok, "runner not found for selection %d", runner.SelectionID)
+			mapped, err := repos.Runner.GetBySelectionID(ctx, fixture.MarketID, runner.SelectionID)
+			require.NoError(t, err, "runner not found for selection %d", runner.SelectionID)
+			runnerID := mapped.ID
 
 			var backPrice, backSize, layPrice, laySize *float64
 			if len(runner.BackPrices) > 0 {
@@ -345,7 +347,7 @@ func TestCompleteWorkflow(t *testing.T) {
 	oddsFixtures := loadOddsFixtures(t)
 
 	marketToRace, selectionToRunner := seedRacesAndRunners(t, ctx, repos, raceFixtures)
-	seedOddsSnapshots(t, ctx, repos, oddsFixtures, marketToRace, selectionToRunner)
+	seedOddsSnapshots(t, ctx, repos, oddsFixtures, marketToRace)
 
 	// Start mock ML service
 	grpcAddr, mlCleanup := startMockMLServer(t)
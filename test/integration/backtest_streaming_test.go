@@ -0,0 +1,168 @@
+//go:build integration
+
+package integration
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/clever-better/internal/backtest"
+	"github.com/yourusername/clever-better/internal/database"
+	"github.com/yourusername/clever-better/internal/models"
+	"github.com/yourusername/clever-better/internal/strategy"
+)
+
+// firstRunnerStrategy backs the first runner of every race at a fixed stake
+type firstRunnerStrategy struct{}
+
+func (firstRunnerStrategy) Name() string { return "first-runner" }
+func (firstRunnerStrategy) Evaluate(ctx context.Context, strategyCtx strategy.Context) ([]strategy.Signal, error) {
+	if len(strategyCtx.Runners) == 0 {
+		return nil, nil
+	}
+	return []strategy.Signal{{
+		RunnerID:      strategyCtx.Runners[0].ID,
+		Side:          models.BetSideBack,
+		Odds:          3.0,
+		Stake:         1.0,
+		Confidence:    0.6,
+		ExpectedValue: 0.1,
+	}}, nil
+}
+func (firstRunnerStrategy) ShouldBet(signal strategy.Signal) bool { return true }
+func (firstRunnerStrategy) CalculateStake(signal strategy.Signal, bankroll float64) float64 {
+	return signal.Stake
+}
+func (firstRunnerStrategy) GetParameters() map[string]interface{} { return map[string]interface{}{} }
+
+// TestStreamingBacktestMatchesInMemory tests the cursor-based replay settles
+// the same bets as loading the window up front, with a lower peak heap
+func TestStreamingBacktestMatchesInMemory(t *testing.T) {
+	if testing.Short() {
+		t.Skip(skipIntegration)
+	}
+
+	ctx := context.Background()
+	db := database.SetupTestDB(t)
+	defer database.TeardownTestDB(t, db)
+
+	const raceCount = 10000
+	start := time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(raceCount * time.Minute)
+	seedStreamingRaces(t, ctx, db, start, raceCount)
+	defer func() {
+		_, _ = db.GetPool().Exec(ctx, `DELETE FROM race_results WHERE race_id IN (SELECT id FROM races WHERE track = 'Stream Park')`)
+		_, _ = db.GetPool().Exec(ctx, `DELETE FROM odds_snapshots WHERE race_id IN (SELECT id FROM races WHERE track = 'Stream Park')`)
+		_, _ = db.GetPool().Exec(ctx, `DELETE FROM races WHERE track = 'Stream Park'`)
+	}()
+
+	run := func(streaming bool) (backtest.Metrics, uint64) {
+		logger := logrus.New()
+		logger.SetLevel(logrus.ErrorLevel)
+		engine, err := backtest.NewEngine(backtest.BacktestConfig{
+			StartDate:       start,
+			EndDate:         end,
+			InitialBankroll: 100000,
+			CommissionRate:  0.05,
+			Streaming:       streaming,
+		}, db, firstRunnerStrategy{}, logger)
+		require.NoError(t, err)
+
+		var metrics backtest.Metrics
+		peak := peakHeapDuring(func() {
+			_, metrics, err = engine.Run(ctx, start, end)
+		})
+		require.NoError(t, err)
+		return metrics, peak
+	}
+
+	inMemory, inMemoryPeak := run(false)
+	streamed, streamedPeak := run(true)
+
+	assert.Equal(t, raceCount, streamed.TotalBets)
+	assert.Equal(t, inMemory, streamed)
+	assert.Less(t, streamedPeak, inMemoryPeak, "streaming should not hold the whole window in memory")
+	t.Logf("peak heap growth: in-memory %d bytes, streaming %d bytes", inMemoryPeak, streamedPeak)
+}
+
+// seedStreamingRaces inserts one single-runner race a minute from start, each
+// with a pre-off price and a result. Conditions are padded so holding every
+// race at once is clearly visible in the heap.
+func seedStreamingRaces(t *testing.T, ctx context.Context, db *database.DB, start time.Time, count int) {
+	t.Helper()
+	pool := db.GetPool()
+
+	_, err := pool.Exec(ctx, `
+		INSERT INTO races (id, scheduled_start, track, race_type, distance, grade, conditions, status)
+		SELECT gen_random_uuid(), $1::timestamptz + n * INTERVAL '1 minute', 'Stream Park', 'Flat', 480, 'A1',
+		       jsonb_build_object('going', 'good', 'notes', repeat('x', 4096)), 'completed'
+		FROM generate_series(1, $2) AS n
+	`, start, count)
+	require.NoError(t, err)
+
+	_, err = pool.Exec(ctx, `
+		INSERT INTO runners (race_id, trap_number, name)
+		SELECT id, 1, 'Stream Runner' FROM races WHERE track = 'Stream Park'
+	`)
+	require.NoError(t, err)
+
+	_, err = pool.Exec(ctx, `
+		INSERT INTO odds_snapshots (time, race_id, runner_id, back_price, back_size, lay_price, lay_size)
+		SELECT r.scheduled_start - INTERVAL '1 minute', r.id, ru.id, 3.0, 1000, 3.2, 1000
+		FROM races r JOIN runners ru ON ru.race_id = r.id
+		WHERE r.track = 'Stream Park'
+	`)
+	require.NoError(t, err)
+
+	// Trap 1 wins every other race
+	_, err = pool.Exec(ctx, `
+		INSERT INTO race_results (time, race_id, winner_trap, status)
+		SELECT scheduled_start, id,
+		       CASE WHEN (EXTRACT(EPOCH FROM scheduled_start)::BIGINT / 60) % 2 = 0 THEN 1 ELSE 2 END,
+		       'completed'
+		FROM races WHERE track = 'Stream Park'
+	`)
+	require.NoError(t, err)
+}
+
+// peakHeapDuring runs fn and returns the largest heap growth seen while it ran
+func peakHeapDuring(fn func()) uint64 {
+	runtime.GC()
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	baseline := stats.HeapAlloc
+
+	var peak atomic.Uint64
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ticker := time.NewTicker(5 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				var s runtime.MemStats
+				runtime.ReadMemStats(&s)
+				if s.HeapAlloc > baseline && s.HeapAlloc-baseline > peak.Load() {
+					peak.Store(s.HeapAlloc - baseline)
+				}
+			}
+		}
+	}()
+
+	fn()
+	close(done)
+	wg.Wait()
+	return peak.Load()
+}
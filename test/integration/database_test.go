@@ -5,17 +5,24 @@ package integration
 import (
 	"context"
 	"encoding/json"
+	"sort"
 	"sync"
 	"testing"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
+	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/yourusername/clever-better/internal/backtest"
+	"github.com/yourusername/clever-better/internal/bot"
+	"github.com/yourusername/clever-better/internal/config"
 	"github.com/yourusername/clever-better/internal/database"
 	"github.com/yourusername/clever-better/internal/models"
 	"github.com/yourusername/clever-better/internal/repository"
+	"github.com/yourusername/clever-better/internal/service"
+	"github.com/yourusername/clever-better/internal/strategy"
 )
 
 const skipIntegration = "Skipping integration test in short mode"
@@ -116,6 +123,528 @@ func TestDatabaseRepositoryIntegration(t *testing.T) {
 	})
 }
 
+// TestStrategyArchiving tests archived strategies drop out of strategy
+// listings while their bets stay queryable
+func TestStrategyArchiving(t *testing.T) {
+	if testing.Short() {
+		t.Skip(skipIntegration)
+	}
+
+	ctx := context.Background()
+	db := database.SetupTestDB(t)
+	defer database.TeardownTestDB(t, db)
+
+	strategyRepo := repository.NewPostgresStrategyRepository(db)
+	betRepo := repository.NewPostgresBetRepository(db)
+
+	retired := &models.Strategy{ID: uuid.New(), Name: "retired-" + uuid.NewString()[:8], Active: true, Parameters: json.RawMessage(`{}`)}
+	require.NoError(t, strategyRepo.Create(ctx, retired))
+
+	runner := seedRaceAndRunner(t, ctx, db)
+	placedAt := time.Now().Add(-time.Hour)
+	bet := &models.Bet{
+		ID:         uuid.New(),
+		MarketID:   "1.12345",
+		RaceID:     runner.RaceID,
+		RunnerID:   runner.ID,
+		StrategyID: retired.ID,
+		MarketType: models.MarketTypeWin,
+		Side:       models.BetSideBack,
+		Odds:       3.5,
+		Stake:      10.0,
+		Status:     models.BetStatusPending,
+		PlacedAt:   placedAt,
+	}
+	require.NoError(t, betRepo.Create(ctx, bet))
+
+	require.NoError(t, strategyRepo.Archive(ctx, retired.ID))
+
+	listed := func(strategies []*models.Strategy) bool {
+		for _, s := range strategies {
+			if s.ID == retired.ID {
+				return true
+			}
+		}
+		return false
+	}
+
+	all, err := strategyRepo.GetAll(ctx)
+	require.NoError(t, err)
+	assert.False(t, listed(all), "archived strategies are excluded from GetAll")
+
+	active, err := strategyRepo.GetActive(ctx)
+	require.NoError(t, err)
+	assert.False(t, listed(active), "archived strategies are excluded from GetActive")
+
+	archived, err := strategyRepo.GetArchived(ctx)
+	require.NoError(t, err)
+	assert.True(t, listed(archived))
+
+	retrieved, err := strategyRepo.GetByID(ctx, retired.ID)
+	require.NoError(t, err)
+	assert.True(t, retrieved.IsArchived())
+	assert.False(t, retrieved.Active, "archiving deactivates the strategy")
+
+	bets, err := betRepo.GetByStrategyID(ctx, retired.ID, placedAt.Add(-time.Minute), time.Now())
+	require.NoError(t, err)
+	require.Len(t, bets, 1)
+	assert.Equal(t, bet.ID, bets[0].ID)
+
+	// Unarchiving lists the strategy again, still inactive
+	require.NoError(t, strategyRepo.Unarchive(ctx, retired.ID))
+	all, err = strategyRepo.GetAll(ctx)
+	require.NoError(t, err)
+	assert.True(t, listed(all))
+	active, err = strategyRepo.GetActive(ctx)
+	require.NoError(t, err)
+	assert.False(t, listed(active))
+
+	assert.ErrorIs(t, strategyRepo.Archive(ctx, uuid.New()), models.ErrNotFound)
+}
+
+// TestRunnerSelectionMapping tests resolving runners by Betfair selection ID
+func TestRunnerSelectionMapping(t *testing.T) {
+	if testing.Short() {
+		t.Skip(skipIntegration)
+	}
+
+	ctx := context.Background()
+	db := database.SetupTestDB(t)
+	defer database.TeardownTestDB(t, db)
+
+	runnerRepo := repository.NewPostgresRunnerRepository(db)
+	runner := seedRaceAndRunner(t, ctx, db)
+
+	runner.Metadata = json.RawMessage(`{"form":"1-2-1"}`)
+	require.NoError(t, runner.SetBetfairSelection("1.234567890", 4412345))
+	require.NoError(t, runnerRepo.Update(ctx, runner))
+
+	t.Run("ResolvesMappedSelection", func(t *testing.T) {
+		found, err := runnerRepo.GetBySelectionID(ctx, "1.234567890", 4412345)
+		require.NoError(t, err)
+		assert.Equal(t, runner.ID, found.ID)
+
+		selection, ok := found.BetfairSelection()
+		require.True(t, ok)
+		assert.Equal(t, "1.234567890", selection.MarketID)
+		assert.Equal(t, uint64(4412345), selection.SelectionID)
+		assert.Contains(t, string(found.Metadata), `"form"`)
+	})
+
+	t.Run("UnknownSelection", func(t *testing.T) {
+		_, err := runnerRepo.GetBySelectionID(ctx, "1.234567890", 999)
+		assert.ErrorIs(t, err, models.ErrNotFound)
+	})
+
+	t.Run("SelectionInOtherMarket", func(t *testing.T) {
+		_, err := runnerRepo.GetBySelectionID(ctx, "1.999999999", 4412345)
+		assert.ErrorIs(t, err, models.ErrNotFound)
+	})
+
+	t.Run("ResolvesMarketRunners", func(t *testing.T) {
+		found, err := runnerRepo.GetByMarketID(ctx, "1.234567890")
+		require.NoError(t, err)
+		require.Len(t, found, 1)
+		assert.Equal(t, runner.ID, found[0].ID)
+
+		none, err := runnerRepo.GetByMarketID(ctx, "1.999999999")
+		require.NoError(t, err)
+		assert.Empty(t, none)
+	})
+}
+
+// TestRaceBundle tests loading races with their runners and latest odds in
+// one query
+func TestRaceBundle(t *testing.T) {
+	if testing.Short() {
+		t.Skip(skipIntegration)
+	}
+
+	ctx := context.Background()
+	db := database.SetupTestDB(t)
+	defer database.TeardownTestDB(t, db)
+
+	raceRepo := repository.NewPostgresRaceRepository(db)
+	runnerRepo := repository.NewPostgresRunnerRepository(db)
+	oddsRepo := repository.NewPostgresOddsRepository(db)
+
+	seedRace := func(start time.Time) *models.Race {
+		race := &models.Race{
+			ID:             uuid.New(),
+			ScheduledStart: start,
+			Track:          "Bundle Park",
+			RaceType:       "A1",
+			Distance:       480,
+			Status:         "scheduled",
+		}
+		require.NoError(t, raceRepo.Create(ctx, race))
+		return race
+	}
+	seedRunner := func(race *models.Race, trap int, status string) *models.Runner {
+		runner := &models.Runner{ID: uuid.New(), RaceID: race.ID, TrapNumber: trap, Name: "Runner", Status: status}
+		require.NoError(t, runnerRepo.Create(ctx, runner))
+		return runner
+	}
+	seedOdds := func(runner *models.Runner, at time.Time, price float64, source string) {
+		require.NoError(t, oddsRepo.Insert(ctx, &models.OddsSnapshot{
+			Time: at, RaceID: runner.RaceID, RunnerID: runner.ID, BackPrice: &price, Source: source,
+		}))
+	}
+
+	off := time.Now().Add(-time.Hour).Truncate(time.Second)
+	race := seedRace(off)
+	reserve := seedRunner(race, 3, models.RunnerStatusReserve)
+	favourite := seedRunner(race, 1, models.RunnerStatusActive)
+	seedRunner(race, 2, models.RunnerStatusWithdrawn)
+	outsider := seedRunner(race, 4, models.RunnerStatusActive)
+
+	seedOdds(favourite, off.Add(-10*time.Minute), 2.5, "betfair")
+	seedOdds(favourite, off.Add(-time.Minute), 2.2, "betfair")
+	seedOdds(favourite, off.Add(-time.Minute), 2.24, "racing_api")
+	seedOdds(favourite, off.Add(time.Minute), 1.9, "betfair")
+	seedOdds(outsider, off.Add(-5*time.Minute), 12, "betfair")
+
+	other := seedRace(off.Add(-30 * time.Minute))
+	otherRunner := seedRunner(other, 1, "")
+	seedOdds(otherRunner, other.ScheduledStart.Add(-time.Minute), 4, "betfair")
+
+	t.Run("AssemblesRaceRunnersAndOddsHistory", func(t *testing.T) {
+		before := db.GetPool().Stat().AcquireCount()
+		bundle, err := raceRepo.GetRaceBundle(ctx, race.ID, off.Add(-5*time.Minute))
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), db.GetPool().Stat().AcquireCount()-before, "the bundle is loaded in one query")
+
+		assert.Equal(t, race.ID, bundle.Race.ID)
+		assert.Equal(t, "Bundle Park", bundle.Race.Track)
+		assert.True(t, off.Equal(bundle.Race.ScheduledStart))
+
+		runnerIDs := make([]uuid.UUID, len(bundle.Runners))
+		for i, runner := range bundle.Runners {
+			runnerIDs[i] = runner.ID
+		}
+		assert.Equal(t, []uuid.UUID{favourite.ID, reserve.ID, outsider.ID}, runnerIDs, "withdrawn runners are left out, in trap order")
+		assert.Equal(t, models.RunnerStatusReserve, bundle.Runners[1].Status)
+
+		require.Len(t, bundle.Odds, 3, "odds before oddsSince and after the off are left out")
+		for i, snapshot := range bundle.Odds {
+			assert.False(t, snapshot.Time.After(off), "odds after the off are never bundled")
+			if i > 0 {
+				assert.False(t, snapshot.Time.Before(bundle.Odds[i-1].Time), "odds are oldest first")
+			}
+		}
+		assert.Equal(t, outsider.ID, bundle.Odds[0].RunnerID)
+		assert.Equal(t, 12.0, *bundle.Odds[0].BackPrice)
+		preferred := models.OddsSourcePreference{"racing_api"}.Prefer(bundle.Odds[1:])
+		require.Len(t, preferred, 1, "every source's snapshot is kept")
+		assert.Equal(t, 2.24, *preferred[0].BackPrice)
+	})
+
+	t.Run("IncludesEarlierOddsWhenAsked", func(t *testing.T) {
+		bundle, err := raceRepo.GetRaceBundle(ctx, race.ID, time.Time{})
+		require.NoError(t, err)
+		require.Len(t, bundle.Odds, 4)
+		assert.Equal(t, 2.5, *bundle.Odds[0].BackPrice)
+	})
+
+	t.Run("BatchesRacesInStartOrder", func(t *testing.T) {
+		before := db.GetPool().Stat().AcquireCount()
+		bundles, err := raceRepo.GetRaceBundles(ctx, []uuid.UUID{race.ID, uuid.New(), other.ID}, time.Time{})
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), db.GetPool().Stat().AcquireCount()-before, "all bundles are loaded in one query")
+
+		require.Len(t, bundles, 2, "unknown races are left out")
+		assert.Equal(t, other.ID, bundles[0].Race.ID)
+		assert.Equal(t, race.ID, bundles[1].Race.ID)
+		require.Len(t, bundles[0].Runners, 1)
+		assert.Equal(t, otherRunner.ID, bundles[0].Runners[0].ID)
+		require.Len(t, bundles[0].Odds, 1)
+		assert.Equal(t, 4.0, *bundles[0].Odds[0].BackPrice)
+	})
+
+	t.Run("RaceWithoutRunners", func(t *testing.T) {
+		empty := seedRace(off.Add(time.Hour))
+		bundle, err := raceRepo.GetRaceBundle(ctx, empty.ID, time.Time{})
+		require.NoError(t, err)
+		assert.Empty(t, bundle.Runners)
+		assert.Empty(t, bundle.Odds)
+	})
+
+	t.Run("UnknownRace", func(t *testing.T) {
+		_, err := raceRepo.GetRaceBundle(ctx, uuid.New(), time.Time{})
+		assert.ErrorIs(t, err, models.ErrNotFound)
+	})
+}
+
+// TestBacktestResultTags tests stamping backtest results with tags and
+// finding them again by tag
+func TestBacktestResultTags(t *testing.T) {
+	if testing.Short() {
+		t.Skip(skipIntegration)
+	}
+
+	ctx := context.Background()
+	db := database.SetupTestDB(t)
+	defer database.TeardownTestDB(t, db)
+
+	strategy := &models.Strategy{ID: uuid.New(), Name: "tagged-" + uuid.NewString()[:8], Parameters: json.RawMessage(`{}`)}
+	require.NoError(t, repository.NewPostgresStrategyRepository(db).Create(ctx, strategy))
+
+	repo := repository.NewPostgresBacktestResultRepository(db)
+	save := func(runDate time.Time, tags ...string) *models.BacktestResult {
+		result := &models.BacktestResult{
+			ID:             uuid.New(),
+			StrategyID:     strategy.ID,
+			RunDate:        runDate,
+			StartDate:      runDate.AddDate(0, -1, 0),
+			EndDate:        runDate,
+			InitialCapital: 1000,
+			FinalCapital:   1100,
+			Method:         "historical",
+			Recommendation: "GOOD",
+			Tags:           tags,
+			CreatedAt:      runDate,
+		}
+		require.NoError(t, repo.SaveResult(ctx, result))
+		return result
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	gbFlat := save(now.Add(-2*time.Hour), "GB", " flat ", "gb")
+	gbJumps := save(now.Add(-1*time.Hour), "gb", "jumps")
+	untagged := save(now)
+
+	ids := func(results []*models.BacktestResult) []uuid.UUID {
+		out := make([]uuid.UUID, len(results))
+		for i, result := range results {
+			out[i] = result.ID
+		}
+		return out
+	}
+
+	t.Run("SingleTag", func(t *testing.T) {
+		results, err := repo.GetByTag(ctx, []string{"GB"}, repository.TagMatchAny)
+		require.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{gbJumps.ID, gbFlat.ID}, ids(results), "newest first")
+		assert.Equal(t, []string{"flat", "gb"}, results[1].Tags, "tags are normalized on save")
+	})
+
+	t.Run("AllTags", func(t *testing.T) {
+		results, err := repo.GetByTag(ctx, []string{"gb", "flat"}, repository.TagMatchAll)
+		require.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{gbFlat.ID}, ids(results))
+	})
+
+	t.Run("AnyTag", func(t *testing.T) {
+		results, err := repo.GetByTag(ctx, []string{"flat", "jumps"}, repository.TagMatchAny)
+		require.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{gbJumps.ID, gbFlat.ID}, ids(results))
+	})
+
+	t.Run("UnknownTag", func(t *testing.T) {
+		results, err := repo.GetByTag(ctx, []string{"ie"}, repository.TagMatchAny)
+		require.NoError(t, err)
+		assert.Empty(t, results)
+	})
+
+	t.Run("UntaggedResultsReadBackEmpty", func(t *testing.T) {
+		results, err := repo.GetByStrategyID(ctx, strategy.ID)
+		require.NoError(t, err)
+		require.Len(t, results, 3)
+		assert.Equal(t, untagged.ID, results[0].ID)
+		assert.Empty(t, results[0].Tags)
+	})
+}
+
+// TestBacktestResultRankingTieBreak tests results tied on score and run date
+// rank in the same order on every query
+func TestBacktestResultRankingTieBreak(t *testing.T) {
+	if testing.Short() {
+		t.Skip(skipIntegration)
+	}
+
+	ctx := context.Background()
+	db := database.SetupTestDB(t)
+	defer database.TeardownTestDB(t, db)
+
+	strategy := &models.Strategy{ID: uuid.New(), Name: "tied-" + uuid.NewString()[:8], Parameters: json.RawMessage(`{}`)}
+	require.NoError(t, repository.NewPostgresStrategyRepository(db).Create(ctx, strategy))
+
+	repo := repository.NewPostgresBacktestResultRepository(db)
+	runDate := time.Now().UTC().Truncate(time.Second)
+	expected := make([]string, 0, 5)
+	for i := 0; i < 5; i++ {
+		result := &models.BacktestResult{
+			ID:             uuid.New(),
+			StrategyID:     strategy.ID,
+			RunDate:        runDate,
+			StartDate:      runDate.AddDate(0, -1, 0),
+			EndDate:        runDate,
+			InitialCapital: 1000,
+			FinalCapital:   1100,
+			Method:         "ml_estimate",
+			CompositeScore: 9999,
+			Recommendation: "GOOD",
+			CreatedAt:      runDate,
+		}
+		require.NoError(t, repo.SaveResult(ctx, result))
+		expected = append(expected, result.ID.String())
+	}
+	// Ties fall back to ID, descending
+	sort.Sort(sort.Reverse(sort.StringSlice(expected)))
+
+	ids := func(results []*models.BacktestResult) []string {
+		out := make([]string, len(results))
+		for i, result := range results {
+			out[i] = result.ID.String()
+		}
+		return out
+	}
+
+	for i := 0; i < 10; i++ {
+		top, err := repo.GetTopPerforming(ctx, len(expected))
+		require.NoError(t, err)
+		assert.Equal(t, expected, ids(top))
+
+		ranged, err := repo.GetByCompositeScoreRange(ctx, 9999, 9999, len(expected))
+		require.NoError(t, err)
+		assert.Equal(t, expected, ids(ranged))
+
+		byStrategy, err := repo.GetByStrategyID(ctx, strategy.ID)
+		require.NoError(t, err)
+		assert.Equal(t, expected, ids(byStrategy))
+	}
+}
+
+// TestBacktestResultRescore tests that a legacy result stored with a stale
+// composite score is rescored under the current weights
+func TestBacktestResultRescore(t *testing.T) {
+	if testing.Short() {
+		t.Skip(skipIntegration)
+	}
+
+	ctx := context.Background()
+	db := database.SetupTestDB(t)
+	defer database.TeardownTestDB(t, db)
+
+	strategy := &models.Strategy{ID: uuid.New(), Name: "legacy-" + uuid.NewString()[:8], Parameters: json.RawMessage(`{}`)}
+	require.NoError(t, repository.NewPostgresStrategyRepository(db).Create(ctx, strategy))
+
+	historical := backtest.Metrics{TotalReturn: 0.3, SharpeRatio: 1.5, MaxDrawdown: 0.1, TotalBets: 200, WinRate: 0.55, ProfitFactor: 1.8}
+	monteCarlo := backtest.MonteCarloResult{MeanReturn: 0.25}
+	walkForward := backtest.WalkForwardResult{ConsistencyScore: 0.7, AggregatedMetrics: backtest.Metrics{TotalReturn: 0.2}}
+	weights := backtest.AggregationWeights{HistoricalReplay: 0.5, MonteCarlo: 0.2, WalkForward: 0.3}
+	expected := backtest.AggregateResults(historical, monteCarlo, walkForward, weights)
+
+	fullResults, err := json.Marshal(backtest.AggregateResults(historical, monteCarlo, walkForward, backtest.DefaultAggregationWeights))
+	require.NoError(t, err)
+	runDate := time.Now().UTC().Add(-time.Hour).Truncate(time.Second)
+	legacy := &models.BacktestResult{
+		ID:             uuid.New(),
+		StrategyID:     strategy.ID,
+		RunDate:        runDate,
+		StartDate:      runDate.AddDate(0, -1, 0),
+		EndDate:        runDate,
+		InitialCapital: 1000,
+		FinalCapital:   1300,
+		TotalReturn:    historical.TotalReturn,
+		SharpeRatio:    historical.SharpeRatio,
+		MaxDrawdown:    historical.MaxDrawdown,
+		TotalBets:      historical.TotalBets,
+		WinRate:        historical.WinRate,
+		ProfitFactor:   historical.ProfitFactor,
+		Method:         backtest.MethodAggregated,
+		CompositeScore: 42, // scored under an older formula
+		Recommendation: "ACCEPT",
+		FullResults:    fullResults,
+		CreatedAt:      runDate,
+	}
+	repo := repository.NewPostgresBacktestResultRepository(db)
+	require.NoError(t, repo.SaveResult(ctx, legacy))
+
+	stored := func() *models.BacktestResult {
+		results, err := repo.GetByStrategyID(ctx, strategy.ID)
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		return results[0]
+	}
+
+	preview, err := backtest.RescoreCompositeScores(ctx, repo, weights, true)
+	require.NoError(t, err)
+	require.NotEmpty(t, preview.Changes)
+	assert.Equal(t, 42.0, stored().CompositeScore, "a dry run leaves the row alone")
+
+	_, err = backtest.RescoreCompositeScores(ctx, repo, weights, false)
+	require.NoError(t, err)
+	rescored := stored()
+	assert.InDelta(t, expected.CompositeScore, rescored.CompositeScore, 1e-4)
+	assert.Equal(t, expected.Recommendation, rescored.Recommendation)
+
+	again, err := backtest.RescoreCompositeScores(ctx, repo, weights, false)
+	require.NoError(t, err)
+	for _, change := range again.Changes {
+		assert.NotEqual(t, legacy.ID, change.ResultID, "a rescored row is stable")
+	}
+}
+
+// TestBetReasoningPersisted tests that a placed bet keeps the signal's reasoning
+func TestBetReasoningPersisted(t *testing.T) {
+	if testing.Short() {
+		t.Skip(skipIntegration)
+	}
+
+	ctx := context.Background()
+	db := database.SetupTestDB(t)
+	defer database.TeardownTestDB(t, db)
+
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	betRepo := repository.NewPostgresBetRepository(db)
+	riskManager := bot.NewRiskManager(&config.TradingConfig{
+		MaxStakePerBet: 100,
+		MaxExposure:    500,
+		MaxDailyLoss:   200,
+	}, betRepo, logger)
+	executor := bot.NewExecutor(nil, betRepo, riskManager, true, false, logger, nil)
+
+	runner := seedRaceAndRunner(t, ctx, db)
+	stratModel := &models.Strategy{ID: uuid.New(), Name: "explained-" + uuid.NewString()[:8], Parameters: json.RawMessage(`{}`)}
+	require.NoError(t, repository.NewPostgresStrategyRepository(db).Create(ctx, stratModel))
+
+	probability := 0.42
+	signal := strategy.Signal{
+		RunnerID:         runner.ID,
+		Side:             models.BetSideBack,
+		Odds:             3.0,
+		Stake:            10,
+		Confidence:       0.8,
+		ExpectedValue:    0.26,
+		Reasoning:        "model probability 0.42 beats implied 0.33",
+		ModelProbability: &probability,
+	}
+
+	placed, err := executor.ExecuteSignal(ctx, signal, stratModel.ID, runner.RaceID, "1.12345", 1)
+	require.NoError(t, err)
+
+	retrieved, err := betRepo.GetByID(ctx, placed.ID)
+	require.NoError(t, err)
+	assert.Equal(t, signal.Reasoning, retrieved.Reasoning)
+	require.NotNil(t, retrieved.ExpectedValue)
+	assert.InDelta(t, signal.ExpectedValue, *retrieved.ExpectedValue, 1e-6)
+	require.NotNil(t, retrieved.Confidence)
+	assert.InDelta(t, signal.Confidence, *retrieved.Confidence, 1e-6)
+	require.NotNil(t, retrieved.ModelProbability)
+	assert.InDelta(t, probability, *retrieved.ModelProbability, 1e-6)
+
+	// Bets without an ML prediction keep a NULL model probability
+	signal.ModelProbability = nil
+	placed, err = executor.ExecuteSignal(ctx, signal, stratModel.ID, runner.RaceID, "1.12345", 1)
+	require.NoError(t, err)
+	retrieved, err = betRepo.GetByID(ctx, placed.ID)
+	require.NoError(t, err)
+	assert.Nil(t, retrieved.ModelProbability)
+}
+
 // TestHypertablePartitioning tests TimescaleDB hypertable functionality
 func TestHypertablePartitioning(t *testing.T) {
 	if testing.Short() {
@@ -357,7 +886,8 @@ func TestDatabaseMigrations(t *testing.T) {
 	t.Log("✓ Database migrations validated")
 }
 
-// TestDataRetention tests time-series data retention policies
+// TestDataRetention tests the odds retention job compresses and drops old
+// odds_snapshots chunks and keeps recent data
 func TestDataRetention(t *testing.T) {
 	if testing.Short() {
 		t.Skip("Skipping integration test in short mode")
@@ -368,35 +898,61 @@ func TestDataRetention(t *testing.T) {
 	defer database.TeardownTestDB(t, db)
 
 	oddsRepo := repository.NewPostgresOddsRepository(db)
+	chunkRepo := repository.NewPostgresChunkRepository(db)
 	runner := seedRaceAndRunner(t, ctx, db)
 
-	// Insert old data (beyond retention period)
-	oldTime := time.Now().Add(-120 * 24 * time.Hour)
-	oldBack := 3.0
-	oldSnapshot := &models.OddsSnapshot{
-		Time:      oldTime,
-		RaceID:    runner.RaceID,
-		RunnerID:  runner.ID,
-		BackPrice: &oldBack,
+	// Beyond retention, due compression, and recent
+	snapshotTimes := map[string]time.Time{
+		"old":    time.Now().Add(-120 * 24 * time.Hour),
+		"mid":    time.Now().Add(-30 * 24 * time.Hour),
+		"recent": time.Now().Add(-1 * time.Hour),
+	}
+	for _, at := range snapshotTimes {
+		back := 3.0
+		err := oddsRepo.Insert(ctx, &models.OddsSnapshot{
+			Time:      at,
+			RaceID:    runner.RaceID,
+			RunnerID:  runner.ID,
+			BackPrice: &back,
+		})
+		require.NoError(t, err)
 	}
 
-	err := oddsRepo.Insert(ctx, oldSnapshot)
-	require.NoError(t, err)
-
-	// Insert recent data
-	recentTime := time.Now().Add(-1 * time.Hour)
-	recentBack := 3.4
-	recentSnapshot := &models.OddsSnapshot{
-		Time:      recentTime,
-		RaceID:    runner.RaceID,
-		RunnerID:  runner.ID,
-		BackPrice: &recentBack,
+	countSince := func(from time.Time) int {
+		snapshots, err := oddsRepo.GetByRaceID(ctx, runner.RaceID, from, time.Now())
+		require.NoError(t, err)
+		return len(snapshots)
 	}
+	chunkCompressed := func(at time.Time) bool {
+		chunks, err := chunkRepo.GetChunks(ctx, service.OddsSnapshotsTable, time.Now().AddDate(0, 0, 30))
+		require.NoError(t, err)
+		for _, chunk := range chunks {
+			if !at.Before(chunk.RangeStart) && at.Before(chunk.RangeEnd) {
+				return chunk.Compressed
+			}
+		}
+		return false
+	}
+
+	logger := logrus.New()
+	policy := config.OddsRetentionConfig{CompressAfterDays: 7, RetentionDays: 90, DryRun: true}
 
-	err = oddsRepo.Insert(ctx, recentSnapshot)
+	// A dry run reports the old chunk without touching anything
+	result, err := service.NewOddsRetentionService(chunkRepo, policy, logger).Enforce(ctx)
 	require.NoError(t, err)
+	assert.GreaterOrEqual(t, result.ChunksDropped, 1)
+	assert.Equal(t, 3, countSince(snapshotTimes["old"].Add(-time.Minute)))
+	assert.False(t, chunkCompressed(snapshotTimes["mid"]))
 
-	// In production, old data would be compressed or dropped
-	// based on retention policy
-	t.Log("✓ Data retention policy configured")
+	policy.DryRun = false
+	result, err = service.NewOddsRetentionService(chunkRepo, policy, logger).Enforce(ctx)
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, result.ChunksDropped, 1)
+	assert.GreaterOrEqual(t, result.ChunksCompressed, 1)
+	assert.Positive(t, result.BytesReclaimed)
+
+	assert.Equal(t, 2, countSince(snapshotTimes["old"].Add(-time.Minute)), "the old snapshot is dropped")
+	assert.True(t, chunkCompressed(snapshotTimes["mid"]), "the mid chunk is compressed")
+	assert.False(t, chunkCompressed(snapshotTimes["recent"]), "the recent chunk is left alone")
+	assert.Equal(t, 1, countSince(snapshotTimes["recent"].Add(-time.Minute)), "recent data is retained")
 }
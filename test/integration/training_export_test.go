@@ -0,0 +1,165 @@
+//go:build integration
+
+package integration
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/clever-better/internal/database"
+	"github.com/yourusername/clever-better/internal/models"
+	"github.com/yourusername/clever-better/internal/repository"
+	"github.com/yourusername/clever-better/internal/service"
+)
+
+// TestExportTrainingExamples tests settled bets are joined with the prediction
+// made before placement and labelled with the runner's result
+func TestExportTrainingExamples(t *testing.T) {
+	if testing.Short() {
+		t.Skip(skipIntegration)
+	}
+
+	ctx := context.Background()
+	db := database.SetupTestDB(t)
+	defer database.TeardownTestDB(t, db)
+
+	repos, err := repository.NewRepositories(db)
+	require.NoError(t, err)
+
+	start := time.Now().UTC().Add(-2 * time.Hour).Truncate(time.Second)
+	off := start.Add(30 * time.Minute)
+
+	model := &models.Model{
+		ID:        uuid.New(),
+		Name:      "training-export",
+		Version:   uuid.New().String()[:8],
+		ModelType: "xgboost",
+		Path:      "/models/training-export",
+		TrainedAt: start.Add(-24 * time.Hour),
+	}
+	require.NoError(t, repos.Model.Create(ctx, model))
+
+	race := &models.Race{
+		ID:             uuid.New(),
+		ScheduledStart: off,
+		Track:          "Export Park",
+		RaceType:       "Flat",
+		Distance:       480,
+		Grade:          "A2",
+		Conditions:     json.RawMessage(`{"going":"good"}`),
+		Status:         "completed",
+	}
+	require.NoError(t, repos.Race.Create(ctx, race))
+
+	runners := make([]*models.Runner, 3)
+	for i := range runners {
+		runners[i] = &models.Runner{ID: uuid.New(), RaceID: race.ID, TrapNumber: i + 1, Name: "Export Runner"}
+		require.NoError(t, repos.Runner.Create(ctx, runners[i]))
+	}
+	winner, loser, unpredicted := runners[0], runners[1], runners[2]
+
+	winnerTrap := winner.TrapNumber
+	require.NoError(t, repos.RaceResult.Insert(ctx, &models.RaceResult{
+		Time:       off,
+		RaceID:     race.ID,
+		WinnerTrap: &winnerTrap,
+		Status:     models.RaceResultStatusCompleted,
+	}))
+
+	predict := func(runner *models.Runner, probability float64, at time.Time, features string) {
+		require.NoError(t, repos.Prediction.Insert(ctx, &models.Prediction{
+			ID:          uuid.New(),
+			ModelID:     model.ID,
+			RaceID:      race.ID,
+			RunnerID:    runner.ID,
+			Probability: probability,
+			Confidence:  0.7,
+			Features:    json.RawMessage(features),
+			PredictedAt: at,
+		}))
+	}
+	placedAt := off.Add(-5 * time.Minute)
+	predict(winner, 0.40, placedAt.Add(-10*time.Minute), `{"odds_vwap": 3.1}`)
+	predict(winner, 0.45, placedAt.Add(-1*time.Minute), `{"odds_vwap": 2.9, "odds_volatility": 0.05}`)
+	// Made after the bet was placed, so it must not be used
+	predict(winner, 0.90, placedAt.Add(time.Minute), `{"odds_vwap": 1.5}`)
+	predict(loser, 0.20, placedAt.Add(-2*time.Minute), `[4.5, 0.1]`)
+
+	settle := func(runner *models.Runner, side models.BetSide, profitLoss float64) *models.Bet {
+		bet := &models.Bet{
+			ID:         uuid.New(),
+			MarketID:   "1.98765",
+			RaceID:     race.ID,
+			RunnerID:   runner.ID,
+			StrategyID: uuid.New(),
+			MarketType: models.MarketTypeWin,
+			Side:       side,
+			Odds:       3.0,
+			Stake:      10.0,
+			Status:     models.BetStatusPending,
+			PlacedAt:   placedAt,
+		}
+		require.NoError(t, repos.Bet.Create(ctx, bet))
+
+		settledAt := off.Add(5 * time.Minute)
+		bet.Status = models.BetStatusSettled
+		bet.SettledAt = &settledAt
+		bet.ProfitLoss = &profitLoss
+		require.NoError(t, repos.Bet.Update(ctx, bet))
+		return bet
+	}
+	backWinner := settle(winner, models.BetSideBack, 20)
+	backLoser := settle(loser, models.BetSideBack, -10)
+	layLoser := settle(loser, models.BetSideLay, 10)
+	settle(unpredicted, models.BetSideBack, -10)
+
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+	exporter := service.NewTrainingExampleExporter(repos.Bet, repos.Runner, repos.Prediction, repos.RaceResult, logger)
+
+	examples, err := exporter.ExportTrainingExamples(ctx, start, time.Now().UTC().Add(time.Hour))
+	require.NoError(t, err)
+
+	byBet := make(map[uuid.UUID]service.TrainingExample)
+	for _, example := range examples {
+		if example.RaceID == race.ID {
+			byBet[example.BetID] = example
+		}
+	}
+	require.Len(t, byBet, 3, "the bet without a prior prediction is skipped")
+
+	won := byBet[backWinner.ID]
+	assert.Equal(t, 1, won.Label)
+	assert.True(t, won.BetWon)
+	assert.Equal(t, model.ID, won.ModelID)
+	assert.InDelta(t, 0.45, won.Probability, 1e-6)
+	assert.Equal(t, map[string]float64{"odds_vwap": 2.9, "odds_volatility": 0.05}, won.Features)
+	assert.InDelta(t, 20.0, won.ProfitLoss, 1e-6)
+
+	lost := byBet[backLoser.ID]
+	assert.Equal(t, 0, lost.Label)
+	assert.False(t, lost.BetWon)
+	assert.Equal(t, map[string]float64{"feature_0": 4.5, "feature_1": 0.1}, lost.Features)
+
+	lay := byBet[layLoser.ID]
+	assert.Equal(t, 0, lay.Label, "label follows the runner, not the bet")
+	assert.True(t, lay.BetWon)
+
+	var buf bytes.Buffer
+	require.NoError(t, service.WriteTrainingExamples(&buf, []service.TrainingExample{won, lost}))
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 2)
+	var row map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &row))
+	for _, column := range []string{"bet_id", "runner_id", "event_timestamp", "features", "label"} {
+		assert.Contains(t, row, column)
+	}
+}